@@ -0,0 +1,254 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"interview-bot-complete/internal/api"
+	"interview-bot-complete/internal/config"
+	"interview-bot-complete/internal/extractor"
+	"interview-bot-complete/internal/prompts"
+	"interview-bot-complete/internal/storage"
+	"interview-bot-complete/internal/tenant"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+// backfill заново извлекает профили всех сохраненных интервью с текущими схемой и промптами.
+// Каждый профиль сохраняется новой ревизией, существующие файлы не перезаписываются.
+// С -batch промпты отправляются пакетами OpenAI Batch API: ответы приходят в течение 24 часов,
+// но стоят вдвое дешевле. Прерванное ожидание продолжается флагом -batch-resume.
+func main() {
+	concurrency := flag.Int("concurrency", 3, "количество параллельных запросов к OpenAI")
+	model := flag.String("model", "", "модель для извлечения (по умолчанию OPENAI_MODEL)")
+	promptVersion := flag.String("prompt", "", "версия промпта извлечения (по умолчанию текущая)")
+	dryRun := flag.Bool("dry-run", false, "только показать оценку стоимости, без запросов к API")
+	yes := flag.Bool("yes", false, "не спрашивать подтверждение перед запуском")
+	limit := flag.Int("limit", 0, "обработать не больше N интервью (0 - все)")
+	batchMode := flag.Bool("batch", false, "отправить запросы пакетами OpenAI Batch API (~50% дешевле, до 24 часов)")
+	batchSize := flag.Int("batch-size", 1000, "интервью в одном пакете")
+	batchResume := flag.String("batch-resume", "", "дождаться ранее отправленного пакета с этим ID и сохранить профили")
+	pollInterval := flag.Duration("poll", time.Minute, "интервал опроса состояния пакета")
+	flag.Parse()
+
+	if err := godotenv.Load(); err != nil {
+		log.Printf("Предупреждение: .env файл не найден, используем переменные системы")
+	}
+
+	if os.Getenv("OPENAI_API_KEY") == "" && !strings.EqualFold(os.Getenv("LLM_PROVIDER"), "mock") {
+		log.Fatal("OPENAI_API_KEY не установлен")
+	}
+
+	appCfg := config.LoadAppConfig()
+	storageCfg := appCfg.Storage
+	// Арендаторы: файлы каждого хранятся в его директории, как в боте
+	tenants, err := tenant.Load(appCfg.Tenants.File)
+	if err != nil {
+		log.Fatalf("Ошибка загрузки арендаторов: %v", err)
+	}
+	if err := storage.Configure(storage.Paths{
+		ResultsDir:  storageCfg.ResultsDir,
+		OutputDir:   storageCfg.OutputDir,
+		ResultFile:  storageCfg.ResultFileTemplate,
+		ProfileFile: storageCfg.ProfileFileTemplate,
+		TenantDirs:  tenants.StoragePrefixes(),
+	}); err != nil {
+		log.Fatalf("Ошибка настройки хранилища: %v", err)
+	}
+
+	if err := prompts.LoadTemplates(appCfg.Interview.PromptsDir); err != nil {
+		log.Fatalf("Ошибка загрузки шаблонов промптов: %v", err)
+	}
+
+	extractorService, err := extractor.New(os.Getenv("OPENAI_API_KEY"))
+	if err != nil {
+		log.Fatalf("Ошибка инициализации Profile Extractor: %v", err)
+	}
+
+	// Ctrl+C прерывает ожидание пакета; отправленный пакет можно принять позже через -batch-resume
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	report := &backfillReport{}
+	if *batchResume != "" {
+		job, err := storage.LoadBatchJob(*batchResume)
+		if err != nil {
+			log.Fatalf("Ошибка загрузки пакета: %v", err)
+		}
+		ingestBatch(ctx, extractorService, job, *pollInterval, report)
+		report.print()
+		return
+	}
+
+	interviewIDs, err := storage.ListResults()
+	if err != nil {
+		log.Fatalf("Ошибка чтения списка интервью: %v", err)
+	}
+	if *limit > 0 && len(interviewIDs) > *limit {
+		interviewIDs = interviewIDs[:*limit]
+	}
+	if len(interviewIDs) == 0 {
+		fmt.Println("Сохраненных интервью не найдено.")
+		return
+	}
+
+	opts := extractor.ExtractOptions{Model: *model, PromptVersion: *promptVersion}
+
+	// Предварительная оценка стоимости
+	fmt.Printf("📋 Интервью к обработке: %d\n", len(interviewIDs))
+	var totalPromptTokens, totalCompletionTokens int
+	var totalCost float64
+	var estimateModel string
+	for _, id := range interviewIDs {
+		estimate, err := extractorService.EstimateReextraction(id, opts)
+		if err != nil {
+			fmt.Printf("⚠️ %s: %v\n", id, err)
+			continue
+		}
+		estimateModel = estimate.Model
+		totalPromptTokens += estimate.PromptTokens
+		totalCompletionTokens += estimate.CompletionTokens
+		totalCost += estimate.Cost
+	}
+	fmt.Printf("🤖 Модель: %s\n", estimateModel)
+	fmt.Printf("🔢 Оценка токенов: ~%d входных, ~%d выходных\n", totalPromptTokens, totalCompletionTokens)
+	fmt.Printf("💰 Оценка стоимости: ~$%.4f\n", totalCost)
+	if *batchMode {
+		fmt.Printf("📦 В пакетном режиме: ~$%.4f, ответы в течение 24 часов\n", totalCost*api.BatchDiscount)
+	}
+
+	if *dryRun {
+		return
+	}
+
+	if !*yes && !confirm("Запустить повторное извлечение?") {
+		fmt.Println("Отменено.")
+		return
+	}
+
+	if *batchMode {
+		runBatches(ctx, extractorService, interviewIDs, opts, *batchSize, *concurrency, *pollInterval, report)
+	} else {
+		reextractAll(extractorService, interviewIDs, opts, *concurrency, report)
+	}
+	report.print()
+}
+
+// backfillReport подсчитывает итоги повторного извлечения
+type backfillReport struct {
+	mutex     sync.Mutex
+	succeeded int
+	failed    int
+	spent     float64
+	tokens    int
+}
+
+// record учитывает и печатает итог одного интервью
+func (r *backfillReport) record(id string, reextraction *extractor.Reextraction, err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if err != nil {
+		r.failed++
+		fmt.Printf("❌ %s: %v\n", id, err)
+		return
+	}
+	r.succeeded++
+	r.spent += reextraction.Cost
+	r.tokens += reextraction.Result.Usage.TotalTokens
+	fmt.Printf("✅ %s → %s (v%d)\n", id, reextraction.FileName, reextraction.Revision)
+}
+
+// print выводит итоги и завершает программу с ошибкой, если хотя бы одно интервью не обработано
+func (r *backfillReport) print() {
+	fmt.Printf("\n📊 Готово: %d успешно, %d с ошибками\n", r.succeeded, r.failed)
+	fmt.Printf("🔢 Израсходовано токенов: %d, стоимость ≈ $%.4f\n", r.tokens, r.spent)
+
+	if r.failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// reextractAll извлекает профили обычными запросами с ограничением параллельности
+func reextractAll(extractorService *extractor.Service, interviewIDs []string, opts extractor.ExtractOptions, concurrency int, report *backfillReport) {
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, max(concurrency, 1))
+
+	for _, id := range interviewIDs {
+		wg.Add(1)
+		semaphore <- struct{}{}
+
+		go func(id string) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			reextraction, err := extractorService.ReextractProfile(id, opts)
+			report.record(id, reextraction, err)
+		}(id)
+	}
+	wg.Wait()
+}
+
+// runBatches отправляет интервью пакетами по batchSize, затем ждет каждый пакет и сохраняет профили.
+// Интервью, которые извлекаются по частям, обрабатываются обычными запросами.
+func runBatches(ctx context.Context, extractorService *extractor.Service, interviewIDs []string, opts extractor.ExtractOptions, batchSize, concurrency int, pollInterval time.Duration, report *backfillReport) {
+	batchSize = min(max(batchSize, 1), api.MaxBatchRequests)
+
+	var jobs []*storage.BatchJob
+	var synchronous []string
+	for start := 0; start < len(interviewIDs); start += batchSize {
+		end := min(start+batchSize, len(interviewIDs))
+		job, rest, err := extractorService.SubmitBatchReextraction(ctx, interviewIDs[start:end], opts)
+		if err != nil {
+			log.Fatalf("Ошибка отправки пакета: %v", err)
+		}
+		synchronous = append(synchronous, rest...)
+		if job != nil {
+			jobs = append(jobs, job)
+			fmt.Printf("📦 Пакет %s: %d интервью (продолжить после прерывания: -batch-resume %s)\n", job.BatchID, len(job.InterviewIDs), job.BatchID)
+		}
+	}
+
+	if len(synchronous) > 0 {
+		fmt.Printf("✂️ Интервью, извлекаемые по частям, обрабатываются сразу: %d\n", len(synchronous))
+		reextractAll(extractorService, synchronous, opts, concurrency, report)
+	}
+	for _, job := range jobs {
+		ingestBatch(ctx, extractorService, job, pollInterval, report)
+	}
+}
+
+// ingestBatch ждет завершения пакета и сохраняет профили из его ответов
+func ingestBatch(ctx context.Context, extractorService *extractor.Service, job *storage.BatchJob, pollInterval time.Duration, report *backfillReport) {
+	lastStatus := ""
+	batch, err := extractorService.WaitBatch(ctx, job.BatchID, pollInterval, func(batch *api.Batch) {
+		if batch.Status != lastStatus {
+			lastStatus = batch.Status
+			fmt.Printf("⏳ Пакет %s: %s (%d/%d)\n", batch.ID, batch.Status, batch.RequestCounts.Completed, batch.RequestCounts.Total)
+		}
+	})
+	if err != nil {
+		log.Fatalf("Пакет %s не дождались: %v\nПродолжить: backfill -batch-resume %s", job.BatchID, err, job.BatchID)
+	}
+	if batch.Status != api.BatchCompleted {
+		fmt.Printf("⚠️ Пакет %s завершился со статусом %s: профили без ответа не обновлены\n", batch.ID, batch.Status)
+	}
+
+	if err := extractorService.IngestBatch(ctx, job, extractor.ExtractOptions{}, report.record); err != nil {
+		log.Fatalf("Ошибка обработки пакета %s: %v", job.BatchID, err)
+	}
+}
+
+// confirm запрашивает подтверждение у оператора
+func confirm(question string) bool {
+	fmt.Printf("%s [y/N]: ", question)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes" || answer == "д" || answer == "да"
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"interview-bot-complete/internal/config"
+	"interview-bot-complete/internal/engine"
+	"interview-bot-complete/internal/extractor"
+	"interview-bot-complete/internal/interviewer"
+	"interview-bot-complete/internal/storage"
+	"interview-bot-complete/internal/tenant"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/joho/godotenv"
+)
+
+// cli проводит интервью в терминале на том же движке, что и Telegram бот: вопросы, уточнения,
+// условия блоков и саммари совпадают, результат и профиль сохраняются в те же директории.
+// Каждая строка ввода - один ответ; /stop прерывает интервью.
+func main() {
+	templateID := flag.String("template", "", "шаблон интервью (по умолчанию основной)")
+	userID := flag.Int64("user", 1, "ID участника (определяет варианты вопросов)")
+	lang := flag.String("lang", "", "язык вопросов, если у шаблона есть перевод (ru, en)")
+	persona := flag.String("persona", "", "персона интервьюера из шаблона (по умолчанию - персона шаблона)")
+	noProfile := flag.Bool("no-profile", false, "не составлять профиль после интервью")
+	flag.Parse()
+
+	if err := godotenv.Load(); err != nil {
+		log.Printf("Предупреждение: .env файл не найден, используем переменные системы")
+	}
+
+	openaiKey := os.Getenv("OPENAI_API_KEY")
+	if openaiKey == "" && !strings.EqualFold(os.Getenv("LLM_PROVIDER"), interviewer.ProviderMock) {
+		log.Fatal("OPENAI_API_KEY не установлен")
+	}
+
+	appCfg := config.LoadAppConfig()
+	// Арендаторы: файлы каждого хранятся в его директории, как в боте
+	tenants, err := tenant.Load(appCfg.Tenants.File)
+	if err != nil {
+		log.Fatalf("Ошибка загрузки арендаторов: %v", err)
+	}
+	if err := storage.Configure(storage.Paths{
+		ResultsDir:  appCfg.Storage.ResultsDir,
+		OutputDir:   appCfg.Storage.OutputDir,
+		ResultFile:  appCfg.Storage.ResultFileTemplate,
+		ProfileFile: appCfg.Storage.ProfileFileTemplate,
+		TenantDirs:  tenants.StoragePrefixes(),
+	}); err != nil {
+		log.Fatalf("Ошибка настройки хранилища: %v", err)
+	}
+
+	templates, err := config.LoadTemplates(appCfg.Interview.ConfigFile, appCfg.Interview.TemplatesDir)
+	if err != nil {
+		log.Fatalf("Ошибка загрузки конфигурации интервью: %v", err)
+	}
+	if *templateID != "" {
+		if _, ok := templates.Get(*templateID); !ok {
+			log.Fatalf("Неизвестный шаблон %q (доступны: %s)", *templateID, strings.Join(templates.IDs(), ", "))
+		}
+	}
+
+	var extractorService *extractor.Service
+	if !*noProfile {
+		extractorService, err = extractor.New(openaiKey)
+		if err != nil {
+			log.Fatalf("Ошибка инициализации Profile Extractor: %v", err)
+		}
+	}
+
+	interviewEngine := engine.New(templates, interviewer.New(openaiKey))
+	session := interviewEngine.NewInterview(*userID, *templateID)
+	interviewEngine.SetLanguage(session, *lang)
+	if err := interviewEngine.SetPersona(session, *persona); err != nil {
+		log.Fatalf("Ошибка выбора персоны: %v", err)
+	}
+	cfg := interviewEngine.Config(session)
+	fmt.Printf("🎯 Интервью %s: блоков %d, вопросов в блоке до %d\n", session.InterviewID,
+		cfg.GetTotalBlocks(), cfg.GetQuestionsPerBlock()+cfg.GetMaxFollowupQuestions())
+	fmt.Println("Каждая строка - ответ на вопрос, /stop - прервать интервью.")
+
+	ctx := context.Background()
+	input := bufio.NewScanner(os.Stdin)
+	input.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	prompt, events, err := interviewEngine.Start(ctx, session)
+	for {
+		printEvents(events, cfg.GetTotalBlocks())
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			if errors.Is(err, engine.ErrCompleted) {
+				break
+			}
+			if !errors.Is(err, engine.ErrInvalidChoice) {
+				fmt.Println("Нажмите Enter, чтобы повторить.")
+			}
+		}
+		if session.Phase == engine.PhaseCompleted {
+			break
+		}
+		if prompt != nil {
+			printPrompt(prompt)
+		}
+
+		answer, ok := readAnswer(input, session.Phase == engine.PhaseBlockPending)
+		if !ok || answer == "/stop" {
+			abandon(interviewEngine, session, input, extractorService)
+			return
+		}
+		prompt, events, err = interviewEngine.Advance(ctx, session, answer)
+	}
+
+	fmt.Printf("\n✅ Интервью завершено: %s\n", session.InterviewID)
+	if extractorService != nil {
+		extractProfile(extractorService, cfg, session.Result)
+	}
+}
+
+// readAnswer читает непустой ответ; в фазе повтора завершения блока подходит и пустая строка
+func readAnswer(input *bufio.Scanner, retry bool) (string, bool) {
+	for {
+		fmt.Print("> ")
+		if !input.Scan() {
+			return "", false
+		}
+		answer := strings.TrimSpace(input.Text())
+		if answer != "" || retry {
+			return answer, true
+		}
+		fmt.Println("Пожалуйста, дайте ответ.")
+	}
+}
+
+func printEvents(events []engine.Event, totalBlocks int) {
+	for _, event := range events {
+		switch event.Kind {
+		case engine.EventBlockStarted:
+			fmt.Printf("\n📋 Блок %d/%d: %s\n", event.Block, totalBlocks, event.BlockTitle)
+		case engine.EventBlockSkipped:
+			fmt.Printf("⏭ Блок %d пропущен: %s\n", event.Block, event.BlockTitle)
+		case engine.EventBlockFinished:
+			fmt.Printf("✅ Блок %d завершен\n", event.Block)
+		}
+	}
+}
+
+func printPrompt(prompt *engine.Prompt) {
+	if prompt.Kind == engine.PromptClarification {
+		fmt.Printf("🔎 %s\n", prompt.Text)
+		return
+	}
+	fmt.Printf("\n❓ Вопрос %d: %s\n", prompt.Number, prompt.Text)
+	for i, option := range prompt.Options {
+		fmt.Printf("  %d. %s\n", i+1, option)
+	}
+	if len(prompt.Options) > 0 {
+		hint := "Введите номер или текст варианта"
+		if prompt.AllowOther {
+			hint += " либо свой ответ"
+		}
+		fmt.Println(hint + ".")
+	}
+}
+
+// abandon прерывает интервью; по пройденным блокам можно составить неполный профиль, как в боте
+func abandon(interviewEngine *engine.Engine, session *engine.Session, input *bufio.Scanner, extractorService *extractor.Service) {
+	fmt.Println("\n⏹ Интервью прервано.")
+	result := interviewEngine.Abandon(session)
+	if result == nil || extractorService == nil {
+		return
+	}
+
+	fmt.Printf("Пройдено блоков: %d из %d. Составить неполный профиль по данным ответам? [y/N]: ",
+		len(result.Blocks), result.TotalBlocks-len(result.SkippedBlocks))
+	if !input.Scan() || !isYes(input.Text()) {
+		fmt.Println("Ответы не сохранены.")
+		return
+	}
+	if err := storage.SaveResult(result); err != nil {
+		log.Fatalf("Ошибка сохранения результата интервью: %v", err)
+	}
+	extractProfile(extractorService, interviewEngine.Config(session), result)
+}
+
+// extractProfile составляет и сохраняет профиль с настройками модели шаблона
+func extractProfile(extractorService *extractor.Service, cfg *config.Config, result *storage.InterviewResult) {
+	fmt.Println("🧠 Составляю профиль...")
+	settings := cfg.ModelFor(config.UseCaseExtraction)
+	profileResult, err := extractorService.ExtractProfileWithOptions(result, extractor.ExtractOptions{
+		Model:       settings.Model,
+		Temperature: settings.Temperature,
+		MaxTokens:   settings.MaxTokens,
+	})
+	if err == nil && !profileResult.Success {
+		err = errors.New(profileResult.Error)
+	}
+	if err != nil {
+		log.Fatalf("Ошибка при анализе профиля: %v", err)
+	}
+
+	fileName, err := extractorService.SaveProfile(result.InterviewID, profileResult)
+	if err != nil {
+		log.Fatalf("Профиль создан, но не удалось сохранить файл: %v", err)
+	}
+	if summary, err := extractorService.GetProfileSummary(profileResult.ProfileJSON); err == nil {
+		fmt.Printf("\n🎯 %s\n", summary)
+	}
+	fmt.Printf("💾 Профиль сохранен: %s\n", fileName)
+}
+
+func isYes(answer string) bool {
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes" || answer == "д" || answer == "да"
+}
@@ -0,0 +1,259 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"interview-bot-complete/internal/condition"
+	"interview-bot-complete/internal/config"
+	"interview-bot-complete/internal/interviewer"
+	"interview-bot-complete/internal/prompts"
+	"interview-bot-complete/internal/schema"
+	"interview-bot-complete/internal/storage"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/joho/godotenv"
+)
+
+// schemaTypes - типы полей, которые понимают промпты извлечения
+var schemaTypes = map[string]bool{"string": true, "int": true, "float": true, "bool": true, "array": true, "object": true}
+
+// Заглушки данных интервью в предпросмотре промптов
+const (
+	sampleAnswer        = "<ответ участника>"
+	sampleInterviewText = "<текст интервью>"
+)
+
+// checker накапливает ошибки и предупреждения проверки
+type checker struct {
+	errors   int
+	warnings int
+}
+
+func (c *checker) fail(format string, args ...interface{}) {
+	c.errors++
+	fmt.Printf("❌ "+format+"\n", args...)
+}
+
+func (c *checker) warn(format string, args ...interface{}) {
+	c.warnings++
+	fmt.Printf("⚠️ "+format+"\n", args...)
+}
+
+// validate-config проверяет шаблоны интервью, схему профиля и шаблоны промптов извлечения
+// перед деплоем и печатает промпты каждого блока в том виде, в каком их получит модель.
+// Запросов к OpenAI не выполняет; при ошибках завершается с кодом 1.
+func main() {
+	configFile := flag.String("config", "", "основной шаблон интервью (по умолчанию INTERVIEW_CONFIG)")
+	templatesDir := flag.String("templates", "", "каталог дополнительных шаблонов (по умолчанию INTERVIEW_TEMPLATES_DIR)")
+	schemaFile := flag.String("schema", "config/profile_schema.yaml", "схема профиля")
+	promptsDir := flag.String("prompts", "", "каталог шаблонов промптов извлечения (по умолчанию PROMPTS_DIR)")
+	templateID := flag.String("template", "", "проверить только шаблон с этим ID")
+	lang := flag.String("lang", "", "язык перевода шаблона для предпросмотра (по умолчанию язык шаблона)")
+	quiet := flag.Bool("quiet", false, "не печатать промпты, только результат проверки")
+	flag.Parse()
+
+	if err := godotenv.Load(); err != nil {
+		log.Printf("Предупреждение: .env файл не найден, используем переменные системы")
+	}
+
+	appCfg := config.LoadAppConfig()
+	if *configFile == "" {
+		*configFile = appCfg.Interview.ConfigFile
+	}
+	if *templatesDir == "" {
+		*templatesDir = appCfg.Interview.TemplatesDir
+	}
+	if *promptsDir == "" {
+		*promptsDir = appCfg.Interview.PromptsDir
+	}
+
+	c := &checker{}
+	fmt.Printf("📄 Схема профиля: %s\n", *schemaFile)
+	fields := c.checkSchema(*schemaFile)
+
+	fmt.Printf("\n📝 Промпты извлечения: %s\n", *promptsDir)
+	c.checkExtractionPrompts(*promptsDir, fields)
+
+	files, err := config.TemplateFiles(*configFile, *templatesDir)
+	if err != nil {
+		log.Fatalf("Ошибка поиска шаблонов интервью: %v", err)
+	}
+	ids := make([]string, 0, len(files))
+	for id := range files {
+		if *templateID == "" || id == *templateID {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		log.Fatalf("Шаблон %q не найден", *templateID)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		fmt.Printf("\n📋 Шаблон %s: %s\n", id, files[id])
+		cfg, err := config.Load(files[id])
+		if err != nil {
+			c.fail("%v", err)
+			continue
+		}
+		c.checkTemplate(cfg, appCfg)
+		if !*quiet {
+			previewTemplate(cfg.Localized(cfg.MatchLanguage(*lang)), fields)
+		}
+	}
+
+	fmt.Printf("\n📊 Шаблонов: %d, ошибок: %d, предупреждений: %d\n", len(ids), c.errors, c.warnings)
+	if c.errors > 0 {
+		os.Exit(1)
+	}
+}
+
+// checkSchema разбирает схему профиля и проверяет типы полей
+func (c *checker) checkSchema(file string) map[string]schema.SchemaField {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		c.fail("ошибка чтения схемы: %v", err)
+		return nil
+	}
+	fields, err := schema.ParseYAMLSchema(data)
+	if err != nil {
+		c.fail("ошибка разбора схемы: %v", err)
+		return nil
+	}
+	if len(fields) == 0 {
+		c.fail("схема не содержит полей")
+		return nil
+	}
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if field := fields[name]; !schemaTypes[field.Type] {
+			c.warn("поле %s: неизвестный тип %q, модель получит его как есть", name, field.Type)
+		}
+		if root, _, nested := strings.Cut(name, "."); nested {
+			if parent, ok := fields[root]; ok && parent.Type != "object" {
+				c.fail("поле %s: родительское поле %s объявлено с типом %s, а не object", name, root, parent.Type)
+			}
+		}
+	}
+	fmt.Printf("✅ полей: %d\n", len(fields))
+	return fields
+}
+
+// checkExtractionPrompts загружает шаблоны промптов с диска и строит промпт каждой версии
+// на каждом языке по схеме профиля
+func (c *checker) checkExtractionPrompts(dir string, fields map[string]schema.SchemaField) {
+	if err := prompts.LoadTemplates(dir); err != nil {
+		c.fail("%v", err)
+		return
+	}
+	for _, version := range prompts.PromptVersions() {
+		for _, lang := range []string{"ru", "en"} {
+			if _, err := prompts.GenerateExtractionPrompt(version, fields, sampleInterviewText, lang); err != nil {
+				c.fail("версия %s (%s): %v", version, lang, err)
+			}
+		}
+	}
+	fmt.Printf("✅ версии: %s (по умолчанию %s)\n", strings.Join(prompts.PromptVersions(), ", "), prompts.DefaultPromptVersion)
+}
+
+// checkTemplate дополняет проверки config.Load тем, что не мешает запуску бота,
+// но почти наверняка является ошибкой автора шаблона
+func (c *checker) checkTemplate(cfg *config.Config, appCfg *config.AppConfig) {
+	followups := cfg.GetMaxFollowupQuestions()
+	usedFlags := make(map[string]bool)
+	for n, block := range cfg.Blocks {
+		fmt.Printf("   блок %d «%s»: вопросов %d, уточняющих до %d", block.ID, block.Title, len(block.Questions), followups)
+		if block.Condition != "" {
+			fmt.Printf(", условие: %s", block.Condition)
+		}
+		fmt.Println()
+
+		if len(block.FocusAreas) == 0 && followups > 0 {
+			c.warn("блок %d: нет focus_areas, уточняющие вопросы будут без областей фокуса", block.ID)
+		}
+		if len(block.CoverageGoals) > 0 && followups == 0 {
+			c.warn("блок %d: coverage_goals заданы, но max_followup_questions = 0 - нераскрытые темы не будут дозаданы", block.ID)
+		}
+		if block.Condition == "" {
+			continue
+		}
+		expr, err := condition.Parse(block.Condition)
+		if err != nil {
+			// config.Load уже проверил условие
+			continue
+		}
+		for _, name := range condition.Flags(expr) {
+			usedFlags[name] = true
+		}
+		if n == 0 {
+			c.warn("блок %d: условие первого блока вычисляется до ответов и саммари", block.ID)
+		}
+	}
+
+	for name := range cfg.Flags {
+		if !usedFlags[name] {
+			c.warn("флаг %s не используется в условиях блоков", name)
+		}
+	}
+	if catalog := cfg.InterviewConfig.ArchetypeCatalog; catalog != "" {
+		c.checkFile("archetype_catalog", filepath.Join(appCfg.Premium.ArchetypesDir, catalog+".yaml"))
+	}
+	if rubric := cfg.InterviewConfig.Rubric; rubric != "" {
+		c.checkFile("rubric", filepath.Join(appCfg.Outcome.RubricsDir, rubric+".yaml"))
+	}
+	if languages := cfg.Languages(); len(languages) > 1 {
+		fmt.Printf("   языки: %s\n", strings.Join(languages, ", "))
+	}
+}
+
+// checkFile проверяет, что файл, на который ссылается шаблон, существует
+func (c *checker) checkFile(setting, file string) {
+	if _, err := os.Stat(file); err != nil {
+		c.fail("%s: файл %s не найден", setting, file)
+	}
+}
+
+// previewTemplate печатает промпты каждого блока: уточняющего вопроса (после базовых вопросов
+// блока), саммари и проверки целей, а затем промпт извлечения профиля
+func previewTemplate(cfg *config.Config, fields map[string]schema.SchemaField) {
+	service := interviewer.New("")
+	var summaries []storage.BlockSummary
+	for _, block := range cfg.Blocks {
+		dialogue := make([]storage.QA, 0, len(block.Questions))
+		for _, slot := range block.Questions {
+			dialogue = append(dialogue, storage.QA{Question: slot.Variants[0], Answer: sampleAnswer})
+		}
+
+		if cfg.GetMaxFollowupQuestions() > 0 {
+			printPrompt(fmt.Sprintf("Блок %d: уточняющий вопрос", block.ID), service.PreviewQuestionPrompt(block, dialogue, summaries, cfg))
+		}
+		printPrompt(fmt.Sprintf("Блок %d: саммари", block.ID), service.PreviewSummaryPrompt(dialogue, cfg))
+
+		summary := storage.BlockSummary{Text: fmt.Sprintf("<саммари блока %d>", block.ID)}
+		if len(block.CoverageGoals) > 0 {
+			printPrompt(fmt.Sprintf("Блок %d: проверка целей", block.ID), service.PreviewCoveragePrompt(summary, block.CoverageGoals))
+		}
+		summaries = append(summaries, summary)
+	}
+
+	if fields == nil {
+		return
+	}
+	prompt, err := prompts.GenerateExtractionPrompt(prompts.DefaultPromptVersion, fields, sampleInterviewText, cfg.Language())
+	if err == nil {
+		printPrompt("Извлечение профиля ("+prompts.DefaultPromptVersion+")", prompt)
+	}
+}
+
+func printPrompt(title, prompt string) {
+	fmt.Printf("\n──── %s ────\n%s\n", title, prompt)
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"interview-bot-complete/internal/config"
+	"interview-bot-complete/internal/storage"
+	"interview-bot-complete/internal/tenant"
+	"log"
+	"os"
+
+	"github.com/joho/godotenv"
+)
+
+// verify проверяет целостность сохраненных результатов интервью и профилей.
+// С -repair файлы без контрольной суммы получают ее, а файлы с некорректным JSON
+// переносятся в карантин (<файл>.corrupt-<время>); профили затем можно
+// восстановить повторным извлечением через cmd/backfill.
+func main() {
+	repair := flag.Bool("repair", false, "восстановить: досчитать контрольные суммы и убрать поврежденные файлы в карантин")
+	acceptModified := flag.Bool("accept-modified", false, "с -repair: пересчитать сумму файлов с корректным JSON, измененных после сохранения")
+	flag.Parse()
+
+	if err := godotenv.Load(); err != nil {
+		log.Printf("Предупреждение: .env файл не найден, используем переменные системы")
+	}
+
+	appCfg := config.LoadAppConfig()
+	storageCfg := appCfg.Storage
+	// Арендаторы: файлы каждого хранятся в его директории, как в боте
+	tenants, err := tenant.Load(appCfg.Tenants.File)
+	if err != nil {
+		log.Fatalf("Ошибка загрузки арендаторов: %v", err)
+	}
+	if err := storage.Configure(storage.Paths{
+		ResultsDir:  storageCfg.ResultsDir,
+		OutputDir:   storageCfg.OutputDir,
+		ResultFile:  storageCfg.ResultFileTemplate,
+		ProfileFile: storageCfg.ProfileFileTemplate,
+		TenantDirs:  tenants.StoragePrefixes(),
+	}); err != nil {
+		log.Fatalf("Ошибка настройки хранилища: %v", err)
+	}
+
+	interviewIDs, err := storage.ListResults()
+	if err != nil {
+		log.Fatalf("Ошибка чтения списка интервью: %v", err)
+	}
+
+	var files []string
+	for _, id := range interviewIDs {
+		if path, err := storage.FindResult(id); err == nil {
+			files = append(files, path)
+		}
+		for revision := 1; ; revision++ {
+			path, err := storage.FindProfile(id, revision)
+			if err != nil {
+				break
+			}
+			files = append(files, path)
+		}
+	}
+
+	var ok, unsealed, corrupted, repaired, unresolved int
+	for _, path := range files {
+		err := storage.VerifyFile(path)
+		switch {
+		case err == nil && storage.HasChecksum(path):
+			ok++
+		case err == nil:
+			unsealed++
+			fmt.Printf("⚪ %s: нет контрольной суммы\n", path)
+			if *repair {
+				if err := storage.Reseal(path); err != nil {
+					fmt.Printf("   ❌ %v\n", err)
+				} else {
+					repaired++
+					fmt.Println("   ✅ контрольная сумма сохранена")
+				}
+			}
+		case errors.Is(err, storage.ErrCorrupted):
+			corrupted++
+			fmt.Printf("❌ %v\n", err)
+			if *repair {
+				fixed := repairFile(path, *acceptModified)
+				repaired += fixed
+				unresolved += 1 - fixed
+			} else {
+				unresolved++
+			}
+		default:
+			corrupted++
+			unresolved++
+			fmt.Printf("❌ %s: %v\n", path, err)
+		}
+	}
+
+	fmt.Printf("\n📊 Файлов: %d, в порядке: %d, без контрольной суммы: %d, повреждено: %d\n",
+		len(files), ok, unsealed, corrupted)
+	if *repair {
+		fmt.Printf("🔧 Восстановлено: %d\n", repaired)
+	} else if unsealed+corrupted > 0 {
+		fmt.Println("Для восстановления запустите с -repair")
+	}
+
+	if unresolved > 0 {
+		os.Exit(1)
+	}
+}
+
+// repairFile пересчитывает сумму измененного файла с корректным JSON (при acceptModified)
+// или переносит файл с некорректным JSON в карантин. Возвращает 1, если файл восстановлен.
+func repairFile(path string, acceptModified bool) int {
+	data, err := os.ReadFile(path)
+	if err == nil && json.Valid(data) {
+		if !acceptModified {
+			fmt.Println("   ⚠️ JSON корректен, но файл изменен после сохранения; проверьте его и запустите с -accept-modified")
+			return 0
+		}
+		if err := storage.Reseal(path); err != nil {
+			fmt.Printf("   ❌ %v\n", err)
+			return 0
+		}
+		fmt.Println("   ✅ контрольная сумма пересчитана")
+		return 1
+	}
+
+	target, err := storage.Quarantine(path)
+	if err != nil {
+		fmt.Printf("   ❌ %v\n", err)
+		return 0
+	}
+	fmt.Printf("   📦 перемещен в %s; профиль можно извлечь заново через go run ./cmd/backfill\n", target)
+	return 1
+}
@@ -0,0 +1,142 @@
+package answercheck
+
+import (
+	"interview-bot-complete/internal/config"
+	"interview-bot-complete/internal/language"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// minRepetitionLength - ответы короче не проверяются на повторяющиеся символы
+const minRepetitionLength = 10
+
+// defaultMessages - сообщения правил по умолчанию
+var defaultMessages = map[string]string{
+	config.RuleMinLength:  "Ответ слишком короткий (минимум {min} символов). Расскажите, пожалуйста, подробнее.",
+	config.RuleMaxLength:  "Сообщение слишком длинное (максимум {max} символов).",
+	config.RuleRepetition: "Сообщение содержит слишком много повторяющихся символов.",
+	config.RuleProfanity:  "Пожалуйста, сформулируйте ответ без нецензурной лексики.",
+	config.RuleLinks:      "Ссылки в ответах не принимаются. Опишите, пожалуйста, своими словами.",
+	config.RuleLanguage:   "Пожалуйста, отвечайте на одном из языков: {languages}.",
+}
+
+// defaultProfanity - встроенный список; слово с * на конце проверяется как начало слова
+var defaultProfanity = []string{
+	"хуй*", "хуе*", "хуё*", "пизд*", "ебан*", "ебат*", "ебал*", "выеб*", "заеб*", "бля", "блять", "блядь", "бляд*",
+	"сука", "суки", "мудак*", "fuck*", "shit*", "bitch*", "cunt*", "asshole*", "motherfuck*",
+}
+
+// linkPattern - ссылки: со схемой, www., t.me и домены популярных зон. Зоны .net и .io
+// не проверяются без схемы, чтобы не отклонять названия технологий (ASP.NET, Socket.IO)
+var linkPattern = regexp.MustCompile(`(?i)(https?://|www\.|t\.me/)\S+|\b[a-z0-9-]+\.(com|ru|org|info)\b`)
+
+// Violation - нарушенное правило и сообщение для пользователя
+type Violation struct {
+	Rule    string
+	Message string
+}
+
+func (v *Violation) Error() string {
+	return v.Message
+}
+
+// Check проверяет ответ по правилам и возвращает *Violation первого нарушенного правила
+// (в порядке config.AnswerRuleNames) или nil
+func Check(rules config.AnswerRules, text string) error {
+	length := utf8.RuneCountInString(strings.TrimSpace(text))
+	for _, rule := range config.AnswerRuleNames {
+		var violated bool
+		switch rule {
+		case config.RuleMaxLength:
+			violated = rules.MaxLength > 0 && length > rules.MaxLength
+		case config.RuleMinLength:
+			violated = rules.MinLength > 0 && length < rules.MinLength
+		case config.RuleRepetition:
+			violated = rules.MaxRepeatedShare > 0 && repeatedShare(text) > rules.MaxRepeatedShare
+		case config.RuleLinks:
+			violated = rules.BlockLinks && linkPattern.MatchString(text)
+		case config.RuleProfanity:
+			violated = rules.ProfanityFilter && containsProfanity(text, rules.ProfanityWords)
+		case config.RuleLanguage:
+			violated = len(rules.Languages) > 0 && !allowedLanguage(text, rules.Languages)
+		}
+		if violated {
+			return &Violation{Rule: rule, Message: message(rules, rule)}
+		}
+	}
+	return nil
+}
+
+// repeatedShare возвращает долю самого частого символа в ответе (0 для коротких ответов)
+func repeatedShare(text string) float64 {
+	counts := make(map[rune]int)
+	total, top := 0, 0
+	for _, r := range text {
+		total++
+		counts[r]++
+		if counts[r] > top {
+			top = counts[r]
+		}
+	}
+	if total <= minRepetitionLength {
+		return 0
+	}
+	return float64(top) / float64(total)
+}
+
+// containsProfanity ищет в ответе слова из встроенного и дополнительного списков
+func containsProfanity(text string, extra []string) bool {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	for _, list := range [][]string{defaultProfanity, extra} {
+		for _, pattern := range list {
+			pattern = strings.ToLower(pattern)
+			prefix := strings.HasSuffix(pattern, "*")
+			pattern = strings.TrimSuffix(pattern, "*")
+			if pattern == "" {
+				continue
+			}
+			for _, word := range words {
+				if word == pattern || (prefix && strings.HasPrefix(word, pattern)) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// allowedLanguage проверяет язык ответа; короткие ответы (язык не определяется) допускаются
+func allowedLanguage(text string, languages []string) bool {
+	if !language.Confident(text) {
+		return true
+	}
+	detected := language.Detect(text)
+	for _, lang := range languages {
+		if lang == detected {
+			return true
+		}
+	}
+	return false
+}
+
+// message возвращает сообщение правила с подставленными значениями
+func message(rules config.AnswerRules, rule string) string {
+	text := rules.Messages[rule]
+	if text == "" {
+		text = defaultMessages[rule]
+	}
+	names := make([]string, 0, len(rules.Languages))
+	for _, lang := range rules.Languages {
+		names = append(names, language.Name(lang))
+	}
+	return strings.NewReplacer(
+		"{min}", strconv.Itoa(rules.MinLength),
+		"{max}", strconv.Itoa(rules.MaxLength),
+		"{languages}", strings.Join(names, ", "),
+	).Replace(text)
+}
@@ -0,0 +1,389 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// MaxBatchRequests - предел запросов в одном пакете OpenAI Batch API
+	MaxBatchRequests = 50000
+	// BatchDiscount - доля обычной цены, которую стоят запросы в пакете
+	BatchDiscount = 0.5
+
+	batchEndpoint         = "/v1/chat/completions"
+	batchCompletionWindow = "24h"
+	// localBatchPrefix - ID пакетов, выполненных локально (mock-провайдер и Completer)
+	localBatchPrefix = "local_batch_"
+)
+
+// Статусы пакета OpenAI Batch API
+const (
+	BatchValidating = "validating"
+	BatchInProgress = "in_progress"
+	BatchFinalizing = "finalizing"
+	BatchCompleted  = "completed"
+	BatchFailed     = "failed"
+	BatchExpired    = "expired"
+	BatchCancelling = "cancelling"
+	BatchCancelled  = "cancelled"
+)
+
+// ErrBatchUnsupported - пакетные запросы недоступны для настроенного API
+var ErrBatchUnsupported = errors.New("batch API is not supported for Azure OpenAI endpoints")
+
+// BatchRequest - запрос пакета: CustomID связывает ответ с запросом
+type BatchRequest struct {
+	CustomID string
+	Prompt   string
+	Options  CompletionOptions
+}
+
+// Batch - состояние пакета OpenAI Batch API
+type Batch struct {
+	ID            string            `json:"id"`
+	Status        string            `json:"status"`
+	InputFileID   string            `json:"input_file_id"`
+	OutputFileID  string            `json:"output_file_id,omitempty"`
+	ErrorFileID   string            `json:"error_file_id,omitempty"`
+	CreatedAt     int64             `json:"created_at"`
+	RequestCounts BatchCounts       `json:"request_counts"`
+	Metadata      map[string]string `json:"metadata,omitempty"`
+	Errors        *struct {
+		Data []APIError `json:"data"`
+	} `json:"errors,omitempty"`
+}
+
+// BatchCounts - число запросов пакета по состояниям
+type BatchCounts struct {
+	Total     int `json:"total"`
+	Completed int `json:"completed"`
+	Failed    int `json:"failed"`
+}
+
+// Done сообщает, что пакет больше не изменится
+func (b *Batch) Done() bool {
+	switch b.Status {
+	case BatchCompleted, BatchFailed, BatchExpired, BatchCancelled:
+		return true
+	}
+	return false
+}
+
+// BatchResult - ответ на запрос пакета: Completion или Err
+type BatchResult struct {
+	Completion *Completion
+	Err        error
+}
+
+// batchLine - строка входного файла пакета
+type batchLine struct {
+	CustomID string        `json:"custom_id"`
+	Method   string        `json:"method"`
+	URL      string        `json:"url"`
+	Body     OpenAIRequest `json:"body"`
+}
+
+// batchOutputLine - строка файла результатов или ошибок пакета
+type batchOutputLine struct {
+	CustomID string `json:"custom_id"`
+	Response *struct {
+		StatusCode int            `json:"status_code"`
+		Body       OpenAIResponse `json:"body"`
+	} `json:"response"`
+	Error *APIError `json:"error"`
+}
+
+// localBatches - пакеты, выполненные без обращения к OpenAI
+type localBatches struct {
+	mu      sync.Mutex
+	next    int
+	results map[string]map[string]BatchResult
+}
+
+// SubmitBatch загружает запросы файлом и создает пакет OpenAI Batch API. Ответы приходят
+// в течение 24 часов по цене BatchDiscount от обычной; их забирает BatchResults.
+// С mock-провайдером и Completer пакет выполняется сразу и хранится в памяти клиента.
+func (c *OpenAIClient) SubmitBatch(ctx context.Context, requests []BatchRequest, metadata map[string]string) (*Batch, error) {
+	if len(requests) == 0 {
+		return nil, fmt.Errorf("batch has no requests")
+	}
+	if len(requests) > MaxBatchRequests {
+		return nil, fmt.Errorf("batch has %d requests, the limit is %d", len(requests), MaxBatchRequests)
+	}
+	if c.completer != nil || isMockProvider(c.provider) {
+		return c.submitLocalBatch(requests, metadata)
+	}
+	if c.endpoint.IsAzure() {
+		return nil, ErrBatchUnsupported
+	}
+
+	var input bytes.Buffer
+	for _, request := range requests {
+		line, err := json.Marshal(batchLine{
+			CustomID: request.CustomID,
+			Method:   http.MethodPost,
+			URL:      batchEndpoint,
+			Body:     c.batchRequestBody(request.Prompt, request.Options),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling batch request %s: %w", request.CustomID, err)
+		}
+		input.Write(append(line, '\n'))
+	}
+
+	fileID, err := c.uploadBatchFile(ctx, input.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"input_file_id":     fileID,
+		"endpoint":          batchEndpoint,
+		"completion_window": batchCompletionWindow,
+		"metadata":          metadata,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling batch: %w", err)
+	}
+	var batch Batch
+	if err := c.batchCall(ctx, http.MethodPost, "/batches", "application/json", bytes.NewReader(body), &batch); err != nil {
+		return nil, err
+	}
+	c.logger.Info("Batch submitted", "batch_id", batch.ID, "requests", len(requests), "input_file_id", fileID)
+	return &batch, nil
+}
+
+// GetBatch возвращает текущее состояние пакета
+func (c *OpenAIClient) GetBatch(ctx context.Context, batchID string) (*Batch, error) {
+	if strings.HasPrefix(batchID, localBatchPrefix) {
+		return c.localBatch(batchID)
+	}
+	var batch Batch
+	if err := c.batchCall(ctx, http.MethodGet, "/batches/"+batchID, "", nil, &batch); err != nil {
+		return nil, err
+	}
+	return &batch, nil
+}
+
+// WaitBatch опрашивает пакет каждые interval, пока он не завершится; onPoll получает каждое состояние
+func (c *OpenAIClient) WaitBatch(ctx context.Context, batchID string, interval time.Duration, onPoll func(*Batch)) (*Batch, error) {
+	for {
+		batch, err := c.GetBatch(ctx, batchID)
+		if err != nil {
+			return nil, err
+		}
+		if onPoll != nil {
+			onPoll(batch)
+		}
+		if batch.Done() {
+			return batch, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// BatchResults скачивает ответы завершенного пакета по CustomID запросов. Запросы, не вошедшие
+// в результаты (пакет истек или отменен), отсутствуют в ответе.
+func (c *OpenAIClient) BatchResults(ctx context.Context, batch *Batch) (map[string]BatchResult, error) {
+	if strings.HasPrefix(batch.ID, localBatchPrefix) {
+		c.local.mu.Lock()
+		defer c.local.mu.Unlock()
+		return c.local.results[batch.ID], nil
+	}
+
+	results := make(map[string]BatchResult)
+	for _, fileID := range []string{batch.OutputFileID, batch.ErrorFileID} {
+		if fileID == "" {
+			continue
+		}
+		var content bytes.Buffer
+		if err := c.batchCall(ctx, http.MethodGet, "/files/"+fileID+"/content", "", nil, &content); err != nil {
+			return nil, err
+		}
+		if err := c.parseBatchOutput(&content, results); err != nil {
+			return nil, fmt.Errorf("batch %s file %s: %w", batch.ID, fileID, err)
+		}
+	}
+	return results, nil
+}
+
+// parseBatchOutput разбирает строки файла результатов пакета
+func (c *OpenAIClient) parseBatchOutput(content io.Reader, results map[string]BatchResult) error {
+	scanner := bufio.NewScanner(content)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16<<20)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var line batchOutputLine
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			return fmt.Errorf("error unmarshaling batch output: %w", err)
+		}
+		results[line.CustomID] = c.batchResult(line)
+	}
+	return scanner.Err()
+}
+
+// batchResult переводит строку результатов в ответ на запрос
+func (c *OpenAIClient) batchResult(line batchOutputLine) BatchResult {
+	switch {
+	case line.Error != nil:
+		return BatchResult{Err: fmt.Errorf("batch request failed: %s", line.Error.Message)}
+	case line.Response == nil:
+		return BatchResult{Err: fmt.Errorf("batch request has no response")}
+	case line.Response.StatusCode != http.StatusOK:
+		body, _ := json.Marshal(line.Response.Body)
+		return BatchResult{Err: &StatusError{StatusCode: line.Response.StatusCode, Body: string(body)}}
+	}
+
+	response := line.Response.Body
+	if response.Error != nil {
+		return BatchResult{Err: fmt.Errorf("OpenAI API error: %s", response.Error.Message)}
+	}
+	if len(response.Choices) == 0 {
+		return BatchResult{Err: fmt.Errorf("no choices returned from OpenAI API")}
+	}
+	if c.usage != nil {
+		c.usage.RecordUsage(response.Model, response.Usage)
+	}
+	return BatchResult{Completion: &Completion{
+		Content: cleanJSONResponse(response.Choices[0].Message.Content),
+		Model:   response.Model,
+		Usage:   response.Usage,
+	}}
+}
+
+// batchRequestBody формирует тело запроса Chat Completions с параметрами клиента по умолчанию
+func (c *OpenAIClient) batchRequestBody(prompt string, opts CompletionOptions) OpenAIRequest {
+	body := OpenAIRequest{
+		Model:          c.model,
+		Messages:       []Message{{Role: "user", Content: prompt}},
+		Temperature:    c.temperature,
+		MaxTokens:      c.maxTokens,
+		PromptCacheKey: c.cacheKey,
+	}
+	if opts.Model != "" {
+		body.Model = opts.Model
+	}
+	if opts.Temperature != nil {
+		body.Temperature = *opts.Temperature
+	}
+	if opts.MaxTokens > 0 {
+		body.MaxTokens = opts.MaxTokens
+	}
+	return body
+}
+
+// uploadBatchFile загружает входной файл пакета и возвращает его ID
+func (c *OpenAIClient) uploadBatchFile(ctx context.Context, data []byte) (string, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	if err := writer.WriteField("purpose", "batch"); err != nil {
+		return "", fmt.Errorf("error writing batch file form: %w", err)
+	}
+	part, err := writer.CreateFormFile("file", "batch.jsonl")
+	if err != nil {
+		return "", fmt.Errorf("error writing batch file form: %w", err)
+	}
+	if _, err := part.Write(data); err != nil {
+		return "", fmt.Errorf("error writing batch file form: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return "", fmt.Errorf("error writing batch file form: %w", err)
+	}
+
+	var file struct {
+		ID string `json:"id"`
+	}
+	if err := c.batchCall(ctx, http.MethodPost, "/files", writer.FormDataContentType(), &body, &file); err != nil {
+		return "", err
+	}
+	return file.ID, nil
+}
+
+// batchCall выполняет запрос к API файлов и пакетов; out - структура для JSON ответа
+// или *bytes.Buffer для содержимого файла
+func (c *OpenAIClient) batchCall(ctx context.Context, method, path, contentType string, body io.Reader, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, c.endpoint.BaseURL+path, body)
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	c.endpoint.Authorize(req, c.apiKey)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		c.logger.Error("OpenAI batch API error", "path", path, "status", resp.StatusCode, "body", string(data))
+		return &StatusError{StatusCode: resp.StatusCode, Body: string(data)}
+	}
+	if buffer, ok := out.(*bytes.Buffer); ok {
+		buffer.Write(data)
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("error unmarshaling response: %w", err)
+	}
+	return nil
+}
+
+// submitLocalBatch выполняет пакет сразу через mock-провайдер или Completer
+func (c *OpenAIClient) submitLocalBatch(requests []BatchRequest, metadata map[string]string) (*Batch, error) {
+	results := make(map[string]BatchResult, len(requests))
+	for _, request := range requests {
+		completion, err := c.ExtractProfileWithOptions(request.Prompt, request.Options)
+		results[request.CustomID] = BatchResult{Completion: completion, Err: err}
+	}
+
+	c.local.mu.Lock()
+	defer c.local.mu.Unlock()
+	if c.local.results == nil {
+		c.local.results = make(map[string]map[string]BatchResult)
+	}
+	c.local.next++
+	id := fmt.Sprintf("%s%d", localBatchPrefix, c.local.next)
+	c.local.results[id] = results
+	return &Batch{ID: id, Status: BatchCompleted, CreatedAt: time.Now().Unix(), Metadata: metadata,
+		RequestCounts: BatchCounts{Total: len(requests), Completed: len(requests)}}, nil
+}
+
+// localBatch возвращает состояние локального пакета
+func (c *OpenAIClient) localBatch(batchID string) (*Batch, error) {
+	c.local.mu.Lock()
+	defer c.local.mu.Unlock()
+	results, ok := c.local.results[batchID]
+	if !ok {
+		return nil, fmt.Errorf("local batch %s not found (local batches do not survive a restart)", batchID)
+	}
+	return &Batch{ID: batchID, Status: BatchCompleted,
+		RequestCounts: BatchCounts{Total: len(results), Completed: len(results)}}, nil
+}
+
+// EstimateBatchCost оценивает стоимость запроса в пакете
+func EstimateBatchCost(model string, usage Usage) float64 {
+	return EstimateCost(model, usage) * BatchDiscount
+}
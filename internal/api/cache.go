@@ -0,0 +1,116 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// responseCache кэширует ответы на одинаковые запросы на время ttl
+// и объединяет одновременные одинаковые запросы в один вызов API
+type responseCache struct {
+	ttl        time.Duration
+	maxEntries int
+
+	mutex    sync.Mutex
+	entries  map[string]cachedResponse
+	inflight map[string]*inflightRequest
+}
+
+type cachedResponse struct {
+	completion Completion
+	expiresAt  time.Time
+}
+
+// inflightRequest - выполняющийся запрос, результат которого ждут дубликаты
+type inflightRequest struct {
+	done       chan struct{}
+	completion *Completion
+	err        error
+}
+
+// newResponseCache создает кэш ответов; ttl <= 0 отключает хранение, но не объединение запросов
+func newResponseCache(ttl time.Duration, maxEntries int) *responseCache {
+	return &responseCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]cachedResponse),
+		inflight:   make(map[string]*inflightRequest),
+	}
+}
+
+// responseCacheKey строит ключ кэша по параметрам, влияющим на ответ модели
+func responseCacheKey(model string, temperature float64, maxTokens int, prompt string) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s\x00%g\x00%d\x00", model, temperature, maxTokens)
+	hash.Write([]byte(prompt))
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// do возвращает ответ из кэша или выполняет fetch. Второе значение сообщает,
+// что запрос к API не выполнялся: такой ответ помечается Cached и не расходует токены.
+// Ошибки не кэшируются.
+func (c *responseCache) do(key string, fetch func() (*Completion, error)) (*Completion, bool, error) {
+	c.mutex.Lock()
+	if entry, ok := c.entries[key]; ok {
+		if time.Now().Before(entry.expiresAt) {
+			c.mutex.Unlock()
+			return cachedCopy(entry.completion), true, nil
+		}
+		delete(c.entries, key)
+	}
+	if request, ok := c.inflight[key]; ok {
+		c.mutex.Unlock()
+		<-request.done
+		if request.err != nil {
+			return nil, false, request.err
+		}
+		return cachedCopy(*request.completion), true, nil
+	}
+	request := &inflightRequest{done: make(chan struct{})}
+	c.inflight[key] = request
+	c.mutex.Unlock()
+
+	request.completion, request.err = fetch()
+
+	c.mutex.Lock()
+	delete(c.inflight, key)
+	if request.err == nil && c.ttl > 0 {
+		c.store(key, *request.completion)
+	}
+	c.mutex.Unlock()
+	close(request.done)
+
+	return request.completion, false, request.err
+}
+
+// store сохраняет ответ, освобождая место от устаревших записей. Вызывается под mutex.
+func (c *responseCache) store(key string, completion Completion) {
+	now := time.Now()
+	if c.maxEntries > 0 && len(c.entries) >= c.maxEntries {
+		var oldestKey string
+		var oldest time.Time
+		for k, entry := range c.entries {
+			if now.After(entry.expiresAt) {
+				delete(c.entries, k)
+				continue
+			}
+			if oldestKey == "" || entry.expiresAt.Before(oldest) {
+				oldestKey, oldest = k, entry.expiresAt
+			}
+		}
+		if len(c.entries) >= c.maxEntries {
+			delete(c.entries, oldestKey)
+		}
+	}
+	c.entries[key] = cachedResponse{completion: completion, expiresAt: now.Add(c.ttl)}
+}
+
+// cachedCopy возвращает копию ответа для повторной выдачи без расхода токенов
+func cachedCopy(completion Completion) *Completion {
+	completion.Usage = Usage{}
+	completion.Cached = true
+	return &completion
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"interview-bot-complete/internal/config"
+	"interview-bot-complete/internal/prompts"
+	"strings"
+)
+
+// ModelCompleter - модель в ответах, полученных от Completer вместо OpenAI
+const ModelCompleter = "completer"
+
+// Сценарии запросов клиента помимо извлечения профиля; определяются по маркеру промпта
+const (
+	UseCaseProfileSummary   = "profile_summary"
+	UseCaseExtendedAnalysis = "extended_analysis"
+	UseCaseArchetypeMatch   = "archetype_match"
+	UseCaseRubricScore      = "rubric_score"
+	UseCaseJSONRepair       = "json_repair"
+	UseCaseProfileReduce    = "profile_reduce"
+	UseCaseSchemaRepair     = "schema_repair"
+	UseCaseResumeFacts      = "resume_facts"
+)
+
+// PromptUseCase определяет сценарий запроса по маркеру в начале промпта
+// (config.UseCaseExtraction для промпта извлечения профиля)
+func PromptUseCase(prompt string) string {
+	switch {
+	case strings.HasPrefix(prompt, prompts.ExtendedAnalysisMarker):
+		return UseCaseExtendedAnalysis
+	case strings.HasPrefix(prompt, prompts.ArchetypeMatchMarker):
+		return UseCaseArchetypeMatch
+	case strings.HasPrefix(prompt, prompts.RubricScoreMarker):
+		return UseCaseRubricScore
+	case strings.HasPrefix(prompt, prompts.ProfileSummaryMarker):
+		return UseCaseProfileSummary
+	case strings.HasPrefix(prompt, prompts.JSONRepairMarker):
+		return UseCaseJSONRepair
+	case strings.HasPrefix(prompt, prompts.ProfileReduceMarker):
+		return UseCaseProfileReduce
+	case strings.HasPrefix(prompt, prompts.SchemaRepairMarker):
+		return UseCaseSchemaRepair
+	case strings.HasPrefix(prompt, prompts.ResumeFactsMarker):
+		return UseCaseResumeFacts
+	default:
+		return config.UseCaseExtraction
+	}
+}
+
+// Completer отвечает на промпт вместо OpenAI: сценарные ответы в тестах и прогонах без сети.
+// useCase - сценарий обращения к модели (config.UseCase*).
+type Completer interface {
+	Complete(useCase, prompt string) (string, error)
+}
+
+// CompleterFunc позволяет использовать функцию как Completer
+type CompleterFunc func(useCase, prompt string) (string, error)
+
+// Complete вызывает f(useCase, prompt)
+func (f CompleterFunc) Complete(useCase, prompt string) (string, error) {
+	return f(useCase, prompt)
+}
+
+// SetCompleter направляет запросы клиента в completer вместо OpenAI (nil - снова OpenAI).
+// Кэш ответов и учет токенов для таких запросов не используются.
+func (c *OpenAIClient) SetCompleter(completer Completer) {
+	c.completer = completer
+}
@@ -0,0 +1,21 @@
+package api
+
+// modelContextWindows - размер контекста моделей OpenAI в токенах (промпт вместе с ответом)
+var modelContextWindows = map[string]int{
+	"gpt-4.1":       1_047_576,
+	"gpt-4o":        128_000,
+	"gpt-4":         8_192,
+	"gpt-4-turbo":   128_000,
+	"gpt-3.5-turbo": 16_385,
+}
+
+// defaultContextWindow - контекст неизвестных моделей
+const defaultContextWindow = 128_000
+
+// ContextWindow возвращает размер контекста модели в токенах
+func ContextWindow(model string) int {
+	if window, ok := lookupModel(modelContextWindows, model); ok {
+		return window
+	}
+	return defaultContextWindow
+}
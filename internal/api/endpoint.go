@@ -0,0 +1,90 @@
+package api
+
+import (
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// Типы API (OPENAI_API_TYPE)
+const (
+	APITypeOpenAI = "openai" // OpenAI или совместимый шлюз: Bearer токен, модель в теле запроса
+	APITypeAzure  = "azure"  // Azure OpenAI: заголовок api-key, модель выбирается деплойментом в URL
+)
+
+const (
+	defaultBaseURL = "https://api.openai.com/v1"
+	// defaultAzureAPIVersion - версия Azure OpenAI API, если OPENAI_API_VERSION не задана
+	defaultAzureAPIVersion = "2024-06-01"
+)
+
+// Endpoint - адрес OpenAI-совместимого API: OpenAI, прокси-шлюз или ресурс Azure OpenAI
+type Endpoint struct {
+	// BaseURL - корень API: https://api.openai.com/v1, адрес шлюза или https://<resource>.openai.azure.com
+	BaseURL string
+	APIType string
+	// APIVersion - параметр api-version запросов к Azure
+	APIVersion string
+	// Deployments - имена деплойментов Azure по моделям; модель без записи - деплоймент с ее именем
+	Deployments map[string]string
+}
+
+// EndpointFromEnv читает адрес API из OPENAI_BASE_URL, OPENAI_API_TYPE, OPENAI_API_VERSION
+// и AZURE_OPENAI_DEPLOYMENTS (пары model=deployment через запятую)
+func EndpointFromEnv() Endpoint {
+	endpoint := Endpoint{
+		BaseURL:     strings.TrimRight(getEnvOrDefault("OPENAI_BASE_URL", defaultBaseURL), "/"),
+		APIType:     strings.ToLower(getEnvOrDefault("OPENAI_API_TYPE", APITypeOpenAI)),
+		APIVersion:  getEnvOrDefault("OPENAI_API_VERSION", defaultAzureAPIVersion),
+		Deployments: make(map[string]string),
+	}
+	for _, pair := range strings.Split(os.Getenv("AZURE_OPENAI_DEPLOYMENTS"), ",") {
+		model, deployment, ok := strings.Cut(pair, "=")
+		model, deployment = strings.TrimSpace(model), strings.TrimSpace(deployment)
+		if ok && model != "" && deployment != "" {
+			endpoint.Deployments[model] = deployment
+		}
+	}
+	return endpoint
+}
+
+// IsAzure сообщает, что запросы идут в Azure OpenAI
+func (e Endpoint) IsAzure() bool {
+	return e.APIType == APITypeAzure
+}
+
+// Deployment возвращает деплоймент Azure для модели
+func (e Endpoint) Deployment(model string) string {
+	if deployment, ok := e.Deployments[model]; ok {
+		return deployment
+	}
+	return model
+}
+
+// URL возвращает адрес метода API (chat/completions, embeddings) для модели.
+// В Azure модель выбирается деплойментом в пути, версия API передается параметром.
+func (e Endpoint) URL(method, model string) string {
+	if !e.IsAzure() {
+		return e.BaseURL + "/" + method
+	}
+	return e.BaseURL + "/openai/deployments/" + url.PathEscape(e.Deployment(model)) + "/" + method +
+		"?api-version=" + url.QueryEscape(e.APIVersion)
+}
+
+// ModelsURL возвращает адрес списка моделей для проверки ключа
+func (e Endpoint) ModelsURL() string {
+	if !e.IsAzure() {
+		return e.BaseURL + "/models"
+	}
+	return e.BaseURL + "/openai/models?api-version=" + url.QueryEscape(e.APIVersion)
+}
+
+// Authorize добавляет ключ в заголовок запроса по схеме API
+func (e Endpoint) Authorize(req *http.Request, apiKey string) {
+	if e.IsAzure() {
+		req.Header.Set("api-key", apiKey)
+		return
+	}
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"fmt"
+	"interview-bot-complete/internal/apperr"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// StatusError - ответ OpenAI с кодом, отличным от 200
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("OpenAI API error: status %d, body: %s", e.StatusCode, e.Body)
+}
+
+// Unwrap возвращает категорию ошибки: 429 - превышен лимит запросов, остальные коды - LLM недоступна
+func (e *StatusError) Unwrap() error {
+	if e.StatusCode == http.StatusTooManyRequests {
+		return apperr.ErrRateLimited
+	}
+	return apperr.ErrLLMUnavailable
+}
+
+// modelErrorMarkers - признаки ошибок, связанных с конкретной моделью, в теле ответа
+var modelErrorMarkers = []string{
+	"model_not_found",
+	"does not exist",
+	"overloaded",
+	"capacity",
+	"currently unavailable",
+}
+
+// IsModelError сообщает, стоит ли повторить запрос на следующей модели цепочки:
+// модель недоступна (404), перегружена (503, 529) или ответ явно указывает на модель
+func IsModelError(statusCode int, body string) bool {
+	switch statusCode {
+	case http.StatusNotFound, http.StatusServiceUnavailable, 529:
+		return true
+	case http.StatusBadRequest, http.StatusTooManyRequests, http.StatusInternalServerError:
+		lower := strings.ToLower(body)
+		for _, marker := range modelErrorMarkers {
+			if strings.Contains(lower, marker) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// FallbackModels возвращает резервные модели из OPENAI_FALLBACK_MODELS (через запятую)
+func FallbackModels() []string {
+	var models []string
+	for _, model := range strings.Split(os.Getenv("OPENAI_FALLBACK_MODELS"), ",") {
+		if model = strings.TrimSpace(model); model != "" {
+			models = append(models, model)
+		}
+	}
+	return models
+}
+
+// ModelChain строит упорядоченную цепочку моделей: основная, затем резервные без повторов
+func ModelChain(primary string, fallbacks []string) []string {
+	chain := []string{primary}
+	seen := map[string]bool{primary: true}
+	for _, model := range fallbacks {
+		if !seen[model] {
+			seen[model] = true
+			chain = append(chain, model)
+		}
+	}
+	return chain
+}
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// apiKeyCheckTTL - время, в течение которого результат проверки ключа считается актуальным
+const apiKeyCheckTTL = 5 * time.Minute
+
+// keyCheckCache хранит результат последней проверки ключа OpenAI
+type keyCheckCache struct {
+	mutex     sync.Mutex
+	checkedAt time.Time
+	err       error
+}
+
+// CheckAPIKey проверяет валидность ключа дешевым запросом списка моделей.
+// Результат кэшируется, чтобы частые пробы готовности не расходовали лимиты.
+func (c *OpenAIClient) CheckAPIKey(ctx context.Context) error {
+	if isMockProvider(c.provider) {
+		return nil
+	}
+
+	c.keyCheck.mutex.Lock()
+	defer c.keyCheck.mutex.Unlock()
+
+	if !c.keyCheck.checkedAt.IsZero() && time.Since(c.keyCheck.checkedAt) < apiKeyCheckTTL {
+		return c.keyCheck.err
+	}
+
+	err := c.listModels(ctx)
+	c.keyCheck.checkedAt = time.Now()
+	c.keyCheck.err = err
+	return err
+}
+
+func (c *OpenAIClient) listModels(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.endpoint.ModelsURL(), nil)
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	c.endpoint.Authorize(req, c.apiKey)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error making request: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return fmt.Errorf("invalid OpenAI API key")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("OpenAI API error: status %d", resp.StatusCode)
+	}
+	return nil
+}
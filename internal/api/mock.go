@@ -0,0 +1,148 @@
+package api
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"interview-bot-complete/internal/jsonrepair"
+	"interview-bot-complete/internal/prompts"
+)
+
+// ProviderMock включает режим симуляции без обращения к OpenAI
+const ProviderMock = "mock"
+
+// mockProfileJSON - фикстура профиля, возвращаемая в режиме симуляции
+const mockProfileJSON = `{
+  "name": "Тестовый Пользователь",
+  "age": 28,
+  "birth_city": "Казань",
+  "current_city": "Москва",
+  "native_language": "русский",
+  "university": "КФУ",
+  "education_level": "магистр",
+  "field_of_study": "прикладная математика",
+  "graduation_year": 2019,
+  "current_position": "бэкенд-разработчик",
+  "work_experience_years": 5,
+  "previous_companies": ["Стартап А", "Компания Б"],
+  "career_goals": ["стать тимлидом"],
+  "hard_skills": ["Go", "SQL", "Docker"],
+  "soft_skills": ["коммуникабельность", "ответственность"],
+  "programming_languages": ["Go", "Python"],
+  "tools_and_technologies": ["PostgreSQL", "Kubernetes"],
+  "certifications": [],
+  "hobbies": ["бег", "фотография"],
+  "interests": ["распределенные системы"],
+  "favorite_books": [],
+  "favorite_movies": [],
+  "sports": ["бег"],
+  "personality_traits": ["целеустремленный", "спокойный"],
+  "values": ["развитие", "честность"],
+  "motivations": ["сложные задачи"],
+  "work_style": "самостоятельный с регулярной синхронизацией",
+  "family_status": null,
+  "has_children": null,
+  "relationship_status": null,
+  "short_term_goals": ["освоить Rust"],
+  "long_term_goals": ["запустить собственный продукт"],
+  "dream_projects": [],
+  "preferred_work_environment": "гибридный формат",
+  "communication_style": "прямой",
+  "learning_style": "через практику",
+  "languages_spoken": ["русский", "английский"],
+  "travel_experience": [],
+  "volunteer_experience": [],
+  "achievements": ["запустил сервис с нуля"],
+  "_provenance": {
+    "hard_skills": [{"ref": "B1.Q1", "quote": "Go"}],
+    "hobbies": [{"ref": "B1.Q2", "quote": "бег"}]
+  }
+}`
+
+// mockExtendedAnalysisJSON - фикстура расширенного анализа
+const mockExtendedAnalysisJSON = `{
+  "report": [
+    {"title": "Мотивация", "text": "Главный источник энергии - сложные задачи и профессиональный рост."},
+    {"title": "Стиль работы", "text": "Предпочитает работать самостоятельно с регулярной синхронизацией."}
+  ],
+  "career_paths": ["тимлид бэкенд-команды", "архитектор распределенных систем"]
+}`
+
+// mockArchetypeMatchJSON - фикстура подбора архетипа; ID подставляется из каталога в промпте
+const mockArchetypeMatchJSON = `{
+  "archetype_id": %q,
+  "description": "Тестовый пользователь стремится разобраться в устройстве сложных систем и получает удовольствие от самостоятельного поиска решений.",
+  "strengths": ["глубокое погружение в задачи", "самостоятельность"],
+  "blind_spots": ["может откладывать коммуникацию ради работы над задачей"],
+  "growth": ["регулярно делиться промежуточными результатами с командой"]
+}`
+
+// mockArchetypeIDPattern находит ID архетипов каталога и компетенций рубрики в промпте
+var mockArchetypeIDPattern = regexp.MustCompile(`(?m)^\[([a-z0-9_]+)\] `)
+
+// mockRubricScoreJSON - фикстура оценки по рубрике; оценки подставляются для всех компетенций из промпта
+const mockRubricScoreJSON = `{
+  "competencies": [%s],
+  "summary": "Кандидат уверенно владеет бэкенд-стеком и самостоятельно ведет задачи; опыт управления командой пока небольшой.",
+  "concerns": ["проверить опыт наставничества", "уточнить ожидания по формату работы"]
+}`
+
+// mockSummaryJSON - фикстура резюме профиля
+const mockSummaryJSON = `{"summary": "• Тестовый Пользователь, 28 лет, Москва\n• Бэкенд-разработчик, 5 лет опыта\n• Навыки: Go, SQL, Docker\n• Цель: стать тимлидом"}`
+
+// mockResumeFactsJSON - фикстура фактов из резюме
+const mockResumeFactsJSON = `{
+  "name": "Тестовый Пользователь",
+  "current_position": "Бэкенд-разработчик",
+  "work_experience_years": 5,
+  "previous_companies": ["ООО Ромашка"],
+  "programming_languages": ["Go", "SQL"]
+}`
+
+// mockResponse выбирает фикстуру по промпту
+func mockResponse(prompt string) string {
+	switch PromptUseCase(prompt) {
+	case UseCaseExtendedAnalysis:
+		return mockExtendedAnalysisJSON
+	case UseCaseArchetypeMatch:
+		var id string
+		if match := mockArchetypeIDPattern.FindStringSubmatch(prompt); match != nil {
+			id = match[1]
+		}
+		return fmt.Sprintf(mockArchetypeMatchJSON, id)
+	case UseCaseRubricScore:
+		var scores []string
+		for i, match := range mockArchetypeIDPattern.FindAllStringSubmatch(prompt, -1) {
+			scores = append(scores, fmt.Sprintf(`{"id": %q, "score": %d, "evidence": "Подтверждено примерами из ответов."}`, match[1], 5-i%3))
+		}
+		return fmt.Sprintf(mockRubricScoreJSON, strings.Join(scores, ", "))
+	case UseCaseProfileSummary:
+		return mockSummaryJSON
+	case UseCaseResumeFacts:
+		return mockResumeFactsJSON
+	case UseCaseJSONRepair:
+		// Модель «исправляет» JSON тем же локальным восстановлением
+		_, broken, _ := strings.Cut(prompt, prompts.JSONRepairInputHeader)
+		broken, _, _ = strings.Cut(broken, prompts.JSONRepairOutputHeader)
+		if fixed, _, err := jsonrepair.Repair(broken); err == nil {
+			return fixed
+		}
+		return "{}"
+	case UseCaseSchemaRepair:
+		// Модель возвращает профиль без изменений: фикстуры соответствуют схеме
+		_, profile, _ := strings.Cut(prompt, prompts.SchemaRepairInputHeader)
+		profile, _, _ = strings.Cut(profile, prompts.SchemaRepairOutputHeader)
+		return strings.TrimSpace(profile)
+	case UseCaseProfileReduce:
+		// Модель не выбирает значения: остаются значения последних частей
+		return "{}"
+	default:
+		return mockProfileJSON
+	}
+}
+
+// isMockProvider сообщает, выбран ли провайдер симуляции
+func isMockProvider(provider string) bool {
+	return strings.EqualFold(strings.TrimSpace(provider), ProviderMock)
+}
@@ -3,12 +3,12 @@ package api
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"interview-bot-complete/internal/apperr"
 	"io"
 	"log/slog"
-	"net"
 	"net/http"
 	"os"
 	"strings"
@@ -17,11 +17,21 @@ import (
 
 type OpenAIClient struct {
 	apiKey      string
+	provider    string
+	endpoint    Endpoint
 	model       string
 	maxTokens   int
 	temperature float64
 	client      *http.Client
 	logger      *slog.Logger
+	keyCheck    keyCheckCache
+	cache       *responseCache
+	cacheKey    string
+	fallbacks   []string
+	usage       UsageRecorder
+	completer   Completer
+	scheduler   *Scheduler
+	local       localBatches
 }
 
 type OpenAIRequest struct {
@@ -29,6 +39,8 @@ type OpenAIRequest struct {
 	Messages    []Message `json:"messages"`
 	Temperature float64   `json:"temperature"`
 	MaxTokens   int       `json:"max_tokens"`
+	// PromptCacheKey группирует запросы с общим префиксом для кэширования промпта на стороне OpenAI
+	PromptCacheKey string `json:"prompt_cache_key,omitempty"`
 }
 
 type Message struct {
@@ -53,9 +65,23 @@ type Choice struct {
 }
 
 type Usage struct {
-	PromptTokens     int `json:"prompt_tokens"`
-	CompletionTokens int `json:"completion_tokens"`
-	TotalTokens      int `json:"total_tokens"`
+	PromptTokens        int                  `json:"prompt_tokens"`
+	CompletionTokens    int                  `json:"completion_tokens"`
+	TotalTokens         int                  `json:"total_tokens"`
+	PromptTokensDetails *PromptTokensDetails `json:"prompt_tokens_details,omitempty"`
+}
+
+// PromptTokensDetails - детализация входных токенов, в том числе взятых из кэша промпта
+type PromptTokensDetails struct {
+	CachedTokens int `json:"cached_tokens"`
+}
+
+// CachedTokens возвращает число входных токенов, обслуженных из кэша промпта провайдера
+func (u Usage) CachedTokens() int {
+	if u.PromptTokensDetails == nil {
+		return 0
+	}
+	return u.PromptTokensDetails.CachedTokens
 }
 
 type APIError struct {
@@ -66,103 +92,226 @@ type APIError struct {
 
 func NewOpenAIClient(apiKey string) *OpenAIClient {
 	// Читаем настройки из переменных окружения
+	provider := getEnvOrDefault("LLM_PROVIDER", "openai")
 	model := getEnvOrDefault("OPENAI_MODEL", "gpt-4.1-mini")
 	maxTokens := getEnvAsIntOrDefault("OPENAI_MAX_TOKENS", 4000)
 	temperature := getEnvAsFloatOrDefault("OPENAI_TEMPERATURE", 0.1)
-
-	// Настройка транспорта для лучшей производительности
-	transport := &http.Transport{
-		MaxIdleConns:        100,
-		MaxConnsPerHost:     100,
-		MaxIdleConnsPerHost: 100,
-		IdleConnTimeout:     90 * time.Second,
-		TLSHandshakeTimeout: 10 * time.Second,
-		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: false,
-		},
-		DialContext: (&net.Dialer{
-			Timeout:   30 * time.Second,
-			KeepAlive: 30 * time.Second,
-		}).DialContext,
-	}
+	cacheTTL := getEnvAsDurationOrDefault("LLM_CACHE_TTL", 10*time.Minute)
+	cacheSize := getEnvAsIntOrDefault("LLM_CACHE_MAX_ENTRIES", 256)
+	cacheKey := getEnvOrDefault("OPENAI_PROMPT_CACHE_KEY", "interview-bot-extraction")
 
 	return &OpenAIClient{
 		apiKey:      apiKey,
+		provider:    provider,
+		endpoint:    EndpointFromEnv(),
 		model:       model,
 		maxTokens:   maxTokens,
 		temperature: temperature,
-		client: &http.Client{
-			Timeout:   120 * time.Second,
-			Transport: transport,
-		},
-		logger: slog.Default(),
+		client:      NewHTTPClient(120 * time.Second),
+		logger:      slog.Default(),
+		cache:       newResponseCache(cacheTTL, cacheSize),
+		cacheKey:    cacheKey,
+		fallbacks:   FallbackModels(),
 	}
 }
 
+// CompletionOptions переопределяет параметры отдельного запроса
+type CompletionOptions struct {
+	Model       string   // пустое значение - модель клиента по умолчанию
+	Temperature *float64 // nil - OPENAI_TEMPERATURE
+	MaxTokens   int      // 0 - OPENAI_MAX_TOKENS
+	Priority    Priority // класс в очереди планировщика; по умолчанию фоновый
+	NoCache     bool     // запрос к API без кэша ответов (повторное извлечение должно получить новый ответ)
+}
+
+// Completion - результат запроса вместе с фактической моделью и расходом токенов
+type Completion struct {
+	Content string
+	Model   string
+	Usage   Usage
+	Cached  bool // ответ взят из локального кэша, запрос к API не выполнялся
+	// RequestedModel - запрошенная модель, если ответ получен от резервной
+	RequestedModel string
+}
+
+// SetUsageRecorder подключает учет израсходованных токенов (ответы из кэша не учитываются)
+func (c *OpenAIClient) SetUsageRecorder(recorder UsageRecorder) {
+	c.usage = recorder
+}
+
+// SetScheduler подключает общий планировщик запросов с лимитами RPM/TPM аккаунта
+func (c *OpenAIClient) SetScheduler(scheduler *Scheduler) {
+	c.scheduler = scheduler
+}
+
+// Model возвращает модель, используемую клиентом по умолчанию
+func (c *OpenAIClient) Model() string {
+	return c.model
+}
+
+// MaxTokens возвращает лимит токенов ответа по умолчанию (OPENAI_MAX_TOKENS)
+func (c *OpenAIClient) MaxTokens() int {
+	return c.maxTokens
+}
+
 // ExtractProfile - единственный метод для работы с профилями
 func (c *OpenAIClient) ExtractProfile(prompt string) (string, error) {
+	completion, err := c.ExtractProfileWithOptions(prompt, CompletionOptions{})
+	if err != nil {
+		return "", err
+	}
+	return completion.Content, nil
+}
+
+// ExtractProfileWithOptions извлекает профиль с переопределенными параметрами запроса
+func (c *OpenAIClient) ExtractProfileWithOptions(prompt string, opts CompletionOptions) (*Completion, error) {
+	model := c.model
+	if opts.Model != "" {
+		model = opts.Model
+	}
+	temperature := c.temperature
+	if opts.Temperature != nil {
+		temperature = *opts.Temperature
+	}
+	maxTokens := c.maxTokens
+	if opts.MaxTokens > 0 {
+		maxTokens = opts.MaxTokens
+	}
+
+	if c.completer != nil {
+		content, err := c.completer.Complete(PromptUseCase(prompt), prompt)
+		if err != nil {
+			return nil, apperr.Wrap(apperr.ErrLLMUnavailable, err)
+		}
+		return &Completion{Content: cleanJSONResponse(content), Model: ModelCompleter}, nil
+	}
+
+	// В режиме симуляции возвращаем фикстуру профиля
+	if isMockProvider(c.provider) {
+		c.logger.Info("Mock provider: returning fixture profile", "prompt_length", len(prompt))
+		return &Completion{Content: mockResponse(prompt), Model: model}, nil
+	}
+
+	if opts.NoCache {
+		return c.createCompletionWithFallback(model, prompt, temperature, maxTokens, opts.Priority)
+	}
+	// Одинаковые запросы обслуживаем из кэша, параллельные дубликаты ждут первый запрос
+	key := responseCacheKey(model, temperature, maxTokens, prompt)
+	completion, cached, err := c.cache.do(key, func() (*Completion, error) {
+		return c.createCompletionWithFallback(model, prompt, temperature, maxTokens, opts.Priority)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if cached {
+		c.logger.Info("Serving profile extraction from cache", "model", model, "prompt_length", len(prompt))
+	}
+	return completion, nil
+}
+
+// createCompletionWithFallback перебирает цепочку моделей, пока ошибка связана с моделью
+func (c *OpenAIClient) createCompletionWithFallback(model, prompt string, temperature float64, maxTokens int, priority Priority) (*Completion, error) {
+	var lastErr error
+	for _, candidate := range ModelChain(model, c.fallbacks) {
+		completion, err := c.createCompletion(candidate, prompt, temperature, maxTokens, priority)
+		if err == nil {
+			if candidate != model {
+				completion.RequestedModel = model
+				c.logger.Warn("Profile extracted by fallback model", "requested", model, "model", candidate)
+			}
+			return completion, nil
+		}
+
+		var statusErr *StatusError
+		if !errors.As(err, &statusErr) || !IsModelError(statusErr.StatusCode, statusErr.Body) {
+			return nil, err
+		}
+		c.logger.Warn("Model unavailable, trying next in chain", "model", candidate, "status", statusErr.StatusCode)
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// createCompletion выполняет запрос к OpenAI Chat Completions
+func (c *OpenAIClient) createCompletion(model, prompt string, temperature float64, maxTokens int, priority Priority) (*Completion, error) {
+	if c.scheduler != nil {
+		waited, err := c.scheduler.Wait(context.Background(), priority, EstimateTokens(prompt)+maxTokens)
+		if err != nil {
+			c.logger.Error("OpenAI request not scheduled", "priority", priority, "waited", waited, "error", err)
+			return nil, err
+		}
+		if waited >= time.Second {
+			c.logger.Info("OpenAI request waited for rate limit", "priority", priority, "waited", waited)
+		}
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
 	defer cancel()
 
 	reqBody := OpenAIRequest{
-		Model: c.model,
+		Model: model,
 		Messages: []Message{
 			{
 				Role:    "user",
 				Content: prompt,
 			},
 		},
-		Temperature: c.temperature,
-		MaxTokens:   c.maxTokens,
+		Temperature:    temperature,
+		MaxTokens:      maxTokens,
+		PromptCacheKey: c.cacheKey,
+	}
+	if c.endpoint.IsAzure() {
+		// Azure OpenAI не принимает prompt_cache_key
+		reqBody.PromptCacheKey = ""
 	}
 
 	jsonBody, err := json.Marshal(reqBody)
 	if err != nil {
 		c.logger.Error("Failed to marshal request", "error", err)
-		return "", fmt.Errorf("error marshaling request: %w", err)
+		return nil, fmt.Errorf("error marshaling request: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", "https://api.openai.com/v1/chat/completions", bytes.NewBuffer(jsonBody))
+	req, err := http.NewRequestWithContext(ctx, "POST", c.endpoint.URL("chat/completions", model), bytes.NewBuffer(jsonBody))
 	if err != nil {
 		c.logger.Error("Failed to create request", "error", err)
-		return "", fmt.Errorf("error creating request: %w", err)
+		return nil, fmt.Errorf("error creating request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	c.endpoint.Authorize(req, c.apiKey)
 
 	resp, err := c.client.Do(req)
 	if err != nil {
 		c.logger.Error("Failed to make request", "error", err)
-		return "", fmt.Errorf("error making request: %w", err)
+		return nil, apperr.Wrap(apperr.ErrLLMUnavailable, fmt.Errorf("error making request: %w", err))
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		c.logger.Error("Failed to read response", "error", err)
-		return "", fmt.Errorf("error reading response: %w", err)
+		return nil, apperr.Wrap(apperr.ErrLLMUnavailable, fmt.Errorf("error reading response: %w", err))
 	}
 
 	if resp.StatusCode != http.StatusOK {
 		c.logger.Error("OpenAI API error", "status", resp.StatusCode, "body", string(body))
-		return "", fmt.Errorf("OpenAI API error: status %d, body: %s", resp.StatusCode, string(body))
+		return nil, &StatusError{StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
 	var openAIResp OpenAIResponse
 	if err := json.Unmarshal(body, &openAIResp); err != nil {
 		c.logger.Error("Failed to unmarshal response", "error", err)
-		return "", fmt.Errorf("error unmarshaling response: %w", err)
+		return nil, fmt.Errorf("error unmarshaling response: %w", err)
 	}
 
 	if openAIResp.Error != nil {
 		c.logger.Error("OpenAI API returned error", "error", openAIResp.Error.Message)
-		return "", fmt.Errorf("OpenAI API error: %s", openAIResp.Error.Message)
+		return nil, fmt.Errorf("OpenAI API error: %s", openAIResp.Error.Message)
 	}
 
 	if len(openAIResp.Choices) == 0 {
 		c.logger.Error("No choices returned from OpenAI API")
-		return "", fmt.Errorf("no choices returned from OpenAI API")
+		return nil, fmt.Errorf("no choices returned from OpenAI API")
 	}
 
 	content := openAIResp.Choices[0].Message.Content
@@ -173,11 +322,20 @@ func (c *OpenAIClient) ExtractProfile(prompt string) (string, error) {
 		c.logger.Info("Token usage",
 			"prompt_tokens", openAIResp.Usage.PromptTokens,
 			"completion_tokens", openAIResp.Usage.CompletionTokens,
-			"total_tokens", openAIResp.Usage.TotalTokens)
+			"total_tokens", openAIResp.Usage.TotalTokens,
+			"cached_tokens", openAIResp.Usage.CachedTokens())
+	}
+
+	if c.usage != nil {
+		c.usage.RecordUsage(model, openAIResp.Usage)
 	}
 
-	c.logger.Info("Successfully extracted profile", "content_length", len(content))
-	return content, nil
+	c.logger.Info("Successfully extracted profile", "model", model, "content_length", len(content))
+	return &Completion{
+		Content: content,
+		Model:   model,
+		Usage:   openAIResp.Usage,
+	}, nil
 }
 
 // cleanJSONResponse удаляет markdown форматирование из ответа
@@ -218,6 +376,15 @@ func getEnvAsFloatOrDefault(key string, defaultValue float64) float64 {
 	return defaultValue
 }
 
+func getEnvAsDurationOrDefault(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if duration, err := time.ParseDuration(value); err == nil {
+			return duration
+		}
+	}
+	return defaultValue
+}
+
 func parseIntSafe(s string) (int, error) {
 	// Простая реализация без импорта strconv для избежания конфликтов
 	var result int
@@ -0,0 +1,59 @@
+package api
+
+import "strings"
+
+// modelPrice - стоимость в долларах за 1M токенов
+type modelPrice struct {
+	Input       float64
+	CachedInput float64 // 0 - модель не поддерживает кэширование промпта
+	Output      float64
+}
+
+// modelPrices - публичные цены OpenAI для оценки стоимости запросов
+var modelPrices = map[string]modelPrice{
+	"gpt-4.1":       {Input: 2.00, CachedInput: 0.50, Output: 8.00},
+	"gpt-4.1-mini":  {Input: 0.40, CachedInput: 0.10, Output: 1.60},
+	"gpt-4.1-nano":  {Input: 0.10, CachedInput: 0.025, Output: 0.40},
+	"gpt-4o":        {Input: 2.50, CachedInput: 1.25, Output: 10.00},
+	"gpt-4o-mini":   {Input: 0.15, CachedInput: 0.075, Output: 0.60},
+	"gpt-4":         {Input: 30.00, Output: 60.00},
+	"gpt-3.5-turbo": {Input: 0.50, Output: 1.50},
+}
+
+// EstimateCost оценивает стоимость запроса в долларах; для неизвестных моделей возвращает 0
+func EstimateCost(model string, usage Usage) float64 {
+	price, ok := lookupModel(modelPrices, model)
+	if !ok {
+		return 0
+	}
+
+	cachedPrice := price.CachedInput
+	if cachedPrice == 0 {
+		cachedPrice = price.Input
+	}
+	cached := usage.CachedTokens()
+	input := float64(usage.PromptTokens-cached)*price.Input + float64(cached)*cachedPrice
+
+	return (input + float64(usage.CompletionTokens)*price.Output) / 1_000_000
+}
+
+// lookupModel ищет значение для модели; модели с датой в имени (gpt-4o-2024-08-06)
+// ищутся по самому длинному префиксу
+func lookupModel[T any](table map[string]T, model string) (T, bool) {
+	if value, ok := table[model]; ok {
+		return value, true
+	}
+	var value T
+	bestLen := 0
+	for name, v := range table {
+		if strings.HasPrefix(model, name) && len(name) > bestLen {
+			value, bestLen = v, len(name)
+		}
+	}
+	return value, bestLen > 0
+}
+
+// EstimateTokens грубо оценивает число токенов в тексте (≈4 байта UTF-8 на токен)
+func EstimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
@@ -0,0 +1,215 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"interview-bot-complete/internal/apperr"
+	"sync"
+	"time"
+)
+
+// Priority - класс приоритета запроса к OpenAI. Нулевое значение - фоновые запросы.
+type Priority int
+
+const (
+	PriorityBatch       Priority = iota // извлечение профиля, матчинг, рубрики
+	PrioritySummary                     // саммари блоков и проверка раскрытия целей
+	PriorityInteractive                 // вопросы интервьюера и ответы, которых ждет пользователь
+
+	priorityCount = iota
+)
+
+// String возвращает название класса для логов
+func (p Priority) String() string {
+	switch p {
+	case PriorityInteractive:
+		return "interactive"
+	case PrioritySummary:
+		return "summary"
+	}
+	return "batch"
+}
+
+// priorityShare - доля лимитов, доступная классу: остаток окна придерживается
+// для более приоритетных запросов, чтобы всплеск извлечений не задерживал вопросы
+var priorityShare = [priorityCount]float64{
+	PriorityBatch:       0.7,
+	PrioritySummary:     0.9,
+	PriorityInteractive: 1,
+}
+
+// schedulerWindow - окно лимитов OpenAI (RPM/TPM)
+const schedulerWindow = time.Minute
+
+// ErrSchedulerTimeout - запрос не дождался свободного места в лимитах OpenAI
+var ErrSchedulerTimeout = apperr.Wrap(apperr.ErrRateLimited, errors.New("timed out waiting for OpenAI rate limit"))
+
+// SchedulerConfig задает лимиты аккаунта OpenAI. Нулевые значения отключают соответствующий лимит.
+type SchedulerConfig struct {
+	RequestsPerMinute int
+	TokensPerMinute   int
+	MaxWait           time.Duration // максимальное ожидание в очереди (0 - до отмены контекста)
+}
+
+// Scheduler распределяет запросы к OpenAI всех сервисов процесса в пределах общих RPM/TPM.
+// Запросы более высокого класса проходят первыми, внутри класса - в порядке очереди.
+type Scheduler struct {
+	config SchedulerConfig
+
+	mutex   sync.Mutex
+	window  []grant                 // запросы, пропущенные за последнюю минуту, от старых к новым
+	queues  [priorityCount][]uint64 // номера ожидающих запросов по классам
+	ticket  uint64
+	changed chan struct{} // закрывается при изменении окна или очередей
+	nowFunc func() time.Time
+}
+
+type grant struct {
+	at     time.Time
+	tokens int
+}
+
+// NewScheduler создает планировщик с лимитами config
+func NewScheduler(config SchedulerConfig) *Scheduler {
+	return &Scheduler{
+		config:  config,
+		changed: make(chan struct{}),
+		nowFunc: time.Now,
+	}
+}
+
+// Wait ждет, пока запрос класса priority на tokens токенов (промпт плюс max_tokens, как считает
+// OpenAI) уложится в лимиты, и учитывает его.
+// Возвращает время ожидания в очереди.
+func (s *Scheduler) Wait(ctx context.Context, priority Priority, tokens int) (time.Duration, error) {
+	if priority < 0 || priority >= priorityCount {
+		priority = PriorityBatch
+	}
+	if s.config.MaxWait > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.config.MaxWait)
+		defer cancel()
+	}
+
+	s.mutex.Lock()
+	started := s.nowFunc()
+	s.ticket++
+	w := s.ticket
+	s.queues[priority] = append(s.queues[priority], w)
+	for {
+		now := s.nowFunc()
+		s.expire(now)
+		delay := time.Duration(-1) // не наша очередь: ждем изменения очередей
+		if s.turn(priority, w) {
+			delay = s.delay(priority, tokens, now)
+			if delay == 0 {
+				s.window = append(s.window, grant{at: now, tokens: tokens})
+				s.remove(priority, w)
+				s.mutex.Unlock()
+				return now.Sub(started), nil
+			}
+		}
+		changed := s.changed
+		s.mutex.Unlock()
+
+		var timer *time.Timer
+		var timeout <-chan time.Time
+		if delay > 0 {
+			timer = time.NewTimer(delay)
+			timeout = timer.C
+		}
+		select {
+		case <-changed:
+		case <-timeout:
+		case <-ctx.Done():
+			if timer != nil {
+				timer.Stop()
+			}
+			s.mutex.Lock()
+			s.remove(priority, w)
+			waited := s.nowFunc().Sub(started)
+			s.mutex.Unlock()
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return waited, ErrSchedulerTimeout
+			}
+			return waited, ctx.Err()
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		s.mutex.Lock()
+	}
+}
+
+// turn сообщает, что w - первый в своей очереди и более приоритетных запросов не ждет
+func (s *Scheduler) turn(priority Priority, w uint64) bool {
+	for higher := priority + 1; higher < priorityCount; higher++ {
+		if len(s.queues[higher]) > 0 {
+			return false
+		}
+	}
+	return s.queues[priority][0] == w
+}
+
+// delay возвращает время, через которое запрос уложится в долю лимитов своего класса (0 - сейчас)
+func (s *Scheduler) delay(priority Priority, tokens int, now time.Time) time.Duration {
+	var delay time.Duration
+	if limit := classLimit(s.config.RequestsPerMinute, priority); limit > 0 && len(s.window) >= limit {
+		// Должны выйти из окна самые старые запросы, чтобы осталось limit-1
+		delay = max(delay, s.window[len(s.window)-limit].at.Add(schedulerWindow).Sub(now))
+	}
+	if limit := classLimit(s.config.TokensPerMinute, priority); limit > 0 {
+		// Запрос больше лимита целиком ждет пустого окна
+		tokens = min(tokens, limit)
+		used := 0
+		for _, g := range s.window {
+			used += g.tokens
+		}
+		for _, g := range s.window {
+			if used+tokens <= limit {
+				break
+			}
+			used -= g.tokens
+			delay = max(delay, g.at.Add(schedulerWindow).Sub(now))
+		}
+	}
+	return delay
+}
+
+// classLimit возвращает долю лимита, доступную классу (0 - без ограничения)
+func classLimit(limit int, priority Priority) int {
+	if limit <= 0 {
+		return 0
+	}
+	return max(1, int(float64(limit)*priorityShare[priority]))
+}
+
+// expire убирает из окна запросы старше минуты
+func (s *Scheduler) expire(now time.Time) {
+	n := 0
+	for n < len(s.window) && !s.window[n].at.Add(schedulerWindow).After(now) {
+		n++
+	}
+	if n > 0 {
+		s.window = append(s.window[:0], s.window[n:]...)
+		s.notify()
+	}
+}
+
+// remove убирает w из очереди и будит остальных ожидающих
+func (s *Scheduler) remove(priority Priority, w uint64) {
+	queue := s.queues[priority]
+	for i, queued := range queue {
+		if queued == w {
+			s.queues[priority] = append(queue[:i], queue[i+1:]...)
+			break
+		}
+	}
+	s.notify()
+}
+
+// notify будит всех ожидающих, чтобы они перепроверили свою очередь
+func (s *Scheduler) notify() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"interview-bot-complete/internal/httpclient"
+)
+
+// proxyEnv - прокси запросов к OpenAI; перекрывает HTTPS_PROXY
+const proxyEnv = "OPENAI_PROXY"
+
+var shared struct {
+	once      sync.Once
+	transport *http.Transport
+	err       error
+}
+
+// HTTPTransport возвращает общий транспорт клиентов OpenAI: прокси из OPENAI_PROXY (иначе HTTPS_PROXY
+// с учетом NO_PROXY) и корневые сертификаты из HTTP_CA_CERT_FILE. Настройки читаются один раз.
+func HTTPTransport() (*http.Transport, error) {
+	shared.once.Do(func() {
+		shared.transport, shared.err = httpclient.NewTransport(httpclient.OptionsFromEnv(proxyEnv))
+		if shared.err == nil {
+			// Все клиенты OpenAI ходят на один хост - держим соединения для параллельных извлечений
+			shared.transport.MaxConnsPerHost = 100
+			shared.transport.MaxIdleConnsPerHost = 100
+		}
+	})
+	return shared.transport, shared.err
+}
+
+// NewHTTPClient создает клиент OpenAI на общем транспорте (timeout 0 - без ограничения).
+// При ошибке настройки транспорта запросы клиента завершаются ею, а не идут в обход прокси.
+func NewHTTPClient(timeout time.Duration) *http.Client {
+	transport, err := HTTPTransport()
+	if err != nil {
+		return &http.Client{Timeout: timeout, Transport: failingTransport{err: err}}
+	}
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
+
+// failingTransport отклоняет запросы ошибкой настройки транспорта
+type failingTransport struct {
+	err error
+}
+
+func (t failingTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, t.err
+}
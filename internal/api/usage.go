@@ -0,0 +1,14 @@
+package api
+
+// UsageRecorder учитывает токены, израсходованные запросом к OpenAI (например, в дневном бюджете)
+type UsageRecorder interface {
+	RecordUsage(model string, usage Usage)
+}
+
+// UsageRecorderFunc позволяет использовать функцию как UsageRecorder
+type UsageRecorderFunc func(model string, usage Usage)
+
+// RecordUsage вызывает f(model, usage)
+func (f UsageRecorderFunc) RecordUsage(model string, usage Usage) {
+	f(model, usage)
+}
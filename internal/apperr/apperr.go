@@ -0,0 +1,123 @@
+package apperr
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"interview-bot-complete/internal/language"
+	"strings"
+)
+
+// Категории ошибок, о которых сообщается пользователю. Вместо текста исходной ошибки (тела ответа API,
+// путей файлов) пользователь получает сообщение категории на языке интервью и код для поддержки,
+// по которому ошибка находится в логах. errors.Is находит категорию в ошибке, отмеченной Wrap.
+var (
+	ErrLLMUnavailable = errors.New("сервис LLM недоступен")
+	ErrInvalidJSON    = errors.New("ответ модели не является корректным JSON")
+	ErrStorage        = errors.New("ошибка хранилища")
+	ErrRateLimited    = errors.New("превышен лимит запросов")
+)
+
+// kinds - категории в порядке проверки: лимит запросов - частный случай недоступности LLM
+var kinds = []error{ErrRateLimited, ErrLLMUnavailable, ErrInvalidJSON, ErrStorage}
+
+// codes - префиксы кодов ошибок по категории
+var codes = map[error]string{
+	ErrLLMUnavailable: "LLM",
+	ErrInvalidJSON:    "JSON",
+	ErrStorage:        "STORAGE",
+	ErrRateLimited:    "RATE",
+}
+
+// internalCode - префикс кода ошибки без категории
+const internalCode = "INTERNAL"
+
+// messages - сообщения пользователю по языку и категории; nil - ошибка без категории
+var messages = map[string]map[error]string{
+	language.Russian: {
+		ErrLLMUnavailable: "Сервис анализа временно недоступен.",
+		ErrInvalidJSON:    "Не удалось разобрать ответ сервиса анализа.",
+		ErrStorage:        "Не удалось сохранить или прочитать данные.",
+		ErrRateLimited:    "Сервис анализа перегружен, попробуйте чуть позже.",
+		nil:               "Произошла внутренняя ошибка.",
+	},
+	language.English: {
+		ErrLLMUnavailable: "The analysis service is temporarily unavailable.",
+		ErrInvalidJSON:    "The analysis service returned a response we couldn't process.",
+		ErrStorage:        "We couldn't save or read your data.",
+		ErrRateLimited:    "The analysis service is busy, please try again a little later.",
+		nil:               "Something went wrong on our side.",
+	},
+}
+
+// codeLabels - подпись кода ошибки по языку: %s - код
+var codeLabels = map[string]string{
+	language.Russian: "Код ошибки для поддержки: `%s`",
+	language.English: "Error code for support: `%s`",
+}
+
+// Error - ошибка, отнесенная к категории. Текст - текст исходной ошибки,
+// errors.Is и errors.As находят и категорию, и исходную ошибку.
+type Error struct {
+	Kind error
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+// Wrap относит err к категории kind; nil остается nil
+func Wrap(kind, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Kind: kind, Err: err}
+}
+
+// KindOf возвращает категорию ошибки; nil - категория не известна (внутренняя ошибка)
+func KindOf(err error) error {
+	for _, kind := range kinds {
+		if errors.Is(err, kind) {
+			return kind
+		}
+	}
+	return nil
+}
+
+// Code возвращает новый код ошибки для поддержки: категория и случайная ссылка (LLM-7F3A9C).
+// Код пишется в лог вместе с ошибкой, чтобы по нему найти подробности.
+func Code(err error) string {
+	prefix, ok := codes[KindOf(err)]
+	if !ok {
+		prefix = internalCode
+	}
+	ref := make([]byte, 3)
+	if _, err := rand.Read(ref); err != nil {
+		return prefix
+	}
+	return prefix + "-" + strings.ToUpper(hex.EncodeToString(ref))
+}
+
+// Message возвращает сообщение пользователю об ошибке на языке lang, без подробностей ошибки
+func Message(err error, lang string) string {
+	localized, ok := messages[lang]
+	if !ok {
+		localized = messages[language.Default]
+	}
+	return localized[KindOf(err)]
+}
+
+// UserMessage возвращает сообщение пользователю с кодом ошибки (Markdown)
+func UserMessage(err error, lang, code string) string {
+	label, ok := codeLabels[lang]
+	if !ok {
+		label = codeLabels[language.Default]
+	}
+	return Message(err, lang) + "\n" + fmt.Sprintf(label, code)
+}
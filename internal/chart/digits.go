@@ -0,0 +1,50 @@
+package chart
+
+import (
+	"image"
+	"image/color"
+	"strconv"
+)
+
+// Растровые цифры 3×5 для номеров осей: строка - три бита слева направо
+const (
+	digitWidth  = 3
+	digitHeight = 5
+)
+
+var digitRows = [10][digitHeight]uint8{
+	{0b111, 0b101, 0b101, 0b101, 0b111},
+	{0b010, 0b110, 0b010, 0b010, 0b111},
+	{0b111, 0b001, 0b111, 0b100, 0b111},
+	{0b111, 0b001, 0b111, 0b001, 0b111},
+	{0b101, 0b101, 0b111, 0b001, 0b001},
+	{0b111, 0b100, 0b111, 0b001, 0b111},
+	{0b111, 0b100, 0b111, 0b101, 0b111},
+	{0b111, 0b001, 0b010, 0b010, 0b010},
+	{0b111, 0b101, 0b111, 0b101, 0b111},
+	{0b111, 0b101, 0b111, 0b001, 0b111},
+}
+
+// drawNumber рисует число с центром в center; pixel - размер точки цифры
+func drawNumber(img *image.RGBA, number int, center point, pixel int, c color.RGBA) {
+	text := strconv.Itoa(number)
+	width := (len(text)*(digitWidth+1) - 1) * pixel
+	left := int(center.x) - width/2
+	top := int(center.y) - digitHeight*pixel/2
+	for i, ch := range text {
+		rows := digitRows[ch-'0']
+		x0 := left + i*(digitWidth+1)*pixel
+		for row, bits := range rows {
+			for col := 0; col < digitWidth; col++ {
+				if bits&(1<<(digitWidth-1-col)) == 0 {
+					continue
+				}
+				for dy := 0; dy < pixel; dy++ {
+					for dx := 0; dx < pixel; dx++ {
+						img.SetRGBA(x0+col*pixel+dx, top+row*pixel+dy, c)
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package chart
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"sort"
+)
+
+// MinAxes - наименьшее число осей, при котором у радара есть площадь
+const MinAxes = 3
+
+// gridLevels - число концентрических уровней сетки
+const gridLevels = 4
+
+var (
+	background = color.RGBA{255, 255, 255, 255}
+	gridColor  = color.RGBA{210, 214, 220, 255}
+	spokeColor = color.RGBA{170, 176, 186, 255}
+	fillColor  = color.RGBA{66, 133, 244, 90}
+	lineColor  = color.RGBA{25, 90, 200, 255}
+	labelColor = color.RGBA{60, 64, 72, 255}
+)
+
+// Axis - ось радара; Value - доля от 0 до 1 (значения вне диапазона обрезаются)
+type Axis struct {
+	Label string
+	Value float64
+}
+
+// Radar рисует радарную диаграмму size×size в PNG. Оси подписаны номерами 1..N
+// по порядку axes, названия осей выводятся в подписи к изображению.
+func Radar(axes []Axis, size int) ([]byte, error) {
+	if len(axes) < MinAxes {
+		return nil, fmt.Errorf("для диаграммы нужно минимум %d оси, получено %d", MinAxes, len(axes))
+	}
+	if size < 100 {
+		return nil, errors.New("слишком маленький размер диаграммы")
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: background}, image.Point{}, draw.Src)
+
+	center := float64(size) / 2
+	radius := center * 0.8
+	// scale - толщина линий и размер точки цифр, растущие с размером изображения
+	scale := size / 200
+	if scale < 1 {
+		scale = 1
+	}
+
+	// Сетка и спицы
+	for level := 1; level <= gridLevels; level++ {
+		polygon := vertices(len(axes), center, radius*float64(level)/gridLevels, nil)
+		strokePolygon(img, polygon, gridColor, 1)
+	}
+	outer := vertices(len(axes), center, radius, nil)
+	for _, p := range outer {
+		line(img, point{center, center}, p, spokeColor, 1)
+	}
+
+	// Значения
+	values := make([]float64, len(axes))
+	for i, axis := range axes {
+		values[i] = clamp(axis.Value)
+	}
+	shape := vertices(len(axes), center, radius, values)
+	fillPolygon(img, shape, fillColor)
+	strokePolygon(img, shape, lineColor, scale)
+	for _, p := range shape {
+		dot(img, p, float64(scale)*3, lineColor)
+	}
+
+	// Номера осей за внешним кругом
+	labels := vertices(len(axes), center, radius+float64(digitHeight*scale*2), nil)
+	for i, p := range labels {
+		drawNumber(img, i+1, p, scale*2, labelColor)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("ошибка кодирования PNG: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+type point struct{ x, y float64 }
+
+// vertices возвращает вершины многоугольника с n осями, первая ось направлена вверх;
+// values задают долю радиуса для каждой вершины (nil - все вершины на радиусе)
+func vertices(n int, center, radius float64, values []float64) []point {
+	points := make([]point, n)
+	for i := range points {
+		r := radius
+		if values != nil {
+			r *= values[i]
+		}
+		angle := -math.Pi/2 + 2*math.Pi*float64(i)/float64(n)
+		points[i] = point{center + r*math.Cos(angle), center + r*math.Sin(angle)}
+	}
+	return points
+}
+
+func clamp(value float64) float64 {
+	if math.IsNaN(value) || value < 0 {
+		return 0
+	}
+	if value > 1 {
+		return 1
+	}
+	return value
+}
+
+// blend накладывает цвет c с его альфа-каналом на пиксель (x, y)
+func blend(img *image.RGBA, x, y int, c color.RGBA) {
+	if !(image.Point{x, y}.In(img.Rect)) {
+		return
+	}
+	offset := img.PixOffset(x, y)
+	alpha := uint32(c.A)
+	for i, v := range []uint8{c.R, c.G, c.B} {
+		dst := uint32(img.Pix[offset+i])
+		img.Pix[offset+i] = uint8((uint32(v)*alpha + dst*(255-alpha)) / 255)
+	}
+	img.Pix[offset+3] = 255
+}
+
+// line рисует отрезок толщиной width
+func line(img *image.RGBA, from, to point, c color.RGBA, width int) {
+	steps := int(math.Max(math.Abs(to.x-from.x), math.Abs(to.y-from.y))) + 1
+	half := width / 2
+	for i := 0; i <= steps; i++ {
+		t := float64(i) / float64(steps)
+		x := int(math.Round(from.x + (to.x-from.x)*t))
+		y := int(math.Round(from.y + (to.y-from.y)*t))
+		for dx := -half; dx <= half; dx++ {
+			for dy := -half; dy <= half; dy++ {
+				if c.A == 255 {
+					img.SetRGBA(x+dx, y+dy, c)
+				} else {
+					blend(img, x+dx, y+dy, c)
+				}
+			}
+		}
+	}
+}
+
+func strokePolygon(img *image.RGBA, polygon []point, c color.RGBA, width int) {
+	for i := range polygon {
+		line(img, polygon[i], polygon[(i+1)%len(polygon)], c, width)
+	}
+}
+
+// fillPolygon заливает многоугольник построчно (правило четности пересечений)
+func fillPolygon(img *image.RGBA, polygon []point, c color.RGBA) {
+	minY, maxY := polygon[0].y, polygon[0].y
+	for _, p := range polygon {
+		minY, maxY = math.Min(minY, p.y), math.Max(maxY, p.y)
+	}
+	for y := int(math.Ceil(minY)); y <= int(math.Floor(maxY)); y++ {
+		scan := float64(y)
+		var xs []float64
+		for i := range polygon {
+			a, b := polygon[i], polygon[(i+1)%len(polygon)]
+			if (a.y <= scan && b.y > scan) || (b.y <= scan && a.y > scan) {
+				xs = append(xs, a.x+(scan-a.y)*(b.x-a.x)/(b.y-a.y))
+			}
+		}
+		sort.Float64s(xs)
+		for i := 0; i+1 < len(xs); i += 2 {
+			for x := int(math.Ceil(xs[i])); x <= int(math.Floor(xs[i+1])); x++ {
+				blend(img, x, y, c)
+			}
+		}
+	}
+}
+
+// dot рисует закрашенный круг радиуса r
+func dot(img *image.RGBA, center point, r float64, c color.RGBA) {
+	for y := int(center.y - r); y <= int(center.y+r); y++ {
+		for x := int(center.x - r); x <= int(center.x+r); x++ {
+			if math.Hypot(float64(x)-center.x, float64(y)-center.y) <= r {
+				img.SetRGBA(x, y, c)
+			}
+		}
+	}
+}
@@ -0,0 +1,311 @@
+package condition
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Env предоставляет данные, на которых вычисляются условия блоков
+type Env interface {
+	// Text возвращает текст источника ("summary" или "answers")
+	Text(source string) string
+	// Flag сообщает, выставлен ли флаг с указанным именем
+	Flag(name string) bool
+}
+
+// Expr представляет разобранное условие
+type Expr interface {
+	Eval(env Env) bool
+}
+
+// Sources перечисляет допустимые источники текста для оператора contains
+var Sources = []string{"summary", "answers"}
+
+// Parse разбирает выражение условия.
+//
+// Поддерживаемый синтаксис:
+//
+//	summary contains "студент"
+//	answers contains "работаю"
+//	flag is_student
+//	not <expr>, <expr> and <expr>, <expr> or <expr>, ( <expr> )
+func Parse(input string) (Expr, error) {
+	tokens, err := tokenize(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.done() {
+		return nil, fmt.Errorf("неожиданный токен %q", p.peek().value)
+	}
+	return expr, nil
+}
+
+// Flags возвращает имена всех флагов, на которые ссылается выражение
+func Flags(expr Expr) []string {
+	var names []string
+	var walk func(e Expr)
+	walk = func(e Expr) {
+		switch v := e.(type) {
+		case flagExpr:
+			names = append(names, v.name)
+		case notExpr:
+			walk(v.inner)
+		case binaryExpr:
+			walk(v.left)
+			walk(v.right)
+		}
+	}
+	walk(expr)
+	return names
+}
+
+// SourcesOf возвращает источники текста, на которые ссылается выражение
+func SourcesOf(expr Expr) []string {
+	var sources []string
+	var walk func(e Expr)
+	walk = func(e Expr) {
+		switch v := e.(type) {
+		case containsExpr:
+			sources = append(sources, v.source)
+		case notExpr:
+			walk(v.inner)
+		case binaryExpr:
+			walk(v.left)
+			walk(v.right)
+		}
+	}
+	walk(expr)
+	return sources
+}
+
+type containsExpr struct {
+	source string
+	needle string
+}
+
+func (e containsExpr) Eval(env Env) bool {
+	return strings.Contains(strings.ToLower(env.Text(e.source)), e.needle)
+}
+
+type flagExpr struct {
+	name string
+}
+
+func (e flagExpr) Eval(env Env) bool {
+	return env.Flag(e.name)
+}
+
+type notExpr struct {
+	inner Expr
+}
+
+func (e notExpr) Eval(env Env) bool {
+	return !e.inner.Eval(env)
+}
+
+type binaryExpr struct {
+	op          string
+	left, right Expr
+}
+
+func (e binaryExpr) Eval(env Env) bool {
+	if e.op == "and" {
+		return e.left.Eval(env) && e.right.Eval(env)
+	}
+	return e.left.Eval(env) || e.right.Eval(env)
+}
+
+type tokenKind int
+
+const (
+	tokenWord tokenKind = iota
+	tokenString
+	tokenLParen
+	tokenRParen
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+}
+
+func tokenize(input string) ([]token, error) {
+	var tokens []token
+	runes := []rune(input)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLParen, value: "("})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRParen, value: ")"})
+			i++
+		case r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			if end >= len(runes) {
+				return nil, fmt.Errorf("незакрытая строка в условии")
+			}
+			tokens = append(tokens, token{kind: tokenString, value: string(runes[i+1 : end])})
+			i = end + 1
+		case r == '!':
+			tokens = append(tokens, token{kind: tokenWord, value: "not"})
+			i++
+		case r == '&' || r == '|':
+			if i+1 >= len(runes) || runes[i+1] != r {
+				return nil, fmt.Errorf("неизвестный оператор %q", string(r))
+			}
+			op := "and"
+			if r == '|' {
+				op = "or"
+			}
+			tokens = append(tokens, token{kind: tokenWord, value: op})
+			i += 2
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_':
+			end := i
+			for end < len(runes) && (unicode.IsLetter(runes[end]) || unicode.IsDigit(runes[end]) || runes[end] == '_') {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokenWord, value: string(runes[i:end])})
+			i = end
+		default:
+			return nil, fmt.Errorf("недопустимый символ %q в условии", string(r))
+		}
+	}
+
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("пустое условие")
+	}
+	return tokens, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *parser) peek() token {
+	if p.done() {
+		return token{}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() (token, error) {
+	if p.done() {
+		return token{}, fmt.Errorf("неожиданный конец условия")
+	}
+	t := p.tokens[p.pos]
+	p.pos++
+	return t, nil
+}
+
+func (p *parser) isWord(value string) bool {
+	t := p.peek()
+	return !p.done() && t.kind == tokenWord && t.value == value
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isWord("or") {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryExpr{op: "or", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isWord("and") {
+		p.pos++
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryExpr{op: "and", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	if p.isWord("not") {
+		p.pos++
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{inner: inner}, nil
+	}
+
+	t, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case t.kind == tokenLParen:
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		closing, err := p.next()
+		if err != nil || closing.kind != tokenRParen {
+			return nil, fmt.Errorf("ожидалась закрывающая скобка")
+		}
+		return expr, nil
+	case t.kind == tokenWord && t.value == "flag":
+		name, err := p.next()
+		if err != nil || name.kind != tokenWord {
+			return nil, fmt.Errorf("после flag ожидалось имя флага")
+		}
+		return flagExpr{name: name.value}, nil
+	case t.kind == tokenWord && isSource(t.value):
+		if !p.isWord("contains") {
+			return nil, fmt.Errorf("после %s ожидался оператор contains", t.value)
+		}
+		p.pos++
+		needle, err := p.next()
+		if err != nil || needle.kind != tokenString {
+			return nil, fmt.Errorf("после contains ожидалась строка в кавычках")
+		}
+		return containsExpr{source: t.value, needle: strings.ToLower(needle.value)}, nil
+	default:
+		return nil, fmt.Errorf("неожиданный токен %q", t.value)
+	}
+}
+
+func isSource(value string) bool {
+	for _, s := range Sources {
+		if s == value {
+			return true
+		}
+	}
+	return false
+}
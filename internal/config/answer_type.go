@@ -0,0 +1,29 @@
+package config
+
+import "fmt"
+
+// Ожидаемые типы ответа на открытый вопрос (answer_type)
+const (
+	AnswerText   = "text"   // свободный ответ (по умолчанию)
+	AnswerNumber = "number" // число: «25», «двадцать пять»
+	AnswerDate   = "date"   // дата: «15.03.2021», «март 2021», «2021»
+	AnswerList   = "list"   // перечисление через запятую, точку с запятой или с новой строки
+)
+
+// AnswerTypeFor возвращает ожидаемый тип ответа на вопрос question (с нуля) блока;
+// пусто - свободный ответ, закрытый или уточняющий вопрос модели
+func (b Block) AnswerTypeFor(question int) string {
+	if question < 0 || question >= len(b.Questions) || b.Questions[question].AnswerType == AnswerText {
+		return ""
+	}
+	return b.Questions[question].AnswerType
+}
+
+// validateAnswerType проверяет тип ответа вопроса
+func validateAnswerType(answerType string) error {
+	switch answerType {
+	case "", AnswerText, AnswerNumber, AnswerDate, AnswerList:
+		return nil
+	}
+	return fmt.Errorf("неизвестный answer_type %q (допустимы %s, %s, %s и %s)", answerType, AnswerText, AnswerNumber, AnswerDate, AnswerList)
+}
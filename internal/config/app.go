@@ -3,19 +3,219 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
 type AppConfig struct {
-	OpenAI   OpenAIConfig
-	Telegram TelegramConfig
-	Server   ServerConfig
+	OpenAI     OpenAIConfig
+	Telegram   TelegramConfig
+	Server     ServerConfig
+	RateLimit  RateLimitConfig
+	Interview  InterviewFilesConfig
+	Invites    InvitesConfig
+	ProfileQA  ProfileQAConfig
+	Storage    StorageConfig
+	Reporting  ReportingConfig
+	Consent    ConsentConfig
+	Redis      RedisConfig
+	Premium    PremiumConfig
+	Logging    LoggingConfig
+	Quotas     QuotaConfig
+	Sheets     SheetsConfig
+	Tenants    TenantsConfig
+	Retention  RetentionConfig
+	Outcome    OutcomeConfig
+	Moderation ModerationConfig
+	Research   ResearchConfig
+	Search     SearchConfig
+}
+
+// SearchConfig задает полнотекстовый поиск по архиву интервью (/search, /api/search)
+type SearchConfig struct {
+	Enabled bool
+	// RefreshInterval - как часто индекс перестраивается из хранилища; 0 - только при запуске
+	RefreshInterval time.Duration
+}
+
+// ResearchConfig задает обезличенную выгрузку данных для исследований (/researchexport)
+type ResearchConfig struct {
+	// PseudonymSalt - секрет стабильных псевдонимов; пусто - выгрузка недоступна
+	PseudonymSalt string
+}
+
+// Политики обработки отмеченных модерацией ответов при составлении профиля
+const (
+	ModerationExclude = "exclude" // ответы исключаются из анализа профиля
+	ModerationKeep    = "keep"    // ответы анализируются вместе с остальными
+)
+
+// ModerationConfig задает проверку ответов через OpenAI Moderation API
+type ModerationConfig struct {
+	Enabled bool
+	Model   string
+	// Categories - категории модерации, при которых пользователю отправляется CrisisMessage
+	Categories []string
+	// CrisisMessage - сообщение пользователю об отмеченном ответе (контакты службы поддержки)
+	CrisisMessage string
+	// NotifyAdmins - уведомлять администраторов об отмеченных ответах (без текста ответа)
+	NotifyAdmins bool
+	// ExtractionPolicy - ModerationExclude или ModerationKeep
+	ExtractionPolicy string
+}
+
+// OutcomeConfig задает оценку кандидатов по рубрикам ролей для рекрутеров;
+// оценка выполняется, если задан чат рекрутера (общий или арендатора)
+type OutcomeConfig struct {
+	RubricsDir      string // рубрики ролей (*.yaml)
+	Rubric          string // рубрика по умолчанию; пустая - только рубрики шаблонов
+	RecruiterChatID int64  // чат, куда отправляются отчеты; 0 - только чаты арендаторов
+}
+
+// RetentionConfig задает срок хранения ответов интервью; профили хранятся бессрочно
+type RetentionConfig struct {
+	Days       int    // через сколько дней после интервью ответы удаляются; 0 - бессрочно
+	Mode       string // archive - перенести в zip архив месяца, delete - удалить
+	ArchiveDir string // директория архивов ответов
+	RunAt      string // время ежедневного запуска, ЧЧ:ММ
+}
+
+// TenantsConfig задает арендаторов бота; отсутствующий файл - бот без арендаторов
+type TenantsConfig struct {
+	File string // список арендаторов (YAML)
+}
+
+// SheetsConfig задает выгрузку профилей в Google Sheets; пустой SpreadsheetID отключает выгрузку
+type SheetsConfig struct {
+	CredentialsFile string   // JSON ключ сервисного аккаунта с доступом к таблице
+	SpreadsheetID   string   // ID таблицы из ее URL
+	Sheet           string   // лист, в который дописываются строки
+	Columns         []string // поля профиля, выгружаемые после служебных колонок
+}
+
+// QuotaConfig задает дневные квоты на интервью и бюджет токенов OpenAI; 0 отключает ограничение
+type QuotaConfig struct {
+	InterviewsPerUserPerDay int // новых интервью одного пользователя за сутки
+	MaxActiveInterviews     int // одновременно идущих интервью на всех репликах
+	DailyTokenBudget        int // токенов OpenAI за сутки (UTC), после чего новые интервью откладываются
+}
+
+// LoggingConfig задает уровень и формат логов
+type LoggingConfig struct {
+	Level  string // debug, info, warn, error
+	Format string // text или json
+}
+
+// PremiumConfig задает платный расширенный анализ профиля (/premium)
+type PremiumConfig struct {
+	Enabled       bool
+	ProviderToken string // токен платежного провайдера; пустой - оплата в Telegram Stars
+	Currency      string
+	Price         int    // в минимальных единицах валюты (для XTR - в звездах)
+	PDFFont       string // TrueType шрифт для PDF отчета; пустой - отчет отправляется текстом
+	// ArchetypesDir - каталоги архетипов (*.yaml); ArchetypeCatalog - каталог по умолчанию
+	ArchetypesDir    string
+	ArchetypeCatalog string
+}
+
+// RedisConfig задает общее хранилище лимитов и сессий для нескольких реплик бота.
+// Пустой URL - все хранится в памяти процесса.
+type RedisConfig struct {
+	URL        string
+	KeyPrefix  string
+	SessionTTL time.Duration
+}
+
+// ConsentConfig задает запрос согласия на обработку данных перед интервью
+type ConsentConfig struct {
+	Required bool
+	// Version - версия текста согласия; при ее смене согласие запрашивается заново
+	Version string
+}
+
+// ReportingConfig задает отправку отчетов о паниках во внешнюю систему
+type ReportingConfig struct {
+	SentryDSN   string
+	Environment string
+}
+
+// StorageConfig задает директории и шаблоны имен файлов результатов и профилей.
+// Шаблоны поддерживают {{id}}, {{date}} и {{template}}.
+type StorageConfig struct {
+	ResultsDir          string
+	OutputDir           string
+	ResultFileTemplate  string
+	ProfileFileTemplate string
+	// ExportFormats - форматы для ATS (json_resume, hr_xml), в которых профиль сохраняется дополнительно
+	ExportFormats []string
+}
+
+// ProfileQAConfig задает квоту вопросов о профиле в режиме /ask
+type ProfileQAConfig struct {
+	QuestionsPerDay int
+}
+
+// InterviewFilesConfig задает расположение конфигурации и шаблонов интервью
+type InterviewFilesConfig struct {
+	ConfigFile   string
+	TemplatesDir string
+	// PromptsDir - шаблоны промптов извлечения (*.tmpl), дополняющие и заменяющие встроенные версии;
+	// PromptsReloadInterval - период проверки их изменений (0 - без перезагрузки)
+	PromptsDir            string
+	PromptsReloadInterval time.Duration
+	// WatchConfig - перезагружать шаблоны интервью и схему профиля при изменении их файлов
+	// (false - только по команде /reloadconfig)
+	WatchConfig bool
+}
+
+// InvitesConfig задает проверку приглашений из deep link /start <payload>
+type InvitesConfig struct {
+	File       string // список приглашений (YAML)
+	HMACSecret string // секрет для подписанных ссылок
+	Required   bool   // запрещать интервью без приглашения
 }
 
 type TelegramConfig struct {
 	Token      string
 	WebhookURL string
 	Debug      bool
+	AdminIDs   []int64
+	// WebhookSecret - секрет вебхука WebhookURL: https адреса, на который Telegram отправляет обновления
+	// вместо getUpdates, чтобы несколько реплик бота работали за балансировщиком. Telegram передает
+	// секрет в заголовке каждого запроса. Без WebhookURL обновления получаются long polling.
+	WebhookSecret string
+	// AdminChatID - чат для уведомлений об ошибках; 0 - уведомлять каждого администратора
+	AdminChatID int64
+	// ProfilePreview включает превью профиля сообщением перед отправкой файла
+	ProfilePreview bool
+	// BlockReview включает проверку ответов блока кнопками перед созданием саммари
+	BlockReview bool
+	// InterviewerMemory - уточняющие вопросы учитывают профиль прошлого интервью пользователя,
+	// если он включил это командой /memory
+	InterviewerMemory bool
+	// ObserverChatID - чат наблюдателей, куда в реальном времени транслируются обезличенные
+	// интервью без арендатора и арендаторов с observer: true; 0 - трансляция выключена
+	ObserverChatID int64
+	// NudgeAfter - через сколько напомнить о вопросе без ответа (второе напоминание - еще через столько же); 0 - не напоминать
+	NudgeAfter time.Duration
+	// PollTimeout - длительность long polling getUpdates; 0 - короткий polling
+	PollTimeout time.Duration
+	// PollLimit - максимум обновлений за один getUpdates (1-100)
+	PollLimit int
+	// AllowedUpdates - типы обновлений getUpdates; пусто - все, которые обрабатывает бот
+	AllowedUpdates []string
+	// Maintenance - режим обслуживания при запуске: новые интервью не начинаются, идущие доигрываются
+	Maintenance bool
+	// DrainTimeout - сколько при остановке ждать идущих извлечений профилей
+	DrainTimeout time.Duration
+	// DropoffInterval - как часто присылать администраторам отчет об уходе по вопросам; 0 - не присылать
+	DropoffInterval time.Duration
+	// UpdateWorkers - обновлений, обрабатываемых одновременно (обновления одного чата - по очереди)
+	UpdateWorkers int
+	// UpdateQueueSize - предел обновлений в обработке и очереди, после которого прием приостанавливается
+	UpdateQueueSize int
+	// UpdateShedAfter - сколько ждать места в заполненной очереди, прежде чем отбросить обновление
+	UpdateShedAfter time.Duration
 }
 
 type ServerConfig struct {
@@ -23,6 +223,28 @@ type ServerConfig struct {
 	ReadTimeout     time.Duration
 	WriteTimeout    time.Duration
 	ShutdownTimeout time.Duration
+	// APIToken - bearer-токен для эндпоинтов /api/*; без него API недоступен
+	APIToken string
+	// InterviewAPI включает InterviewService - проведение интервью через API без Telegram
+	InterviewAPI bool
+}
+
+// RateLimitConfig задает лимиты сообщений и обращений к OpenAI
+type RateLimitConfig struct {
+	MessagesPerMinute  int
+	MessagesBurst      int
+	LLMCallsPerMinute  int
+	LLMCallsBurst      int
+	GlobalLLMPerMinute int
+	GlobalLLMBurst     int
+	IdleTTL            time.Duration
+	CleanupInterval    time.Duration
+	// OpenAIRequestsPerMinute и OpenAITokensPerMinute - лимиты аккаунта OpenAI (RPM/TPM) для
+	// планировщика запросов всех сервисов; 0 отключает лимит
+	OpenAIRequestsPerMinute int
+	OpenAITokensPerMinute   int
+	// OpenAIMaxWait - сколько запрос может ждать места в лимитах OpenAI
+	OpenAIMaxWait time.Duration
 }
 
 func LoadAppConfig() *AppConfig {
@@ -32,17 +254,145 @@ func LoadAppConfig() *AppConfig {
 			Model:       getEnv("OPENAI_MODEL", "gpt-4"),
 			MaxTokens:   getEnvAsInt("OPENAI_MAX_TOKENS", 4000),
 			Temperature: getEnvAsFloat("OPENAI_TEMPERATURE", 0.1),
+
+			ContextTokens: getEnvAsInt("OPENAI_CONTEXT_TOKENS", 0),
 		},
 		Telegram: TelegramConfig{
-			Token:      getEnv("TELEGRAM_BOT_TOKEN", ""),
-			WebhookURL: getEnv("TELEGRAM_WEBHOOK_URL", ""),
-			Debug:      getEnvAsBool("TELEGRAM_DEBUG", false),
+			Token:             getEnv("TELEGRAM_BOT_TOKEN", ""),
+			WebhookURL:        getEnv("TELEGRAM_WEBHOOK_URL", ""),
+			WebhookSecret:     getEnv("TELEGRAM_WEBHOOK_SECRET", ""),
+			Debug:             getEnvAsBool("TELEGRAM_DEBUG", false),
+			AdminIDs:          getEnvAsInt64Slice("TELEGRAM_ADMIN_IDS"),
+			AdminChatID:       getEnvAsInt64("TELEGRAM_ADMIN_CHAT_ID", 0),
+			ProfilePreview:    getEnvAsBool("TELEGRAM_PROFILE_PREVIEW", false),
+			BlockReview:       getEnvAsBool("TELEGRAM_BLOCK_REVIEW", true),
+			InterviewerMemory: getEnvAsBool("INTERVIEWER_MEMORY_ENABLED", false),
+			ObserverChatID:    getEnvAsInt64("TELEGRAM_OBSERVER_CHAT_ID", 0),
+			NudgeAfter:        getEnvAsDuration("TELEGRAM_NUDGE_AFTER", 30*time.Minute),
+			PollTimeout:       getEnvAsDuration("TELEGRAM_POLL_TIMEOUT", 30*time.Second),
+			PollLimit:         getEnvAsInt("TELEGRAM_POLL_LIMIT", 100),
+			AllowedUpdates:    getEnvAsList("TELEGRAM_ALLOWED_UPDATES", nil),
+			Maintenance:       getEnvAsBool("MAINTENANCE_MODE", false),
+			DrainTimeout:      getEnvAsDuration("SHUTDOWN_DRAIN_TIMEOUT", 2*time.Minute),
+			DropoffInterval:   getEnvAsDuration("DROPOFF_REPORT_INTERVAL", 0),
+			UpdateWorkers:     getEnvAsInt("TELEGRAM_UPDATE_WORKERS", 32),
+			UpdateQueueSize:   getEnvAsInt("TELEGRAM_UPDATE_QUEUE_SIZE", 1000),
+			UpdateShedAfter:   getEnvAsDuration("TELEGRAM_UPDATE_SHED_AFTER", 5*time.Second),
 		},
 		Server: ServerConfig{
 			Port:            getEnvAsInt("SERVER_PORT", 8080),
 			ReadTimeout:     getEnvAsDuration("SERVER_READ_TIMEOUT", 10*time.Second),
 			WriteTimeout:    getEnvAsDuration("SERVER_WRITE_TIMEOUT", 10*time.Second),
 			ShutdownTimeout: getEnvAsDuration("SERVER_SHUTDOWN_TIMEOUT", 30*time.Second),
+			APIToken:        getEnv("SERVER_API_TOKEN", ""),
+			InterviewAPI:    getEnvAsBool("SERVER_INTERVIEW_API", false),
+		},
+		RateLimit: RateLimitConfig{
+			MessagesPerMinute:  getEnvAsInt("RATE_LIMIT_MESSAGES_PER_MINUTE", 10),
+			MessagesBurst:      getEnvAsInt("RATE_LIMIT_MESSAGES_BURST", 10),
+			LLMCallsPerMinute:  getEnvAsInt("RATE_LIMIT_LLM_PER_MINUTE", 6),
+			LLMCallsBurst:      getEnvAsInt("RATE_LIMIT_LLM_BURST", 3),
+			GlobalLLMPerMinute: getEnvAsInt("RATE_LIMIT_GLOBAL_LLM_PER_MINUTE", 60),
+			GlobalLLMBurst:     getEnvAsInt("RATE_LIMIT_GLOBAL_LLM_BURST", 20),
+			IdleTTL:            getEnvAsDuration("RATE_LIMIT_IDLE_TTL", time.Hour),
+			CleanupInterval:    getEnvAsDuration("RATE_LIMIT_CLEANUP_INTERVAL", 10*time.Minute),
+
+			OpenAIRequestsPerMinute: getEnvAsInt("RATE_LIMIT_OPENAI_RPM", 0),
+			OpenAITokensPerMinute:   getEnvAsInt("RATE_LIMIT_OPENAI_TPM", 0),
+			OpenAIMaxWait:           getEnvAsDuration("RATE_LIMIT_OPENAI_MAX_WAIT", 2*time.Minute),
+		},
+		Interview: InterviewFilesConfig{
+			ConfigFile:            getEnv("INTERVIEW_CONFIG", "config/interview.yaml"),
+			TemplatesDir:          getEnv("INTERVIEW_TEMPLATES_DIR", "config/templates"),
+			PromptsDir:            getEnv("PROMPTS_DIR", "prompts"),
+			PromptsReloadInterval: getEnvAsDuration("PROMPTS_RELOAD_INTERVAL", 30*time.Second),
+			WatchConfig:           getEnvAsBool("CONFIG_WATCH", true),
+		},
+		ProfileQA: ProfileQAConfig{
+			QuestionsPerDay: getEnvAsInt("ASK_QUESTIONS_PER_DAY", 10),
+		},
+		Storage: StorageConfig{
+			ResultsDir:          getEnv("RESULTS_DIR", "results"),
+			OutputDir:           getEnv("OUTPUT_DIR", "output"),
+			ResultFileTemplate:  getEnv("RESULT_FILE_TEMPLATE", "interview_{{id}}.json"),
+			ProfileFileTemplate: getEnv("PROFILE_FILE_TEMPLATE", "profile_{{id}}.json"),
+			ExportFormats:       getEnvAsList("PROFILE_EXPORT_FORMATS", nil),
+		},
+		Reporting: ReportingConfig{
+			SentryDSN:   getEnv("SENTRY_DSN", ""),
+			Environment: getEnv("SENTRY_ENVIRONMENT", "production"),
+		},
+		Moderation: ModerationConfig{
+			Enabled: getEnvAsBool("MODERATION_ENABLED", false),
+			Model:   getEnv("MODERATION_MODEL", "omni-moderation-latest"),
+			Categories: getEnvAsList("MODERATION_CATEGORIES", []string{
+				"self-harm", "self-harm/intent", "self-harm/instructions", "harassment/threatening", "violence",
+			}),
+			CrisisMessage: getEnv("MODERATION_CRISIS_MESSAGE", "💙 Похоже, вам сейчас может быть тяжело. Вы не одни: "+
+				"в России можно бесплатно и анонимно позвонить на телефон доверия 8-800-2000-122 (круглосуточно). "+
+				"Если есть угроза жизни, звоните 112.\n\nИнтервью можно продолжить, когда будете готовы, или остановить командой /stop."),
+			NotifyAdmins:     getEnvAsBool("MODERATION_NOTIFY_ADMINS", true),
+			ExtractionPolicy: getEnv("MODERATION_EXTRACTION_POLICY", ModerationExclude),
+		},
+		Research: ResearchConfig{
+			PseudonymSalt: getEnv("RESEARCH_PSEUDONYM_SALT", ""),
+		},
+		Search: SearchConfig{
+			Enabled:         getEnvAsBool("SEARCH_ENABLED", true),
+			RefreshInterval: getEnvAsDuration("SEARCH_REFRESH_INTERVAL", time.Hour),
+		},
+		Consent: ConsentConfig{
+			Required: getEnvAsBool("CONSENT_REQUIRED", true),
+			Version:  getEnv("CONSENT_VERSION", "1"),
+		},
+		Redis: RedisConfig{
+			URL:        getEnv("REDIS_URL", ""),
+			KeyPrefix:  getEnv("REDIS_KEY_PREFIX", "interview-bot:"),
+			SessionTTL: getEnvAsDuration("REDIS_SESSION_TTL", 24*time.Hour),
+		},
+		Premium: PremiumConfig{
+			Enabled:          getEnvAsBool("PREMIUM_ENABLED", false),
+			ProviderToken:    getEnv("PAYMENTS_PROVIDER_TOKEN", ""),
+			Currency:         getEnv("PREMIUM_CURRENCY", "XTR"),
+			Price:            getEnvAsInt("PREMIUM_PRICE", 100),
+			PDFFont:          getEnv("PREMIUM_PDF_FONT", ""),
+			ArchetypesDir:    getEnv("PREMIUM_ARCHETYPES_DIR", "config/archetypes"),
+			ArchetypeCatalog: getEnv("PREMIUM_ARCHETYPE_CATALOG", "marvel"),
+		},
+		Invites: InvitesConfig{
+			File:       getEnv("INVITES_FILE", "config/invites.yaml"),
+			HMACSecret: getEnv("INVITE_HMAC_SECRET", ""),
+			Required:   getEnvAsBool("INVITES_REQUIRED", false),
+		},
+		Logging: LoggingConfig{
+			Level:  getEnv("LOG_LEVEL", "info"),
+			Format: getEnv("LOG_FORMAT", "text"),
+		},
+		Quotas: QuotaConfig{
+			InterviewsPerUserPerDay: getEnvAsInt("QUOTA_INTERVIEWS_PER_USER_PER_DAY", 0),
+			MaxActiveInterviews:     getEnvAsInt("QUOTA_MAX_ACTIVE_INTERVIEWS", 0),
+			DailyTokenBudget:        getEnvAsInt("QUOTA_DAILY_TOKEN_BUDGET", 0),
+		},
+		Retention: RetentionConfig{
+			Days:       getEnvAsInt("RETENTION_DAYS", 0),
+			Mode:       getEnv("RETENTION_MODE", "archive"),
+			ArchiveDir: getEnv("RETENTION_ARCHIVE_DIR", "archive"),
+			RunAt:      getEnv("RETENTION_RUN_AT", "03:00"),
+		},
+		Outcome: OutcomeConfig{
+			RubricsDir:      getEnv("OUTCOME_RUBRICS_DIR", "config/rubrics"),
+			Rubric:          getEnv("OUTCOME_RUBRIC", ""),
+			RecruiterChatID: getEnvAsInt64("RECRUITER_CHAT_ID", 0),
+		},
+		Tenants: TenantsConfig{
+			File: getEnv("TENANTS_FILE", "config/tenants.yaml"),
+		},
+		Sheets: SheetsConfig{
+			CredentialsFile: getEnv("GOOGLE_SHEETS_CREDENTIALS_FILE", "config/google_service_account.json"),
+			SpreadsheetID:   getEnv("GOOGLE_SHEETS_SPREADSHEET_ID", ""),
+			Sheet:           getEnv("GOOGLE_SHEETS_SHEET", "Profiles"),
+			Columns: getEnvAsList("GOOGLE_SHEETS_COLUMNS", []string{"name", "age", "current_city", "current_position",
+				"work_experience_years", "hard_skills", "soft_skills", "career_goals", "work_style"}),
 		},
 	}
 }
@@ -81,6 +431,40 @@ func getEnvAsFloat(key string, defaultValue float64) float64 {
 	return defaultValue
 }
 
+func getEnvAsInt64(key string, defaultValue int64) int64 {
+	if value := os.Getenv(key); value != "" {
+		if intValue, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return intValue
+		}
+	}
+	return defaultValue
+}
+
+// getEnvAsInt64Slice читает список чисел, разделенных запятыми
+func getEnvAsInt64Slice(key string) []int64 {
+	var values []int64
+	for _, part := range strings.Split(os.Getenv(key), ",") {
+		if value, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64); err == nil {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// getEnvAsList читает список строк, разделенных запятыми
+func getEnvAsList(key string, defaultValue []string) []string {
+	var values []string
+	for _, part := range strings.Split(os.Getenv(key), ",") {
+		if value := strings.TrimSpace(part); value != "" {
+			values = append(values, value)
+		}
+	}
+	if len(values) == 0 {
+		return defaultValue
+	}
+	return values
+}
+
 func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
 	if value := os.Getenv(key); value != "" {
 		if duration, err := time.ParseDuration(value); err == nil {
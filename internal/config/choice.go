@@ -0,0 +1,162 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Типы вопросов шаблона
+const (
+	QuestionText   = "text"   // открытый ответ (по умолчанию)
+	QuestionChoice = "choice" // выбор из вариантов; ответ записывается без обращения к модели
+)
+
+// Отображение вариантов ответа в Telegram
+const (
+	KeyboardInline = "inline" // кнопки под вопросом (по умолчанию)
+	KeyboardReply  = "reply"  // клавиатура вместо поля ввода
+)
+
+// maxChoiceOptions - наибольшее число вариантов ответа (кнопок) у вопроса
+const maxChoiceOptions = 20
+
+// Choice - варианты ответа на закрытый вопрос
+type Choice struct {
+	Options  []string
+	Keyboard string
+	// AllowOther - кроме вариантов принимается свой ответ текстом
+	AllowOther bool
+}
+
+// questionYAML - вопрос, записанный объектом:
+// {text: "вопрос", type: choice, options: [да, нет], keyboard: reply, allow_other: true}
+// или {text: "вопрос", answer_type: number, profile_field: age}
+type questionYAML struct {
+	Text       yaml.Node `yaml:"text"`
+	Type       string    `yaml:"type"`
+	Options    []string  `yaml:"options"`
+	Keyboard   string    `yaml:"keyboard"`
+	AllowOther bool      `yaml:"allow_other"`
+	AnswerType string    `yaml:"answer_type"`
+	// ProfileField - поле профиля, которое заполняет ответ на открытый вопрос
+	ProfileField string `yaml:"profile_field"`
+}
+
+// unmarshalChoice читает вопрос, записанный объектом
+func (s *QuestionSlot) unmarshalChoice(value *yaml.Node) error {
+	var question questionYAML
+	if err := value.Decode(&question); err != nil {
+		return err
+	}
+	if question.Text.Kind == 0 {
+		return fmt.Errorf("строка %d: вопрос должен иметь text", value.Line)
+	}
+	variants, err := decodeVariants(&question.Text)
+	if err != nil {
+		return err
+	}
+
+	// Вопрос с options - закрытый и без type (удобно в переводах)
+	if question.Type == "" && len(question.Options) > 0 {
+		question.Type = QuestionChoice
+	}
+	switch question.Type {
+	case "", QuestionText:
+		if len(question.Options) > 0 {
+			return fmt.Errorf("строка %d: options допустимы только для type: %s", value.Line, QuestionChoice)
+		}
+		if err := validateAnswerType(question.AnswerType); err != nil {
+			return fmt.Errorf("строка %d: %w", value.Line, err)
+		}
+		*s = QuestionSlot{Variants: variants, AnswerType: question.AnswerType, ProfileField: question.ProfileField}
+	case QuestionChoice:
+		if question.AnswerType != "" {
+			return fmt.Errorf("строка %d: answer_type допустим только для открытых вопросов", value.Line)
+		}
+		if question.ProfileField != "" {
+			return fmt.Errorf("строка %d: profile_field допустим только для открытых вопросов", value.Line)
+		}
+		*s = QuestionSlot{Variants: variants, Choice: &Choice{
+			Options:    question.Options,
+			Keyboard:   question.Keyboard,
+			AllowOther: question.AllowOther,
+		}}
+	default:
+		return fmt.Errorf("строка %d: неизвестный тип вопроса %q (допустимы %s и %s)", value.Line, question.Type, QuestionText, QuestionChoice)
+	}
+	return nil
+}
+
+// ChoiceFor возвращает варианты ответа на вопрос question (с нуля) блока;
+// nil - открытый или уточняющий вопрос модели
+func (b Block) ChoiceFor(question int) *Choice {
+	if question < 0 || question >= len(b.Questions) {
+		return nil
+	}
+	return b.Questions[question].Choice
+}
+
+// Match сопоставляет ответ с вариантом по тексту (без учета регистра) или по номеру варианта
+// и возвращает текст варианта
+func (c *Choice) Match(answer string) (string, bool) {
+	answer = strings.TrimSpace(answer)
+	for _, option := range c.Options {
+		if strings.EqualFold(option, answer) {
+			return option, true
+		}
+	}
+	if number, err := strconv.Atoi(answer); err == nil && number >= 1 && number <= len(c.Options) {
+		return c.Options[number-1], true
+	}
+	return "", false
+}
+
+// validateChoice проверяет варианты ответа вопроса
+func validateChoice(choice *Choice) error {
+	if len(choice.Options) < 2 {
+		return fmt.Errorf("вопрос с type: %s должен иметь не меньше двух options", QuestionChoice)
+	}
+	if len(choice.Options) > maxChoiceOptions {
+		return fmt.Errorf("вопрос с type: %s может иметь не больше %d options", QuestionChoice, maxChoiceOptions)
+	}
+	seen := make(map[string]bool, len(choice.Options))
+	for _, option := range choice.Options {
+		key := strings.ToLower(strings.TrimSpace(option))
+		if key == "" {
+			return fmt.Errorf("options содержат пустой вариант")
+		}
+		if seen[key] {
+			return fmt.Errorf("вариант %q повторяется в options", option)
+		}
+		seen[key] = true
+	}
+	if choice.Keyboard != "" && choice.Keyboard != KeyboardInline && choice.Keyboard != KeyboardReply {
+		return fmt.Errorf("неизвестный keyboard %q (допустимы %s и %s)", choice.Keyboard, KeyboardInline, KeyboardReply)
+	}
+	return nil
+}
+
+// translateChoice накладывает перевод вариантов ответа: их число должно совпадать с исходным,
+// отображение и allow_other берутся из шаблона. Без перевода варианты остаются исходными.
+func translateChoice(source, translated QuestionSlot) (*Choice, error) {
+	if source.Choice == nil {
+		if translated.Choice != nil {
+			return nil, fmt.Errorf("в шаблоне вопрос без вариантов ответа")
+		}
+		return nil, nil
+	}
+	if translated.Choice == nil {
+		return source.Choice, nil
+	}
+	if len(translated.Choice.Options) != len(source.Choice.Options) {
+		return nil, fmt.Errorf("переведено %d вариантов ответа, в шаблоне %d", len(translated.Choice.Options), len(source.Choice.Options))
+	}
+	return &Choice{
+		Options:    translated.Choice.Options,
+		Keyboard:   source.Choice.Keyboard,
+		AllowOther: source.Choice.AllowOther,
+	}, nil
+}
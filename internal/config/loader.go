@@ -1,8 +1,12 @@
 package config
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"interview-bot-complete/internal/condition"
 	"os"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -26,9 +30,33 @@ func Load(filename string) (*Config, error) {
 		return nil, fmt.Errorf("ошибка валидации конфигурации: %w", err)
 	}
 
+	sum := sha256.New()
+	sum.Write(data)
+	if err := loadTranslations(&config, filename, sum); err != nil {
+		return nil, err
+	}
+	config.setRevision(hex.EncodeToString(sum.Sum(nil))[:revisionLength])
+
 	return &config, nil
 }
 
+// revisionLength - длина ревизии шаблона (начало SHA-256 файлов шаблона)
+const revisionLength = 12
+
+// Revision возвращает ревизию шаблона - контрольную сумму файла шаблона и его переводов.
+// По ней интервью доводится по той версии шаблона, с которой начато (Templates.Revision).
+func (c *Config) Revision() string {
+	return c.revision
+}
+
+// setRevision задает ревизию шаблону и его переводам
+func (c *Config) setRevision(revision string) {
+	c.revision = revision
+	for _, localized := range c.translations {
+		localized.revision = revision
+	}
+}
+
 // validateConfig проверяет корректность конфигурации
 func validateConfig(config *Config) error {
 	if config.InterviewConfig.TotalBlocks <= 0 {
@@ -43,6 +71,37 @@ func validateConfig(config *Config) error {
 		return fmt.Errorf("max_followup_questions не может быть отрицательным")
 	}
 
+	if config.InterviewConfig.BlockTimeLimitMinutes < 0 {
+		return fmt.Errorf("block_time_limit_minutes не может быть отрицательным")
+	}
+
+	if config.InterviewConfig.MinAnswerLength < 0 {
+		return fmt.Errorf("min_answer_length не может быть отрицательным")
+	}
+
+	if lang := config.InterviewConfig.Language; lang != "" && !languageCodePattern.MatchString(lang) {
+		return fmt.Errorf("language должен быть двухбуквенным кодом языка, получен %q", lang)
+	}
+
+	if err := validateModels(config.Models); err != nil {
+		return err
+	}
+	if err := validateStages(config.OpenAI); err != nil {
+		return err
+	}
+
+	if err := validateAnswerRules(config); err != nil {
+		return err
+	}
+
+	if err := validatePersonas(config); err != nil {
+		return err
+	}
+
+	if err := validateMessages(config.Messages); err != nil {
+		return err
+	}
+
 	if len(config.Blocks) != config.InterviewConfig.TotalBlocks {
 		return fmt.Errorf("количество блоков (%d) не соответствует total_blocks (%d)",
 			len(config.Blocks), config.InterviewConfig.TotalBlocks)
@@ -77,7 +136,94 @@ func validateConfig(config *Config) error {
 		if len(block.Questions) != config.InterviewConfig.QuestionsPerBlock {
 			return fmt.Errorf("блок %d должен содержать %d вопросов, найдено %d", block.ID, config.InterviewConfig.QuestionsPerBlock, len(block.Questions))
 		}
+
+		for n, question := range block.Questions {
+			if len(question.Variants) == 0 {
+				return fmt.Errorf("блок %d: вопрос %d не содержит вариантов", block.ID, n+1)
+			}
+			for _, variant := range question.Variants {
+				if strings.TrimSpace(variant) == "" {
+					return fmt.Errorf("блок %d: вопрос %d содержит пустой вариант", block.ID, n+1)
+				}
+			}
+			if question.Choice != nil {
+				if err := validateChoice(question.Choice); err != nil {
+					return fmt.Errorf("блок %d: вопрос %d: %w", block.ID, n+1, err)
+				}
+			}
+		}
+
+		for n, goal := range block.CoverageGoals {
+			if strings.TrimSpace(goal) == "" {
+				return fmt.Errorf("блок %d: цель покрытия %d пустая", block.ID, n+1)
+			}
+		}
+
+		if block.TimeLimitMinutes < 0 {
+			return fmt.Errorf("блок %d: time_limit_minutes не может быть отрицательным", block.ID)
+		}
+
+		if block.OptInQuestion != "" && !block.Optional {
+			return fmt.Errorf("блок %d: opt_in_question задается только для блока с optional: true", block.ID)
+		}
+
+		if block.Condition != "" {
+			expr, err := condition.Parse(block.Condition)
+			if err != nil {
+				return fmt.Errorf("блок %d: неверное условие %q: %w", block.ID, block.Condition, err)
+			}
+			for _, name := range condition.Flags(expr) {
+				if _, ok := config.Flags[name]; !ok {
+					return fmt.Errorf("блок %d: условие ссылается на неизвестный флаг %s", block.ID, name)
+				}
+			}
+		}
 	}
 
 	return nil
 }
+
+// validateModels проверяет переопределения моделей по сценариям
+func validateModels(models map[string]ModelSettings) error {
+	for useCase, settings := range models {
+		known := false
+		for _, candidate := range UseCases {
+			if useCase == candidate {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("models: неизвестный сценарий %q (доступны: %s)", useCase, strings.Join(UseCases, ", "))
+		}
+		if err := validateModelSettings("models."+useCase, settings); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateStages проверяет параметры моделей по этапам (секция openai)
+func validateStages(stages OpenAIStages) error {
+	for name, settings := range map[string]ModelSettings{
+		"interview":  stages.Interview,
+		"summary":    stages.Summary,
+		"extraction": stages.Extraction,
+		"matching":   stages.Matching,
+	} {
+		if err := validateModelSettings("openai."+name, settings); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateModelSettings(path string, settings ModelSettings) error {
+	if settings.Temperature != nil && (*settings.Temperature < 0 || *settings.Temperature > 2) {
+		return fmt.Errorf("%s.temperature должна быть от 0 до 2", path)
+	}
+	if settings.MaxTokens < 0 {
+		return fmt.Errorf("%s.max_tokens не может быть отрицательным", path)
+	}
+	return nil
+}
@@ -0,0 +1,219 @@
+package config
+
+import (
+	"fmt"
+	"hash"
+	"interview-bot-complete/internal/language"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// translationFilePattern - файл перевода шаблона: <шаблон>.<язык>.yaml
+	translationFilePattern = regexp.MustCompile(`^(.+)\.([a-z]{2})\.yaml$`)
+	// languageCodePattern - код языка шаблона (ISO 639-1)
+	languageCodePattern = regexp.MustCompile(`^[a-z]{2}$`)
+)
+
+// Translation - перевод шаблона на другой язык. ID и name блоков не переводятся,
+// поэтому результаты интервью на разных языках сопоставимы по блокам.
+type Translation struct {
+	Blocks []BlockTranslation `yaml:"blocks"`
+	// Flags дополняет ключевые слова флагов словами на языке перевода
+	Flags map[string][]string `yaml:"flags,omitempty"`
+	// Messages - сообщения участнику на языке перевода; незаданные берутся из шаблона
+	Messages Messages `yaml:"messages,omitempty"`
+}
+
+// BlockTranslation - перевод блока; незаданные поля берутся из исходного шаблона
+type BlockTranslation struct {
+	ID            int            `yaml:"id"`
+	Title         string         `yaml:"title,omitempty"`
+	ContextPrompt string         `yaml:"context_prompt,omitempty"`
+	FocusAreas    []string       `yaml:"focus_areas,omitempty"`
+	CoverageGoals []string       `yaml:"coverage_goals,omitempty"`
+	Questions     []QuestionSlot `yaml:"questions,omitempty"`
+	OptInQuestion string         `yaml:"opt_in_question,omitempty"`
+}
+
+// Language возвращает язык вопросов шаблона
+func (c *Config) Language() string {
+	if c.InterviewConfig.Language == "" {
+		return language.Default
+	}
+	return c.InterviewConfig.Language
+}
+
+// Languages возвращает язык шаблона и языки его переводов
+func (c *Config) Languages() []string {
+	languages := []string{c.Language()}
+	for lang := range c.translations {
+		if lang != c.Language() {
+			languages = append(languages, lang)
+		}
+	}
+	sort.Strings(languages[1:])
+	return languages
+}
+
+// MatchLanguage подбирает язык шаблона по коду языка пользователя (например, en-US);
+// если перевода нет, возвращается язык шаблона
+func (c *Config) MatchLanguage(code string) string {
+	code = strings.ToLower(code)
+	if i := strings.IndexAny(code, "-_"); i >= 0 {
+		code = code[:i]
+	}
+	if _, ok := c.translations[code]; ok {
+		return code
+	}
+	return c.Language()
+}
+
+// Localized возвращает шаблон на языке lang (сам шаблон, если перевода нет)
+func (c *Config) Localized(lang string) *Config {
+	if localized, ok := c.translations[lang]; ok {
+		return localized
+	}
+	return c
+}
+
+// translationFiles возвращает файлы переводов шаблона <шаблон>.<язык>.yaml рядом с ним
+func translationFiles(file string) ([]string, error) {
+	base := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+	matches, err := filepath.Glob(filepath.Join(filepath.Dir(file), base+".*.yaml"))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка поиска переводов %s: %w", file, err)
+	}
+
+	var files []string
+	for _, path := range matches {
+		if match := translationFilePattern.FindStringSubmatch(filepath.Base(path)); match != nil && match[1] == base {
+			files = append(files, path)
+		}
+	}
+	return files, nil
+}
+
+// loadTranslations загружает переводы шаблона из файлов <шаблон>.<язык>.yaml рядом с ним;
+// содержимое файлов добавляется в sum для ревизии шаблона
+func loadTranslations(cfg *Config, file string, sum hash.Hash) error {
+	files, err := translationFiles(file)
+	if err != nil {
+		return err
+	}
+
+	for _, path := range files {
+		lang := translationFilePattern.FindStringSubmatch(filepath.Base(path))[2]
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("ошибка чтения файла %s: %w", path, err)
+		}
+		sum.Write(data)
+		var translation Translation
+		if err := yaml.Unmarshal(data, &translation); err != nil {
+			return fmt.Errorf("перевод %s: ошибка парсинга YAML: %w", lang, err)
+		}
+		localized, err := cfg.translate(lang, translation)
+		if err != nil {
+			return fmt.Errorf("перевод %s: %w", lang, err)
+		}
+		if cfg.translations == nil {
+			cfg.translations = make(map[string]*Config)
+		}
+		cfg.translations[lang] = localized
+	}
+	return nil
+}
+
+// translate накладывает перевод на копию шаблона. Число вопросов и вариантов формулировки
+// должно совпадать с исходным, чтобы ID вариантов (b2.q1.v2) означали одно и то же.
+func (c *Config) translate(lang string, translation Translation) (*Config, error) {
+	localized := *c
+	localized.translations = nil
+	localized.InterviewConfig.Language = lang
+	localized.Blocks = append([]Block(nil), c.Blocks...)
+
+	seen := make(map[int]bool)
+	for _, bt := range translation.Blocks {
+		if bt.ID < 1 || bt.ID > len(localized.Blocks) {
+			return nil, fmt.Errorf("блок %d отсутствует в шаблоне", bt.ID)
+		}
+		if seen[bt.ID] {
+			return nil, fmt.Errorf("блок %d переведен повторно", bt.ID)
+		}
+		seen[bt.ID] = true
+
+		block := &localized.Blocks[bt.ID-1]
+		if bt.Title != "" {
+			block.Title = bt.Title
+		}
+		if bt.ContextPrompt != "" {
+			block.ContextPrompt = bt.ContextPrompt
+		}
+		if len(bt.FocusAreas) > 0 {
+			block.FocusAreas = bt.FocusAreas
+		}
+		if bt.OptInQuestion != "" {
+			block.OptInQuestion = bt.OptInQuestion
+		}
+		if len(bt.CoverageGoals) > 0 {
+			if len(bt.CoverageGoals) != len(block.CoverageGoals) {
+				return nil, fmt.Errorf("блок %d: переведено %d целей покрытия, в шаблоне %d", bt.ID, len(bt.CoverageGoals), len(block.CoverageGoals))
+			}
+			block.CoverageGoals = bt.CoverageGoals
+		}
+		if len(bt.Questions) == 0 {
+			continue
+		}
+		if len(bt.Questions) != len(block.Questions) {
+			return nil, fmt.Errorf("блок %d: переведено %d вопросов, в шаблоне %d", bt.ID, len(bt.Questions), len(block.Questions))
+		}
+		questions := make([]QuestionSlot, len(bt.Questions))
+		for n, question := range bt.Questions {
+			if len(question.Variants) != len(block.Questions[n].Variants) {
+				return nil, fmt.Errorf("блок %d: вопрос %d содержит %d вариантов, в шаблоне %d", bt.ID, n+1, len(question.Variants), len(block.Questions[n].Variants))
+			}
+			choice, err := translateChoice(block.Questions[n], question)
+			if err != nil {
+				return nil, fmt.Errorf("блок %d: вопрос %d: %w", bt.ID, n+1, err)
+			}
+			if question.AnswerType != "" && question.AnswerType != block.Questions[n].AnswerType {
+				return nil, fmt.Errorf("блок %d: вопрос %d: answer_type перевода отличается от шаблона", bt.ID, n+1)
+			}
+			questions[n] = QuestionSlot{Variants: question.Variants, Choice: choice, AnswerType: block.Questions[n].AnswerType,
+				ProfileField: block.Questions[n].ProfileField}
+		}
+		block.Questions = questions
+	}
+
+	if translation.Messages.Welcome != "" {
+		localized.Messages.Welcome = translation.Messages.Welcome
+	}
+	if translation.Messages.Completion != "" {
+		localized.Messages.Completion = translation.Messages.Completion
+	}
+
+	if len(translation.Flags) > 0 {
+		localized.Flags = make(map[string][]string, len(c.Flags))
+		for name, keywords := range c.Flags {
+			localized.Flags[name] = keywords
+		}
+		for name, keywords := range translation.Flags {
+			if _, ok := c.Flags[name]; !ok {
+				return nil, fmt.Errorf("флаг %s отсутствует в шаблоне", name)
+			}
+			localized.Flags[name] = append(append([]string(nil), c.Flags[name]...), keywords...)
+		}
+	}
+
+	if err := validateConfig(&localized); err != nil {
+		return nil, err
+	}
+	return &localized, nil
+}
@@ -0,0 +1,120 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Плейсхолдеры шаблонов сообщений
+const (
+	PlaceholderInterviewID     = "{{interview_id}}"     // ID интервью
+	PlaceholderBlocks          = "{{blocks}}"           // число блоков шаблона
+	PlaceholderQuestions       = "{{questions}}"        // вопросов в блоке, включая уточняющие
+	PlaceholderMinutes         = "{{minutes}}"          // примерная длительность интервью в минутах
+	PlaceholderCompletedBlocks = "{{completed_blocks}}" // пройдено блоков
+	PlaceholderAnswers         = "{{answers}}"          // получено ответов
+)
+
+// minutesPerBlock - оценка длительности одного блока для {{minutes}}
+const minutesPerBlock = 3
+
+// messagePlaceholderPattern находит плейсхолдеры в шаблоне сообщения
+var messagePlaceholderPattern = regexp.MustCompile(`\{\{[^}]*\}\}`)
+
+// DefaultWelcomeMessage - приветствие перед первым блоком, если messages.welcome не задан
+const DefaultWelcomeMessage = `🎯 *Добро пожаловать в интервью!*
+
+🆔 *ID интервью:* ` + "`{{interview_id}}`" + `
+📋 *Всего блоков:* {{blocks}}
+❓ *Вопросов в блоке:* до {{questions}}
+⏱ *Время:* ~{{minutes}} минут
+
+*Правила:*
+• Отвечайте честно и подробно
+• Можете отвечать в несколько сообщений
+• Используйте /status для проверки прогресса
+• Используйте /stop для остановки
+
+Готовы начать? Сейчас начнется первый блок! 🚀`
+
+// DefaultCompletionMessage - сообщение о завершении интервью, если messages.completion не задан
+const DefaultCompletionMessage = `✅ *Интервью успешно завершено!*
+📊 Собрано данных:
+• {{completed_blocks}} блоков пройдено
+• {{answers}} ответов получено
+• 🆔 ID: ` + "`{{interview_id}}`" + `
+
+🧠 Анализ профиля в процессе...
+Результат будет готов через 1-2 минуты.
+
+Используйте /start для нового интервью.`
+
+// Messages - тексты сообщений участнику с плейсхолдерами {{...}}; пустой текст - сообщение по умолчанию.
+// Тексты отправляются с разметкой Markdown.
+type Messages struct {
+	// Welcome - приветствие перед первым блоком: {{interview_id}}, {{blocks}}, {{questions}}, {{minutes}}
+	Welcome string `yaml:"welcome,omitempty"`
+	// Completion - сообщение о завершении интервью: {{interview_id}}, {{blocks}}, {{completed_blocks}}, {{answers}}
+	Completion string `yaml:"completion,omitempty"`
+}
+
+// welcomePlaceholders и completionPlaceholders - плейсхолдеры, допустимые в сообщениях
+var (
+	welcomePlaceholders    = []string{PlaceholderInterviewID, PlaceholderBlocks, PlaceholderQuestions, PlaceholderMinutes}
+	completionPlaceholders = []string{PlaceholderInterviewID, PlaceholderBlocks, PlaceholderCompletedBlocks, PlaceholderAnswers}
+)
+
+// WelcomeMessage возвращает приветствие интервью interviewID
+func (c *Config) WelcomeMessage(interviewID string) string {
+	text := c.Messages.Welcome
+	if strings.TrimSpace(text) == "" {
+		text = DefaultWelcomeMessage
+	}
+	return strings.NewReplacer(
+		PlaceholderInterviewID, interviewID,
+		PlaceholderBlocks, strconv.Itoa(c.GetTotalBlocks()),
+		PlaceholderQuestions, strconv.Itoa(c.GetQuestionsPerBlock()+c.GetMaxFollowupQuestions()),
+		PlaceholderMinutes, strconv.Itoa(c.GetTotalBlocks()*minutesPerBlock),
+	).Replace(strings.TrimSpace(text))
+}
+
+// CompletionMessage возвращает сообщение о завершении интервью interviewID
+func (c *Config) CompletionMessage(interviewID string, completedBlocks, answers int) string {
+	text := c.Messages.Completion
+	if strings.TrimSpace(text) == "" {
+		text = DefaultCompletionMessage
+	}
+	return strings.NewReplacer(
+		PlaceholderInterviewID, interviewID,
+		PlaceholderBlocks, strconv.Itoa(c.GetTotalBlocks()),
+		PlaceholderCompletedBlocks, strconv.Itoa(completedBlocks),
+		PlaceholderAnswers, strconv.Itoa(answers),
+	).Replace(strings.TrimSpace(text))
+}
+
+// validateMessages проверяет, что в сообщениях используются только известные плейсхолдеры
+func validateMessages(messages Messages) error {
+	if err := validateMessage("messages.welcome", messages.Welcome, welcomePlaceholders); err != nil {
+		return err
+	}
+	return validateMessage("messages.completion", messages.Completion, completionPlaceholders)
+}
+
+// validateMessage проверяет плейсхолдеры одного сообщения
+func validateMessage(path, text string, allowed []string) error {
+	for _, placeholder := range messagePlaceholderPattern.FindAllString(text, -1) {
+		known := false
+		for _, name := range allowed {
+			if placeholder == name {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("%s: неизвестный плейсхолдер %s, допустимы %s", path, placeholder, strings.Join(allowed, ", "))
+		}
+	}
+	return nil
+}
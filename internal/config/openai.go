@@ -11,6 +11,9 @@ type OpenAIConfig struct {
 	Model       string
 	MaxTokens   int
 	Temperature float64
+
+	// ContextTokens - размер контекста модели для извлечения профиля (0 - по модели)
+	ContextTokens int
 }
 
 // LoadOpenAIConfig загружает конфигурацию OpenAI из переменных окружения
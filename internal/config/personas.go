@@ -0,0 +1,68 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// personaIDPattern - ID персоны: латиница в нижнем регистре, цифры, _ и -
+var personaIDPattern = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+// Persona - образ интервьюера: системный промпт и тон генерируемых вопросов
+type Persona struct {
+	ID          string `yaml:"id"`
+	Name        string `yaml:"name"`
+	Description string `yaml:"description,omitempty"`
+	// SystemPrompt заменяет роль интервьюера в начале промпта вопроса
+	SystemPrompt string `yaml:"system_prompt"`
+	// Tone - указания по тону и стилю вопросов
+	Tone []string `yaml:"tone,omitempty"`
+	// Temperature переопределяет температуру генерации вопросов (models.questions)
+	Temperature *float64 `yaml:"temperature,omitempty"`
+}
+
+// Persona возвращает персону шаблона по ID
+func (c *Config) Persona(id string) (Persona, bool) {
+	for _, persona := range c.Personas {
+		if persona.ID == id {
+			return persona, true
+		}
+	}
+	return Persona{}, false
+}
+
+// MatchPersona возвращает ID персоны, если она есть в шаблоне, иначе персону шаблона по умолчанию
+func (c *Config) MatchPersona(id string) string {
+	if _, ok := c.Persona(id); ok {
+		return id
+	}
+	return c.InterviewConfig.Persona
+}
+
+// validatePersonas проверяет персоны шаблона и персону по умолчанию
+func validatePersonas(config *Config) error {
+	seen := make(map[string]bool)
+	for i, persona := range config.Personas {
+		if !personaIDPattern.MatchString(persona.ID) {
+			return fmt.Errorf("personas[%d]: id %q должен состоять из латинских букв, цифр, _ и -", i, persona.ID)
+		}
+		if seen[persona.ID] {
+			return fmt.Errorf("personas: повторяющийся id %q", persona.ID)
+		}
+		seen[persona.ID] = true
+		if persona.Name == "" {
+			return fmt.Errorf("personas.%s: name не должно быть пустым", persona.ID)
+		}
+		if persona.SystemPrompt == "" {
+			return fmt.Errorf("personas.%s: system_prompt не должен быть пустым", persona.ID)
+		}
+		if persona.Temperature != nil && (*persona.Temperature < 0 || *persona.Temperature > 2) {
+			return fmt.Errorf("personas.%s.temperature должна быть от 0 до 2", persona.ID)
+		}
+	}
+
+	if id := config.InterviewConfig.Persona; id != "" && !seen[id] {
+		return fmt.Errorf("persona: персона %q не описана в personas", id)
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultTemplateID - шаблон интервью из основного файла конфигурации
+const DefaultTemplateID = "default"
+
+// templateIDPattern - допустимые ID шаблонов (должны помещаться в deep link Telegram)
+var templateIDPattern = regexp.MustCompile(`^[A-Za-z0-9_]{1,32}$`)
+
+// Templates - набор шаблонов интервью, доступных по ID. Шаблоны можно перезагрузить
+// без перезапуска (Load, Replace): новые интервью получают новые шаблоны, начатые
+// доводятся по ревизии, с которой начаты (Revision).
+type Templates struct {
+	defaultFile string
+	dir         string
+
+	mutex   sync.RWMutex
+	configs map[string]*Config
+	// revisions - все ревизии шаблонов, загруженные процессом
+	revisions map[string]*Config
+}
+
+// LoadTemplates загружает шаблон по умолчанию из defaultFile и дополнительные
+// шаблоны из каталога dir (ID шаблона - имя файла без расширения).
+// Отсутствующий каталог не считается ошибкой.
+func LoadTemplates(defaultFile, dir string) (*Templates, error) {
+	files, err := TemplateFiles(defaultFile, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	templates := &Templates{
+		defaultFile: defaultFile,
+		dir:         dir,
+		configs:     make(map[string]*Config, len(files)),
+		revisions:   make(map[string]*Config, len(files)),
+	}
+	for id, file := range files {
+		cfg, err := Load(file)
+		if err != nil {
+			if id == DefaultTemplateID {
+				return nil, err
+			}
+			return nil, fmt.Errorf("шаблон %s: %w", id, err)
+		}
+		templates.configs[id] = cfg
+		templates.revisions[cfg.Revision()] = cfg
+	}
+
+	return templates, nil
+}
+
+// Load заново загружает шаблоны из тех же файлов, не заменяя текущие: новый набор
+// проверяется целиком и применяется через Replace
+func (t *Templates) Load() (*Templates, error) {
+	return LoadTemplates(t.defaultFile, t.dir)
+}
+
+// Replace заменяет шаблоны набором next. Прежние ревизии остаются доступны через Revision.
+func (t *Templates) Replace(next *Templates) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.configs = next.configs
+	for _, cfg := range next.configs {
+		t.revisions[cfg.Revision()] = cfg
+	}
+}
+
+// Signature описывает файлы шаблонов и их переводов (имя, размер, время изменения)
+// для обнаружения правок
+func (t *Templates) Signature() (string, error) {
+	files, err := TemplateFiles(t.defaultFile, t.dir)
+	if err != nil {
+		return "", err
+	}
+	ids := make([]string, 0, len(files))
+	for id := range files {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var signature strings.Builder
+	for _, id := range ids {
+		translations, err := translationFiles(files[id])
+		if err != nil {
+			return "", err
+		}
+		for _, file := range append([]string{files[id]}, translations...) {
+			info, err := os.Stat(file)
+			if err != nil {
+				return "", err
+			}
+			signature.WriteString(fmt.Sprintf("%s:%d:%d;", file, info.Size(), info.ModTime().UnixNano()))
+		}
+	}
+	return signature.String(), nil
+}
+
+// Dirs возвращает каталоги файлов шаблонов: переводы лежат рядом с шаблонами, поэтому
+// за правками достаточно следить в этих каталогах
+func (t *Templates) Dirs() []string {
+	dirs := []string{filepath.Dir(t.defaultFile)}
+	if t.dir != "" && filepath.Clean(t.dir) != dirs[0] {
+		dirs = append(dirs, filepath.Clean(t.dir))
+	}
+	return dirs
+}
+
+// TemplateFiles возвращает файлы шаблонов по ID: defaultFile и *.yaml из каталога dir
+// без файлов переводов
+func TemplateFiles(defaultFile, dir string) (map[string]string, error) {
+	files := map[string]string{DefaultTemplateID: defaultFile}
+	if dir == "" {
+		return files, nil
+	}
+
+	matches, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка поиска шаблонов в %s: %w", dir, err)
+	}
+
+	for _, file := range matches {
+		if translationFilePattern.MatchString(filepath.Base(file)) {
+			// Перевод загружается вместе со своим шаблоном
+			continue
+		}
+		id := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+		if !templateIDPattern.MatchString(id) {
+			return nil, fmt.Errorf("недопустимый ID шаблона %q: разрешены латинские буквы, цифры и _", id)
+		}
+		if _, exists := files[id]; exists {
+			return nil, fmt.Errorf("шаблон %q объявлен повторно", id)
+		}
+		files[id] = file
+	}
+
+	return files, nil
+}
+
+// Get возвращает шаблон по ID; пустой ID означает шаблон по умолчанию
+func (t *Templates) Get(id string) (*Config, bool) {
+	if id == "" {
+		id = DefaultTemplateID
+	}
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	cfg, ok := t.configs[id]
+	return cfg, ok
+}
+
+// Revision возвращает шаблон по ревизии (Config.Revision), в том числе замененный перезагрузкой
+func (t *Templates) Revision(revision string) (*Config, bool) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	cfg, ok := t.revisions[revision]
+	return cfg, ok
+}
+
+// Default возвращает шаблон по умолчанию
+func (t *Templates) Default() *Config {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	return t.configs[DefaultTemplateID]
+}
+
+// IDs возвращает отсортированный список ID шаблонов
+func (t *Templates) IDs() []string {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	ids := make([]string, 0, len(t.configs))
+	for id := range t.configs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
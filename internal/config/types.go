@@ -1,11 +1,86 @@
 package config
 
+import "time"
+
 // Config представляет конфигурацию интервью
 type Config struct {
-	InterviewConfig  InterviewConfig  `yaml:"interview_config"`
-	Blocks           []Block          `yaml:"blocks"`
-	ProfileFields    []string         `yaml:"profile_fields"`
-	SummaryStructure SummaryStructure `yaml:"summary_structure"`
+	InterviewConfig  InterviewConfig     `yaml:"interview_config"`
+	Blocks           []Block             `yaml:"blocks"`
+	ProfileFields    []string            `yaml:"profile_fields"`
+	SummaryStructure SummaryStructure    `yaml:"summary_structure"`
+	Flags            map[string][]string `yaml:"flags"`
+	// OpenAI задает модель и параметры генерации по этапам конвейера (interview, summary, extraction, matching)
+	OpenAI OpenAIStages `yaml:"openai,omitempty"`
+	// Models переопределяет параметры этапа для отдельных сценариев (ключи - UseCase*)
+	Models map[string]ModelSettings `yaml:"models,omitempty"`
+	// Validation - правила проверки ответов пользователя
+	Validation AnswerRules `yaml:"validation,omitempty"`
+	// Personas - образы интервьюера на выбор (interview_config.persona и /persona)
+	Personas []Persona `yaml:"personas,omitempty"`
+	// Messages - приветствие и сообщение о завершении интервью (пусто - тексты по умолчанию)
+	Messages Messages `yaml:"messages,omitempty"`
+
+	// translations - переводы шаблона по языкам (файлы <шаблон>.<язык>.yaml)
+	translations map[string]*Config
+	// revision - контрольная сумма файлов шаблона (Revision)
+	revision string
+}
+
+// Сценарии обращения к модели, для которых можно переопределить параметры
+const (
+	UseCaseBlock       = "block"        // проведение блока целиком
+	UseCaseQuestions   = "questions"    // уточняющие вопросы в блоке
+	UseCaseSummary     = "summary"      // саммари блока
+	UseCaseAnswerCheck = "answer_check" // оценка информативности ответа
+	UseCaseCoverage    = "coverage"     // проверка раскрытия целей блока
+	UseCaseProfileQA   = "profile_qa"   // вопросы о профиле (/ask)
+	UseCaseExtraction  = "extraction"   // извлечение профиля
+	UseCaseMatching    = "matching"     // подбор архетипа и оценка по рубрике роли
+)
+
+// UseCases - все сценарии в порядке описания
+var UseCases = []string{UseCaseBlock, UseCaseQuestions, UseCaseSummary, UseCaseAnswerCheck, UseCaseCoverage, UseCaseProfileQA, UseCaseExtraction, UseCaseMatching}
+
+// OpenAIStages - параметры модели по этапам конвейера; незаданные поля берутся из глобальных настроек (env)
+type OpenAIStages struct {
+	Interview  ModelSettings `yaml:"interview,omitempty"`  // вопросы интервьюера, проверка ответов, /ask
+	Summary    ModelSettings `yaml:"summary,omitempty"`    // саммари блоков и проверка раскрытия целей
+	Extraction ModelSettings `yaml:"extraction,omitempty"` // извлечение профиля и расширенный анализ
+	Matching   ModelSettings `yaml:"matching,omitempty"`   // подбор архетипа и оценка по рубрике роли
+}
+
+// Stage возвращает параметры этапа, к которому относится сценарий useCase
+func (o OpenAIStages) Stage(useCase string) ModelSettings {
+	switch useCase {
+	case UseCaseSummary, UseCaseCoverage:
+		return o.Summary
+	case UseCaseExtraction:
+		return o.Extraction
+	case UseCaseMatching:
+		return o.Matching
+	}
+	return o.Interview
+}
+
+// ModelSettings - переопределения для сценария; незаданные поля берутся из глобальных настроек (env)
+type ModelSettings struct {
+	Model       string   `yaml:"model,omitempty"`
+	Temperature *float64 `yaml:"temperature,omitempty"`
+	MaxTokens   int      `yaml:"max_tokens,omitempty"`
+}
+
+// Override возвращает параметры, в которых заданные поля override заменяют поля s
+func (s ModelSettings) Override(override ModelSettings) ModelSettings {
+	if override.Model != "" {
+		s.Model = override.Model
+	}
+	if override.Temperature != nil {
+		s.Temperature = override.Temperature
+	}
+	if override.MaxTokens > 0 {
+		s.MaxTokens = override.MaxTokens
+	}
+	return s
 }
 
 // InterviewConfig содержит общие настройки интервью
@@ -13,6 +88,23 @@ type InterviewConfig struct {
 	TotalBlocks          int `yaml:"total_blocks"`
 	QuestionsPerBlock    int `yaml:"questions_per_block"`
 	MaxFollowupQuestions int `yaml:"max_followup_questions"`
+	// BlockTimeLimitMinutes - мягкий лимит времени на блок по умолчанию (0 - без лимита)
+	BlockTimeLimitMinutes int `yaml:"block_time_limit_minutes,omitempty"`
+	// MinAnswerLength - ответы короче этого числа символов получают уточняющий вопрос (0 - не проверять)
+	MinAnswerLength int `yaml:"min_answer_length,omitempty"`
+	// CheckAnswerQuality - дополнительно просить модель оценить информативность ответа
+	CheckAnswerQuality bool `yaml:"check_answer_quality,omitempty"`
+	// ArchetypeCatalog - каталог архетипов для расширенного анализа (пустой - PREMIUM_ARCHETYPE_CATALOG)
+	ArchetypeCatalog string `yaml:"archetype_catalog,omitempty"`
+	// Rubric - рубрика роли для отчета рекрутеру (пустая - OUTCOME_RUBRIC)
+	Rubric string `yaml:"rubric,omitempty"`
+	// AsyncSummaries - следующий блок начинается сразу, саммари блоков создаются в фоне
+	// и дожидаются перед сохранением результата
+	AsyncSummaries bool `yaml:"async_summaries,omitempty"`
+	// Language - язык вопросов шаблона (по умолчанию ru); переводы лежат в <шаблон>.<язык>.yaml
+	Language string `yaml:"language,omitempty"`
+	// Persona - ID персоны интервьюера по умолчанию (пусто - базовая роль интервьюера)
+	Persona string `yaml:"persona,omitempty"`
 }
 
 // Block представляет один блок интервью
@@ -22,7 +114,21 @@ type Block struct {
 	Title         string   `yaml:"title"`
 	ContextPrompt string   `yaml:"context_prompt"`
 	FocusAreas    []string `yaml:"focus_areas"`
-	Questions     []string `yaml:"questions"`
+	// CoverageGoals - темы, которые должны быть раскрыты в блоке. После вопросов из конфигурации
+	// уточняющие вопросы задаются только о нераскрытых темах, а блок завершается, как только раскрыты все
+	CoverageGoals []string `yaml:"coverage_goals,omitempty"`
+	// Questions - вопросы блока; у вопроса может быть несколько вариантов формулировки
+	Questions []QuestionSlot `yaml:"questions"`
+	// Condition - необязательное условие, при ложности которого блок пропускается
+	Condition string `yaml:"condition,omitempty"`
+	// TimeLimitMinutes переопределяет мягкий лимит времени для блока
+	TimeLimitMinutes int `yaml:"time_limit_minutes,omitempty"`
+	// Validation переопределяет длину ответов в блоке и для отдельных вопросов
+	Validation *BlockValidation `yaml:"validation,omitempty"`
+	// Optional - необязательный блок: перед ним пользователь решает, хочет ли говорить на эту тему.
+	// OptInQuestion - вопрос об этом (пусто - стандартный вопрос с названием блока)
+	Optional      bool   `yaml:"optional,omitempty"`
+	OptInQuestion string `yaml:"opt_in_question,omitempty"`
 }
 
 // SummaryStructure определяет структуру саммари
@@ -36,6 +142,27 @@ type SummaryStructure struct {
 	SensitiveTopics    []string `yaml:"sensitive_topics"`
 }
 
+// SummarySection - раздел структурированного саммари
+type SummarySection struct {
+	Key     string   // ключ раздела в ответе модели
+	Title   string   // описание раздела на русском
+	TitleEN string   // описание раздела на английском
+	Hints   []string // на что обратить внимание (из конфигурации)
+}
+
+// Sections возвращает разделы саммари в фиксированном порядке вместе с подсказками из конфигурации
+func (s SummaryStructure) Sections() []SummarySection {
+	return []SummarySection{
+		{Key: "key_facts", Title: "Ключевые факты о человеке", TitleEN: "Key facts about the person", Hints: s.KeyFacts},
+		{Key: "important_themes", Title: "Важные темы", TitleEN: "Important themes", Hints: s.ImportantThemes},
+		{Key: "emotional_markers", Title: "Эмоциональные реакции", TitleEN: "Emotional reactions", Hints: s.EmotionalMarkers},
+		{Key: "behavioral_patterns", Title: "Паттерны поведения", TitleEN: "Behavior patterns", Hints: s.BehavioralPatterns},
+		{Key: "values_beliefs", Title: "Ценности и убеждения", TitleEN: "Values and beliefs", Hints: s.ValuesBeliefs},
+		{Key: "priorities", Title: "Приоритеты", TitleEN: "Priorities", Hints: s.Priorities},
+		{Key: "sensitive_topics", Title: "Чувствительные темы", TitleEN: "Sensitive topics", Hints: s.SensitiveTopics},
+	}
+}
+
 // Методы для удобного доступа к конфигурации
 func (c *Config) GetTotalBlocks() int {
 	return c.InterviewConfig.TotalBlocks
@@ -48,3 +175,22 @@ func (c *Config) GetQuestionsPerBlock() int {
 func (c *Config) GetMaxFollowupQuestions() int {
 	return c.InterviewConfig.MaxFollowupQuestions
 }
+
+// ModelFor возвращает параметры модели для сценария: параметры его этапа из секции openai
+// с переопределениями из секции models (пустые поля - глобальные настройки)
+func (c *Config) ModelFor(useCase string) ModelSettings {
+	return c.OpenAI.Stage(useCase).Override(c.Models[useCase])
+}
+
+func (c *Config) GetMinAnswerLength() int {
+	return c.InterviewConfig.MinAnswerLength
+}
+
+// BlockTimeLimit возвращает мягкий лимит времени на блок (0 - без лимита)
+func (c *Config) BlockTimeLimit(block Block) time.Duration {
+	minutes := c.InterviewConfig.BlockTimeLimitMinutes
+	if block.TimeLimitMinutes > 0 {
+		minutes = block.TimeLimitMinutes
+	}
+	return time.Duration(minutes) * time.Minute
+}
@@ -0,0 +1,158 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultMaxAnswerLength - максимальная длина ответа по умолчанию (лимит сообщения Telegram с запасом)
+const DefaultMaxAnswerLength = 4000
+
+// DefaultMaxRepeatedShare - доля одного символа, после которой ответ считается спамом
+const DefaultMaxRepeatedShare = 0.8
+
+// Правила проверки ответов; имена используются как ключи сообщений (validation.messages)
+const (
+	RuleMinLength  = "min_length"
+	RuleMaxLength  = "max_length"
+	RuleRepetition = "repetition"
+	RuleProfanity  = "profanity"
+	RuleLinks      = "links"
+	RuleLanguage   = "language"
+)
+
+// AnswerRuleNames - все правила в порядке проверки
+var AnswerRuleNames = []string{RuleMaxLength, RuleMinLength, RuleRepetition, RuleLinks, RuleProfanity, RuleLanguage}
+
+// AnswerRules - правила проверки ответов пользователя (секция validation шаблона).
+// Ответ, нарушивший правило, не принимается, пользователь получает сообщение правила.
+type AnswerRules struct {
+	MinLength int `yaml:"min_length,omitempty"`
+	// MaxLength - максимальная длина ответа в символах (0 - DefaultMaxAnswerLength)
+	MaxLength int `yaml:"max_length,omitempty"`
+	// MaxRepeatedShare - наибольшая доля одного символа в ответе (0 - DefaultMaxRepeatedShare)
+	MaxRepeatedShare float64 `yaml:"max_repeated_share,omitempty"`
+	ProfanityFilter  bool    `yaml:"profanity_filter,omitempty"`
+	// ProfanityWords дополняет встроенный список; слово с * на конце проверяется как начало слова
+	ProfanityWords []string `yaml:"profanity_words,omitempty"`
+	BlockLinks     bool     `yaml:"block_links,omitempty"`
+	// Languages - допустимые языки ответов (пусто - любые); определяются русский и английский
+	Languages []string `yaml:"languages,omitempty"`
+	// Messages переопределяет сообщения правил; {min}, {max} и {languages} заменяются значениями
+	Messages map[string]string `yaml:"messages,omitempty"`
+}
+
+// LengthRules - ограничения длины ответа блока или отдельного вопроса (0 - как у шаблона)
+type LengthRules struct {
+	MinLength int `yaml:"min_length,omitempty"`
+	MaxLength int `yaml:"max_length,omitempty"`
+}
+
+// BlockValidation переопределяет длину ответов в блоке и для отдельных вопросов (по номеру с 1)
+type BlockValidation struct {
+	LengthRules `yaml:",inline"`
+	Questions   map[int]LengthRules `yaml:"questions,omitempty"`
+}
+
+// AnswerRules возвращает правила для ответа на вопрос question (с нуля) блока block (с 1).
+// block 0 - правила шаблона без переопределений блоков.
+func (c *Config) AnswerRules(block, question int) AnswerRules {
+	rules := c.Validation
+	if rules.MaxLength == 0 {
+		rules.MaxLength = DefaultMaxAnswerLength
+	}
+	if rules.MaxRepeatedShare == 0 {
+		rules.MaxRepeatedShare = DefaultMaxRepeatedShare
+	}
+	if block < 1 || block > len(c.Blocks) {
+		return rules
+	}
+	if c.Blocks[block-1].ChoiceFor(question) != nil {
+		// Вариант ответа задан шаблоном: длина и язык не проверяются
+		rules.MinLength = 0
+		rules.Languages = nil
+	}
+	switch c.Blocks[block-1].AnswerTypeFor(question) {
+	case AnswerNumber, AnswerDate:
+		// Число и дату можно записать цифрами: язык не проверяется
+		rules.MinLength = 0
+		rules.Languages = nil
+	case AnswerList:
+		rules.MinLength = 0
+	}
+	if c.Blocks[block-1].Validation == nil {
+		return rules
+	}
+
+	validation := c.Blocks[block-1].Validation
+	rules.applyLength(validation.LengthRules)
+	if override, ok := validation.Questions[question+1]; ok {
+		rules.applyLength(override)
+	}
+	return rules
+}
+
+func (r *AnswerRules) applyLength(length LengthRules) {
+	if length.MinLength > 0 {
+		r.MinLength = length.MinLength
+	}
+	if length.MaxLength > 0 {
+		r.MaxLength = length.MaxLength
+	}
+}
+
+// validateAnswerRules проверяет секцию validation и переопределения длины в блоках
+func validateAnswerRules(config *Config) error {
+	rules := config.Validation
+	if err := validateLength("validation", LengthRules{MinLength: rules.MinLength, MaxLength: rules.MaxLength}); err != nil {
+		return err
+	}
+	if rules.MaxRepeatedShare < 0 || rules.MaxRepeatedShare > 1 {
+		return fmt.Errorf("validation.max_repeated_share должна быть от 0 до 1")
+	}
+	for _, lang := range rules.Languages {
+		if !languageCodePattern.MatchString(lang) {
+			return fmt.Errorf("validation.languages: %q не является двухбуквенным кодом языка", lang)
+		}
+	}
+	for rule := range rules.Messages {
+		known := false
+		for _, name := range AnswerRuleNames {
+			if rule == name {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("validation.messages: неизвестное правило %q (доступны: %s)", rule, strings.Join(AnswerRuleNames, ", "))
+		}
+	}
+
+	for _, block := range config.Blocks {
+		if block.Validation == nil {
+			continue
+		}
+		if err := validateLength(fmt.Sprintf("блок %d: validation", block.ID), block.Validation.LengthRules); err != nil {
+			return err
+		}
+		for question, length := range block.Validation.Questions {
+			if question < 1 || question > len(block.Questions) {
+				return fmt.Errorf("блок %d: validation.questions ссылается на вопрос %d, в блоке %d вопросов", block.ID, question, len(block.Questions))
+			}
+			if err := validateLength(fmt.Sprintf("блок %d: validation.questions.%d", block.ID, question), length); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func validateLength(section string, length LengthRules) error {
+	if length.MinLength < 0 || length.MaxLength < 0 {
+		return fmt.Errorf("%s: длина ответа не может быть отрицательной", section)
+	}
+	if length.MaxLength > 0 && length.MinLength > length.MaxLength {
+		return fmt.Errorf("%s: min_length (%d) больше max_length (%d)", section, length.MinLength, length.MaxLength)
+	}
+	return nil
+}
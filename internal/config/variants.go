@@ -0,0 +1,89 @@
+package config
+
+import (
+	"fmt"
+	"hash/fnv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// QuestionSlot - вопрос блока: одна формулировка или несколько вариантов для A/B теста.
+// В YAML записывается строкой или списком строк: questions: ["вопрос", [вариант1, вариант2]],
+// а вопрос с выбором ответа или типом ответа - объектом {text, type: choice, options} (см. choice.go)
+type QuestionSlot struct {
+	Variants []string
+	Choice   *Choice
+	// AnswerType - ожидаемый тип ответа открытого вопроса (см. answer_type.go); пусто - свободный ответ
+	AnswerType string
+	// ProfileField - поле профиля, о котором открытый вопрос; если поле известно из резюме,
+	// вопрос задается как просьба подтвердить факт
+	ProfileField string
+}
+
+// UnmarshalYAML принимает строку, список вариантов или объект вопроса с выбором ответа
+func (s *QuestionSlot) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.MappingNode {
+		return s.unmarshalChoice(value)
+	}
+	variants, err := decodeVariants(value)
+	if err != nil {
+		return err
+	}
+	*s = QuestionSlot{Variants: variants}
+	return nil
+}
+
+// decodeVariants читает формулировку вопроса: строку или список вариантов
+func decodeVariants(value *yaml.Node) ([]string, error) {
+	switch value.Kind {
+	case yaml.ScalarNode:
+		var text string
+		if err := value.Decode(&text); err != nil {
+			return nil, err
+		}
+		return []string{text}, nil
+	case yaml.SequenceNode:
+		var variants []string
+		if err := value.Decode(&variants); err != nil {
+			return nil, err
+		}
+		return variants, nil
+	default:
+		return nil, fmt.Errorf("строка %d: вопрос должен быть строкой или списком вариантов", value.Line)
+	}
+}
+
+// HasVariants сообщает, участвует ли вопрос в A/B тесте
+func (s QuestionSlot) HasVariants() bool {
+	return len(s.Variants) > 1
+}
+
+// Pick выбирает вариант вопроса для пользователя. Выбор детерминирован:
+// один и тот же пользователь всегда получает один и тот же вариант, в том числе после перезапуска
+func (s QuestionSlot) Pick(seed string) (string, int) {
+	if len(s.Variants) <= 1 {
+		return s.Variants[0], 0
+	}
+	hash := fnv.New32a()
+	hash.Write([]byte(seed))
+	index := int(hash.Sum32() % uint32(len(s.Variants)))
+	return s.Variants[index], index
+}
+
+// Question возвращает формулировку вопроса slot блока для пользователя и ID варианта
+// вида b2.q1.v2 (пустой, если у вопроса одна формулировка)
+func (b Block) Question(slot int, userID int64) (string, string) {
+	text, variant := b.Questions[slot].Pick(fmt.Sprintf("%d:%s:%d", userID, b.Name, slot))
+	if !b.Questions[slot].HasVariants() {
+		return text, ""
+	}
+	return text, fmt.Sprintf("b%d.q%d.v%d", b.ID, slot+1, variant+1)
+}
+
+// ProfileFieldFor возвращает поле профиля вопроса question (с нуля) блока; пусто - поле не указано
+func (b Block) ProfileFieldFor(question int) string {
+	if question < 0 || question >= len(b.Questions) {
+		return ""
+	}
+	return b.Questions[question].ProfileField
+}
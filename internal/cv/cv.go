@@ -0,0 +1,65 @@
+package cv
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"unicode"
+)
+
+// MaxTextRunes - столько символов текста резюме передается модели; остальное отбрасывается
+const MaxTextRunes = 20000
+
+var (
+	// ErrUnsupportedFormat - файл не PDF и не DOCX
+	ErrUnsupportedFormat = errors.New("поддерживаются только резюме в PDF и DOCX")
+	// ErrNoText - в файле нет извлекаемого текста (например, PDF из отсканированных страниц)
+	ErrNoText = errors.New("в файле не найден текст")
+)
+
+// Сигнатуры форматов: DOCX - zip-архив
+var (
+	pdfSignature = []byte("%PDF-")
+	zipSignature = []byte("PK\x03\x04")
+)
+
+// ExtractText извлекает текст резюме из PDF или DOCX; формат определяется по содержимому файла.
+// Пустые строки и лишние пробелы убираются, текст обрезается до MaxTextRunes символов.
+func ExtractText(data []byte) (string, error) {
+	var (
+		text string
+		err  error
+	)
+	switch {
+	case bytes.HasPrefix(data, pdfSignature):
+		text, err = pdfText(data)
+	case bytes.HasPrefix(data, zipSignature):
+		text, err = docxText(data)
+	default:
+		return "", ErrUnsupportedFormat
+	}
+	if err != nil {
+		return "", err
+	}
+
+	text = normalizeText(text)
+	if text == "" {
+		return "", ErrNoText
+	}
+	if runes := []rune(text); len(runes) > MaxTextRunes {
+		text = string(runes[:MaxTextRunes])
+	}
+	return text, nil
+}
+
+// normalizeText схлопывает пробелы внутри строк и убирает пустые строки
+func normalizeText(text string) string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.Join(strings.FieldsFunc(line, unicode.IsSpace), " ")
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
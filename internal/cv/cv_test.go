@@ -0,0 +1,287 @@
+package cv
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// testPDF собирает PDF из тел объектов: объект i+1 - objects[i], пустое тело - объекта нет.
+// Таблица xref не нужна - parsePDF находит объекты по заголовкам «N 0 obj»
+func testPDF(objects ...string) []byte {
+	var data bytes.Buffer
+	data.WriteString("%PDF-1.4\n")
+	for i, object := range objects {
+		if object == "" {
+			continue
+		}
+		fmt.Fprintf(&data, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	data.WriteString("trailer\n<< /Root 1 0 R >>\n%%EOF\n")
+	return data.Bytes()
+}
+
+// testStream - объект потока с содержимым content и записанной /Length
+func testStream(dict, content string) string {
+	return fmt.Sprintf("<< %s /Length %d >>\nstream\n%s\nendstream", dict, len(content), content)
+}
+
+// testPages - каталог и дерево из одной страницы: объекты 1-3; шрифт F1 - объект 4, содержимое - объект 5
+const testPages = "<< /Type /Catalog /Pages 2 0 R >>\x00" +
+	"<< /Type /Pages /Kids [3 0 R] /Count 1 >>\x00" +
+	"<< /Type /Page /Parent 2 0 R /Resources << /Font << /F1 4 0 R >> >> /Contents 5 0 R >>"
+
+// pagesWith возвращает объекты testPages, шрифт font (пустой - объекта нет) и следующие объекты
+func pagesWith(font string, content ...string) []string {
+	return append(append(strings.Split(testPages, "\x00"), font), content...)
+}
+
+// testDocx собирает архив DOCX с файлами files (имя - содержимое)
+func testDocx(t testing.TB, files map[string]string) []byte {
+	t.Helper()
+	var data bytes.Buffer
+	archive := zip.NewWriter(&data)
+	for name, content := range files {
+		file, err := archive.Create(name)
+		if err != nil {
+			t.Fatalf("не удалось создать %s в архиве: %v", name, err)
+		}
+		if _, err := file.Write([]byte(content)); err != nil {
+			t.Fatalf("не удалось записать %s в архив: %v", name, err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatalf("не удалось закрыть архив: %v", err)
+	}
+	return data.Bytes()
+}
+
+// testCMap - ToUnicode шрифта с двухбайтовыми кодами 0001-0006: «Привет»
+const testCMap = `/CIDInit /ProcSet findresource begin
+begincmap
+1 begincodespacerange <0000> <FFFF> endcodespacerange
+4 beginbfchar <0001> <041F> <0004> <0432> <0005> <0435> <0006> <0442> endbfchar
+1 beginbfrange <0002> <0003> [<0440> <0438>] endbfrange
+endcmap`
+
+// TestPDFText проверяет извлечение текста из корректных файлов
+func TestPDFText(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{
+			name: "несжатый поток",
+			data: testPDF(pagesWith("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+				testStream("", "BT /F1 12 Tf (Hello) Tj 0 -14 Td [(wor) -20 (ld)] TJ ET"))...),
+			want: "Hello\nworld",
+		},
+		{
+			name: "шрифт с ToUnicode",
+			data: testPDF(pagesWith("<< /Type /Font /Subtype /Type0 /ToUnicode 6 0 R >>",
+				testStream("", "BT /F1 12 Tf <000100020003000400050006> Tj ET"),
+				testStream("", testCMap))...),
+			want: "Привет",
+		},
+		{
+			name: "объект из потока объектов",
+			// Шрифт - объект 4 из потока объектов 6
+			data: testPDF(pagesWith("",
+				testStream("", "BT /F1 12 Tf (Text) Tj ET"),
+				testStream("/Type /ObjStm /N 1 /First 4", "4 0 << /Type /Font /Subtype /Type1 >>"))...),
+			want: "Text",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExtractText(tt.data)
+			if err != nil {
+				t.Fatalf("ошибка извлечения текста: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("текст %q, ожидался %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestPDFTextMalformed проверяет, что поврежденные длины и смещения не приводят к панике
+// и не выводят за границы файла
+func TestPDFTextMalformed(t *testing.T) {
+	content := "BT /F1 12 Tf (Hello) Tj ET"
+	font := "<< /Type /Font /Subtype /Type1 >>"
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{
+			name: "огромная Length",
+			data: testPDF(pagesWith(font,
+				"<< /Length 1e19 >>\nstream\n"+content+"\nendstream")...),
+		},
+		{
+			name: "отрицательная Length",
+			data: testPDF(pagesWith(font,
+				"<< /Length -5 >>\nstream\n"+content+"\nendstream")...),
+		},
+		{
+			name: "Length за концом файла",
+			data: testPDF(pagesWith(font,
+				"<< /Length 100000 >>\nstream\n"+content+"\nendstream")...),
+		},
+		{
+			name: "отрицательная First",
+			data: testPDF(pagesWith(font, testStream("", content),
+				testStream("/Type /ObjStm /N 1 /First -5", "7 0 << >>"))...),
+		},
+		{
+			name: "огромная First",
+			data: testPDF(pagesWith(font, testStream("", content),
+				testStream("/Type /ObjStm /N 1 /First 1e19", "7 0 << >>"))...),
+		},
+		{
+			name: "смещение за концом потока объектов",
+			data: testPDF(pagesWith(font, testStream("", content),
+				testStream("/Type /ObjStm /N 2 /First 12", "7 0 8 1e19\n<< >>"))...),
+		},
+		{
+			name: "отрицательное смещение в потоке объектов",
+			data: testPDF(pagesWith(font, testStream("", content),
+				testStream("/Type /ObjStm /N 1 /First 6", "7 -100 << >>"))...),
+		},
+		{
+			// Незакрытая hex-строка в конце файла не должна выводить лексер за границу данных
+			name: "незакрытая hex-строка",
+			data: []byte("%PDF-1.4\n1 0 obj\n<< /Type /Catalog /Key <00"),
+		},
+		{
+			name: "огромное N",
+			data: testPDF(pagesWith(font, testStream("", content),
+				testStream("/Type /ObjStm /N 1e19 /First 4", "7 0 << >>"))...),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Текст может не извлечься, но разбор должен завершиться без паники
+			if _, err := pdfText(tt.data); err != nil {
+				t.Logf("ошибка извлечения: %v", err)
+			}
+		})
+	}
+}
+
+// TestDocxText проверяет текст абзацев, табуляции и переносы строк DOCX
+func TestDocxText(t *testing.T) {
+	const body = `<?xml version="1.0" encoding="UTF-8"?>
+<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>
+<w:p><w:r><w:t>Иван</w:t></w:r><w:r><w:t xml:space="preserve"> Петров</w:t></w:r></w:p>
+<w:p><w:r><w:t>Go</w:t><w:tab/><w:t>5 лет</w:t><w:br/><w:t>SQL</w:t></w:r></w:p>
+</w:body></w:document>`
+
+	got, err := ExtractText(testDocx(t, map[string]string{docxDocument: body}))
+	if err != nil {
+		t.Fatalf("ошибка извлечения текста: %v", err)
+	}
+	if want := "Иван Петров\nGo 5 лет\nSQL"; got != want {
+		t.Errorf("текст %q, ожидался %q", got, want)
+	}
+}
+
+// TestDocxTextMalformed проверяет ошибки для архивов без документа и с поврежденным XML
+func TestDocxTextMalformed(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "не архив", data: []byte("PK\x03\x04 поврежденный архив")},
+		{name: "нет document.xml", data: testDocx(t, map[string]string{"word/styles.xml": "<styles/>"})},
+		{name: "поврежденный XML", data: testDocx(t, map[string]string{docxDocument: "<w:document><w:p>"})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := docxText(tt.data); err == nil {
+				t.Error("ожидалась ошибка чтения DOCX")
+			}
+		})
+	}
+}
+
+// TestParseCMap проверяет bfchar, bfrange со строкой и массивом и отбрасывание некорректных диапазонов
+func TestParseCMap(t *testing.T) {
+	tests := []struct {
+		name  string
+		cmap  string
+		codes []byte
+		want  string
+	}{
+		{name: "bfchar и bfrange", cmap: testCMap, codes: []byte{0, 1, 0, 2, 0, 3, 0, 4, 0, 5, 0, 6}, want: "Привет"},
+		{
+			name:  "однобайтовые коды",
+			cmap:  "1 begincodespacerange <00> <FF> endcodespacerange 1 beginbfrange <41> <43> <0061> endbfrange",
+			codes: []byte("ABC"),
+			want:  "abc",
+		},
+		{
+			// Диапазон у верхней границы uint32: перебор кодов не должен переполниться и зациклиться
+			name:  "диапазон до FFFFFFFF",
+			cmap:  "1 beginbfrange <FFFFFFFE> <FFFFFFFF> <0078> endbfrange 1 beginbfchar <0001> <0079> endbfchar",
+			codes: []byte{0, 1},
+			want:  "y",
+		},
+		{
+			name:  "перевернутый и слишком большой диапазоны",
+			cmap:  "2 beginbfrange <0010> <0001> <0078> <0000> <FFFFFF> <0078> endbfrange 1 beginbfchar <0001> <0079> endbfchar",
+			codes: []byte{0, 1, 0, 2},
+			want:  "y",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmap := parseCMap([]byte(tt.cmap))
+			if cmap == nil {
+				t.Fatal("таблица ToUnicode не прочитана")
+			}
+			if got := cmap.decode(tt.codes); got != tt.want {
+				t.Errorf("текст %q, ожидался %q", got, tt.want)
+			}
+		})
+	}
+
+	if cmap := parseCMap([]byte("1 beginbfrange <FFFFFFFE> <FFFFFFFF> <0078> endbfrange")); cmap == nil || len(cmap.chars) != 2 {
+		t.Errorf("диапазон до FFFFFFFF: ожидалось 2 кода, таблица %+v", cmap)
+	}
+}
+
+// FuzzPDFText проверяет, что разбор произвольного PDF не паникует
+func FuzzPDFText(f *testing.F) {
+	f.Add(testPDF(pagesWith("<< /Type /Font >>", testStream("", "BT /F1 12 Tf (Hello) Tj ET"))...))
+	f.Add(testPDF(pagesWith("<< /Type /Font /ToUnicode 6 0 R >>",
+		testStream("", "BT /F1 12 Tf <0001> Tj ET"), testStream("", testCMap))...))
+	f.Add(testPDF(pagesWith("<< >>", testStream("/Type /ObjStm /N 2 /First 8", "7 0 8 3 << >> 42"))...))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		_, _ = pdfText(data)
+	})
+}
+
+// FuzzDocxText проверяет, что разбор произвольного document.xml не паникует
+func FuzzDocxText(f *testing.F) {
+	f.Add(`<w:document><w:body><w:p><w:r><w:t>Текст</w:t><w:tab/><w:br/></w:r></w:p></w:body></w:document>`)
+	f.Add(`<w:p><w:t>незакрытый`)
+	f.Fuzz(func(t *testing.T, body string) {
+		_, _ = docxText(testDocx(t, map[string]string{docxDocument: body}))
+	})
+}
+
+// FuzzParseCMap проверяет, что разбор произвольной таблицы ToUnicode не паникует и не зацикливается
+func FuzzParseCMap(f *testing.F) {
+	f.Add([]byte(testCMap))
+	f.Add([]byte("1 beginbfrange <FFFFFFFE> <FFFFFFFF> [<0078> <0079>] endbfrange"))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if cmap := parseCMap(data); cmap != nil {
+			_ = cmap.decode(data)
+		}
+	})
+}
@@ -0,0 +1,77 @@
+package cv
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// docxDocument - основной текст документа Word внутри архива
+const docxDocument = "word/document.xml"
+
+// maxDocxXMLBytes - предел распакованного document.xml (защита от zip-бомб)
+const maxDocxXMLBytes = 32 << 20
+
+// docxText извлекает текст абзацев DOCX: w:t - текст, w:tab - табуляция, w:br и конец w:p - перенос строки
+func docxText(data []byte) (string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", fmt.Errorf("ошибка чтения DOCX: %w", err)
+	}
+
+	var document *zip.File
+	for _, file := range archive.File {
+		if file.Name == docxDocument {
+			document = file
+			break
+		}
+	}
+	if document == nil {
+		return "", ErrUnsupportedFormat
+	}
+	reader, err := document.Open()
+	if err != nil {
+		return "", fmt.Errorf("ошибка чтения DOCX: %w", err)
+	}
+	defer reader.Close()
+
+	var text strings.Builder
+	decoder := xml.NewDecoder(io.LimitReader(reader, maxDocxXMLBytes))
+	inText := false
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("ошибка разбора DOCX: %w", err)
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "t":
+				inText = true
+			case "tab":
+				text.WriteString("\t")
+			case "br", "cr":
+				text.WriteString("\n")
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				text.WriteString("\n")
+			}
+		case xml.CharData:
+			if inText {
+				text.Write(t)
+			}
+		}
+	}
+	return text.String(), nil
+}
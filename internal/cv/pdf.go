@@ -0,0 +1,396 @@
+package cv
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf16"
+)
+
+// maxStreamBytes - предел распакованного потока PDF (защита от zip-бомб)
+const maxStreamBytes = 16 << 20
+
+// maxPageDepth - предел вложенности дерева страниц (защита от циклов)
+const maxPageDepth = 32
+
+// pdfObjectHeader находит начала объектов «N G obj»
+var pdfObjectHeader = regexp.MustCompile(`(\d+)\s+\d+\s+obj\b`)
+
+// pdfObject - объект PDF и сырые данные его потока (nil - объект без потока)
+type pdfObject struct {
+	value  interface{}
+	stream []byte
+}
+
+// pdfDocument - объекты PDF по номерам, включая объекты из потоков объектов
+type pdfDocument struct {
+	objects map[int]*pdfObject
+	cmaps   map[pdfRef]*toUnicode
+}
+
+// pdfText извлекает текст страниц PDF в порядке дерева страниц. Поддерживаются несжатые потоки
+// и FlateDecode, шрифты с ToUnicode и однобайтовые кодировки; текст из картинок (сканы) не распознается.
+func pdfText(data []byte) (string, error) {
+	doc := parsePDF(data)
+	pages := doc.pages()
+	if len(pages) == 0 {
+		return "", fmt.Errorf("ошибка чтения PDF: страницы не найдены")
+	}
+
+	var text strings.Builder
+	for _, page := range pages {
+		var content []byte
+		for _, ref := range refList(page.dict[pdfName("Contents")]) {
+			if stream, err := doc.decodeStream(ref); err == nil {
+				content = append(append(content, stream...), '\n')
+			}
+		}
+		doc.writeContentText(&text, content, page.resources)
+		text.WriteString("\n")
+	}
+	return text.String(), nil
+}
+
+// parsePDF находит все объекты файла; при инкрементальных обновлениях действует последняя версия объекта
+func parsePDF(data []byte) *pdfDocument {
+	doc := &pdfDocument{objects: make(map[int]*pdfObject), cmaps: make(map[pdfRef]*toUnicode)}
+	for _, match := range pdfObjectHeader.FindAllSubmatchIndex(data, -1) {
+		var number int
+		fmt.Sscanf(string(data[match[2]:match[3]]), "%d", &number)
+		lexer := &pdfLexer{data: data, pos: match[1]}
+		value, ok := lexer.value()
+		if !ok {
+			continue
+		}
+		object := &pdfObject{value: value}
+		if dict, isDict := value.(pdfDict); isDict {
+			object.stream = readStream(data, lexer, dict)
+		}
+		doc.objects[number] = object
+	}
+
+	// Объекты PDF 1.5+ хранятся в сжатых потоках объектов
+	for _, object := range doc.objects {
+		if dict, ok := object.value.(pdfDict); ok && dict[pdfName("Type")] == pdfName("ObjStm") {
+			doc.loadObjectStream(dict, object.stream)
+		}
+	}
+	return doc
+}
+
+// readStream возвращает сырые данные потока после словаря или nil, если у объекта нет потока
+func readStream(data []byte, lexer *pdfLexer, dict pdfDict) []byte {
+	lexer.skipSpace()
+	if !bytes.HasPrefix(data[lexer.pos:], []byte("stream")) {
+		return nil
+	}
+	start := lexer.pos + len("stream")
+	if start < len(data) && data[start] == '\r' {
+		start++
+	}
+	if start < len(data) && data[start] == '\n' {
+		start++
+	}
+	if length, ok := pdfInt(dict[pdfName("Length")], len(data)-start); ok {
+		end := start + length
+		if bytes.HasPrefix(bytes.TrimLeft(data[end:], "\r\n \t"), []byte("endstream")) {
+			return data[start:end]
+		}
+	}
+	// Length - ссылка или неверна: ищем конец потока
+	end := bytes.Index(data[start:], []byte("endstream"))
+	if end < 0 {
+		return nil
+	}
+	return bytes.TrimRight(data[start:start+end], "\r\n")
+}
+
+// pdfInt возвращает длину или смещение из файла, если это число от 0 до limit: значения
+// вроде 1e19 или -5 не должны выводить за границы данных
+func pdfInt(value interface{}, limit int) (int, bool) {
+	number, ok := value.(float64)
+	if !ok || !(number >= 0 && number <= float64(limit)) {
+		return 0, false
+	}
+	return int(number), true
+}
+
+// loadObjectStream добавляет объекты из потока объектов, не перекрывая объекты верхнего уровня
+func (d *pdfDocument) loadObjectStream(dict pdfDict, raw []byte) {
+	data, err := decodeFilters(dict, raw)
+	if err != nil {
+		return
+	}
+	count, _ := pdfInt(dict[pdfName("N")], len(data))
+	first, ok := pdfInt(dict[pdfName("First")], len(data))
+	if !ok {
+		return
+	}
+	header := &pdfLexer{data: data[:first]}
+	for i := 0; i < count; i++ {
+		number, ok1 := header.value()
+		offset, ok2 := header.value()
+		num, isNum := number.(float64)
+		off, isOff := pdfInt(offset, len(data)-first)
+		if !ok1 || !ok2 || !isNum || !isOff {
+			return
+		}
+		if _, exists := d.objects[int(num)]; exists {
+			continue
+		}
+		lexer := &pdfLexer{data: data, pos: first + off}
+		if value, ok := lexer.value(); ok {
+			d.objects[int(num)] = &pdfObject{value: value}
+		}
+	}
+}
+
+// resolve разыменовывает ссылку на объект
+func (d *pdfDocument) resolve(value interface{}) interface{} {
+	for i := 0; i < 8; i++ {
+		ref, ok := value.(pdfRef)
+		if !ok {
+			return value
+		}
+		object, exists := d.objects[int(ref)]
+		if !exists {
+			return nil
+		}
+		value = object.value
+	}
+	return nil
+}
+
+func (d *pdfDocument) dict(value interface{}) pdfDict {
+	dict, _ := d.resolve(value).(pdfDict)
+	return dict
+}
+
+// decodeStream возвращает распакованный поток объекта ref
+func (d *pdfDocument) decodeStream(ref pdfRef) ([]byte, error) {
+	object, ok := d.objects[int(ref)]
+	if !ok || object.stream == nil {
+		return nil, fmt.Errorf("поток %d не найден", ref)
+	}
+	dict, _ := object.value.(pdfDict)
+	return decodeFilters(dict, object.stream)
+}
+
+// decodeFilters распаковывает поток; поддерживается только FlateDecode
+func decodeFilters(dict pdfDict, raw []byte) ([]byte, error) {
+	var filters []interface{}
+	switch filter := dict[pdfName("Filter")].(type) {
+	case pdfName:
+		filters = []interface{}{filter}
+	case []interface{}:
+		filters = filter
+	}
+	data := raw
+	for _, filter := range filters {
+		if filter != pdfName("FlateDecode") {
+			return nil, fmt.Errorf("фильтр %v не поддерживается", filter)
+		}
+		reader, err := zlib.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		// Оборванный поток часто все же содержит текст: берем распакованное до ошибки
+		decoded, err := io.ReadAll(io.LimitReader(reader, maxStreamBytes))
+		reader.Close()
+		if len(decoded) == 0 && err != nil {
+			return nil, err
+		}
+		data = decoded
+	}
+	return data, nil
+}
+
+// pdfPage - словарь страницы и ресурсы с учетом наследования от родителей
+type pdfPage struct {
+	dict      pdfDict
+	resources pdfDict
+}
+
+// pages возвращает страницы в порядке дерева страниц; без каталога - в порядке номеров объектов
+func (d *pdfDocument) pages() []pdfPage {
+	var pages []pdfPage
+	for _, object := range d.objects {
+		if dict, ok := object.value.(pdfDict); ok && dict[pdfName("Type")] == pdfName("Catalog") {
+			d.walkPages(d.dict(dict[pdfName("Pages")]), nil, 0, &pages)
+			if len(pages) > 0 {
+				return pages
+			}
+		}
+	}
+
+	numbers := make([]int, 0, len(d.objects))
+	for number := range d.objects {
+		numbers = append(numbers, number)
+	}
+	sort.Ints(numbers)
+	for _, number := range numbers {
+		if dict, ok := d.objects[number].value.(pdfDict); ok && dict[pdfName("Type")] == pdfName("Page") {
+			pages = append(pages, pdfPage{dict: dict, resources: d.dict(dict[pdfName("Resources")])})
+		}
+	}
+	return pages
+}
+
+func (d *pdfDocument) walkPages(node pdfDict, inherited pdfDict, depth int, pages *[]pdfPage) {
+	if node == nil || depth > maxPageDepth {
+		return
+	}
+	resources := inherited
+	if own := d.dict(node[pdfName("Resources")]); own != nil {
+		resources = own
+	}
+	if node[pdfName("Type")] == pdfName("Page") {
+		*pages = append(*pages, pdfPage{dict: node, resources: resources})
+		return
+	}
+	kids, _ := d.resolve(node[pdfName("Kids")]).([]interface{})
+	for _, kid := range kids {
+		d.walkPages(d.dict(kid), resources, depth+1, pages)
+	}
+}
+
+// refList приводит ссылку или массив ссылок к списку
+func refList(value interface{}) []pdfRef {
+	switch v := value.(type) {
+	case pdfRef:
+		return []pdfRef{v}
+	case []interface{}:
+		var refs []pdfRef
+		for _, item := range v {
+			if ref, ok := item.(pdfRef); ok {
+				refs = append(refs, ref)
+			}
+		}
+		return refs
+	}
+	return nil
+}
+
+// pdfFont декодирует строки показа текста шрифта
+type pdfFont struct {
+	cmap *toUnicode
+	// composite - составной шрифт (Type0) с двухбайтовыми кодами
+	composite bool
+}
+
+func (d *pdfDocument) font(resources pdfDict, name pdfName) *pdfFont {
+	fonts := d.dict(resources[pdfName("Font")])
+	fontDict := d.dict(fonts[name])
+	if fontDict == nil {
+		return nil
+	}
+	font := &pdfFont{composite: fontDict[pdfName("Subtype")] == pdfName("Type0")}
+	if ref, ok := fontDict[pdfName("ToUnicode")].(pdfRef); ok {
+		font.cmap = d.toUnicode(ref)
+	}
+	return font
+}
+
+// decode переводит байты строки в текст: по ToUnicode, иначе как UTF-16BE с BOM или Latin-1.
+// Составной шрифт без ToUnicode не декодируется.
+func (f *pdfFont) decode(raw []byte) string {
+	if f != nil && f.cmap != nil {
+		return f.cmap.decode(raw)
+	}
+	if f != nil && f.composite {
+		return ""
+	}
+	if len(raw) >= 2 && raw[0] == 0xFE && raw[1] == 0xFF {
+		return utf16BE(raw[2:])
+	}
+	runes := make([]rune, len(raw))
+	for i, b := range raw {
+		runes[i] = rune(b)
+	}
+	return string(runes)
+}
+
+// writeContentText выполняет операторы текста потока содержимого
+func (d *pdfDocument) writeContentText(text *strings.Builder, content []byte, resources pdfDict) {
+	lexer := &pdfLexer{data: content}
+	fonts := make(map[pdfName]*pdfFont)
+	var font *pdfFont
+	var operands []interface{}
+	for {
+		value, ok := lexer.value()
+		if !ok {
+			return
+		}
+		operator, isOperator := value.(pdfKeyword)
+		if !isOperator {
+			operands = append(operands, value)
+			continue
+		}
+		switch operator {
+		case "Tf":
+			if len(operands) >= 2 {
+				if name, ok := operands[len(operands)-2].(pdfName); ok {
+					if _, cached := fonts[name]; !cached {
+						fonts[name] = d.font(resources, name)
+					}
+					font = fonts[name]
+				}
+			}
+		case "Tj":
+			writeShown(text, font, operands)
+		case "'", "\"":
+			text.WriteString("\n")
+			writeShown(text, font, operands)
+		case "TJ":
+			if len(operands) > 0 {
+				items, _ := operands[len(operands)-1].([]interface{})
+				for _, item := range items {
+					switch v := item.(type) {
+					case []byte:
+						text.WriteString(font.decode(v))
+					case float64:
+						// Большой отрицательный сдвиг - пробел между словами
+						if v < -180 {
+							text.WriteString(" ")
+						}
+					}
+				}
+			}
+		case "Td", "TD":
+			if len(operands) >= 2 {
+				if dy, ok := operands[len(operands)-1].(float64); ok && dy != 0 {
+					text.WriteString("\n")
+				} else {
+					text.WriteString(" ")
+				}
+			}
+		case "T*", "ET":
+			text.WriteString("\n")
+		case "Tm":
+			text.WriteString(" ")
+		}
+		operands = operands[:0]
+	}
+}
+
+// writeShown выводит последнюю строку операндов Tj, ' и "
+func writeShown(text *strings.Builder, font *pdfFont, operands []interface{}) {
+	if len(operands) == 0 {
+		return
+	}
+	if raw, ok := operands[len(operands)-1].([]byte); ok {
+		text.WriteString(font.decode(raw))
+	}
+}
+
+func utf16BE(raw []byte) string {
+	units := make([]uint16, 0, len(raw)/2)
+	for i := 0; i+1 < len(raw); i += 2 {
+		units = append(units, uint16(raw[i])<<8|uint16(raw[i+1]))
+	}
+	return string(utf16.Decode(units))
+}
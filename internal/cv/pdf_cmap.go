@@ -0,0 +1,129 @@
+package cv
+
+import "strings"
+
+// maxCMapRange - предел диапазона bfrange (защита от огромных таблиц)
+const maxCMapRange = 1 << 16
+
+// toUnicode - таблица ToUnicode шрифта: коды глифов в текст
+type toUnicode struct {
+	// width - длина кода в байтах (по codespacerange; по умолчанию 2)
+	width int
+	chars map[uint32]string
+}
+
+// toUnicode загружает таблицу ToUnicode из потока ref; nil - таблица не читается
+func (d *pdfDocument) toUnicode(ref pdfRef) *toUnicode {
+	if cmap, ok := d.cmaps[ref]; ok {
+		return cmap
+	}
+	var cmap *toUnicode
+	if data, err := d.decodeStream(ref); err == nil {
+		cmap = parseCMap(data)
+	}
+	d.cmaps[ref] = cmap
+	return cmap
+}
+
+// parseCMap читает codespacerange, bfchar и bfrange
+func parseCMap(data []byte) *toUnicode {
+	cmap := &toUnicode{chars: make(map[uint32]string)}
+	lexer := &pdfLexer{data: data}
+	var section pdfKeyword
+	var operands []interface{}
+	for {
+		value, ok := lexer.value()
+		if !ok {
+			break
+		}
+		keyword, isKeyword := value.(pdfKeyword)
+		if !isKeyword {
+			if section != "" {
+				operands = append(operands, value)
+			}
+			continue
+		}
+		switch keyword {
+		case "begincodespacerange", "beginbfchar", "beginbfrange":
+			section, operands = keyword, operands[:0]
+		case "endcodespacerange":
+			if len(operands) > 0 {
+				if low, ok := operands[0].([]byte); ok && len(low) > 0 {
+					cmap.width = len(low)
+				}
+			}
+			section = ""
+		case "endbfchar":
+			for i := 0; i+1 < len(operands); i += 2 {
+				code, ok1 := operands[i].([]byte)
+				dst, ok2 := operands[i+1].([]byte)
+				if ok1 && ok2 {
+					cmap.chars[codeValue(code)] = utf16BE(dst)
+				}
+			}
+			section = ""
+		case "endbfrange":
+			for i := 0; i+2 < len(operands); i += 3 {
+				low, ok1 := operands[i].([]byte)
+				high, ok2 := operands[i+1].([]byte)
+				if ok1 && ok2 {
+					cmap.addRange(codeValue(low), codeValue(high), operands[i+2])
+				}
+			}
+			section = ""
+		}
+	}
+	if cmap.width == 0 {
+		cmap.width = 2
+	}
+	if len(cmap.chars) == 0 {
+		return nil
+	}
+	return cmap
+}
+
+// addRange добавляет диапазон кодов: назначение - начальная строка (последний символ
+// увеличивается с кодом) или массив строк по одной на код
+func (c *toUnicode) addRange(low, high uint32, dst interface{}) {
+	if high < low || high-low >= maxCMapRange {
+		return
+	}
+	switch v := dst.(type) {
+	case []byte:
+		runes := []rune(utf16BE(v))
+		if len(runes) == 0 {
+			return
+		}
+		last := runes[len(runes)-1]
+		// Смещение от low, а не сам код: high = 0xFFFFFFFF не должен зациклить перебор
+		for offset := uint32(0); offset <= high-low; offset++ {
+			runes[len(runes)-1] = last + rune(offset)
+			c.chars[low+offset] = string(runes)
+		}
+	case []interface{}:
+		for i, item := range v {
+			if raw, ok := item.([]byte); ok && uint32(i) <= high-low {
+				c.chars[low+uint32(i)] = utf16BE(raw)
+			}
+		}
+	}
+}
+
+// decode переводит байты строки в текст по кодам фиксированной длины
+func (c *toUnicode) decode(raw []byte) string {
+	var text strings.Builder
+	for i := 0; i+c.width <= len(raw); i += c.width {
+		if value, ok := c.chars[codeValue(raw[i:i+c.width])]; ok {
+			text.WriteString(value)
+		}
+	}
+	return text.String()
+}
+
+func codeValue(code []byte) uint32 {
+	var value uint32
+	for _, b := range code {
+		value = value<<8 | uint32(b)
+	}
+	return value
+}
@@ -0,0 +1,246 @@
+package cv
+
+import (
+	"bytes"
+	"strconv"
+)
+
+// Значения объектов PDF: числа - float64, строки - []byte, массивы - []interface{}
+type (
+	pdfName    string
+	pdfKeyword string
+	pdfRef     int
+	pdfDict    map[pdfName]interface{}
+)
+
+// pdfLexer читает объекты PDF и операторы потоков содержимого
+type pdfLexer struct {
+	data []byte
+	pos  int
+}
+
+func isPDFSpace(c byte) bool {
+	return c == ' ' || c == '\n' || c == '\r' || c == '\t' || c == '\f' || c == 0
+}
+
+func isPDFDelimiter(c byte) bool {
+	return bytes.IndexByte([]byte("()<>[]{}/%"), c) >= 0
+}
+
+// skipSpace пропускает пробелы и комментарии
+func (l *pdfLexer) skipSpace() {
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		if c == '%' {
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+			continue
+		}
+		if !isPDFSpace(c) {
+			return
+		}
+		l.pos++
+	}
+}
+
+// value читает следующий объект или оператор; ok=false - конец данных
+func (l *pdfLexer) value() (interface{}, bool) {
+	l.skipSpace()
+	if l.pos >= len(l.data) {
+		return nil, false
+	}
+	switch c := l.data[l.pos]; {
+	case c == '(':
+		return l.literalString(), true
+	case c == '<' && l.peek(1) == '<':
+		return l.dict(), true
+	case c == '<':
+		return l.hexString(), true
+	case c == '[':
+		l.pos++
+		var array []interface{}
+		for {
+			l.skipSpace()
+			if l.pos >= len(l.data) {
+				return array, true
+			}
+			if l.data[l.pos] == ']' {
+				l.pos++
+				return array, true
+			}
+			item, ok := l.value()
+			if !ok {
+				return array, true
+			}
+			array = append(array, item)
+		}
+	case c == '/':
+		l.pos++
+		return pdfName(l.word()), true
+	case c == '+' || c == '-' || c == '.' || (c >= '0' && c <= '9'):
+		return l.number(), true
+	case c == ']' || c == '>' || c == ')' || c == '{' || c == '}':
+		// Лишний разделитель - пропускаем, чтобы не зациклиться
+		l.pos++
+		return pdfKeyword(string(c)), true
+	default:
+		return pdfKeyword(l.word()), true
+	}
+}
+
+func (l *pdfLexer) peek(offset int) byte {
+	if l.pos+offset < len(l.data) {
+		return l.data[l.pos+offset]
+	}
+	return 0
+}
+
+// word читает имя или оператор до пробела или разделителя
+func (l *pdfLexer) word() string {
+	start := l.pos
+	for l.pos < len(l.data) && !isPDFSpace(l.data[l.pos]) && !isPDFDelimiter(l.data[l.pos]) {
+		l.pos++
+	}
+	if l.pos == start && l.pos < len(l.data) {
+		l.pos++
+	}
+	return string(l.data[start:l.pos])
+}
+
+// number читает число; «N G R» превращается в ссылку на объект N
+func (l *pdfLexer) number() interface{} {
+	value, _ := strconv.ParseFloat(l.word(), 64)
+	saved := l.pos
+	l.skipSpace()
+	if l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '9' {
+		l.word()
+		l.skipSpace()
+		if l.pos < len(l.data) && l.data[l.pos] == 'R' && (l.pos+1 == len(l.data) ||
+			isPDFSpace(l.data[l.pos+1]) || isPDFDelimiter(l.data[l.pos+1])) {
+			l.pos++
+			return pdfRef(int(value))
+		}
+	}
+	l.pos = saved
+	return value
+}
+
+func (l *pdfLexer) dict() pdfDict {
+	l.pos += 2
+	dict := make(pdfDict)
+	for {
+		l.skipSpace()
+		if l.pos >= len(l.data) {
+			return dict
+		}
+		if l.data[l.pos] == '>' && l.peek(1) == '>' {
+			l.pos += 2
+			return dict
+		}
+		key, ok := l.value()
+		if !ok {
+			return dict
+		}
+		name, isName := key.(pdfName)
+		if !isName {
+			continue
+		}
+		value, ok := l.value()
+		if !ok {
+			return dict
+		}
+		dict[name] = value
+	}
+}
+
+func (l *pdfLexer) literalString() []byte {
+	l.pos++
+	var out []byte
+	depth := 1
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return out
+			}
+		case '\\':
+			if l.pos >= len(l.data) {
+				return out
+			}
+			escaped := l.data[l.pos]
+			l.pos++
+			switch escaped {
+			case 'n':
+				out = append(out, '\n')
+			case 'r':
+				out = append(out, '\r')
+			case 't':
+				out = append(out, '\t')
+			case 'b':
+				out = append(out, '\b')
+			case 'f':
+				out = append(out, '\f')
+			case '\r':
+				// Перенос строки после \ продолжает строку
+				if l.pos < len(l.data) && l.data[l.pos] == '\n' {
+					l.pos++
+				}
+			case '\n':
+			default:
+				if escaped >= '0' && escaped <= '7' {
+					code := int(escaped - '0')
+					for i := 0; i < 2 && l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '7'; i++ {
+						code = code*8 + int(l.data[l.pos]-'0')
+						l.pos++
+					}
+					out = append(out, byte(code))
+				} else {
+					out = append(out, escaped)
+				}
+			}
+			continue
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+func (l *pdfLexer) hexString() []byte {
+	l.pos++
+	var out []byte
+	var digits []byte
+	for l.pos < len(l.data) && l.data[l.pos] != '>' {
+		if value, ok := hexDigit(l.data[l.pos]); ok {
+			digits = append(digits, value)
+		}
+		l.pos++
+	}
+	if l.pos < len(l.data) {
+		l.pos++
+	}
+	if len(digits)%2 == 1 {
+		digits = append(digits, 0)
+	}
+	for i := 0; i < len(digits); i += 2 {
+		out = append(out, digits[i]<<4|digits[i+1])
+	}
+	return out
+}
+
+func hexDigit(c byte) (byte, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0', true
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10, true
+	case c >= 'A' && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
+}
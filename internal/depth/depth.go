@@ -0,0 +1,126 @@
+package depth
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// Level - глубина ответов собеседника, под которую подстраиваются уточняющие вопросы
+type Level string
+
+const (
+	LevelShallow  Level = "shallow"  // короткие общие ответы - нужны простые конкретные вопросы
+	LevelBalanced Level = "balanced" // обычные ответы - вопросы без изменений
+	LevelDeep     Level = "deep"     // развернутые рефлексивные ответы - можно спрашивать глубже
+)
+
+// Пороги средней оценки ответов для уровней
+const (
+	shallowBelow = 0.35
+	deepFrom     = 0.65
+)
+
+// Веса составляющих эвристической оценки
+const (
+	lengthWeight      = 0.4
+	specificityWeight = 0.3
+	reflectionWeight  = 0.3
+)
+
+const (
+	// fullLengthWords - число слов, при котором ответ считается развернутым
+	fullLengthWords = 60
+	// fullSpecificity - число деталей (чисел, имен, примеров) для полной конкретики
+	fullSpecificity = 3
+	// fullReflection - число рефлексивных оборотов для полной оценки рефлексии
+	fullReflection = 2
+)
+
+// MaxRating - наибольшая оценка глубины ответа моделью (шкала 1..MaxRating)
+const MaxRating = 5
+
+// specificityMarkers - обороты, вводящие пример или конкретную ситуацию
+var specificityMarkers = []string{
+	"например", "в частности", "однажды", "когда я", "в прошлом году", "в году", "проект", "случай",
+	"for example", "for instance", "once", "when i", "last year", "project",
+}
+
+// reflectionMarkers - обороты, в которых человек объясняет причины и выводы
+var reflectionMarkers = []string{
+	"потому что", "поэтому", "понял", "поняла", "осознал", "осознала", "для меня", "чувствую", "думаю",
+	"мне важно", "я считаю", "это научило", "с тех пор",
+	"because", "that's why", "i realized", "i learned", "for me", "i feel", "i think", "matters to me", "since then",
+}
+
+// Score оценивает информативность ответа от 0 до 1 по длине, конкретике и рефлексии.
+// rating - оценка глубины моделью от 1 до MaxRating (0 - нет оценки); она учитывается наравне с эвристикой.
+func Score(answer string, rating int) float64 {
+	words := strings.Fields(answer)
+	if len(words) == 0 {
+		return 0
+	}
+	lower := strings.ToLower(answer)
+
+	length := saturate(len(words), fullLengthWords)
+	specificity := saturate(countMarkers(lower, specificityMarkers)+countDetails(words), fullSpecificity)
+	reflection := saturate(countMarkers(lower, reflectionMarkers), fullReflection)
+	score := lengthWeight*length + specificityWeight*specificity + reflectionWeight*reflection
+
+	if rating > 0 {
+		rated := float64(min(rating, MaxRating)-1) / float64(MaxRating-1)
+		score = (score + rated) / 2
+	}
+	return math.Round(score*100) / 100
+}
+
+// Assess определяет уровень глубины по оценкам ответов (нулевые оценки - ответов еще нет)
+func Assess(scores []float64) Level {
+	sum, count := 0.0, 0
+	for _, score := range scores {
+		if score > 0 {
+			sum += score
+			count++
+		}
+	}
+	if count == 0 {
+		return LevelBalanced
+	}
+	switch average := sum / float64(count); {
+	case average < shallowBelow:
+		return LevelShallow
+	case average >= deepFrom:
+		return LevelDeep
+	default:
+		return LevelBalanced
+	}
+}
+
+func saturate(value, full int) float64 {
+	return math.Min(float64(value)/float64(full), 1)
+}
+
+func countMarkers(text string, markers []string) int {
+	count := 0
+	for _, marker := range markers {
+		count += strings.Count(text, marker)
+	}
+	return count
+}
+
+// countDetails считает числа и имена собственные (слова с заглавной буквы не в начале предложения)
+func countDetails(words []string) int {
+	count := 0
+	sentenceStart := true
+	for _, word := range words {
+		trimmed := strings.TrimFunc(word, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+		if trimmed != "" {
+			first := []rune(trimmed)[0]
+			if unicode.IsDigit(first) || (!sentenceStart && unicode.IsUpper(first)) {
+				count++
+			}
+		}
+		sentenceStart = strings.ContainsAny(word[len(word)-1:], ".!?")
+	}
+	return count
+}
@@ -0,0 +1,140 @@
+package embeddings
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+
+	"interview-bot-complete/internal/api"
+)
+
+// mockDimensions - размерность векторов в режиме симуляции
+const mockDimensions = 256
+
+// client получает эмбеддинги текста через OpenAI Embeddings API
+type client struct {
+	apiKey   string
+	model    string
+	mock     bool
+	http     *http.Client
+	endpoint api.Endpoint
+}
+
+type embeddingRequest struct {
+	Model string `json:"model"`
+	Input string `json:"input"`
+}
+
+type embeddingResponse struct {
+	Data []struct {
+		Embedding []float64 `json:"embedding"`
+	} `json:"data"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// embed возвращает вектор текста
+func (c *client) embed(ctx context.Context, text string) ([]float64, error) {
+	if c.mock {
+		return mockEmbedding(text), nil
+	}
+
+	body, err := json.Marshal(embeddingRequest{Model: c.model, Input: text})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка сериализации запроса: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.endpoint.URL("embeddings", c.model), bytes.NewBuffer(body))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка создания запроса: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	c.endpoint.Authorize(req, c.apiKey)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка запроса эмбеддинга: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения ответа: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ошибка OpenAI Embeddings API: статус %d, тело: %s", resp.StatusCode, string(data))
+	}
+
+	var parsed embeddingResponse
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return nil, fmt.Errorf("ошибка парсинга ответа: %w", err)
+	}
+	if parsed.Error != nil {
+		return nil, fmt.Errorf("ошибка OpenAI Embeddings API: %s", parsed.Error.Message)
+	}
+	if len(parsed.Data) == 0 || len(parsed.Data[0].Embedding) == 0 {
+		return nil, fmt.Errorf("OpenAI не вернул эмбеддинг")
+	}
+
+	return parsed.Data[0].Embedding, nil
+}
+
+// mockEmbedding строит детерминированный вектор по хэшам слов (bag of words),
+// чтобы похожие тексты были близки и без обращения к API
+func mockEmbedding(text string) []float64 {
+	vector := make([]float64, mockDimensions)
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		hash := fnv.New32a()
+		hash.Write([]byte(word))
+		vector[hash.Sum32()%mockDimensions]++
+	}
+	return normalize(vector)
+}
+
+// normalize приводит вектор к единичной длине
+func normalize(vector []float64) []float64 {
+	var norm float64
+	for _, v := range vector {
+		norm += v * v
+	}
+	if norm == 0 {
+		return vector
+	}
+	norm = math.Sqrt(norm)
+	for i := range vector {
+		vector[i] /= norm
+	}
+	return vector
+}
+
+// cosine вычисляет косинусное сходство векторов одинаковой размерности
+func cosine(a, b []float64) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
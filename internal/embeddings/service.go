@@ -0,0 +1,193 @@
+package embeddings
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"interview-bot-complete/internal/api"
+	"interview-bot-complete/internal/storage"
+)
+
+// storeFile - файл с векторами профилей в директории профилей
+const storeFile = "embeddings.json"
+
+// ErrNotIndexed - профиль интервью еще не проиндексирован (или проиндексирован другой моделью)
+var ErrNotIndexed = errors.New("profile is not indexed yet")
+
+// ProfileSource возвращает JSON профиля по ID интервью
+type ProfileSource func(interviewID string) (string, error)
+
+// Match - найденный похожий профиль
+type Match struct {
+	InterviewID string  `json:"interview_id"`
+	Score       float64 `json:"score"`
+}
+
+// record - сохраненный вектор профиля
+type record struct {
+	Model     string    `json:"model"`
+	Vector    []float64 `json:"vector"`
+	UpdatedAt string    `json:"updated_at"`
+}
+
+// Service вычисляет эмбеддинги профилей и ищет похожие профили
+type Service struct {
+	client    *client
+	source    ProfileSource
+	storePath string
+
+	mutex   sync.RWMutex
+	records map[string]record
+}
+
+// New создает сервис эмбеддингов и загружает сохраненные векторы
+func New(apiKey string, source ProfileSource) (*Service, error) {
+	model := os.Getenv("OPENAI_EMBEDDING_MODEL")
+	if model == "" {
+		model = "text-embedding-3-small"
+	}
+	mock := strings.EqualFold(os.Getenv("LLM_PROVIDER"), "mock")
+	if mock {
+		model = "mock"
+	}
+
+	s := &Service{
+		client:    &client{apiKey: apiKey, model: model, mock: mock, http: api.NewHTTPClient(0), endpoint: api.EndpointFromEnv()},
+		source:    source,
+		storePath: filepath.Join(storage.OutputDir(), storeFile),
+		records:   make(map[string]record),
+	}
+
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// IndexProfile вычисляет и сохраняет эмбеддинг профиля
+func (s *Service) IndexProfile(ctx context.Context, interviewID, profileJSON string) error {
+	vector, err := s.client.embed(ctx, profileText(profileJSON))
+	if err != nil {
+		return fmt.Errorf("эмбеддинг профиля %s: %w", interviewID, err)
+	}
+
+	s.mutex.Lock()
+	s.records[interviewID] = record{
+		Model:     s.client.model,
+		Vector:    vector,
+		UpdatedAt: time.Now().Format(time.RFC3339),
+	}
+	s.mutex.Unlock()
+
+	return s.save()
+}
+
+// EnsureIndexed вычисляет эмбеддинг профиля интервью, если он еще не проиндексирован текущей моделью
+func (s *Service) EnsureIndexed(ctx context.Context, interviewID string) error {
+	s.mutex.RLock()
+	target, ok := s.records[interviewID]
+	s.mutex.RUnlock()
+	if ok && target.Model == s.client.model {
+		return nil
+	}
+
+	profileJSON, err := s.source(interviewID)
+	if err != nil {
+		return err
+	}
+	return s.IndexProfile(ctx, interviewID, profileJSON)
+}
+
+// FindSimilar возвращает k профилей, наиболее похожих на профиль интервью.
+// Эмбеддинги не вычисляются: для непроиндексированного профиля возвращается ErrNotIndexed.
+func (s *Service) FindSimilar(ctx context.Context, interviewID string, k int) ([]Match, error) {
+	s.mutex.RLock()
+	target, ok := s.records[interviewID]
+	s.mutex.RUnlock()
+	if !ok || target.Model != s.client.model {
+		return nil, ErrNotIndexed
+	}
+
+	s.mutex.RLock()
+	matches := make([]Match, 0, len(s.records))
+	for id, rec := range s.records {
+		// Векторы разных моделей несравнимы
+		if id == interviewID || rec.Model != target.Model {
+			continue
+		}
+		matches = append(matches, Match{InterviewID: id, Score: cosine(target.Vector, rec.Vector)})
+	}
+	s.mutex.RUnlock()
+
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	if k > 0 && len(matches) > k {
+		matches = matches[:k]
+	}
+	return matches, nil
+}
+
+// Count возвращает число проиндексированных профилей
+func (s *Service) Count() int {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return len(s.records)
+}
+
+// profileText убирает служебные метаданные, чтобы сходство определялось только содержанием профиля
+func profileText(profileJSON string) string {
+	var profile map[string]interface{}
+	if err := json.Unmarshal([]byte(profileJSON), &profile); err != nil {
+		return profileJSON
+	}
+	delete(profile, "_metadata")
+
+	data, err := json.Marshal(profile)
+	if err != nil {
+		return profileJSON
+	}
+	return string(data)
+}
+
+func (s *Service) load() error {
+	data, err := os.ReadFile(s.storePath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("ошибка чтения %s: %w", s.storePath, err)
+	}
+	if err := json.Unmarshal(data, &s.records); err != nil {
+		return fmt.Errorf("ошибка парсинга %s: %w", s.storePath, err)
+	}
+	return nil
+}
+
+// save атомарно перезаписывает файл векторов
+func (s *Service) save() error {
+	s.mutex.RLock()
+	data, err := json.Marshal(s.records)
+	s.mutex.RUnlock()
+	if err != nil {
+		return fmt.Errorf("ошибка сериализации эмбеддингов: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.storePath), 0755); err != nil {
+		return fmt.Errorf("ошибка создания папки %s: %w", filepath.Dir(s.storePath), err)
+	}
+	tmp := s.storePath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("ошибка записи %s: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, s.storePath); err != nil {
+		return fmt.Errorf("ошибка сохранения %s: %w", s.storePath, err)
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package engine
+
+import (
+	"interview-bot-complete/internal/normalize"
+	"interview-bot-complete/internal/storage"
+)
+
+// AnswerTypeError - ответ на вопрос с answer_type (число, дата) не удалось привести к типу;
+// состояние не изменено, текст ошибки - просьба ответить заново
+type AnswerTypeError struct {
+	AnswerType string
+}
+
+func (e *AnswerTypeError) Error() string {
+	return normalize.Hint(e.AnswerType)
+}
+
+// normalizeAnswer приводит ответ к типу вопроса и записывает приведенное значение в qa.
+// Свободный ответ и ответ на закрытый вопрос не приводятся.
+func normalizeAnswer(qa *storage.QA, answer string) error {
+	if qa.AnswerType == "" || len(qa.Options) > 0 {
+		return nil
+	}
+	normalized, ok := normalize.Answer(qa.AnswerType, answer)
+	if !ok {
+		return &AnswerTypeError{AnswerType: qa.AnswerType}
+	}
+	qa.Normalized = normalized
+	return nil
+}
@@ -0,0 +1,27 @@
+package engine
+
+import "interview-bot-complete/internal/config"
+
+// choiceFor возвращает варианты ответа на вопрос index (с нуля) текущего блока; nil - открытый вопрос.
+// Варианты берутся из диалога (какими их увидел пользователь), allow_other и отображение - из шаблона.
+func (e *Engine) choiceFor(session *Session, index int) *config.Choice {
+	if index < 0 || index >= len(session.CurrentDialogue) || len(session.CurrentDialogue[index].Options) == 0 {
+		return nil
+	}
+	choice := config.Choice{Options: session.CurrentDialogue[index].Options}
+	if configured := e.Config(session).Blocks[session.CurrentBlock-1].ChoiceFor(templateIndex(session, index)); configured != nil {
+		choice.Keyboard, choice.AllowOther = configured.Keyboard, configured.AllowOther
+	}
+	return &choice
+}
+
+// matchChoice приводит ответ на закрытый вопрос к тексту варианта; свой ответ принимается только при allow_other
+func matchChoice(choice *config.Choice, answer string) (string, error) {
+	if option, ok := choice.Match(answer); ok {
+		return option, nil
+	}
+	if choice.AllowOther {
+		return answer, nil
+	}
+	return "", ErrInvalidChoice
+}
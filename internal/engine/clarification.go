@@ -0,0 +1,86 @@
+package engine
+
+import (
+	"context"
+	"interview-bot-complete/internal/depth"
+	"interview-bot-complete/internal/language"
+	"interview-bot-complete/internal/storage"
+	"time"
+	"unicode/utf8"
+)
+
+// Причины уточнения ответа
+const (
+	clarificationTooShort      = "too_short"
+	clarificationUninformative = "uninformative"
+)
+
+// defaultClarifications - уточняющий вопрос, когда модель не предложила свой
+var defaultClarifications = map[string]string{
+	language.Russian: "Могли бы вы рассказать об этом чуть подробнее? Пример или пара деталей очень помогут.",
+	language.English: "Could you expand on that a little? An example or a couple of details would really help.",
+}
+
+// pendingClarification возвращает последний вопрос диалога, если он ждет ответа на уточнение
+func pendingClarification(session *Session) *storage.QA {
+	if len(session.CurrentDialogue) == 0 {
+		return nil
+	}
+	qa := &session.CurrentDialogue[len(session.CurrentDialogue)-1]
+	if qa.Clarification == nil || qa.Clarification.AnsweredAt != "" {
+		return nil
+	}
+	return qa
+}
+
+// recordClarificationAnswer дописывает ответ на уточнение к исходному ответу
+func recordClarificationAnswer(qa *storage.QA, answer string) {
+	qa.Clarification.Answer = answer
+	qa.Clarification.AnsweredAt = time.Now().Format(time.RFC3339)
+	qa.Answer = qa.Answer + "\n" + answer
+	qa.DepthScore = depth.Score(qa.Answer, qa.DepthRating)
+}
+
+// maybeAskClarification задает один уточняющий вопрос к слишком короткому или неинформативному ответу.
+// Уточнение не расходует лимит вопросов блока. Возвращает уточнение или nil.
+func (e *Engine) maybeAskClarification(ctx context.Context, session *Session, qa *storage.QA) *Prompt {
+	if qa.Clarification != nil {
+		return nil
+	}
+
+	cfg := e.Config(session)
+	reason, question := "", ""
+
+	if minLength := cfg.GetMinAnswerLength(); minLength > 0 && utf8.RuneCountInString(qa.Answer) < minLength {
+		reason = clarificationTooShort
+	} else if cfg.InterviewConfig.CheckAnswerQuality && e.waitLLMBudget(ctx, session.UserID) == nil {
+		check, err := e.interviewer.WithLogger(e.Logger(session)).CheckAnswer(qa.Question, qa.Answer, cfg)
+		if err != nil {
+			e.Logger(session).Warn("Ошибка оценки ответа", "error", err)
+			return nil
+		}
+		if check.Depth > 0 {
+			qa.DepthRating = check.Depth
+			qa.DepthScore = depth.Score(qa.Answer, qa.DepthRating)
+		}
+		if check.Informative {
+			return nil
+		}
+		reason, question = clarificationUninformative, check.Clarification
+	}
+
+	if reason == "" {
+		return nil
+	}
+	if question == "" {
+		question = defaultClarifications[language.Detect(qa.Answer)]
+	}
+
+	qa.Clarification = &storage.Clarification{
+		Question: question,
+		Reason:   reason,
+		AskedAt:  time.Now().Format(time.RFC3339),
+		Source:   storage.QuestionSourceClarification,
+	}
+	return e.Current(session)
+}
@@ -0,0 +1,88 @@
+package engine
+
+import (
+	"interview-bot-complete/internal/condition"
+	"interview-bot-complete/internal/config"
+	"interview-bot-complete/internal/interviewer"
+	"log/slog"
+	"strings"
+)
+
+// sessionEnv предоставляет данные интервью для вычисления условий блоков
+type sessionEnv struct {
+	session *Session
+	config  *config.Config
+}
+
+// Text возвращает текст саммари или ответов пользователя
+func (e sessionEnv) Text(source string) string {
+	switch source {
+	case "summary":
+		texts := make([]string, 0, len(e.session.CumulativeSummaries))
+		for _, summary := range e.session.CumulativeSummaries {
+			texts = append(texts, interviewer.SummaryText(summary))
+		}
+		return strings.Join(texts, "\n")
+	case "answers":
+		return e.answersText()
+	default:
+		return ""
+	}
+}
+
+// Flag проверяет, встречается ли в ответах одно из ключевых слов флага
+func (e sessionEnv) Flag(name string) bool {
+	answers := strings.ToLower(e.answersText())
+	for _, keyword := range e.config.Flags[name] {
+		if keyword != "" && strings.Contains(answers, strings.ToLower(keyword)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e sessionEnv) answersText() string {
+	if e.session.Result == nil {
+		return ""
+	}
+
+	var answers []string
+	for _, block := range e.session.Result.Blocks {
+		for _, qa := range block.QuestionsAndAnswers {
+			answers = append(answers, qa.Answer)
+		}
+	}
+	return strings.Join(answers, "\n")
+}
+
+// shouldRunBlock вычисляет условие блока; блок без условия выполняется всегда
+func shouldRunBlock(session *Session, cfg *config.Config, block config.Block, logger *slog.Logger) bool {
+	if block.Condition == "" {
+		return true
+	}
+
+	expr, err := condition.Parse(block.Condition)
+	if err != nil {
+		logger.Warn("Ошибка разбора условия блока, блок выполняется", "block_id", block.ID, "condition", block.Condition, "error", err)
+		return true
+	}
+
+	return expr.Eval(sessionEnv{session: session, config: cfg})
+}
+
+// usesSummaries проверяет, ссылается ли условие блока на саммари предыдущих блоков
+func usesSummaries(block config.Block) bool {
+	if block.Condition == "" {
+		return false
+	}
+	expr, err := condition.Parse(block.Condition)
+	if err != nil {
+		return false
+	}
+	for _, source := range condition.SourcesOf(expr) {
+		if source == "summary" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,35 @@
+package engine
+
+import (
+	"context"
+	"interview-bot-complete/internal/config"
+)
+
+// uncoveredGoals создает саммари текущего диалога блока и возвращает цели покрытия, которые в нем не раскрыты
+func (e *Engine) uncoveredGoals(ctx context.Context, session *Session, block config.Block) ([]string, error) {
+	cfg := e.Config(session)
+	interviewerService := e.interviewer.WithLogger(e.Logger(session))
+	if err := e.waitLLMBudget(ctx, session.UserID); err != nil {
+		return nil, err
+	}
+	summary, err := interviewerService.CreateSummary(session.CurrentDialogue, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := e.waitLLMBudget(ctx, session.UserID); err != nil {
+		return nil, err
+	}
+	uncovered, err := interviewerService.CheckCoverage(*summary, block.CoverageGoals, cfg)
+	if err != nil {
+		return nil, err
+	}
+	e.Logger(session).Info("Проверено покрытие целей блока", "goals", len(block.CoverageGoals), "uncovered", len(uncovered))
+	return uncovered, nil
+}
+
+// coverageBlock возвращает блок, в областях фокуса которого остались только нераскрытые цели:
+// по ним модель формулирует уточняющий вопрос
+func coverageBlock(block config.Block, uncovered []string) config.Block {
+	block.FocusAreas = uncovered
+	return block
+}
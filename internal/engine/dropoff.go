@@ -0,0 +1,56 @@
+package engine
+
+import (
+	"interview-bot-complete/internal/storage"
+	"time"
+)
+
+// Dropoff описывает, где пользователь бросает интервью: блок, вопрос без ответа и данные
+// ответы. Вызывается до Abandon и сброса сессии; nil - интервью еще не начато.
+func (e *Engine) Dropoff(session *Session, reason string) *storage.Dropoff {
+	result := session.Result
+	if result == nil {
+		return nil
+	}
+
+	dropoff := &storage.Dropoff{
+		InterviewID: session.InterviewID,
+		TemplateID:  result.TemplateID,
+		Tenant:      result.Tenant,
+		Reason:      reason,
+		At:          time.Now().Format(time.RFC3339),
+	}
+	for _, block := range result.Blocks {
+		for i, qa := range block.QuestionsAndAnswers {
+			dropoff.Answers = append(dropoff.Answers, dropoffAnswer(block.BlockID, i+1, qa))
+		}
+	}
+
+	cfg := e.Config(session)
+	if session.CurrentBlock < 1 || session.CurrentBlock > len(cfg.Blocks) {
+		return dropoff
+	}
+	block := cfg.Blocks[session.CurrentBlock-1]
+	dropoff.BlockID, dropoff.BlockName = block.ID, block.Name
+	for i, qa := range session.CurrentDialogue {
+		if qa.Answer == "" {
+			dropoff.Question, dropoff.QuestionText = i+1, qa.Question
+			break
+		}
+		dropoff.Answers = append(dropoff.Answers, dropoffAnswer(block.ID, i+1, qa))
+	}
+	if dropoff.Question == 0 {
+		// Все заданные вопросы отвечены: пользователь ушел на проверке блока или до следующего вопроса
+		dropoff.Question = len(session.CurrentDialogue) + 1
+	}
+	return dropoff
+}
+
+func dropoffAnswer(blockID, question int, qa storage.QA) storage.DropoffAnswer {
+	return storage.DropoffAnswer{
+		BlockID:  blockID,
+		Question: question,
+		Length:   len([]rune(qa.Answer)),
+		Skipped:  qa.Skipped,
+	}
+}
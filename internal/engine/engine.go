@@ -0,0 +1,637 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"interview-bot-complete/internal/config"
+	"interview-bot-complete/internal/depth"
+	"interview-bot-complete/internal/interviewer"
+	"interview-bot-complete/internal/logging"
+	"interview-bot-complete/internal/metrics"
+	"interview-bot-complete/internal/ratelimit"
+	"interview-bot-complete/internal/sentiment"
+	"interview-bot-complete/internal/storage"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// llmBudgetWaitTimeout - сколько ждать свободного токена в бюджете обращений к OpenAI
+const llmBudgetWaitTimeout = 2 * time.Minute
+
+var (
+	// ErrCompleted - интервью уже завершено, ответы больше не принимаются
+	ErrCompleted = errors.New("интервью уже завершено")
+	// ErrBusy - бюджет обращений к OpenAI исчерпан; состояние не изменено, шаг можно повторить
+	ErrBusy = errors.New("сервис анализа перегружен, попробуйте позже")
+	// ErrSummary - не удалось создать саммари блока; следующий Advance повторит завершение блока
+	ErrSummary = errors.New("ошибка при создании саммари блока")
+	// ErrSave - не удалось сохранить результат интервью; следующий Advance повторит сохранение
+	ErrSave = errors.New("ошибка сохранения результата интервью")
+	// ErrInvalidChoice - ответ на закрытый вопрос не совпал ни с одним вариантом; состояние не изменено
+	ErrInvalidChoice = errors.New("выберите один из предложенных вариантов ответа")
+)
+
+// Phase - этап прохождения интервью
+type Phase string
+
+const (
+	PhaseNew          Phase = "new"           // интервью создано, первый блок еще не начат
+	PhaseAnswering    Phase = "answering"     // ожидается ответ на вопрос или уточнение
+	PhaseBlockReview  Phase = "block_review"  // вопросы блока заданы, ответы ждут подтверждения перед саммари
+	PhaseBlockOptIn   Phase = "block_opt_in"  // перед необязательным блоком ждем решения пользователя, проходить ли его
+	PhaseBlockPending Phase = "block_pending" // блок завершен, но саммари или сохранение не удались - шаг нужно повторить
+	PhaseCompleted    Phase = "completed"     // все блоки пройдены, результат сохранен
+)
+
+// Session - состояние интервью, не зависящее от транспорта (Telegram, API, CLI)
+type Session struct {
+	InterviewID         string                   `json:"interview_id"`
+	TemplateID          string                   `json:"template_id,omitempty"`
+	Language            string                   `json:"language,omitempty"` // язык перевода шаблона; пустой - язык шаблона
+	Persona             string                   `json:"persona,omitempty"`  // персона интервьюера; пустая - базовая роль
+	UserID              int64                    `json:"user_id"`
+	Phase               Phase                    `json:"phase"`
+	CurrentBlock        int                      `json:"current_block"`
+	QuestionCount       int                      `json:"question_count"`
+	CurrentDialogue     []storage.QA             `json:"current_dialogue"`
+	CumulativeSummaries []storage.BlockSummary   `json:"cumulative_summaries"`
+	Result              *storage.InterviewResult `json:"result"`
+	BlockStartedAt      time.Time                `json:"block_started_at,omitempty"`
+	BlockNudged         bool                     `json:"block_nudged,omitempty"`
+	// ReviewBlocks - перед саммари блока ответы показываются пользователю для проверки (ConfirmBlock)
+	ReviewBlocks bool `json:"review_blocks,omitempty"`
+	// PendingSummaries - индексы блоков результата, саммари которых создаются в фоне (async_summaries)
+	PendingSummaries []int `json:"pending_summaries,omitempty"`
+	// InjectedQuestions - очередь вопросов супервизора (InjectQuestion)
+	InjectedQuestions []string `json:"injected_questions,omitempty"`
+	// Memory - прошлый профиль пользователя для уточняющих вопросов (SetMemory); пусто - без памяти
+	Memory string `json:"memory,omitempty"`
+	// ResumeFacts - факты из резюме для уточняющих вопросов (SetResumeFacts); пусто - резюме не загружено
+	ResumeFacts string `json:"resume_facts,omitempty"`
+	// TemplateRevision - ревизия шаблона на начало интервью: после перезагрузки шаблонов
+	// интервью доводится по ней; пустая или неизвестная (после перезапуска) - текущий шаблон
+	TemplateRevision string `json:"template_revision,omitempty"`
+}
+
+// PromptKind - вид сообщения, ожидающего ответа пользователя
+type PromptKind string
+
+const (
+	PromptQuestion      PromptKind = "question"
+	PromptClarification PromptKind = "clarification"
+	PromptOptIn         PromptKind = "block_opt_in" // проходить ли необязательный блок: Options - согласие и отказ
+)
+
+// Prompt - вопрос, на который сейчас должен ответить пользователь
+type Prompt struct {
+	Kind       PromptKind `json:"kind"`
+	Text       string     `json:"text"`
+	Block      int        `json:"block"`
+	BlockTitle string     `json:"block_title"`
+	Number     int        `json:"number"` // номер вопроса в блоке
+	Variant    string     `json:"variant,omitempty"`
+	// Options - варианты ответа закрытого вопроса (type: choice); Keyboard - их отображение в Telegram
+	Options    []string `json:"options,omitempty"`
+	Keyboard   string   `json:"keyboard,omitempty"`
+	AllowOther bool     `json:"allow_other,omitempty"`
+	// AnswerType - ожидаемый тип ответа открытого вопроса (number, date, list)
+	AnswerType string `json:"answer_type,omitempty"`
+}
+
+// EventKind - вид события, произошедшего при переходе состояния
+type EventKind string
+
+const (
+	EventBlockStarted       EventKind = "block_started"
+	EventBlockSkipped       EventKind = "block_skipped"
+	EventBlockReview        EventKind = "block_review" // ответы блока ждут подтверждения через ConfirmBlock
+	EventBlockFinished      EventKind = "block_finished"
+	EventInterviewCompleted EventKind = "interview_completed"
+	EventBlockDeclined      EventKind = "block_declined" // пользователь отказался от необязательного блока
+)
+
+// Event - событие перехода; транспорт решает, как показать его пользователю
+type Event struct {
+	Kind       EventKind                `json:"kind"`
+	Block      int                      `json:"block,omitempty"`
+	BlockTitle string                   `json:"block_title,omitempty"`
+	Result     *storage.InterviewResult `json:"-"` // для EventInterviewCompleted
+}
+
+// Engine проводит интервью по шаблону: блоки, вопросы, уточнения, саммари и сохранение результата
+type Engine struct {
+	templates   *config.Templates
+	interviewer *interviewer.Service
+	llmLimiter  ratelimit.RateLimiter
+	metrics     *metrics.Registry
+	logger      *slog.Logger
+	summaries   *summaryJobs
+}
+
+// New создает движок интервью
+func New(templates *config.Templates, interviewerService *interviewer.Service) *Engine {
+	return &Engine{templates: templates, interviewer: interviewerService, logger: slog.Default(), summaries: newSummaryJobs()}
+}
+
+// SetLLMLimiter подключает бюджет обращений к OpenAI (общий с другими фронтендами)
+func (e *Engine) SetLLMLimiter(limiter ratelimit.RateLimiter) {
+	e.llmLimiter = limiter
+}
+
+// SetMetrics подключает реестр метрик
+func (e *Engine) SetMetrics(registry *metrics.Registry) {
+	e.metrics = registry
+}
+
+// SetLogger задает логгер движка
+func (e *Engine) SetLogger(logger *slog.Logger) {
+	e.logger = logger
+}
+
+// Logger возвращает логгер с атрибутами интервью: ID, пользователь и текущий блок
+func (e *Engine) Logger(session *Session) *slog.Logger {
+	return logging.Interview(e.logger, session.InterviewID, session.UserID, session.CurrentBlock)
+}
+
+// Config возвращает конфигурацию шаблона интервью на языке сессии: ревизию, с которой
+// интервью начато, иначе текущий шаблон (шаблон по умолчанию для неизвестного ID)
+func (e *Engine) Config(session *Session) *config.Config {
+	if cfg, ok := e.templates.Revision(session.TemplateRevision); ok {
+		return cfg.Localized(session.Language)
+	}
+	cfg, ok := e.templates.Get(session.TemplateID)
+	if !ok {
+		cfg = e.templates.Default()
+	}
+	return cfg.Localized(session.Language)
+}
+
+// SetLanguage выбирает перевод шаблона по коду языка пользователя (например, en-US).
+// Вызывается до Start; без подходящего перевода вопросы задаются на языке шаблона.
+func (e *Engine) SetLanguage(session *Session, languageCode string) {
+	session.Language = ""
+	session.Language = e.Config(session).MatchLanguage(languageCode)
+	session.Result.Language = session.Language
+}
+
+// SetPersona выбирает персону интервьюера из персон шаблона (пустой ID - персона шаблона по умолчанию).
+// Действует на следующие сгенерированные вопросы.
+func (e *Engine) SetPersona(session *Session, id string) error {
+	cfg := e.Config(session)
+	if id == "" {
+		id = cfg.InterviewConfig.Persona
+	} else if _, ok := cfg.Persona(id); !ok {
+		return fmt.Errorf("персона %q не найдена в шаблоне", id)
+	}
+	session.Persona = id
+	session.Result.Persona = id
+	return nil
+}
+
+// SetMemory подключает к уточняющим вопросам профиль прошлого интервью interviewID пользователя.
+// Интервью, с которым связан профиль, записывается в результат.
+func (e *Engine) SetMemory(session *Session, interviewID, profileJSON string) error {
+	memory, err := interviewer.ProfileMemory(profileJSON)
+	if err != nil {
+		return err
+	}
+	if memory == "" {
+		return fmt.Errorf("в профиле интервью %s нет данных", interviewID)
+	}
+	session.Memory = memory
+	session.Result.MemoryFrom = interviewID
+	return nil
+}
+
+// Templates возвращает шаблоны интервью
+func (e *Engine) Templates() *config.Templates {
+	return e.templates
+}
+
+// NewInterview создает состояние нового интервью пользователя по шаблону templateID
+func (e *Engine) NewInterview(userID int64, templateID string) *Session {
+	session := &Session{
+		InterviewID: uuid.New().String(),
+		TemplateID:  templateID,
+		UserID:      userID,
+		Phase:       PhaseNew,
+	}
+	session.TemplateRevision = e.Config(session).Revision()
+	session.Result = &storage.InterviewResult{
+		InterviewID: session.InterviewID,
+		Timestamp:   time.Now().Format(time.RFC3339),
+		Blocks:      make([]storage.BlockResult, 0, e.Config(session).GetTotalBlocks()),
+		TemplateID:  templateID,
+		UserID:      userID,
+	}
+	session.Persona = e.Config(session).InterviewConfig.Persona
+	session.Result.Persona = session.Persona
+	session.Result.TemplateRevision = session.TemplateRevision
+	return session
+}
+
+// Start начинает первый блок и возвращает первый вопрос
+func (e *Engine) Start(ctx context.Context, session *Session) (*Prompt, []Event, error) {
+	if session.Phase != PhaseNew {
+		return e.Current(session), nil, nil
+	}
+	e.metrics.InterviewStarted()
+	session.CurrentBlock = 1
+	session.Phase = PhaseAnswering
+
+	var events []Event
+	prompt, err := e.startNextBlock(ctx, session, &events)
+	return prompt, events, err
+}
+
+// Advance принимает ответ на текущий вопрос и переводит интервью к следующему шагу.
+// Возвращает следующий вопрос (nil после завершения интервью) и события перехода.
+// В фазах PhaseBlockReview и PhaseBlockPending ответ игнорируется: блок подтверждается
+// или повторяется его завершение.
+func (e *Engine) Advance(ctx context.Context, session *Session, answer string) (*Prompt, []Event, error) {
+	switch session.Phase {
+	case PhaseNew:
+		return e.Start(ctx, session)
+	case PhaseCompleted:
+		return nil, nil, ErrCompleted
+	case PhaseBlockOptIn:
+		return e.answerOptIn(ctx, session, answer)
+	case PhaseBlockReview, PhaseBlockPending:
+		var events []Event
+		prompt, err := e.finishBlock(ctx, session, &events)
+		return prompt, events, err
+	}
+
+	e.collectReadySummaries(session)
+	if qa := pendingClarification(session); qa != nil {
+		// Ответ на уточнение дополняет исходный ответ
+		recordClarificationAnswer(qa, answer)
+	} else if len(session.CurrentDialogue) > 0 {
+		index := len(session.CurrentDialogue) - 1
+		qa := &session.CurrentDialogue[index]
+		choice := e.choiceFor(session, index)
+		if choice != nil {
+			option, err := matchChoice(choice, answer)
+			if err != nil {
+				return e.Current(session), nil, err
+			}
+			answer = option
+		}
+		if err := normalizeAnswer(qa, answer); err != nil {
+			return e.Current(session), nil, err
+		}
+		qa.Answer = answer
+		qa.AnsweredAt = time.Now().Format(time.RFC3339)
+		e.metrics.VariantAnswered(storage.VariantKey(session.Result.TemplateID, qa.Variant))
+
+		// Выбранный вариант, ответ с типом (число, дата, список) и подтверждение факта из резюме записываются
+		// как есть: без оценки глубины и уточнений модели. Краткий ответ - одно уточнение без расхода лимита вопросов блока
+		if choice == nil && qa.AnswerType == "" && qa.Source != storage.QuestionSourceResume {
+			qa.DepthScore = depth.Score(answer, 0)
+			if prompt := e.maybeAskClarification(ctx, session, qa); prompt != nil {
+				return prompt, nil, nil
+			}
+		}
+	}
+
+	countAnsweredQuestion(session)
+	var events []Event
+	prompt, err := e.nextQuestion(ctx, session, &events)
+	return prompt, events, err
+}
+
+// ConfirmBlock подтверждает проверенные ответы блока: создается саммари и начинается следующий блок
+func (e *Engine) ConfirmBlock(ctx context.Context, session *Session) (*Prompt, []Event, error) {
+	if session.Phase != PhaseBlockReview {
+		return nil, nil, fmt.Errorf("ответы блока %d не ожидают подтверждения", session.CurrentBlock)
+	}
+	var events []Event
+	prompt, err := e.finishBlock(ctx, session, &events)
+	return prompt, events, err
+}
+
+// EditAnswer заменяет ответ на вопрос index (с нуля) текущего блока. Исправлять можно,
+// пока по блоку не создано саммари; исходный ответ сохраняется в OriginalAnswer.
+func (e *Engine) EditAnswer(session *Session, index int, answer string) error {
+	if session.Phase != PhaseAnswering && session.Phase != PhaseBlockReview && session.Phase != "" {
+		return fmt.Errorf("ответы блока %d уже нельзя исправить", session.CurrentBlock)
+	}
+	if index < 0 || index >= len(session.CurrentDialogue) || session.CurrentDialogue[index].Answer == "" {
+		return fmt.Errorf("в текущем блоке нет ответа на вопрос %d", index+1)
+	}
+
+	qa := &session.CurrentDialogue[index]
+	choice := e.choiceFor(session, index)
+	if choice != nil {
+		option, err := matchChoice(choice, answer)
+		if err != nil {
+			return err
+		}
+		answer = option
+	}
+	if err := normalizeAnswer(qa, answer); err != nil {
+		return err
+	}
+	if !qa.Edited {
+		qa.OriginalAnswer = qa.Answer
+	}
+	qa.Answer = answer
+	qa.Edited = true
+	qa.DepthScore = 0
+	if choice == nil && qa.AnswerType == "" {
+		qa.DepthScore = depth.Score(answer, 0)
+	}
+	qa.DepthRating = 0
+	return nil
+}
+
+// Current возвращает вопрос, ожидающий ответа (nil, если ответа не ждут)
+func (e *Engine) Current(session *Session) *Prompt {
+	if session.Phase == PhaseBlockOptIn {
+		return e.optInPrompt(session)
+	}
+	// Пустая фаза - сессия, сохраненная до появления фаз: она в процессе ответа
+	if (session.Phase != PhaseAnswering && session.Phase != "") || len(session.CurrentDialogue) == 0 {
+		return nil
+	}
+	block := e.Config(session).Blocks[session.CurrentBlock-1]
+	qa := session.CurrentDialogue[len(session.CurrentDialogue)-1]
+	prompt := &Prompt{
+		Kind:       PromptQuestion,
+		Text:       qa.Question,
+		Block:      session.CurrentBlock,
+		BlockTitle: block.Title,
+		Number:     session.QuestionCount + 1,
+		Variant:    qa.Variant,
+	}
+	if pending := pendingClarification(session); pending != nil {
+		prompt.Kind, prompt.Text = PromptClarification, pending.Clarification.Question
+		return prompt
+	}
+	if choice := e.choiceFor(session, len(session.CurrentDialogue)-1); choice != nil {
+		prompt.Options, prompt.Keyboard, prompt.AllowOther = choice.Options, choice.Keyboard, choice.AllowOther
+	}
+	prompt.AnswerType = qa.AnswerType
+	if qa.Answer != "" {
+		return nil
+	}
+	return prompt
+}
+
+// nextQuestion задает следующий вопрос блока или завершает блок
+func (e *Engine) nextQuestion(ctx context.Context, session *Session, events *[]Event) (*Prompt, error) {
+	if prompt := e.nextInjectedQuestion(session); prompt != nil {
+		return prompt, nil
+	}
+	cfg := e.Config(session)
+	block := cfg.Blocks[session.CurrentBlock-1]
+	if session.QuestionCount >= cfg.GetQuestionsPerBlock()+cfg.GetMaxFollowupQuestions() {
+		return e.endBlock(ctx, session, events)
+	}
+
+	var question, generatedBy, variant, answerType string
+	var options []string
+	source := storage.QuestionSourceStatic
+	if session.QuestionCount < len(block.Questions) {
+		question, variant = block.Question(session.QuestionCount, session.UserID)
+		answerType = block.AnswerTypeFor(session.QuestionCount)
+		if choice := block.ChoiceFor(session.QuestionCount); choice != nil {
+			options = choice.Options
+		}
+		if confirmation := e.resumeConfirmation(session, block, session.QuestionCount, question); confirmation != "" {
+			// Факт известен из резюме: достаточно подтверждения, ответ «да» не приводится к типу
+			question, answerType, source = confirmation, "", storage.QuestionSourceResume
+		}
+	} else if session.QuestionCount-len(block.Questions) < cfg.GetMaxFollowupQuestions() {
+		// Вопросы из конфигурации закончились - уточняющий вопрос генерирует модель
+		// с учетом текущего диалога и структурированных саммари предыдущих блоков
+		if len(block.CoverageGoals) > 0 {
+			uncovered, err := e.uncoveredGoals(ctx, session, block)
+			switch {
+			case err != nil:
+				e.Logger(session).Warn("Не удалось проверить покрытие целей блока, задается обычный уточняющий вопрос", "error", err)
+			case len(uncovered) == 0:
+				e.Logger(session).Info("Цели блока раскрыты, блок завершается", "questions", session.QuestionCount)
+				return e.endBlock(ctx, session, events)
+			default:
+				block = coverageBlock(block, uncovered)
+			}
+		}
+		generated, model, err := e.generateFollowupQuestion(ctx, session, block)
+		if errors.Is(err, interviewer.ErrRejectedQuestion) {
+			// Модель дважды вернула недопустимый вопрос - задаем заготовленный
+			if fallback := fallbackFollowup(session, cfg.Language()); fallback != "" {
+				e.Logger(session).Warn("Сгенерированный вопрос отклонен, задан заготовленный", "error", err)
+				generated, model, err = fallback, "", nil
+			}
+		}
+		if err != nil {
+			e.Logger(session).Warn("Не удалось сгенерировать уточняющий вопрос, блок завершается", "error", err)
+			return e.endBlock(ctx, session, events)
+		}
+		question, generatedBy, source = generated, model, storage.QuestionSourceFollowup
+	} else {
+		return e.endBlock(ctx, session, events)
+	}
+
+	session.CurrentDialogue = append(session.CurrentDialogue, storage.QA{
+		Question:    question,
+		AskedAt:     time.Now().Format(time.RFC3339),
+		GeneratedBy: generatedBy,
+		Variant:     variant,
+		Options:     options,
+		AnswerType:  answerType,
+		Source:      source,
+	})
+	e.metrics.VariantAsked(storage.VariantKey(session.Result.TemplateID, variant))
+	session.Phase = PhaseAnswering
+	return e.Current(session), nil
+}
+
+// generateFollowupQuestion запрашивает у модели уточняющий вопрос для текущего блока
+func (e *Engine) generateFollowupQuestion(ctx context.Context, session *Session, block config.Block) (string, string, error) {
+	if err := e.waitLLMBudget(ctx, session.UserID); err != nil {
+		return "", "", err
+	}
+	cfg := e.Config(session)
+	interviewerService := e.interviewer.WithLogger(e.Logger(session))
+	if persona, ok := cfg.Persona(session.Persona); ok {
+		interviewerService = interviewerService.WithPersona(persona)
+	}
+	if session.Memory != "" {
+		interviewerService = interviewerService.WithMemory(session.Memory)
+	}
+	if session.ResumeFacts != "" {
+		interviewerService = interviewerService.WithResumeFacts(session.ResumeFacts)
+	}
+	question, model, err := interviewerService.GenerateQuestion(block, session.CurrentDialogue, session.CumulativeSummaries, cfg)
+	if err != nil {
+		return "", "", err
+	}
+	if question == "" {
+		return "", "", fmt.Errorf("модель вернула пустой вопрос")
+	}
+	return question, model, nil
+}
+
+// endBlock завершает вопросы блока: при ReviewBlocks ответы ждут подтверждения, иначе блок сразу завершается
+func (e *Engine) endBlock(ctx context.Context, session *Session, events *[]Event) (*Prompt, error) {
+	if !session.ReviewBlocks {
+		return e.finishBlock(ctx, session, events)
+	}
+	session.Phase = PhaseBlockReview
+	*events = append(*events, Event{Kind: EventBlockReview, Block: session.CurrentBlock,
+		BlockTitle: e.Config(session).Blocks[session.CurrentBlock-1].Title})
+	return nil, nil
+}
+
+// finishBlock создает саммари блока, сохраняет его в результат и начинает следующий блок
+func (e *Engine) finishBlock(ctx context.Context, session *Session, events *[]Event) (*Prompt, error) {
+	session.Phase = PhaseBlockPending
+	cfg := e.Config(session)
+	if session.CurrentBlock > cfg.GetTotalBlocks() {
+		// Блоки пройдены, не удалось только сохранение результата
+		return nil, e.complete(ctx, session, events)
+	}
+
+	block := cfg.Blocks[session.CurrentBlock-1]
+	blockResult := storage.BlockResult{
+		BlockID:             block.ID,
+		BlockName:           block.Name,
+		QuestionsAndAnswers: session.CurrentDialogue,
+		Sentiment:           sentiment.Analyze(session.CurrentDialogue),
+	}
+	finishBlockTiming(session, &blockResult, time.Now())
+
+	if cfg.InterviewConfig.AsyncSummaries {
+		// Следующий блок начинается сразу, саммари догонит его в фоне
+		session.Result.Blocks = append(session.Result.Blocks, blockResult)
+		session.PendingSummaries = append(session.PendingSummaries, len(session.Result.Blocks)-1)
+		e.startSummary(session, len(session.Result.Blocks)-1, cfg)
+		return e.blockFinished(ctx, session, events, block, blockResult)
+	}
+
+	if err := e.waitLLMBudget(ctx, session.UserID); err != nil {
+		return nil, err
+	}
+	summary, err := e.interviewer.WithLogger(e.Logger(session)).CreateSummary(session.CurrentDialogue, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSummary, err)
+	}
+
+	blockResult.Summary = summary
+	session.Result.Blocks = append(session.Result.Blocks, blockResult)
+	session.CumulativeSummaries = append(session.CumulativeSummaries, *summary)
+	return e.blockFinished(ctx, session, events, block, blockResult)
+}
+
+// blockFinished отмечает завершение блока и начинает следующий
+func (e *Engine) blockFinished(ctx context.Context, session *Session, events *[]Event, block config.Block, blockResult storage.BlockResult) (*Prompt, error) {
+	*events = append(*events, Event{Kind: EventBlockFinished, Block: session.CurrentBlock, BlockTitle: block.Title})
+	e.Logger(session).Info("Блок завершен", "questions", len(blockResult.QuestionsAndAnswers), "duration_seconds", blockResult.DurationSeconds)
+
+	session.CurrentBlock++
+	return e.startNextBlock(ctx, session, events)
+}
+
+// startNextBlock пропускает блоки с невыполненными условиями и задает первый вопрос следующего
+func (e *Engine) startNextBlock(ctx context.Context, session *Session, events *[]Event) (*Prompt, error) {
+	cfg := e.Config(session)
+	for session.CurrentBlock <= cfg.GetTotalBlocks() {
+		block := cfg.Blocks[session.CurrentBlock-1]
+		if len(session.PendingSummaries) > 0 && usesSummaries(block) {
+			// Условие по саммари вычисляется только после всех предыдущих саммари
+			if err := e.awaitSummaries(ctx, session); err != nil {
+				e.Logger(session).Warn("Саммари не готовы, условие блока вычисляется без них", "block_id", block.ID, "error", err)
+			}
+		}
+		if shouldRunBlock(session, cfg, block, e.Logger(session)) {
+			break
+		}
+		session.Result.SkippedBlocks = append(session.Result.SkippedBlocks, block.ID)
+		*events = append(*events, Event{Kind: EventBlockSkipped, Block: session.CurrentBlock, BlockTitle: block.Title})
+		session.CurrentBlock++
+	}
+
+	if session.CurrentBlock > cfg.GetTotalBlocks() {
+		session.Phase = PhaseBlockPending
+		return nil, e.complete(ctx, session, events)
+	}
+
+	session.QuestionCount = 0
+	session.CurrentDialogue = []storage.QA{}
+	if cfg.Blocks[session.CurrentBlock-1].Optional {
+		// Необязательный блок начнется, только если пользователь согласится (answerOptIn)
+		session.Phase = PhaseBlockOptIn
+		return e.Current(session), nil
+	}
+	return e.beginBlock(ctx, session, events)
+}
+
+// beginBlock начинает текущий блок и задает его первый вопрос
+func (e *Engine) beginBlock(ctx context.Context, session *Session, events *[]Event) (*Prompt, error) {
+	block := e.Config(session).Blocks[session.CurrentBlock-1]
+	startBlockTiming(session, time.Now())
+	*events = append(*events, Event{Kind: EventBlockStarted, Block: session.CurrentBlock, BlockTitle: block.Title})
+
+	return e.nextQuestion(ctx, session, events)
+}
+
+// complete дожидается фоновых саммари и сохраняет результат интервью
+func (e *Engine) complete(ctx context.Context, session *Session, events *[]Event) error {
+	if err := e.awaitSummaries(ctx, session); err != nil {
+		return err
+	}
+	finishInterviewTiming(session.Result, time.Now())
+	if err := storage.SaveResult(session.Result); err != nil {
+		e.metrics.RecordError("storage", err.Error())
+		return fmt.Errorf("%w: %v", ErrSave, err)
+	}
+	if err := storage.RecordUserInterview(session.Result); err != nil {
+		e.Logger(session).Warn("Не удалось записать интервью в историю пользователя", "error", err)
+	}
+
+	session.Phase = PhaseCompleted
+	e.metrics.InterviewCompleted(time.Duration(session.Result.DurationSeconds) * time.Second)
+	for _, block := range session.Result.Blocks {
+		for _, qa := range block.QuestionsAndAnswers {
+			e.metrics.VariantCompleted(storage.VariantKey(session.Result.TemplateID, qa.Variant))
+		}
+	}
+	*events = append(*events, Event{Kind: EventInterviewCompleted, Result: session.Result})
+	e.Logger(session).Info("Интервью завершено", "blocks", len(session.Result.Blocks),
+		"skipped_blocks", len(session.Result.SkippedBlocks), "duration_seconds", session.Result.DurationSeconds)
+	return nil
+}
+
+// Abandon прерывает интервью и возвращает результат пройденных блоков, помеченный как неполный
+// (nil, если ни один блок не завершен)
+func (e *Engine) Abandon(session *Session) *storage.InterviewResult {
+	result := session.Result
+	if result == nil || len(result.Blocks) == 0 {
+		return nil
+	}
+	// Готовые фоновые саммари попадают в результат, остальные блоки остаются без саммари
+	e.collectReadySummaries(session)
+	e.dropSummaries(session)
+	result.Partial = true
+	result.TotalBlocks = e.Config(session).GetTotalBlocks()
+	finishInterviewTiming(result, time.Now())
+	return result
+}
+
+// waitLLMBudget ожидает свободный токен в бюджете обращений к OpenAI
+func (e *Engine) waitLLMBudget(ctx context.Context, userID int64) error {
+	if e.llmLimiter == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, llmBudgetWaitTimeout)
+	defer cancel()
+	if err := e.llmLimiter.Wait(ctx, userID); err != nil {
+		return ErrBusy
+	}
+	return nil
+}
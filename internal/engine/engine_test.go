@@ -0,0 +1,60 @@
+package engine_test
+
+import (
+	"interview-bot-complete/internal/engine"
+	"interview-bot-complete/internal/enginetest"
+	"interview-bot-complete/internal/storage"
+	"testing"
+)
+
+// testProfile - профиль, который модель со сценарием возвращает при извлечении
+const testProfile = `{"name": "Анна", "age": 29, "profession": "аналитик данных",
+"skills": ["SQL", "Python"], "interests": ["походы", "шахматы"]}`
+
+// TestInterviewEndToEnd проводит интервью по основному шаблону от первого вопроса до профиля
+// и сравнивает сохраненный результат и профиль с эталонами testdata/*.golden.json
+func TestInterviewEndToEnd(t *testing.T) {
+	llm := enginetest.NewLLM().Profiles(testProfile)
+	h := enginetest.New(t, llm, enginetest.Options{})
+
+	user := &enginetest.User{
+		Answers: []string{
+			"Меня зовут Анна, мне 29 лет, работаю аналитиком данных.",
+			"Каждый день пишу SQL запросы и скрипты на Python для отчетов.",
+		},
+		Default: "Затрудняюсь ответить подробнее, но готова обсудить это позже.",
+	}
+	session, transcript := h.Interview(t, 42, "", user)
+
+	if session.Phase != engine.PhaseCompleted {
+		t.Fatalf("интервью не завершено: фаза %s", session.Phase)
+	}
+	if got, want := countEvents(transcript, engine.EventBlockFinished), len(session.Result.Blocks); got != want {
+		t.Errorf("событий завершения блока %d, блоков в результате %d", got, want)
+	}
+	if got := len(llm.CallsFor("summary")); got != len(session.Result.Blocks) {
+		t.Errorf("запросов саммари %d, ожидалось по одному на блок (%d)", got, len(session.Result.Blocks))
+	}
+
+	result, err := storage.LoadResult(session.InterviewID)
+	if err != nil {
+		t.Fatalf("результат интервью не сохранен: %v", err)
+	}
+	enginetest.AssertGolden(t, "interview_result", enginetest.NormalizeResult(result))
+
+	profile := h.ExtractProfile(t, session.InterviewID)
+	if !profile.Success {
+		t.Fatalf("профиль не извлечен: %s", profile.Error)
+	}
+	enginetest.AssertGolden(t, "interview_profile", enginetest.NormalizeProfile(t, profile.ProfileJSON))
+}
+
+func countEvents(transcript *enginetest.Transcript, kind engine.EventKind) int {
+	count := 0
+	for _, event := range transcript.Events {
+		if event.Kind == kind {
+			count++
+		}
+	}
+	return count
+}
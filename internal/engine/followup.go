@@ -0,0 +1,40 @@
+package engine
+
+import "interview-bot-complete/internal/language"
+
+// defaultFollowups - заготовленные уточняющие вопросы на случай, когда модель дважды
+// вернула недопустимый вопрос
+var defaultFollowups = map[string][]string{
+	language.Russian: {
+		"Могли бы вы привести конкретный пример из своего опыта, связанный с этим?",
+		"Что для вас было самым важным в этой ситуации?",
+		"Как этот опыт повлиял на ваши дальнейшие решения?",
+	},
+	language.English: {
+		"Could you share a specific example from your experience related to this?",
+		"What mattered most to you in that situation?",
+		"How did this experience influence your later decisions?",
+	},
+}
+
+// fallbackFollowup возвращает заготовленный уточняющий вопрос на языке lang, еще не заданный
+// в текущем блоке (пусто - все заготовленные вопросы уже заданы)
+func fallbackFollowup(session *Session, lang string) string {
+	questions, ok := defaultFollowups[lang]
+	if !ok {
+		questions = defaultFollowups[language.Default]
+	}
+	for _, question := range questions {
+		asked := false
+		for _, qa := range session.CurrentDialogue {
+			if qa.Question == question {
+				asked = true
+				break
+			}
+		}
+		if !asked {
+			return question
+		}
+	}
+	return ""
+}
@@ -0,0 +1,31 @@
+package engine
+
+// RecordQuestionMessage запоминает ID сообщения фронтенда, в котором задан текущий вопрос
+// или уточнение к нему. При повторной отправке вопроса запоминается последнее сообщение.
+func (e *Engine) RecordQuestionMessage(session *Session, messageID int) {
+	if messageID == 0 || len(session.CurrentDialogue) == 0 {
+		return
+	}
+	if qa := pendingClarification(session); qa != nil {
+		qa.Clarification.QuestionMessageID = messageID
+		return
+	}
+	if qa := &session.CurrentDialogue[len(session.CurrentDialogue)-1]; qa.Answer == "" {
+		qa.QuestionMessageID = messageID
+	}
+}
+
+// RecordAnswerMessage запоминает ID сообщения с ответом на текущий вопрос или уточнение;
+// вызывается перед Advance. Если ответ будет отклонен, ID перезапишет следующая попытка.
+func (e *Engine) RecordAnswerMessage(session *Session, messageID int) {
+	if messageID == 0 || len(session.CurrentDialogue) == 0 {
+		return
+	}
+	if qa := pendingClarification(session); qa != nil {
+		qa.Clarification.AnswerMessageID = messageID
+		return
+	}
+	if qa := &session.CurrentDialogue[len(session.CurrentDialogue)-1]; qa.Answer == "" {
+		qa.AnswerMessageID = messageID
+	}
+}
@@ -0,0 +1,28 @@
+package engine
+
+// FlagAnswer отмечает вопрос, ожидающий ответа (или ожидающий уточнения), категориями модерации. Вызывается до Advance:
+// ответ записывается в отмеченный вопрос, а политика модерации решает, попадет ли он в анализ профиля.
+func (e *Engine) FlagAnswer(session *Session, categories []string) {
+	if e.Current(session) == nil || len(categories) == 0 {
+		return
+	}
+	qa := pendingClarification(session)
+	if qa == nil {
+		qa = &session.CurrentDialogue[len(session.CurrentDialogue)-1]
+	}
+	for _, category := range categories {
+		if !containsString(qa.Moderation, category) {
+			qa.Moderation = append(qa.Moderation, category)
+		}
+	}
+	e.Logger(session).Warn("Ответ отмечен модерацией", "categories", qa.Moderation)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"interview-bot-complete/internal/config"
+	"interview-bot-complete/internal/language"
+)
+
+// optInOptions - варианты ответа на вопрос перед необязательным блоком: согласие, затем отказ
+var optInOptions = map[string][]string{
+	language.Russian: {"Да, давайте", "Пропустить тему"},
+	language.English: {"Yes, let's", "Skip this topic"},
+}
+
+// optInQuestions - вопрос перед необязательным блоком без opt_in_question: %s - название блока
+var optInQuestions = map[string]string{
+	language.Russian: "Следующая тема - «%s». Она необязательная: хотите поговорить об этом?",
+	language.English: "The next topic is \"%s\". It's optional: would you like to talk about it?",
+}
+
+// optInChoice возвращает варианты ответа на вопрос о необязательном блоке на языке шаблона
+func optInChoice(cfg *config.Config) *config.Choice {
+	options, ok := optInOptions[cfg.Language()]
+	if !ok {
+		options = optInOptions[language.Default]
+	}
+	return &config.Choice{Options: options, Keyboard: config.KeyboardInline}
+}
+
+// optInPrompt возвращает вопрос о том, проходить ли необязательный текущий блок
+func (e *Engine) optInPrompt(session *Session) *Prompt {
+	cfg := e.Config(session)
+	block := cfg.Blocks[session.CurrentBlock-1]
+	text := block.OptInQuestion
+	if text == "" {
+		template, ok := optInQuestions[cfg.Language()]
+		if !ok {
+			template = optInQuestions[language.Default]
+		}
+		text = fmt.Sprintf(template, block.Title)
+	}
+	choice := optInChoice(cfg)
+	return &Prompt{
+		Kind:       PromptOptIn,
+		Text:       text,
+		Block:      session.CurrentBlock,
+		BlockTitle: block.Title,
+		Options:    choice.Options,
+		Keyboard:   choice.Keyboard,
+	}
+}
+
+// answerOptIn принимает решение пользователя о необязательном блоке: при согласии блок начинается,
+// при отказе пропускается и отмечается в DeclinedBlocks результата
+func (e *Engine) answerOptIn(ctx context.Context, session *Session, answer string) (*Prompt, []Event, error) {
+	cfg := e.Config(session)
+	choice := optInChoice(cfg)
+	option, err := matchChoice(choice, answer)
+	if err != nil {
+		return e.Current(session), nil, err
+	}
+	return e.decideOptIn(ctx, session, option == choice.Options[0])
+}
+
+// decideOptIn начинает необязательный блок (accepted) или пропускает его
+func (e *Engine) decideOptIn(ctx context.Context, session *Session, accepted bool) (*Prompt, []Event, error) {
+	block := e.Config(session).Blocks[session.CurrentBlock-1]
+	session.Phase = PhaseAnswering
+	var events []Event
+	if accepted {
+		e.Logger(session).Info("Необязательный блок принят", "block_id", block.ID)
+		prompt, err := e.beginBlock(ctx, session, &events)
+		return prompt, events, err
+	}
+
+	e.Logger(session).Info("Необязательный блок отклонен", "block_id", block.ID)
+	session.Result.SkippedBlocks = append(session.Result.SkippedBlocks, block.ID)
+	session.Result.DeclinedBlocks = append(session.Result.DeclinedBlocks, block.ID)
+	events = append(events, Event{Kind: EventBlockDeclined, Block: session.CurrentBlock, BlockTitle: block.Title})
+	session.CurrentBlock++
+	prompt, err := e.startNextBlock(ctx, session, &events)
+	return prompt, events, err
+}
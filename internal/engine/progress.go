@@ -0,0 +1,86 @@
+package engine
+
+import (
+	"interview-bot-complete/internal/config"
+	"interview-bot-complete/internal/storage"
+	"time"
+)
+
+// Progress - положение пользователя в интервью и оценка оставшегося времени
+type Progress struct {
+	Block       int // текущий блок (с 1)
+	TotalBlocks int
+	Question    int // текущий вопрос блока (с 1)
+	// BlockQuestions - наибольшее число вопросов блока: вопросы шаблона и уточняющие
+	BlockQuestions int
+	// Remaining - оценка оставшегося времени по средней длительности ответа; 0, пока ответов нет
+	Remaining time.Duration
+}
+
+// Progress вычисляет прогресс интервью. Оставшиеся вопросы считаются по максимуму
+// для каждого блока, поэтому оценка времени - верхняя граница.
+func (e *Engine) Progress(session *Session) Progress {
+	cfg := e.Config(session)
+	progress := Progress{
+		Block:       session.CurrentBlock,
+		TotalBlocks: cfg.GetTotalBlocks(),
+		Question:    session.QuestionCount + 1,
+	}
+	if session.CurrentBlock < 1 || session.CurrentBlock > len(cfg.Blocks) {
+		return progress
+	}
+
+	progress.BlockQuestions = blockQuestionLimit(cfg, cfg.Blocks[session.CurrentBlock-1])
+	if progress.Question > progress.BlockQuestions {
+		progress.Question = progress.BlockQuestions
+	}
+
+	remaining := progress.BlockQuestions - session.QuestionCount
+	for _, block := range cfg.Blocks[session.CurrentBlock:] {
+		remaining += blockQuestionLimit(cfg, block)
+	}
+	if average, ok := averageAnswerTime(session); ok && remaining > 0 {
+		progress.Remaining = average * time.Duration(remaining)
+	}
+	return progress
+}
+
+// blockQuestionLimit возвращает наибольшее число вопросов блока (см. nextQuestion)
+func blockQuestionLimit(cfg *config.Config, block config.Block) int {
+	limit := cfg.GetQuestionsPerBlock() + cfg.GetMaxFollowupQuestions()
+	if withFollowups := len(block.Questions) + cfg.GetMaxFollowupQuestions(); withFollowups < limit {
+		limit = withFollowups
+	}
+	return limit
+}
+
+// averageAnswerTime возвращает среднее время от вопроса до ответа по отвеченным вопросам интервью
+func averageAnswerTime(session *Session) (time.Duration, bool) {
+	var total time.Duration
+	count := 0
+	add := func(qas []storage.QA) {
+		for _, qa := range qas {
+			asked, err := time.Parse(time.RFC3339, qa.AskedAt)
+			if err != nil {
+				continue
+			}
+			answered, err := time.Parse(time.RFC3339, qa.AnsweredAt)
+			if err != nil || answered.Before(asked) {
+				continue
+			}
+			total += answered.Sub(asked)
+			count++
+		}
+	}
+	if session.Result != nil {
+		for _, block := range session.Result.Blocks {
+			add(block.QuestionsAndAnswers)
+		}
+	}
+	add(session.CurrentDialogue)
+
+	if count == 0 {
+		return 0, false
+	}
+	return total / time.Duration(count), true
+}
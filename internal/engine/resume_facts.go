@@ -0,0 +1,53 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"interview-bot-complete/internal/config"
+	"interview-bot-complete/internal/interviewer"
+	"interview-bot-complete/internal/language"
+)
+
+// resumeConfirmations - вопрос шаблона с просьбой подтвердить факт из резюме: %s - вопрос, %s - значение
+var resumeConfirmations = map[string]string{
+	language.Russian: "%s\n\n📄 В резюме указано: %s. Если все верно, ответьте «да», если что-то изменилось - напишите, как сейчас.",
+	language.English: "%s\n\n📄 Your resume says: %s. If that's right, answer \"yes\"; if something has changed, tell me how it is now.",
+}
+
+// SetResumeFacts подключает к интервью факты из резюме (JSON-объект «поле профиля - значение»).
+// Вопросы шаблона об известных полях задаются как просьба подтвердить факт, уточняющие вопросы
+// их не повторяют, а поля профиля, не раскрытые в ответах, заполняются ими при извлечении.
+func (e *Engine) SetResumeFacts(session *Session, factsJSON string) error {
+	var facts map[string]interface{}
+	if err := json.Unmarshal([]byte(factsJSON), &facts); err != nil {
+		return fmt.Errorf("ошибка разбора фактов из резюме: %w", err)
+	}
+	text, err := interviewer.ProfileMemory(factsJSON)
+	if err != nil {
+		return err
+	}
+	if text == "" {
+		return fmt.Errorf("в резюме нет фактов для профиля")
+	}
+	session.ResumeFacts = text
+	session.Result.ResumeFacts = facts
+	return nil
+}
+
+// resumeConfirmation возвращает вопрос slot шаблона, заданный как просьба подтвердить факт из резюме;
+// пусто - у вопроса нет поля профиля или поле в резюме не указано
+func (e *Engine) resumeConfirmation(session *Session, block config.Block, slot int, question string) string {
+	field := block.ProfileFieldFor(slot)
+	if field == "" || block.ChoiceFor(slot) != nil || session.Result.ResumeFacts == nil {
+		return ""
+	}
+	value := interviewer.FieldValue(session.Result.ResumeFacts[field])
+	if value == "" {
+		return ""
+	}
+	template, ok := resumeConfirmations[e.Config(session).Language()]
+	if !ok {
+		template = resumeConfirmations[language.Default]
+	}
+	return fmt.Sprintf(template, question, value)
+}
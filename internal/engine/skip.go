@@ -0,0 +1,50 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"interview-bot-complete/internal/language"
+	"time"
+)
+
+// ErrNothingToSkip - сейчас нет вопроса, ожидающего ответа
+var ErrNothingToSkip = errors.New("сейчас нет вопроса, который можно пропустить")
+
+// skippedAnswers - ответ, записываемый вместо пропущенного вопроса
+var skippedAnswers = map[string]string{
+	language.Russian: "(вопрос пропущен)",
+	language.English: "(question skipped)",
+}
+
+// Skip пропускает текущий вопрос и переводит интервью к следующему шагу. Пропущенный вопрос
+// расходует лимит вопросов блока; пропуск уточнения оставляет исходный ответ без дополнения,
+// а пропуск вопроса о необязательном блоке пропускает блок.
+func (e *Engine) Skip(ctx context.Context, session *Session) (*Prompt, []Event, error) {
+	if e.Current(session) == nil {
+		return nil, nil, ErrNothingToSkip
+	}
+	if session.Phase == PhaseBlockOptIn {
+		// Пропуск вопроса о необязательном блоке - отказ от блока
+		return e.decideOptIn(ctx, session, false)
+	}
+
+	e.collectReadySummaries(session)
+	now := time.Now().Format(time.RFC3339)
+	if qa := pendingClarification(session); qa != nil {
+		qa.Clarification.AnsweredAt = now
+	} else {
+		qa := &session.CurrentDialogue[len(session.CurrentDialogue)-1]
+		answer, ok := skippedAnswers[e.Config(session).Language()]
+		if !ok {
+			answer = skippedAnswers[language.Default]
+		}
+		qa.Answer = answer
+		qa.AnsweredAt = now
+		qa.Skipped = true
+	}
+
+	countAnsweredQuestion(session)
+	var events []Event
+	prompt, err := e.nextQuestion(ctx, session, &events)
+	return prompt, events, err
+}
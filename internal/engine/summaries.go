@@ -0,0 +1,155 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"interview-bot-complete/internal/config"
+	"interview-bot-complete/internal/storage"
+	"sync"
+	"time"
+)
+
+// summaryJobTTL - сколько хранить готовое саммари, которое не забрала ни одна сессия
+// (интервью брошено или продолжено на другой реплике)
+const summaryJobTTL = time.Hour
+
+// summaryJob - саммари блока, создаваемое в фоне
+type summaryJob struct {
+	done     chan struct{}
+	summary  *storage.BlockSummary
+	err      error
+	finished time.Time
+}
+
+// summaryJobs - фоновые саммари по ключу интервью и блока. Горутины не трогают Session:
+// результат забирается в сессию в порядке блоков (collectReadySummaries, awaitSummaries).
+type summaryJobs struct {
+	mu   sync.Mutex
+	jobs map[string]*summaryJob
+}
+
+func newSummaryJobs() *summaryJobs {
+	return &summaryJobs{jobs: make(map[string]*summaryJob)}
+}
+
+func summaryJobKey(interviewID string, index int) string {
+	return fmt.Sprintf("%s/%d", interviewID, index)
+}
+
+func (s *summaryJobs) get(key string) *summaryJob {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.jobs[key]
+}
+
+func (s *summaryJobs) remove(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.jobs, key)
+}
+
+// add регистрирует задачу и удаляет давно готовые, которые никто не забрал
+func (s *summaryJobs) add(key string, job *summaryJob) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	for k, old := range s.jobs {
+		select {
+		case <-old.done:
+			if now.Sub(old.finished) > summaryJobTTL {
+				delete(s.jobs, k)
+			}
+		default:
+		}
+	}
+	s.jobs[key] = job
+}
+
+// startSummary запускает создание саммари блока index результата в фоне
+func (e *Engine) startSummary(session *Session, index int, cfg *config.Config) {
+	dialogue := append([]storage.QA(nil), session.Result.Blocks[index].QuestionsAndAnswers...)
+	job := &summaryJob{done: make(chan struct{})}
+	e.summaries.add(summaryJobKey(session.InterviewID, index), job)
+
+	userID := session.UserID
+	logger := e.Logger(session)
+	go func() {
+		defer close(job.done)
+		defer func() { job.finished = time.Now() }()
+		if err := e.waitLLMBudget(context.Background(), userID); err != nil {
+			job.err = err
+			return
+		}
+		job.summary, job.err = e.interviewer.WithLogger(logger).CreateSummary(dialogue, cfg)
+		if job.err != nil {
+			logger.Warn("Не удалось создать саммари блока в фоне", "block_index", index, "error", job.err)
+		}
+	}()
+}
+
+// collectReadySummaries переносит готовые фоновые саммари в сессию строго в порядке блоков:
+// CumulativeSummaries пополняется, только пока все предыдущие саммари готовы
+func (e *Engine) collectReadySummaries(session *Session) {
+	for len(session.PendingSummaries) > 0 {
+		job := e.summaries.get(summaryJobKey(session.InterviewID, session.PendingSummaries[0]))
+		if job == nil {
+			return
+		}
+		select {
+		case <-job.done:
+		default:
+			return
+		}
+		if job.err != nil {
+			// Неудачное саммари создается заново в awaitSummaries
+			return
+		}
+		e.applySummary(session, job)
+	}
+}
+
+// awaitSummaries дожидается всех фоновых саммари. Потерянные (например, после перезапуска)
+// и неудачные создаются заново; при ошибке следующий вызов повторит попытку.
+func (e *Engine) awaitSummaries(ctx context.Context, session *Session) error {
+	cfg := e.Config(session)
+	for len(session.PendingSummaries) > 0 {
+		index := session.PendingSummaries[0]
+		key := summaryJobKey(session.InterviewID, index)
+		job := e.summaries.get(key)
+		if job == nil {
+			e.startSummary(session, index, cfg)
+			job = e.summaries.get(key)
+		}
+
+		select {
+		case <-job.done:
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %v", ErrSummary, ctx.Err())
+		}
+		if job.err != nil {
+			e.summaries.remove(key)
+			return fmt.Errorf("%w: %v", ErrSummary, job.err)
+		}
+		e.applySummary(session, job)
+	}
+	return nil
+}
+
+// applySummary записывает готовое саммари первого ожидающего блока в результат
+func (e *Engine) applySummary(session *Session, job *summaryJob) {
+	index := session.PendingSummaries[0]
+	e.summaries.remove(summaryJobKey(session.InterviewID, index))
+	session.Result.Blocks[index].Summary = job.summary
+	session.CumulativeSummaries = append(session.CumulativeSummaries, *job.summary)
+	session.PendingSummaries = session.PendingSummaries[1:]
+	if len(session.PendingSummaries) == 0 {
+		session.PendingSummaries = nil
+	}
+}
+
+// dropSummaries забывает фоновые саммари прерванного интервью
+func (e *Engine) dropSummaries(session *Session) {
+	for _, index := range session.PendingSummaries {
+		e.summaries.remove(summaryJobKey(session.InterviewID, index))
+	}
+}
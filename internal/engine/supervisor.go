@@ -0,0 +1,62 @@
+package engine
+
+import (
+	"errors"
+	"interview-bot-complete/internal/storage"
+	"strings"
+	"time"
+)
+
+// GeneratedBySupervisor - отметка GeneratedBy вопроса, заданного человеком-супервизором
+const GeneratedBySupervisor = "supervisor"
+
+// InjectQuestion ставит вопрос супервизора в очередь. Вопрос задается следующим, раньше вопросов
+// шаблона и модели, записывается в диалог как обычный вопрос и не расходует лимит вопросов блока.
+func (e *Engine) InjectQuestion(session *Session, question string) error {
+	if session.Phase == PhaseCompleted {
+		return ErrCompleted
+	}
+	question = strings.TrimSpace(question)
+	if question == "" {
+		return errors.New("пустой вопрос")
+	}
+	session.InjectedQuestions = append(session.InjectedQuestions, question)
+	e.Logger(session).Info("Вопрос супервизора поставлен в очередь", "queued", len(session.InjectedQuestions))
+	return nil
+}
+
+// nextInjectedQuestion задает первый вопрос из очереди супервизора; nil - очередь пуста
+func (e *Engine) nextInjectedQuestion(session *Session) *Prompt {
+	if len(session.InjectedQuestions) == 0 {
+		return nil
+	}
+	question := session.InjectedQuestions[0]
+	session.InjectedQuestions = session.InjectedQuestions[1:]
+	session.CurrentDialogue = append(session.CurrentDialogue, storage.QA{
+		Question:    question,
+		AskedAt:     time.Now().Format(time.RFC3339),
+		GeneratedBy: GeneratedBySupervisor,
+		Source:      storage.QuestionSourceSupervisor,
+	})
+	session.Phase = PhaseAnswering
+	return e.Current(session)
+}
+
+// countAnsweredQuestion засчитывает ответ на последний вопрос диалога в лимит вопросов блока;
+// вопросы супервизора не засчитываются
+func countAnsweredQuestion(session *Session) {
+	if n := len(session.CurrentDialogue); n > 0 && session.CurrentDialogue[n-1].GeneratedBy == GeneratedBySupervisor {
+		return
+	}
+	session.QuestionCount++
+}
+
+// templateIndex возвращает номер вопроса шаблона (с нуля) для вопроса диалога index без учета вопросов супервизора
+func templateIndex(session *Session, index int) int {
+	for i := 0; i < index && i < len(session.CurrentDialogue); i++ {
+		if session.CurrentDialogue[i].GeneratedBy == GeneratedBySupervisor {
+			index--
+		}
+	}
+	return index
+}
@@ -0,0 +1,31 @@
+package engine
+
+import (
+	"interview-bot-complete/internal/storage"
+	"time"
+)
+
+// startBlockTiming отмечает начало блока
+func startBlockTiming(session *Session, now time.Time) {
+	session.BlockStartedAt = now
+	session.BlockNudged = false
+}
+
+// finishBlockTiming записывает время блока в результат
+func finishBlockTiming(session *Session, block *storage.BlockResult, now time.Time) {
+	if session.BlockStartedAt.IsZero() {
+		return
+	}
+	block.StartedAt = session.BlockStartedAt.Format(time.RFC3339)
+	block.FinishedAt = now.Format(time.RFC3339)
+	block.DurationSeconds = int(now.Sub(session.BlockStartedAt).Seconds())
+	block.TimeLimitExceeded = session.BlockNudged
+}
+
+// finishInterviewTiming записывает время завершения и общую длительность интервью
+func finishInterviewTiming(result *storage.InterviewResult, now time.Time) {
+	result.CompletedAt = now.Format(time.RFC3339)
+	if started, err := time.Parse(time.RFC3339, result.Timestamp); err == nil {
+		result.DurationSeconds = int(now.Sub(started).Seconds())
+	}
+}
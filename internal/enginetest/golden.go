@@ -0,0 +1,118 @@
+package enginetest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"interview-bot-complete/internal/storage"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// UpdateGoldenEnv - переменная окружения, при заданном значении которой AssertGolden
+// перезаписывает эталонные файлы вместо сравнения: UPDATE_GOLDEN=1 go test ./...
+const UpdateGoldenEnv = "UPDATE_GOLDEN"
+
+// placeholderID заменяет ID интервью в нормализованных данных
+const placeholderID = "<interview_id>"
+
+// volatileMetadata - поля _metadata профиля, зависящие от времени прогона
+var volatileMetadata = []string{"creation_date", "duration_seconds", "block_durations_seconds"}
+
+// NormalizeResult возвращает копию результата без ID интервью и меток времени,
+// чтобы его можно было сравнить с эталоном
+func NormalizeResult(result *storage.InterviewResult) *storage.InterviewResult {
+	data, _ := json.Marshal(result)
+	var normalized storage.InterviewResult
+	json.Unmarshal(data, &normalized)
+
+	normalized.InterviewID = placeholderID
+	normalized.Timestamp = ""
+	normalized.CompletedAt = ""
+	normalized.DurationSeconds = 0
+	if normalized.Consent != nil {
+		normalized.Consent.AcceptedAt = ""
+	}
+	for i := range normalized.Blocks {
+		block := &normalized.Blocks[i]
+		block.StartedAt, block.FinishedAt, block.DurationSeconds = "", "", 0
+		for j := range block.QuestionsAndAnswers {
+			qa := &block.QuestionsAndAnswers[j]
+			qa.AskedAt, qa.AnsweredAt = "", ""
+			if qa.Clarification != nil {
+				qa.Clarification.AskedAt = ""
+			}
+		}
+	}
+	return &normalized
+}
+
+// NormalizeProfile разбирает JSON профиля и убирает из _metadata ID интервью и поля времени
+func NormalizeProfile(t testing.TB, profileJSON string) map[string]interface{} {
+	t.Helper()
+	var profile map[string]interface{}
+	if err := json.Unmarshal([]byte(profileJSON), &profile); err != nil {
+		t.Fatalf("enginetest: JSON профиля: %v", err)
+	}
+	if metadata, ok := profile["_metadata"].(map[string]interface{}); ok {
+		if _, ok := metadata["interview_id"]; ok {
+			metadata["interview_id"] = placeholderID
+		}
+		for _, key := range volatileMetadata {
+			delete(metadata, key)
+		}
+	}
+	return profile
+}
+
+// AssertGolden сравнивает got в JSON с эталоном testdata/<name>.golden.json пакета теста
+func AssertGolden(t testing.TB, name string, got interface{}) {
+	t.Helper()
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(got); err != nil {
+		t.Fatalf("enginetest: сериализация %s: %v", name, err)
+	}
+	data := buf.Bytes()
+	path := filepath.Join("testdata", name+".golden.json")
+
+	if os.Getenv(UpdateGoldenEnv) != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("enginetest: %v", err)
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatalf("enginetest: запись эталона %s: %v", path, err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("enginetest: эталон %s не прочитан (%v); создайте его с %s=1", path, err, UpdateGoldenEnv)
+	}
+	if string(want) != string(data) {
+		t.Errorf("enginetest: %s отличается от эталона %s:\n%s", name, path, firstDifference(string(want), string(data)))
+	}
+}
+
+// firstDifference описывает первую отличающуюся строку эталона и фактического значения
+func firstDifference(want, got string) string {
+	wantLines, gotLines := strings.Split(want, "\n"), strings.Split(got, "\n")
+	for i := 0; i < len(wantLines) || i < len(gotLines); i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w != g {
+			return fmt.Sprintf("строка %d:\n  эталон:   %s\n  получено: %s", i+1, w, g)
+		}
+	}
+	return ""
+}
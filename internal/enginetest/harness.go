@@ -0,0 +1,125 @@
+package enginetest
+
+import (
+	"context"
+	"interview-bot-complete/internal/config"
+	"interview-bot-complete/internal/engine"
+	"interview-bot-complete/internal/extractor"
+	"interview-bot-complete/internal/interviewer"
+	"interview-bot-complete/internal/storage"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Options задает файлы харнесса; пути относительны корня модуля
+type Options struct {
+	// Template - шаблон интервью по умолчанию (пусто - config/interview.yaml)
+	Template string
+	// TemplatesDir - каталог дополнительных шаблонов (пусто - только шаблон по умолчанию)
+	TemplatesDir string
+	// Schema - схема профиля (пусто - config/profile_schema.yaml)
+	Schema string
+}
+
+// Harness - движок интервью и экстрактор профилей, обращающиеся к LLM со сценарием.
+// Результаты и профили сохраняются во временный каталог теста.
+type Harness struct {
+	LLM         *LLM
+	Engine      *engine.Engine
+	Interviewer *interviewer.Service
+	Extractor   *extractor.Service
+	// Dir - каталог хранилища теста (results и output)
+	Dir string
+}
+
+// New собирает харнесс для теста. Пути хранилища глобальны, поэтому тесты
+// с харнессом не должны выполняться параллельно.
+func New(t testing.TB, llm *LLM, opts Options) *Harness {
+	t.Helper()
+	root := ModuleRoot(t)
+	if opts.Template == "" {
+		opts.Template = "config/interview.yaml"
+	}
+	if opts.Schema == "" {
+		opts.Schema = "config/profile_schema.yaml"
+	}
+	templatesDir := ""
+	if opts.TemplatesDir != "" {
+		templatesDir = filepath.Join(root, opts.TemplatesDir)
+	}
+
+	templates, err := config.LoadTemplates(filepath.Join(root, opts.Template), templatesDir)
+	if err != nil {
+		t.Fatalf("enginetest: загрузка шаблонов: %v", err)
+	}
+
+	interviewerService := interviewer.New("")
+	interviewerService.SetCompleter(llm)
+	extractorService, err := extractor.NewWithSchema("", filepath.Join(root, opts.Schema))
+	if err != nil {
+		t.Fatalf("enginetest: создание экстрактора: %v", err)
+	}
+	extractorService.SetCompleter(llm)
+
+	dir := t.TempDir()
+	if err := storage.Configure(storage.Paths{
+		ResultsDir: filepath.Join(dir, "results"),
+		OutputDir:  filepath.Join(dir, "output"),
+	}); err != nil {
+		t.Fatalf("enginetest: настройка хранилища: %v", err)
+	}
+	t.Cleanup(func() { storage.Configure(storage.DefaultPaths()) })
+
+	return &Harness{
+		LLM:         llm,
+		Engine:      engine.New(templates, interviewerService),
+		Interviewer: interviewerService,
+		Extractor:   extractorService,
+		Dir:         dir,
+	}
+}
+
+// Interview проводит интервью пользователя userID по шаблону templateID до завершения
+func (h *Harness) Interview(t testing.TB, userID int64, templateID string, user *User) (*engine.Session, *Transcript) {
+	t.Helper()
+	session := h.Engine.NewInterview(userID, templateID)
+	transcript, err := Run(context.Background(), h.Engine, session, user)
+	if err != nil {
+		t.Fatalf("enginetest: интервью: %v", err)
+	}
+	return session, transcript
+}
+
+// ExtractProfile извлекает профиль из сохраненного результата интервью
+func (h *Harness) ExtractProfile(t testing.TB, interviewID string) *extractor.ProfileResult {
+	t.Helper()
+	result, err := storage.LoadResult(interviewID)
+	if err != nil {
+		t.Fatalf("enginetest: загрузка результата: %v", err)
+	}
+	profile, err := h.Extractor.ExtractProfile(result)
+	if err != nil {
+		t.Fatalf("enginetest: извлечение профиля: %v", err)
+	}
+	return profile
+}
+
+// ModuleRoot возвращает корень модуля (каталог с go.mod) над рабочим каталогом теста
+func ModuleRoot(t testing.TB) string {
+	t.Helper()
+	dir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("enginetest: рабочий каталог: %v", err)
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			t.Fatalf("enginetest: go.mod не найден")
+		}
+		dir = parent
+	}
+}
@@ -0,0 +1,130 @@
+package enginetest
+
+import (
+	"fmt"
+	"interview-bot-complete/internal/api"
+	"interview-bot-complete/internal/config"
+	"interview-bot-complete/internal/jsonrepair"
+	"interview-bot-complete/internal/prompts"
+	"strings"
+	"sync"
+)
+
+// DefaultSummary - саммари блока, которое LLM возвращает, если саммари не заданы сценарием
+const DefaultSummary = `{"summary": "Человек подробно рассказал о себе.", "key_facts": ["Отвечает развернуто"],
+"important_themes": ["работа"], "emotional_markers": [], "behavioral_patterns": [], "values_beliefs": [],
+"priorities": [], "sensitive_topics": []}`
+
+// Call - обращение к LLM: сценарий и полный текст промпта
+type Call struct {
+	UseCase string
+	Prompt  string
+}
+
+// LLM - модель со сценарием: на каждый сценарий (config.UseCase* и api.UseCase*) отвечает
+// заданными ответами по очереди, последний ответ повторяется. Запросы записываются для проверок.
+// Сценарий без ответов - ошибка, чтобы тест не проходил на неожиданном обращении к модели;
+// исключения - саммари блоков (DefaultSummary) и исправление JSON (локальное восстановление).
+type LLM struct {
+	mu        sync.Mutex
+	responses map[string][]string
+	errors    map[string]error
+	served    map[string]int
+	calls     []Call
+}
+
+// NewLLM создает LLM без заданных ответов
+func NewLLM() *LLM {
+	return &LLM{
+		responses: make(map[string][]string),
+		errors:    make(map[string]error),
+		served:    make(map[string]int),
+	}
+}
+
+// On задает ответы на сценарий useCase (дописываются к уже заданным)
+func (l *LLM) On(useCase string, responses ...string) *LLM {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.responses[useCase] = append(l.responses[useCase], responses...)
+	return l
+}
+
+// Questions задает уточняющие вопросы интервьюера
+func (l *LLM) Questions(questions ...string) *LLM {
+	return l.On(config.UseCaseQuestions, questions...)
+}
+
+// Summaries задает JSON саммари блоков в порядке блоков
+func (l *LLM) Summaries(summaries ...string) *LLM {
+	return l.On(config.UseCaseSummary, summaries...)
+}
+
+// Profiles задает JSON профилей, возвращаемых при извлечении
+func (l *LLM) Profiles(profiles ...string) *LLM {
+	return l.On(config.UseCaseExtraction, profiles...)
+}
+
+// Fail задает ошибку для всех следующих обращений сценария useCase
+func (l *LLM) Fail(useCase string, err error) *LLM {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.errors[useCase] = err
+	return l
+}
+
+// Complete реализует api.Completer
+func (l *LLM) Complete(useCase, prompt string) (string, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.calls = append(l.calls, Call{UseCase: useCase, Prompt: prompt})
+
+	if err := l.errors[useCase]; err != nil {
+		return "", err
+	}
+	responses := l.responses[useCase]
+	if len(responses) == 0 {
+		switch useCase {
+		case config.UseCaseSummary:
+			return DefaultSummary, nil
+		case api.UseCaseJSONRepair:
+			return repairJSON(prompt), nil
+		}
+		return "", fmt.Errorf("enginetest: нет ответа для сценария %s", useCase)
+	}
+
+	index := l.served[useCase]
+	l.served[useCase]++
+	if index >= len(responses) {
+		index = len(responses) - 1
+	}
+	return responses[index], nil
+}
+
+// Calls возвращает обращения к LLM по порядку
+func (l *LLM) Calls() []Call {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]Call(nil), l.calls...)
+}
+
+// CallsFor возвращает обращения сценария useCase
+func (l *LLM) CallsFor(useCase string) []Call {
+	var calls []Call
+	for _, call := range l.Calls() {
+		if call.UseCase == useCase {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// repairJSON исправляет JSON из промпта исправления локальным восстановлением
+func repairJSON(prompt string) string {
+	_, broken, _ := strings.Cut(prompt, prompts.JSONRepairInputHeader)
+	broken, _, _ = strings.Cut(broken, prompts.JSONRepairOutputHeader)
+	if fixed, _, err := jsonrepair.Repair(broken); err == nil {
+		return fixed
+	}
+	return "{}"
+}
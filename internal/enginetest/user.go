@@ -0,0 +1,86 @@
+package enginetest
+
+import (
+	"context"
+	"fmt"
+	"interview-bot-complete/internal/engine"
+)
+
+// maxSteps - предел шагов интервью в Run, чтобы ошибка сценария не зациклила тест
+const maxSteps = 500
+
+// User - пользователь со сценарием: отвечает заданными ответами по порядку,
+// после их окончания - Default. Уточнения получают ответы наравне с вопросами.
+type User struct {
+	Answers []string
+	// Default - ответ после окончания Answers (пустой - ошибка в Run)
+	Default string
+	// Respond, если задан, отвечает вместо Answers (например, по тексту вопроса)
+	Respond func(prompt *engine.Prompt) string
+	// DeclineOptional - отказываться от необязательных блоков (по умолчанию пользователь их проходит);
+	// ответы на вопросы о них не расходуют Answers
+	DeclineOptional bool
+
+	next int
+}
+
+// Answer возвращает ответ на вопрос
+func (u *User) Answer(prompt *engine.Prompt) (string, error) {
+	if prompt.Kind == engine.PromptOptIn && len(prompt.Options) == 2 {
+		if u.DeclineOptional {
+			return prompt.Options[1], nil
+		}
+		return prompt.Options[0], nil
+	}
+	if u.Respond != nil {
+		return u.Respond(prompt), nil
+	}
+	if u.next < len(u.Answers) {
+		u.next++
+		return u.Answers[u.next-1], nil
+	}
+	if u.Default == "" {
+		return "", fmt.Errorf("enginetest: у пользователя закончились ответы (блок %d, вопрос %d: %s)", prompt.Block, prompt.Number, prompt.Text)
+	}
+	return u.Default, nil
+}
+
+// Transcript - ход интервью: заданные вопросы и события в порядке появления
+type Transcript struct {
+	Prompts []engine.Prompt
+	Events  []engine.Event
+}
+
+// Run проводит интервью session от начала до завершения: отвечает за пользователя
+// и подтверждает блоки, ожидающие проверки (ReviewBlocks)
+func Run(ctx context.Context, eng *engine.Engine, session *engine.Session, user *User) (*Transcript, error) {
+	transcript := &Transcript{}
+	prompt, events, err := eng.Start(ctx, session)
+	for step := 0; ; step++ {
+		transcript.Events = append(transcript.Events, events...)
+		if err != nil {
+			return transcript, err
+		}
+		if session.Phase == engine.PhaseCompleted {
+			return transcript, nil
+		}
+		if step >= maxSteps {
+			return transcript, fmt.Errorf("enginetest: интервью не завершилось за %d шагов", maxSteps)
+		}
+
+		if session.Phase == engine.PhaseBlockReview {
+			prompt, events, err = eng.ConfirmBlock(ctx, session)
+			continue
+		}
+		if prompt == nil {
+			return transcript, fmt.Errorf("enginetest: нет вопроса в фазе %s", session.Phase)
+		}
+		transcript.Prompts = append(transcript.Prompts, *prompt)
+
+		answer, answerErr := user.Answer(prompt)
+		if answerErr != nil {
+			return transcript, answerErr
+		}
+		prompt, events, err = eng.Advance(ctx, session, answer)
+	}
+}
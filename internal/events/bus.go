@@ -0,0 +1,117 @@
+package events
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Kind - вид события интервью
+type Kind string
+
+const (
+	InterviewStarted   Kind = "interview_started"
+	BlockCompleted     Kind = "block_completed"
+	InterviewCompleted Kind = "interview_completed"
+	ProfileExtracted   Kind = "profile_extracted"
+	ExtractionFailed   Kind = "extraction_failed"
+)
+
+// Event - событие интервью для интеграций. Поля, не относящиеся к виду события, пустые.
+type Event struct {
+	Kind        Kind
+	Time        time.Time
+	InterviewID string
+	TemplateID  string
+	Tenant      string // пусто - интервью без арендатора
+	UserID      int64
+	Partial     bool // интервью прервано, профиль составляется по пройденным блокам
+
+	// BlockCompleted: номер и название блока
+	Block      int
+	BlockTitle string
+
+	// InterviewCompleted - длительность интервью, ProfileExtracted - длительность составления профиля
+	Duration time.Duration
+
+	// ProfileExtracted: профиль, модель и расход токенов
+	ProfileJSON      string
+	Model            string
+	PromptTokens     int
+	CompletionTokens int
+	CostUSD          float64
+
+	// ExtractionFailed: причина ошибки
+	Err error
+}
+
+// Subscriber получает события шины. Вызывается синхронно в порядке подписки,
+// поэтому сетевые и долгие операции подписчик выполняет в фоне.
+type Subscriber interface {
+	HandleEvent(event Event)
+}
+
+// SubscriberFunc - функция-подписчик
+type SubscriberFunc func(event Event)
+
+// HandleEvent вызывает функцию
+func (f SubscriberFunc) HandleEvent(event Event) {
+	f(event)
+}
+
+type subscription struct {
+	name       string
+	subscriber Subscriber
+}
+
+// Bus рассылает события интервью подписчикам (метрики, вебхуки, журнал, выгрузки)
+type Bus struct {
+	mutex         sync.RWMutex
+	subscriptions []subscription
+	logger        *slog.Logger
+}
+
+// NewBus создает шину без подписчиков
+func NewBus() *Bus {
+	return &Bus{logger: slog.Default()}
+}
+
+// SetLogger задает логгер ошибок подписчиков
+func (b *Bus) SetLogger(logger *slog.Logger) {
+	b.logger = logger
+}
+
+// Subscribe подписывает subscriber на все события; name используется в логах
+func (b *Bus) Subscribe(name string, subscriber Subscriber) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.subscriptions = append(b.subscriptions, subscription{name: name, subscriber: subscriber})
+}
+
+// Publish передает событие всем подписчикам. Паника подписчика не мешает остальным.
+// Шина nil ничего не делает.
+func (b *Bus) Publish(event Event) {
+	if b == nil {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	b.mutex.RLock()
+	subscriptions := b.subscriptions
+	b.mutex.RUnlock()
+
+	for _, s := range subscriptions {
+		b.deliver(s, event)
+	}
+}
+
+func (b *Bus) deliver(s subscription, event Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			b.logger.Error("Паника подписчика событий", "subscriber", s.name, "event", string(event.Kind), "error", fmt.Sprint(r))
+		}
+	}()
+	s.subscriber.HandleEvent(event)
+}
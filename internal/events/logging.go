@@ -0,0 +1,27 @@
+package events
+
+import (
+	"interview-bot-complete/internal/logging"
+	"log/slog"
+)
+
+// LogSubscriber записывает события интервью в журнал
+func LogSubscriber(logger *slog.Logger) Subscriber {
+	return SubscriberFunc(func(event Event) {
+		attrs := []interface{}{"event", string(event.Kind), logging.KeyInterviewID, event.InterviewID, logging.KeyUserID, event.UserID}
+		if event.Tenant != "" {
+			attrs = append(attrs, "tenant", event.Tenant)
+		}
+		switch event.Kind {
+		case BlockCompleted:
+			attrs = append(attrs, logging.KeyBlock, event.Block)
+		case InterviewCompleted, ProfileExtracted:
+			attrs = append(attrs, "duration_seconds", int(event.Duration.Seconds()))
+		case ExtractionFailed:
+			if event.Err != nil {
+				attrs = append(attrs, "error", event.Err.Error())
+			}
+		}
+		logger.Info("Событие интервью", attrs...)
+	})
+}
@@ -0,0 +1,28 @@
+package extractor
+
+import (
+	"interview-bot-complete/internal/formatters"
+	"interview-bot-complete/internal/logging"
+	"interview-bot-complete/internal/storage"
+)
+
+// SetExportFormats включает сохранение профилей в форматах для ATS (formatters.Format*)
+func (s *Service) SetExportFormats(formats []string) {
+	s.exportFormats = formats
+}
+
+// exportProfile сохраняет профиль в форматах для ATS; ошибка выгрузки не отменяет сохранение профиля
+func (s *Service) exportProfile(interviewID string, profileResult *ProfileResult) {
+	meta := formatters.Meta{InterviewID: interviewID, Timestamp: profileResult.InterviewTimestamp, ExternalID: profileResult.ExternalID}
+	for _, format := range s.exportFormats {
+		data, err := formatters.Render(format, profileResult.ProfileJSON, meta)
+		if err != nil {
+			s.logger.Warn("Ошибка преобразования профиля для ATS", logging.KeyInterviewID, interviewID, "format", format, "error", err)
+			continue
+		}
+		path := storage.ATSExportPath(format, interviewID, profileResult.Tenant, formatters.Extension(format))
+		if err := storage.WriteFileAtomic(path, data); err != nil {
+			s.logger.Warn("Ошибка сохранения профиля для ATS", logging.KeyInterviewID, interviewID, "format", format, "error", err)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package extractor
+
+import (
+	"context"
+	"fmt"
+	"interview-bot-complete/internal/api"
+	"interview-bot-complete/internal/prompts"
+	"interview-bot-complete/internal/storage"
+	"time"
+)
+
+// SubmitBatchReextraction отправляет промпты извлечения профилей интервью interviewIDs одним пакетом
+// OpenAI Batch API и сохраняет задание для IngestBatch. Интервью, которые не помещаются в контекст
+// модели и извлекаются по частям, в пакет не входят и возвращаются для обычного извлечения.
+// Расширенный анализ в пакетном режиме не выполняется. Как и ReextractProfile, повторное извлечение
+// идет мимо кэша ответов - и в пакете, и в запросах IngestBatch после него.
+func (s *Service) SubmitBatchReextraction(ctx context.Context, interviewIDs []string, opts ExtractOptions) (*storage.BatchJob, []string, error) {
+	opts.NoCache = true
+	promptVersion := opts.PromptVersion
+	if promptVersion == "" {
+		promptVersion = prompts.DefaultPromptVersion
+	}
+	completionOpts := api.CompletionOptions{
+		Model:       opts.Model,
+		Temperature: opts.Temperature,
+		MaxTokens:   opts.MaxTokens,
+		NoCache:     opts.NoCache,
+	}
+
+	var requests []api.BatchRequest
+	var batched, synchronous []string
+	for _, interviewID := range interviewIDs {
+		interviewResult, err := storage.LoadResult(interviewID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("ошибка загрузки интервью %s: %w", interviewID, err)
+		}
+		logger := s.loggerFor(opts, interviewID)
+		prompt, lang, err := s.prepareExtractionPrompt(interviewResult, promptVersion, logger)
+		if err != nil {
+			return nil, nil, fmt.Errorf("интервью %s: %w", interviewID, err)
+		}
+		if chunks := s.splitForContext(interviewResult, promptVersion, lang, prompt, opts, logger); len(chunks) > 1 {
+			synchronous = append(synchronous, interviewID)
+			continue
+		}
+		requests = append(requests, api.BatchRequest{CustomID: interviewID, Prompt: prompt, Options: completionOpts})
+		batched = append(batched, interviewID)
+	}
+	if len(requests) == 0 {
+		return nil, synchronous, nil
+	}
+
+	batch, err := s.apiClient.SubmitBatch(ctx, requests, map[string]string{
+		"purpose":        "reextraction",
+		"prompt_version": promptVersion,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("ошибка отправки пакета: %w", err)
+	}
+	job := &storage.BatchJob{
+		BatchID:       batch.ID,
+		Model:         opts.Model,
+		PromptVersion: promptVersion,
+		SubmittedAt:   time.Now().Format(time.RFC3339),
+		InterviewIDs:  batched,
+	}
+	if err := storage.SaveBatchJob(job); err != nil {
+		return nil, nil, fmt.Errorf("пакет %s отправлен, но задание не сохранено: %w", batch.ID, err)
+	}
+	s.logger.Info("Пакет повторного извлечения отправлен", "batch_id", batch.ID, "interviews", len(batched), "synchronous", len(synchronous))
+	return job, synchronous, nil
+}
+
+// WaitBatch ждет завершения пакета, опрашивая его каждые interval
+func (s *Service) WaitBatch(ctx context.Context, batchID string, interval time.Duration, onPoll func(*api.Batch)) (*api.Batch, error) {
+	return s.apiClient.WaitBatch(ctx, batchID, interval, onPoll)
+}
+
+// IngestBatch сохраняет профили из ответов завершенного пакета задания job новыми ревизиями.
+// Принятые интервью отмечаются в задании, поэтому повторный вызов продолжает с места остановки.
+// onResult получает итог каждого интервью: ревизию или ошибку запроса либо обработки ответа.
+func (s *Service) IngestBatch(ctx context.Context, job *storage.BatchJob, opts ExtractOptions, onResult func(interviewID string, reextraction *Reextraction, err error)) error {
+	batch, err := s.apiClient.GetBatch(ctx, job.BatchID)
+	if err != nil {
+		return fmt.Errorf("ошибка получения пакета %s: %w", job.BatchID, err)
+	}
+	if !batch.Done() {
+		return fmt.Errorf("пакет %s еще выполняется (статус %s)", job.BatchID, batch.Status)
+	}
+	results, err := s.apiClient.BatchResults(ctx, batch)
+	if err != nil {
+		return fmt.Errorf("ошибка загрузки результатов пакета %s: %w", job.BatchID, err)
+	}
+
+	opts.Model = job.Model
+	opts.PromptVersion = job.PromptVersion
+	opts.BatchID = job.BatchID
+	opts.Extended = false
+	opts.NoCache = true
+
+	ingested := make(map[string]bool, len(job.Ingested))
+	for _, interviewID := range job.Ingested {
+		ingested[interviewID] = true
+	}
+	for _, interviewID := range job.InterviewIDs {
+		if ingested[interviewID] {
+			continue
+		}
+		result, ok := results[interviewID]
+		switch {
+		case !ok:
+			err = fmt.Errorf("нет ответа в пакете (статус пакета %s)", batch.Status)
+		case result.Err != nil:
+			err = result.Err
+		default:
+			var reextraction *Reextraction
+			reextraction, err = s.ingestCompletion(interviewID, result.Completion, opts)
+			if err == nil {
+				job.Ingested = append(job.Ingested, interviewID)
+				delete(job.Failed, interviewID)
+				if err := storage.SaveBatchJob(job); err != nil {
+					return fmt.Errorf("ошибка сохранения пакетного задания: %w", err)
+				}
+				onResult(interviewID, reextraction, nil)
+				continue
+			}
+		}
+
+		if job.Failed == nil {
+			job.Failed = make(map[string]string)
+		}
+		job.Failed[interviewID] = err.Error()
+		onResult(interviewID, nil, err)
+	}
+	return storage.SaveBatchJob(job)
+}
+
+// ingestCompletion строит профиль интервью из ответа модели, полученного в пакете,
+// и сохраняет его новой ревизией
+func (s *Service) ingestCompletion(interviewID string, completion *api.Completion, opts ExtractOptions) (*Reextraction, error) {
+	interviewResult, err := storage.LoadResult(interviewID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка загрузки интервью: %w", err)
+	}
+	logger := s.loggerFor(opts, interviewID)
+	_, lang, err := s.prepareExtractionPrompt(interviewResult, opts.PromptVersion, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := &rawProfile{completion: completion, promptVersion: opts.PromptVersion, lang: lang, chunks: 1}
+	raw.profileJSON, raw.repairMethod, err = s.decodeJSON(completion.Content, &raw.profile, logger)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка парсинга JSON: %w", err)
+	}
+
+	reextraction := s.newReextraction(interviewID)
+	profileResult, err := s.finishProfile(interviewResult, opts, raw, logger)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.saveReextraction(interviewID, reextraction, profileResult); err != nil {
+		return nil, err
+	}
+	reextraction.Cost = api.EstimateBatchCost(profileResult.Model, profileResult.Usage)
+	return reextraction, nil
+}
@@ -0,0 +1,69 @@
+package extractor
+
+import (
+	"container/list"
+	"sync"
+)
+
+// defaultProfileCacheSize - количество профилей, хранимых в памяти
+const defaultProfileCacheSize = 100
+
+// profileCache - потокобезопасный LRU-кэш JSON профилей по ID интервью
+type profileCache struct {
+	mutex    sync.Mutex
+	capacity int
+	order    *list.List
+	items    map[string]*list.Element
+}
+
+type profileCacheEntry struct {
+	interviewID string
+	profileJSON string
+}
+
+func newProfileCache(capacity int) *profileCache {
+	if capacity <= 0 {
+		capacity = defaultProfileCacheSize
+	}
+	return &profileCache{
+		capacity: capacity,
+		order:    list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+// Get возвращает профиль и помечает его как недавно использованный
+func (c *profileCache) Get(interviewID string) (string, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	element, ok := c.items[interviewID]
+	if !ok {
+		return "", false
+	}
+	c.order.MoveToFront(element)
+	return element.Value.(*profileCacheEntry).profileJSON, true
+}
+
+// Put сохраняет профиль, вытесняя самый давно использованный при переполнении
+func (c *profileCache) Put(interviewID, profileJSON string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if element, ok := c.items[interviewID]; ok {
+		element.Value.(*profileCacheEntry).profileJSON = profileJSON
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.items[interviewID] = c.order.PushFront(&profileCacheEntry{
+		interviewID: interviewID,
+		profileJSON: profileJSON,
+	})
+
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*profileCacheEntry).interviewID)
+	}
+}
@@ -0,0 +1,311 @@
+package extractor
+
+import (
+	"encoding/json"
+	"fmt"
+	"interview-bot-complete/internal/api"
+	"interview-bot-complete/internal/jsonrepair"
+	"interview-bot-complete/internal/prompts"
+	"interview-bot-complete/internal/storage"
+	"log/slog"
+	"strings"
+)
+
+// contextReserve - доля контекста про запас: EstimateTokens оценивает токены грубо
+const contextReserve = 0.1
+
+// interviewChunk - часть длинного интервью из целых блоков и ее промпт извлечения
+type interviewChunk struct {
+	blockIDs []int
+	prompt   string
+}
+
+// chunkedProfile - профиль, собранный из частичных профилей частей интервью
+type chunkedProfile struct {
+	completion   *api.Completion // суммарный расход токенов всех запросов
+	profile      map[string]interface{}
+	profileJSON  string
+	repairMethod string
+}
+
+// mergeConflicts - поля, по которым частичные профили разошлись
+type mergeConflicts struct {
+	values map[string][]interface{} // путь "a.b" - разные значения в порядке частей
+	keys   map[string][]string      // путь - ключи вложенных объектов
+}
+
+// promptBudget возвращает число токенов, доступное промпту извлечения в контексте модели
+func (s *Service) promptBudget(opts ExtractOptions) int {
+	window := s.contextTokens
+	if window <= 0 {
+		model := opts.Model
+		if model == "" {
+			model = s.apiClient.Model()
+		}
+		window = api.ContextWindow(model)
+	}
+	maxTokens := opts.MaxTokens
+	if maxTokens <= 0 {
+		maxTokens = s.apiClient.MaxTokens()
+	}
+	return int(float64(window)*(1-contextReserve)) - maxTokens
+}
+
+// splitForContext делит интервью на части из идущих подряд блоков, если промпт извлечения
+// не помещается в контекст модели; nil - интервью извлекается одним запросом.
+// Блоки не дробятся: метки ответов для ссылок на источники нумеруются внутри блока.
+func (s *Service) splitForContext(result *storage.InterviewResult, promptVersion, lang, prompt string, opts ExtractOptions, logger *slog.Logger) []interviewChunk {
+	budget := s.promptBudget(opts)
+	estimated := api.EstimateTokens(prompt)
+	if estimated <= budget || len(result.Blocks) < 2 {
+		return nil
+	}
+
+	var chunks []interviewChunk
+	var current []storage.BlockResult
+	var currentPrompt string
+	for _, block := range result.Blocks {
+		candidate := append(current[:len(current):len(current)], block)
+		candidatePrompt, err := s.buildExtractionPrompt(chunkResult(result, candidate), promptVersion, lang, logger)
+		if err != nil {
+			logger.Warn("Не удалось построить промпт части интервью, извлекаю целиком", "error", err)
+			return nil
+		}
+		if len(current) > 0 && api.EstimateTokens(candidatePrompt) > budget {
+			chunks = append(chunks, newInterviewChunk(current, currentPrompt))
+			candidate = []storage.BlockResult{block}
+			if candidatePrompt, err = s.buildExtractionPrompt(chunkResult(result, candidate), promptVersion, lang, logger); err != nil {
+				logger.Warn("Не удалось построить промпт части интервью, извлекаю целиком", "error", err)
+				return nil
+			}
+		}
+		if len(candidate) == 1 && api.EstimateTokens(candidatePrompt) > budget {
+			logger.Warn("Блок не помещается в контекст модели даже отдельно", "block_id", block.BlockID, "estimated_tokens", api.EstimateTokens(candidatePrompt), "budget", budget)
+		}
+		current, currentPrompt = candidate, candidatePrompt
+	}
+	chunks = append(chunks, newInterviewChunk(current, currentPrompt))
+
+	logger.Info("Интервью не помещается в контекст модели, извлекаю по частям", "estimated_tokens", estimated, "budget", budget, "chunks", len(chunks))
+	return chunks
+}
+
+// chunkResult возвращает копию интервью только с блоками части
+func chunkResult(result *storage.InterviewResult, blocks []storage.BlockResult) *storage.InterviewResult {
+	chunk := *result
+	chunk.Blocks = blocks
+	return &chunk
+}
+
+func newInterviewChunk(blocks []storage.BlockResult, prompt string) interviewChunk {
+	chunk := interviewChunk{prompt: prompt}
+	for _, block := range blocks {
+		chunk.blockIDs = append(chunk.blockIDs, block.BlockID)
+	}
+	return chunk
+}
+
+// extractChunked извлекает частичный профиль из каждой части интервью (map) и объединяет их (reduce):
+// сначала по детерминированным правилам mergeProfiles, затем разошедшиеся скалярные поля
+// разрешает модель промптом объединения
+func (s *Service) extractChunked(chunks []interviewChunk, opts ExtractOptions, lang string, logger *slog.Logger) (*chunkedProfile, error) {
+	completionOpts := api.CompletionOptions{
+		Model:       opts.Model,
+		Temperature: opts.Temperature,
+		MaxTokens:   opts.MaxTokens,
+		NoCache:     opts.NoCache,
+	}
+	result := &chunkedProfile{repairMethod: jsonrepair.MethodStrict}
+
+	partials := make([]map[string]interface{}, 0, len(chunks))
+	for n, chunk := range chunks {
+		opts.stage(StageExtracting, 15+55*n/len(chunks))
+		completion, err := s.apiClient.ExtractProfileWithOptions(chunk.prompt, completionOpts)
+		if err != nil {
+			return nil, fmt.Errorf("часть %d из %d: %w", n+1, len(chunks), err)
+		}
+		result.addCompletion(completion)
+
+		var partial map[string]interface{}
+		_, repairMethod, err := s.decodeJSON(completion.Content, &partial, logger)
+		if err != nil {
+			return nil, fmt.Errorf("часть %d из %d: ошибка парсинга JSON: %w", n+1, len(chunks), err)
+		}
+		if repairMethod != jsonrepair.MethodStrict {
+			result.repairMethod = repairMethod
+		}
+		logger.Debug("Часть интервью извлечена", "chunk", n+1, "blocks", chunk.blockIDs, "fields", len(partial))
+		partials = append(partials, partial)
+	}
+
+	profile, conflicts := mergeProfiles(partials)
+	if len(conflicts.values) > 0 {
+		s.reduceConflicts(profile, conflicts, completionOpts, lang, result, logger)
+	}
+
+	profileJSON, err := json.Marshal(profile)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка сериализации объединенного профиля: %w", err)
+	}
+	result.profile = profile
+	result.profileJSON = string(profileJSON)
+	return result, nil
+}
+
+// addCompletion учитывает ответ очередного запроса; модель профиля - модель первой части
+func (p *chunkedProfile) addCompletion(completion *api.Completion) {
+	if p.completion == nil {
+		p.completion = &api.Completion{
+			Model:          completion.Model,
+			RequestedModel: completion.RequestedModel,
+			Usage:          completion.Usage,
+			Cached:         completion.Cached,
+		}
+		return
+	}
+	p.completion.Usage = addUsage(p.completion.Usage, completion.Usage)
+	p.completion.Cached = p.completion.Cached && completion.Cached
+}
+
+// reduceConflicts просит модель выбрать итоговые значения разошедшихся полей. Поля, которые
+// модель не вернула, и любая ошибка оставляют значение последней части.
+func (s *Service) reduceConflicts(profile map[string]interface{}, conflicts *mergeConflicts, opts api.CompletionOptions, lang string, result *chunkedProfile, logger *slog.Logger) {
+	prompt, err := prompts.GenerateProfileReducePrompt(conflicts.values, lang)
+	var resolved map[string]interface{}
+	if err == nil {
+		var completion *api.Completion
+		if completion, err = s.apiClient.ExtractProfileWithOptions(prompt, opts); err == nil {
+			result.addCompletion(completion)
+			_, _, err = s.decodeJSON(completion.Content, &resolved, logger)
+		}
+	}
+	if err != nil {
+		logger.Warn("Не удалось объединить частичные профили моделью, оставлены значения последних частей", "fields", len(conflicts.values), "error", err)
+		return
+	}
+
+	applied := 0
+	for path, keys := range conflicts.keys {
+		if value, ok := resolved[path]; ok && !isEmptyValue(value) {
+			setPath(profile, keys, value)
+			applied++
+		}
+	}
+	logger.Info("Расхождения частичных профилей разрешены", "fields", len(conflicts.values), "resolved_by_model", applied)
+}
+
+// mergeProfiles объединяет частичные профили по порядку частей: объекты - по полям,
+// массивы - без повторов, пустые значения не затирают заполненные, совпадающие скаляры
+// сохраняются. Разные скаляры возвращаются в конфликтах; в профиле остается значение последней части.
+func mergeProfiles(partials []map[string]interface{}) (map[string]interface{}, *mergeConflicts) {
+	profile := make(map[string]interface{})
+	conflicts := &mergeConflicts{
+		values: make(map[string][]interface{}),
+		keys:   make(map[string][]string),
+	}
+	for _, partial := range partials {
+		mergeObject(profile, partial, nil, conflicts)
+	}
+	return profile, conflicts
+}
+
+func mergeObject(dst, src map[string]interface{}, keys []string, conflicts *mergeConflicts) {
+	for key, value := range src {
+		dst[key] = mergeValue(dst[key], value, append(keys[:len(keys):len(keys)], key), conflicts)
+	}
+}
+
+func mergeValue(current, value interface{}, keys []string, conflicts *mergeConflicts) interface{} {
+	if isEmptyValue(value) {
+		if current == nil {
+			return value
+		}
+		return current
+	}
+	if isEmptyValue(current) {
+		if list, ok := value.([]interface{}); ok {
+			return appendUnique(nil, list...)
+		}
+		return value
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if c, ok := current.(map[string]interface{}); ok {
+			mergeObject(c, v, keys, conflicts)
+			return c
+		}
+	case []interface{}:
+		if c, ok := current.([]interface{}); ok {
+			return appendUnique(c, v...)
+		}
+		return appendUnique([]interface{}{current}, v...)
+	}
+	if c, ok := current.([]interface{}); ok {
+		return appendUnique(c, value)
+	}
+	if valueKey(current) == valueKey(value) {
+		return current
+	}
+
+	path := strings.Join(keys, ".")
+	if _, ok := conflicts.values[path]; !ok {
+		conflicts.values[path] = []interface{}{current}
+		conflicts.keys[path] = keys
+	}
+	conflicts.values[path] = appendUnique(conflicts.values[path], value)
+	return value
+}
+
+// appendUnique добавляет в список значения, которых в нем еще нет
+func appendUnique(list []interface{}, values ...interface{}) []interface{} {
+	seen := make(map[string]bool, len(list))
+	for _, item := range list {
+		seen[valueKey(item)] = true
+	}
+	for _, value := range values {
+		if key := valueKey(value); !seen[key] {
+			seen[key] = true
+			list = append(list, value)
+		}
+	}
+	return list
+}
+
+// valueKey - ключ сравнения значений: строки без учета регистра и пробелов по краям,
+// остальное - по JSON (ключи объектов в нем отсортированы)
+func valueKey(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return "s:" + strings.ToLower(strings.TrimSpace(s))
+	}
+	data, _ := json.Marshal(value)
+	return string(data)
+}
+
+// isEmptyValue сообщает, что модель не нашла значения поля в части интервью
+func isEmptyValue(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(v) == ""
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+	return false
+}
+
+// setPath записывает значение во вложенное поле профиля
+func setPath(profile map[string]interface{}, keys []string, value interface{}) {
+	for _, key := range keys[:len(keys)-1] {
+		next, ok := profile[key].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			profile[key] = next
+		}
+		profile = next
+	}
+	profile[keys[len(keys)-1]] = value
+}
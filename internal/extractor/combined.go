@@ -0,0 +1,200 @@
+package extractor
+
+import (
+	"encoding/json"
+	"fmt"
+	"interview-bot-complete/internal/storage"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Правила объединения поля профиля из нескольких интервью
+const (
+	// MergeLatest - значение последнего интервью, в котором поле заполнено
+	MergeLatest = "latest"
+	// MergeUnion - списки всех интервью объединяются, элементы из новых интервью первыми
+	MergeUnion = "union"
+)
+
+// fieldMergeRules - поля, накапливающиеся со временем; остальные поля объединяются по MergeLatest
+var fieldMergeRules = map[string]string{
+	"hard_skills":            MergeUnion,
+	"programming_languages":  MergeUnion,
+	"tools_and_technologies": MergeUnion,
+	"certifications":         MergeUnion,
+	"languages_spoken":       MergeUnion,
+	"achievements":           MergeUnion,
+	"previous_companies":     MergeUnion,
+}
+
+// FieldRevision - значение поля профиля, впервые появившееся в интервью InterviewID
+type FieldRevision struct {
+	InterviewID string      `json:"interview_id"`
+	Date        string      `json:"date"`
+	Value       interface{} `json:"value"`
+}
+
+// mergeSource - профиль одного интервью пользователя
+type mergeSource struct {
+	interview storage.UserInterview
+	profile   map[string]interface{}
+}
+
+// MergeProfiles объединяет профили интервью пользователя userID в сводный профиль (JSON).
+// Интервью без профиля пропускаются. Поле берется по правилу fieldMergeRules; значение прерванного
+// интервью не заменяет значение завершенного, а только заполняет пустое поле. В _history
+// перечислены значения изменявшихся полей по порядку появления, в _metadata - интервью-источники
+// и интервью, из которого взято каждое поле.
+func (s *Service) MergeProfiles(userID int64, interviews []storage.UserInterview) ([]byte, error) {
+	var sources []mergeSource
+	for _, interview := range interviews {
+		if !interview.HasProfile {
+			continue
+		}
+		profileJSON, err := s.GetLastProfileJSON(interview.InterviewID)
+		if err != nil {
+			s.logger.Warn("Профиль пропущен при объединении", "interview_id", interview.InterviewID, "error", err)
+			continue
+		}
+		var profile map[string]interface{}
+		if err := json.Unmarshal([]byte(profileJSON), &profile); err != nil {
+			s.logger.Warn("Профиль пропущен при объединении", "interview_id", interview.InterviewID, "error", err)
+			continue
+		}
+		sources = append(sources, mergeSource{interview: interview, profile: profile})
+	}
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("у пользователя %d нет профилей для объединения", userID)
+	}
+	// Старые интервью первыми: значения новых перекрывают их
+	sort.SliceStable(sources, func(i, j int) bool {
+		return sources[i].interview.Timestamp < sources[j].interview.Timestamp
+	})
+
+	fields := make(map[string]bool)
+	for _, source := range sources {
+		for key := range source.profile {
+			if !strings.HasPrefix(key, "_") {
+				fields[key] = true
+			}
+		}
+	}
+
+	combined := make(map[string]interface{})
+	history := make(map[string][]FieldRevision)
+	fieldSources := make(map[string]string)
+	for field := range fields {
+		value, from := mergeField(field, sources)
+		if value == nil {
+			continue
+		}
+		combined[field] = value
+		fieldSources[field] = from
+		if revisions := fieldHistory(field, sources); len(revisions) > 1 {
+			history[field] = revisions
+		}
+	}
+
+	interviewIDs := make([]string, len(sources))
+	for i, source := range sources {
+		interviewIDs[i] = source.interview.InterviewID
+	}
+	combined["_history"] = history
+	combined["_metadata"] = map[string]interface{}{
+		"type":          "combined",
+		"user_id":       userID,
+		"interviews":    interviewIDs,
+		"field_sources": fieldSources,
+		"creation_date": time.Now().Format("2006-01-02 15:04:05"),
+	}
+
+	data, err := json.MarshalIndent(combined, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("ошибка сериализации сводного профиля: %w", err)
+	}
+	return data, nil
+}
+
+// mergeField возвращает значение поля по его правилу объединения и интервью, из которого оно взято
+// (для MergeUnion - последнее интервью, добавившее элементы); nil - поле не заполнено ни в одном интервью
+func mergeField(field string, sources []mergeSource) (interface{}, string) {
+	if fieldMergeRules[field] == MergeUnion {
+		if value, from := unionField(field, sources); value != nil {
+			return value, from
+		}
+	}
+
+	var value interface{}
+	var from string
+	var fromComplete bool
+	for _, source := range sources {
+		candidate := source.profile[field]
+		if formatValue(candidate) == "" {
+			continue
+		}
+		complete := !source.interview.Partial
+		if fromComplete && !complete {
+			continue
+		}
+		value, from, fromComplete = candidate, source.interview.InterviewID, complete
+	}
+	return value, from
+}
+
+// unionField объединяет списки поля из всех интервью без повторов (без учета регистра);
+// nil - поле ни в одном интервью не список строк
+func unionField(field string, sources []mergeSource) (interface{}, string) {
+	seen := make(map[string]bool)
+	var items []interface{}
+	var from string
+	for i := len(sources) - 1; i >= 0; i-- {
+		list, ok := sources[i].profile[field].([]interface{})
+		if !ok || hasObjects(list) {
+			continue
+		}
+		for _, item := range list {
+			key := strings.ToLower(formatValue(item))
+			if key == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			items = append(items, item)
+			if from == "" {
+				from = sources[i].interview.InterviewID
+			}
+		}
+	}
+	if len(items) == 0 {
+		return nil, ""
+	}
+	return items, from
+}
+
+// fieldHistory возвращает значения поля по порядку интервью, пропуская повторы предыдущего значения
+func fieldHistory(field string, sources []mergeSource) []FieldRevision {
+	var revisions []FieldRevision
+	var last string
+	for _, source := range sources {
+		value := source.profile[field]
+		formatted := formatValue(value)
+		if formatted == "" || formatted == last {
+			continue
+		}
+		last = formatted
+		revisions = append(revisions, FieldRevision{
+			InterviewID: source.interview.InterviewID,
+			Date:        historyDate(source.interview),
+			Value:       value,
+		})
+	}
+	return revisions
+}
+
+// historyDate возвращает дату интервью: завершения, а для прерванных - начала
+func historyDate(interview storage.UserInterview) string {
+	if interview.CompletedAt != "" {
+		return interview.CompletedAt
+	}
+	return interview.Timestamp
+}
@@ -0,0 +1,172 @@
+package extractor
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Виды изменения поля между ревизиями профиля
+const (
+	FieldAdded   = "added"
+	FieldRemoved = "removed"
+	FieldChanged = "changed"
+)
+
+// FieldChange - изменение одного поля профиля. Path - путь через точку (education.university,
+// work_experience[0].position); списки простых значений сравниваются целиком.
+type FieldChange struct {
+	Path   string `json:"path"`
+	Kind   string `json:"kind"`
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+}
+
+// ProfileDiff - различия между двумя ревизиями профиля (служебные поля _metadata и т.п. не сравниваются)
+type ProfileDiff struct {
+	InterviewID string        `json:"interview_id"`
+	From        int           `json:"from"`
+	To          int           `json:"to"`
+	FromModel   string        `json:"from_model,omitempty"`
+	ToModel     string        `json:"to_model,omitempty"`
+	Changes     []FieldChange `json:"changes"`
+}
+
+// DiffProfileRevisions сравнивает ревизии from и to профиля интервью.
+// to=0 - последняя ревизия, from=0 - ревизия перед to.
+func (s *Service) DiffProfileRevisions(interviewID string, from, to int) (*ProfileDiff, error) {
+	latest := s.latestRevision(interviewID)
+	if latest == 0 {
+		return nil, fmt.Errorf("профиль %s не найден", interviewID)
+	}
+	if to == 0 {
+		to = latest
+	}
+	if from == 0 {
+		from = to - 1
+	}
+	if from < 1 || to > latest || from >= to {
+		return nil, fmt.Errorf("нужны ревизии 1 ≤ from < to ≤ %d (сохранено ревизий: %d)", latest, latest)
+	}
+
+	before, fromModel, err := s.loadRevisionFields(interviewID, from)
+	if err != nil {
+		return nil, err
+	}
+	after, toModel, err := s.loadRevisionFields(interviewID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ProfileDiff{
+		InterviewID: interviewID,
+		From:        from,
+		To:          to,
+		FromModel:   fromModel,
+		ToModel:     toModel,
+		Changes:     diffFields(before, after),
+	}, nil
+}
+
+// loadRevisionFields читает ревизию профиля и раскладывает ее в плоский набор путь → значение
+func (s *Service) loadRevisionFields(interviewID string, revision int) (map[string]string, string, error) {
+	profileJSON, err := s.LoadProfileRevision(interviewID, revision)
+	if err != nil {
+		return nil, "", err
+	}
+	var profile map[string]interface{}
+	if err := json.Unmarshal([]byte(profileJSON), &profile); err != nil {
+		return nil, "", fmt.Errorf("ошибка парсинга ревизии v%d: %w", revision, err)
+	}
+	model, _, _ := profileUsage(profileJSON)
+
+	fields := make(map[string]string)
+	for key, value := range profile {
+		// Служебные поля (_metadata, _provenance) меняются при каждом извлечении
+		if strings.HasPrefix(key, "_") {
+			continue
+		}
+		flattenField(key, value, fields)
+	}
+	return fields, model, nil
+}
+
+// flattenField добавляет в fields листовые значения value по пути path
+func flattenField(path string, value interface{}, fields map[string]string) {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for key, nested := range typed {
+			flattenField(path+"."+key, nested, fields)
+		}
+	case []interface{}:
+		if !hasObjects(typed) {
+			fields[path] = formatValue(typed)
+			return
+		}
+		for i, nested := range typed {
+			flattenField(fmt.Sprintf("%s[%d]", path, i), nested, fields)
+		}
+	default:
+		fields[path] = formatValue(typed)
+	}
+}
+
+func hasObjects(items []interface{}) bool {
+	for _, item := range items {
+		switch item.(type) {
+		case map[string]interface{}, []interface{}:
+			return true
+		}
+	}
+	return false
+}
+
+// formatValue возвращает значение в виде для сравнения и показа; пустые значения - ""
+func formatValue(value interface{}) string {
+	switch typed := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(typed)
+	case []interface{}:
+		items := make([]string, 0, len(typed))
+		for _, item := range typed {
+			if formatted := formatValue(item); formatted != "" {
+				items = append(items, formatted)
+			}
+		}
+		return strings.Join(items, ", ")
+	default:
+		data, _ := json.Marshal(typed)
+		return string(data)
+	}
+}
+
+// diffFields сравнивает плоские наборы полей; пустое значение равносильно отсутствию поля
+func diffFields(before, after map[string]string) []FieldChange {
+	paths := make(map[string]bool)
+	for path := range before {
+		paths[path] = true
+	}
+	for path := range after {
+		paths[path] = true
+	}
+
+	var changes []FieldChange
+	for path := range paths {
+		old, now := before[path], after[path]
+		switch {
+		case old == now:
+			continue
+		case old == "":
+			changes = append(changes, FieldChange{Path: path, Kind: FieldAdded, After: now})
+		case now == "":
+			changes = append(changes, FieldChange{Path: path, Kind: FieldRemoved, Before: old})
+		default:
+			changes = append(changes, FieldChange{Path: path, Kind: FieldChanged, Before: old, After: now})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes
+}
@@ -0,0 +1,51 @@
+package extractor
+
+import (
+	"interview-bot-complete/internal/sentiment"
+	"interview-bot-complete/internal/storage"
+)
+
+// emotionalMarkersField - раздел профиля с эмоциональными маркерами интервью
+const emotionalMarkersField = "emotional_markers"
+
+// summaryEmotionalMarkers - раздел саммари блока (summary_structure.emotional_markers)
+const summaryEmotionalMarkers = "emotional_markers"
+
+// EmotionalMarkers - эмоциональные маркеры интервью: общая тональность и разбивка по блокам
+type EmotionalMarkers struct {
+	Overall *storage.Tone          `json:"overall,omitempty"`
+	Blocks  []BlockEmotionalMarker `json:"blocks"`
+}
+
+// BlockEmotionalMarker - тональность блока и эмоциональные реакции из его саммари
+type BlockEmotionalMarker struct {
+	BlockID   int    `json:"block_id"`
+	BlockName string `json:"block_name"`
+	*storage.Tone
+	Notes []string `json:"notes,omitempty"`
+}
+
+// addEmotionalMarkers добавляет в профиль тональность блоков и эмоциональные реакции из саммари
+func addEmotionalMarkers(profile map[string]interface{}, result *storage.InterviewResult) {
+	markers := EmotionalMarkers{}
+	var tones []*storage.BlockSentiment
+	for _, block := range result.Blocks {
+		marker := BlockEmotionalMarker{BlockID: block.BlockID, BlockName: block.BlockName}
+		if block.Sentiment != nil {
+			marker.Tone = &block.Sentiment.Tone
+			tones = append(tones, block.Sentiment)
+		}
+		if block.Summary != nil {
+			marker.Notes = block.Summary.Fields[summaryEmotionalMarkers]
+		}
+		if marker.Tone != nil || len(marker.Notes) > 0 {
+			markers.Blocks = append(markers.Blocks, marker)
+		}
+	}
+	if len(markers.Blocks) == 0 {
+		return
+	}
+
+	markers.Overall = sentiment.Aggregate(tones)
+	profile[emotionalMarkersField] = markers
+}
@@ -0,0 +1,233 @@
+package extractor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"interview-bot-complete/internal/api"
+	"interview-bot-complete/internal/language"
+	"interview-bot-complete/internal/logging"
+	"interview-bot-complete/internal/matcher"
+	"interview-bot-complete/internal/prompts"
+	"interview-bot-complete/internal/storage"
+)
+
+// extendedAnalysisField - раздел профиля с платным расширенным анализом
+const extendedAnalysisField = "extended_analysis"
+
+// ErrNotEntitled - расширенный анализ запрошен для пользователя, который его не оплатил
+var ErrNotEntitled = errors.New("расширенный анализ не оплачен")
+
+// ExtendedAnalysis - расширенный анализ: развернутый отчет и разбор архетипа
+type ExtendedAnalysis struct {
+	// Archetype пуст, если каталоги архетипов не подключены
+	Archetype   Archetype       `json:"archetype"`
+	Report      []ReportSection `json:"report"`
+	CareerPaths []string        `json:"career_paths,omitempty"`
+}
+
+// Archetype - архетип личности из каталога с сильными сторонами, слепыми зонами и рекомендациями
+type Archetype struct {
+	ID          string   `json:"id,omitempty"`
+	Catalog     string   `json:"catalog,omitempty"`
+	CatalogName string   `json:"catalog_name,omitempty"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Strengths   []string `json:"strengths,omitempty"`
+	BlindSpots  []string `json:"blind_spots,omitempty"`
+	Growth      []string `json:"growth,omitempty"`
+}
+
+// ReportSection - раздел развернутого отчета
+type ReportSection struct {
+	Title string `json:"title"`
+	Text  string `json:"text"`
+}
+
+// ExtendedProfile - ревизия профиля с добавленным расширенным анализом
+type ExtendedProfile struct {
+	FileName    string
+	Revision    int
+	ProfileJSON string
+	Analysis    *ExtendedAnalysis
+	Model       string
+	Usage       api.Usage
+}
+
+// checkEntitlement проверяет, что пользователь оплатил расширенный анализ
+func checkEntitlement(userID int64) error {
+	entitled, err := storage.HasEntitlement(userID, storage.FeatureExtendedAnalysis)
+	if err != nil {
+		return fmt.Errorf("ошибка проверки оплаты: %w", err)
+	}
+	if !entitled {
+		return ErrNotEntitled
+	}
+	return nil
+}
+
+// SetArchetypes подключает каталоги архетипов для расширенного анализа
+func (s *Service) SetArchetypes(catalogs *matcher.Catalogs) {
+	s.matcher = matcher.New(s.apiClient, catalogs)
+}
+
+// extendedAnalysis запрашивает у модели расширенный анализ по профилю и ответам интервью
+// и подбирает архетип из каталога opts.ArchetypeCatalog
+func (s *Service) extendedAnalysis(profileJSON string, interviewResult *storage.InterviewResult, opts ExtractOptions) (*ExtendedAnalysis, *api.Completion, error) {
+	extractorInterview := s.convertToExtractorFormat(interviewResult)
+	lang := language.Detect(extractorInterview.ExtractAllAnswers())
+	answers := extractorInterview.ExtractContextualAnswers()
+	completionOpts := api.CompletionOptions{
+		Model:       opts.Model,
+		Temperature: opts.Temperature,
+		MaxTokens:   opts.MaxTokens,
+		NoCache:     opts.NoCache,
+	}
+
+	opts.stage(StageAnalyzing, 80)
+	completion, err := s.apiClient.ExtractProfileWithOptions(prompts.GenerateExtendedAnalysisPrompt(profileJSON, answers, lang), completionOpts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("ошибка расширенного анализа: %w", err)
+	}
+
+	var analysis ExtendedAnalysis
+	if _, _, err := s.decodeJSON(completion.Content, &analysis, s.loggerFor(opts, interviewResult.InterviewID)); err != nil {
+		return nil, nil, fmt.Errorf("ошибка парсинга расширенного анализа: %w", err)
+	}
+	if len(analysis.Report) == 0 {
+		return nil, nil, fmt.Errorf("расширенный анализ не содержит отчета")
+	}
+	if s.matcher == nil {
+		return &analysis, completion, nil
+	}
+
+	opts.stage(StageMatching, 88)
+	match, matchCompletion, err := s.matcher.Match(opts.ArchetypeCatalog, matcher.Input{
+		ProfileJSON: profileJSON,
+		Answers:     answers,
+		Language:    lang,
+	}, matchingOptions(opts))
+	if err != nil {
+		return nil, nil, err
+	}
+	analysis.Archetype = Archetype{
+		ID:          match.ArchetypeID,
+		Catalog:     match.CatalogID,
+		CatalogName: match.CatalogName,
+		Name:        match.Name,
+		Description: match.Description,
+		Strengths:   match.Strengths,
+		BlindSpots:  match.BlindSpots,
+		Growth:      match.Growth,
+	}
+	completion.Usage = addUsage(completion.Usage, matchCompletion.Usage)
+	return &analysis, completion, nil
+}
+
+// addUsage суммирует расход токенов двух запросов
+func addUsage(a, b api.Usage) api.Usage {
+	return api.Usage{
+		PromptTokens:     a.PromptTokens + b.PromptTokens,
+		CompletionTokens: a.CompletionTokens + b.CompletionTokens,
+		TotalTokens:      a.TotalTokens + b.TotalTokens,
+	}
+}
+
+// attachExtendedAnalysis добавляет анализ в профиль и отмечает его в _metadata
+func attachExtendedAnalysis(profile map[string]interface{}, analysis *ExtendedAnalysis, completion *api.Completion) {
+	profile[extendedAnalysisField] = analysis
+	metadata, _ := profile["_metadata"].(map[string]interface{})
+	if metadata == nil {
+		metadata = make(map[string]interface{})
+		profile["_metadata"] = metadata
+	}
+	metadata["extended_model"] = completion.Model
+	metadata["extended_total_tokens"] = completion.Usage.TotalTokens
+}
+
+// ExtendProfile добавляет расширенный анализ к последней ревизии профиля и сохраняет
+// результат новой ревизией. Доступно только пользователю, оплатившему анализ.
+func (s *Service) ExtendProfile(interviewID string, opts ExtractOptions) (*ExtendedProfile, error) {
+	if err := checkEntitlement(opts.UserID); err != nil {
+		return nil, err
+	}
+
+	interviewResult, err := storage.LoadResult(interviewID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка загрузки интервью: %w", err)
+	}
+	profileJSON, err := s.GetLastProfileJSON(interviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	var profile map[string]interface{}
+	if err := json.Unmarshal([]byte(profileJSON), &profile); err != nil {
+		return nil, fmt.Errorf("ошибка парсинга профиля: %w", err)
+	}
+
+	analysis, completion, err := s.extendedAnalysis(profileJSON, interviewResult, opts)
+	if err != nil {
+		return nil, err
+	}
+	attachExtendedAnalysis(profile, analysis, completion)
+
+	extendedJSON, err := json.MarshalIndent(profile, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("ошибка форматирования профиля: %w", err)
+	}
+
+	fileName, revision, err := s.SaveProfileRevision(interviewID, &ProfileResult{
+		ProfileJSON:        string(extendedJSON),
+		Success:            true,
+		TemplateID:         interviewResult.TemplateID,
+		Tenant:             interviewResult.Tenant,
+		InterviewTimestamp: interviewResult.Timestamp,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Расширенный анализ профиля сохранен", logging.KeyInterviewID, interviewID, "revision", revision)
+	return &ExtendedProfile{
+		FileName:    fileName,
+		Revision:    revision,
+		ProfileJSON: string(extendedJSON),
+		Analysis:    analysis,
+		Model:       completion.Model,
+		Usage:       completion.Usage,
+	}, nil
+}
+
+// LatestExtendedProfile возвращает последнюю ревизию профиля интервью, если она уже содержит
+// расширенный анализ; nil - анализа нет или профиль извлечен заново после него
+func (s *Service) LatestExtendedProfile(interviewID string) *ExtendedProfile {
+	revision := s.latestRevision(interviewID)
+	if revision == 0 {
+		return nil
+	}
+	profileJSON, err := s.LoadProfileRevision(interviewID, revision)
+	if err != nil {
+		return nil
+	}
+	analysis := ParseExtendedAnalysis(profileJSON)
+	if analysis == nil {
+		return nil
+	}
+	fileName, err := storage.FindProfile(interviewID, revision)
+	if err != nil {
+		return nil
+	}
+	return &ExtendedProfile{FileName: fileName, Revision: revision, ProfileJSON: profileJSON, Analysis: analysis}
+}
+
+// ParseExtendedAnalysis читает расширенный анализ из профиля (nil, если его нет)
+func ParseExtendedAnalysis(profileJSON string) *ExtendedAnalysis {
+	var profile struct {
+		Extended *ExtendedAnalysis `json:"extended_analysis"`
+	}
+	if json.Unmarshal([]byte(profileJSON), &profile) != nil {
+		return nil
+	}
+	return profile.Extended
+}
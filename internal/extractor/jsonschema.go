@@ -0,0 +1,63 @@
+package extractor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"interview-bot-complete/internal/api"
+	"interview-bot-complete/internal/apperr"
+	"interview-bot-complete/internal/prompts"
+	"interview-bot-complete/internal/schema"
+	"interview-bot-complete/internal/validator"
+	"log/slog"
+)
+
+// maxSchemaRepairs - столько раз модель исправляет профиль, не прошедший проверку по JSON Schema
+const maxSchemaRepairs = 2
+
+// ProfileJSONSchema возвращает JSON Schema профиля арендатора (общую, если своей схемы у него нет)
+func (s *Service) ProfileJSONSchema(tenantID string) map[string]interface{} {
+	return schema.JSONSchema(s.schemaFor(tenantID))
+}
+
+// conformToSchema проверяет профиль по JSON Schema и при несоответствии просит модель исправить
+// поля с ошибками. Профиль, так и не прошедший проверку, отклоняется.
+// Возвращает исправленный профиль и его JSON.
+func (s *Service) conformToSchema(profile map[string]interface{}, profileJSON, tenantID string, logger *slog.Logger) (map[string]interface{}, string, error) {
+	document := s.ProfileJSONSchema(tenantID)
+	for attempt := 1; ; attempt++ {
+		err := validator.ValidateJSONSchema(profileJSON, document)
+		var schemaErr *validator.SchemaError
+		if err == nil || !errors.As(err, &schemaErr) || attempt > maxSchemaRepairs {
+			// Профиль, не соответствующий схеме, - такой же негодный ответ модели, как невалидный JSON
+			return profile, profileJSON, apperr.Wrap(apperr.ErrInvalidJSON, err)
+		}
+
+		logger.Warn("Профиль не соответствует схеме, запрашиваю исправление", "attempt", attempt, "violations", len(schemaErr.Violations), "error", err)
+		fixed, err := s.repairSchemaWithLLM(profileJSON, schemaErr.Violations, document)
+		if err != nil {
+			return nil, "", err
+		}
+		var repaired map[string]interface{}
+		if profileJSON, _, err = s.decodeJSON(fixed, &repaired, logger); err != nil {
+			return nil, "", fmt.Errorf("модель вернула невалидный JSON при исправлении по схеме: %w", err)
+		}
+		profile = repaired
+	}
+}
+
+// repairSchemaWithLLM просит модель привести поля профиля к типам схемы
+func (s *Service) repairSchemaWithLLM(profileJSON string, violations []string, document map[string]interface{}) (string, error) {
+	schemaJSON, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("ошибка сериализации JSON Schema: %w", err)
+	}
+	temperature := 0.0
+	completion, err := s.apiClient.ExtractProfileWithOptions(prompts.GenerateSchemaRepairPrompt(profileJSON, violations, string(schemaJSON)), api.CompletionOptions{
+		Temperature: &temperature,
+	})
+	if err != nil {
+		return "", fmt.Errorf("ошибка исправления профиля по схеме: %w", err)
+	}
+	return completion.Content, nil
+}
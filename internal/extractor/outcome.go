@@ -0,0 +1,64 @@
+package extractor
+
+import (
+	"fmt"
+	"interview-bot-complete/internal/api"
+	"interview-bot-complete/internal/config"
+	"interview-bot-complete/internal/language"
+	"interview-bot-complete/internal/rubric"
+	"interview-bot-complete/internal/storage"
+	"time"
+)
+
+// SetRubrics подключает рубрики ролей для оценки кандидатов
+func (s *Service) SetRubrics(rubrics *rubric.Rubrics) {
+	s.classifier = rubric.NewClassifier(s.apiClient, rubrics)
+}
+
+// OutcomeEnabled сообщает, подключены ли рубрики ролей и есть ли рубрика rubricID
+// (пустой ID - рубрика по умолчанию)
+func (s *Service) OutcomeEnabled(rubricID string) bool {
+	if s.classifier == nil {
+		return false
+	}
+	_, ok := s.classifier.Rubrics().Get(rubricID)
+	return ok
+}
+
+// ClassifyOutcome оценивает кандидата по рубрике роли opts.Rubric и готовит отчет для рекрутера.
+// Отчет не входит в профиль и кандидату не показывается.
+func (s *Service) ClassifyOutcome(interviewResult *storage.InterviewResult, profileJSON string, opts ExtractOptions) (*rubric.Report, error) {
+	if s.classifier == nil {
+		return nil, fmt.Errorf("рубрики ролей не подключены")
+	}
+
+	extractorInterview := s.convertToExtractorFormat(interviewResult)
+	report, _, err := s.classifier.Classify(opts.Rubric, rubric.Input{
+		ProfileJSON: profileJSON,
+		Answers:     extractorInterview.ExtractContextualAnswers(),
+		Language:    language.Detect(extractorInterview.ExtractAllAnswers()),
+	}, matchingOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+	report.InterviewID = interviewResult.InterviewID
+	report.Tenant = interviewResult.Tenant
+	report.CreatedAt = time.Now().Format(time.RFC3339)
+	return report, nil
+}
+
+// matchingOptions возвращает параметры запросов подбора архетипа и оценки по рубрике:
+// поля opts.Matching переопределяют параметры извлечения
+func matchingOptions(opts ExtractOptions) api.CompletionOptions {
+	settings := config.ModelSettings{
+		Model:       opts.Model,
+		Temperature: opts.Temperature,
+		MaxTokens:   opts.MaxTokens,
+	}.Override(opts.Matching)
+	return api.CompletionOptions{
+		Model:       settings.Model,
+		Temperature: settings.Temperature,
+		MaxTokens:   settings.MaxTokens,
+		NoCache:     opts.NoCache,
+	}
+}
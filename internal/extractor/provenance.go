@@ -0,0 +1,124 @@
+package extractor
+
+import (
+	"encoding/json"
+	"interview-bot-complete/internal/interview"
+	"interview-bot-complete/internal/prompts"
+	"interview-bot-complete/internal/storage"
+	"strings"
+)
+
+// provenanceField - раздел сохраненного профиля с источниками полей
+const provenanceField = "provenance"
+
+// Citation - подтвержденный источник значения поля профиля
+type Citation struct {
+	Ref      string `json:"ref"`
+	BlockID  int    `json:"block_id"`
+	Block    string `json:"block"`
+	Question string `json:"question"`
+	Quote    string `json:"quote"`
+}
+
+// answerSource - ответ интервью, на который может ссылаться цитата
+type answerSource struct {
+	ref        string
+	blockID    int
+	block      string
+	question   string
+	normalized string
+}
+
+// buildProvenance забирает из ответа модели поле _provenance и проверяет каждую цитату:
+// цитата принимается, только если она дословно встречается в ответах интервью.
+// Если метка указывает не на тот ответ, источник исправляется по найденной цитате.
+// Возвращает подтвержденные источники по полям и число отклоненных цитат.
+func buildProvenance(profile map[string]interface{}, result *storage.InterviewResult) (map[string][]Citation, int) {
+	raw, ok := profile[prompts.ProvenanceKey]
+	delete(profile, prompts.ProvenanceKey)
+	if !ok {
+		return nil, 0
+	}
+
+	var claimed map[string][]struct {
+		Ref   string `json:"ref"`
+		Quote string `json:"quote"`
+	}
+	data, _ := json.Marshal(raw)
+	if err := json.Unmarshal(data, &claimed); err != nil {
+		return nil, 0
+	}
+
+	sources := answerSources(result)
+	provenance := make(map[string][]Citation)
+	rejected := 0
+
+	for field, citations := range claimed {
+		if value, exists := profile[field]; !exists || value == nil {
+			rejected += len(citations)
+			continue
+		}
+
+		for _, citation := range citations {
+			source := findQuote(sources, citation.Ref, normalizeQuote(citation.Quote))
+			if source == nil {
+				rejected++
+				continue
+			}
+			provenance[field] = append(provenance[field], Citation{
+				Ref:      source.ref,
+				BlockID:  source.blockID,
+				Block:    source.block,
+				Question: source.question,
+				Quote:    strings.TrimSpace(citation.Quote),
+			})
+		}
+	}
+
+	return provenance, rejected
+}
+
+// answerSources нумерует ответы так же, как ExtractCitableAnswers
+func answerSources(result *storage.InterviewResult) []answerSource {
+	var sources []answerSource
+	for _, block := range result.Blocks {
+		for n, qa := range block.QuestionsAndAnswers {
+			if strings.TrimSpace(qa.Answer) == "" {
+				continue
+			}
+			sources = append(sources, answerSource{
+				ref:        interview.AnswerRef(block.BlockID, n+1),
+				blockID:    block.BlockID,
+				block:      block.BlockName,
+				question:   qa.Question,
+				normalized: normalizeQuote(qa.Answer),
+			})
+		}
+	}
+	return sources
+}
+
+// findQuote ищет цитату сначала в ответе по метке, затем во всех ответах
+func findQuote(sources []answerSource, ref, quote string) *answerSource {
+	if quote == "" {
+		return nil
+	}
+	for i := range sources {
+		if sources[i].ref == ref && strings.Contains(sources[i].normalized, quote) {
+			return &sources[i]
+		}
+	}
+	for i := range sources {
+		if strings.Contains(sources[i].normalized, quote) {
+			return &sources[i]
+		}
+	}
+	return nil
+}
+
+// normalizeQuote приводит текст к виду для нестрогого сравнения: регистр, ё, пробелы, кавычки
+func normalizeQuote(text string) string {
+	text = strings.ToLower(strings.ReplaceAll(text, "ё", "е"))
+	text = strings.Join(strings.Fields(text), " ")
+	return strings.Trim(text, ` "'«»„“”.,;:!?…`)
+}
@@ -0,0 +1,146 @@
+package extractor
+
+import (
+	"encoding/json"
+	"fmt"
+	"interview-bot-complete/internal/api"
+	"interview-bot-complete/internal/storage"
+)
+
+// Reextraction описывает результат повторного извлечения профиля
+type Reextraction struct {
+	FileName string
+	Revision int
+	Result   *ProfileResult
+	Cost     float64
+
+	// Данные предыдущей ревизии; PreviousKnown=false для профилей без метаданных о токенах
+	PreviousKnown bool
+	PreviousModel string
+	PreviousUsage api.Usage
+	PreviousCost  float64
+}
+
+// TokenDelta возвращает изменение расхода токенов относительно предыдущей ревизии
+func (r *Reextraction) TokenDelta() int {
+	return r.Result.Usage.TotalTokens - r.PreviousUsage.TotalTokens
+}
+
+// CostDelta возвращает изменение стоимости относительно предыдущей ревизии в долларах
+func (r *Reextraction) CostDelta() float64 {
+	return r.Cost - r.PreviousCost
+}
+
+// defaultCompletionTokens - ожидаемый размер ответа, если прежний расход неизвестен
+const defaultCompletionTokens = 800
+
+// ExtractionEstimate - предварительная оценка расхода на извлечение профиля
+type ExtractionEstimate struct {
+	Model            string
+	PromptTokens     int
+	CompletionTokens int
+	Cost             float64
+}
+
+// EstimateReextraction оценивает токены и стоимость повторного извлечения без запроса к API
+func (s *Service) EstimateReextraction(interviewID string, opts ExtractOptions) (*ExtractionEstimate, error) {
+	interviewResult, err := storage.LoadResult(interviewID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка загрузки интервью: %w", err)
+	}
+
+	prompt, _, err := s.prepareExtractionPrompt(interviewResult, opts.PromptVersion, s.loggerFor(opts, interviewID))
+	if err != nil {
+		return nil, err
+	}
+
+	estimate := &ExtractionEstimate{
+		Model:            opts.Model,
+		PromptTokens:     api.EstimateTokens(prompt),
+		CompletionTokens: defaultCompletionTokens,
+	}
+	if estimate.Model == "" {
+		estimate.Model = s.apiClient.Model()
+	}
+
+	// Размер ответа берем из предыдущей ревизии, если он известен
+	if previous := s.latestRevision(interviewID); previous > 0 {
+		if profileJSON, err := s.LoadProfileRevision(interviewID, previous); err == nil {
+			if _, usage, ok := profileUsage(profileJSON); ok && usage.CompletionTokens > 0 {
+				estimate.CompletionTokens = usage.CompletionTokens
+			}
+		}
+	}
+
+	estimate.Cost = api.EstimateCost(estimate.Model, api.Usage{
+		PromptTokens:     estimate.PromptTokens,
+		CompletionTokens: estimate.CompletionTokens,
+	})
+	return estimate, nil
+}
+
+// ReextractProfile заново извлекает профиль сохраненного интервью и сохраняет его новой ревизией.
+// Модель вызывается мимо кэша ответов: иначе повторный запрос вернул бы прежний ответ без расхода.
+func (s *Service) ReextractProfile(interviewID string, opts ExtractOptions) (*Reextraction, error) {
+	opts.NoCache = true
+	interviewResult, err := storage.LoadResult(interviewID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка загрузки интервью: %w", err)
+	}
+
+	reextraction := s.newReextraction(interviewID)
+	profileResult, err := s.ExtractProfileWithOptions(interviewResult, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.saveReextraction(interviewID, reextraction, profileResult); err != nil {
+		return nil, err
+	}
+	reextraction.Cost = api.EstimateCost(profileResult.Model, profileResult.Usage)
+	return reextraction, nil
+}
+
+// newReextraction заполняет данные предыдущей ревизии профиля для сравнения расхода
+func (s *Service) newReextraction(interviewID string) *Reextraction {
+	reextraction := &Reextraction{}
+	if previous := s.latestRevision(interviewID); previous > 0 {
+		if profileJSON, err := s.LoadProfileRevision(interviewID, previous); err == nil {
+			reextraction.PreviousModel, reextraction.PreviousUsage, reextraction.PreviousKnown = profileUsage(profileJSON)
+			reextraction.PreviousCost = api.EstimateCost(reextraction.PreviousModel, reextraction.PreviousUsage)
+		}
+	}
+	return reextraction
+}
+
+// saveReextraction сохраняет профиль новой ревизией и отмечает ее в reextraction
+func (s *Service) saveReextraction(interviewID string, reextraction *Reextraction, profileResult *ProfileResult) error {
+	fileName, revision, err := s.SaveProfileRevision(interviewID, profileResult)
+	if err != nil {
+		return err
+	}
+	reextraction.FileName = fileName
+	reextraction.Revision = revision
+	reextraction.Result = profileResult
+	return nil
+}
+
+// profileUsage читает модель и расход токенов из _metadata сохраненного профиля
+func profileUsage(profileJSON string) (string, api.Usage, bool) {
+	var profile struct {
+		Metadata struct {
+			Model            string `json:"model"`
+			PromptTokens     int    `json:"prompt_tokens"`
+			CompletionTokens int    `json:"completion_tokens"`
+			TotalTokens      *int   `json:"total_tokens"`
+		} `json:"_metadata"`
+	}
+	if err := json.Unmarshal([]byte(profileJSON), &profile); err != nil || profile.Metadata.TotalTokens == nil {
+		return "", api.Usage{}, false
+	}
+
+	return profile.Metadata.Model, api.Usage{
+		PromptTokens:     profile.Metadata.PromptTokens,
+		CompletionTokens: profile.Metadata.CompletionTokens,
+		TotalTokens:      *profile.Metadata.TotalTokens,
+	}, true
+}
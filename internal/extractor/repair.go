@@ -0,0 +1,58 @@
+package extractor
+
+import (
+	"encoding/json"
+	"fmt"
+	"interview-bot-complete/internal/api"
+	"interview-bot-complete/internal/apperr"
+	"interview-bot-complete/internal/jsonrepair"
+	"interview-bot-complete/internal/metrics"
+	"interview-bot-complete/internal/prompts"
+	"log/slog"
+)
+
+// SetMetrics подключает учет способов восстановления JSON ответов модели
+func (s *Service) SetMetrics(registry *metrics.Registry) {
+	s.metrics = registry
+}
+
+// decodeJSON разбирает JSON из ответа модели в target и возвращает валидный JSON и способ восстановления.
+// Невалидный ответ сначала чинится локально (висячие запятые, обрезанный текст), затем одним
+// запросом «исправь JSON» к модели.
+func (s *Service) decodeJSON(content string, target interface{}, logger *slog.Logger) (string, string, error) {
+	fixed, method, err := jsonrepair.Repair(content)
+	if err != nil {
+		logger.Warn("JSON ответа модели не восстановлен локально, запрашиваю исправление", "error", err, "content_length", len(content))
+		fixed, err = s.repairWithLLM(content, err)
+		method = jsonrepair.MethodLLM
+	}
+	if err == nil {
+		err = json.Unmarshal([]byte(fixed), target)
+	}
+	if err != nil {
+		s.metrics.JSONRepaired(jsonrepair.MethodFailed)
+		return "", jsonrepair.MethodFailed, apperr.Wrap(apperr.ErrInvalidJSON, err)
+	}
+
+	s.metrics.JSONRepaired(method)
+	if method != jsonrepair.MethodStrict {
+		logger.Info("JSON ответа модели восстановлен", "method", method)
+	}
+	return fixed, method, nil
+}
+
+// repairWithLLM просит модель исправить синтаксис JSON; ответ еще раз проходит локальное восстановление
+func (s *Service) repairWithLLM(content string, parseErr error) (string, error) {
+	temperature := 0.0
+	completion, err := s.apiClient.ExtractProfileWithOptions(prompts.GenerateJSONRepairPrompt(content, parseErr), api.CompletionOptions{
+		Temperature: &temperature,
+	})
+	if err != nil {
+		return "", fmt.Errorf("ошибка исправления JSON: %w", err)
+	}
+	fixed, _, err := jsonrepair.Repair(completion.Content)
+	if err != nil {
+		return "", fmt.Errorf("модель не исправила JSON: %w", err)
+	}
+	return fixed, nil
+}
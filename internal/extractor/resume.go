@@ -0,0 +1,80 @@
+package extractor
+
+import (
+	"encoding/json"
+	"fmt"
+	"interview-bot-complete/internal/api"
+	"interview-bot-complete/internal/interviewer"
+	"interview-bot-complete/internal/prompts"
+	"sort"
+)
+
+// resumeFactFields - фактические поля профиля, которые можно взять из резюме; черты характера,
+// мотивы и цели остаются интервью
+var resumeFactFields = []string{
+	"name", "age", "current_city",
+	"university", "education_level", "field_of_study", "graduation_year",
+	"current_position", "work_experience_years", "previous_companies",
+	"hard_skills", "programming_languages", "tools_and_technologies", "certifications",
+	"languages_spoken", "achievements",
+}
+
+// ExtractResumeFacts извлекает из текста резюме факты для полей схемы профиля арендатора tenantID
+// и возвращает их JSON-объектом «поле - значение»; пусто - в резюме нет подходящих фактов.
+// Поля вне resumeFactFields и схемы, а также пустые значения отбрасываются.
+func (s *Service) ExtractResumeFacts(resumeText, tenantID, lang string) (string, error) {
+	schemaFields := s.schemaFor(tenantID)
+	var fields []string
+	allowed := make(map[string]bool)
+	for _, name := range resumeFactFields {
+		if field, ok := schemaFields[name]; ok {
+			fields = append(fields, field.String())
+			allowed[name] = true
+		}
+	}
+	if len(fields) == 0 {
+		return "", fmt.Errorf("в схеме профиля нет полей, которые можно заполнить из резюме")
+	}
+
+	prompt := prompts.GenerateResumeFactsPrompt(resumeText, fields, lang)
+	completion, err := s.apiClient.ExtractProfileWithOptions(prompt, api.CompletionOptions{
+		Priority: api.PriorityInteractive,
+	})
+	if err != nil {
+		return "", fmt.Errorf("ошибка извлечения фактов из резюме: %w", err)
+	}
+
+	var extracted map[string]interface{}
+	if _, _, err := s.decodeJSON(completion.Content, &extracted, s.logger); err != nil {
+		return "", fmt.Errorf("ошибка парсинга фактов из резюме: %w", err)
+	}
+	facts := make(map[string]interface{})
+	for name, value := range extracted {
+		if allowed[name] && interviewer.FieldValue(value) != "" {
+			facts[name] = value
+		}
+	}
+	if len(facts) == 0 {
+		return "", nil
+	}
+	data, err := json.Marshal(facts)
+	if err != nil {
+		return "", fmt.Errorf("ошибка сериализации фактов из резюме: %w", err)
+	}
+	return string(data), nil
+}
+
+// applyResumeFacts заполняет фактами из резюме поля профиля, пустые после извлечения из ответов
+// (ответы интервью актуальнее резюме), и возвращает заполненные поля
+func applyResumeFacts(profile map[string]interface{}, facts map[string]interface{}) []string {
+	var filled []string
+	for name, value := range facts {
+		if interviewer.FieldValue(profile[name]) != "" {
+			continue
+		}
+		profile[name] = value
+		filled = append(filled, name)
+	}
+	sort.Strings(filled)
+	return filled
+}
@@ -2,16 +2,24 @@ package extractor
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"interview-bot-complete/internal/api"
+	"interview-bot-complete/internal/config"
 	"interview-bot-complete/internal/interview"
+	"interview-bot-complete/internal/jsonrepair"
+	"interview-bot-complete/internal/language"
+	"interview-bot-complete/internal/logging"
+	"interview-bot-complete/internal/matcher"
+	"interview-bot-complete/internal/metrics"
 	"interview-bot-complete/internal/prompts"
+	"interview-bot-complete/internal/rubric"
 	"interview-bot-complete/internal/schema"
 	"interview-bot-complete/internal/storage"
 	"interview-bot-complete/internal/validator"
 	"io/ioutil"
-	"log"
-	"os"
+	"log/slog"
+	"sync"
 	"time"
 )
 
@@ -19,6 +27,27 @@ import (
 type Service struct {
 	apiClient    *api.OpenAIClient
 	schemaFields map[string]schema.SchemaField
+	// schemaFile - файл общей схемы профиля; схема перечитывается из него (ReadSchema, SetSchema)
+	schemaFile  string
+	schemaMutex sync.RWMutex
+	// tenantSchemas - схемы профиля арендаторов со своей схемой (ключ - ID арендатора)
+	tenantSchemas   map[string]map[string]schema.SchemaField
+	lastProfileJSON *profileCache
+	// summaryMutex защищает файлы готовых резюме по стилям
+	summaryMutex sync.Mutex
+	logger       *slog.Logger
+	// matcher подбирает архетип для расширенного анализа; nil - без архетипа
+	matcher *matcher.Matcher
+	// classifier оценивает кандидатов по рубрикам ролей для рекрутеров; nil - оценка отключена
+	classifier *rubric.Classifier
+	// metrics учитывает способы восстановления JSON ответов; nil - без учета
+	metrics *metrics.Registry
+	// exportFormats - форматы для ATS, в которых профиль сохраняется дополнительно
+	exportFormats []string
+	// excludeModerated исключает из анализа ответы, отмеченные модерацией
+	excludeModerated bool
+	// contextTokens - размер контекста модели; 0 - по таблице моделей api.ContextWindow
+	contextTokens int
 }
 
 // ProfileResult представляет результат анализа профиля
@@ -27,83 +56,373 @@ type ProfileResult struct {
 	Metadata    map[string]interface{} `json:"metadata"`
 	Success     bool                   `json:"success"`
 	Error       string                 `json:"error,omitempty"`
+	Model       string                 `json:"model,omitempty"`
+	Usage       api.Usage              `json:"usage"`
+	Language    string                 `json:"language,omitempty"`
+	// TemplateID, Tenant и InterviewTimestamp нужны для шаблона имени файла профиля
+	TemplateID         string `json:"template_id,omitempty"`
+	Tenant             string `json:"tenant,omitempty"`
+	InterviewTimestamp string `json:"interview_timestamp,omitempty"`
+	// ExternalID - идентификатор пользователя во внешней HR-системе (/link) для выгрузок в ATS
+	ExternalID string `json:"external_id,omitempty"`
 }
 
+// ExtractOptions переопределяет модель и версию промпта при извлечении
+type ExtractOptions struct {
+	Model         string
+	PromptVersion string
+	Temperature   *float64
+	MaxTokens     int
+	// NoCache - запросы извлечения идут к модели мимо кэша ответов (повторное извлечение)
+	NoCache bool
+	// Extended добавляет платный расширенный анализ; требует оплаты пользователем UserID
+	Extended bool
+	UserID   int64
+	// ArchetypeCatalog - каталог архетипов расширенного анализа; пустой - каталог по умолчанию
+	ArchetypeCatalog string
+	// Rubric - рубрика роли для оценки кандидата; пустая - рубрика по умолчанию
+	Rubric string
+	// Matching - параметры модели для подбора архетипа и оценки по рубрике; пустые поля - как при извлечении
+	Matching config.ModelSettings
+	// Logger - логгер с атрибутами интервью вызывающей стороны; nil - логгер сервиса
+	Logger *slog.Logger
+	// OnStage вызывается в начале каждого этапа извлечения (сообщения о ходе работы); nil - без уведомлений
+	OnStage func(Stage)
+	// BatchID - пакет OpenAI Batch API, в котором получен ответ модели (пишется в _metadata)
+	BatchID string
+}
+
+// SetModerationPolicy задает обработку отмеченных модерацией ответов (config.ModerationExclude или ModerationKeep)
+func (s *Service) SetModerationPolicy(policy string) {
+	s.excludeModerated = policy != config.ModerationKeep
+}
+
+// SetContextTokens задает размер контекста модели извлечения (0 - по модели). Интервью,
+// не помещающиеся в контекст, извлекаются по частям (см. extractChunked).
+func (s *Service) SetContextTokens(tokens int) {
+	s.contextTokens = tokens
+}
+
+// SetLogger задает логгер сервиса
+func (s *Service) SetLogger(logger *slog.Logger) {
+	s.logger = logger
+}
+
+// SetUsageRecorder подключает учет токенов, израсходованных запросами к OpenAI
+func (s *Service) SetUsageRecorder(recorder api.UsageRecorder) {
+	s.apiClient.SetUsageRecorder(recorder)
+}
+
+// SetScheduler ставит запросы экстрактора в общую очередь к OpenAI с фоновым приоритетом
+func (s *Service) SetScheduler(scheduler *api.Scheduler) {
+	s.apiClient.SetScheduler(scheduler)
+}
+
+// SetCompleter направляет запросы к модели в completer вместо OpenAI (nil - снова OpenAI)
+func (s *Service) SetCompleter(completer api.Completer) {
+	s.apiClient.SetCompleter(completer)
+}
+
+// loggerFor возвращает логгер вызова с ID интервью
+func (s *Service) loggerFor(opts ExtractOptions, interviewID string) *slog.Logger {
+	if opts.Logger != nil {
+		return opts.Logger
+	}
+	return logging.Interview(s.logger, interviewID, opts.UserID, 0)
+}
+
+// defaultSchemaFile - схема профиля относительно рабочей директории
+const defaultSchemaFile = "config/profile_schema.yaml"
+
 // New создает новый сервис экстрактора
 func New(openaiAPIKey string) (*Service, error) {
+	return NewWithSchema(openaiAPIKey, defaultSchemaFile)
+}
+
+// NewWithSchema создает сервис экстрактора со схемой профиля из schemaFile
+func NewWithSchema(openaiAPIKey, schemaFile string) (*Service, error) {
 	// Создаем клиент API
 	client := api.NewOpenAIClient(openaiAPIKey)
 
-	// Загружаем схему из config/profile_schema.yaml
-	yamlContent, err := ioutil.ReadFile("config/profile_schema.yaml")
+	// Загружаем схему профиля
+	schemaFields, err := loadSchema(schemaFile)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := slog.Default()
+	logger.Info("Profile Extractor: загружена схема профиля", "fields", len(schemaFields))
+
+	return &Service{
+		apiClient:       client,
+		schemaFile:      schemaFile,
+		schemaFields:    schemaFields,
+		lastProfileJSON: newProfileCache(defaultProfileCacheSize),
+		logger:          logger,
+	}, nil
+}
+
+// loadSchema читает и разбирает схему профиля
+func loadSchema(schemaFile string) (map[string]schema.SchemaField, error) {
+	yamlContent, err := ioutil.ReadFile(schemaFile)
 	if err != nil {
-		return nil, fmt.Errorf("error reading config/profile_schema.yaml: %w", err)
+		return nil, fmt.Errorf("error reading %s: %w", schemaFile, err)
 	}
 
-	// Парсим схему
 	schemaFields, err := schema.ParseYAMLSchema(yamlContent)
 	if err != nil {
 		return nil, fmt.Errorf("error parsing schema: %w", err)
 	}
+	return schemaFields, nil
+}
 
-	log.Printf("Profile Extractor: Загружена схема с %d полями", len(schemaFields))
+// SchemaFile возвращает файл общей схемы профиля
+func (s *Service) SchemaFile() string {
+	return s.schemaFile
+}
 
-	return &Service{
-		apiClient:    client,
-		schemaFields: schemaFields,
-	}, nil
+// ReadSchema заново читает общую схему профиля из файла, не применяя ее (см. SetSchema)
+func (s *Service) ReadSchema() (map[string]schema.SchemaField, error) {
+	schemaFields, err := loadSchema(s.schemaFile)
+	if err != nil {
+		return nil, err
+	}
+	if len(schemaFields) == 0 {
+		return nil, fmt.Errorf("schema %s has no fields", s.schemaFile)
+	}
+	return schemaFields, nil
+}
+
+// SetSchema заменяет общую схему профиля; действует на следующие извлечения
+func (s *Service) SetSchema(schemaFields map[string]schema.SchemaField) {
+	s.schemaMutex.Lock()
+	s.schemaFields = schemaFields
+	s.schemaMutex.Unlock()
+	s.logger.Info("Profile Extractor: схема профиля обновлена", "fields", len(schemaFields))
+}
+
+// LoadTenantSchema загружает схему профиля арендатора; его интервью извлекаются по ней
+func (s *Service) LoadTenantSchema(tenantID, schemaFile string) error {
+	schemaFields, err := loadSchema(schemaFile)
+	if err != nil {
+		return err
+	}
+	if s.tenantSchemas == nil {
+		s.tenantSchemas = make(map[string]map[string]schema.SchemaField)
+	}
+	s.tenantSchemas[tenantID] = schemaFields
+	s.logger.Info("Profile Extractor: загружена схема профиля арендатора", "tenant", tenantID, "fields", len(schemaFields))
+	return nil
+}
+
+// schemaFor возвращает схему профиля арендатора (общую, если своей схемы у него нет)
+func (s *Service) schemaFor(tenantID string) map[string]schema.SchemaField {
+	if fields, ok := s.tenantSchemas[tenantID]; ok {
+		return fields
+	}
+	s.schemaMutex.RLock()
+	defer s.schemaMutex.RUnlock()
+	return s.schemaFields
 }
 
 // ExtractProfile извлекает профиль из результата интервью (оптимизированно - один запрос)
 func (s *Service) ExtractProfile(interviewResult *storage.InterviewResult) (*ProfileResult, error) {
-	log.Printf("Начинаю извлечение профиля для интервью: %s", interviewResult.InterviewID)
+	return s.ExtractProfileWithOptions(interviewResult, ExtractOptions{})
+}
 
-	// Конвертируем InterviewResult в формат Profile Extractor
-	extractorInterview := s.convertToExtractorFormat(interviewResult)
+// ExtractProfileWithOptions извлекает профиль с переопределенной моделью или версией промпта
+func (s *Service) ExtractProfileWithOptions(interviewResult *storage.InterviewResult, opts ExtractOptions) (*ProfileResult, error) {
+	logger := s.loggerFor(opts, interviewResult.InterviewID)
+	logger.Info("Начинаю извлечение профиля", "model", opts.Model, "prompt_version", opts.PromptVersion, "extended", opts.Extended)
 
-	// Извлекаем контекстуальные ответы
-	userText := extractorInterview.ExtractContextualAnswers()
-	log.Printf("Извлечено текста: %d символов", len(userText))
+	if opts.Extended {
+		if err := checkEntitlement(opts.UserID); err != nil {
+			return &ProfileResult{Success: false, Error: err.Error()}, err
+		}
+	}
 
-	// ЕДИНСТВЕННЫЙ запрос к API - извлечение и валидация в одном промпте
-	log.Println("Извлечение профиля (оптимизированно)...")
-	optimizedPrompt := prompts.GenerateOptimizedExtractionPrompt(s.schemaFields, userText)
+	promptVersion := opts.PromptVersion
+	if promptVersion == "" {
+		promptVersion = prompts.DefaultPromptVersion
+	}
+	opts.stage(StagePreparing, 5)
 
-	profileJSON, err := s.apiClient.ExtractProfile(optimizedPrompt)
+	// ЕДИНСТВЕННЫЙ запрос к API - извлечение и валидация в одном промпте
+	optimizedPrompt, lang, err := s.prepareExtractionPrompt(interviewResult, promptVersion, logger)
 	if err != nil {
 		return &ProfileResult{
 			Success: false,
-			Error:   fmt.Sprintf("Ошибка извлечения профиля: %v", err),
+			Error:   fmt.Sprintf("Ошибка подготовки промпта: %v", err),
 		}, err
 	}
 
-	// Быстрая проверка структуры без дополнительных запросов
-	if err := validator.ValidateProfileJSON(profileJSON, s.schemaFields); err != nil {
-		log.Printf("Предупреждение валидации: %v", err)
+	var completion *api.Completion
+	var formatted map[string]interface{}
+	var profileJSON, repairMethod string
+	chunks := s.splitForContext(interviewResult, promptVersion, lang, optimizedPrompt, opts, logger)
+	if len(chunks) > 1 {
+		// Интервью не помещается в контекст модели: извлекаем по частям и объединяем
+		chunked, err := s.extractChunked(chunks, opts, lang, logger)
+		if err != nil {
+			return &ProfileResult{
+				Success: false,
+				Error:   fmt.Sprintf("Ошибка извлечения профиля по частям: %v", err),
+			}, err
+		}
+		completion, formatted, profileJSON, repairMethod = chunked.completion, chunked.profile, chunked.profileJSON, chunked.repairMethod
+	} else {
+		opts.stage(StageExtracting, 15)
+		completion, err = s.apiClient.ExtractProfileWithOptions(optimizedPrompt, api.CompletionOptions{
+			Model:       opts.Model,
+			Temperature: opts.Temperature,
+			MaxTokens:   opts.MaxTokens,
+			NoCache:     opts.NoCache,
+		})
+		if err != nil {
+			return &ProfileResult{
+				Success: false,
+				Error:   fmt.Sprintf("Ошибка извлечения профиля: %v", err),
+			}, err
+		}
+
+		// Парсим JSON, при необходимости восстанавливая обрезанный или невалидный ответ
+		profileJSON, repairMethod, err = s.decodeJSON(completion.Content, &formatted, logger)
+		if err != nil {
+			return &ProfileResult{
+				Success: false,
+				Error:   fmt.Sprintf("Ошибка парсинга JSON: %v", err),
+			}, err
+		}
 	}
 
-	// Парсим JSON для проверки
-	var formatted map[string]interface{}
-	if err := json.Unmarshal([]byte(profileJSON), &formatted); err != nil {
+	return s.finishProfile(interviewResult, opts, &rawProfile{
+		completion:    completion,
+		profile:       formatted,
+		profileJSON:   profileJSON,
+		repairMethod:  repairMethod,
+		promptVersion: promptVersion,
+		lang:          lang,
+		chunks:        len(chunks),
+	}, logger)
+}
+
+// rawProfile - ответ модели на промпт извлечения до проверки схемой и добавления метаданных
+type rawProfile struct {
+	completion    *api.Completion
+	profile       map[string]interface{}
+	profileJSON   string
+	repairMethod  string
+	promptVersion string
+	lang          string
+	// chunks - число частей, на которые делилось интервью; 1 - один запрос
+	chunks int
+}
+
+// finishProfile проверяет ответ модели схемой, дополняет его резюме, источниками полей, метаданными
+// и расширенным анализом и возвращает готовый профиль
+func (s *Service) finishProfile(interviewResult *storage.InterviewResult, opts ExtractOptions, raw *rawProfile, logger *slog.Logger) (*ProfileResult, error) {
+	completion, promptVersion, lang := raw.completion, raw.promptVersion, raw.lang
+
+	// Профиль должен соответствовать JSON Schema (/schema/profile.json); несоответствия исправляет модель
+	opts.stage(StageValidating, 70)
+	formatted, profileJSON, err := s.conformToSchema(raw.profile, raw.profileJSON, interviewResult.Tenant, logger)
+	if err != nil {
 		return &ProfileResult{
 			Success: false,
-			Error:   fmt.Sprintf("Ошибка парсинга JSON: %v", err),
+			Error:   fmt.Sprintf("Профиль не соответствует схеме: %v", err),
 		}, err
 	}
 
+	// Поля, не раскрытые в ответах, заполняются фактами из резюме
+	resumeFields := applyResumeFacts(formatted, interviewResult.ResumeFacts)
+
+	// Источники полей: только цитаты, найденные в ответах интервью
+	provenance, rejectedCitations := buildProvenance(formatted, interviewResult)
+	if len(provenance) > 0 {
+		formatted[provenanceField] = provenance
+	}
+
 	// Добавляем минимальные метаданные
-	extractorInterview = s.convertToExtractorFormat(interviewResult)
+	extractorInterview := s.convertToExtractorFormat(interviewResult)
 	metadata := extractorInterview.GetInterviewMetadata()
 
 	// Только важные метаданные
-	formatted["_metadata"] = map[string]interface{}{
-		"interview_id":    interviewResult.InterviewID,
-		"creation_date":   time.Now().Format("2006-01-02 15:04:05"),
-		"total_questions": metadata["total_questions"],
-		"completion_rate": metadata["completion_rate"],
+	profileMetadata := map[string]interface{}{
+		"interview_id":      interviewResult.InterviewID,
+		"creation_date":     time.Now().Format("2006-01-02 15:04:05"),
+		"total_questions":   metadata["total_questions"],
+		"completion_rate":   metadata["completion_rate"],
+		"model":             completion.Model,
+		"prompt_version":    promptVersion,
+		"language":          lang,
+		"prompt_tokens":     completion.Usage.PromptTokens,
+		"completion_tokens": completion.Usage.CompletionTokens,
+		"total_tokens":      completion.Usage.TotalTokens,
+		"cached_tokens":     completion.Usage.CachedTokens(),
+		"cached_response":   completion.Cached,
+	}
+	if completion.RequestedModel != "" {
+		profileMetadata["requested_model"] = completion.RequestedModel
+	}
+	if interviewResult.Persona != "" {
+		profileMetadata["interviewer_persona"] = interviewResult.Persona
+	}
+	if interviewResult.Tenant != "" {
+		profileMetadata["tenant"] = interviewResult.Tenant
+	}
+	externalID := s.externalID(interviewResult, logger)
+	if externalID != "" {
+		profileMetadata["external_id"] = externalID
+	}
+	if len(interviewResult.DeclinedBlocks) > 0 {
+		// Темы необязательных блоков, от которых пользователь отказался, в профиле пусты намеренно
+		profileMetadata["declined_blocks"] = interviewResult.DeclinedBlocks
+	}
+	if len(resumeFields) > 0 {
+		profileMetadata["resume_fields"] = resumeFields
+	}
+	if raw.repairMethod != jsonrepair.MethodStrict {
+		profileMetadata["json_repair"] = raw.repairMethod
+	}
+	if raw.chunks > 1 {
+		profileMetadata["extraction_chunks"] = raw.chunks
+	}
+	if opts.BatchID != "" {
+		profileMetadata["batch_id"] = opts.BatchID
+	}
+	if revision := prompts.PromptRevision(promptVersion, lang); revision != "" {
+		// Шаблон промпта с диска: ревизия меняется с каждой правкой файла
+		profileMetadata["prompt_template"] = revision
+	}
+	if prompts.UsesCitations(promptVersion, lang) {
+		profileMetadata["provenance_fields"] = len(provenance)
+		profileMetadata["provenance_rejected"] = rejectedCitations
+	}
+	if interviewResult.Partial {
+		// Прерванное интервью: completion_rate учитывает и непройденные блоки
+		if rate, ok := metadata["completion_rate"].(float64); ok {
+			profileMetadata["completion_rate"] = rate * interviewResult.BlocksCompletion()
+		}
+		profileMetadata["partial"] = true
+		profileMetadata["completed_blocks"] = len(interviewResult.Blocks)
+		profileMetadata["planned_blocks"] = interviewResult.TotalBlocks - len(interviewResult.SkippedBlocks)
+	}
+	addDurationMetadata(profileMetadata, interviewResult)
+	formatted["_metadata"] = profileMetadata
+	addEmotionalMarkers(formatted, interviewResult)
+
+	// Расширенный анализ не критичен: при ошибке сохраняется базовый профиль
+	if opts.Extended {
+		analysis, extendedCompletion, err := s.extendedAnalysis(profileJSON, interviewResult, opts)
+		if err != nil {
+			logger.Warn("Расширенный анализ пропущен", "error", err)
+		} else {
+			attachExtendedAnalysis(formatted, analysis, extendedCompletion)
+		}
 	}
 
 	// Конвертируем обратно в JSON строку
+	opts.stage(StageFinalizing, 95)
 	finalJSON, err := json.MarshalIndent(formatted, "", "  ")
 	if err != nil {
 		return &ProfileResult{
@@ -112,31 +431,158 @@ func (s *Service) ExtractProfile(interviewResult *storage.InterviewResult) (*Pro
 		}, err
 	}
 
-	log.Printf("Извлечение профиля завершено успешно для интервью: %s", interviewResult.InterviewID)
+	logger.Info("Извлечение профиля завершено", "model", completion.Model, "language", lang, "total_tokens", completion.Usage.TotalTokens)
 
 	return &ProfileResult{
 		ProfileJSON: string(finalJSON),
 		Metadata:    metadata,
 		Success:     true,
+		Model:       completion.Model,
+		Usage:       completion.Usage,
+		Language:    lang,
+
+		TemplateID:         interviewResult.TemplateID,
+		Tenant:             interviewResult.Tenant,
+		InterviewTimestamp: interviewResult.Timestamp,
+		ExternalID:         externalID,
 	}, nil
 }
 
-// SaveProfile сохраняет профиль в файл
+// prepareExtractionPrompt строит промпт извлечения и возвращает определенный язык ответов
+func (s *Service) prepareExtractionPrompt(interviewResult *storage.InterviewResult, promptVersion string, logger *slog.Logger) (string, string, error) {
+	// Конвертируем InterviewResult в формат Profile Extractor
+	extractorInterview := s.convertToExtractorFormat(interviewResult)
+
+	// Определяем язык по самим ответам, без вопросов бота
+	lang := language.Detect(extractorInterview.ExtractAllAnswers())
+
+	prompt, err := s.buildExtractionPrompt(interviewResult, promptVersion, lang, logger)
+	if err != nil {
+		return "", "", err
+	}
+	return prompt, lang, nil
+}
+
+// buildExtractionPrompt строит промпт извлечения на языке lang
+func (s *Service) buildExtractionPrompt(interviewResult *storage.InterviewResult, promptVersion, lang string, logger *slog.Logger) (string, error) {
+	extractorInterview := s.convertToExtractorFormat(interviewResult)
+
+	// Извлекаем контекстуальные ответы (с метками для ссылок, если промпт их просит)
+	userText := extractorInterview.ExtractContextualAnswers()
+	if prompts.UsesCitations(promptVersion, lang) {
+		userText = extractorInterview.ExtractCitableAnswers()
+	}
+	logger.Debug("Подготовлен текст для извлечения", "chars", len(userText), "language", lang, "prompt_version", promptVersion)
+
+	return prompts.GenerateExtractionPrompt(promptVersion, s.schemaFor(interviewResult.Tenant), userText, lang)
+}
+
+// SaveProfile сохраняет профиль новой ревизией (v1 для первого профиля интервью).
+// Прежние ревизии не перезаписываются, их можно сравнить через DiffProfileRevisions.
 func (s *Service) SaveProfile(interviewID string, profileResult *ProfileResult) (string, error) {
-	// Создаем папку output если не существует
-	if err := os.MkdirAll("output", 0755); err != nil {
-		return "", fmt.Errorf("ошибка создания папки output: %w", err)
+	fileName, _, err := s.SaveProfileRevision(interviewID, profileResult)
+	return fileName, err
+}
+
+// SaveProfileRevision сохраняет профиль как новую ревизию, не перезаписывая предыдущие.
+// Исходный файл профиля считается ревизией v1.
+func (s *Service) SaveProfileRevision(interviewID string, profileResult *ProfileResult) (string, int, error) {
+	if err := validator.ValidateJSONSchema(profileResult.ProfileJSON, s.ProfileJSONSchema(profileResult.Tenant)); err != nil {
+		return "", 0, fmt.Errorf("профиль не соответствует схеме: %w", err)
+	}
+
+	revision := s.latestRevision(interviewID) + 1
+	fileName := storage.ProfilePath(interviewID, profileResult.TemplateID, profileResult.Tenant, profileResult.InterviewTimestamp, revision)
+
+	// CreateFileAtomic защищает от перезаписи ревизии при параллельных запусках
+	if err := storage.CreateFileAtomic(fileName, []byte(profileResult.ProfileJSON)); err != nil {
+		return "", 0, fmt.Errorf("ошибка сохранения ревизии профиля: %w", err)
+	}
+
+	s.lastProfileJSON.Put(interviewID, profileResult.ProfileJSON)
+	s.exportProfile(interviewID, profileResult)
+
+	s.logger.Info("Ревизия профиля сохранена", logging.KeyInterviewID, interviewID, "revision", revision, "file", fileName)
+	return fileName, revision, nil
+}
+
+// LoadProfileRevision читает сохраненную ревизию профиля
+func (s *Service) LoadProfileRevision(interviewID string, revision int) (string, error) {
+	fileName, err := storage.FindProfile(interviewID, revision)
+	if err != nil {
+		return "", fmt.Errorf("ревизия v%d профиля %s не найдена: %w", revision, interviewID, err)
+	}
+	data, err := storage.ReadFileVerified(fileName)
+	if errors.Is(err, storage.ErrCorrupted) {
+		return "", err
+	}
+	if err != nil {
+		return "", fmt.Errorf("ревизия v%d профиля %s не найдена: %w", revision, interviewID, err)
+	}
+	return string(data), nil
+}
+
+// ProfileRevisions возвращает число сохраненных ревизий профиля интервью
+func (s *Service) ProfileRevisions(interviewID string) int {
+	return s.latestRevision(interviewID)
+}
+
+// latestRevision возвращает номер последней сохраненной ревизии (0 если профиля нет)
+func (s *Service) latestRevision(interviewID string) int {
+	revision := 0
+	for {
+		if _, err := storage.FindProfile(interviewID, revision+1); err != nil {
+			return revision
+		}
+		revision++
+	}
+}
+
+// GetLastProfileJSON возвращает последний профиль интервью из кэша,
+// при промахе читая сохраненный файл профиля
+func (s *Service) GetLastProfileJSON(interviewID string) (string, error) {
+	if profileJSON, ok := s.lastProfileJSON.Get(interviewID); ok {
+		return profileJSON, nil
+	}
+
+	// Откатываемся на последнюю сохраненную ревизию
+	profileJSON, err := s.LoadProfileRevision(interviewID, s.latestRevision(interviewID))
+	if err != nil {
+		return "", fmt.Errorf("профиль %s не найден: %w", interviewID, err)
 	}
 
-	// Сохраняем результат с ID интервью в имени файла
-	fileName := fmt.Sprintf("output/profile_%s.json", interviewID)
-	err := ioutil.WriteFile(fileName, []byte(profileResult.ProfileJSON), 0644)
+	s.lastProfileJSON.Put(interviewID, profileJSON)
+	return profileJSON, nil
+}
+
+// externalID возвращает идентификатор пользователя интервью во внешней HR-системе; пусто - не привязан.
+// Ошибка чтения привязок не мешает извлечению.
+func (s *Service) externalID(result *storage.InterviewResult, logger *slog.Logger) string {
+	if result.UserID == 0 {
+		return ""
+	}
+	externalID, err := storage.ExternalIDOf(result.UserID)
 	if err != nil {
-		return "", fmt.Errorf("ошибка сохранения профиля: %w", err)
+		logger.Warn("Не удалось прочитать привязку к HR-системе", "error", err)
+	}
+	return externalID
+}
+
+// addDurationMetadata добавляет длительность интервью и блоков, если они были записаны
+func addDurationMetadata(metadata map[string]interface{}, result *storage.InterviewResult) {
+	if result.DurationSeconds > 0 {
+		metadata["duration_seconds"] = result.DurationSeconds
 	}
 
-	log.Printf("Профиль сохранен в: %s", fileName)
-	return fileName, nil
+	blockDurations := make(map[string]int)
+	for _, block := range result.Blocks {
+		if block.DurationSeconds > 0 {
+			blockDurations[block.BlockName] = block.DurationSeconds
+		}
+	}
+	if len(blockDurations) > 0 {
+		metadata["block_durations_seconds"] = blockDurations
+	}
 }
 
 // convertToExtractorFormat конвертирует InterviewResult в формат Profile Extractor
@@ -147,9 +593,14 @@ func (s *Service) convertToExtractorFormat(result *storage.InterviewResult) *int
 		var qas []interview.QuestionAndAnswer
 
 		for _, qa := range block.QuestionsAndAnswers {
+			if s.excludeModerated && len(qa.Moderation) > 0 {
+				continue
+			}
 			qas = append(qas, interview.QuestionAndAnswer{
-				Question: qa.Question,
-				Answer:   qa.Answer,
+				Question:   qa.Question,
+				Answer:     qa.Answer,
+				AnswerType: qa.AnswerType,
+				Normalized: qa.Normalized,
 			})
 		}
 
@@ -167,30 +618,61 @@ func (s *Service) convertToExtractorFormat(result *storage.InterviewResult) *int
 	}
 }
 
-// GetProfileSummary создает краткое резюме профиля для отправки в Telegram
+// profileSummaryLabels - подписи краткого резюме профиля
+type profileSummaryLabels struct {
+	Header, Name, University, Position, Hobbies, Skills, Footer string
+}
+
+// summaryLabels - подписи краткого резюме по языку интервью
+var summaryLabels = map[string]profileSummaryLabels{
+	language.Russian: {
+		Header:     "Краткое резюме профиля",
+		Name:       "Имя",
+		University: "Университет",
+		Position:   "Позиция",
+		Hobbies:    "Хобби",
+		Skills:     "Навыки",
+		Footer:     "Полный профиль сохранен в JSON файле.",
+	},
+	language.English: {
+		Header:     "Profile summary",
+		Name:       "Name",
+		University: "University",
+		Position:   "Position",
+		Hobbies:    "Hobbies",
+		Skills:     "Skills",
+		Footer:     "The full profile is saved to a JSON file.",
+	},
+}
+
+// GetProfileSummary создает краткое резюме профиля для отправки в Telegram на языке интервью
 func (s *Service) GetProfileSummary(profileJSON string) (string, error) {
 	var profile map[string]interface{}
 	if err := json.Unmarshal([]byte(profileJSON), &profile); err != nil {
 		return "", err
 	}
+	labels, ok := summaryLabels[ProfileLanguage(profileJSON)]
+	if !ok {
+		labels = summaryLabels[language.Default]
+	}
 
-	summary := "📊 **Краткое резюме профиля:**\n\n"
+	summary := fmt.Sprintf("📊 **%s:**\n\n", labels.Header)
 
 	// Извлекаем ключевые данные из нового формата
 	if name, ok := profile["name"].(string); ok && name != "" {
-		summary += fmt.Sprintf("👤 **Имя:** %s\n", name)
+		summary += fmt.Sprintf("👤 **%s:** %s\n", labels.Name, name)
 	}
 
 	if university, ok := profile["university"].(string); ok && university != "" {
-		summary += fmt.Sprintf("🎓 **Университет:** %s\n", university)
+		summary += fmt.Sprintf("🎓 **%s:** %s\n", labels.University, university)
 	}
 
 	if position, ok := profile["current_position"].(string); ok && position != "" {
-		summary += fmt.Sprintf("💼 **Позиция:** %s\n", position)
+		summary += fmt.Sprintf("💼 **%s:** %s\n", labels.Position, position)
 	}
 
 	if hobbies, ok := profile["hobbies"].([]interface{}); ok && len(hobbies) > 0 {
-		summary += "🎯 **Хобби:** "
+		summary += fmt.Sprintf("🎯 **%s:** ", labels.Hobbies)
 		for i, hobby := range hobbies {
 			if i > 0 && i < 3 {
 				summary += ", "
@@ -205,7 +687,7 @@ func (s *Service) GetProfileSummary(profileJSON string) (string, error) {
 	}
 
 	if skills, ok := profile["hard_skills"].([]interface{}); ok && len(skills) > 0 {
-		summary += "💪 **Навыки:** "
+		summary += fmt.Sprintf("💪 **%s:** ", labels.Skills)
 		for i, skill := range skills {
 			if i > 0 && i < 3 {
 				summary += ", "
@@ -219,7 +701,7 @@ func (s *Service) GetProfileSummary(profileJSON string) (string, error) {
 		summary += "\n"
 	}
 
-	summary += "\n_Полный профиль сохранен в JSON файле._"
+	summary += fmt.Sprintf("\n_%s_", labels.Footer)
 
 	return summary, nil
 }
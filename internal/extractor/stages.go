@@ -0,0 +1,24 @@
+package extractor
+
+// Этапы извлечения профиля, о которых сообщает ExtractOptions.OnStage
+const (
+	StagePreparing  = "preparing"
+	StageExtracting = "extracting"
+	StageValidating = "validating"
+	StageAnalyzing  = "analyzing"
+	StageMatching   = "matching"
+	StageFinalizing = "finalizing"
+)
+
+// Stage - ход извлечения профиля: начавшийся этап и примерная доля выполненной работы в процентах
+type Stage struct {
+	Name    string
+	Percent int
+}
+
+// stage сообщает о начале этапа извлечения, если задан OnStage
+func (o ExtractOptions) stage(name string, percent int) {
+	if o.OnStage != nil {
+		o.OnStage(Stage{Name: name, Percent: percent})
+	}
+}
@@ -0,0 +1,129 @@
+package extractor
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"interview-bot-complete/internal/api"
+	"interview-bot-complete/internal/language"
+	"interview-bot-complete/internal/prompts"
+	"interview-bot-complete/internal/storage"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// summariesDir - поддиректория директории профилей с готовыми резюме по стилям
+const summariesDir = "summaries"
+
+// styledSummary - резюме профиля в одном стиле; ProfileHash связывает его с ревизией профиля
+type styledSummary struct {
+	ProfileHash string `json:"profile_hash"`
+	Text        string `json:"text"`
+	Model       string `json:"model,omitempty"`
+	CreatedAt   string `json:"created_at"`
+}
+
+// GetStyledSummary возвращает резюме последней ревизии профиля в стиле style (bullets, narrative, table).
+// Готовые резюме сохраняются по интервью и стилю и пересоздаются только после изменения профиля.
+func (s *Service) GetStyledSummary(interviewID, style string, opts ExtractOptions) (string, error) {
+	if !prompts.IsSummaryStyle(style) {
+		return "", fmt.Errorf("неизвестный стиль резюме %q (доступны: %s)", style, strings.Join(prompts.SummaryStyles, ", "))
+	}
+
+	profileJSON, err := s.GetLastProfileJSON(interviewID)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256([]byte(profileJSON))
+	profileHash := hex.EncodeToString(hash[:])
+
+	s.summaryMutex.Lock()
+	summaries := loadSummaries(interviewID)
+	s.summaryMutex.Unlock()
+	if cached, ok := summaries[style]; ok && cached.ProfileHash == profileHash {
+		return cached.Text, nil
+	}
+
+	prompt := prompts.GenerateProfileSummaryPrompt(profileJSON, style, ProfileLanguage(profileJSON))
+	completion, err := s.apiClient.ExtractProfileWithOptions(prompt, api.CompletionOptions{
+		Model:       opts.Model,
+		Temperature: opts.Temperature,
+		MaxTokens:   opts.MaxTokens,
+		Priority:    api.PrioritySummary,
+	})
+	if err != nil {
+		return "", fmt.Errorf("ошибка создания резюме: %w", err)
+	}
+
+	var response struct {
+		Summary string `json:"summary"`
+	}
+	if _, _, err := s.decodeJSON(completion.Content, &response, s.logger); err != nil {
+		return "", fmt.Errorf("ошибка парсинга резюме: %w", err)
+	}
+	text := strings.TrimSpace(response.Summary)
+	if text == "" {
+		return "", errors.New("модель вернула пустое резюме")
+	}
+
+	s.summaryMutex.Lock()
+	defer s.summaryMutex.Unlock()
+	summaries = loadSummaries(interviewID)
+	summaries[style] = styledSummary{
+		ProfileHash: profileHash,
+		Text:        text,
+		Model:       completion.Model,
+		CreatedAt:   time.Now().Format(time.RFC3339),
+	}
+	if err := saveSummaries(interviewID, summaries); err != nil {
+		// Резюме уже готово - при ошибке сохранения оно будет создано заново в следующий раз
+		s.loggerFor(opts, interviewID).Warn("Ошибка сохранения резюме", "style", style, "error", err)
+	}
+	return text, nil
+}
+
+// ProfileLanguage возвращает язык профиля из _metadata (или определяет по тексту профиля)
+func ProfileLanguage(profileJSON string) string {
+	var profile struct {
+		Metadata struct {
+			Language string `json:"language"`
+		} `json:"_metadata"`
+	}
+	if json.Unmarshal([]byte(profileJSON), &profile) == nil && profile.Metadata.Language != "" {
+		return profile.Metadata.Language
+	}
+	return language.Detect(profileJSON)
+}
+
+func summariesPath(interviewID string) string {
+	return filepath.Join(storage.OutputDir(), summariesDir, "summary_"+interviewID+".json")
+}
+
+// loadSummaries читает сохраненные резюме интервью; отсутствующий или поврежденный файл - пустой набор
+func loadSummaries(interviewID string) map[string]styledSummary {
+	summaries := make(map[string]styledSummary)
+	data, err := storage.ReadFileVerified(summariesPath(interviewID))
+	if err != nil {
+		return summaries
+	}
+	if json.Unmarshal(data, &summaries) != nil {
+		return make(map[string]styledSummary)
+	}
+	return summaries
+}
+
+func saveSummaries(interviewID string, summaries map[string]styledSummary) error {
+	path := summariesPath(interviewID)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("ошибка создания директории %s: %w", filepath.Dir(path), err)
+	}
+	data, err := json.MarshalIndent(summaries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("ошибка сериализации резюме: %w", err)
+	}
+	return storage.WriteFileAtomic(path, data)
+}
@@ -0,0 +1,135 @@
+package formatters
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Форматы выгрузки профиля для ATS
+const (
+	FormatJSONResume = "json_resume" // JSON Resume (jsonresume.org), schema v1.0.0
+	FormatHRXML      = "hr_xml"      // HR-XML 2.5 Resume
+)
+
+// Meta - сведения об интервью, которых нет в самом профиле
+type Meta struct {
+	InterviewID string
+	Timestamp   string // время интервью, RFC3339; пусто - дата не указывается
+	ExternalID  string // идентификатор кандидата во внешней HR-системе; пусто - не привязан
+}
+
+// formatter преобразует профиль в документ формата
+type formatter struct {
+	extension string
+	render    func(profile Profile, meta Meta) ([]byte, error)
+}
+
+var formatters = map[string]formatter{
+	FormatJSONResume: {extension: ".json", render: renderJSONResume},
+	FormatHRXML:      {extension: ".xml", render: renderHRXML},
+}
+
+// Names возвращает поддерживаемые форматы по алфавиту
+func Names() []string {
+	names := make([]string, 0, len(formatters))
+	for name := range formatters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Validate проверяет, что все форматы поддерживаются
+func Validate(names []string) error {
+	for _, name := range names {
+		if _, ok := formatters[name]; !ok {
+			return fmt.Errorf("неизвестный формат выгрузки %q (допустимы: %s)", name, strings.Join(Names(), ", "))
+		}
+	}
+	return nil
+}
+
+// Extension возвращает расширение файла формата (с точкой)
+func Extension(name string) string {
+	return formatters[name].extension
+}
+
+// Render преобразует JSON профиля в документ формата name
+func Render(name, profileJSON string, meta Meta) ([]byte, error) {
+	f, ok := formatters[name]
+	if !ok {
+		return nil, fmt.Errorf("неизвестный формат выгрузки %q", name)
+	}
+	var profile Profile
+	if err := json.Unmarshal([]byte(profileJSON), &profile); err != nil {
+		return nil, fmt.Errorf("ошибка разбора профиля: %w", err)
+	}
+	return f.render(profile, meta)
+}
+
+// Profile - профиль в формате Viget (поля config/profile_schema.yaml)
+type Profile map[string]interface{}
+
+// String возвращает строковое поле профиля (числа - в десятичной записи)
+func (p Profile) String(key string) string {
+	switch value := p[key].(type) {
+	case string:
+		return strings.TrimSpace(value)
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
+	return ""
+}
+
+// List возвращает поле-массив строками. Модель иногда возвращает элементы объектами -
+// из них берется name, title или company.
+func (p Profile) List(key string) []string {
+	items, ok := p[key].([]interface{})
+	if !ok {
+		if value := p.String(key); value != "" {
+			return []string{value}
+		}
+		return nil
+	}
+	var values []string
+	for _, item := range items {
+		value := ""
+		switch item := item.(type) {
+		case string:
+			value = strings.TrimSpace(item)
+		case map[string]interface{}:
+			for _, field := range []string{"name", "title", "company"} {
+				if text, ok := item[field].(string); ok && strings.TrimSpace(text) != "" {
+					value = strings.TrimSpace(text)
+					break
+				}
+			}
+		case float64:
+			value = strconv.FormatFloat(item, 'f', -1, 64)
+		}
+		if value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// objectives собирает цели кандидата: карьерные, краткосрочные и долгосрочные
+func (p Profile) objectives() []string {
+	var goals []string
+	for _, key := range []string{"career_goals", "short_term_goals", "long_term_goals"} {
+		goals = append(goals, p.List(key)...)
+	}
+	return goals
+}
+
+// date возвращает дату (ГГГГ-ММ-ДД) из метки времени RFC3339
+func date(timestamp string) string {
+	if len(timestamp) < len("2006-01-02") {
+		return ""
+	}
+	return timestamp[:len("2006-01-02")]
+}
@@ -0,0 +1,215 @@
+package formatters
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// hrxmlNamespace - пространство имен HR-XML 2.5
+const hrxmlNamespace = "http://ns.hr-xml.org/2007-04-15"
+
+// hrxmlIDOwner - владелец идентификаторов в документе
+const hrxmlIDOwner = "interview-bot"
+
+type hrxmlResume struct {
+	XMLName      xml.Name        `xml:"Resume"`
+	Namespace    string          `xml:"xmlns,attr"`
+	ResumeID     *hrxmlID        `xml:"ResumeId,omitempty"`
+	Structured   hrxmlStructured `xml:"StructuredXMLResume"`
+	RevisionDate string          `xml:"RevisionDate,omitempty"`
+	UserArea     *hrxmlUserArea  `xml:"UserArea,omitempty"`
+}
+
+type hrxmlID struct {
+	IDOwner string `xml:"idOwner,attr"`
+	Value   string `xml:"IdValue"`
+}
+
+// hrxmlUserArea - расширение HR-XML для данных вне стандарта: идентификатор кандидата в HR-системе
+type hrxmlUserArea struct {
+	CandidateID string `xml:"CandidateId"`
+}
+
+type hrxmlStructured struct {
+	ContactInfo    hrxmlContactInfo     `xml:"ContactInfo"`
+	Objective      string               `xml:"Objective,omitempty"`
+	Employment     *hrxmlEmployment     `xml:"EmploymentHistory,omitempty"`
+	Education      *hrxmlEducation      `xml:"EducationHistory,omitempty"`
+	Certifications *hrxmlCertifications `xml:"LicensesAndCertifications,omitempty"`
+	Qualifications *hrxmlQualifications `xml:"Qualifications,omitempty"`
+	Languages      *hrxmlLanguages      `xml:"Languages,omitempty"`
+	Achievements   *hrxmlAchievements   `xml:"Achievements,omitempty"`
+}
+
+// Вложенные элементы заданы указателями: пустой путь a>b с omitempty все равно выводит пустой a
+type hrxmlContactInfo struct {
+	PersonName    *hrxmlPersonName    `xml:"PersonName,omitempty"`
+	ContactMethod *hrxmlContactMethod `xml:"ContactMethod,omitempty"`
+}
+
+type hrxmlPersonName struct {
+	FormattedName string `xml:"FormattedName"`
+}
+
+type hrxmlContactMethod struct {
+	Municipality string `xml:"PostalAddress>Municipality"`
+}
+
+type hrxmlEmployment struct {
+	Employers []hrxmlEmployer `xml:"EmployerOrg"`
+}
+
+type hrxmlEmployer struct {
+	Name     string         `xml:"EmployerOrgName,omitempty"`
+	Position *hrxmlPosition `xml:"PositionHistory,omitempty"`
+}
+
+type hrxmlPosition struct {
+	Title string `xml:"Title"`
+}
+
+type hrxmlEducation struct {
+	Schools []hrxmlSchool `xml:"SchoolOrInstitution"`
+}
+
+type hrxmlSchool struct {
+	School *hrxmlSchoolName `xml:"School,omitempty"`
+	Degree *hrxmlDegree     `xml:"Degree,omitempty"`
+}
+
+type hrxmlSchoolName struct {
+	Name string `xml:"SchoolName"`
+}
+
+type hrxmlDegree struct {
+	Name  string           `xml:"DegreeName,omitempty"`
+	Date  *hrxmlDegreeDate `xml:"DegreeDate,omitempty"`
+	Major *hrxmlNamed      `xml:"DegreeMajor,omitempty"`
+}
+
+type hrxmlDegreeDate struct {
+	Year string `xml:"Year"`
+}
+
+type hrxmlCertifications struct {
+	Items []hrxmlNamed `xml:"LicenseOrCertification"`
+}
+
+type hrxmlNamed struct {
+	Name string `xml:"Name"`
+}
+
+type hrxmlQualifications struct {
+	Competencies []hrxmlCompetency `xml:"Competency"`
+}
+
+type hrxmlCompetency struct {
+	Name string  `xml:"name,attr"`
+	ID   hrxmlID `xml:"CompetencyId"`
+}
+
+type hrxmlLanguages struct {
+	Items []hrxmlLanguage `xml:"Language"`
+}
+
+type hrxmlLanguage struct {
+	Code string `xml:"LanguageCode"`
+}
+
+type hrxmlAchievements struct {
+	Items []hrxmlDescription `xml:"Achievement"`
+}
+
+type hrxmlDescription struct {
+	Description string `xml:"Description"`
+}
+
+// renderHRXML преобразует профиль в резюме HR-XML. Группа навыка (hard_skills, programming_languages...)
+// передается в CompetencyId, чтобы ATS могла различать навыки.
+func renderHRXML(profile Profile, meta Meta) ([]byte, error) {
+	resume := hrxmlResume{
+		Namespace: hrxmlNamespace,
+		Structured: hrxmlStructured{
+			Objective: strings.Join(profile.objectives(), "; "),
+		},
+		RevisionDate: date(meta.Timestamp),
+	}
+	if meta.InterviewID != "" {
+		resume.ResumeID = &hrxmlID{IDOwner: hrxmlIDOwner, Value: meta.InterviewID}
+	}
+	if meta.ExternalID != "" {
+		resume.UserArea = &hrxmlUserArea{CandidateID: meta.ExternalID}
+	}
+	structured := &resume.Structured
+	if name := profile.String("name"); name != "" {
+		structured.ContactInfo.PersonName = &hrxmlPersonName{FormattedName: name}
+	}
+	if city := profile.String("current_city"); city != "" {
+		structured.ContactInfo.ContactMethod = &hrxmlContactMethod{Municipality: city}
+	}
+
+	var employers []hrxmlEmployer
+	if position := profile.String("current_position"); position != "" {
+		employers = append(employers, hrxmlEmployer{Position: &hrxmlPosition{Title: position}})
+	}
+	for _, company := range profile.List("previous_companies") {
+		employers = append(employers, hrxmlEmployer{Name: company})
+	}
+	if len(employers) > 0 {
+		structured.Employment = &hrxmlEmployment{Employers: employers}
+	}
+
+	var school hrxmlSchool
+	if university := profile.String("university"); university != "" {
+		school.School = &hrxmlSchoolName{Name: university}
+	}
+	degree := hrxmlDegree{Name: profile.String("education_level")}
+	if year := profile.String("graduation_year"); year != "" {
+		degree.Date = &hrxmlDegreeDate{Year: year}
+	}
+	if major := profile.String("field_of_study"); major != "" {
+		degree.Major = &hrxmlNamed{Name: major}
+	}
+	if degree != (hrxmlDegree{}) {
+		school.Degree = &degree
+	}
+	if school.School != nil || school.Degree != nil {
+		structured.Education = &hrxmlEducation{Schools: []hrxmlSchool{school}}
+	}
+
+	if certifications := profile.List("certifications"); len(certifications) > 0 {
+		structured.Certifications = &hrxmlCertifications{}
+		for _, name := range certifications {
+			structured.Certifications.Items = append(structured.Certifications.Items, hrxmlNamed{Name: name})
+		}
+	}
+
+	var competencies []hrxmlCompetency
+	for _, group := range skillGroups {
+		for _, skill := range profile.List(group.key) {
+			competencies = append(competencies, hrxmlCompetency{Name: skill, ID: hrxmlID{IDOwner: hrxmlIDOwner, Value: group.key}})
+		}
+	}
+	if len(competencies) > 0 {
+		structured.Qualifications = &hrxmlQualifications{Competencies: competencies}
+	}
+
+	if languages := profile.List("languages_spoken"); len(languages) > 0 {
+		structured.Languages = &hrxmlLanguages{}
+		for _, lang := range languages {
+			structured.Languages.Items = append(structured.Languages.Items, hrxmlLanguage{Code: lang})
+		}
+	}
+	if achievements := profile.List("achievements"); len(achievements) > 0 {
+		structured.Achievements = &hrxmlAchievements{}
+		for _, achievement := range achievements {
+			structured.Achievements.Items = append(structured.Achievements.Items, hrxmlDescription{Description: achievement})
+		}
+	}
+
+	data, err := xml.MarshalIndent(resume, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
+}
@@ -0,0 +1,144 @@
+package formatters
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// jsonResumeSchema - схема JSON Resume, которой соответствует документ
+const jsonResumeSchema = "https://raw.githubusercontent.com/jsonresume/resume-schema/v1.0.0/schema.json"
+
+type jsonResume struct {
+	Schema       string              `json:"$schema"`
+	Basics       jsonResumeBasics    `json:"basics"`
+	Work         []jsonResumeWork    `json:"work,omitempty"`
+	Volunteer    []jsonResumeWork    `json:"volunteer,omitempty"`
+	Education    []jsonResumeStudy   `json:"education,omitempty"`
+	Awards       []jsonResumeTitle   `json:"awards,omitempty"`
+	Certificates []jsonResumeName    `json:"certificates,omitempty"`
+	Skills       []jsonResumeSkill   `json:"skills,omitempty"`
+	Languages    []jsonResumeLang    `json:"languages,omitempty"`
+	Interests    []jsonResumeName    `json:"interests,omitempty"`
+	Meta         jsonResumeMetaBlock `json:"meta"`
+}
+
+type jsonResumeBasics struct {
+	Name     string              `json:"name,omitempty"`
+	Label    string              `json:"label,omitempty"`
+	Summary  string              `json:"summary,omitempty"`
+	Location *jsonResumeLocation `json:"location,omitempty"`
+}
+
+type jsonResumeLocation struct {
+	City string `json:"city"`
+}
+
+type jsonResumeWork struct {
+	Name         string `json:"name,omitempty"`
+	Organization string `json:"organization,omitempty"`
+	Position     string `json:"position,omitempty"`
+	Summary      string `json:"summary,omitempty"`
+}
+
+type jsonResumeStudy struct {
+	Institution string `json:"institution,omitempty"`
+	Area        string `json:"area,omitempty"`
+	StudyType   string `json:"studyType,omitempty"`
+	EndDate     string `json:"endDate,omitempty"`
+}
+
+type jsonResumeTitle struct {
+	Title string `json:"title"`
+}
+
+type jsonResumeName struct {
+	Name string `json:"name"`
+}
+
+type jsonResumeSkill struct {
+	Name     string   `json:"name"`
+	Keywords []string `json:"keywords"`
+}
+
+type jsonResumeLang struct {
+	Language string `json:"language"`
+}
+
+type jsonResumeMetaBlock struct {
+	Canonical    string `json:"canonical,omitempty"`
+	Version      string `json:"version"`
+	LastModified string `json:"lastModified,omitempty"`
+	InterviewID  string `json:"interviewId,omitempty"`
+	ExternalID   string `json:"externalId,omitempty"`
+}
+
+// skillGroups - поля навыков профиля и названия групп навыков JSON Resume
+var skillGroups = []struct {
+	key, name string
+}{
+	{"hard_skills", "Hard skills"},
+	{"soft_skills", "Soft skills"},
+	{"programming_languages", "Programming languages"},
+	{"tools_and_technologies", "Tools and technologies"},
+}
+
+// renderJSONResume преобразует профиль в JSON Resume: цели кандидата попадают в basics.summary
+func renderJSONResume(profile Profile, meta Meta) ([]byte, error) {
+	resume := jsonResume{
+		Schema: jsonResumeSchema,
+		Basics: jsonResumeBasics{
+			Name:    profile.String("name"),
+			Label:   profile.String("current_position"),
+			Summary: strings.Join(profile.objectives(), "; "),
+		},
+		Meta: jsonResumeMetaBlock{
+			Version:      "v1.0.0",
+			LastModified: meta.Timestamp,
+			InterviewID:  meta.InterviewID,
+			ExternalID:   meta.ExternalID,
+		},
+	}
+	if city := profile.String("current_city"); city != "" {
+		resume.Basics.Location = &jsonResumeLocation{City: city}
+	}
+
+	if position := profile.String("current_position"); position != "" {
+		resume.Work = append(resume.Work, jsonResumeWork{Position: position})
+	}
+	for _, company := range profile.List("previous_companies") {
+		resume.Work = append(resume.Work, jsonResumeWork{Name: company})
+	}
+	for _, experience := range profile.List("volunteer_experience") {
+		resume.Volunteer = append(resume.Volunteer, jsonResumeWork{Summary: experience})
+	}
+
+	study := jsonResumeStudy{
+		Institution: profile.String("university"),
+		Area:        profile.String("field_of_study"),
+		StudyType:   profile.String("education_level"),
+		EndDate:     profile.String("graduation_year"),
+	}
+	if study != (jsonResumeStudy{}) {
+		resume.Education = append(resume.Education, study)
+	}
+
+	for _, achievement := range profile.List("achievements") {
+		resume.Awards = append(resume.Awards, jsonResumeTitle{Title: achievement})
+	}
+	for _, certification := range profile.List("certifications") {
+		resume.Certificates = append(resume.Certificates, jsonResumeName{Name: certification})
+	}
+	for _, group := range skillGroups {
+		if keywords := profile.List(group.key); len(keywords) > 0 {
+			resume.Skills = append(resume.Skills, jsonResumeSkill{Name: group.name, Keywords: keywords})
+		}
+	}
+	for _, lang := range profile.List("languages_spoken") {
+		resume.Languages = append(resume.Languages, jsonResumeLang{Language: lang})
+	}
+	for _, interest := range append(profile.List("interests"), profile.List("hobbies")...) {
+		resume.Interests = append(resume.Interests, jsonResumeName{Name: interest})
+	}
+
+	return json.MarshalIndent(resume, "", "  ")
+}
@@ -3,6 +3,7 @@ package interview
 import (
 	"encoding/json"
 	"fmt"
+	"interview-bot-complete/internal/normalize"
 	"strings"
 )
 
@@ -21,6 +22,9 @@ type Block struct {
 type QuestionAndAnswer struct {
 	Question string `json:"question"`
 	Answer   string `json:"answer"`
+	// AnswerType и Normalized - ожидаемый тип ответа и ответ, приведенный к нему (25, 2021-03-15)
+	AnswerType string `json:"answer_type,omitempty"`
+	Normalized string `json:"normalized,omitempty"`
 }
 
 // ParseInterviewJSON парсит JSON файл интервью
@@ -79,6 +83,7 @@ func (i *Interview) ExtractContextualAnswers() string {
 				// Добавляем вопрос как контекст для лучшего понимания
 				contextualText = append(contextualText, fmt.Sprintf("На вопрос: %s", qa.Question))
 				contextualText = append(contextualText, fmt.Sprintf("Ответ: %s", qa.Answer))
+				contextualText = append(contextualText, qa.typedHint()...)
 				contextualText = append(contextualText, "") // Пустая строка для разделения
 			}
 		}
@@ -87,6 +92,42 @@ func (i *Interview) ExtractContextualAnswers() string {
 	return strings.Join(contextualText, "\n")
 }
 
+// AnswerRef возвращает метку ответа для ссылок на источник: B<id блока>.Q<номер вопроса в блоке>
+func AnswerRef(blockID, questionNumber int) string {
+	return fmt.Sprintf("B%d.Q%d", blockID, questionNumber)
+}
+
+// ExtractCitableAnswers - вариант ExtractContextualAnswers, где каждый ответ помечен
+// меткой AnswerRef, чтобы модель могла сослаться на источник информации
+func (i *Interview) ExtractCitableAnswers() string {
+	var contextualText []string
+
+	for _, block := range i.Blocks {
+		contextualText = append(contextualText, fmt.Sprintf("=== %s ===", formatBlockName(block.BlockName)))
+
+		for n, qa := range block.QuestionsAndAnswers {
+			if strings.TrimSpace(qa.Answer) != "" {
+				contextualText = append(contextualText, fmt.Sprintf("[%s] На вопрос: %s", AnswerRef(block.BlockID, n+1), qa.Question))
+				contextualText = append(contextualText, fmt.Sprintf("Ответ: %s", qa.Answer))
+				contextualText = append(contextualText, qa.typedHint()...)
+				contextualText = append(contextualText, "")
+			}
+		}
+	}
+
+	return strings.Join(contextualText, "\n")
+}
+
+// typedHint возвращает строку с ответом, приведенным к типу вопроса, чтобы модель взяла
+// значение без пересчета («двадцать пять» - 25); пусто - свободный ответ
+func (qa QuestionAndAnswer) typedHint() []string {
+	label := normalize.Label(qa.AnswerType)
+	if label == "" || qa.Normalized == "" {
+		return nil
+	}
+	return []string{fmt.Sprintf("Значение (%s): %s", label, qa.Normalized)}
+}
+
 // formatBlockName преобразует техническое название блока в читаемое
 func formatBlockName(blockName string) string {
 	blockNames := map[string]string{
@@ -129,6 +170,14 @@ func (i *Interview) GetInterviewMetadata() map[string]interface{} {
 		"total_blocks":    len(i.Blocks),
 		"total_questions": totalQuestions,
 		"total_answers":   totalAnswers,
-		"completion_rate": float64(totalAnswers) / float64(totalQuestions) * 100,
+		"completion_rate": completionRate(totalAnswers, totalQuestions),
+	}
+}
+
+// completionRate - процент вопросов с ответом (0 для интервью без вопросов)
+func completionRate(answers, questions int) float64 {
+	if questions == 0 {
+		return 0
 	}
+	return float64(answers) / float64(questions) * 100
 }
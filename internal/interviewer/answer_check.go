@@ -0,0 +1,78 @@
+package interviewer
+
+import (
+	"encoding/json"
+	"fmt"
+	"interview-bot-complete/internal/config"
+	"interview-bot-complete/internal/depth"
+	"interview-bot-complete/internal/language"
+	"strings"
+)
+
+// answerCheckMarker - заголовок промпта оценки ответа (по нему mock-режим выбирает ответ)
+const answerCheckMarker = "ОЦЕНКА ОТВЕТА"
+
+// AnswerCheck - оценка информативности ответа моделью
+type AnswerCheck struct {
+	Informative   bool   `json:"informative"`
+	Clarification string `json:"clarification"`
+	// Depth - глубина ответа от 1 (отписка) до 5 (развернутая рефлексия с примерами); 0 - модель не оценила
+	Depth int `json:"depth"`
+}
+
+// CheckAnswer просит модель оценить, достаточно ли ответ информативен.
+// Для неинформативного ответа модель предлагает уточняющий вопрос.
+func (s *Service) CheckAnswer(question, answer string, cfg *config.Config) (*AnswerCheck, error) {
+	messages := []Message{
+		{Role: "system", Content: buildAnswerCheckPrompt(question, answer, language.Detect(answer))},
+	}
+
+	reply, err := s.callOpenAI(messages, cfg, config.UseCaseAnswerCheck)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка оценки ответа: %w", err)
+	}
+
+	reply = strings.TrimSpace(reply)
+	reply = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(reply, "```json"), "```"), "```"))
+
+	var check AnswerCheck
+	if err := json.Unmarshal([]byte(reply), &check); err != nil {
+		return nil, fmt.Errorf("ошибка парсинга оценки ответа: %w", err)
+	}
+	// Недопустимое уточнение заменяется заготовленным на стороне движка
+	check.Clarification = cleanQuestion(check.Clarification)
+	if check.Clarification != "" {
+		if reason := s.checkQuestion(check.Clarification, config.Block{}); reason != "" {
+			s.logger.Warn("Уточняющий вопрос модели отклонен", "reason", reason, "question", check.Clarification)
+			check.Clarification = ""
+		}
+	}
+	if check.Depth < 0 || check.Depth > depth.MaxRating {
+		check.Depth = 0
+	}
+	return &check, nil
+}
+
+// buildAnswerCheckPrompt создает промпт оценки ответа
+func buildAnswerCheckPrompt(question, answer, lang string) string {
+	var prompt strings.Builder
+
+	prompt.WriteString(answerCheckMarker + "\n\n")
+	prompt.WriteString("Ты опытный интервьюер. Оцени, достаточно ли информативен ответ на вопрос интервью.\n\n")
+	prompt.WriteString(fmt.Sprintf("Вопрос: %s\n", question))
+	prompt.WriteString(fmt.Sprintf("Ответ: %s\n\n", answer))
+
+	prompt.WriteString("Неинформативный ответ - отписка, уход от вопроса или одно-два слова без подробностей.\n")
+	prompt.WriteString("Если ответ неинформативен, сформулируй один короткий доброжелательный уточняющий вопрос,\n")
+	prompt.WriteString("который поможет человеку раскрыть ответ.\n\n")
+	if lang == language.English {
+		prompt.WriteString("Уточняющий вопрос сформулируй на английском языке.\n\n")
+	}
+
+	prompt.WriteString("Оцени также глубину ответа (depth) от 1 до 5: 1 - отписка, 3 - обычный ответ по существу,\n")
+	prompt.WriteString("5 - развернутый ответ с примерами, причинами и выводами.\n\n")
+
+	prompt.WriteString(`ФОРМАТ ОТВЕТА: только JSON без markdown: {"informative": true|false, "depth": 1-5, "clarification": "..."}`)
+
+	return prompt.String()
+}
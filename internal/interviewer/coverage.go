@@ -0,0 +1,69 @@
+package interviewer
+
+import (
+	"encoding/json"
+	"fmt"
+	"interview-bot-complete/internal/config"
+	"interview-bot-complete/internal/storage"
+	"strings"
+)
+
+// coverageMarker - заголовок промпта проверки покрытия (по нему mock-режим выбирает ответ)
+const coverageMarker = "ПОКРЫТИЕ ТЕМ"
+
+// CheckCoverage просит модель определить по саммари диалога блока, какие цели покрытия еще не раскрыты.
+// Возвращает нераскрытые цели в порядке goals.
+func (s *Service) CheckCoverage(summary storage.BlockSummary, goals []string, cfg *config.Config) ([]string, error) {
+	messages := []Message{
+		{Role: "system", Content: buildCoveragePrompt(summary, goals)},
+	}
+
+	reply, err := s.callOpenAI(messages, cfg, config.UseCaseCoverage)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка проверки покрытия: %w", err)
+	}
+
+	reply = strings.TrimSpace(reply)
+	reply = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(reply, "```json"), "```"), "```"))
+
+	var check struct {
+		Uncovered []int `json:"uncovered"`
+	}
+	if err := json.Unmarshal([]byte(reply), &check); err != nil {
+		return nil, fmt.Errorf("ошибка парсинга проверки покрытия: %w", err)
+	}
+
+	// Модель отвечает номерами целей: номера вне списка игнорируются
+	var uncovered []string
+	for n, goal := range goals {
+		for _, number := range check.Uncovered {
+			if number == n+1 {
+				uncovered = append(uncovered, goal)
+				break
+			}
+		}
+	}
+	return uncovered, nil
+}
+
+// buildCoveragePrompt создает промпт проверки покрытия целей блока
+func buildCoveragePrompt(summary storage.BlockSummary, goals []string) string {
+	var prompt strings.Builder
+
+	prompt.WriteString(coverageMarker + "\n\n")
+	prompt.WriteString("Ты опытный интервьюер. Ниже саммари ответов человека в текущем блоке интервью\n")
+	prompt.WriteString("и темы, которые должны быть раскрыты в этом блоке.\n\n")
+	prompt.WriteString("САММАРИ:\n")
+	prompt.WriteString(SummaryText(summary) + "\n\n")
+
+	prompt.WriteString("ТЕМЫ:\n")
+	for n, goal := range goals {
+		prompt.WriteString(fmt.Sprintf("%d. %s\n", n+1, goal))
+	}
+	prompt.WriteString("\n")
+
+	prompt.WriteString("Тема раскрыта, если человек рассказал о ней по существу; упоминания вскользь недостаточно.\n\n")
+	prompt.WriteString(`ФОРМАТ ОТВЕТА: только JSON без markdown с номерами нераскрытых тем: {"uncovered": [1, 3]}`)
+
+	return prompt.String()
+}
@@ -0,0 +1,100 @@
+package interviewer
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// memoryListItems - столько элементов поля-списка прошлого профиля попадает в промпт
+const memoryListItems = 5
+
+// WithResumeFacts возвращает копию сервиса, которая знает факты из резюме пользователя (строки ProfileMemory)
+func (s *Service) WithResumeFacts(facts string) *Service {
+	scoped := *s
+	scoped.resumeFacts = facts
+	return &scoped
+}
+
+// WithMemory возвращает копию сервиса, которая учитывает прошлое интервью пользователя (см. ProfileMemory)
+func (s *Service) WithMemory(memory string) *Service {
+	scoped := *s
+	scoped.memory = memory
+	return &scoped
+}
+
+// ProfileMemory сжимает профиль прошлого интервью в строки «поле: значение» для промпта вопросов.
+// Служебные поля (_metadata...) и пустые значения пропускаются; пусто - в профиле нет данных.
+func ProfileMemory(profileJSON string) (string, error) {
+	var profile map[string]interface{}
+	if err := json.Unmarshal([]byte(profileJSON), &profile); err != nil {
+		return "", fmt.Errorf("ошибка разбора прошлого профиля: %w", err)
+	}
+
+	keys := make([]string, 0, len(profile))
+	for key := range profile {
+		if !strings.HasPrefix(key, "_") {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var lines []string
+	for _, key := range keys {
+		if value := FieldValue(profile[key]); value != "" {
+			lines = append(lines, fmt.Sprintf("- %s: %s", key, value))
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// FieldValue переводит значение поля профиля в текст; у списков - первые memoryListItems элементов
+func FieldValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case float64, bool:
+		return fmt.Sprint(v)
+	case []interface{}:
+		var items []string
+		for _, item := range v {
+			if text := FieldValue(item); text != "" {
+				items = append(items, text)
+			}
+			if len(items) == memoryListItems {
+				break
+			}
+		}
+		return strings.Join(items, "; ")
+	case map[string]interface{}:
+		for _, field := range []string{"name", "title", "company"} {
+			if text, ok := v[field].(string); ok && strings.TrimSpace(text) != "" {
+				return strings.TrimSpace(text)
+			}
+		}
+	}
+	return ""
+}
+
+// writeMemory добавляет в промпт прошлый профиль пользователя, если он согласился на память интервьюера
+func (s *Service) writeMemory(prompt *strings.Builder) {
+	if s.memory == "" {
+		return
+	}
+	prompt.WriteString("ИЗ ПРОШЛОГО ИНТЕРВЬЮ С ЭТИМ ЧЕЛОВЕКОМ:\n")
+	prompt.WriteString(s.memory + "\n")
+	prompt.WriteString("Если это уместно в текущем блоке, сошлись на прошлые ответы («в прошлый раз вы упоминали...») " +
+		"и спроси, что изменилось. Не пересказывай прошлый профиль и не считай его актуальным без подтверждения.\n\n")
+}
+
+// writeResumeFacts добавляет в промпт факты из резюме, чтобы интервьюер не спрашивал их заново
+func (s *Service) writeResumeFacts(prompt *strings.Builder) {
+	if s.resumeFacts == "" {
+		return
+	}
+	prompt.WriteString("ИЗ РЕЗЮМЕ ЭТОГО ЧЕЛОВЕКА (известные факты):\n")
+	prompt.WriteString(s.resumeFacts + "\n")
+	prompt.WriteString("Не спрашивай заново то, что уже указано в резюме. Если факт важен для блока, " +
+		"можно коротко попросить его подтвердить, а вопрос посвятить тому, чего в резюме нет: мотивам, опыту, примерам.\n\n")
+}
@@ -0,0 +1,112 @@
+package interviewer
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ProviderMock включает режим симуляции без обращения к OpenAI
+const ProviderMock = "mock"
+
+// mockQuestions - заготовленные вопросы для режима симуляции
+var mockQuestions = []string{
+	"Расскажите, пожалуйста, об этом подробнее — что для вас здесь самое важное?",
+	"Можете привести конкретный пример из своего опыта?",
+	"Как это повлияло на ваши дальнейшие решения?",
+}
+
+// mockSummary - заготовленное структурированное саммари блока для режима симуляции
+const mockSummary = `{"summary": "[mock] Человек открыто делится опытом, ориентирован на развитие и командную работу.",
+"key_facts": ["[mock] Работает в команде разработки"],
+"important_themes": ["профессиональный рост", "обучение", "баланс работы и жизни"],
+"emotional_markers": [], "behavioral_patterns": [], "values_beliefs": ["развитие"], "priorities": [], "sensitive_topics": []}`
+
+// mockProfileAnswer - заготовленный ответ на вопрос о профиле для режима симуляции
+const mockProfileAnswer = "[mock] По вашему профилю видно стремление к развитию и командной работе — " +
+	"вам могут подойти роли, где важны обучение других и совместные проекты."
+
+// mockAnswerCheck считает информативными ответы от пяти слов, глубокими - от тридцати
+func mockAnswerCheck(prompt string) string {
+	answer := ""
+	if start := strings.Index(prompt, "Ответ: "); start >= 0 {
+		answer = prompt[start+len("Ответ: "):]
+		if end := strings.Index(answer, "\n\n"); end >= 0 {
+			answer = answer[:end]
+		}
+	}
+	switch words := len(strings.Fields(answer)); {
+	case words >= 30:
+		return `{"informative": true, "depth": 5, "clarification": ""}`
+	case words >= 5:
+		return `{"informative": true, "depth": 3, "clarification": ""}`
+	}
+	return `{"informative": false, "depth": 1, "clarification": "[mock] Могли бы вы рассказать об этом чуть подробнее?"}`
+}
+
+// mockCoverage считает раскрытыми темы, слова которых встречаются в саммари
+func mockCoverage(prompt string) string {
+	summary, goals := prompt, ""
+	if start := strings.Index(prompt, "САММАРИ:\n"); start >= 0 {
+		summary = prompt[start:]
+	}
+	if start := strings.Index(summary, "ТЕМЫ:\n"); start >= 0 {
+		summary, goals = strings.ToLower(summary[:start]), summary[start+len("ТЕМЫ:\n"):]
+	}
+	if end := strings.Index(goals, "\n\n"); end >= 0 {
+		goals = goals[:end]
+	}
+
+	uncovered := []string{}
+	for n, line := range strings.Split(goals, "\n") {
+		_, goal, _ := strings.Cut(line, ". ")
+		for _, word := range strings.Fields(strings.ToLower(goal)) {
+			if !strings.Contains(summary, word) {
+				uncovered = append(uncovered, strconv.Itoa(n+1))
+				break
+			}
+		}
+	}
+	return `{"uncovered": [` + strings.Join(uncovered, ", ") + `]}`
+}
+
+// getProviderFromEnv возвращает провайдера LLM из переменных окружения
+func getProviderFromEnv() string {
+	provider := strings.ToLower(strings.TrimSpace(os.Getenv("LLM_PROVIDER")))
+	if provider == "" {
+		return "openai" // значение по умолчанию
+	}
+	return provider
+}
+
+// mockCompletion возвращает заготовленный ответ вместо запроса к OpenAI
+func mockCompletion(messages []Message) string {
+	prompt := ""
+	if len(messages) > 0 {
+		prompt = messages[0].Content
+	}
+
+	if strings.Contains(prompt, profileQAMarker) {
+		return mockProfileAnswer
+	}
+
+	if strings.Contains(prompt, answerCheckMarker) {
+		return mockAnswerCheck(prompt)
+	}
+
+	if strings.Contains(prompt, coverageMarker) {
+		return mockCoverage(prompt)
+	}
+
+	if strings.Contains(prompt, "САММАРИ") || strings.Contains(prompt, "SUMMARY") {
+		return mockSummary
+	}
+
+	// Чередуем вопросы в зависимости от длины текущего диалога
+	return mockQuestions[strings.Count(prompt, "Вопрос ")%len(mockQuestions)]
+}
+
+// IsMock сообщает, работает ли сервис в режиме симуляции
+func (s *Service) IsMock() bool {
+	return s.provider == ProviderMock
+}
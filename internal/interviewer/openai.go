@@ -2,12 +2,17 @@ package interviewer
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"interview-bot-complete/internal/api"
 	"interview-bot-complete/internal/config"
 	"io"
 	"net/http"
 	"os"
+	"strings"
+	"time"
 )
 
 // OpenAI API структуры
@@ -25,6 +30,7 @@ type Message struct {
 
 type OpenAIResponse struct {
 	Choices []Choice  `json:"choices"`
+	Usage   api.Usage `json:"usage"`
 	Error   *APIError `json:"error,omitempty"`
 }
 
@@ -37,8 +43,6 @@ type APIError struct {
 	Type    string `json:"type"`
 }
 
-const openaiURL = "https://api.openai.com/v1/chat/completions"
-
 // getModelFromEnv возвращает модель из переменных окружения
 func getModelFromEnv() string {
 	model := os.Getenv("OPENAI_MODEL")
@@ -48,19 +52,112 @@ func getModelFromEnv() string {
 	return model
 }
 
-// callOpenAI делает запрос к OpenAI API
-func (s *Service) callOpenAI(messages []Message, cfg *config.Config) (string, error) {
-	// Получаем модель из переменных окружения
-	model := getModelFromEnv()
+// callOpenAI делает запрос к OpenAI API с параметрами сценария useCase
+func (s *Service) callOpenAI(messages []Message, cfg *config.Config, useCase string) (string, error) {
+	content, _, err := s.complete(messages, cfg, useCase)
+	return content, err
+}
+
+// complete делает запрос к OpenAI API, переходя по цепочке резервных моделей,
+// и возвращает ответ вместе с моделью, которая его сформировала.
+// Модель и параметры генерации задаются по этапам в секции openai конфигурации и по сценариям в секции models.
+func (s *Service) complete(messages []Message, cfg *config.Config, useCase string) (string, string, error) {
+	settings := cfg.ModelFor(useCase)
+	if s.persona != nil && s.persona.Temperature != nil && useCase == config.UseCaseQuestions {
+		settings.Temperature = s.persona.Temperature
+	}
+	model := settings.Model
+	if model == "" {
+		model = getModelFromEnv()
+	}
+
+	if s.completer != nil {
+		content, err := s.completer.Complete(useCase, joinMessages(messages))
+		if err != nil {
+			return "", "", err
+		}
+		return content, api.ModelCompleter, nil
+	}
+
+	// В режиме симуляции отвечаем заготовками без запроса к API
+	if s.IsMock() {
+		return mockCompletion(messages), ProviderMock, nil
+	}
+
+	var lastErr error
+	for _, candidate := range api.ModelChain(model, s.fallbacks) {
+		content, err := s.requestCompletion(candidate, messages, cfg, settings, priorityFor(useCase))
+		if err == nil {
+			if candidate != model {
+				s.logger.Warn("Ответ получен от резервной модели", "model", candidate, "requested", model, "use_case", useCase)
+			}
+			return content, candidate, nil
+		}
+
+		var statusErr *api.StatusError
+		if !errors.As(err, &statusErr) || !api.IsModelError(statusErr.StatusCode, statusErr.Body) {
+			return "", "", err
+		}
+		s.logger.Warn("Модель недоступна, пробуем следующую", "model", candidate, "status", statusErr.StatusCode, "use_case", useCase)
+		lastErr = err
+	}
+	return "", "", lastErr
+}
+
+// priorityFor возвращает класс запроса сценария в планировщике: вопросы и проверки, ответа на
+// которые ждет пользователь, идут раньше саммари и проверки целей блока
+func priorityFor(useCase string) api.Priority {
+	switch useCase {
+	case config.UseCaseSummary, config.UseCaseCoverage:
+		return api.PrioritySummary
+	}
+	return api.PriorityInteractive
+}
+
+// joinMessages склеивает сообщения диалога в один промпт для Completer
+func joinMessages(messages []Message) string {
+	contents := make([]string, 0, len(messages))
+	for _, message := range messages {
+		contents = append(contents, message.Content)
+	}
+	return strings.Join(contents, "\n\n")
+}
+
+// defaultTemperature - температура запросов интервьюера, если она не задана в секциях openai и models
+const defaultTemperature = 0.7
+
+// defaultMaxTokens - max_tokens запросов интервьюера, если он не задан в секциях openai и models:
+// запас на вопросы блока и уточняющие вопросы при проведении блока целиком
+func defaultMaxTokens(cfg *config.Config) int {
+	return 500 + (cfg.GetQuestionsPerBlock()+cfg.GetMaxFollowupQuestions())*100
+}
 
-	// Динамически рассчитываем max_tokens на основе конфигурации
-	maxTokens := 500 + (cfg.GetQuestionsPerBlock()+cfg.GetMaxFollowupQuestions())*100
+// requestCompletion выполняет один запрос к указанной модели
+func (s *Service) requestCompletion(model string, messages []Message, cfg *config.Config, settings config.ModelSettings, priority api.Priority) (string, error) {
+	maxTokens := defaultMaxTokens(cfg)
+	if settings.MaxTokens > 0 {
+		maxTokens = settings.MaxTokens
+	}
+	temperature := defaultTemperature
+	if settings.Temperature != nil {
+		temperature = *settings.Temperature
+	}
+
+	if s.scheduler != nil {
+		waited, err := s.scheduler.Wait(context.Background(), priority, api.EstimateTokens(joinMessages(messages))+maxTokens)
+		if err != nil {
+			return "", fmt.Errorf("запрос не дождался лимита OpenAI: %w", err)
+		}
+		if waited >= time.Second {
+			s.logger.Info("Запрос к OpenAI ждал в очереди лимитов", "priority", priority, "waited", waited)
+		}
+	}
 
 	// Подготавливаем запрос
 	request := OpenAIRequest{
 		Model:       model,
 		Messages:    messages,
-		Temperature: 0.7,
+		Temperature: temperature,
 		MaxTokens:   maxTokens,
 	}
 
@@ -71,14 +168,14 @@ func (s *Service) callOpenAI(messages []Message, cfg *config.Config) (string, er
 	}
 
 	// Создаем HTTP запрос
-	req, err := http.NewRequest("POST", openaiURL, bytes.NewBuffer(jsonData))
+	req, err := http.NewRequest("POST", s.endpoint.URL("chat/completions", model), bytes.NewBuffer(jsonData))
 	if err != nil {
 		return "", fmt.Errorf("ошибка создания запроса: %w", err)
 	}
 
 	// Устанавливаем заголовки
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.apiKey))
+	s.endpoint.Authorize(req, s.apiKey)
 
 	// Выполняем запрос
 	resp, err := s.client.Do(req)
@@ -95,7 +192,7 @@ func (s *Service) callOpenAI(messages []Message, cfg *config.Config) (string, er
 
 	// Проверяем статус код
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("HTTP ошибка %d: %s", resp.StatusCode, string(body))
+		return "", fmt.Errorf("HTTP ошибка: %w", &api.StatusError{StatusCode: resp.StatusCode, Body: string(body)})
 	}
 
 	// Парсим ответ
@@ -110,6 +207,10 @@ func (s *Service) callOpenAI(messages []Message, cfg *config.Config) (string, er
 		return "", fmt.Errorf("OpenAI API ошибка: %s", openaiResp.Error.Message)
 	}
 
+	if s.usage != nil {
+		s.usage.RecordUsage(model, openaiResp.Usage)
+	}
+
 	// Проверяем наличие ответа
 	if len(openaiResp.Choices) == 0 {
 		return "", fmt.Errorf("пустой ответ от OpenAI")
@@ -0,0 +1,22 @@
+package interviewer
+
+import (
+	"interview-bot-complete/internal/config"
+	"interview-bot-complete/internal/storage"
+)
+
+// PreviewQuestionPrompt возвращает промпт уточняющего вопроса блока без обращения к модели
+// (проверка шаблонов в cmd/validate-config)
+func (s *Service) PreviewQuestionPrompt(block config.Block, dialogue []storage.QA, previousSummaries []storage.BlockSummary, cfg *config.Config) string {
+	return s.buildQuestionPrompt(block, dialogue, previousSummaries, cfg)
+}
+
+// PreviewSummaryPrompt возвращает промпт саммари блока на языке шаблона без обращения к модели
+func (s *Service) PreviewSummaryPrompt(dialogue []storage.QA, cfg *config.Config) string {
+	return s.buildSummaryPrompt(dialogue, cfg.SummaryStructure.Sections(), cfg.Language())
+}
+
+// PreviewCoveragePrompt возвращает промпт проверки раскрытия целей блока без обращения к модели
+func (s *Service) PreviewCoveragePrompt(summary storage.BlockSummary, goals []string) string {
+	return buildCoveragePrompt(summary, goals)
+}
@@ -0,0 +1,61 @@
+package interviewer
+
+import (
+	"fmt"
+	"interview-bot-complete/internal/config"
+	"interview-bot-complete/internal/language"
+	"interview-bot-complete/internal/storage"
+	"strings"
+)
+
+// profileQAMarker - заголовок промпта вопросов по профилю (по нему mock-режим выбирает ответ)
+const profileQAMarker = "ПРОФИЛЬ ПОЛЬЗОВАТЕЛЯ"
+
+// AnswerProfileQuestion отвечает на вопрос пользователя о его собственном профиле.
+// history - предыдущие вопросы и ответы этого разговора для связности.
+func (s *Service) AnswerProfileQuestion(profileJSON string, history []storage.QA, question string, cfg *config.Config) (string, error) {
+	prompt := buildProfileQAPrompt(profileJSON, language.Detect(question))
+
+	messages := []Message{
+		{Role: "system", Content: prompt},
+	}
+	for _, qa := range history {
+		messages = append(messages,
+			Message{Role: "user", Content: qa.Question},
+			Message{Role: "assistant", Content: qa.Answer},
+		)
+	}
+	messages = append(messages, Message{Role: "user", Content: question})
+
+	answer, err := s.callOpenAI(messages, cfg, config.UseCaseProfileQA)
+	if err != nil {
+		return "", fmt.Errorf("ошибка ответа на вопрос о профиле: %w", err)
+	}
+
+	return strings.TrimSpace(answer), nil
+}
+
+// buildProfileQAPrompt создает системный промпт разговора о профиле
+func buildProfileQAPrompt(profileJSON string, lang string) string {
+	var prompt strings.Builder
+
+	prompt.WriteString("Ты карьерный консультант и психолог. Человек прошел интервью, по которому составлен его профиль.\n")
+	prompt.WriteString("Отвечай на его вопросы о себе, опираясь ТОЛЬКО на данные профиля.\n\n")
+
+	prompt.WriteString(profileQAMarker + " (JSON):\n")
+	prompt.WriteString(profileJSON)
+	prompt.WriteString("\n\n")
+
+	prompt.WriteString("ПРАВИЛА:\n")
+	prompt.WriteString("- Отвечай тепло и по делу, 3-6 предложений, без markdown-таблиц\n")
+	prompt.WriteString("- Ссылайся на конкретные факты из профиля\n")
+	prompt.WriteString("- Если данных недостаточно, честно скажи об этом и предложи, что можно уточнить\n")
+	prompt.WriteString("- Не ставь диагнозов и не давай медицинских, юридических или финансовых гарантий\n")
+	if lang == language.English {
+		prompt.WriteString("- Answer in English\n")
+	} else {
+		prompt.WriteString("- Отвечай на русском языке\n")
+	}
+
+	return prompt.String()
+}
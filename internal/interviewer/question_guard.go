@@ -0,0 +1,106 @@
+package interviewer
+
+import (
+	"errors"
+	"interview-bot-complete/internal/config"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrRejectedQuestion - модель вернула вопрос, который нельзя отправить пользователю
+var ErrRejectedQuestion = errors.New("модель вернула недопустимый вопрос")
+
+// questionAttempts - сколько раз генерируется вопрос, прежде чем он считается отклоненным
+const questionAttempts = 2
+
+// Допустимая длина сгенерированного вопроса в символах
+const (
+	minQuestionLength = 10
+	maxQuestionLength = 400
+)
+
+// leakedPromptRunes - столько первых символов инструкции из промпта в вопросе считается утечкой
+const leakedPromptRunes = 40
+
+// questionPrefixPattern - служебная подпись перед текстом вопроса, которую модель иногда добавляет
+var questionPrefixPattern = regexp.MustCompile(`(?i)^\s*(\*\*)?(вопрос|question)(\s*\d+)?\s*:\s*(\*\*)?\s*`)
+
+// promptMarkers - заголовки промпта вопроса и фразы о нем; в вопросе означают утечку инструкций
+var promptMarkers = []string{
+	"текущий блок:", "стратегия:", "текущий диалог в блоке", "твоя задача", "области фокуса",
+	"глубина ответов:", "тон и стиль вопросов", "психолог-интервьюер", "системный промпт", "system prompt",
+	"из прошлого интервью с этим человеком",
+}
+
+// metaMarkers - начала ответа, которыми модель комментирует задачу вместо вопроса
+var metaMarkers = []string{
+	"вот вопрос", "вот следующий вопрос", "следующий вопрос", "как ии", "как языковая модель",
+	"я не могу", "извините, но", "here is", "here's", "sure,", "as an ai", "as a language model", "i can't", "i cannot",
+}
+
+// unsafeMarkers - основы слов, недопустимых в вопросе интервьюера
+var unsafeMarkers = []string{
+	"суицид", "самоубий", "покончить с собой", "убить", "наркотик", "порн", "сексуальн", "интим",
+	"suicide", "kill yourself", "self-harm", "drugs", "porn", "sexual",
+}
+
+// cleanQuestion убирает пробелы, кавычки и подпись «Вопрос:», которые модель добавляет к тексту вопроса
+func cleanQuestion(question string) string {
+	question = strings.TrimSpace(question)
+	question = questionPrefixPattern.ReplaceAllString(question, "")
+	question = strings.Trim(question, "\"«»“” ")
+	return strings.TrimSpace(question)
+}
+
+// checkQuestion проверяет сгенерированный вопрос перед отправкой пользователю и возвращает
+// причину отказа (пусто - вопрос допустим): один вопрос, длина в пределах, без утечки промпта,
+// комментариев модели и недопустимых тем
+func (s *Service) checkQuestion(question string, block config.Block) string {
+	length := utf8.RuneCountInString(question)
+	if length < minQuestionLength {
+		return "too short"
+	}
+	if length > maxQuestionLength {
+		return "too long"
+	}
+	if strings.Count(question, "?") > 1 || strings.Contains(question, "\n\n") {
+		return "more than one question"
+	}
+
+	lower := strings.ToLower(question)
+	for _, marker := range metaMarkers {
+		if strings.HasPrefix(lower, marker) {
+			return "meta commentary"
+		}
+	}
+	for _, marker := range promptMarkers {
+		if strings.Contains(lower, marker) {
+			return "prompt leakage"
+		}
+	}
+	instructions := []string{block.ContextPrompt}
+	if s.persona != nil {
+		instructions = append(instructions, s.persona.SystemPrompt)
+	}
+	for _, instruction := range instructions {
+		if fragment := promptFragment(instruction); fragment != "" && strings.Contains(lower, fragment) {
+			return "prompt leakage"
+		}
+	}
+	for _, marker := range unsafeMarkers {
+		if strings.Contains(lower, marker) {
+			return "unsafe content"
+		}
+	}
+	return ""
+}
+
+// promptFragment возвращает начало инструкции в нижнем регистре (пусто - инструкция слишком коротка для проверки)
+func promptFragment(instruction string) string {
+	runes := []rune(strings.ToLower(strings.TrimSpace(instruction)))
+	if len(runes) < leakedPromptRunes {
+		return ""
+	}
+	return string(runes[:leakedPromptRunes])
+}
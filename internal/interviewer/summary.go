@@ -0,0 +1,189 @@
+package interviewer
+
+import (
+	"encoding/json"
+	"fmt"
+	"interview-bot-complete/internal/config"
+	"interview-bot-complete/internal/language"
+	"interview-bot-complete/internal/storage"
+	"strings"
+)
+
+// createSummary создает структурированное саммари блока по разделам summary_structure
+func (s *Service) createSummary(dialogue []storage.QA, cfg *config.Config) (*storage.BlockSummary, error) {
+	sections := cfg.SummaryStructure.Sections()
+	prompt := s.buildSummaryPrompt(dialogue, sections, language.Detect(dialogueAnswers(dialogue)))
+
+	messages := []Message{
+		{Role: "system", Content: prompt},
+	}
+
+	reply, model, err := s.complete(messages, cfg, config.UseCaseSummary)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка создания саммари: %w", err)
+	}
+
+	summary := parseSummary(reply, sections)
+	summary.Model = model
+	return summary, nil
+}
+
+// dialogueAnswers объединяет ответы пользователя для определения языка
+func dialogueAnswers(dialogue []storage.QA) string {
+	answers := make([]string, 0, len(dialogue))
+	for _, qa := range dialogue {
+		answers = append(answers, qa.Answer)
+	}
+	return strings.Join(answers, "\n")
+}
+
+// buildSummaryPrompt создает промпт для саммаризации на языке ответов пользователя
+func (s *Service) buildSummaryPrompt(dialogue []storage.QA, sections []config.SummarySection, lang string) string {
+	if lang == language.English {
+		return s.buildEnglishSummaryPrompt(dialogue, sections)
+	}
+
+	var prompt strings.Builder
+
+	prompt.WriteString("Ты опытный психолог-аналитик. Проанализируй прошедший блок интервью и создай структурированное саммари.\n\n")
+
+	prompt.WriteString("ВОПРОСЫ И ОТВЕТЫ:\n")
+	for i, qa := range dialogue {
+		prompt.WriteString(fmt.Sprintf("%d. Вопрос: %s\n", i+1, qa.Question))
+		prompt.WriteString(fmt.Sprintf("   Ответ: %s\n\n", qa.Answer))
+	}
+
+	prompt.WriteString("ЗАДАЧА: Извлечь максимум полезной информации для следующих блоков интервью.\n\n")
+
+	prompt.WriteString("СОЗДАЙ САММАРИ ПО РАЗДЕЛАМ:\n")
+	for _, section := range sections {
+		prompt.WriteString(fmt.Sprintf("- %s: %s", section.Key, section.Title))
+		if len(section.Hints) > 0 {
+			prompt.WriteString(fmt.Sprintf(" (обрати внимание: %s)", strings.Join(section.Hints, ", ")))
+		}
+		prompt.WriteString("\n")
+	}
+	prompt.WriteString("\n")
+
+	prompt.WriteString("ФОРМАТ ОТВЕТА: только JSON без markdown, где summary - краткое саммари в свободной форме,\n")
+	prompt.WriteString("а каждый раздел - массив коротких утверждений (пустой массив, если сведений нет):\n")
+	prompt.WriteString(summaryJSONExample(sections))
+	prompt.WriteString("\n\nВАЖНО: Будь конкретным, избегай общих фраз. Информация должна быть полезна для адаптации следующих блоков.")
+
+	return prompt.String()
+}
+
+// buildEnglishSummaryPrompt - вариант промпта саммаризации для англоязычных ответов
+func (s *Service) buildEnglishSummaryPrompt(dialogue []storage.QA, sections []config.SummarySection) string {
+	var prompt strings.Builder
+
+	prompt.WriteString("You are an experienced psychologist-analyst. Analyze the completed interview block and write a structured summary.\n\n")
+
+	prompt.WriteString("QUESTIONS AND ANSWERS:\n")
+	for i, qa := range dialogue {
+		prompt.WriteString(fmt.Sprintf("%d. Question: %s\n", i+1, qa.Question))
+		prompt.WriteString(fmt.Sprintf("   Answer: %s\n\n", qa.Answer))
+	}
+
+	prompt.WriteString("TASK: Extract as much useful information as possible for the next interview blocks.\n\n")
+
+	prompt.WriteString("WRITE A SUMMARY IN ENGLISH BY SECTIONS:\n")
+	for _, section := range sections {
+		prompt.WriteString(fmt.Sprintf("- %s: %s", section.Key, section.TitleEN))
+		if len(section.Hints) > 0 {
+			prompt.WriteString(fmt.Sprintf(" (look for: %s)", strings.Join(section.Hints, ", ")))
+		}
+		prompt.WriteString("\n")
+	}
+	prompt.WriteString("\n")
+
+	prompt.WriteString("RESPONSE FORMAT: JSON only, no markdown. summary is a short free-form summary,\n")
+	prompt.WriteString("each section is an array of short statements (empty array if nothing is known):\n")
+	prompt.WriteString(summaryJSONExample(sections))
+	prompt.WriteString("\n\nIMPORTANT: Be specific, avoid generic phrases. The information must help adapt the next blocks.")
+
+	return prompt.String()
+}
+
+// summaryJSONExample строит образец ответа со всеми разделами
+func summaryJSONExample(sections []config.SummarySection) string {
+	parts := []string{`"summary": "..."`}
+	for _, section := range sections {
+		parts = append(parts, fmt.Sprintf(`"%s": ["..."]`, section.Key))
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+// parseSummary разбирает ответ модели. Если ответ не JSON, он целиком сохраняется как текст саммари.
+func parseSummary(reply string, sections []config.SummarySection) *storage.BlockSummary {
+	reply = strings.TrimSpace(reply)
+	cleaned := strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(reply, "```json"), "```"), "```"))
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(cleaned), &raw); err != nil {
+		return &storage.BlockSummary{Text: reply}
+	}
+
+	summary := &storage.BlockSummary{}
+	if text, ok := raw["summary"]; ok {
+		json.Unmarshal(text, &summary.Text)
+	}
+
+	for _, section := range sections {
+		var items []string
+		if value, ok := raw[section.Key]; !ok || json.Unmarshal(value, &items) != nil {
+			continue
+		}
+		items = nonEmpty(items)
+		if len(items) == 0 {
+			continue
+		}
+		if summary.Fields == nil {
+			summary.Fields = make(map[string][]string)
+		}
+		summary.Fields[section.Key] = items
+	}
+
+	if summary.Text == "" && summary.Fields == nil {
+		return &storage.BlockSummary{Text: reply}
+	}
+	return summary
+}
+
+// nonEmpty убирает пустые строки из списка
+func nonEmpty(items []string) []string {
+	result := items[:0]
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// writePreviousSummaries добавляет в промпт саммари предыдущих блоков вместе с их разделами
+func writePreviousSummaries(prompt *strings.Builder, summaries []storage.BlockSummary, cfg *config.Config) {
+	if len(summaries) == 0 {
+		return
+	}
+
+	prompt.WriteString("КОНТЕКСТ ИЗ ПРЕДЫДУЩИХ БЛОКОВ:\n")
+	for i, summary := range summaries {
+		prompt.WriteString(fmt.Sprintf("Блок %d: %s\n", i+1, summary.Text))
+		for _, section := range cfg.SummaryStructure.Sections() {
+			if items := summary.Fields[section.Key]; len(items) > 0 {
+				prompt.WriteString(fmt.Sprintf("  %s: %s\n", section.Title, strings.Join(items, "; ")))
+			}
+		}
+	}
+	prompt.WriteString("\n")
+}
+
+// SummaryText объединяет текст и разделы саммари для поиска по ключевым словам
+func SummaryText(summary storage.BlockSummary) string {
+	parts := []string{summary.Text}
+	for _, items := range summary.Fields {
+		parts = append(parts, items...)
+	}
+	return strings.Join(parts, "\n")
+}
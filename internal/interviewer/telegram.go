@@ -3,12 +3,15 @@ package interviewer
 import (
 	"fmt"
 	"interview-bot-complete/internal/config"
+	"interview-bot-complete/internal/depth"
 	"interview-bot-complete/internal/storage"
 	"strings"
 )
 
-// GenerateQuestion генерирует следующий вопрос для текущего блока
-func (s *Service) GenerateQuestion(block config.Block, currentDialogue []storage.QA, previousSummaries []string, cfg *config.Config) (string, error) {
+// GenerateQuestion генерирует следующий вопрос для текущего блока.
+// Возвращает текст вопроса и модель, которая его сформировала; вопрос, не прошедший
+// проверку и после повторной генерации, возвращается как ErrRejectedQuestion.
+func (s *Service) GenerateQuestion(block config.Block, currentDialogue []storage.QA, previousSummaries []storage.BlockSummary, cfg *config.Config) (string, string, error) {
 	// Строим промпт для генерации вопроса
 	prompt := s.buildQuestionPrompt(block, currentDialogue, previousSummaries, cfg)
 
@@ -16,38 +19,54 @@ func (s *Service) GenerateQuestion(block config.Block, currentDialogue []storage
 		{Role: "system", Content: prompt},
 	}
 
-	question, err := s.callOpenAI(messages, cfg)
-	if err != nil {
-		return "", fmt.Errorf("ошибка генерации вопроса: %w", err)
+	// Вопрос с комментариями модели, утечкой промпта или недопустимым содержимым
+	// генерируется повторно один раз
+	reason := ""
+	for attempt := 1; attempt <= questionAttempts; attempt++ {
+		question, model, err := s.complete(messages, cfg, config.UseCaseQuestions)
+		if err != nil {
+			return "", "", fmt.Errorf("ошибка генерации вопроса: %w", err)
+		}
+		question = cleanQuestion(question)
+		if reason = s.checkQuestion(question, block); reason == "" {
+			return question, model, nil
+		}
+		s.logger.Warn("Сгенерированный вопрос отклонен", "reason", reason, "attempt", attempt, "model", model, "question", question)
 	}
-
-	return strings.TrimSpace(question), nil
+	return "", "", fmt.Errorf("%w: %s", ErrRejectedQuestion, reason)
 }
 
-// CreateSummary создает саммари блока (используется из telegram handler)
-func (s *Service) CreateSummary(dialogue []storage.QA, cfg *config.Config) (string, error) {
+// CreateSummary создает структурированное саммари блока (используется из telegram handler)
+func (s *Service) CreateSummary(dialogue []storage.QA, cfg *config.Config) (*storage.BlockSummary, error) {
 	return s.createSummary(dialogue, cfg)
 }
 
 // buildQuestionPrompt создает промпт для генерации одного вопроса
-func (s *Service) buildQuestionPrompt(block config.Block, currentDialogue []storage.QA, previousSummaries []string, cfg *config.Config) string {
+func (s *Service) buildQuestionPrompt(block config.Block, currentDialogue []storage.QA, previousSummaries []storage.BlockSummary, cfg *config.Config) string {
 	var prompt strings.Builder
 
-	// Базовая роль
-	prompt.WriteString("Ты опытный психолог-интервьюер с 15-летним стажем, работающий через Telegram бот.\n\n")
+	// Роль интервьюера: персона или базовая роль
+	if s.persona != nil {
+		prompt.WriteString(s.persona.SystemPrompt + "\n\n")
+		if len(s.persona.Tone) > 0 {
+			prompt.WriteString("ТОН И СТИЛЬ ВОПРОСОВ:\n")
+			for _, tone := range s.persona.Tone {
+				prompt.WriteString(fmt.Sprintf("- %s\n", tone))
+			}
+			prompt.WriteString("\n")
+		}
+	} else {
+		prompt.WriteString("Ты опытный психолог-интервьюер с 15-летним стажем, работающий через Telegram бот.\n\n")
+	}
 
 	// Контекст блока
 	prompt.WriteString(fmt.Sprintf("ТЕКУЩИЙ БЛОК: \"%s\" (%d/%d)\n", block.Title, block.ID, cfg.GetTotalBlocks()))
 	prompt.WriteString(fmt.Sprintf("СТРАТЕГИЯ: %s\n\n", block.ContextPrompt))
 
 	// Контекст из предыдущих блоков
-	if len(previousSummaries) > 0 {
-		prompt.WriteString("КОНТЕКСТ ИЗ ПРЕДЫДУЩИХ БЛОКОВ:\n")
-		for i, summary := range previousSummaries {
-			prompt.WriteString(fmt.Sprintf("Блок %d: %s\n", i+1, summary))
-		}
-		prompt.WriteString("\n")
-	}
+	writePreviousSummaries(&prompt, previousSummaries, cfg)
+	s.writeMemory(&prompt)
+	s.writeResumeFacts(&prompt)
 
 	// Текущий диалог в блоке
 	if len(currentDialogue) > 0 {
@@ -60,6 +79,9 @@ func (s *Service) buildQuestionPrompt(block config.Block, currentDialogue []stor
 		}
 	}
 
+	// Сложность вопроса по глубине ответов
+	writeDepthGuidance(&prompt, currentDialogue)
+
 	// Инструкции
 	maxQuestions := cfg.GetQuestionsPerBlock() + cfg.GetMaxFollowupQuestions()
 	currentQuestionNum := len(currentDialogue) + 1
@@ -69,6 +91,9 @@ func (s *Service) buildQuestionPrompt(block config.Block, currentDialogue []stor
 
 	if currentQuestionNum <= cfg.GetQuestionsPerBlock() {
 		prompt.WriteString("- Это базовый вопрос - должен покрывать ключевые области блока\n")
+	} else if len(block.CoverageGoals) > 0 {
+		// Движок передает в областях фокуса только нераскрытые цели блока
+		prompt.WriteString("- Это уточняющий вопрос - спроси о теме из областей фокуса, которую человек еще не раскрыл\n")
 	} else {
 		prompt.WriteString("- Это уточняющий вопрос - углубись в интересные детали из предыдущих ответов\n")
 	}
@@ -96,3 +121,23 @@ func (s *Service) buildQuestionPrompt(block config.Block, currentDialogue []stor
 
 	return prompt.String()
 }
+
+// depthGuidance - указания к сложности вопроса для уровней глубины ответов (для обычных ответов не нужны)
+var depthGuidance = map[depth.Level]string{
+	depth.LevelShallow: "Собеседник отвечает коротко и в общих словах. Задай простой конкретный вопрос о фактах " +
+		"или недавнем случае из жизни, на который легко ответить. Избегай абстрактных и составных вопросов.",
+	depth.LevelDeep: "Собеседник отвечает развернуто и размышляет. Задай более глубокий вопрос: о мотивах, " +
+		"противоречиях, выводах и о том, как этот опыт изменил его взгляды.",
+}
+
+// writeDepthGuidance добавляет в промпт указания к сложности вопроса по оценкам ответов блока
+func writeDepthGuidance(prompt *strings.Builder, currentDialogue []storage.QA) {
+	scores := make([]float64, 0, len(currentDialogue))
+	for _, qa := range currentDialogue {
+		scores = append(scores, qa.DepthScore)
+	}
+	if guidance, ok := depthGuidance[depth.Assess(scores)]; ok {
+		prompt.WriteString("ГЛУБИНА ОТВЕТОВ:\n")
+		prompt.WriteString(guidance + "\n\n")
+	}
+}
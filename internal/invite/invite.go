@@ -0,0 +1,159 @@
+package invite
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Источники приглашения
+const (
+	SourceList = "list" // токен из списка приглашений
+	SourceHMAC = "hmac" // ссылка, подписанная секретом
+)
+
+// maxPayloadLength - ограничение Telegram на параметр deep link /start
+const maxPayloadLength = 64
+
+// signatureLength - длина подписи в шестнадцатеричных символах
+const signatureLength = 16
+
+// ErrInvalid - приглашение не найдено, подделано или истекло
+var ErrInvalid = errors.New("приглашение недействительно")
+
+// partPattern - допустимые символы ID шаблона и ID приглашения в подписанной ссылке
+var partPattern = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// Entry - приглашение из списка
+type Entry struct {
+	Token     string     `yaml:"token"`
+	Template  string     `yaml:"template"`
+	Candidate string     `yaml:"candidate,omitempty"`
+	ExpiresAt *time.Time `yaml:"expires_at,omitempty"`
+	// Tenant - арендатор, к которому относится интервью по приглашению
+	Tenant string `yaml:"tenant,omitempty"`
+}
+
+// Invitation - проверенное приглашение, по которому начинается интервью
+type Invitation struct {
+	Token      string
+	TemplateID string
+	Candidate  string
+	Source     string
+	Tenant     string
+}
+
+// Validator проверяет приглашения по списку и по HMAC подписи
+type Validator struct {
+	entries map[string]Entry
+	secret  []byte
+}
+
+// NewValidator создает валидатор; пустой secret отключает подписанные ссылки
+func NewValidator(entries []Entry, secret string) (*Validator, error) {
+	v := &Validator{entries: make(map[string]Entry), secret: []byte(secret)}
+	for _, entry := range entries {
+		if entry.Token == "" {
+			return nil, fmt.Errorf("у приглашения не указан token")
+		}
+		if strings.Contains(entry.Token, "-") || len(entry.Token) > maxPayloadLength {
+			return nil, fmt.Errorf("токен %q: допустимы до %d символов без '-'", entry.Token, maxPayloadLength)
+		}
+		if _, exists := v.entries[entry.Token]; exists {
+			return nil, fmt.Errorf("токен %q объявлен повторно", entry.Token)
+		}
+		v.entries[entry.Token] = entry
+	}
+	return v, nil
+}
+
+// Load читает список приглашений из YAML файла. Отсутствующий файл не считается ошибкой.
+func Load(filename, secret string) (*Validator, error) {
+	var file struct {
+		Invites []Entry `yaml:"invites"`
+	}
+
+	data, err := os.ReadFile(filename)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("ошибка чтения файла %s: %w", filename, err)
+	}
+	if err == nil {
+		if err := yaml.Unmarshal(data, &file); err != nil {
+			return nil, fmt.Errorf("ошибка парсинга %s: %w", filename, err)
+		}
+	}
+
+	return NewValidator(file.Invites, secret)
+}
+
+// Enabled сообщает, настроен ли хотя бы один способ проверки приглашений
+func (v *Validator) Enabled() bool {
+	return len(v.entries) > 0 || len(v.secret) > 0
+}
+
+// SigningEnabled сообщает, можно ли выпускать подписанные ссылки
+func (v *Validator) SigningEnabled() bool {
+	return len(v.secret) > 0
+}
+
+// Resolve проверяет параметр deep link. Подписанная ссылка имеет вид
+// <template>-<invite_id>-<signature>, иначе параметр ищется в списке приглашений.
+func (v *Validator) Resolve(payload string, now time.Time) (*Invitation, error) {
+	if parts := strings.Split(payload, "-"); len(parts) == 3 {
+		if !v.SigningEnabled() || !hmac.Equal([]byte(v.sign(parts[0], parts[1])), []byte(parts[2])) {
+			return nil, ErrInvalid
+		}
+		return &Invitation{Token: parts[1], TemplateID: parts[0], Source: SourceHMAC}, nil
+	}
+
+	entry, ok := v.entries[payload]
+	if !ok {
+		return nil, ErrInvalid
+	}
+	if entry.ExpiresAt != nil && now.After(*entry.ExpiresAt) {
+		return nil, fmt.Errorf("%w: срок действия истек %s", ErrInvalid, entry.ExpiresAt.Format("02.01.2006"))
+	}
+
+	return &Invitation{
+		Token:      entry.Token,
+		TemplateID: entry.Template,
+		Candidate:  entry.Candidate,
+		Source:     SourceList,
+		Tenant:     entry.Tenant,
+	}, nil
+}
+
+// Sign выпускает подписанный параметр deep link для шаблона и ID приглашения
+func (v *Validator) Sign(templateID, inviteID string) (string, error) {
+	if !v.SigningEnabled() {
+		return "", fmt.Errorf("секрет для подписи приглашений не задан")
+	}
+	if !partPattern.MatchString(templateID) || !partPattern.MatchString(inviteID) {
+		return "", fmt.Errorf("ID шаблона и приглашения могут содержать только латинские буквы, цифры и _")
+	}
+
+	payload := templateID + "-" + inviteID + "-" + v.sign(templateID, inviteID)
+	if len(payload) > maxPayloadLength {
+		return "", fmt.Errorf("приглашение длиннее %d символов, сократите ID", maxPayloadLength)
+	}
+	return payload, nil
+}
+
+func (v *Validator) sign(templateID, inviteID string) string {
+	mac := hmac.New(sha256.New, v.secret)
+	mac.Write([]byte(templateID + "-" + inviteID))
+	return hex.EncodeToString(mac.Sum(nil))[:signatureLength]
+}
+
+// Link строит ссылку t.me, открывающую бота с параметром /start
+func Link(botUsername, payload string) string {
+	return fmt.Sprintf("https://t.me/%s?start=%s", botUsername, payload)
+}
@@ -0,0 +1,308 @@
+package jsonrepair
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Способы, которыми получен валидный JSON
+const (
+	MethodStrict   = "strict"   // ответ валиден как есть (после снятия markdown и текста вокруг)
+	MethodTolerant = "tolerant" // исправлены висячие запятые, комментарии, кавычки и ключи без кавычек
+	MethodBalanced = "balanced" // обрезанный ответ закрыт по последнему целому значению
+	MethodLLM      = "llm"      // JSON исправила модель (запрос делает вызывающая сторона)
+	MethodFailed   = "failed"   // JSON восстановить не удалось
+)
+
+// ErrUnrepairable - JSON не удалось восстановить локально
+var ErrUnrepairable = errors.New("не удалось восстановить JSON")
+
+// Repair возвращает валидный JSON из ответа модели и способ, которым он получен.
+// Сначала ответ проверяется как есть, затем разбирается терпимым парсером (в духе JSON5),
+// затем обрезанный ответ закрывается по последнему целому значению.
+func Repair(content string) (string, string, error) {
+	text := trimToValue(content)
+	if json.Valid([]byte(text)) {
+		return text, MethodStrict, nil
+	}
+
+	tolerant := normalize(text)
+	if json.Valid([]byte(tolerant)) {
+		return tolerant, MethodTolerant, nil
+	}
+
+	if balanced, ok := balance(tolerant); ok && json.Valid([]byte(balanced)) {
+		return balanced, MethodBalanced, nil
+	}
+
+	var syntaxErr error = ErrUnrepairable
+	if err := json.Unmarshal([]byte(text), new(interface{})); err != nil {
+		syntaxErr = fmt.Errorf("%w: %v", ErrUnrepairable, err)
+	}
+	return "", "", syntaxErr
+}
+
+// trimToValue отбрасывает markdown и текст перед первой { или [
+func trimToValue(content string) string {
+	text := strings.TrimSpace(content)
+	text = strings.TrimPrefix(text, "```json")
+	text = strings.TrimPrefix(text, "```")
+	text = strings.TrimSuffix(strings.TrimSpace(text), "```")
+	if start := strings.IndexAny(text, "{["); start > 0 {
+		text = text[start:]
+	}
+	return strings.TrimSpace(text)
+}
+
+// normalize переписывает JSON5-подобный текст в JSON: удаляет комментарии и висячие запятые,
+// переводит строки в одинарных кавычках в двойные, берет в кавычки ключи-идентификаторы,
+// заменяет True/False/None и отбрасывает текст после закрытия верхнего значения.
+// Незакрытая строка в конце текста остается незакрытой - ее закрывает balance.
+func normalize(text string) string {
+	var out strings.Builder
+	depth := 0
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch {
+		case c == '"' || c == '\'':
+			str, n := readString(text[i:])
+			out.WriteString(str)
+			i += n
+		case c == '/' && i+1 < len(text) && (text[i+1] == '/' || text[i+1] == '*'):
+			i += skipComment(text[i:])
+		case c == ',':
+			next := skipSpace(text, i+1)
+			if next >= len(text) || text[next] == '}' || text[next] == ']' {
+				i++
+				continue
+			}
+			out.WriteByte(c)
+			i++
+		case c == '{' || c == '[':
+			depth++
+			out.WriteByte(c)
+			i++
+		case c == '}' || c == ']':
+			depth--
+			out.WriteByte(c)
+			i++
+			if depth <= 0 {
+				return out.String()
+			}
+		case isIdentStart(c):
+			j := i
+			for j < len(text) && isIdentPart(text[j]) {
+				j++
+			}
+			word := text[i:j]
+			if next := skipSpace(text, j); next < len(text) && text[next] == ':' {
+				out.WriteString(`"` + word + `"`)
+			} else {
+				out.WriteString(literal(word))
+			}
+			i = j
+		default:
+			out.WriteByte(c)
+			i++
+		}
+	}
+	return out.String()
+}
+
+// readString читает строку в одинарных или двойных кавычках и возвращает ее в двойных кавычках
+// с экранированными управляющими символами; незакрытая строка возвращается без закрывающей кавычки
+func readString(text string) (string, int) {
+	quote := text[0]
+	var out strings.Builder
+	out.WriteByte('"')
+	i := 1
+	for i < len(text) {
+		c := text[i]
+		switch {
+		case c == '\\' && i+1 < len(text):
+			next := text[i+1]
+			if next == '\'' {
+				out.WriteByte('\'')
+			} else {
+				out.WriteByte(c)
+				out.WriteByte(next)
+			}
+			i += 2
+			continue
+		case c == '\\':
+			// Обрезано посреди escape-последовательности
+			return out.String(), len(text)
+		case c == quote:
+			out.WriteByte('"')
+			return out.String(), i + 1
+		case c == '"':
+			out.WriteString(`\"`)
+		case c == '\n':
+			out.WriteString(`\n`)
+		case c == '\r':
+			out.WriteString(`\r`)
+		case c == '\t':
+			out.WriteString(`\t`)
+		default:
+			out.WriteByte(c)
+		}
+		i++
+	}
+	return out.String(), len(text)
+}
+
+// skipComment возвращает длину комментария // или /* */ в начале текста
+func skipComment(text string) int {
+	if strings.HasPrefix(text, "//") {
+		if end := strings.IndexByte(text, '\n'); end >= 0 {
+			return end
+		}
+		return len(text)
+	}
+	if end := strings.Index(text[2:], "*/"); end >= 0 {
+		return end + 4
+	}
+	return len(text)
+}
+
+func skipSpace(text string, i int) int {
+	for i < len(text) {
+		switch {
+		case text[i] == ' ' || text[i] == '\n' || text[i] == '\r' || text[i] == '\t':
+			i++
+		case strings.HasPrefix(text[i:], "//") || strings.HasPrefix(text[i:], "/*"):
+			i += skipComment(text[i:])
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+// literal переводит литералы Python и JavaScript в JSON
+func literal(word string) string {
+	switch word {
+	case "True":
+		return "true"
+	case "False":
+		return "false"
+	case "None", "undefined", "NaN", "Infinity":
+		return "null"
+	default:
+		return word
+	}
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || (c >= '0' && c <= '9')
+}
+
+// cut - место, где текст можно обрезать, и незакрытые скобки в этом месте
+type cut struct {
+	pos   int
+	stack string
+}
+
+// balance закрывает обрезанный JSON: текст обрезается по последнему целому значению
+// (незаконченная строка-значение дописывается кавычкой), затем закрываются открытые скобки
+func balance(text string) (string, bool) {
+	var stack []byte
+	var last *cut
+	// expectKey - в объекте ожидается ключ, следующая строка - ключ, а не значение
+	expectKey := false
+	mark := func(pos int) {
+		last = &cut{pos: pos, stack: string(stack)}
+	}
+
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch {
+		case c == '"':
+			end := stringEnd(text, i)
+			if end < 0 {
+				if expectKey {
+					return closeAt(text, last)
+				}
+				// Строка-значение обрезана: закрываем ее
+				value := text[i:]
+				if strings.HasSuffix(value, `\`) {
+					value = value[:len(value)-1]
+				}
+				return closeAt(text[:i]+value+`"`, &cut{pos: i + len(value) + 1, stack: string(stack)})
+			}
+			i = end + 1
+			if expectKey {
+				expectKey = false
+				continue
+			}
+			mark(i)
+		case c == '{' || c == '[':
+			stack = append(stack, c)
+			expectKey = c == '{'
+			i++
+			mark(i)
+		case c == '}' || c == ']':
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+			i++
+			mark(i)
+		case c == ',':
+			expectKey = len(stack) > 0 && stack[len(stack)-1] == '{'
+			i++
+		case c == ':':
+			i++
+		case c == ' ' || c == '\n' || c == '\r' || c == '\t':
+			i++
+		default:
+			// Число или литерал: целое, только если за ним идет разделитель
+			j := i
+			for j < len(text) && !strings.ContainsRune(",}] \n\r\t", rune(text[j])) {
+				j++
+			}
+			if j < len(text) || json.Valid([]byte(text[i:j])) {
+				i = j
+				mark(i)
+				continue
+			}
+			i = j
+		}
+	}
+	return closeAt(text, last)
+}
+
+// stringEnd возвращает позицию закрывающей кавычки строки, начинающейся в start (-1 - строка не закрыта)
+func stringEnd(text string, start int) int {
+	for i := start + 1; i < len(text); i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+// closeAt обрезает текст по месту c и закрывает скобки, открытые к этому месту
+func closeAt(text string, c *cut) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	var out strings.Builder
+	out.WriteString(strings.TrimRight(text[:c.pos], " \n\r\t,"))
+	for i := len(c.stack) - 1; i >= 0; i-- {
+		if c.stack[i] == '{' {
+			out.WriteByte('}')
+		} else {
+			out.WriteByte(']')
+		}
+	}
+	return out.String(), true
+}
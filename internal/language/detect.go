@@ -0,0 +1,102 @@
+package language
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Коды поддерживаемых языков
+const (
+	Russian = "ru"
+	English = "en"
+)
+
+// Default - язык, используемый при недостатке данных для определения
+const Default = Russian
+
+// minLetters - минимальное число букв для уверенного определения
+const minLetters = 20
+
+// stopwords - частотные слова, помогающие различать языки с одинаковой письменностью
+var stopwords = map[string][]string{
+	English: {"the", "and", "is", "are", "i", "to", "of", "in", "my", "it", "that", "with", "for", "was", "have", "you", "this", "on", "be", "at"},
+	Russian: {"и", "в", "не", "на", "я", "что", "с", "это", "как", "мне", "по", "но", "у", "меня", "к", "так", "все", "для", "за", "от"},
+}
+
+// Confident сообщает, достаточно ли в тексте букв, чтобы Detect определил язык, а не вернул Default
+func Confident(text string) bool {
+	letters := 0
+	for _, r := range text {
+		if unicode.Is(unicode.Cyrillic, r) || unicode.Is(unicode.Latin, r) {
+			letters++
+			if letters >= minLetters {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Detect определяет язык текста по соотношению алфавитов и частотным словам.
+// При недостатке данных возвращает Default.
+func Detect(text string) string {
+	cyrillic, latin := 0, 0
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Cyrillic, r):
+			cyrillic++
+		case unicode.Is(unicode.Latin, r):
+			latin++
+		}
+	}
+
+	if cyrillic+latin < minLetters {
+		return Default
+	}
+
+	// Текст в одной письменности определяется сразу
+	if cyrillic == 0 {
+		return English
+	}
+	if latin == 0 || cyrillic > latin*3 {
+		return Russian
+	}
+
+	// Смешанный текст (термины, названия технологий) - сравниваем частотные слова.
+	// Латиница не решает сама по себе: русские ответы часто состоят из названий технологий.
+	scores := make(map[string]int, len(stopwords))
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	}) {
+		for lang, words := range stopwords {
+			for _, stopword := range words {
+				if word == stopword {
+					scores[lang]++
+				}
+			}
+		}
+	}
+
+	if scores[English] > scores[Russian] {
+		return English
+	}
+	if scores[Russian] > scores[English] {
+		return Russian
+	}
+	if latin > cyrillic {
+		return English
+	}
+	return Russian
+}
+
+// Name возвращает название языка для промптов и сообщений
+func Name(code string) string {
+	switch code {
+	case English:
+		return "English"
+	case Russian:
+		return "русский"
+	default:
+		return code
+	}
+}
@@ -0,0 +1,60 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// Ключи атрибутов, по которым записи одного интервью связываются между собой
+const (
+	KeyInterviewID = "interview_id"
+	KeyUserID      = "user_id"
+	KeyChatID      = "chat_id"
+	KeyBlock       = "block"
+	// KeyErrorCode - код ошибки, который пользователь видит в сообщении и может назвать поддержке
+	KeyErrorCode = "error_code"
+)
+
+// Форматы вывода логов
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// New создает логгер с уровнем level (debug, info, warn, error) и форматом format (text, json)
+func New(w io.Writer, level, format string) (*slog.Logger, error) {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("неизвестный уровень логирования %q (debug, info, warn, error)", level)
+	}
+
+	opts := &slog.HandlerOptions{Level: lvl}
+	switch strings.ToLower(format) {
+	case FormatText, "":
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	case FormatJSON:
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	default:
+		return nil, fmt.Errorf("неизвестный формат логов %q (text, json)", format)
+	}
+}
+
+// Interview добавляет к логгеру атрибуты интервью; нулевые значения пропускаются
+func Interview(logger *slog.Logger, interviewID string, userID int64, block int) *slog.Logger {
+	var attrs []any
+	if interviewID != "" {
+		attrs = append(attrs, KeyInterviewID, interviewID)
+	}
+	if userID != 0 {
+		attrs = append(attrs, KeyUserID, userID)
+	}
+	if block > 0 {
+		attrs = append(attrs, KeyBlock, block)
+	}
+	if len(attrs) == 0 {
+		return logger
+	}
+	return logger.With(attrs...)
+}
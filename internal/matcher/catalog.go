@@ -0,0 +1,207 @@
+package matcher
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// idPattern - допустимые ID каталогов и архетипов
+var idPattern = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// langPattern - код языка перевода каталога (ISO 639-1)
+var langPattern = regexp.MustCompile(`^[a-z]{2}$`)
+
+// Catalog - каталог архетипов; ID каталога - имя файла без расширения
+type Catalog struct {
+	ID          string      `yaml:"-"`
+	Name        string      `yaml:"name"`
+	Description string      `yaml:"description,omitempty"`
+	Archetypes  []Archetype `yaml:"archetypes"`
+	// Translations - названия и описания каталога на других языках (ключ - код языка);
+	// непереведенные поля остаются на языке каталога
+	Translations map[string]CatalogTranslation `yaml:"translations,omitempty"`
+}
+
+// CatalogTranslation - перевод каталога; Archetypes - переводы архетипов по ID
+type CatalogTranslation struct {
+	Name        string                          `yaml:"name,omitempty"`
+	Description string                          `yaml:"description,omitempty"`
+	Archetypes  map[string]ArchetypeTranslation `yaml:"archetypes,omitempty"`
+}
+
+// ArchetypeTranslation - перевод архетипа
+type ArchetypeTranslation struct {
+	Name        string   `yaml:"name,omitempty"`
+	Description string   `yaml:"description,omitempty"`
+	Hints       []string `yaml:"hints,omitempty"`
+}
+
+// Archetype - архетип каталога с подсказками для сопоставления
+type Archetype struct {
+	ID          string   `yaml:"id"`
+	Name        string   `yaml:"name"`
+	Description string   `yaml:"description"`
+	Hints       []string `yaml:"hints,omitempty"`
+}
+
+// Find возвращает архетип каталога по ID
+func (c *Catalog) Find(id string) (*Archetype, bool) {
+	for i := range c.Archetypes {
+		if c.Archetypes[i].ID == id {
+			return &c.Archetypes[i], true
+		}
+	}
+	return nil, false
+}
+
+// Localized возвращает каталог с названиями и описаниями на языке lang; без перевода - сам каталог
+func (c *Catalog) Localized(lang string) *Catalog {
+	translation, ok := c.Translations[lang]
+	if !ok {
+		return c
+	}
+	localized := *c
+	localized.Translations = nil
+	if translation.Name != "" {
+		localized.Name = translation.Name
+	}
+	if translation.Description != "" {
+		localized.Description = translation.Description
+	}
+	localized.Archetypes = make([]Archetype, len(c.Archetypes))
+	for i, archetype := range c.Archetypes {
+		if t, ok := translation.Archetypes[archetype.ID]; ok {
+			if t.Name != "" {
+				archetype.Name = t.Name
+			}
+			if t.Description != "" {
+				archetype.Description = t.Description
+			}
+			if len(t.Hints) > 0 {
+				archetype.Hints = t.Hints
+			}
+		}
+		localized.Archetypes[i] = archetype
+	}
+	return &localized
+}
+
+// Catalogs - загруженные каталоги архетипов
+type Catalogs struct {
+	catalogs  map[string]*Catalog
+	defaultID string
+}
+
+// LoadCatalog загружает и проверяет каталог из YAML файла
+func LoadCatalog(filename string) (*Catalog, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения файла %s: %w", filename, err)
+	}
+
+	var catalog Catalog
+	if err := yaml.Unmarshal(data, &catalog); err != nil {
+		return nil, fmt.Errorf("ошибка парсинга YAML: %w", err)
+	}
+	catalog.ID = strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+
+	if err := validateCatalog(&catalog); err != nil {
+		return nil, fmt.Errorf("каталог %s: %w", catalog.ID, err)
+	}
+	return &catalog, nil
+}
+
+// validateCatalog проверяет ID, названия и уникальность архетипов
+func validateCatalog(catalog *Catalog) error {
+	if !idPattern.MatchString(catalog.ID) {
+		return fmt.Errorf("недопустимый ID каталога %q: разрешены строчные латинские буквы, цифры и _", catalog.ID)
+	}
+	if catalog.Name == "" {
+		return fmt.Errorf("каталог должен иметь name")
+	}
+	if len(catalog.Archetypes) == 0 {
+		return fmt.Errorf("archetypes не должны быть пустыми")
+	}
+
+	seen := make(map[string]bool, len(catalog.Archetypes))
+	for i, archetype := range catalog.Archetypes {
+		if !idPattern.MatchString(archetype.ID) {
+			return fmt.Errorf("архетип %d: недопустимый ID %q", i+1, archetype.ID)
+		}
+		if seen[archetype.ID] {
+			return fmt.Errorf("архетип %q объявлен повторно", archetype.ID)
+		}
+		seen[archetype.ID] = true
+		if archetype.Name == "" || archetype.Description == "" {
+			return fmt.Errorf("архетип %q должен иметь name и description", archetype.ID)
+		}
+	}
+
+	for lang, translation := range catalog.Translations {
+		if !langPattern.MatchString(lang) {
+			return fmt.Errorf("перевод %q: язык должен быть двухбуквенным кодом", lang)
+		}
+		for id := range translation.Archetypes {
+			if !seen[id] {
+				return fmt.Errorf("перевод %s: архетип %q отсутствует в каталоге", lang, id)
+			}
+		}
+	}
+	return nil
+}
+
+// LoadCatalogs загружает все каталоги *.yaml из директории. defaultID - каталог,
+// используемый, когда шаблон интервью не задает свой
+func LoadCatalogs(dir, defaultID string) (*Catalogs, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка поиска каталогов архетипов в %s: %w", dir, err)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("в %s нет каталогов архетипов", dir)
+	}
+
+	catalogs := &Catalogs{catalogs: make(map[string]*Catalog, len(files)), defaultID: defaultID}
+	for _, file := range files {
+		catalog, err := LoadCatalog(file)
+		if err != nil {
+			return nil, err
+		}
+		catalogs.catalogs[catalog.ID] = catalog
+	}
+
+	if _, ok := catalogs.catalogs[defaultID]; !ok {
+		return nil, fmt.Errorf("каталог архетипов по умолчанию %q не найден в %s", defaultID, dir)
+	}
+	return catalogs, nil
+}
+
+// Get возвращает каталог по ID; пустой ID означает каталог по умолчанию
+func (c *Catalogs) Get(id string) (*Catalog, bool) {
+	if id == "" {
+		id = c.defaultID
+	}
+	catalog, ok := c.catalogs[id]
+	return catalog, ok
+}
+
+// Default возвращает каталог по умолчанию
+func (c *Catalogs) Default() *Catalog {
+	return c.catalogs[c.defaultID]
+}
+
+// IDs возвращает отсортированный список ID каталогов
+func (c *Catalogs) IDs() []string {
+	ids := make([]string, 0, len(c.catalogs))
+	for id := range c.catalogs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
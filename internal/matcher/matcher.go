@@ -0,0 +1,117 @@
+package matcher
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"interview-bot-complete/internal/api"
+	"interview-bot-complete/internal/prompts"
+	"strings"
+)
+
+// ErrUnknownArchetype - модель вернула архетип, которого нет в каталоге
+var ErrUnknownArchetype = errors.New("модель выбрала архетип вне каталога")
+
+// Completer - клиент модели, выполняющий запрос с JSON ответом
+type Completer interface {
+	ExtractProfileWithOptions(prompt string, opts api.CompletionOptions) (*api.Completion, error)
+}
+
+// Matcher подбирает архетип из каталога по профилю и ответам интервью
+type Matcher struct {
+	client   Completer
+	catalogs *Catalogs
+}
+
+// Input - данные интервью для подбора архетипа
+type Input struct {
+	ProfileJSON string
+	Answers     string
+	Language    string
+}
+
+// Result - выбранный архетип с персональным разбором
+type Result struct {
+	CatalogID   string
+	CatalogName string
+	ArchetypeID string
+	// Name берется из каталога, а не из ответа модели
+	Name        string
+	Description string
+	Strengths   []string
+	BlindSpots  []string
+	Growth      []string
+}
+
+// matchResponse - ответ модели на промпт подбора архетипа
+type matchResponse struct {
+	ArchetypeID string   `json:"archetype_id"`
+	Description string   `json:"description"`
+	Strengths   []string `json:"strengths"`
+	BlindSpots  []string `json:"blind_spots"`
+	Growth      []string `json:"growth"`
+}
+
+// New создает подбор архетипов по загруженным каталогам
+func New(client Completer, catalogs *Catalogs) *Matcher {
+	return &Matcher{client: client, catalogs: catalogs}
+}
+
+// Catalogs возвращает каталоги архетипов
+func (m *Matcher) Catalogs() *Catalogs {
+	return m.catalogs
+}
+
+// Match выбирает архетип из каталога catalogID (пустой - каталог по умолчанию) одним запросом к модели
+func (m *Matcher) Match(catalogID string, input Input, opts api.CompletionOptions) (*Result, *api.Completion, error) {
+	catalog, ok := m.catalogs.Get(catalogID)
+	if !ok {
+		return nil, nil, fmt.Errorf("неизвестный каталог архетипов %q", catalogID)
+	}
+	// Названия и описания архетипов - на языке интервью, если у каталога есть перевод
+	catalog = catalog.Localized(input.Language)
+
+	prompt := prompts.GenerateArchetypeMatchPrompt(catalog.Name, describeArchetypes(catalog), input.ProfileJSON, input.Answers, input.Language)
+	completion, err := m.client.ExtractProfileWithOptions(prompt, opts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("ошибка подбора архетипа: %w", err)
+	}
+
+	var response matchResponse
+	if err := json.Unmarshal([]byte(completion.Content), &response); err != nil {
+		return nil, nil, fmt.Errorf("ошибка парсинга архетипа: %w", err)
+	}
+	archetype, ok := catalog.Find(strings.ToLower(strings.TrimSpace(response.ArchetypeID)))
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %q (каталог %s)", ErrUnknownArchetype, response.ArchetypeID, catalog.ID)
+	}
+
+	// Без персонального разбора показываем описание архетипа из каталога
+	description := response.Description
+	if description == "" {
+		description = archetype.Description
+	}
+	return &Result{
+		CatalogID:   catalog.ID,
+		CatalogName: catalog.Name,
+		ArchetypeID: archetype.ID,
+		Name:        archetype.Name,
+		Description: description,
+		Strengths:   response.Strengths,
+		BlindSpots:  response.BlindSpots,
+		Growth:      response.Growth,
+	}, completion, nil
+}
+
+// describeArchetypes перечисляет архетипы каталога для промпта: [id] название - описание; признаки
+func describeArchetypes(catalog *Catalog) string {
+	var text strings.Builder
+	for _, archetype := range catalog.Archetypes {
+		text.WriteString(fmt.Sprintf("[%s] %s - %s", archetype.ID, archetype.Name, archetype.Description))
+		if len(archetype.Hints) > 0 {
+			text.WriteString("; " + strings.Join(archetype.Hints, ", "))
+		}
+		text.WriteString("\n")
+	}
+	return strings.TrimRight(text.String(), "\n")
+}
@@ -0,0 +1,26 @@
+package metrics
+
+import (
+	"errors"
+	"interview-bot-complete/internal/events"
+)
+
+// HandleEvent учитывает события интервью в счетчиках арендаторов и профилей
+func (r *Registry) HandleEvent(event events.Event) {
+	switch event.Kind {
+	case events.InterviewStarted:
+		r.TenantInterviewStarted(event.Tenant)
+	case events.InterviewCompleted:
+		r.TenantInterviewCompleted(event.Tenant)
+	case events.ProfileExtracted:
+		r.ProfileGenerated(event.Model, event.PromptTokens, event.CompletionTokens, event.CostUSD, event.Duration)
+		r.TenantProfileGenerated(event.Tenant)
+	case events.ExtractionFailed:
+		err := event.Err
+		if err == nil {
+			err = errors.New("unknown error")
+		}
+		r.ProfileFailed(err)
+		r.TenantProfileFailed(event.Tenant)
+	}
+}
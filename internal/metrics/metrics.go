@@ -0,0 +1,359 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+)
+
+// recentErrorsLimit - сколько последних ошибок хранится для дашборда
+const recentErrorsLimit = 20
+
+// Registry накапливает метрики работы бота с момента запуска процесса.
+// Методы безопасны для вызова на nil-реестре (метрики отключены).
+type Registry struct {
+	mutex     sync.Mutex
+	startedAt time.Time
+
+	interviewsStarted   int
+	interviewsCompleted int
+	profilesGenerated   int
+	profilesFailed      int
+
+	interviewSeconds float64
+	profileSeconds   float64
+
+	promptTokens     int
+	completionTokens int
+	costUSD          float64
+	tokensByModel    map[string]int
+
+	variants map[string]*VariantCounts
+
+	// tenants - воронка интервью по арендаторам
+	tenants map[string]*TenantCounts
+
+	// deferredStarts - отклоненные из-за квот запуски интервью по причинам
+	deferredStarts map[string]int
+
+	// jsonRepairs - разборы JSON ответов модели по способу восстановления
+	jsonRepairs map[string]int
+
+	// updates - очередь обновлений Telegram всех ботов процесса
+	updates       UpdateCounts
+	updateWaitSec float64
+
+	errors []ErrorEntry
+}
+
+// VariantCounts - воронка варианта формулировки вопроса: задан, получен ответ,
+// интервью с этим вариантом завершено
+type VariantCounts struct {
+	Asked          int     `json:"asked"`
+	Answered       int     `json:"answered"`
+	Completed      int     `json:"completed"`
+	CompletionRate float64 `json:"completion_rate"`
+}
+
+// TenantCounts - воронка интервью арендатора
+type TenantCounts struct {
+	InterviewsStarted   int `json:"interviews_started"`
+	InterviewsCompleted int `json:"interviews_completed"`
+	ProfilesGenerated   int `json:"profiles_generated"`
+	ProfilesFailed      int `json:"profiles_failed"`
+}
+
+// UpdateCounts - очередь обработки обновлений Telegram: принятые, взятые в обработку и отброшенные
+// при перегрузке обновления, текущее и пиковое число ждущих обработки
+type UpdateCounts struct {
+	Queued         int            `json:"queued"`
+	Processed      int            `json:"processed"`
+	Shed           map[string]int `json:"shed"`
+	QueueDepth     int            `json:"queue_depth"`
+	PeakQueueDepth int            `json:"peak_queue_depth"`
+	AvgWaitSeconds float64        `json:"avg_wait_seconds"`
+}
+
+// ErrorEntry - запись о недавней ошибке
+type ErrorEntry struct {
+	Time    time.Time `json:"time"`
+	Source  string    `json:"source"`
+	Message string    `json:"message"`
+}
+
+// Snapshot - срез метрик на момент запроса
+type Snapshot struct {
+	StartedAt           time.Time      `json:"started_at"`
+	InterviewsStarted   int            `json:"interviews_started"`
+	InterviewsCompleted int            `json:"interviews_completed"`
+	ProfilesGenerated   int            `json:"profiles_generated"`
+	ProfilesFailed      int            `json:"profiles_failed"`
+	AvgInterviewSeconds float64        `json:"avg_interview_seconds"`
+	AvgProfileSeconds   float64        `json:"avg_profile_seconds"`
+	PromptTokens        int            `json:"prompt_tokens"`
+	CompletionTokens    int            `json:"completion_tokens"`
+	CostUSD             float64        `json:"cost_usd"`
+	TokensByModel       map[string]int `json:"tokens_by_model"`
+	// Variants - воронка по вариантам вопросов (ключ - шаблон/вариант)
+	Variants map[string]VariantCounts `json:"variants"`
+	// Tenants - воронка по арендаторам (ключ - ID арендатора)
+	Tenants map[string]TenantCounts `json:"tenants,omitempty"`
+	// DeferredStarts - запуски интервью, отложенные из-за квот и бюджета (ключ - причина)
+	DeferredStarts map[string]int `json:"deferred_starts"`
+	// JSONRepairs - разборы JSON ответов модели по способу (strict, tolerant, balanced, llm, failed)
+	JSONRepairs  map[string]int `json:"json_repairs"`
+	RecentErrors []ErrorEntry   `json:"recent_errors"`
+	// Updates - очередь обработки обновлений Telegram
+	Updates UpdateCounts `json:"updates"`
+}
+
+// New создает пустой реестр метрик
+func New() *Registry {
+	return &Registry{
+		startedAt:      time.Now(),
+		tokensByModel:  make(map[string]int),
+		variants:       make(map[string]*VariantCounts),
+		tenants:        make(map[string]*TenantCounts),
+		deferredStarts: make(map[string]int),
+		jsonRepairs:    make(map[string]int),
+		updates:        UpdateCounts{Shed: make(map[string]int)},
+	}
+}
+
+// InterviewStarted учитывает начатое интервью
+func (r *Registry) InterviewStarted() {
+	if r == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.interviewsStarted++
+}
+
+// InterviewCompleted учитывает завершенное интервью и его длительность
+func (r *Registry) InterviewCompleted(duration time.Duration) {
+	if r == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.interviewsCompleted++
+	r.interviewSeconds += duration.Seconds()
+}
+
+// InterviewDeferred учитывает запуск интервью, отложенный по причине reason (квота, бюджет)
+func (r *Registry) InterviewDeferred(reason string) {
+	if r == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.deferredStarts[reason]++
+}
+
+// JSONRepaired учитывает разбор JSON ответа модели способом method
+func (r *Registry) JSONRepaired(method string) {
+	if r == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.jsonRepairs[method]++
+}
+
+// ProfileGenerated учитывает созданный профиль, время анализа и расход токенов
+func (r *Registry) ProfileGenerated(model string, promptTokens, completionTokens int, costUSD float64, took time.Duration) {
+	if r == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.profilesGenerated++
+	r.profileSeconds += took.Seconds()
+	r.promptTokens += promptTokens
+	r.completionTokens += completionTokens
+	r.costUSD += costUSD
+	r.tokensByModel[model] += promptTokens + completionTokens
+}
+
+// ProfileFailed учитывает неудачный анализ профиля
+func (r *Registry) ProfileFailed(err error) {
+	if r == nil {
+		return
+	}
+	r.mutex.Lock()
+	r.profilesFailed++
+	r.mutex.Unlock()
+	r.RecordError("profile_extraction", err.Error())
+}
+
+// VariantAsked учитывает, что пользователю задан вариант вопроса (пустой ключ игнорируется)
+func (r *Registry) VariantAsked(key string) {
+	r.countVariant(key, func(c *VariantCounts) { c.Asked++ })
+}
+
+// VariantAnswered учитывает ответ на вариант вопроса
+func (r *Registry) VariantAnswered(key string) {
+	r.countVariant(key, func(c *VariantCounts) { c.Answered++ })
+}
+
+// VariantCompleted учитывает завершенное интервью, в котором был задан вариант вопроса
+func (r *Registry) VariantCompleted(key string) {
+	r.countVariant(key, func(c *VariantCounts) { c.Completed++ })
+}
+
+func (r *Registry) countVariant(key string, count func(*VariantCounts)) {
+	if r == nil || key == "" {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.variants[key] == nil {
+		r.variants[key] = &VariantCounts{}
+	}
+	count(r.variants[key])
+}
+
+// TenantInterviewStarted учитывает интервью, начатое у арендатора (пустой ID игнорируется)
+func (r *Registry) TenantInterviewStarted(tenantID string) {
+	r.countTenant(tenantID, func(c *TenantCounts) { c.InterviewsStarted++ })
+}
+
+// TenantInterviewCompleted учитывает завершенное интервью арендатора
+func (r *Registry) TenantInterviewCompleted(tenantID string) {
+	r.countTenant(tenantID, func(c *TenantCounts) { c.InterviewsCompleted++ })
+}
+
+// TenantProfileGenerated учитывает созданный профиль арендатора
+func (r *Registry) TenantProfileGenerated(tenantID string) {
+	r.countTenant(tenantID, func(c *TenantCounts) { c.ProfilesGenerated++ })
+}
+
+// TenantProfileFailed учитывает неудачный анализ профиля арендатора
+func (r *Registry) TenantProfileFailed(tenantID string) {
+	r.countTenant(tenantID, func(c *TenantCounts) { c.ProfilesFailed++ })
+}
+
+func (r *Registry) countTenant(tenantID string, count func(*TenantCounts)) {
+	if r == nil || tenantID == "" {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.tenants[tenantID] == nil {
+		r.tenants[tenantID] = &TenantCounts{}
+	}
+	count(r.tenants[tenantID])
+}
+
+// UpdateQueued учитывает обновление Telegram, поставленное в очередь обработки
+func (r *Registry) UpdateQueued() {
+	if r == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.updates.Queued++
+	r.updates.QueueDepth++
+	r.updates.PeakQueueDepth = max(r.updates.PeakQueueDepth, r.updates.QueueDepth)
+}
+
+// UpdateDequeued учитывает обновление, взятое в обработку после ожидания в очереди wait
+func (r *Registry) UpdateDequeued(wait time.Duration) {
+	if r == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.updates.Processed++
+	r.updates.QueueDepth--
+	r.updateWaitSec += wait.Seconds()
+}
+
+// UpdateShed учитывает обновление вида kind, отброшенное из-за переполненной очереди
+func (r *Registry) UpdateShed(kind string) {
+	if r == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.updates.Shed[kind]++
+}
+
+// RecordError сохраняет ошибку в списке последних ошибок
+func (r *Registry) RecordError(source, message string) {
+	if r == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.errors = append(r.errors, ErrorEntry{Time: time.Now(), Source: source, Message: message})
+	if len(r.errors) > recentErrorsLimit {
+		r.errors = r.errors[len(r.errors)-recentErrorsLimit:]
+	}
+}
+
+// Snapshot возвращает копию текущих метрик
+func (r *Registry) Snapshot() Snapshot {
+	if r == nil {
+		return Snapshot{}
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	snapshot := Snapshot{
+		StartedAt:           r.startedAt,
+		InterviewsStarted:   r.interviewsStarted,
+		InterviewsCompleted: r.interviewsCompleted,
+		ProfilesGenerated:   r.profilesGenerated,
+		ProfilesFailed:      r.profilesFailed,
+		PromptTokens:        r.promptTokens,
+		CompletionTokens:    r.completionTokens,
+		CostUSD:             r.costUSD,
+		TokensByModel:       make(map[string]int, len(r.tokensByModel)),
+		Variants:            make(map[string]VariantCounts, len(r.variants)),
+		DeferredStarts:      make(map[string]int, len(r.deferredStarts)),
+		JSONRepairs:         make(map[string]int, len(r.jsonRepairs)),
+		RecentErrors:        make([]ErrorEntry, 0, len(r.errors)),
+	}
+	if r.interviewsCompleted > 0 {
+		snapshot.AvgInterviewSeconds = r.interviewSeconds / float64(r.interviewsCompleted)
+	}
+	if r.profilesGenerated > 0 {
+		snapshot.AvgProfileSeconds = r.profileSeconds / float64(r.profilesGenerated)
+	}
+	for model, tokens := range r.tokensByModel {
+		snapshot.TokensByModel[model] = tokens
+	}
+	for key, counts := range r.variants {
+		variant := *counts
+		if variant.Asked > 0 {
+			variant.CompletionRate = float64(variant.Completed) / float64(variant.Asked)
+		}
+		snapshot.Variants[key] = variant
+	}
+	if len(r.tenants) > 0 {
+		snapshot.Tenants = make(map[string]TenantCounts, len(r.tenants))
+		for tenantID, counts := range r.tenants {
+			snapshot.Tenants[tenantID] = *counts
+		}
+	}
+	for reason, count := range r.deferredStarts {
+		snapshot.DeferredStarts[reason] = count
+	}
+	for method, count := range r.jsonRepairs {
+		snapshot.JSONRepairs[method] = count
+	}
+	snapshot.Updates = r.updates
+	snapshot.Updates.Shed = make(map[string]int, len(r.updates.Shed))
+	for kind, count := range r.updates.Shed {
+		snapshot.Updates.Shed[kind] = count
+	}
+	if r.updates.Processed > 0 {
+		snapshot.Updates.AvgWaitSeconds = r.updateWaitSec / float64(r.updates.Processed)
+	}
+	// Новые ошибки первыми
+	for i := len(r.errors) - 1; i >= 0; i-- {
+		snapshot.RecentErrors = append(snapshot.RecentErrors, r.errors[i])
+	}
+	return snapshot
+}
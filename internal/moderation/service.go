@@ -0,0 +1,137 @@
+package moderation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"interview-bot-complete/internal/api"
+	"interview-bot-complete/internal/config"
+)
+
+// requestTimeout - ограничение на проверку одного ответа
+const requestTimeout = 15 * time.Second
+
+// mockMarkers - фразы, которые в режиме симуляции отмечаются категорией self-harm
+var mockMarkers = []string{"покончить с собой", "не хочу жить", "суицид", "kill myself", "suicide"}
+
+// Result - результат проверки текста
+type Result struct {
+	// Categories - сработавшие категории из настроенных, по алфавиту; пусто - текст не отмечен
+	Categories []string
+}
+
+// Flagged сообщает, что текст отмечен хотя бы по одной настроенной категории
+func (r *Result) Flagged() bool {
+	return r != nil && len(r.Categories) > 0
+}
+
+// Service проверяет ответы пользователей через OpenAI Moderation API
+type Service struct {
+	apiKey     string
+	model      string
+	mock       bool
+	categories map[string]bool
+	http       *http.Client
+	endpoint   api.Endpoint
+}
+
+type moderationRequest struct {
+	Model string `json:"model"`
+	Input string `json:"input"`
+}
+
+type moderationResponse struct {
+	Results []struct {
+		Flagged    bool            `json:"flagged"`
+		Categories map[string]bool `json:"categories"`
+	} `json:"results"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// New создает сервис модерации по настройкам cfg
+func New(apiKey string, cfg config.ModerationConfig) *Service {
+	categories := make(map[string]bool, len(cfg.Categories))
+	for _, category := range cfg.Categories {
+		categories[category] = true
+	}
+	return &Service{
+		apiKey:     apiKey,
+		model:      cfg.Model,
+		mock:       strings.EqualFold(os.Getenv("LLM_PROVIDER"), "mock"),
+		categories: categories,
+		http:       api.NewHTTPClient(requestTimeout),
+		endpoint:   api.EndpointFromEnv(),
+	}
+}
+
+// Check проверяет текст и возвращает сработавшие настроенные категории
+func (s *Service) Check(ctx context.Context, text string) (*Result, error) {
+	if s.mock {
+		return s.mockCheck(text), nil
+	}
+
+	body, err := json.Marshal(moderationRequest{Model: s.model, Input: text})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка сериализации запроса: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", s.endpoint.URL("moderations", s.model), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка создания запроса: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	s.endpoint.Authorize(req, s.apiKey)
+
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка запроса модерации: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения ответа: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ошибка OpenAI Moderation API: статус %d, тело: %s", resp.StatusCode, string(data))
+	}
+
+	var parsed moderationResponse
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return nil, fmt.Errorf("ошибка парсинга ответа: %w", err)
+	}
+	if parsed.Error != nil {
+		return nil, fmt.Errorf("ошибка OpenAI Moderation API: %s", parsed.Error.Message)
+	}
+
+	result := &Result{}
+	for _, item := range parsed.Results {
+		for category, flagged := range item.Categories {
+			if flagged && s.categories[category] {
+				result.Categories = append(result.Categories, category)
+			}
+		}
+	}
+	sort.Strings(result.Categories)
+	return result, nil
+}
+
+// mockCheck отмечает текст с явными фразами о самоповреждении - для проверки сценария без API
+func (s *Service) mockCheck(text string) *Result {
+	lower := strings.ToLower(text)
+	for _, marker := range mockMarkers {
+		if strings.Contains(lower, marker) && s.categories["self-harm"] {
+			return &Result{Categories: []string{"self-harm"}}
+		}
+	}
+	return &Result{}
+}
@@ -0,0 +1,121 @@
+package normalize
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Даты цифрами: 15.03.2021 (также через / и -), 2021-03-15 и 03.2021
+var (
+	dayMonthYearPattern = regexp.MustCompile(`\b(\d{1,2})[./-](\d{1,2})[./-](\d{4})\b`)
+	isoDatePattern      = regexp.MustCompile(`\b(\d{4})-(\d{1,2})-(\d{1,2})\b`)
+	monthYearPattern    = regexp.MustCompile(`\b(\d{1,2})[./](\d{4})\b`)
+)
+
+// Допустимые годы даты
+const (
+	minYear = 1900
+	maxYear = 2100
+)
+
+// monthStems - начала русских названий месяцев во всех падежах («марта», «в марте»)
+var monthStems = []string{"январ", "феврал", "март", "апрел", "ма", "июн", "июл", "август", "сентябр", "октябр", "ноябр", "декабр"}
+
+// monthNames - английские названия месяцев; принимаются полностью или первыми тремя буквами
+var monthNames = []string{"january", "february", "march", "april", "may", "june", "july", "august", "september", "october", "november", "december"}
+
+// Date приводит ответ к дате: ГГГГ-ММ-ДД, если указан день, ГГГГ-ММ - месяц и год, ГГГГ - только год.
+// Понимает даты цифрами и с названием месяца: «15 марта 2021», «March 15, 2021», «в мае 2019».
+func Date(answer string) (string, bool) {
+	if match := isoDatePattern.FindStringSubmatch(answer); match != nil {
+		return fullDate(atoi(match[1]), atoi(match[2]), atoi(match[3]))
+	}
+	if match := dayMonthYearPattern.FindStringSubmatch(answer); match != nil {
+		return fullDate(atoi(match[3]), atoi(match[2]), atoi(match[1]))
+	}
+	if match := monthYearPattern.FindStringSubmatch(answer); match != nil {
+		return monthDate(atoi(match[2]), atoi(match[1]))
+	}
+
+	tokens := words(answer)
+	year, month, day := 0, 0, 0
+	for i, token := range tokens {
+		if number, err := strconv.Atoi(token); err == nil {
+			switch {
+			case len(token) == 4 && year == 0:
+				year = number
+			case len(token) <= 2 && day == 0 && nearMonth(tokens, i):
+				day = number
+			}
+			continue
+		}
+		if month == 0 {
+			month = monthOf(token)
+		}
+	}
+	switch {
+	case year == 0:
+		return "", false
+	case month == 0:
+		if year < minYear || year > maxYear {
+			return "", false
+		}
+		return strconv.Itoa(year), true
+	case day == 0:
+		return monthDate(year, month)
+	}
+	return fullDate(year, month, day)
+}
+
+// nearMonth сообщает, стоит ли рядом с числом tokens[i] название месяца («15 марта», «March 15»)
+func nearMonth(tokens []string, i int) bool {
+	return (i+1 < len(tokens) && monthOf(tokens[i+1]) != 0) || (i > 0 && monthOf(tokens[i-1]) != 0)
+}
+
+// monthOf возвращает номер месяца по слову (0 - не месяц)
+func monthOf(token string) int {
+	for n, stem := range monthStems {
+		// «май» склоняется без общей основы длиннее «ма»: май, мая, мае
+		if stem == "ма" {
+			if token == "май" || token == "мая" || token == "мае" {
+				return n + 1
+			}
+			continue
+		}
+		if strings.HasPrefix(token, stem) {
+			return n + 1
+		}
+	}
+	for n, name := range monthNames {
+		if token == name || token == name[:3] {
+			return n + 1
+		}
+	}
+	return 0
+}
+
+// fullDate проверяет и форматирует дату ГГГГ-ММ-ДД
+func fullDate(year, month, day int) (string, bool) {
+	value := fmt.Sprintf("%04d-%02d-%02d", year, month, day)
+	if _, err := time.Parse("2006-01-02", value); err != nil || year < minYear || year > maxYear {
+		return "", false
+	}
+	return value, true
+}
+
+// monthDate проверяет и форматирует месяц ГГГГ-ММ
+func monthDate(year, month int) (string, bool) {
+	if month < 1 || month > 12 || year < minYear || year > maxYear {
+		return "", false
+	}
+	return fmt.Sprintf("%04d-%02d", year, month), true
+}
+
+// atoi - число из подстроки, проверенной регулярным выражением
+func atoi(value string) int {
+	number, _ := strconv.Atoi(value)
+	return number
+}
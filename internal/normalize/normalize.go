@@ -0,0 +1,89 @@
+package normalize
+
+import (
+	"interview-bot-complete/internal/config"
+	"regexp"
+	"strings"
+)
+
+// listSeparator - разделитель элементов приведенного списка
+const listSeparator = "; "
+
+// hints - подсказки пользователю, когда ответ не удалось привести к типу
+var hints = map[string]string{
+	config.AnswerNumber: "Не удалось распознать число. Ответьте, пожалуйста, числом, например: 25.",
+	config.AnswerDate:   "Не удалось распознать дату. Укажите ее, например, так: 15.03.2021, март 2021 или 2021.",
+}
+
+// labels - названия типов ответа для модели извлечения профиля
+var labels = map[string]string{
+	config.AnswerNumber: "число",
+	config.AnswerDate:   "дата",
+	config.AnswerList:   "список",
+}
+
+// Answer приводит ответ к ожидаемому типу answerType (config.AnswerNumber...): число - десятичной
+// записью, дата - ГГГГ-ММ-ДД, ГГГГ-ММ или ГГГГ, список - элементами через «; ».
+// false - ответ не распознан; свободный ответ (пустой тип или text) не приводится.
+func Answer(answerType, answer string) (string, bool) {
+	answer = strings.TrimSpace(answer)
+	switch answerType {
+	case config.AnswerNumber:
+		return Number(answer)
+	case config.AnswerDate:
+		return Date(answer)
+	case config.AnswerList:
+		items := List(answer)
+		return strings.Join(items, listSeparator), len(items) > 0
+	}
+	return "", false
+}
+
+// Hint возвращает просьбу ответить заново для ответа, не приведенного к типу answerType
+func Hint(answerType string) string {
+	if hint, ok := hints[answerType]; ok {
+		return hint
+	}
+	return "Не удалось распознать ответ. Попробуйте ответить иначе."
+}
+
+// Label возвращает название типа ответа для промпта (пусто - свободный ответ)
+func Label(answerType string) string {
+	return labels[answerType]
+}
+
+// listSplitPattern - разделители элементов перечисления
+var listSplitPattern = regexp.MustCompile(`[,;\n]+`)
+
+// listMarkerPattern - маркер или номер пункта в начале элемента: «- », «• », «1. », «2) »
+var listMarkerPattern = regexp.MustCompile(`^\s*([-•*–—]+|\d+[.)])\s*`)
+
+// listConjunctionPattern - союз перед последним элементом перечисления: «Go, Python и Rust»
+var listConjunctionPattern = regexp.MustCompile(`(?i)\s+(и|and)\s+`)
+
+// List разбивает перечисление на элементы: по запятым, точкам с запятой и строкам,
+// без маркеров пунктов; союз «и» разделяет последний элемент, если элементов несколько
+func List(answer string) []string {
+	parts := listSplitPattern.Split(answer, -1)
+	if len(parts) > 1 {
+		last := parts[len(parts)-1]
+		parts = append(parts[:len(parts)-1], listConjunctionPattern.Split(last, -1)...)
+	}
+	var items []string
+	for _, part := range parts {
+		item := strings.TrimSpace(listMarkerPattern.ReplaceAllString(part, ""))
+		item = strings.TrimRight(item, ".")
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// words разбивает ответ на слова в нижнем регистре (буквы и цифры)
+func words(answer string) []string {
+	return wordPattern.FindAllString(strings.ToLower(answer), -1)
+}
+
+// wordPattern - слово ответа: буквы или цифры
+var wordPattern = regexp.MustCompile(`[\p{L}]+|\d+`)
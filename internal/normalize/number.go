@@ -0,0 +1,106 @@
+package normalize
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// digitsPattern - число цифрами: «25», «-3», «1,5», «2 500»
+var digitsPattern = regexp.MustCompile(`-?\d+(?:[ \x{00A0}]\d{3})*(?:[.,]\d+)?`)
+
+// numberWords - значения числительных (русские формы в именительном и родительном падеже и английские)
+var numberWords = map[string]int{
+	"ноль": 0, "нуля": 0, "zero": 0,
+	"один": 1, "одна": 1, "одно": 1, "одного": 1, "одной": 1, "one": 1,
+	"два": 2, "две": 2, "двух": 2, "two": 2,
+	"три": 3, "трех": 3, "трёх": 3, "three": 3,
+	"четыре": 4, "четырех": 4, "четырёх": 4, "four": 4,
+	"пять": 5, "пяти": 5, "five": 5,
+	"шесть": 6, "шести": 6, "six": 6,
+	"семь": 7, "семи": 7, "seven": 7,
+	"восемь": 8, "восьми": 8, "eight": 8,
+	"девять": 9, "девяти": 9, "nine": 9,
+	"десять": 10, "десяти": 10, "ten": 10,
+	"одиннадцать": 11, "одиннадцати": 11, "eleven": 11,
+	"двенадцать": 12, "двенадцати": 12, "twelve": 12,
+	"тринадцать": 13, "тринадцати": 13, "thirteen": 13,
+	"четырнадцать": 14, "четырнадцати": 14, "fourteen": 14,
+	"пятнадцать": 15, "пятнадцати": 15, "fifteen": 15,
+	"шестнадцать": 16, "шестнадцати": 16, "sixteen": 16,
+	"семнадцать": 17, "семнадцати": 17, "seventeen": 17,
+	"восемнадцать": 18, "восемнадцати": 18, "eighteen": 18,
+	"девятнадцать": 19, "девятнадцати": 19, "nineteen": 19,
+	"двадцать": 20, "двадцати": 20, "twenty": 20,
+	"тридцать": 30, "тридцати": 30, "thirty": 30,
+	"сорок": 40, "сорока": 40, "forty": 40,
+	"пятьдесят": 50, "пятидесяти": 50, "fifty": 50,
+	"шестьдесят": 60, "шестидесяти": 60, "sixty": 60,
+	"семьдесят": 70, "семидесяти": 70, "seventy": 70,
+	"восемьдесят": 80, "восьмидесяти": 80, "eighty": 80,
+	"девяносто": 90, "девяноста": 90, "ninety": 90,
+	"сто": 100, "ста": 100,
+	"двести": 200, "двухсот": 200,
+	"триста": 300, "трехсот": 300, "трёхсот": 300,
+	"четыреста": 400, "четырехсот": 400, "четырёхсот": 400,
+	"пятьсот": 500, "пятисот": 500,
+	"шестьсот": 600, "шестисот": 600,
+	"семьсот": 700, "семисот": 700,
+	"восемьсот": 800, "восьмисот": 800,
+	"девятьсот": 900, "девятисот": 900,
+}
+
+// scaleWords - множители: «сто» в английском («two hundred») и тысячи
+var scaleWords = map[string]int{
+	"hundred": 100,
+	"тысяча":  1000, "тысячи": 1000, "тысяч": 1000, "thousand": 1000,
+}
+
+// Number приводит ответ к числу: первое число цифрами или числительное словами
+// («двадцать пять лет» - 25, «twenty-one» - 21)
+func Number(answer string) (string, bool) {
+	if match := digitsPattern.FindString(answer); match != "" {
+		match = strings.NewReplacer(" ", "", "\u00a0", "", ",", ".").Replace(match)
+		value, err := strconv.ParseFloat(match, 64)
+		if err != nil {
+			return "", false
+		}
+		return strconv.FormatFloat(value, 'f', -1, 64), true
+	}
+	return numberFromWords(words(strings.ReplaceAll(answer, "-", " ")))
+}
+
+// numberFromWords складывает первую последовательность числительных; слова вокруг нее пропускаются
+func numberFromWords(tokens []string) (string, bool) {
+	total, current, found := 0, 0, false
+	for _, token := range tokens {
+		if value, ok := numberWords[token]; ok {
+			current += value
+			found = true
+			continue
+		}
+		if scale, ok := scaleWords[token]; ok {
+			if current == 0 {
+				current = 1 // «тысяча» без числа перед ней
+			}
+			if scale == 100 {
+				current *= scale
+			} else {
+				total += current * scale
+				current = 0
+			}
+			found = true
+			continue
+		}
+		if token == "and" && found {
+			continue
+		}
+		if found {
+			break
+		}
+	}
+	if !found {
+		return "", false
+	}
+	return strconv.Itoa(total + current), true
+}
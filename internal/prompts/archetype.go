@@ -0,0 +1,79 @@
+package prompts
+
+import (
+	"fmt"
+
+	"interview-bot-complete/internal/language"
+)
+
+// ArchetypeMatchMarker - заголовок промпта подбора архетипа (по нему mock-режим выбирает ответ)
+const ArchetypeMatchMarker = "ARCHETYPE MATCH"
+
+const archetypeMatchPromptRU = ArchetypeMatchMarker + `
+Ты психолог. Выбери из каталога «%s» ОДИН архетип, которому человек соответствует лучше всего.
+
+АРХЕТИПЫ (формат: [id] название - описание; признаки):
+%s
+
+ВЕРНИ JSON СО СТРУКТУРОЙ:
+{
+  "archetype_id": "id архетипа из каталога, строго как в квадратных скобках",
+  "description": "3-5 предложений: почему человек соответствует архетипу",
+  "strengths": ["сильные стороны архетипа, проявившиеся в ответах"],
+  "blind_spots": ["слепые зоны и риски"],
+  "growth": ["конкретные рекомендации по развитию"]
+}
+
+ПРАВИЛА:
+- Выбирай только из перечисленных архетипов, не придумывай новые
+- Опирайся на профиль и ответы, ссылайся на конкретные факты
+- Не ставь диагнозов
+- Пиши на русском языке
+- Верни ТОЛЬКО валидный JSON, без markdown и комментариев
+
+ПРОФИЛЬ (JSON):
+%s
+
+ТЕКСТ ИНТЕРВЬЮ:
+%s
+
+ОТВЕТ (только JSON):`
+
+const archetypeMatchPromptEN = ArchetypeMatchMarker + `
+You are a psychologist. Choose ONE archetype from the "%s" catalog that fits the person best.
+
+ARCHETYPES (format: [id] name - description; signs):
+%s
+
+RETURN JSON WITH THIS STRUCTURE:
+{
+  "archetype_id": "archetype id from the catalog, exactly as in square brackets",
+  "description": "3-5 sentences: why the person fits the archetype",
+  "strengths": ["archetype strengths shown in the answers"],
+  "blind_spots": ["blind spots and risks"],
+  "growth": ["concrete development recommendations"]
+}
+
+RULES:
+- Choose only from the listed archetypes, do not invent new ones
+- Rely on the profile and the answers, refer to concrete facts
+- Do not diagnose
+- Write in English
+- Return ONLY valid JSON, no markdown and no comments
+
+PROFILE (JSON):
+%s
+
+INTERVIEW TEXT:
+%s
+
+ANSWER (JSON only):`
+
+// GenerateArchetypeMatchPrompt строит промпт выбора архетипа из каталога на языке lang.
+// archetypes - перечень архетипов каталога, по строке на архетип
+func GenerateArchetypeMatchPrompt(catalogName, archetypes, profileJSON, userText, lang string) string {
+	if lang == language.English {
+		return fmt.Sprintf(archetypeMatchPromptEN, catalogName, archetypes, profileJSON, userText)
+	}
+	return fmt.Sprintf(archetypeMatchPromptRU, catalogName, archetypes, profileJSON, userText)
+}
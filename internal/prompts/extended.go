@@ -0,0 +1,71 @@
+package prompts
+
+import (
+	"fmt"
+
+	"interview-bot-complete/internal/language"
+)
+
+// ExtendedAnalysisMarker - заголовок промпта расширенного анализа (по нему mock-режим выбирает ответ)
+const ExtendedAnalysisMarker = "EXTENDED ANALYSIS"
+
+const extendedAnalysisPromptRU = ExtendedAnalysisMarker + `
+Ты карьерный консультант и психолог. По профилю и тексту интервью подготовь расширенный анализ личности.
+
+ВЕРНИ JSON СО СТРУКТУРОЙ:
+{
+  "report": [
+    {"title": "название раздела", "text": "развернутый текст раздела, 1-3 абзаца"}
+  ],
+  "career_paths": ["подходящие направления и роли с кратким обоснованием"]
+}
+
+ПРАВИЛА:
+- Раздели отчет на 5-7 разделов: мотивация, стиль работы, коммуникация, ценности, карьера, точки роста
+- Опирайся только на профиль и ответы, ссылайся на конкретные факты
+- Не ставь диагнозов и не давай медицинских, юридических или финансовых гарантий
+- Пиши на русском языке
+- Верни ТОЛЬКО валидный JSON, без markdown и комментариев
+
+ПРОФИЛЬ (JSON):
+%s
+
+ТЕКСТ ИНТЕРВЬЮ:
+%s
+
+ОТВЕТ (только JSON):`
+
+const extendedAnalysisPromptEN = ExtendedAnalysisMarker + `
+You are a career counselor and psychologist. Using the profile and the interview text, prepare an extended personality analysis.
+
+RETURN JSON WITH THIS STRUCTURE:
+{
+  "report": [
+    {"title": "section title", "text": "detailed section text, 1-3 paragraphs"}
+  ],
+  "career_paths": ["suitable directions and roles with a short rationale"]
+}
+
+RULES:
+- Split the report into 5-7 sections: motivation, work style, communication, values, career, growth areas
+- Rely only on the profile and the answers, refer to concrete facts
+- Do not diagnose and do not give medical, legal or financial guarantees
+- Write in English
+- Return ONLY valid JSON, no markdown and no comments
+
+PROFILE (JSON):
+%s
+
+INTERVIEW TEXT:
+%s
+
+ANSWER (JSON only):`
+
+// GenerateExtendedAnalysisPrompt строит промпт расширенного анализа (отчет и направления) на языке lang.
+// Архетип подбирается отдельно по каталогу (GenerateArchetypeMatchPrompt)
+func GenerateExtendedAnalysisPrompt(profileJSON, userText, lang string) string {
+	if lang == language.English {
+		return fmt.Sprintf(extendedAnalysisPromptEN, profileJSON, userText)
+	}
+	return fmt.Sprintf(extendedAnalysisPromptRU, profileJSON, userText)
+}
@@ -2,11 +2,98 @@ package prompts
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
+	"interview-bot-complete/internal/language"
 	"interview-bot-complete/internal/schema"
 )
 
+// DefaultPromptVersion - версия промпта извлечения, используемая по умолчанию
+const DefaultPromptVersion = "v2"
+
+// extractionPrompt - версия промпта извлечения
+type extractionPrompt struct {
+	generate func(map[string]schema.SchemaField, string, string) string
+	// citations - промпт просит ссылки на ответы; текст интервью размечается метками [B<блок>.Q<вопрос>]
+	citations bool
+}
+
+// extractionPrompts - зарегистрированные версии промпта извлечения профиля
+var extractionPrompts = map[string]extractionPrompt{
+	"v1": {generate: generateV1ExtractionPrompt},
+	"v2": {generate: generateV2ExtractionPrompt, citations: true},
+}
+
+// generateV1ExtractionPrompt выбирает язык инструкций по языку ответов пользователя
+func generateV1ExtractionPrompt(schemaFields map[string]schema.SchemaField, userText string, lang string) string {
+	if lang == language.English {
+		return GenerateEnglishExtractionPrompt(schemaFields, userText)
+	}
+	return GenerateOptimizedExtractionPrompt(schemaFields, userText)
+}
+
+// GenerateExtractionPrompt строит промпт извлечения указанной версии на языке ответов lang.
+// Шаблон версии на диске (LoadTemplates) имеет приоритет над встроенным промптом.
+func GenerateExtractionPrompt(version string, schemaFields map[string]schema.SchemaField, userText string, lang string) (string, error) {
+	if version == "" {
+		version = DefaultPromptVersion
+	}
+
+	if tmpl := lookupTemplate(version, lang); tmpl != nil {
+		return tmpl.render(TemplateData{
+			Schema:        generateSchemaDescription(schemaFields),
+			InterviewText: userText,
+			Language:      lang,
+			Citations:     citationInstructions(lang),
+		})
+	}
+
+	prompt, ok := extractionPrompts[version]
+	if !ok {
+		return "", fmt.Errorf("unknown prompt version %q (available: %s)", version, strings.Join(PromptVersions(), ", "))
+	}
+	return prompt.generate(schemaFields, userText, lang), nil
+}
+
+// UsesCitations сообщает, просит ли версия промпта для языка ответов lang ссылки на источники полей
+func UsesCitations(version, lang string) bool {
+	if version == "" {
+		version = DefaultPromptVersion
+	}
+	if tmpl := lookupTemplate(version, lang); tmpl != nil {
+		return tmpl.citations
+	}
+	return extractionPrompts[version].citations
+}
+
+// PromptRevision возвращает ревизию шаблона версии на диске (<файл>@<контрольная сумма>);
+// пусто - используется встроенный промпт
+func PromptRevision(version, lang string) string {
+	if version == "" {
+		version = DefaultPromptVersion
+	}
+	if tmpl := lookupTemplate(version, lang); tmpl != nil {
+		return tmpl.revision()
+	}
+	return ""
+}
+
+// PromptVersions возвращает список доступных версий промпта: встроенные и шаблоны на диске
+func PromptVersions() []string {
+	versions := make([]string, 0, len(extractionPrompts))
+	for version := range extractionPrompts {
+		versions = append(versions, version)
+	}
+	for _, version := range TemplateVersions() {
+		if _, builtin := extractionPrompts[version]; !builtin {
+			versions = append(versions, version)
+		}
+	}
+	sort.Strings(versions)
+	return versions
+}
+
 // GenerateOptimizedExtractionPrompt - оптимизированный промпт для извлечения профиля за один запрос
 func GenerateOptimizedExtractionPrompt(schemaFields map[string]schema.SchemaField, userText string) string {
 	prompt := `Создай профиль пользователя в формате JSON на основе текста интервью.
@@ -42,6 +129,42 @@ func GenerateOptimizedExtractionPrompt(schemaFields map[string]schema.SchemaFiel
 	return fmt.Sprintf(prompt, schemaDescription, userText)
 }
 
+// GenerateEnglishExtractionPrompt - вариант оптимизированного промпта для англоязычных интервью
+func GenerateEnglishExtractionPrompt(schemaFields map[string]schema.SchemaField, userText string) string {
+	prompt := `Build a user profile in JSON format based on the interview text.
+
+INSTRUCTIONS:
+1. Fill in every field from the list below
+2. If the text has no information for a field - use null
+3. Arrays must contain specific values, not generic phrases
+4. Numeric fields must be numbers, string fields must be strings
+5. Be precise and specific
+6. Keep values in the language of the interview (English)
+7. Return ONLY valid JSON, without markdown or comments
+
+FIELDS TO FILL:
+%s
+
+FILLING RULES:
+- name: the user's full name
+- age: age as a number
+- birth_city/current_city: city names
+- hard_skills: specific technical skills ["Python", "React", "SQL"]
+- soft_skills: personal qualities ["communication", "leadership"]
+- hobbies: specific hobbies ["football", "photography", "programming"]
+- personality_traits: character traits ["goal-oriented", "creative"]
+- values: life values ["family", "growth", "honesty"]
+- career_goals: career goals ["become a team lead", "start a company"]
+
+INTERVIEW TEXT:
+%s
+
+ANSWER (JSON only):`
+
+	schemaDescription := generateSchemaDescription(schemaFields)
+	return fmt.Sprintf(prompt, schemaDescription, userText)
+}
+
 func generateSchemaDescription(schemaFields map[string]schema.SchemaField) string {
 	var builder strings.Builder
 
@@ -0,0 +1,67 @@
+package prompts
+
+import (
+	"fmt"
+	"strings"
+)
+
+// JSONRepairMarker - заголовок промпта исправления JSON (по нему mock-режим выбирает ответ)
+const JSONRepairMarker = "JSON REPAIR"
+
+// Разделы промпта исправления JSON: исходный текст стоит между ними
+const (
+	JSONRepairInputHeader  = "BROKEN JSON:"
+	JSONRepairOutputHeader = "FIXED JSON (only JSON):"
+)
+
+const jsonRepairPrompt = JSONRepairMarker + `
+The text below was meant to be a single JSON value but does not parse: %s
+
+Fix the syntax and return the same data as valid JSON:
+- Keep every key and value, do not add, translate or rephrase anything
+- If the text is cut off, drop the unfinished last key or element and close all brackets
+- Return ONLY valid JSON, without markdown and comments
+
+` + JSONRepairInputHeader + `
+%s
+
+` + JSONRepairOutputHeader
+
+// GenerateJSONRepairPrompt создает промпт исправления невалидного JSON из ответа модели
+func GenerateJSONRepairPrompt(brokenJSON string, parseErr error) string {
+	return fmt.Sprintf(jsonRepairPrompt, parseErr, brokenJSON)
+}
+
+// SchemaRepairMarker - заголовок промпта приведения профиля к JSON Schema (по нему mock-режим выбирает ответ)
+const SchemaRepairMarker = "SCHEMA REPAIR"
+
+// Разделы промпта приведения к схеме: профиль стоит между ними
+const (
+	SchemaRepairInputHeader  = "PROFILE:"
+	SchemaRepairOutputHeader = "FIXED PROFILE (only JSON):"
+)
+
+const schemaRepairPrompt = SchemaRepairMarker + `
+The profile JSON below does not match its JSON Schema.
+
+Violations:
+%s
+
+Fix only the listed fields so the profile matches the schema:
+- Convert values to the required type (a number instead of a string, a list instead of a single value)
+- If a value cannot be converted, replace it with null
+- Keep every other key and value as is, do not add, translate or rephrase anything
+- Return ONLY valid JSON, without markdown and comments
+
+JSON SCHEMA:
+%s
+
+` + SchemaRepairInputHeader + `
+%s
+
+` + SchemaRepairOutputHeader
+
+// GenerateSchemaRepairPrompt создает промпт исправления профиля, не прошедшего проверку по JSON Schema
+func GenerateSchemaRepairPrompt(profileJSON string, violations []string, jsonSchema string) string {
+	return fmt.Sprintf(schemaRepairPrompt, "- "+strings.Join(violations, "\n- "), jsonSchema, profileJSON)
+}
@@ -0,0 +1,38 @@
+package prompts
+
+import (
+	"strings"
+
+	"interview-bot-complete/internal/language"
+	"interview-bot-complete/internal/schema"
+)
+
+// ProvenanceKey - служебное поле ответа модели со ссылками на источники полей профиля
+const ProvenanceKey = "_provenance"
+
+const citationInstructionsRU = `
+
+ИСТОЧНИКИ:
+Каждый ответ в тексте интервью помечен меткой вида [B1.Q2] (блок 1, вопрос 2).
+Добавь в JSON служебное поле "_provenance": для каждого заполненного поля профиля укажи,
+откуда взята информация - метку ответа и ДОСЛОВНУЮ короткую цитату из этого ответа:
+"_provenance": {"hard_skills": [{"ref": "B2.Q1", "quote": "пишу на Python"}]}
+Цитата должна точно совпадать с фрагментом ответа. Не указывай источники для полей со значением null.`
+
+const citationInstructionsEN = `
+
+SOURCES:
+Every answer in the interview text is labeled like [B1.Q2] (block 1, question 2).
+Add a service field "_provenance" to the JSON: for every filled profile field give
+where the information came from - the answer label and a short VERBATIM quote from that answer:
+"_provenance": {"hard_skills": [{"ref": "B2.Q1", "quote": "I write Python"}]}
+The quote must match a fragment of the answer exactly. Do not cite fields whose value is null.`
+
+// generateV2ExtractionPrompt - промпт v1 с просьбой указать источники каждого поля
+func generateV2ExtractionPrompt(schemaFields map[string]schema.SchemaField, userText string, lang string) string {
+	prompt := generateV1ExtractionPrompt(schemaFields, userText, lang)
+	if lang == language.English {
+		return strings.Replace(prompt, "\n\nINTERVIEW TEXT:", citationInstructionsEN+"\n\nINTERVIEW TEXT:", 1)
+	}
+	return strings.Replace(prompt, "\n\nТЕКСТ ИНТЕРВЬЮ:", citationInstructionsRU+"\n\nТЕКСТ ИНТЕРВЬЮ:", 1)
+}
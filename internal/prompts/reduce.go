@@ -0,0 +1,59 @@
+package prompts
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"interview-bot-complete/internal/language"
+)
+
+// ProfileReduceMarker - заголовок промпта объединения частичных профилей (по нему mock-режим выбирает ответ)
+const ProfileReduceMarker = "PROFILE REDUCE"
+
+const profileReducePromptRU = ProfileReduceMarker + `
+Профиль человека извлекался из длинного интервью по частям. Для полей ниже части дали разные значения.
+Для каждого поля выбери одно итоговое значение.
+
+ПРАВИЛА:
+- Значения перечислены в порядке частей интервью; более поздние части обычно точнее и актуальнее
+- Если значения не противоречат друг другу, а дополняют, объедини их в одно значение
+- Сохраняй тип значения (строка, число, true/false)
+- Не придумывай значений, которых нет в списке
+- Пиши на русском языке
+
+ЗНАЧЕНИЯ ПОЛЕЙ ПО ЧАСТЯМ (JSON):
+%s
+
+Верни JSON вида {"поле": итоговое значение} с теми же полями.
+ОТВЕТ (только JSON):`
+
+const profileReducePromptEN = ProfileReduceMarker + `
+A person's profile was extracted from a long interview in parts. The parts gave different values for the fields below.
+Choose one final value for each field.
+
+RULES:
+- Values are listed in interview order; later parts are usually more precise and up to date
+- If the values complement rather than contradict each other, combine them into one value
+- Keep the value type (string, number, true/false)
+- Do not invent values that are not in the list
+- Write in English
+
+FIELD VALUES BY PART (JSON):
+%s
+
+Return JSON of the form {"field": final value} with the same fields.
+ANSWER (only JSON):`
+
+// GenerateProfileReducePrompt создает промпт выбора итоговых значений полей, по которым
+// частичные профили длинного интервью разошлись (поле - значения по частям)
+func GenerateProfileReducePrompt(conflicts map[string][]interface{}, lang string) (string, error) {
+	data, err := json.MarshalIndent(conflicts, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("ошибка сериализации значений полей: %w", err)
+	}
+	template := profileReducePromptRU
+	if lang == language.English {
+		template = profileReducePromptEN
+	}
+	return fmt.Sprintf(template, data), nil
+}
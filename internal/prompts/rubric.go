@@ -0,0 +1,85 @@
+package prompts
+
+import (
+	"fmt"
+
+	"interview-bot-complete/internal/language"
+)
+
+// RubricScoreMarker - заголовок промпта оценки кандидата по рубрике роли (по нему mock-режим выбирает ответ)
+const RubricScoreMarker = "RUBRIC SCORE"
+
+const rubricScorePromptRU = RubricScoreMarker + `
+Ты опытный рекрутер. Оцени кандидата на роль «%s» по каждой компетенции рубрики.
+
+КОМПЕТЕНЦИИ (формат: [id] название - описание; признаки):
+%s
+
+ШКАЛА: 1 - не проявлена или противоречит ответам, 2 - слабые признаки, 3 - на базовом уровне,
+4 - уверенно подтверждена примерами, 5 - выдающийся уровень с конкретными результатами.
+
+ВЕРНИ JSON СО СТРУКТУРОЙ:
+{
+  "competencies": [
+    {"id": "id компетенции, строго как в квадратных скобках", "score": 1-5, "evidence": "1-2 предложения: факты из ответов"}
+  ],
+  "summary": "2-4 предложения для рекрутера: насколько кандидат подходит на роль",
+  "concerns": ["риски и вопросы, которые стоит проверить на следующем этапе"]
+}
+
+ПРАВИЛА:
+- Оцени КАЖДУЮ компетенцию из списка, не добавляй новые
+- Без подтверждения в ответах ставь 1 или 2 и так и напиши в evidence
+- Не оценивай пол, возраст, внешность, семейное положение, здоровье и другие признаки, не относящиеся к работе
+- Пиши на русском языке
+- Верни ТОЛЬКО валидный JSON, без markdown и комментариев
+
+ПРОФИЛЬ (JSON):
+%s
+
+ТЕКСТ ИНТЕРВЬЮ:
+%s
+
+ОТВЕТ (только JSON):`
+
+const rubricScorePromptEN = RubricScoreMarker + `
+You are an experienced recruiter. Score the candidate for the "%s" role on every competency of the rubric.
+
+COMPETENCIES (format: [id] name - description; signs):
+%s
+
+SCALE: 1 - not shown or contradicted by the answers, 2 - weak signs, 3 - basic level,
+4 - confidently backed by examples, 5 - outstanding level with concrete results.
+
+RETURN JSON WITH THIS STRUCTURE:
+{
+  "competencies": [
+    {"id": "competency id, exactly as in square brackets", "score": 1-5, "evidence": "1-2 sentences: facts from the answers"}
+  ],
+  "summary": "2-4 sentences for the recruiter: how well the candidate fits the role",
+  "concerns": ["risks and questions to check at the next stage"]
+}
+
+RULES:
+- Score EVERY listed competency, do not add new ones
+- Without evidence in the answers give 1 or 2 and say so in evidence
+- Do not assess gender, age, appearance, family status, health or other traits unrelated to the job
+- Write in English
+- Return ONLY valid JSON, no markdown and no comments
+
+PROFILE (JSON):
+%s
+
+INTERVIEW TEXT:
+%s
+
+ANSWER (JSON only):`
+
+// GenerateRubricScorePrompt строит промпт оценки кандидата по компетенциям роли на языке lang.
+// competencies - перечень компетенций рубрики, по строке на компетенцию
+func GenerateRubricScorePrompt(role, competencies, profileJSON, userText, lang string) string {
+	if lang == language.English {
+		return fmt.Sprintf(rubricScorePromptEN, role, competencies, profileJSON, userText)
+	}
+	return fmt.Sprintf(rubricScorePromptRU, role, competencies, profileJSON, userText)
+}
@@ -0,0 +1,89 @@
+package prompts
+
+import (
+	"fmt"
+
+	"interview-bot-complete/internal/language"
+)
+
+// ProfileSummaryMarker - заголовок промпта резюме профиля (по нему mock-режим выбирает ответ)
+const ProfileSummaryMarker = "PROFILE SUMMARY"
+
+// Стили резюме профиля (/summary <стиль>)
+const (
+	SummaryStyleBullets   = "bullets"
+	SummaryStyleNarrative = "narrative"
+	SummaryStyleTable     = "table"
+)
+
+// SummaryStyles - поддерживаемые стили в порядке показа
+var SummaryStyles = []string{SummaryStyleBullets, SummaryStyleNarrative, SummaryStyleTable}
+
+// summaryStyleRulesRU и summaryStyleRulesEN - требования к формату для каждого стиля
+var summaryStyleRulesRU = map[string]string{
+	SummaryStyleBullets: `- Маркированный список из 6-10 пунктов, каждый пункт начинается с "• "
+- Один пункт - один факт: кто человек, образование, работа, навыки, интересы, цели`,
+	SummaryStyleNarrative: `- Связный текст из 2-3 абзацев от третьего лица
+- Без списков и заголовков`,
+	SummaryStyleTable: `- Таблица из двух колонок "Поле | Значение", по строке на поле
+- Не больше 12 строк, значения короткие (до 40 символов)
+- Колонки разделяй символом "|", без рамок из "-" и "+"`,
+}
+
+var summaryStyleRulesEN = map[string]string{
+	SummaryStyleBullets: `- A bulleted list of 6-10 items, each item starts with "• "
+- One item - one fact: who the person is, education, work, skills, interests, goals`,
+	SummaryStyleNarrative: `- Coherent text of 2-3 paragraphs in the third person
+- No lists and no headings`,
+	SummaryStyleTable: `- A two-column table "Field | Value", one row per field
+- At most 12 rows, short values (up to 40 characters)
+- Separate columns with "|", no borders made of "-" and "+"`,
+}
+
+const profileSummaryPromptRU = ProfileSummaryMarker + `
+Составь краткое резюме профиля человека для отправки в Telegram.
+
+ФОРМАТ:
+%s
+
+ПРАВИЛА:
+- Используй только данные профиля, пропускай пустые поля
+- Не используй markdown-разметку (*, _, #, обратные кавычки)
+- Пиши на русском языке
+- Верни JSON вида {"summary": "текст резюме"}
+
+ПРОФИЛЬ (JSON):
+%s
+
+ОТВЕТ (только JSON):`
+
+const profileSummaryPromptEN = ProfileSummaryMarker + `
+Write a short summary of the person's profile to be sent in Telegram.
+
+FORMAT:
+%s
+
+RULES:
+- Use only the profile data, skip empty fields
+- Do not use markdown (*, _, #, backticks)
+- Write in English
+- Return JSON like {"summary": "summary text"}
+
+PROFILE (JSON):
+%s
+
+ANSWER (JSON only):`
+
+// IsSummaryStyle проверяет, поддерживается ли стиль резюме
+func IsSummaryStyle(style string) bool {
+	_, ok := summaryStyleRulesRU[style]
+	return ok
+}
+
+// GenerateProfileSummaryPrompt строит промпт резюме профиля в стиле style на языке lang
+func GenerateProfileSummaryPrompt(profileJSON, style, lang string) string {
+	if lang == language.English {
+		return fmt.Sprintf(profileSummaryPromptEN, summaryStyleRulesEN[style], profileJSON)
+	}
+	return fmt.Sprintf(profileSummaryPromptRU, summaryStyleRulesRU[style], profileJSON)
+}
@@ -0,0 +1,215 @@
+package prompts
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"interview-bot-complete/internal/language"
+)
+
+// templateExtension - расширение файлов шаблонов промптов извлечения
+const templateExtension = ".tmpl"
+
+// templateNamePattern - имя файла шаблона: <версия>.tmpl для всех языков или <версия>.<язык>.tmpl
+var templateNamePattern = regexp.MustCompile(`^([A-Za-z0-9_-]{1,32})(?:\.([a-z]{2}))?\.tmpl$`)
+
+// TemplateData - переменные шаблона промпта извлечения
+type TemplateData struct {
+	Schema        string // поля профиля для заполнения
+	InterviewText string // текст интервью; если шаблон использует Citations, ответы помечены [B<блок>.Q<вопрос>]
+	Language      string // язык ответов пользователя (ru, en)
+	Citations     string // инструкции о ссылках на источники полей на языке ответов
+}
+
+// fileTemplate - шаблон промпта извлечения, загруженный с диска
+type fileTemplate struct {
+	template *template.Template
+	name     string // имя файла
+	checksum string // первые символы SHA-256 текста: правка файла меняет ревизию промпта
+	// citations - шаблон использует Citations, текст интервью размечается метками ответов
+	citations bool
+}
+
+// revision возвращает ревизию шаблона для метаданных профиля
+func (t *fileTemplate) revision() string {
+	return t.name + "@" + t.checksum
+}
+
+// templates - шаблоны с диска по версии и языку ("" - для всех языков). Файл с именем
+// встроенной версии (v1, v2) заменяет ее.
+var templates = struct {
+	sync.RWMutex
+	dir       string
+	signature string
+	versions  map[string]map[string]*fileTemplate
+}{}
+
+// LoadTemplates загружает шаблоны промптов извлечения из каталога dir. Отсутствующий каталог
+// означает только встроенные промпты. При ошибке в любом файле остаются прежние шаблоны.
+func LoadTemplates(dir string) error {
+	signature, err := templatesSignature(dir)
+	if err != nil {
+		return err
+	}
+	versions, err := parseTemplates(dir)
+	if err != nil {
+		return err
+	}
+
+	templates.Lock()
+	defer templates.Unlock()
+	templates.dir = dir
+	templates.signature = signature
+	templates.versions = versions
+	return nil
+}
+
+// WatchTemplates раз в interval проверяет каталог шаблонов и перезагружает его при изменении файлов
+func WatchTemplates(interval time.Duration, logger *slog.Logger) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		for range ticker.C {
+			templates.RLock()
+			dir, previous := templates.dir, templates.signature
+			templates.RUnlock()
+
+			signature, err := templatesSignature(dir)
+			if err != nil || signature == previous {
+				continue
+			}
+			if err := LoadTemplates(dir); err != nil {
+				logger.Warn("Шаблоны промптов не перезагружены, используются прежние", "dir", dir, "error", err)
+				// Ошибочные файлы не перечитываются, пока их снова не изменят
+				templates.Lock()
+				templates.signature = signature
+				templates.Unlock()
+				continue
+			}
+			logger.Info("Шаблоны промптов перезагружены", "dir", dir, "versions", strings.Join(TemplateVersions(), ","))
+		}
+	}()
+}
+
+// TemplateVersions возвращает версии промпта, заданные шаблонами на диске
+func TemplateVersions() []string {
+	templates.RLock()
+	defer templates.RUnlock()
+	versions := make([]string, 0, len(templates.versions))
+	for version := range templates.versions {
+		versions = append(versions, version)
+	}
+	sort.Strings(versions)
+	return versions
+}
+
+// templatesSignature описывает файлы шаблонов (имя, размер, время изменения) для обнаружения правок
+func templatesSignature(dir string) (string, error) {
+	files, err := templateFiles(dir)
+	if err != nil {
+		return "", err
+	}
+	var signature strings.Builder
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return "", err
+		}
+		signature.WriteString(fmt.Sprintf("%s:%d:%d;", filepath.Base(file), info.Size(), info.ModTime().UnixNano()))
+	}
+	return signature.String(), nil
+}
+
+// templateFiles возвращает файлы шаблонов каталога по алфавиту
+func templateFiles(dir string) ([]string, error) {
+	if dir == "" {
+		return nil, nil
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*"+templateExtension))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка поиска шаблонов промптов в %s: %w", dir, err)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// parseTemplates разбирает шаблоны каталога и проверяет их на пустых данных
+func parseTemplates(dir string) (map[string]map[string]*fileTemplate, error) {
+	files, err := templateFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make(map[string]map[string]*fileTemplate)
+	for _, file := range files {
+		name := filepath.Base(file)
+		match := templateNamePattern.FindStringSubmatch(name)
+		if match == nil {
+			return nil, fmt.Errorf("недопустимое имя шаблона промпта %s: ожидается <версия>%s или <версия>.<язык>%s",
+				name, templateExtension, templateExtension)
+		}
+		version, lang := match[1], match[2]
+
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка чтения шаблона промпта %s: %w", file, err)
+		}
+		tmpl, err := template.New(name).Option("missingkey=error").Parse(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("ошибка разбора шаблона промпта %s: %w", name, err)
+		}
+		if err := tmpl.Execute(&bytes.Buffer{}, TemplateData{}); err != nil {
+			return nil, fmt.Errorf("ошибка в шаблоне промпта %s: %w", name, err)
+		}
+
+		sum := sha256.Sum256(data)
+		if versions[version] == nil {
+			versions[version] = make(map[string]*fileTemplate)
+		}
+		versions[version][lang] = &fileTemplate{
+			template:  tmpl,
+			name:      name,
+			checksum:  hex.EncodeToString(sum[:])[:12],
+			citations: strings.Contains(string(data), ".Citations"),
+		}
+	}
+	return versions, nil
+}
+
+// lookupTemplate возвращает шаблон версии для языка ответов (nil - шаблона на диске нет)
+func lookupTemplate(version, lang string) *fileTemplate {
+	templates.RLock()
+	defer templates.RUnlock()
+	byLanguage := templates.versions[version]
+	if tmpl, ok := byLanguage[lang]; ok {
+		return tmpl
+	}
+	return byLanguage[""]
+}
+
+// render строит промпт по шаблону
+func (t *fileTemplate) render(data TemplateData) (string, error) {
+	var prompt bytes.Buffer
+	if err := t.template.Execute(&prompt, data); err != nil {
+		return "", fmt.Errorf("ошибка шаблона промпта %s: %w", t.name, err)
+	}
+	return prompt.String(), nil
+}
+
+// citationInstructions возвращает инструкции о ссылках на источники на языке ответов
+func citationInstructions(lang string) string {
+	if lang == language.English {
+		return strings.TrimSpace(citationInstructionsEN)
+	}
+	return strings.TrimSpace(citationInstructionsRU)
+}
@@ -0,0 +1,78 @@
+package ratelimit
+
+import (
+	"interview-bot-complete/internal/api"
+	"sync"
+	"time"
+)
+
+// TokenBudget - дневной бюджет токенов OpenAI на все запросы бота: локальный (DailyBudget)
+// или общий для нескольких реплик (RedisBudget). Сутки считаются по UTC.
+type TokenBudget interface {
+	api.UsageRecorder
+	Used() int
+	Limit() int
+	Exhausted() bool
+	ResetIn() time.Duration
+}
+
+// DailyBudget - дневной бюджет токенов в памяти процесса
+type DailyBudget struct {
+	limit int
+
+	mutex sync.Mutex
+	day   string
+	used  int
+}
+
+// NewDailyBudget создает бюджет limit токенов в сутки; limit <= 0 отключает ограничение
+func NewDailyBudget(limit int) *DailyBudget {
+	return &DailyBudget{limit: limit}
+}
+
+// RecordUsage добавляет израсходованные токены к расходу текущих суток
+func (b *DailyBudget) RecordUsage(model string, usage api.Usage) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.rollover(time.Now())
+	b.used += usage.TotalTokens
+}
+
+// Used возвращает число токенов, израсходованных за текущие сутки
+func (b *DailyBudget) Used() int {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.rollover(time.Now())
+	return b.used
+}
+
+// Limit возвращает дневной лимит токенов (0 - без ограничения)
+func (b *DailyBudget) Limit() int {
+	return max(b.limit, 0)
+}
+
+// Exhausted сообщает, что дневной бюджет израсходован
+func (b *DailyBudget) Exhausted() bool {
+	return b.limit > 0 && b.Used() >= b.limit
+}
+
+// ResetIn возвращает время до начала следующих суток (UTC)
+func (b *DailyBudget) ResetIn() time.Duration {
+	return untilNextDay(time.Now())
+}
+
+func (b *DailyBudget) rollover(now time.Time) {
+	if day := budgetDay(now); day != b.day {
+		b.day, b.used = day, 0
+	}
+}
+
+// budgetDay возвращает сутки бюджета в формате YYYY-MM-DD (UTC)
+func budgetDay(now time.Time) string {
+	return now.UTC().Format(time.DateOnly)
+}
+
+func untilNextDay(now time.Time) time.Duration {
+	now = now.UTC()
+	return now.Truncate(24 * time.Hour).Add(24 * time.Hour).Sub(now)
+}
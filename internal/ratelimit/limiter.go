@@ -0,0 +1,192 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Config задает параметры лимитера. Нулевые значения отключают соответствующий лимит.
+type Config struct {
+	PerUserPerMinute int           // скорость пополнения токенов пользователя
+	PerUserBurst     int           // максимальный запас токенов пользователя
+	GlobalPerMinute  int           // общий лимит на всех пользователей
+	GlobalBurst      int           // максимальный общий запас токенов
+	IdleTTL          time.Duration // время неактивности, после которого бакет пользователя удаляется
+}
+
+// bucket реализует алгоритм token bucket
+type bucket struct {
+	rate     float64 // токенов в секунду
+	burst    float64
+	tokens   float64
+	last     time.Time
+	lastUsed time.Time
+}
+
+func newBucket(perMinute, burst int, now time.Time) *bucket {
+	if burst <= 0 {
+		burst = perMinute
+	}
+	return &bucket{
+		rate:     float64(perMinute) / 60,
+		burst:    float64(burst),
+		tokens:   float64(burst),
+		last:     now,
+		lastUsed: now,
+	}
+}
+
+// refill пополняет бакет с учетом прошедшего времени
+func (b *bucket) refill(now time.Time) {
+	elapsed := now.Sub(b.last).Seconds()
+	if elapsed > 0 {
+		b.tokens = math.Min(b.burst, b.tokens+elapsed*b.rate)
+		b.last = now
+	}
+}
+
+// delay возвращает время ожидания до появления одного токена
+func (b *bucket) delay(now time.Time) time.Duration {
+	b.refill(now)
+	if b.tokens >= 1 {
+		return 0
+	}
+	if b.rate <= 0 {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
+
+func (b *bucket) take(now time.Time) {
+	b.tokens--
+	b.lastUsed = now
+}
+
+// RateLimiter - лимитер частоты действий: локальный (Limiter) или общий для нескольких реплик (RedisLimiter)
+type RateLimiter interface {
+	Allow(userID int64) bool
+	Wait(ctx context.Context, userID int64) error
+}
+
+// Limiter ограничивает частоту действий по пользователям и глобально
+type Limiter struct {
+	mutex   sync.Mutex
+	config  Config
+	users   map[int64]*bucket
+	global  *bucket
+	nowFunc func() time.Time
+}
+
+// New создает новый лимитер
+func New(cfg Config) *Limiter {
+	l := &Limiter{
+		config:  cfg,
+		users:   make(map[int64]*bucket),
+		nowFunc: time.Now,
+	}
+	if cfg.GlobalPerMinute > 0 {
+		l.global = newBucket(cfg.GlobalPerMinute, cfg.GlobalBurst, l.nowFunc())
+	}
+	return l
+}
+
+// Allow пытается взять токен для пользователя без ожидания
+func (l *Limiter) Allow(userID int64) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return l.reserve(userID) == 0
+}
+
+// Wait ждет, пока для пользователя не появится токен, или отмены контекста
+func (l *Limiter) Wait(ctx context.Context, userID int64) error {
+	for {
+		l.mutex.Lock()
+		wait := l.reserve(userID)
+		l.mutex.Unlock()
+
+		if wait == 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// reserve забирает токен из пользовательского и глобального бакетов,
+// если он есть в обоих; иначе возвращает время ожидания
+func (l *Limiter) reserve(userID int64) time.Duration {
+	now := l.nowFunc()
+
+	var user *bucket
+	if l.config.PerUserPerMinute > 0 {
+		user = l.users[userID]
+		if user == nil {
+			user = newBucket(l.config.PerUserPerMinute, l.config.PerUserBurst, now)
+			l.users[userID] = user
+		}
+	}
+
+	var wait time.Duration
+	if user != nil {
+		wait = user.delay(now)
+	}
+	if l.global != nil {
+		if globalWait := l.global.delay(now); globalWait > wait {
+			wait = globalWait
+		}
+	}
+	if wait > 0 {
+		return wait
+	}
+
+	if user != nil {
+		user.take(now)
+	}
+	if l.global != nil {
+		l.global.take(now)
+	}
+	return 0
+}
+
+// Cleanup удаляет бакеты пользователей, неактивных дольше IdleTTL
+func (l *Limiter) Cleanup() int {
+	if l.config.IdleTTL <= 0 {
+		return 0
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	cutoff := l.nowFunc().Add(-l.config.IdleTTL)
+	removed := 0
+	for userID, b := range l.users {
+		if b.lastUsed.Before(cutoff) {
+			delete(l.users, userID)
+			removed++
+		}
+	}
+	return removed
+}
+
+// StartCleanup периодически удаляет неактивных пользователей
+func (l *Limiter) StartCleanup(interval time.Duration) {
+	if interval <= 0 || l.config.IdleTTL <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	go func() {
+		for range ticker.C {
+			l.Cleanup()
+		}
+	}()
+}
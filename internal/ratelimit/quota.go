@@ -0,0 +1,102 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// UserQuota - квота действий пользователя: локальная (Quota) или общая для нескольких реплик (RedisQuota)
+type UserQuota interface {
+	Use(userID int64) (bool, int)
+	Refund(userID int64)
+	ResetIn(userID int64) time.Duration
+}
+
+// Quota ограничивает число действий пользователя за фиксированное окно (например, сутки)
+type Quota struct {
+	limit  int
+	window time.Duration
+
+	mutex sync.Mutex
+	usage map[int64]*quotaUsage
+	// lastSweep - когда последний раз удалялись истекшие окна (sweep)
+	lastSweep time.Time
+}
+
+type quotaUsage struct {
+	windowStart time.Time
+	used        int
+}
+
+// NewQuota создает квоту limit действий за window; limit <= 0 отключает ограничение
+func NewQuota(limit int, window time.Duration) *Quota {
+	return &Quota{
+		limit:  limit,
+		window: window,
+		usage:  make(map[int64]*quotaUsage),
+	}
+}
+
+// Use расходует одно действие пользователя. Возвращает false, если квота исчерпана,
+// и число оставшихся действий в текущем окне (-1 при отключенной квоте).
+func (q *Quota) Use(userID int64) (bool, int) {
+	if q.limit <= 0 {
+		return true, -1
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	now := time.Now()
+	q.sweep(now)
+	usage, ok := q.usage[userID]
+	if !ok || now.Sub(usage.windowStart) >= q.window {
+		usage = &quotaUsage{windowStart: now}
+		q.usage[userID] = usage
+	}
+
+	if usage.used >= q.limit {
+		return false, 0
+	}
+	usage.used++
+	return true, q.limit - usage.used
+}
+
+// sweep не чаще раза за окно удаляет истекшие окна пользователей, чтобы карта не росла
+// с числом всех пользователей за время работы бота
+func (q *Quota) sweep(now time.Time) {
+	if now.Sub(q.lastSweep) < q.window {
+		return
+	}
+	q.lastSweep = now
+	for userID, usage := range q.usage {
+		if now.Sub(usage.windowStart) >= q.window {
+			delete(q.usage, userID)
+		}
+	}
+}
+
+// Refund возвращает действие, если оно не было выполнено (например, из-за ошибки API)
+func (q *Quota) Refund(userID int64) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if usage, ok := q.usage[userID]; ok && usage.used > 0 {
+		usage.used--
+	}
+}
+
+// ResetIn возвращает время до обновления квоты пользователя
+func (q *Quota) ResetIn(userID int64) time.Duration {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	usage, ok := q.usage[userID]
+	if !ok {
+		return 0
+	}
+	if remaining := q.window - time.Since(usage.windowStart); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
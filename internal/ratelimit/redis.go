@@ -0,0 +1,376 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"interview-bot-complete/internal/api"
+	"interview-bot-complete/internal/redis"
+	"log/slog"
+	"strconv"
+	"time"
+)
+
+// redisRequestTimeout - таймаут одного обращения к Redis из лимитеров
+const redisRequestTimeout = 2 * time.Second
+
+// defaultRedisBucketTTL - время жизни бакета в Redis, если IdleTTL не задан
+const defaultRedisBucketTTL = time.Hour
+
+// tokenBucketScript атомарно пополняет пользовательский и глобальный бакеты
+// и забирает по токену, если он есть в обоих; иначе возвращает ожидание в мс.
+// ARGV: now_ms, user_rate, user_burst, global_rate, global_burst (rate - токенов в мс), ttl_ms.
+const tokenBucketScript = `
+local now = tonumber(ARGV[1])
+local ttl = tonumber(ARGV[6])
+
+local function load(key, rate, burst)
+  if rate <= 0 then return nil end
+  local data = redis.call('HMGET', key, 'tokens', 'last')
+  local tokens = tonumber(data[1]) or burst
+  local last = tonumber(data[2]) or now
+  if now > last then
+    tokens = math.min(burst, tokens + (now - last) * rate)
+    last = now
+  end
+  return {tokens, last}
+end
+
+local function delay(state, rate)
+  if state == nil or state[1] >= 1 then return 0 end
+  return math.ceil((1 - state[1]) / rate)
+end
+
+local userRate, globalRate = tonumber(ARGV[2]), tonumber(ARGV[4])
+local user = load(KEYS[1], userRate, tonumber(ARGV[3]))
+local global = load(KEYS[2], globalRate, tonumber(ARGV[5]))
+
+local wait = math.max(delay(user, userRate), delay(global, globalRate))
+if wait > 0 then return wait end
+
+if user then
+  redis.call('HSET', KEYS[1], 'tokens', tostring(user[1] - 1), 'last', tostring(user[2]))
+  redis.call('PEXPIRE', KEYS[1], ttl)
+end
+if global then
+  redis.call('HSET', KEYS[2], 'tokens', tostring(global[1] - 1), 'last', tostring(global[2]))
+  redis.call('PEXPIRE', KEYS[2], ttl)
+end
+return 0
+`
+
+// RedisLimiter - token bucket в Redis, общий для всех реплик бота.
+// При недоступности Redis действия пропускаются (ошибка пишется в лог).
+type RedisLimiter struct {
+	client *redis.Client
+	prefix string
+	config Config
+}
+
+// NewRedis создает лимитер с ключами вида <prefix>:user:<id> и <prefix>:global
+func NewRedis(client *redis.Client, prefix string, cfg Config) *RedisLimiter {
+	return &RedisLimiter{client: client, prefix: prefix, config: cfg}
+}
+
+// Allow пытается взять токен для пользователя без ожидания
+func (l *RedisLimiter) Allow(userID int64) bool {
+	wait, err := l.reserve(context.Background(), userID)
+	if err != nil {
+		slog.Warn("Лимитер: Redis недоступен, запрос пропущен", "limiter", l.prefix, "error", err)
+		return true
+	}
+	return wait == 0
+}
+
+// Wait ждет, пока для пользователя не появится токен, или отмены контекста
+func (l *RedisLimiter) Wait(ctx context.Context, userID int64) error {
+	for {
+		wait, err := l.reserve(ctx, userID)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			slog.Warn("Лимитер: Redis недоступен, запрос пропущен", "limiter", l.prefix, "error", err)
+			return nil
+		}
+		if wait == 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// reserve выполняет скрипт token bucket и возвращает время ожидания
+func (l *RedisLimiter) reserve(ctx context.Context, userID int64) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, redisRequestTimeout)
+	defer cancel()
+
+	ttl := l.config.IdleTTL
+	if ttl <= 0 {
+		ttl = defaultRedisBucketTTL
+	}
+
+	reply, err := l.client.Eval(ctx, tokenBucketScript,
+		[]string{fmt.Sprintf("%s:user:%d", l.prefix, userID), l.prefix + ":global"},
+		strconv.FormatInt(time.Now().UnixMilli(), 10),
+		formatRate(l.config.PerUserPerMinute), strconv.Itoa(burstOrRate(l.config.PerUserBurst, l.config.PerUserPerMinute)),
+		formatRate(l.config.GlobalPerMinute), strconv.Itoa(burstOrRate(l.config.GlobalBurst, l.config.GlobalPerMinute)),
+		strconv.FormatInt(ttl.Milliseconds(), 10),
+	)
+	if err != nil {
+		return 0, err
+	}
+	waitMs, ok := reply.(int64)
+	if !ok {
+		return 0, fmt.Errorf("неожиданный ответ Redis: %T", reply)
+	}
+	return time.Duration(waitMs) * time.Millisecond, nil
+}
+
+// formatRate переводит лимит в минуту в токены на миллисекунду
+func formatRate(perMinute int) string {
+	if perMinute <= 0 {
+		return "0"
+	}
+	return strconv.FormatFloat(float64(perMinute)/60000, 'g', -1, 64)
+}
+
+func burstOrRate(burst, perMinute int) int {
+	if burst <= 0 {
+		return perMinute
+	}
+	return burst
+}
+
+// quotaUseScript увеличивает счетчик окна и откатывает его при превышении лимита.
+// Возвращает остаток действий или -1, если квота исчерпана.
+const quotaUseScript = `
+local used = redis.call('INCR', KEYS[1])
+if used == 1 then redis.call('PEXPIRE', KEYS[1], ARGV[2]) end
+local limit = tonumber(ARGV[1])
+if used > limit then
+  redis.call('DECR', KEYS[1])
+  return -1
+end
+return limit - used
+`
+
+// quotaRefundScript уменьшает счетчик, не опуская его ниже нуля
+const quotaRefundScript = `
+local used = tonumber(redis.call('GET', KEYS[1]) or '0')
+if used > 0 then redis.call('DECR', KEYS[1]) end
+return 0
+`
+
+// RedisQuota - квота действий за окно, общая для всех реплик бота.
+// При недоступности Redis действия разрешаются (ошибка пишется в лог).
+type RedisQuota struct {
+	client *redis.Client
+	prefix string
+	limit  int
+	window time.Duration
+}
+
+// NewRedisQuota создает квоту limit действий за window; limit <= 0 отключает ограничение
+func NewRedisQuota(client *redis.Client, prefix string, limit int, window time.Duration) *RedisQuota {
+	return &RedisQuota{client: client, prefix: prefix, limit: limit, window: window}
+}
+
+func (q *RedisQuota) key(userID int64) string {
+	return fmt.Sprintf("%s:%d", q.prefix, userID)
+}
+
+// Use расходует одно действие пользователя (семантика как у Quota.Use)
+func (q *RedisQuota) Use(userID int64) (bool, int) {
+	if q.limit <= 0 {
+		return true, -1
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), redisRequestTimeout)
+	defer cancel()
+
+	reply, err := q.client.Eval(ctx, quotaUseScript, []string{q.key(userID)},
+		strconv.Itoa(q.limit), strconv.FormatInt(q.window.Milliseconds(), 10))
+	remaining, ok := reply.(int64)
+	if err != nil || !ok {
+		slog.Warn("Квота: Redis недоступен, действие разрешено", "quota", q.prefix, "error", err)
+		return true, -1
+	}
+	if remaining < 0 {
+		return false, 0
+	}
+	return true, int(remaining)
+}
+
+// Refund возвращает действие, если оно не было выполнено
+func (q *RedisQuota) Refund(userID int64) {
+	if q.limit <= 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), redisRequestTimeout)
+	defer cancel()
+
+	if _, err := q.client.Eval(ctx, quotaRefundScript, []string{q.key(userID)}); err != nil {
+		slog.Warn("Квота: не удалось вернуть действие", "quota", q.prefix, "error", err)
+	}
+}
+
+// ResetIn возвращает время до обновления квоты пользователя
+func (q *RedisQuota) ResetIn(userID int64) time.Duration {
+	ctx, cancel := context.WithTimeout(context.Background(), redisRequestTimeout)
+	defer cancel()
+
+	reply, err := q.client.Do(ctx, "PTTL", q.key(userID))
+	ttl, ok := reply.(int64)
+	if err != nil || !ok || ttl < 0 {
+		return 0
+	}
+	return time.Duration(ttl) * time.Millisecond
+}
+
+// budgetKeyTTL - время жизни счетчика суток бюджета в Redis
+const budgetKeyTTL = 48 * time.Hour
+
+// RedisBudget - дневной бюджет токенов, общий для всех реплик бота.
+// При недоступности Redis расход не учитывается, а бюджет считается неисчерпанным.
+type RedisBudget struct {
+	client *redis.Client
+	prefix string
+	limit  int
+}
+
+// NewRedisBudget создает бюджет limit токенов в сутки с ключами вида <prefix>:<YYYY-MM-DD>
+func NewRedisBudget(client *redis.Client, prefix string, limit int) *RedisBudget {
+	return &RedisBudget{client: client, prefix: prefix, limit: limit}
+}
+
+func (b *RedisBudget) key(now time.Time) string {
+	return b.prefix + ":" + budgetDay(now)
+}
+
+// RecordUsage добавляет израсходованные токены к расходу текущих суток
+func (b *RedisBudget) RecordUsage(model string, usage api.Usage) {
+	if usage.TotalTokens <= 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), redisRequestTimeout)
+	defer cancel()
+
+	key := b.key(time.Now())
+	if _, err := b.client.Do(ctx, "INCRBY", key, strconv.Itoa(usage.TotalTokens)); err != nil {
+		slog.Warn("Бюджет токенов: Redis недоступен, расход не учтен", "budget", b.prefix, "tokens", usage.TotalTokens, "error", err)
+		return
+	}
+	b.client.Do(ctx, "PEXPIRE", key, strconv.FormatInt(budgetKeyTTL.Milliseconds(), 10))
+}
+
+// Used возвращает число токенов, израсходованных за текущие сутки
+func (b *RedisBudget) Used() int {
+	ctx, cancel := context.WithTimeout(context.Background(), redisRequestTimeout)
+	defer cancel()
+
+	value, err := b.client.Get(ctx, b.key(time.Now()))
+	if err != nil {
+		if !errors.Is(err, redis.ErrNil) {
+			slog.Warn("Бюджет токенов: Redis недоступен", "budget", b.prefix, "error", err)
+		}
+		return 0
+	}
+	used, _ := strconv.Atoi(value)
+	return used
+}
+
+// Limit возвращает дневной лимит токенов (0 - без ограничения)
+func (b *RedisBudget) Limit() int {
+	return max(b.limit, 0)
+}
+
+// Exhausted сообщает, что дневной бюджет израсходован
+func (b *RedisBudget) Exhausted() bool {
+	return b.limit > 0 && b.Used() >= b.limit
+}
+
+// ResetIn возвращает время до начала следующих суток (UTC)
+func (b *RedisBudget) ResetIn() time.Duration {
+	return untilNextDay(time.Now())
+}
+
+// slotsAcquireScript удаляет просроченные слоты и занимает слот ARGV[1], если есть свободный.
+// ARGV: id, now_ms, ttl_ms, limit. Возвращает 1, если слот занят, иначе 0.
+const slotsAcquireScript = `
+local now, ttl, limit = tonumber(ARGV[2]), tonumber(ARGV[3]), tonumber(ARGV[4])
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - ttl)
+if not redis.call('ZSCORE', KEYS[1], ARGV[1]) and limit > 0 and redis.call('ZCARD', KEYS[1]) >= limit then
+  return 0
+end
+redis.call('ZADD', KEYS[1], now, ARGV[1])
+redis.call('PEXPIRE', KEYS[1], ttl)
+return 1
+`
+
+// RedisSlots - ограничение одновременных интервью, общее для всех реплик бота.
+// При недоступности Redis интервью разрешаются (ошибка пишется в лог).
+type RedisSlots struct {
+	client *redis.Client
+	key    string
+	limit  int
+	ttl    time.Duration
+}
+
+// NewRedisSlots создает ограничение limit одновременных интервью в отсортированном множестве key
+func NewRedisSlots(client *redis.Client, key string, limit int, ttl time.Duration) *RedisSlots {
+	return &RedisSlots{client: client, key: key, limit: limit, ttl: ttl}
+}
+
+// Acquire занимает слот id (семантика как у Slots.Acquire)
+func (s *RedisSlots) Acquire(id string) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), redisRequestTimeout)
+	defer cancel()
+
+	reply, err := s.client.Eval(ctx, slotsAcquireScript, []string{s.key}, id,
+		strconv.FormatInt(time.Now().UnixMilli(), 10), strconv.FormatInt(s.ttl.Milliseconds(), 10), strconv.Itoa(s.limit))
+	acquired, ok := reply.(int64)
+	if err != nil || !ok {
+		slog.Warn("Слоты интервью: Redis недоступен, интервью разрешено", "slots", s.key, "error", err)
+		return true
+	}
+	return acquired == 1
+}
+
+// Release освобождает слот id
+func (s *RedisSlots) Release(id string) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisRequestTimeout)
+	defer cancel()
+
+	if _, err := s.client.Do(ctx, "ZREM", s.key, id); err != nil {
+		slog.Warn("Слоты интервью: не удалось освободить слот", "slots", s.key, "error", err)
+	}
+}
+
+// Active возвращает число занятых слотов
+func (s *RedisSlots) Active() int {
+	ctx, cancel := context.WithTimeout(context.Background(), redisRequestTimeout)
+	defer cancel()
+
+	since := time.Now().Add(-s.ttl).UnixMilli()
+	reply, err := s.client.Do(ctx, "ZCOUNT", s.key, strconv.FormatInt(since, 10), "+inf")
+	count, ok := reply.(int64)
+	if err != nil || !ok {
+		return 0
+	}
+	return int(count)
+}
+
+// Limit возвращает максимальное число слотов (0 - без ограничения)
+func (s *RedisSlots) Limit() int {
+	return max(s.limit, 0)
+}
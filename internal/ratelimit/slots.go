@@ -0,0 +1,72 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// ActiveSlots ограничивает число одновременно идущих интервью: локально (Slots)
+// или для всех реплик (RedisSlots). Занятые дольше ttl слоты освобождаются сами.
+type ActiveSlots interface {
+	Acquire(id string) bool
+	Release(id string)
+	Active() int
+	Limit() int
+}
+
+// Slots - ограничение одновременных интервью в памяти процесса
+type Slots struct {
+	limit int
+	ttl   time.Duration
+
+	mutex  sync.Mutex
+	active map[string]time.Time
+}
+
+// NewSlots создает ограничение limit одновременных интервью; limit <= 0 - без ограничения
+func NewSlots(limit int, ttl time.Duration) *Slots {
+	return &Slots{limit: limit, ttl: ttl, active: make(map[string]time.Time)}
+}
+
+// Acquire занимает слот id. Повторный вызов с тем же id продлевает слот.
+// Возвращает false, если все слоты заняты.
+func (s *Slots) Acquire(id string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	s.expire(now)
+	if _, ok := s.active[id]; !ok && s.limit > 0 && len(s.active) >= s.limit {
+		return false
+	}
+	s.active[id] = now
+	return true
+}
+
+// Release освобождает слот id
+func (s *Slots) Release(id string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.active, id)
+}
+
+// Active возвращает число занятых слотов
+func (s *Slots) Active() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.expire(time.Now())
+	return len(s.active)
+}
+
+// Limit возвращает максимальное число слотов (0 - без ограничения)
+func (s *Slots) Limit() int {
+	return max(s.limit, 0)
+}
+
+func (s *Slots) expire(now time.Time) {
+	for id, acquired := range s.active {
+		if now.Sub(acquired) > s.ttl {
+			delete(s.active, id)
+		}
+	}
+}
@@ -0,0 +1,281 @@
+package redis
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNil возвращается, когда ключ не найден (nil-ответ Redis)
+var ErrNil = errors.New("redis: nil")
+
+// Error - ошибка, которую вернул сам сервер Redis
+type Error string
+
+func (e Error) Error() string { return "redis: " + string(e) }
+
+const (
+	defaultPoolSize = 10
+	dialTimeout     = 5 * time.Second
+	ioTimeout       = 5 * time.Second
+)
+
+// Client - минимальный клиент Redis (протокол RESP2) с пулом соединений
+type Client struct {
+	addr     string
+	username string
+	password string
+	db       int
+	useTLS   bool
+	pool     chan *conn
+}
+
+type conn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+// NewClient создает клиент по URL вида redis://[user:password@]host:port/db
+// (rediss:// - с TLS). Соединения устанавливаются при первом запросе.
+func NewClient(rawURL string) (*Client, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("некорректный REDIS_URL: %w", err)
+	}
+	if u.Scheme != "redis" && u.Scheme != "rediss" {
+		return nil, fmt.Errorf("некорректная схема REDIS_URL %q: ожидается redis:// или rediss://", u.Scheme)
+	}
+
+	c := &Client{
+		addr:   u.Host,
+		useTLS: u.Scheme == "rediss",
+		pool:   make(chan *conn, defaultPoolSize),
+	}
+	if u.Port() == "" {
+		c.addr = net.JoinHostPort(u.Hostname(), "6379")
+	}
+	if u.User != nil {
+		c.username = u.User.Username()
+		c.password, _ = u.User.Password()
+	}
+	if db := strings.Trim(u.Path, "/"); db != "" {
+		if c.db, err = strconv.Atoi(db); err != nil {
+			return nil, fmt.Errorf("некорректный номер базы в REDIS_URL: %q", db)
+		}
+	}
+	return c, nil
+}
+
+// Do выполняет команду и возвращает ответ: string, int64, []interface{} или nil
+func (c *Client) Do(ctx context.Context, args ...string) (interface{}, error) {
+	cn, err := c.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := cn.roundTrip(ctx, args)
+	var serverErr Error
+	if err != nil && !errors.As(err, &serverErr) {
+		// Сетевая ошибка или нарушение протокола - соединение больше не используем
+		cn.Close()
+		return nil, err
+	}
+	c.put(cn)
+	return reply, err
+}
+
+// Ping проверяет доступность сервера
+func (c *Client) Ping(ctx context.Context) error {
+	_, err := c.Do(ctx, "PING")
+	return err
+}
+
+// Get возвращает значение ключа или ErrNil
+func (c *Client) Get(ctx context.Context, key string) (string, error) {
+	reply, err := c.Do(ctx, "GET", key)
+	if err != nil {
+		return "", err
+	}
+	if reply == nil {
+		return "", ErrNil
+	}
+	value, ok := reply.(string)
+	if !ok {
+		return "", fmt.Errorf("redis: неожиданный ответ GET: %T", reply)
+	}
+	return value, nil
+}
+
+// Set сохраняет значение; ttl > 0 задает время жизни ключа
+func (c *Client) Set(ctx context.Context, key, value string, ttl time.Duration) error {
+	args := []string{"SET", key, value}
+	if ttl > 0 {
+		args = append(args, "PX", strconv.FormatInt(ttl.Milliseconds(), 10))
+	}
+	_, err := c.Do(ctx, args...)
+	return err
+}
+
+// Del удаляет ключи
+func (c *Client) Del(ctx context.Context, keys ...string) error {
+	_, err := c.Do(ctx, append([]string{"DEL"}, keys...)...)
+	return err
+}
+
+// Eval выполняет Lua-скрипт на сервере атомарно
+func (c *Client) Eval(ctx context.Context, script string, keys []string, args ...string) (interface{}, error) {
+	cmd := []string{"EVAL", script, strconv.Itoa(len(keys))}
+	cmd = append(cmd, keys...)
+	cmd = append(cmd, args...)
+	return c.Do(ctx, cmd...)
+}
+
+// Close закрывает соединения пула
+func (c *Client) Close() error {
+	for {
+		select {
+		case cn := <-c.pool:
+			cn.Close()
+		default:
+			return nil
+		}
+	}
+}
+
+func (c *Client) get(ctx context.Context) (*conn, error) {
+	select {
+	case cn := <-c.pool:
+		return cn, nil
+	default:
+	}
+	return c.dial(ctx)
+}
+
+func (c *Client) put(cn *conn) {
+	select {
+	case c.pool <- cn:
+	default:
+		cn.Close()
+	}
+}
+
+// dial открывает соединение, проходит аутентификацию и выбирает базу
+func (c *Client) dial(ctx context.Context) (*conn, error) {
+	dialer := &net.Dialer{Timeout: dialTimeout}
+	var (
+		netConn net.Conn
+		err     error
+	)
+	if c.useTLS {
+		host, _, _ := net.SplitHostPort(c.addr)
+		netConn, err = (&tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: host}}).DialContext(ctx, "tcp", c.addr)
+	} else {
+		netConn, err = dialer.DialContext(ctx, "tcp", c.addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка подключения к Redis %s: %w", c.addr, err)
+	}
+
+	cn := &conn{Conn: netConn, reader: bufio.NewReader(netConn)}
+	if c.password != "" {
+		auth := []string{"AUTH", c.password}
+		if c.username != "" {
+			auth = []string{"AUTH", c.username, c.password}
+		}
+		if _, err := cn.roundTrip(ctx, auth); err != nil {
+			cn.Close()
+			return nil, fmt.Errorf("ошибка аутентификации в Redis: %w", err)
+		}
+	}
+	if c.db != 0 {
+		if _, err := cn.roundTrip(ctx, []string{"SELECT", strconv.Itoa(c.db)}); err != nil {
+			cn.Close()
+			return nil, fmt.Errorf("ошибка выбора базы Redis %d: %w", c.db, err)
+		}
+	}
+	return cn, nil
+}
+
+// roundTrip отправляет команду и читает один ответ
+func (cn *conn) roundTrip(ctx context.Context, args []string) (interface{}, error) {
+	deadline := time.Now().Add(ioTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := cn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	var cmd strings.Builder
+	fmt.Fprintf(&cmd, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&cmd, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := io.WriteString(cn, cmd.String()); err != nil {
+		return nil, err
+	}
+	return readReply(cn.reader)
+}
+
+// readReply разбирает ответ в формате RESP2
+func readReply(r *bufio.Reader) (interface{}, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, errors.New("redis: пустой ответ")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, Error(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		if size < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		return string(buf[:size]), nil
+	case '*':
+		count, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		if count < 0 {
+			return nil, nil
+		}
+		items := make([]interface{}, count)
+		for i := range items {
+			if items[i], err = readReply(r); err != nil {
+				var serverErr Error
+				if !errors.As(err, &serverErr) {
+					return nil, err
+				}
+				items[i] = serverErr
+			}
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("redis: неизвестный тип ответа %q", line[0])
+	}
+}
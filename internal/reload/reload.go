@@ -0,0 +1,237 @@
+package reload
+
+import (
+	"fmt"
+	"interview-bot-complete/internal/config"
+	"interview-bot-complete/internal/extractor"
+	"interview-bot-complete/internal/schema"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce - пауза после последнего события файловой системы перед перезагрузкой:
+// редакторы сохраняют файл несколькими операциями (запись во временный файл, переименование)
+const watchDebounce = 500 * time.Millisecond
+
+// Report - итог перезагрузки конфигурации
+type Report struct {
+	// Templates - ID шаблонов после перезагрузки; Changed - новые и измененные, Removed - удаленные
+	Templates []string
+	Changed   []string
+	Removed   []string
+	// SchemaFields - полей в общей схеме профиля (0 - экстрактор не подключен)
+	SchemaFields int
+}
+
+// Reloader перезагружает без перезапуска бота шаблоны интервью (INTERVIEW_CONFIG и каталог
+// шаблонов с переводами) и общую схему профиля. Новая конфигурация применяется только целиком
+// и только после проверки: при ошибке в любом файле остается прежняя. Новые интервью начинаются
+// по новым шаблонам, начатые доводятся по ревизии шаблона, с которой начаты.
+type Reloader struct {
+	templates *config.Templates
+	extractor *extractor.Service
+	checks    []Check
+	logger    *slog.Logger
+
+	mutex sync.Mutex
+	// signature - размеры и время изменения файлов конфигурации при последней перезагрузке
+	signature string
+}
+
+// Check проверяет новые шаблоны перед применением (например, ссылки на каталоги и рубрики);
+// ошибка отменяет перезагрузку
+type Check func(templates *config.Templates) error
+
+// New создает перезагрузку конфигурации; extractorService nil - схема профиля не перезагружается
+func New(templates *config.Templates, extractorService *extractor.Service) *Reloader {
+	r := &Reloader{
+		templates: templates,
+		extractor: extractorService,
+		logger:    slog.Default(),
+	}
+	r.signature, _ = r.fileSignature()
+	return r
+}
+
+// AddCheck добавляет проверку новых шаблонов; вызывается до Watch
+func (r *Reloader) AddCheck(check Check) {
+	r.checks = append(r.checks, check)
+}
+
+// SetLogger задает логгер перезагрузки
+func (r *Reloader) SetLogger(logger *slog.Logger) {
+	r.logger = logger
+}
+
+// Reload перечитывает и проверяет конфигурацию и применяет ее. Ошибочные файлы
+// не перечитываются Watch, пока их снова не изменят.
+func (r *Reloader) Reload() (*Report, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	signature, _ := r.fileSignature()
+	r.signature = signature
+	report, err := r.apply()
+	if err != nil {
+		r.logger.Warn("Конфигурация не перезагружена, используется прежняя", "error", err)
+		return nil, err
+	}
+	r.logger.Info("Конфигурация перезагружена", "templates", strings.Join(report.Templates, ","),
+		"changed", strings.Join(report.Changed, ","), "removed", strings.Join(report.Removed, ","),
+		"schema_fields", report.SchemaFields)
+	return report, nil
+}
+
+// Watch следит через fsnotify за каталогами шаблонов и схемы профиля и перезагружает
+// конфигурацию, когда ее файлы изменились. Следит за каталогами, а не файлами: при сохранении
+// редакторы заменяют файл новым, и наблюдение за самим файлом терялось бы.
+func (r *Reloader) Watch() error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("ошибка запуска отслеживания файлов: %w", err)
+	}
+	for _, dir := range r.dirs() {
+		if err := watcher.Add(dir); err != nil {
+			watcher.Close()
+			return fmt.Errorf("ошибка отслеживания каталога %s: %w", dir, err)
+		}
+	}
+	go r.watch(watcher)
+	return nil
+}
+
+// watch объединяет события файловой системы и после паузы watchDebounce перезагружает
+// конфигурацию, если файлы действительно изменились
+func (r *Reloader) watch(watcher *fsnotify.Watcher) {
+	defer watcher.Close()
+	var debounce <-chan time.Time
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Has(fsnotify.Chmod) && !event.Has(fsnotify.Write) {
+				continue
+			}
+			debounce = time.After(watchDebounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			r.logger.Warn("Ошибка отслеживания файлов конфигурации", "error", err)
+		case <-debounce:
+			debounce = nil
+			r.reloadChanged()
+		}
+	}
+}
+
+// reloadChanged перезагружает конфигурацию, если ее файлы изменились. Паника при разборе
+// файлов пишется в лог и не останавливает ни отслеживание, ни бота.
+func (r *Reloader) reloadChanged() {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			r.logger.Error("Паника при перезагрузке конфигурации", "panic", fmt.Sprint(recovered), "stack", string(debug.Stack()))
+		}
+	}()
+	if r.changed() {
+		// Итог перезагрузки пишется в лог
+		r.Reload()
+	}
+}
+
+// dirs возвращает каталоги файлов конфигурации без повторов
+func (r *Reloader) dirs() []string {
+	dirs := r.templates.Dirs()
+	if r.extractor != nil {
+		schemaDir := filepath.Dir(r.extractor.SchemaFile())
+		for _, dir := range dirs {
+			if dir == schemaDir {
+				return dirs
+			}
+		}
+		dirs = append(dirs, schemaDir)
+	}
+	return dirs
+}
+
+// changed сообщает, изменились ли файлы конфигурации после последней перезагрузки
+func (r *Reloader) changed() bool {
+	signature, err := r.fileSignature()
+	if err != nil {
+		// Файл мог быть удален на время сохранения - проверим в следующий раз
+		return false
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return signature != r.signature
+}
+
+// apply загружает шаблоны и схему, проверяет их и заменяет текущие
+func (r *Reloader) apply() (*Report, error) {
+	next, err := r.templates.Load()
+	if err != nil {
+		return nil, fmt.Errorf("шаблоны интервью: %w", err)
+	}
+	for _, check := range r.checks {
+		if err := check(next); err != nil {
+			return nil, err
+		}
+	}
+
+	var fields map[string]schema.SchemaField
+	if r.extractor != nil {
+		if fields, err = r.extractor.ReadSchema(); err != nil {
+			return nil, fmt.Errorf("схема профиля: %w", err)
+		}
+	}
+
+	report := compare(r.templates, next)
+	r.templates.Replace(next)
+	if fields != nil {
+		r.extractor.SetSchema(fields)
+		report.SchemaFields = len(fields)
+	}
+	return report, nil
+}
+
+// compare сравнивает ревизии текущих и новых шаблонов
+func compare(current, next *config.Templates) *Report {
+	report := &Report{Templates: next.IDs()}
+	for _, id := range report.Templates {
+		cfg, _ := next.Get(id)
+		if old, ok := current.Get(id); !ok || old.Revision() != cfg.Revision() {
+			report.Changed = append(report.Changed, id)
+		}
+	}
+	for _, id := range current.IDs() {
+		if _, ok := next.Get(id); !ok {
+			report.Removed = append(report.Removed, id)
+		}
+	}
+	return report
+}
+
+// fileSignature описывает файлы шаблонов и схемы профиля для обнаружения правок
+func (r *Reloader) fileSignature() (string, error) {
+	signature, err := r.templates.Signature()
+	if err != nil {
+		return "", err
+	}
+	if r.extractor == nil {
+		return signature, nil
+	}
+	info, err := os.Stat(r.extractor.SchemaFile())
+	if err != nil {
+		return "", err
+	}
+	return signature + fmt.Sprintf("%s:%d:%d;", r.extractor.SchemaFile(), info.Size(), info.ModTime().UnixNano()), nil
+}
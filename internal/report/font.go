@@ -0,0 +1,189 @@
+package report
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Font - TrueType шрифт для встраивания в PDF: метрики и таблица символ → глиф
+type Font struct {
+	data        []byte
+	unitsPerEm  int
+	ascent      int
+	descent     int
+	bbox        [4]int
+	advances    []uint16
+	glyphs      map[rune]uint16
+	missingRune rune
+}
+
+// LoadFont читает TrueType шрифт (.ttf) с поддержкой нужных алфавитов, например DejaVuSans
+func LoadFont(path string) (*Font, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения шрифта %s: %w", path, err)
+	}
+	font, err := parseFont(data)
+	if err != nil {
+		return nil, fmt.Errorf("шрифт %s не поддерживается: %w", path, err)
+	}
+	return font, nil
+}
+
+func parseFont(data []byte) (*Font, error) {
+	if len(data) < 12 {
+		return nil, errors.New("файл слишком короткий")
+	}
+	if tag := string(data[:4]); tag == "OTTO" || tag == "ttcf" {
+		return nil, errors.New("нужен TrueType шрифт (.ttf), а не OpenType CFF или коллекция")
+	}
+
+	tables := make(map[string][]byte)
+	numTables := int(binary.BigEndian.Uint16(data[4:]))
+	for i := 0; i < numTables; i++ {
+		record := 12 + i*16
+		if record+16 > len(data) {
+			return nil, errors.New("поврежденный каталог таблиц")
+		}
+		tag := string(data[record : record+4])
+		offset := int(binary.BigEndian.Uint32(data[record+8:]))
+		length := int(binary.BigEndian.Uint32(data[record+12:]))
+		if offset < 0 || length < 0 || offset+length > len(data) {
+			return nil, fmt.Errorf("поврежденная таблица %s", tag)
+		}
+		tables[tag] = data[offset : offset+length]
+	}
+	for _, tag := range []string{"head", "hhea", "hmtx", "cmap", "glyf"} {
+		if tables[tag] == nil {
+			return nil, fmt.Errorf("нет таблицы %s", tag)
+		}
+	}
+
+	head, hhea := tables["head"], tables["hhea"]
+	if len(head) < 54 || len(hhea) < 36 {
+		return nil, errors.New("поврежденные таблицы head/hhea")
+	}
+
+	font := &Font{
+		data:       data,
+		unitsPerEm: int(binary.BigEndian.Uint16(head[18:])),
+		ascent:     int(int16(binary.BigEndian.Uint16(hhea[4:]))),
+		descent:    int(int16(binary.BigEndian.Uint16(hhea[6:]))),
+	}
+	if font.unitsPerEm == 0 {
+		return nil, errors.New("unitsPerEm = 0")
+	}
+	for i := range font.bbox {
+		font.bbox[i] = int(int16(binary.BigEndian.Uint16(head[36+i*2:])))
+	}
+
+	hmtx := tables["hmtx"]
+	numMetrics := int(binary.BigEndian.Uint16(hhea[34:]))
+	if numMetrics == 0 || numMetrics*4 > len(hmtx) {
+		return nil, errors.New("поврежденная таблица hmtx")
+	}
+	font.advances = make([]uint16, numMetrics)
+	for i := range font.advances {
+		font.advances[i] = binary.BigEndian.Uint16(hmtx[i*4:])
+	}
+
+	glyphs, err := parseCmap(tables["cmap"])
+	if err != nil {
+		return nil, err
+	}
+	font.glyphs = glyphs
+	font.missingRune = '?'
+	return font, nil
+}
+
+// parseCmap читает Unicode-таблицу символов формата 4 (BMP)
+func parseCmap(cmap []byte) (map[rune]uint16, error) {
+	if len(cmap) < 4 {
+		return nil, errors.New("поврежденная таблица cmap")
+	}
+
+	var subtable []byte
+	numTables := int(binary.BigEndian.Uint16(cmap[2:]))
+	for i := 0; i < numTables; i++ {
+		record := 4 + i*8
+		if record+8 > len(cmap) {
+			break
+		}
+		platform := binary.BigEndian.Uint16(cmap[record:])
+		encoding := binary.BigEndian.Uint16(cmap[record+2:])
+		offset := int(binary.BigEndian.Uint32(cmap[record+4:]))
+		if offset+4 > len(cmap) || binary.BigEndian.Uint16(cmap[offset:]) != 4 {
+			continue
+		}
+		if (platform == 3 && encoding == 1) || platform == 0 {
+			subtable = cmap[offset:]
+			break
+		}
+	}
+	if subtable == nil || len(subtable) < 14 {
+		return nil, errors.New("нет Unicode-таблицы cmap формата 4")
+	}
+
+	segments := int(binary.BigEndian.Uint16(subtable[6:])) / 2
+	endCodes := 14
+	startCodes := endCodes + segments*2 + 2
+	deltas := startCodes + segments*2
+	rangeOffsets := deltas + segments*2
+	if rangeOffsets+segments*2 > len(subtable) {
+		return nil, errors.New("поврежденная таблица cmap")
+	}
+
+	glyphs := make(map[rune]uint16)
+	for seg := 0; seg < segments; seg++ {
+		end := int(binary.BigEndian.Uint16(subtable[endCodes+seg*2:]))
+		start := int(binary.BigEndian.Uint16(subtable[startCodes+seg*2:]))
+		delta := int(binary.BigEndian.Uint16(subtable[deltas+seg*2:]))
+		rangeOffset := int(binary.BigEndian.Uint16(subtable[rangeOffsets+seg*2:]))
+		if start == 0xFFFF {
+			continue
+		}
+		for code := start; code <= end; code++ {
+			var glyph int
+			if rangeOffset == 0 {
+				glyph = (code + delta) & 0xFFFF
+			} else {
+				addr := rangeOffsets + seg*2 + rangeOffset + (code-start)*2
+				if addr+2 > len(subtable) {
+					continue
+				}
+				glyph = int(binary.BigEndian.Uint16(subtable[addr:]))
+				if glyph != 0 {
+					glyph = (glyph + delta) & 0xFFFF
+				}
+			}
+			if glyph != 0 {
+				glyphs[rune(code)] = uint16(glyph)
+			}
+		}
+	}
+	return glyphs, nil
+}
+
+// glyph возвращает глиф символа (глиф '?' для отсутствующих в шрифте символов)
+func (f *Font) glyph(r rune) (uint16, rune) {
+	if g, ok := f.glyphs[r]; ok {
+		return g, r
+	}
+	return f.glyphs[f.missingRune], f.missingRune
+}
+
+// advance возвращает ширину глифа в тысячных долях кегля
+func (f *Font) advance(glyph uint16) int {
+	width := f.advances[len(f.advances)-1]
+	if int(glyph) < len(f.advances) {
+		width = f.advances[glyph]
+	}
+	return int(width) * 1000 / f.unitsPerEm
+}
+
+// scale переводит величину в единицах шрифта в тысячные доли кегля
+func (f *Font) scale(v int) int {
+	return v * 1000 / f.unitsPerEm
+}
@@ -0,0 +1,278 @@
+package report
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Параметры страницы A4 в пунктах
+const (
+	pageWidth    = 595
+	pageHeight   = 842
+	pageMargin   = 56
+	bodySize     = 11
+	headingSize  = 14
+	titleSize    = 20
+	lineSpacing  = 1.4
+	paragraphGap = 6
+)
+
+// Section - раздел документа: заголовок, абзацы и маркированный список
+type Section struct {
+	Heading    string
+	Paragraphs []string
+	Bullets    []string
+}
+
+// Document - простой текстовый PDF документ с одним встроенным шрифтом
+type Document struct {
+	Title    string
+	Subtitle string
+	Sections []Section
+}
+
+// textLine - строка, уже разбитая по ширине страницы
+type textLine struct {
+	text string
+	size int
+	gap  int // дополнительный отступ перед строкой
+}
+
+// RenderPDF верстает документ в PDF. Шрифт встраивается целиком, поэтому
+// текст на любом поддерживаемом шрифтом языке (в том числе кириллица) отображается и копируется.
+func RenderPDF(font *Font, doc Document) ([]byte, error) {
+	lines := layout(font, doc)
+
+	used := make(map[uint16]rune)
+	var pages []string
+	var content strings.Builder
+	y := float64(pageHeight - pageMargin)
+	for _, line := range lines {
+		height := float64(line.size) * lineSpacing
+		if y-height-float64(line.gap) < pageMargin && content.Len() > 0 {
+			pages = append(pages, content.String())
+			content.Reset()
+			y = float64(pageHeight - pageMargin)
+		}
+		y -= height + float64(line.gap)
+		if line.text == "" {
+			continue
+		}
+
+		var hex strings.Builder
+		for _, r := range line.text {
+			glyph, shown := font.glyph(r)
+			used[glyph] = shown
+			fmt.Fprintf(&hex, "%04X", glyph)
+		}
+		fmt.Fprintf(&content, "BT /F1 %d Tf %d %.2f Td <%s> Tj ET\n", line.size, pageMargin, y, hex.String())
+	}
+	if content.Len() > 0 || len(pages) == 0 {
+		pages = append(pages, content.String())
+	}
+
+	return writePDF(font, doc.Title, pages, used)
+}
+
+// layout разбивает документ на строки с учетом ширины страницы
+func layout(font *Font, doc Document) []textLine {
+	width := pageWidth - 2*pageMargin
+	var lines []textLine
+
+	add := func(text string, size, gap int, indent string) {
+		for i, line := range wrap(font, text, size, width, indent) {
+			if i > 0 {
+				gap = 0
+			}
+			lines = append(lines, textLine{text: line, size: size, gap: gap})
+		}
+	}
+
+	add(doc.Title, titleSize, 0, "")
+	if doc.Subtitle != "" {
+		add(doc.Subtitle, bodySize, paragraphGap, "")
+	}
+	for _, section := range doc.Sections {
+		if section.Heading != "" {
+			add(section.Heading, headingSize, headingSize, "")
+		}
+		for _, paragraph := range section.Paragraphs {
+			for _, part := range strings.Split(paragraph, "\n") {
+				add(strings.TrimSpace(part), bodySize, paragraphGap, "")
+			}
+		}
+		for _, bullet := range section.Bullets {
+			add("• "+strings.TrimSpace(bullet), bodySize, paragraphGap/2, "   ")
+		}
+	}
+	return lines
+}
+
+// wrap переносит текст по словам; слишком длинные слова режутся посимвольно
+func wrap(font *Font, text string, size, width int, indent string) []string {
+	measure := func(s string) int {
+		total := 0
+		for _, r := range s {
+			glyph, _ := font.glyph(r)
+			total += font.advance(glyph)
+		}
+		return total * size / 1000
+	}
+
+	words := strings.FieldsFunc(text, unicode.IsSpace)
+	if len(words) == 0 {
+		return []string{""}
+	}
+
+	var lines []string
+	current := ""
+	for _, word := range words {
+		candidate := word
+		if current != "" {
+			candidate = current + " " + word
+		}
+		if measure(candidate) <= width {
+			current = candidate
+			continue
+		}
+		if current != "" {
+			lines = append(lines, current)
+			current = indent
+		}
+		for measure(current+word) > width && len([]rune(word)) > 1 {
+			runes := []rune(word)
+			cut := len(runes) - 1
+			for cut > 1 && measure(current+string(runes[:cut])) > width {
+				cut--
+			}
+			lines = append(lines, current+string(runes[:cut]))
+			current = indent
+			word = string(runes[cut:])
+		}
+		current += word
+	}
+	return append(lines, current)
+}
+
+// writePDF собирает объекты PDF: каталог, страницы, шрифт Type0 (Identity-H) и потоки
+func writePDF(font *Font, title string, pages []string, used map[uint16]rune) ([]byte, error) {
+	var objects []string
+	add := func(body string) int {
+		objects = append(objects, body)
+		return len(objects)
+	}
+	stream := func(dict string, data []byte) (string, error) {
+		var compressed bytes.Buffer
+		writer := zlib.NewWriter(&compressed)
+		if _, err := writer.Write(data); err != nil {
+			return "", err
+		}
+		if err := writer.Close(); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("<< %s /Filter /FlateDecode /Length %d >>\nstream\n%s\nendstream",
+			dict, compressed.Len(), compressed.String()), nil
+	}
+
+	catalogID := add("") // заполняется после страниц
+	pagesID := add("")
+
+	fontFile, err := stream(fmt.Sprintf("/Length1 %d", len(font.data)), font.data)
+	if err != nil {
+		return nil, err
+	}
+	fontFileID := add(fontFile)
+
+	descriptorID := add(fmt.Sprintf("<< /Type /FontDescriptor /FontName /ReportFont /Flags 32 /FontBBox [%d %d %d %d] "+
+		"/ItalicAngle 0 /Ascent %d /Descent %d /CapHeight %d /StemV 80 /FontFile2 %d 0 R >>",
+		font.scale(font.bbox[0]), font.scale(font.bbox[1]), font.scale(font.bbox[2]), font.scale(font.bbox[3]),
+		font.scale(font.ascent), font.scale(font.descent), font.scale(font.ascent), fontFileID))
+
+	glyphIDs := make([]int, 0, len(used))
+	for glyph := range used {
+		glyphIDs = append(glyphIDs, int(glyph))
+	}
+	sort.Ints(glyphIDs)
+
+	var widths, toUnicode strings.Builder
+	for _, glyph := range glyphIDs {
+		fmt.Fprintf(&widths, "%d [%d] ", glyph, font.advance(uint16(glyph)))
+	}
+	cidFontID := add(fmt.Sprintf("<< /Type /Font /Subtype /CIDFontType2 /BaseFont /ReportFont "+
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> "+
+		"/FontDescriptor %d 0 R /CIDToGIDMap /Identity /W [%s] >>", descriptorID, widths.String()))
+
+	// ToUnicode нужен, чтобы текст из PDF можно было копировать и искать
+	toUnicode.WriteString("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n" +
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n" +
+		"/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n" +
+		"1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+	for start := 0; start < len(glyphIDs); start += 100 {
+		chunk := glyphIDs[start:min(start+100, len(glyphIDs))]
+		fmt.Fprintf(&toUnicode, "%d beginbfchar\n", len(chunk))
+		for _, glyph := range chunk {
+			fmt.Fprintf(&toUnicode, "<%04X> <%s>\n", glyph, utf16Hex(used[uint16(glyph)]))
+		}
+		toUnicode.WriteString("endbfchar\n")
+	}
+	toUnicode.WriteString("endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend")
+	toUnicodeStream, err := stream("", []byte(toUnicode.String()))
+	if err != nil {
+		return nil, err
+	}
+	toUnicodeID := add(toUnicodeStream)
+
+	fontID := add(fmt.Sprintf("<< /Type /Font /Subtype /Type0 /BaseFont /ReportFont /Encoding /Identity-H "+
+		"/DescendantFonts [%d 0 R] /ToUnicode %d 0 R >>", cidFontID, toUnicodeID))
+
+	var kids []string
+	for _, page := range pages {
+		contentStream, err := stream("", []byte(page))
+		if err != nil {
+			return nil, err
+		}
+		contentID := add(contentStream)
+		pageID := add(fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %d %d] "+
+			"/Resources << /Font << /F1 %d 0 R >> >> /Contents %d 0 R >>",
+			pagesID, pageWidth, pageHeight, fontID, contentID))
+		kids = append(kids, fmt.Sprintf("%d 0 R", pageID))
+	}
+
+	objects[pagesID-1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(kids))
+	infoID := add(fmt.Sprintf("<< /Title <FEFF%s> /Producer (interview-bot) >>", utf16Hex([]rune(title)...)))
+	objects[catalogID-1] = fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pagesID)
+
+	var out bytes.Buffer
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	offsets := make([]int, len(objects))
+	for i, body := range objects {
+		offsets[i] = out.Len()
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", i+1, body)
+	}
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root %d 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n",
+		len(objects)+1, catalogID, infoID, xref)
+	return out.Bytes(), nil
+}
+
+// utf16Hex кодирует символы в UTF-16BE шестнадцатеричной строкой
+func utf16Hex(runes ...rune) string {
+	var hex strings.Builder
+	for _, r := range runes {
+		if r > 0xFFFF {
+			r -= 0x10000
+			fmt.Fprintf(&hex, "%04X%04X", 0xD800+(r>>10), 0xDC00+(r&0x3FF))
+			continue
+		}
+		fmt.Fprintf(&hex, "%04X", r)
+	}
+	return hex.String()
+}
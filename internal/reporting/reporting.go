@@ -0,0 +1,18 @@
+package reporting
+
+import "context"
+
+// Event описывает перехваченную ошибку или панику вместе с контекстом пользователя
+type Event struct {
+	Message  string
+	Stack    string
+	UserID   int64
+	ChatID   int64
+	UpdateID int
+	Tags     map[string]string
+}
+
+// Reporter отправляет события об ошибках во внешнюю систему (например, Sentry)
+type Reporter interface {
+	Report(ctx context.Context, event Event) error
+}
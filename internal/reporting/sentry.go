@@ -0,0 +1,140 @@
+package reporting
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"interview-bot-complete/internal/httpclient"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sentryProxyEnv - прокси запросов к Sentry; перекрывает HTTPS_PROXY
+const sentryProxyEnv = "SENTRY_PROXY"
+
+// sentryClientName - идентификатор клиента в заголовке X-Sentry-Auth
+const sentryClientName = "interview-bot/1.0"
+
+// Sentry отправляет события в Sentry через HTTP store API без SDK
+type Sentry struct {
+	storeURL    string
+	publicKey   string
+	environment string
+	http        *http.Client
+}
+
+// NewSentry создает репортер по DSN вида https://<key>@<host>/<project_id>
+func NewSentry(dsn, environment string) (*Sentry, error) {
+	parsed, err := url.Parse(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("некорректный SENTRY_DSN: %w", err)
+	}
+	if parsed.User == nil || parsed.User.Username() == "" {
+		return nil, fmt.Errorf("в SENTRY_DSN нет публичного ключа")
+	}
+
+	// Путь может содержать префикс, если Sentry развернут не в корне домена
+	path := strings.Trim(parsed.Path, "/")
+	prefix, project := "", path
+	if i := strings.LastIndex(path, "/"); i >= 0 {
+		prefix, project = "/"+path[:i], path[i+1:]
+	}
+	if _, err := strconv.Atoi(project); err != nil {
+		return nil, fmt.Errorf("в SENTRY_DSN нет ID проекта")
+	}
+
+	transport, err := httpclient.NewTransport(httpclient.OptionsFromEnv(sentryProxyEnv))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка настройки HTTP клиента Sentry (SENTRY_PROXY, HTTP_CA_CERT_FILE): %w", err)
+	}
+
+	return &Sentry{
+		storeURL:    fmt.Sprintf("%s://%s%s/api/%s/store/", parsed.Scheme, parsed.Host, prefix, project),
+		publicKey:   parsed.User.Username(),
+		environment: environment,
+		http:        &http.Client{Timeout: 10 * time.Second, Transport: transport},
+	}, nil
+}
+
+type sentryEvent struct {
+	EventID     string            `json:"event_id"`
+	Timestamp   string            `json:"timestamp"`
+	Level       string            `json:"level"`
+	Platform    string            `json:"platform"`
+	Logger      string            `json:"logger"`
+	Environment string            `json:"environment,omitempty"`
+	Message     string            `json:"message"`
+	Tags        map[string]string `json:"tags,omitempty"`
+	User        *sentryUser       `json:"user,omitempty"`
+	Extra       map[string]string `json:"extra,omitempty"`
+}
+
+type sentryUser struct {
+	ID string `json:"id"`
+}
+
+// Report отправляет событие в Sentry
+func (s *Sentry) Report(ctx context.Context, event Event) error {
+	payload := sentryEvent{
+		EventID:     newEventID(),
+		Timestamp:   time.Now().UTC().Format(time.RFC3339),
+		Level:       "fatal",
+		Platform:    "go",
+		Logger:      "telegram",
+		Environment: s.environment,
+		Message:     event.Message,
+		Tags:        map[string]string{},
+		Extra:       map[string]string{"stack": event.Stack},
+	}
+	for key, value := range event.Tags {
+		payload.Tags[key] = value
+	}
+	if event.ChatID != 0 {
+		payload.Tags["chat_id"] = strconv.FormatInt(event.ChatID, 10)
+	}
+	if event.UpdateID != 0 {
+		payload.Tags["update_id"] = strconv.Itoa(event.UpdateID)
+	}
+	if event.UserID != 0 {
+		payload.User = &sentryUser{ID: strconv.FormatInt(event.UserID, 10)}
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("ошибка сериализации события: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", s.storeURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("ошибка создания запроса: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Sentry-Auth", fmt.Sprintf("Sentry sentry_version=7, sentry_client=%s, sentry_key=%s",
+		sentryClientName, s.publicKey))
+
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("ошибка отправки события в Sentry: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("Sentry вернул статус %d: %s", resp.StatusCode, respBody)
+	}
+	return nil
+}
+
+// newEventID генерирует 32-символьный hex ID события
+func newEventID() string {
+	id := make([]byte, 16)
+	rand.Read(id)
+	return hex.EncodeToString(id)
+}
@@ -0,0 +1,328 @@
+package research
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"interview-bot-complete/internal/storage"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Файлы набора данных в архиве
+const (
+	interviewsFile = "interviews.jsonl"
+	profilesFile   = "profiles.jsonl"
+	manifestFile   = "manifest.json"
+)
+
+// identifyingFields - поля профиля, значения которых заменяются псевдонимами, и вид псевдонима
+var identifyingFields = map[string]string{
+	"name":               KindPerson,
+	"birth_city":         KindCity,
+	"current_city":       KindCity,
+	"previous_companies": KindOrg,
+}
+
+// ProfileSource возвращает JSON профиля по ID интервью
+type ProfileSource func(interviewID string) (string, error)
+
+// Options - параметры выгрузки
+type Options struct {
+	// Salt - секрет псевдонимов; обязателен
+	Salt string
+	// Tenant - выгружать только интервью арендатора (пусто - все интервью)
+	Tenant string
+	// Profiles - источник профилей; nil - выгружаются только интервью
+	Profiles ProfileSource
+}
+
+// Report - итог выгрузки
+type Report struct {
+	Interviews int
+	Profiles   int
+	Skipped    int // результаты, которые не удалось прочитать
+}
+
+// interviewRecord - строка interviews.jsonl: результат интервью без идентифицирующих данных
+type interviewRecord struct {
+	storage.InterviewResult
+	Participant string `json:"participant,omitempty"`
+}
+
+// profileRecord - строка profiles.jsonl
+type profileRecord struct {
+	Interview string                 `json:"interview"`
+	Profile   map[string]interface{} `json:"profile"`
+}
+
+// manifest описывает набор данных для исследователей
+type manifest struct {
+	CreatedAt   string   `json:"created_at"`
+	Interviews  int      `json:"interviews"`
+	Profiles    int      `json:"profiles"`
+	Pseudonyms  []string `json:"pseudonymized_fields"`
+	Removed     []string `json:"removed_fields"`
+	Description string   `json:"description"`
+}
+
+// Export собирает обезличенный набор данных: zip с interviews.jsonl, profiles.jsonl и manifest.json.
+// Имя, города и работодатели из профиля заменяются псевдонимами в профиле и во всех текстах
+// интервью; ID интервью и пользователя - тоже псевдонимами, приглашение удаляется.
+func Export(opts Options) ([]byte, *Report, error) {
+	if opts.Salt == "" {
+		return nil, nil, fmt.Errorf("не задана соль псевдонимов")
+	}
+	ids, err := storage.ListResults()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pseudonymizer := NewPseudonymizer(opts.Salt)
+	report := &Report{}
+	var results []*storage.InterviewResult
+	for _, id := range ids {
+		result, err := storage.LoadResult(id)
+		if err != nil {
+			report.Skipped++
+			continue
+		}
+		if opts.Tenant != "" && result.Tenant != opts.Tenant {
+			continue
+		}
+		results = append(results, result)
+	}
+	// Строки в порядке прохождения интервью
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Timestamp < results[j].Timestamp })
+
+	var interviews, profiles bytes.Buffer
+	for _, result := range results {
+		var profile map[string]interface{}
+		if opts.Profiles != nil {
+			if profileJSON, err := opts.Profiles(result.InterviewID); err == nil {
+				json.Unmarshal([]byte(profileJSON), &profile)
+			}
+		}
+
+		interviewPseudonym := pseudonymizer.Pseudonym(KindInterview, result.InterviewID)
+		scrub := newScrubber(identifyingValues(pseudonymizer, profile))
+
+		record := interviewRecord{InterviewResult: anonymizeResult(result, scrub)}
+		record.InterviewID = interviewPseudonym
+		if result.MemoryFrom != "" {
+			record.MemoryFrom = pseudonymizer.Pseudonym(KindInterview, result.MemoryFrom)
+		}
+		if result.UserID != 0 {
+			record.Participant = pseudonymizer.Pseudonym(KindParticipant, strconv.FormatInt(result.UserID, 10))
+		}
+		if err := writeLine(&interviews, record); err != nil {
+			return nil, nil, err
+		}
+		report.Interviews++
+
+		if profile == nil {
+			continue
+		}
+		profile = anonymizeProfile(pseudonymizer, profile, scrub, interviewPseudonym)
+		if err := writeLine(&profiles, profileRecord{Interview: interviewPseudonym, Profile: profile}); err != nil {
+			return nil, nil, err
+		}
+		report.Profiles++
+	}
+
+	data, err := bundle(interviews.Bytes(), profiles.Bytes(), report)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, report, nil
+}
+
+// identifyingValues собирает значения идентифицирующих полей профиля и их псевдонимы.
+// Имя добавляется и по частям, чтобы в ответах заменялось и одно имя без фамилии.
+func identifyingValues(p *Pseudonymizer, profile map[string]interface{}) map[string]string {
+	values := make(map[string]string)
+	for field, kind := range identifyingFields {
+		for _, value := range stringValues(profile[field]) {
+			pseudonym := p.Pseudonym(kind, value)
+			values[value] = pseudonym
+			if kind == KindPerson {
+				for _, part := range strings.Fields(value) {
+					values[part] = pseudonym
+				}
+			}
+		}
+	}
+	return values
+}
+
+// anonymizeResult возвращает копию результата без приглашения и ID пользователя,
+// с псевдонимами в вопросах, ответах и саммари
+func anonymizeResult(result *storage.InterviewResult, scrub *scrubber) storage.InterviewResult {
+	anonymized := *result
+	anonymized.UserID = 0
+	anonymized.Invitation = nil
+	anonymized.Blocks = make([]storage.BlockResult, len(result.Blocks))
+	for n, block := range result.Blocks {
+		qas := make([]storage.QA, len(block.QuestionsAndAnswers))
+		for i, qa := range block.QuestionsAndAnswers {
+			qa.Question = scrub.Scrub(qa.Question)
+			qa.Answer = scrub.Scrub(qa.Answer)
+			qa.OriginalAnswer = scrub.Scrub(qa.OriginalAnswer)
+			qa.Normalized = scrub.Scrub(qa.Normalized)
+			if qa.Clarification != nil {
+				clarification := *qa.Clarification
+				clarification.Question = scrub.Scrub(clarification.Question)
+				clarification.Answer = scrub.Scrub(clarification.Answer)
+				qa.Clarification = &clarification
+			}
+			qas[i] = qa
+		}
+		block.QuestionsAndAnswers = qas
+		if block.Summary != nil {
+			summary := *block.Summary
+			summary.Text = scrub.Scrub(summary.Text)
+			summary.Fields = make(map[string][]string, len(block.Summary.Fields))
+			for field, values := range block.Summary.Fields {
+				scrubbed := make([]string, len(values))
+				for i, value := range values {
+					scrubbed[i] = scrub.Scrub(value)
+				}
+				summary.Fields[field] = scrubbed
+			}
+			block.Summary = &summary
+		}
+		anonymized.Blocks[n] = block
+	}
+	return anonymized
+}
+
+// anonymizeProfile заменяет идентифицирующие поля псевдонимами, а в остальных текстах -
+// упоминания их значений; ID интервью в метаданных заменяется псевдонимом интервью
+func anonymizeProfile(p *Pseudonymizer, profile map[string]interface{}, scrub *scrubber, interviewPseudonym string) map[string]interface{} {
+	anonymized := scrubValue(profile, scrub, interviewPseudonym).(map[string]interface{})
+	for field, kind := range identifyingFields {
+		switch value := profile[field].(type) {
+		case string:
+			if strings.TrimSpace(value) != "" {
+				anonymized[field] = p.Pseudonym(kind, value)
+			}
+		case []interface{}:
+			var pseudonyms []interface{}
+			for _, item := range stringValues(value) {
+				pseudonyms = append(pseudonyms, p.Pseudonym(kind, item))
+			}
+			anonymized[field] = pseudonyms
+		}
+	}
+	return anonymized
+}
+
+// scrubValue рекурсивно заменяет значения во всех строках JSON
+func scrubValue(value interface{}, scrub *scrubber, interviewPseudonym string) interface{} {
+	switch v := value.(type) {
+	case string:
+		return scrub.Scrub(v)
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = scrubValue(item, scrub, interviewPseudonym)
+		}
+		return items
+	case map[string]interface{}:
+		fields := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			if key == "interview_id" {
+				fields[key] = interviewPseudonym
+				continue
+			}
+			fields[key] = scrubValue(item, scrub, interviewPseudonym)
+		}
+		return fields
+	}
+	return value
+}
+
+// stringValues возвращает непустые строки поля профиля: строку или элементы массива
+// (у элементов-объектов берется name, title или company)
+func stringValues(value interface{}) []string {
+	var values []string
+	switch v := value.(type) {
+	case string:
+		if strings.TrimSpace(v) != "" {
+			values = append(values, strings.TrimSpace(v))
+		}
+	case []interface{}:
+		for _, item := range v {
+			switch item := item.(type) {
+			case string:
+				values = append(values, stringValues(item)...)
+			case map[string]interface{}:
+				for _, field := range []string{"name", "title", "company"} {
+					if text, ok := item[field].(string); ok && strings.TrimSpace(text) != "" {
+						values = append(values, strings.TrimSpace(text))
+						break
+					}
+				}
+			}
+		}
+	}
+	return values
+}
+
+// writeLine дописывает запись строкой JSONL
+func writeLine(buffer *bytes.Buffer, record interface{}) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("ошибка сериализации записи выгрузки: %w", err)
+	}
+	buffer.Write(data)
+	buffer.WriteByte('\n')
+	return nil
+}
+
+// bundle упаковывает набор данных в zip
+func bundle(interviews, profiles []byte, report *Report) ([]byte, error) {
+	fields := make([]string, 0, len(identifyingFields))
+	for field := range identifyingFields {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	manifestData, err := json.MarshalIndent(manifest{
+		CreatedAt:   time.Now().Format(time.RFC3339),
+		Interviews:  report.Interviews,
+		Profiles:    report.Profiles,
+		Pseudonyms:  append(fields, "interview_id", "memory_from", "user_id"),
+		Removed:     []string{"invitation"},
+		Description: "Pseudonyms are stable across exports made with the same salt; mentions of pseudonymized values in interview texts are replaced too.",
+	}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	var buffer bytes.Buffer
+	writer := zip.NewWriter(&buffer)
+	modified := time.Now()
+	for _, file := range []struct {
+		name string
+		data []byte
+	}{
+		{interviewsFile, interviews},
+		{profilesFile, profiles},
+		{manifestFile, manifestData},
+	} {
+		entry, err := writer.CreateHeader(&zip.FileHeader{Name: file.name, Method: zip.Deflate, Modified: modified})
+		if err != nil {
+			return nil, fmt.Errorf("ошибка добавления %s в архив: %w", file.name, err)
+		}
+		if _, err := entry.Write(file.data); err != nil {
+			return nil, fmt.Errorf("ошибка записи %s в архив: %w", file.name, err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("ошибка сборки архива: %w", err)
+	}
+	return buffer.Bytes(), nil
+}
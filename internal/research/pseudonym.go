@@ -0,0 +1,105 @@
+package research
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Виды псевдонимов: префикс показывает исследователю, что было заменено
+const (
+	KindPerson      = "Person"
+	KindCity        = "City"
+	KindOrg         = "Org"
+	KindParticipant = "Participant"
+	KindInterview   = "Interview"
+)
+
+// pseudonymHexLength - длина хеша в псевдониме (Person_3fa2c1d0)
+const pseudonymHexLength = 8
+
+// minStemRunes - значения короче не ищутся в тексте: совпадений с обычными словами было бы слишком много
+const minStemRunes = 3
+
+// maxEndingRunes - столько букв окончания допускается после основы значения («Москв» - «Москве»)
+const maxEndingRunes = 3
+
+// endingRunes - гласные и мягкие знаки, отбрасываемые с конца значения перед поиском словоформ
+const endingRunes = "аеёиоуыэюяйьaeiouy"
+
+// Pseudonymizer заменяет идентифицирующие значения стабильными псевдонимами: одно значение
+// с одной солью всегда дает один псевдоним, поэтому выгрузки разных дней можно сопоставлять
+type Pseudonymizer struct {
+	salt []byte
+}
+
+// NewPseudonymizer создает замену с секретной солью: без нее псевдоним известного имени
+// можно было бы вычислить перебором
+func NewPseudonymizer(salt string) *Pseudonymizer {
+	return &Pseudonymizer{salt: []byte(salt)}
+}
+
+// Pseudonym возвращает псевдоним значения вида kind_<hex>; регистр и пробелы по краям не учитываются
+func (p *Pseudonymizer) Pseudonym(kind, value string) string {
+	mac := hmac.New(sha256.New, p.salt)
+	mac.Write([]byte(kind + ":" + strings.ToLower(strings.TrimSpace(value))))
+	return kind + "_" + hex.EncodeToString(mac.Sum(nil))[:pseudonymHexLength]
+}
+
+// scrubber заменяет в тексте идентифицирующие значения интервью и их словоформы псевдонимами
+type scrubber struct {
+	patterns []*regexp.Regexp
+	names    []string
+}
+
+// newScrubber собирает замены для значений (значение -> псевдоним). Длинные значения
+// заменяются первыми, чтобы «Нижний Новгород» не превратился в «Нижний City_...»
+func newScrubber(values map[string]string) *scrubber {
+	keys := make([]string, 0, len(values))
+	for value := range values {
+		keys = append(keys, value)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if len(keys[i]) != len(keys[j]) {
+			return len(keys[i]) > len(keys[j])
+		}
+		return keys[i] < keys[j]
+	})
+
+	s := &scrubber{}
+	for _, value := range keys {
+		stem := wordStem(value)
+		if utf8.RuneCountInString(stem) < minStemRunes {
+			continue
+		}
+		pattern := fmt.Sprintf(`(?i)(^|[^\p{L}\p{N}])(%s\p{L}{0,%d})($|[^\p{L}\p{N}])`, regexp.QuoteMeta(stem), maxEndingRunes)
+		s.patterns = append(s.patterns, regexp.MustCompile(pattern))
+		s.names = append(s.names, values[value])
+	}
+	return s
+}
+
+// Scrub заменяет значения в тексте
+func (s *scrubber) Scrub(text string) string {
+	for n, pattern := range s.patterns {
+		replacement := "${1}" + s.names[n] + "${3}"
+		// Второй проход - для соседних вхождений: разделитель между ними поглощается первым совпадением
+		text = pattern.ReplaceAllString(pattern.ReplaceAllString(text, replacement), replacement)
+	}
+	return text
+}
+
+// wordStem отбрасывает с конца значения гласные, чтобы находить его словоформы («Москва» - «Москв»)
+func wordStem(value string) string {
+	value = strings.TrimSpace(value)
+	stem := strings.TrimRight(value, endingRunes+strings.ToUpper(endingRunes))
+	if utf8.RuneCountInString(stem) < minStemRunes {
+		return value
+	}
+	return stem
+}
@@ -0,0 +1,61 @@
+package retention
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"interview-bot-complete/internal/storage"
+	"os"
+	"path/filepath"
+)
+
+// appendToArchive дописывает файлы ответов в zip архив месяца. Zip нельзя дополнить на месте,
+// поэтому архив пересобирается: прежние записи копируются без распаковки, затем файл
+// атомарно заменяется вместе с контрольной суммой.
+func appendToArchive(archive string, batch []expired) error {
+	var buffer bytes.Buffer
+	writer := zip.NewWriter(&buffer)
+	existing := make(map[string]bool)
+
+	if data, err := storage.ReadArchive(archive); err == nil {
+		reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return fmt.Errorf("архив %s поврежден: %w", archive, err)
+		}
+		for _, file := range reader.File {
+			if err := writer.Copy(file); err != nil {
+				return fmt.Errorf("ошибка копирования %s из архива %s: %w", file.Name, archive, err)
+			}
+			existing[file.Name] = true
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	for _, item := range batch {
+		name, err := filepath.Rel(storage.ResultsDir(), item.path)
+		if err != nil {
+			name = filepath.Base(item.path)
+		}
+		name = filepath.ToSlash(name)
+		if existing[name] {
+			continue
+		}
+		data, err := os.ReadFile(item.path)
+		if err != nil {
+			return fmt.Errorf("ошибка чтения файла %s: %w", item.path, err)
+		}
+		entry, err := writer.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: item.finished})
+		if err != nil {
+			return fmt.Errorf("ошибка добавления %s в архив %s: %w", name, archive, err)
+		}
+		if _, err := entry.Write(data); err != nil {
+			return fmt.Errorf("ошибка записи %s в архив %s: %w", name, archive, err)
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("ошибка сборки архива %s: %w", archive, err)
+	}
+	return storage.WriteFileAtomic(archive, buffer.Bytes())
+}
@@ -0,0 +1,209 @@
+package retention
+
+import (
+	"errors"
+	"fmt"
+	"interview-bot-complete/internal/storage"
+	"log/slog"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Действия над ответами интервью старше срока хранения
+const (
+	ModeArchive = "archive" // перенести в архив <archive_dir>/<tenant>/answers_<YYYY-MM>.zip
+	ModeDelete  = "delete"  // удалить без архива
+)
+
+// Policy - срок хранения ответов интервью. Профили политикой не затрагиваются.
+type Policy struct {
+	// Days - через сколько дней после завершения интервью ответы удаляются; 0 - хранятся бессрочно
+	Days int
+	Mode string
+}
+
+// Enabled сообщает, ограничен ли срок хранения
+func (p Policy) Enabled() bool {
+	return p.Days > 0
+}
+
+// Validate проверяет срок и режим политики
+func (p Policy) Validate() error {
+	if p.Days < 0 {
+		return fmt.Errorf("срок хранения не может быть отрицательным: %d", p.Days)
+	}
+	if p.Mode != ModeArchive && p.Mode != ModeDelete {
+		return fmt.Errorf("неизвестный режим хранения %q: допустимы %s и %s", p.Mode, ModeArchive, ModeDelete)
+	}
+	return nil
+}
+
+// Report - итог одного прохода политики хранения
+type Report struct {
+	Checked  int
+	Archived int
+	Deleted  int
+	Skipped  int // поврежденные или нечитаемые результаты
+}
+
+// Job удаляет или архивирует ответы интервью старше срока хранения и пишет журнал retention.jsonl
+type Job struct {
+	policy     Policy
+	tenants    map[string]Policy
+	archiveDir string
+	logger     *slog.Logger
+	// mutex не дает ночному запуску пересечься с запуском вручную
+	mutex sync.Mutex
+}
+
+// New создает задачу хранения с политикой по умолчанию и директорией архивов
+func New(policy Policy, archiveDir string) *Job {
+	return &Job{
+		policy:     policy,
+		tenants:    make(map[string]Policy),
+		archiveDir: archiveDir,
+		logger:     slog.Default(),
+	}
+}
+
+// SetTenantPolicy задает арендатору собственный срок хранения
+func (j *Job) SetTenantPolicy(tenantID string, policy Policy) {
+	j.tenants[tenantID] = policy
+}
+
+// Enabled сообщает, ограничен ли срок хранения хотя бы одной политикой
+func (j *Job) Enabled() bool {
+	if j.policy.Enabled() {
+		return true
+	}
+	for _, policy := range j.tenants {
+		if policy.Enabled() {
+			return true
+		}
+	}
+	return false
+}
+
+// policyFor возвращает политику арендатора (политику по умолчанию, если своей нет)
+func (j *Job) policyFor(tenantID string) Policy {
+	if policy, ok := j.tenants[tenantID]; ok {
+		return policy
+	}
+	return j.policy
+}
+
+// expired - ответы интервью, подлежащие удалению или архивации
+type expired struct {
+	result   *storage.InterviewResult
+	path     string
+	finished time.Time
+	mode     string
+}
+
+// Run удаляет или архивирует ответы интервью, завершенных раньше срока хранения на момент now
+func (j *Job) Run(now time.Time) (*Report, error) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	ids, err := storage.ListResults()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{}
+	var candidates []expired
+	for _, id := range ids {
+		report.Checked++
+		result, err := storage.LoadResult(id)
+		if err != nil {
+			if !errors.Is(err, storage.ErrCorrupted) {
+				j.logger.Warn("Политика хранения: результат не прочитан", "interview_id", id, "error", err)
+			}
+			report.Skipped++
+			continue
+		}
+
+		policy := j.policyFor(result.Tenant)
+		finished := finishedAt(result)
+		if !policy.Enabled() || finished.IsZero() || now.Sub(finished) < time.Duration(policy.Days)*24*time.Hour {
+			continue
+		}
+		path, err := storage.FindResult(id)
+		if err != nil {
+			report.Skipped++
+			continue
+		}
+		candidates = append(candidates, expired{result: result, path: path, finished: finished, mode: policy.Mode})
+	}
+
+	// Ответы архивируются пачками по месяцам; ошибка одного архива не останавливает остальные
+	var errs []error
+	var records []storage.RetentionRecord
+	for archive, batch := range j.groupByArchive(candidates) {
+		if archive != "" {
+			if err := appendToArchive(archive, batch); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+		}
+		for _, item := range batch {
+			if err := storage.RemoveResult(item.result.InterviewID); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			record := storage.RetentionRecord{
+				InterviewID:   item.result.InterviewID,
+				Tenant:        item.result.Tenant,
+				Action:        storage.RetentionDeleted,
+				InterviewDate: item.finished.Format("2006-01-02"),
+				HasProfile:    hasProfile(item.result.InterviewID),
+				PurgedAt:      now.Format(time.RFC3339),
+			}
+			if archive != "" {
+				record.Action = storage.RetentionArchived
+				record.Archive = archive
+				report.Archived++
+			} else {
+				report.Deleted++
+			}
+			records = append(records, record)
+		}
+	}
+
+	sort.Slice(records, func(a, b int) bool { return records[a].InterviewID < records[b].InterviewID })
+	if err := storage.RecordRetention(records); err != nil {
+		errs = append(errs, err)
+	}
+	return report, errors.Join(errs...)
+}
+
+// groupByArchive раскладывает ответы по архивам арендатора и месяца; удаляемые без архива - под ключом ""
+func (j *Job) groupByArchive(candidates []expired) map[string][]expired {
+	groups := make(map[string][]expired)
+	for _, item := range candidates {
+		archive := ""
+		if item.mode == ModeArchive {
+			name := fmt.Sprintf("answers_%s.zip", item.finished.Format("2006-01"))
+			archive = filepath.Join(j.archiveDir, storage.TenantDir(item.result.Tenant), name)
+		}
+		groups[archive] = append(groups[archive], item)
+	}
+	return groups
+}
+
+// finishedAt возвращает время завершения интервью (начала, если интервью не завершено)
+func finishedAt(result *storage.InterviewResult) time.Time {
+	for _, timestamp := range []string{result.CompletedAt, result.Timestamp} {
+		if parsed, err := time.Parse(time.RFC3339, timestamp); err == nil {
+			return parsed
+		}
+	}
+	return time.Time{}
+}
+
+func hasProfile(interviewID string) bool {
+	_, err := storage.FindProfile(interviewID, 1)
+	return err == nil
+}
@@ -0,0 +1,44 @@
+package retention
+
+import (
+	"fmt"
+	"time"
+)
+
+// ParseClock разбирает время ежедневного запуска в формате ЧЧ:ММ
+func ParseClock(value string) (time.Duration, error) {
+	parsed, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("время запуска %q должно быть в формате ЧЧ:ММ", value)
+	}
+	return time.Duration(parsed.Hour())*time.Hour + time.Duration(parsed.Minute())*time.Minute, nil
+}
+
+// nextRun возвращает ближайший после now момент at (смещение от полуночи по местному времени)
+func nextRun(now time.Time, at time.Duration) time.Time {
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	next := midnight.Add(at)
+	if !next.After(now) {
+		next = midnight.AddDate(0, 0, 1).Add(at)
+	}
+	return next
+}
+
+// Start запускает проход политики хранения каждую ночь в at
+func (j *Job) Start(at time.Duration) {
+	go func() {
+		for {
+			next := nextRun(time.Now(), at)
+			time.Sleep(time.Until(next))
+
+			report, err := j.Run(time.Now())
+			if err != nil {
+				j.logger.Error("Ошибка политики хранения", "error", err)
+			}
+			if report != nil {
+				j.logger.Info("Политика хранения применена", "checked", report.Checked,
+					"archived", report.Archived, "deleted", report.Deleted, "skipped", report.Skipped)
+			}
+		}
+	}()
+}
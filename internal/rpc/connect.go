@@ -0,0 +1,185 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ServicePath - префикс путей InterviewService: POST <ServicePath><метод> по gRPC (HTTP/2, protobuf)
+// или по протоколу Connect (unary, JSON)
+const ServicePath = "/interview.v1.InterviewService/"
+
+// maxRequestBytes - максимальный размер тела запроса
+const maxRequestBytes = 64 << 10
+
+// stepWriteTimeout - срок ответа на шаг интервью: шаг может ждать бюджет OpenAI и саммари блока,
+// поэтому общий WriteTimeout HTTP сервера для InterviewService продлевается
+const stepWriteTimeout = 5 * time.Minute
+
+// Коды ошибок протокола Connect
+const (
+	CodeInvalidArgument    = "invalid_argument"
+	CodeNotFound           = "not_found"
+	CodeFailedPrecondition = "failed_precondition"
+	CodeResourceExhausted  = "resource_exhausted"
+	CodeUnavailable        = "unavailable"
+	CodeUnimplemented      = "unimplemented"
+	CodeDeadlineExceeded   = "deadline_exceeded"
+	CodeInternal           = "internal"
+)
+
+// codeStatus - HTTP статус для кода ошибки Connect
+var codeStatus = map[string]int{
+	CodeInvalidArgument:    http.StatusBadRequest,
+	CodeNotFound:           http.StatusNotFound,
+	CodeFailedPrecondition: http.StatusBadRequest,
+	CodeResourceExhausted:  http.StatusTooManyRequests,
+	CodeUnavailable:        http.StatusServiceUnavailable,
+	CodeUnimplemented:      http.StatusNotFound,
+	CodeDeadlineExceeded:   http.StatusGatewayTimeout,
+	CodeInternal:           http.StatusInternalServerError,
+}
+
+// Error - ошибка API в формате Connect: {"code": "...", "message": "..."}
+type Error struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return e.Code + ": " + e.Message
+}
+
+func newError(code, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+// method - вызов метода InterviewService; decode читает запрос в формате протокола
+type method func(ctx context.Context, decode func(req interface{}) error) (interface{}, error)
+
+func (s *Service) methods() map[string]method {
+	return map[string]method{
+		"StartInterview": func(ctx context.Context, decode func(interface{}) error) (interface{}, error) {
+			var req StartInterviewRequest
+			if err := decode(&req); err != nil {
+				return nil, err
+			}
+			return s.StartInterview(ctx, &req)
+		},
+		"SubmitAnswer": func(ctx context.Context, decode func(interface{}) error) (interface{}, error) {
+			var req SubmitAnswerRequest
+			if err := decode(&req); err != nil {
+				return nil, err
+			}
+			return s.SubmitAnswer(ctx, &req)
+		},
+		"GetNextQuestion": func(ctx context.Context, decode func(interface{}) error) (interface{}, error) {
+			var req GetNextQuestionRequest
+			if err := decode(&req); err != nil {
+				return nil, err
+			}
+			return s.GetNextQuestion(ctx, &req)
+		},
+		"GetProfile": func(ctx context.Context, decode func(interface{}) error) (interface{}, error) {
+			var req GetProfileRequest
+			if err := decode(&req); err != nil {
+				return nil, err
+			}
+			return s.GetProfile(ctx, &req)
+		},
+	}
+}
+
+// Handler возвращает HTTP обработчик InterviewService: запросы с Content-Type application/grpc
+// обслуживаются по gRPC, остальные - по протоколу Connect (unary, JSON). Регистрируется по пути ServicePath.
+func (s *Service) Handler() http.HandlerFunc {
+	methods := s.methods()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeError(w, http.StatusMethodNotAllowed, newError(CodeUnimplemented, "only POST is supported"))
+			return
+		}
+		if isGRPC(r) {
+			serveGRPC(w, r, methods)
+			return
+		}
+		if contentType := r.Header.Get("Content-Type"); !strings.HasPrefix(contentType, "application/json") {
+			writeError(w, http.StatusUnsupportedMediaType, newError(CodeInvalidArgument, "content type must be application/json"))
+			return
+		}
+
+		call, ok := methods[strings.TrimPrefix(r.URL.Path, ServicePath)]
+		if !ok {
+			writeError(w, 0, newError(CodeUnimplemented, "unknown method "+r.URL.Path))
+			return
+		}
+		extendWriteDeadline(w)
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestBytes+1))
+		if err != nil || len(body) > maxRequestBytes {
+			writeError(w, 0, newError(CodeInvalidArgument, "request body is too large or unreadable"))
+			return
+		}
+
+		response, err := call(r.Context(), func(req interface{}) error {
+			return decode(body, req)
+		})
+		if err != nil {
+			writeError(w, 0, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			slog.Warn("Ошибка отправки ответа API", "error", err)
+		}
+	}
+}
+
+// extendWriteDeadline продлевает срок ответа до stepWriteTimeout
+func extendWriteDeadline(w http.ResponseWriter) {
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Now().Add(stepWriteTimeout)); err != nil {
+		slog.Warn("Не удалось продлить срок ответа API", "error", err)
+	}
+}
+
+func decode(body []byte, req interface{}) error {
+	if len(body) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(body, req); err != nil {
+		return newError(CodeInvalidArgument, "invalid request: "+err.Error())
+	}
+	return nil
+}
+
+// toError возвращает ошибку API; прочие ошибки логируются и отдаются клиенту как internal
+func toError(err error) *Error {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return newError(CodeDeadlineExceeded, "deadline exceeded")
+	}
+	slog.Error("Внутренняя ошибка API", "error", err)
+	return newError(CodeInternal, "internal error")
+}
+
+// writeError отправляет ошибку; status 0 - статус по коду ошибки
+func writeError(w http.ResponseWriter, status int, err error) {
+	apiErr := toError(err)
+	if status == 0 {
+		status = codeStatus[apiErr.Code]
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(apiErr)
+}
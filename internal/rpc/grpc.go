@@ -0,0 +1,159 @@
+package rpc
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// gRPC поверх HTTP/2 (https://github.com/grpc/grpc/blob/master/doc/PROTOCOL-HTTP2.md): сообщение protobuf
+// в кадре с 5-байтовым префиксом (флаг сжатия и длина), статус вызова - в трейлерах Grpc-Status и
+// Grpc-Message. Поддерживаются unary-вызовы без сжатия. Отказы HandleAPI (401, 503) клиенты gRPC
+// получают по HTTP статусу как UNAUTHENTICATED и UNAVAILABLE.
+
+// grpcFrameHeader - размер префикса кадра gRPC
+const grpcFrameHeader = 5
+
+// grpcCodes - коды статуса gRPC для кодов ошибок
+var grpcCodes = map[string]int{
+	CodeInvalidArgument:    3,
+	CodeNotFound:           5,
+	CodeFailedPrecondition: 9,
+	CodeResourceExhausted:  8,
+	CodeUnavailable:        14,
+	CodeUnimplemented:      12,
+	CodeDeadlineExceeded:   4,
+	CodeInternal:           13,
+}
+
+// grpcTimeoutUnits - единицы заголовка Grpc-Timeout
+var grpcTimeoutUnits = map[byte]time.Duration{
+	'H': time.Hour,
+	'M': time.Minute,
+	'S': time.Second,
+	'm': time.Millisecond,
+	'u': time.Microsecond,
+	'n': time.Nanosecond,
+}
+
+// isGRPC сообщает, что запрос отправлен клиентом gRPC
+func isGRPC(r *http.Request) bool {
+	contentType := r.Header.Get("Content-Type")
+	return contentType == "application/grpc" || strings.HasPrefix(contentType, "application/grpc+")
+}
+
+// serveGRPC выполняет unary-вызов gRPC: ответ - кадр с сообщением, статус - в трейлерах
+func serveGRPC(w http.ResponseWriter, r *http.Request, methods map[string]method) {
+	if r.ProtoMajor != 2 {
+		http.Error(w, "gRPC requires HTTP/2", http.StatusHTTPVersionNotSupported)
+		return
+	}
+	if contentType := r.Header.Get("Content-Type"); contentType != "application/grpc" && contentType != "application/grpc+proto" {
+		http.Error(w, "only protobuf messages are supported", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/grpc")
+	w.Header().Set("Trailer", "Grpc-Status, Grpc-Message")
+	w.WriteHeader(http.StatusOK)
+
+	response, err := callGRPC(w, r, methods)
+	if err == nil {
+		message := marshalProto(response)
+		frame := make([]byte, grpcFrameHeader, grpcFrameHeader+len(message))
+		binary.BigEndian.PutUint32(frame[1:], uint32(len(message)))
+		if _, err := w.Write(append(frame, message...)); err != nil {
+			return
+		}
+	}
+	writeGRPCStatus(w, err)
+}
+
+func callGRPC(w http.ResponseWriter, r *http.Request, methods map[string]method) (interface{}, error) {
+	call, ok := methods[strings.TrimPrefix(r.URL.Path, ServicePath)]
+	if !ok {
+		return nil, newError(CodeUnimplemented, "unknown method "+r.URL.Path)
+	}
+	extendWriteDeadline(w)
+
+	ctx := r.Context()
+	if timeout, ok := grpcTimeout(r.Header.Get("Grpc-Timeout")); ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	message, err := readGRPCMessage(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	return call(ctx, func(req interface{}) error {
+		if err := unmarshalProto(message, req); err != nil {
+			return newError(CodeInvalidArgument, "invalid request: "+err.Error())
+		}
+		return nil
+	})
+}
+
+// readGRPCMessage читает кадр с сообщением запроса
+func readGRPCMessage(body io.Reader) ([]byte, error) {
+	var header [grpcFrameHeader]byte
+	if _, err := io.ReadFull(body, header[:]); err != nil {
+		return nil, newError(CodeInvalidArgument, "request message is missing")
+	}
+	if header[0] != 0 {
+		return nil, newError(CodeUnimplemented, "compressed messages are not supported")
+	}
+	length := binary.BigEndian.Uint32(header[1:])
+	if length > maxRequestBytes {
+		return nil, newError(CodeResourceExhausted, fmt.Sprintf("request message is larger than %d bytes", maxRequestBytes))
+	}
+	message := make([]byte, length)
+	if _, err := io.ReadFull(body, message); err != nil {
+		return nil, newError(CodeInvalidArgument, "request message is truncated")
+	}
+	return message, nil
+}
+
+// writeGRPCStatus записывает статус вызова в трейлеры; err nil - OK
+func writeGRPCStatus(w http.ResponseWriter, err error) {
+	if err == nil {
+		w.Header().Set("Grpc-Status", "0")
+		return
+	}
+	apiErr := toError(err)
+	w.Header().Set("Grpc-Status", strconv.Itoa(grpcCodes[apiErr.Code]))
+	w.Header().Set("Grpc-Message", grpcMessage(apiErr.Message))
+}
+
+// grpcMessage кодирует текст ошибки для Grpc-Message: байты вне печатного ASCII и «%» - как %XX
+func grpcMessage(text string) string {
+	var encoded strings.Builder
+	for i := 0; i < len(text); i++ {
+		if c := text[i]; c < ' ' || c > '~' || c == '%' {
+			fmt.Fprintf(&encoded, "%%%02X", c)
+		} else {
+			encoded.WriteByte(c)
+		}
+	}
+	return encoded.String()
+}
+
+// grpcTimeout разбирает заголовок Grpc-Timeout: до 8 цифр и единица (H, M, S, m, u, n)
+func grpcTimeout(value string) (time.Duration, bool) {
+	if len(value) < 2 || len(value) > 9 {
+		return 0, false
+	}
+	unit, ok := grpcTimeoutUnits[value[len(value)-1]]
+	amount, err := strconv.ParseInt(value[:len(value)-1], 10, 64)
+	if !ok || err != nil || amount < 0 || amount > math.MaxInt64/int64(unit) {
+		return 0, false
+	}
+	return time.Duration(amount) * unit, true
+}
@@ -0,0 +1,197 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// startGRPCServer запускает h2c сервер с обработчиком InterviewService и возвращает клиента HTTP/2 без TLS
+func startGRPCServer(t *testing.T, handler http.Handler) (*httptest.Server, *http.Client) {
+	t.Helper()
+	server := httptest.NewUnstartedServer(handler)
+	server.Config.Protocols = new(http.Protocols)
+	server.Config.Protocols.SetHTTP1(true)
+	server.Config.Protocols.SetUnencryptedHTTP2(true)
+	server.Start()
+	t.Cleanup(server.Close)
+
+	protocols := new(http.Protocols)
+	protocols.SetUnencryptedHTTP2(true)
+	return server, &http.Client{Transport: &http.Transport{Protocols: protocols}}
+}
+
+// callGRPCMethod выполняет unary-вызов и возвращает сообщение ответа и трейлеры со статусом
+func callGRPCMethod(t *testing.T, server *httptest.Server, client *http.Client, name string, req interface{}) ([]byte, http.Header) {
+	t.Helper()
+	message := marshalProto(req)
+	frame := make([]byte, grpcFrameHeader, grpcFrameHeader+len(message))
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(message)))
+
+	request, err := http.NewRequest(http.MethodPost, server.URL+ServicePath+name, bytes.NewReader(append(frame, message...)))
+	if err != nil {
+		t.Fatalf("ошибка создания запроса: %v", err)
+	}
+	request.Header.Set("Content-Type", "application/grpc")
+	request.Header.Set("Te", "trailers")
+	response, err := client.Do(request)
+	if err != nil {
+		t.Fatalf("ошибка вызова %s: %v", name, err)
+	}
+	defer response.Body.Close()
+
+	if response.ProtoMajor != 2 || response.StatusCode != http.StatusOK || response.Header.Get("Content-Type") != "application/grpc" {
+		t.Fatalf("%s: ответ %s %d %q, ожидался HTTP/2 200 application/grpc",
+			name, response.Proto, response.StatusCode, response.Header.Get("Content-Type"))
+	}
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		t.Fatalf("ошибка чтения ответа %s: %v", name, err)
+	}
+	if len(body) == 0 {
+		return nil, response.Trailer
+	}
+	if len(body) < grpcFrameHeader || int(binary.BigEndian.Uint32(body[1:grpcFrameHeader])) != len(body)-grpcFrameHeader {
+		t.Fatalf("%s: некорректный кадр ответа % x", name, body)
+	}
+	return body[grpcFrameHeader:], response.Trailer
+}
+
+// TestProtoWireFormat проверяет кодирование полей в формат protobuf
+func TestProtoWireFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		message interface{}
+		want    []byte
+	}{
+		{name: "строка", message: &GetProfileRequest{InterviewID: "ab"}, want: []byte{0x0a, 2, 'a', 'b'}},
+		{name: "значения по умолчанию", message: &GetProfileResponse{}, want: nil},
+		{name: "int64", message: &StartInterviewRequest{UserID: 300}, want: []byte{0x08, 0xac, 0x02}},
+		{name: "enum", message: &GetNextQuestionResponse{Phase: PhaseCompleted}, want: []byte{0x08, 3}},
+		{
+			name:    "вложенное сообщение и repeated",
+			message: &SubmitAnswerResponse{Question: &Question{Kind: "a"}, Events: []Event{{Kind: "b"}, {}}},
+			want:    []byte{0x12, 3, 0x0a, 1, 'a', 0x1a, 3, 0x0a, 1, 'b', 0x1a, 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := marshalProto(tt.message); !bytes.Equal(got, tt.want) {
+				t.Errorf("сообщение % x, ожидалось % x", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestProtoRoundTrip проверяет, что декодирование восстанавливает закодированное сообщение
+func TestProtoRoundTrip(t *testing.T) {
+	want := &StartInterviewResponse{
+		InterviewID: "interview_1",
+		Phase:       PhaseBlockPending,
+		Question: &Question{
+			Kind: "question", Text: "Сколько лет опыта?", Block: 2, BlockTitle: "Опыт", Number: -1, Variant: "b2.q1.v2",
+			Options: []string{"1-3", "3+", ""}, AllowOther: true, AnswerType: "number",
+		},
+		Events: []Event{{Kind: "block_started", Block: 2, BlockTitle: "Опыт"}, {Kind: "block_finished"}},
+	}
+	got := &StartInterviewResponse{}
+	if err := unmarshalProto(marshalProto(want), got); err != nil {
+		t.Fatalf("ошибка декодирования: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("декодировано %+v, ожидалось %+v", got, want)
+	}
+
+	// Неизвестные поля всех типов пропускаются
+	unknown := []byte{0x78, 1, 0x79, 1, 2, 3, 4, 5, 6, 7, 8, 0x7a, 1, 'x', 0x7d, 1, 2, 3, 4, 0x0a, 1, 'y'}
+	var req GetProfileRequest
+	if err := unmarshalProto(unknown, &req); err != nil || req.InterviewID != "y" {
+		t.Errorf("неизвестные поля: %+v, ошибка %v", req, err)
+	}
+
+	malformed := map[string][]byte{
+		"обрезанная строка":      {0x0a, 5, 'a'},
+		"обрезанный varint":      {0x08, 0xff},
+		"неверный тип кодировки": {0x0a ^ 0x02, 1},
+		"невалидный UTF-8":       {0x0a, 1, 0xff},
+	}
+	for name, data := range malformed {
+		if err := unmarshalProto(data, &GetProfileRequest{}); err == nil {
+			t.Errorf("%s: ожидалась ошибка декодирования", name)
+		}
+	}
+}
+
+// TestGRPCHandler проверяет вызовы InterviewService по gRPC: кадры, статусы в трейлерах и их коды
+func TestGRPCHandler(t *testing.T) {
+	service := &Service{interviews: make(map[string]*interview)}
+	methods := service.methods()
+	methods["Echo"] = func(ctx context.Context, decode func(interface{}) error) (interface{}, error) {
+		var req GetProfileRequest
+		if err := decode(&req); err != nil {
+			return nil, err
+		}
+		return &GetProfileResponse{Status: ProfileReady, ProfileJSON: req.InterviewID}, nil
+	}
+	server, client := startGRPCServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serveGRPC(w, r, methods)
+	}))
+
+	message, trailer := callGRPCMethod(t, server, client, "Echo", &GetProfileRequest{InterviewID: "{}"})
+	var response GetProfileResponse
+	if err := unmarshalProto(message, &response); err != nil {
+		t.Fatalf("ошибка декодирования ответа: %v", err)
+	}
+	if trailer.Get("Grpc-Status") != "0" || response != (GetProfileResponse{Status: ProfileReady, ProfileJSON: "{}"}) {
+		t.Errorf("Echo: статус %q, ответ %+v", trailer.Get("Grpc-Status"), response)
+	}
+
+	tests := []struct {
+		name    string
+		method  string
+		req     interface{}
+		status  string
+		message string
+	}{
+		{name: "неизвестный метод", method: "Unknown", req: &GetProfileRequest{}, status: "12",
+			message: "unknown method " + ServicePath + "Unknown"},
+		{name: "неверный аргумент", method: "GetProfile", req: &GetProfileRequest{}, status: "3",
+			message: "interview_id is required"},
+		{name: "интервью не найдено", method: "SubmitAnswer", req: &SubmitAnswerRequest{InterviewID: "нет", Answer: "да"},
+			status: "5", message: "interview not found"},
+		{name: "сервис недоступен", method: "GetProfile", req: &GetProfileRequest{InterviewID: "1"}, status: "14",
+			message: "profile extraction is disabled"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message, trailer := callGRPCMethod(t, server, client, tt.method, tt.req)
+			if message != nil {
+				t.Errorf("при ошибке не ожидалось сообщение, получено % x", message)
+			}
+			if got := trailer.Get("Grpc-Status"); got != tt.status {
+				t.Errorf("Grpc-Status %q, ожидался %q", got, tt.status)
+			}
+			if got := trailer.Get("Grpc-Message"); got != tt.message {
+				t.Errorf("Grpc-Message %q, ожидалось %q", got, tt.message)
+			}
+		})
+	}
+}
+
+// TestGRPCTimeoutAndMessage проверяет разбор Grpc-Timeout и кодирование Grpc-Message
+func TestGRPCTimeoutAndMessage(t *testing.T) {
+	timeouts := map[string]bool{"100m": true, "5S": true, "99999999S": true, "99999999H": false, "1": false, "10x": false, "-1S": false, "123456789S": false}
+	for value, valid := range timeouts {
+		if _, ok := grpcTimeout(value); ok != valid {
+			t.Errorf("Grpc-Timeout %q: разобран %v, ожидалось %v", value, ok, valid)
+		}
+	}
+	if got, want := grpcMessage("50% нет\n"), "50%25 %D0%BD%D0%B5%D1%82%0A"; got != want {
+		t.Errorf("Grpc-Message %q, ожидалось %q", got, want)
+	}
+}
@@ -0,0 +1,212 @@
+package rpc
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"unicode/utf8"
+)
+
+// Двоичный формат protobuf для сообщений gRPC. Номер поля задается тегом protobuf:"<номер>" у полей
+// структур из types.go; типы полей соответствуют proto: string, int32/int64 (int, Int64), bool, enum
+// (protoEnum), вложенное сообщение (*struct) и repeated (срезы).
+
+// Типы кодирования полей (wire types)
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+var errTruncated = errors.New("truncated message")
+
+// protoEnum - enum proto, который в Go хранится именем значения
+type protoEnum interface {
+	enumNumber() int32
+}
+
+// protoEnumSetter восстанавливает значение enum по номеру
+type protoEnumSetter interface {
+	setEnumNumber(number int32)
+}
+
+// marshalProto кодирует сообщение (указатель на структуру)
+func marshalProto(message interface{}) []byte {
+	return appendMessage(nil, reflect.ValueOf(message).Elem())
+}
+
+// unmarshalProto декодирует сообщение в структуру по указателю; неизвестные поля пропускаются
+func unmarshalProto(data []byte, message interface{}) error {
+	return decodeMessage(data, reflect.ValueOf(message).Elem())
+}
+
+// fieldNumbers возвращает индексы полей структуры по номерам полей proto
+func fieldNumbers(t reflect.Type) map[int]int {
+	numbers := make(map[int]int, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		if number, err := strconv.Atoi(t.Field(i).Tag.Get("protobuf")); err == nil {
+			numbers[number] = i
+		}
+	}
+	return numbers
+}
+
+func appendMessage(buf []byte, v reflect.Value) []byte {
+	for i := 0; i < v.NumField(); i++ {
+		number, err := strconv.Atoi(v.Type().Field(i).Tag.Get("protobuf"))
+		if err != nil {
+			continue
+		}
+		field := v.Field(i)
+		if field.Kind() == reflect.Slice {
+			for j := 0; j < field.Len(); j++ {
+				buf = appendValue(buf, number, field.Index(j))
+			}
+			continue
+		}
+		// proto3: значения по умолчанию не передаются
+		if !field.IsZero() {
+			buf = appendValue(buf, number, field)
+		}
+	}
+	return buf
+}
+
+func appendValue(buf []byte, number int, v reflect.Value) []byte {
+	if enum, ok := v.Interface().(protoEnum); ok {
+		return binary.AppendUvarint(appendTag(buf, number, wireVarint), uint64(enum.enumNumber()))
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return appendBytes(appendTag(buf, number, wireBytes), []byte(v.String()))
+	case reflect.Bool:
+		value := uint64(0)
+		if v.Bool() {
+			value = 1
+		}
+		return binary.AppendUvarint(appendTag(buf, number, wireVarint), value)
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		return binary.AppendUvarint(appendTag(buf, number, wireVarint), uint64(v.Int()))
+	case reflect.Pointer:
+		return appendBytes(appendTag(buf, number, wireBytes), appendMessage(nil, v.Elem()))
+	case reflect.Struct:
+		return appendBytes(appendTag(buf, number, wireBytes), appendMessage(nil, v))
+	}
+	panic(fmt.Sprintf("rpc: unsupported protobuf field type %s", v.Type()))
+}
+
+func appendTag(buf []byte, number, wire int) []byte {
+	return binary.AppendUvarint(buf, uint64(number)<<3|uint64(wire))
+}
+
+func appendBytes(buf, value []byte) []byte {
+	return append(binary.AppendUvarint(buf, uint64(len(value))), value...)
+}
+
+func decodeMessage(data []byte, v reflect.Value) error {
+	numbers := fieldNumbers(v.Type())
+	for len(data) > 0 {
+		key, n := binary.Uvarint(data)
+		if n <= 0 {
+			return errTruncated
+		}
+		data = data[n:]
+		number, wire := int(key>>3), int(key&7)
+
+		var varint uint64
+		var raw []byte
+		switch wire {
+		case wireVarint:
+			varint, n = binary.Uvarint(data)
+			if n <= 0 {
+				return errTruncated
+			}
+			data = data[n:]
+		case wireFixed64, wireFixed32:
+			size := 8
+			if wire == wireFixed32 {
+				size = 4
+			}
+			if len(data) < size {
+				return errTruncated
+			}
+			data = data[size:]
+		case wireBytes:
+			length, n := binary.Uvarint(data)
+			if n <= 0 || length > uint64(len(data)-n) {
+				return errTruncated
+			}
+			raw = data[n : n+int(length)]
+			data = data[n+int(length):]
+		default:
+			return fmt.Errorf("field %d: unsupported wire type %d", number, wire)
+		}
+
+		i, ok := numbers[number]
+		if !ok {
+			continue
+		}
+		if err := setField(v.Field(i), wire, varint, raw); err != nil {
+			return fmt.Errorf("field %d: %w", number, err)
+		}
+	}
+	return nil
+}
+
+// setField записывает значение поля; значения repeated полей добавляются к срезу
+func setField(v reflect.Value, wire int, varint uint64, raw []byte) error {
+	if v.Kind() != reflect.Slice {
+		return setValue(v, wire, varint, raw)
+	}
+	item := reflect.New(v.Type().Elem()).Elem()
+	if err := setValue(item, wire, varint, raw); err != nil {
+		return err
+	}
+	v.Set(reflect.Append(v, item))
+	return nil
+}
+
+func setValue(v reflect.Value, wire int, varint uint64, raw []byte) error {
+	enum, isEnum := v.Addr().Interface().(protoEnumSetter)
+	expected := wireBytes
+	switch v.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int32, reflect.Int64:
+		expected = wireVarint
+	}
+	if isEnum {
+		expected = wireVarint
+	}
+	if wire != expected {
+		return fmt.Errorf("unexpected wire type %d", wire)
+	}
+
+	if isEnum {
+		enum.setEnumNumber(int32(varint))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		if !utf8.Valid(raw) {
+			return errors.New("string is not valid UTF-8")
+		}
+		v.SetString(string(raw))
+	case reflect.Bool:
+		v.SetBool(varint != 0)
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		v.SetInt(int64(varint))
+	case reflect.Pointer:
+		message := reflect.New(v.Type().Elem())
+		if err := decodeMessage(raw, message.Elem()); err != nil {
+			return err
+		}
+		v.Set(message)
+	case reflect.Struct:
+		return decodeMessage(raw, v)
+	default:
+		panic(fmt.Sprintf("rpc: unsupported protobuf field type %s", v.Type()))
+	}
+	return nil
+}
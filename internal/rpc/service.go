@@ -0,0 +1,296 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"interview-bot-complete/internal/answercheck"
+	"interview-bot-complete/internal/api"
+	"interview-bot-complete/internal/config"
+	"interview-bot-complete/internal/engine"
+	"interview-bot-complete/internal/extractor"
+	"interview-bot-complete/internal/metrics"
+	"interview-bot-complete/internal/storage"
+	"strings"
+	"sync"
+	"time"
+)
+
+// interviewTTL - время неактивности, после которого интервью удаляется из памяти
+const interviewTTL = 24 * time.Hour
+
+// interview - интервью, проводимое через API
+type interview struct {
+	mu           sync.Mutex // шаги одного интервью выполняются последовательно
+	state        *engine.Session
+	lastActivity time.Time
+	profile      GetProfileResponse
+}
+
+// Service реализует InterviewService поверх движка интервью
+type Service struct {
+	engine     *engine.Engine
+	extractor  *extractor.Service
+	metrics    *metrics.Registry
+	interviews map[string]*interview
+	mutex      sync.Mutex
+}
+
+// NewService создает сервис API интервью
+func NewService(interviewEngine *engine.Engine) *Service {
+	s := &Service{
+		engine:     interviewEngine,
+		interviews: make(map[string]*interview),
+	}
+	s.startCleanup()
+	return s
+}
+
+// SetExtractor включает составление профиля после завершения интервью
+func (s *Service) SetExtractor(extractorService *extractor.Service) {
+	s.extractor = extractorService
+}
+
+// SetMetrics подключает реестр метрик
+func (s *Service) SetMetrics(registry *metrics.Registry) {
+	s.metrics = registry
+}
+
+// StartInterview создает интервью и возвращает первый вопрос
+func (s *Service) StartInterview(ctx context.Context, req *StartInterviewRequest) (*StartInterviewResponse, error) {
+	if req.UserID == 0 {
+		return nil, newError(CodeInvalidArgument, "user_id is required")
+	}
+	if req.TemplateID != "" {
+		if _, ok := s.templates().Get(req.TemplateID); !ok {
+			return nil, newError(CodeInvalidArgument, fmt.Sprintf("unknown template %q (available: %s)",
+				req.TemplateID, strings.Join(s.templates().IDs(), ", ")))
+		}
+	}
+
+	item := &interview{
+		state:        s.engine.NewInterview(int64(req.UserID), req.TemplateID),
+		lastActivity: time.Now(),
+	}
+	// По отметке GetProfile отдает профили только интервью, начатых через API
+	item.state.Result.Channel = storage.ChannelAPI
+	s.engine.SetLanguage(item.state, req.Language)
+	if err := s.engine.SetPersona(item.state, req.Persona); err != nil {
+		return nil, newError(CodeInvalidArgument, fmt.Sprintf("unknown persona %q", req.Persona))
+	}
+	item.mu.Lock()
+	defer item.mu.Unlock()
+
+	s.mutex.Lock()
+	s.interviews[item.state.InterviewID] = item
+	s.mutex.Unlock()
+
+	prompt, events, err := s.engine.Start(ctx, item.state)
+	if err != nil {
+		s.mutex.Lock()
+		delete(s.interviews, item.state.InterviewID)
+		s.mutex.Unlock()
+		return nil, s.engineError(item.state, err)
+	}
+	s.afterStep(item, events)
+	return &StartInterviewResponse{
+		InterviewID: item.state.InterviewID,
+		Phase:       toPhase(item.state.Phase),
+		Question:    toQuestion(prompt),
+		Events:      toEvents(events),
+	}, nil
+}
+
+// SubmitAnswer принимает ответ на текущий вопрос
+func (s *Service) SubmitAnswer(ctx context.Context, req *SubmitAnswerRequest) (*SubmitAnswerResponse, error) {
+	answer := strings.TrimSpace(req.Answer)
+	if answer == "" {
+		return nil, newError(CodeInvalidArgument, "answer is required")
+	}
+
+	item, err := s.lookup(req.InterviewID)
+	if err != nil {
+		return nil, err
+	}
+	item.mu.Lock()
+	defer item.mu.Unlock()
+
+	// Правила validation шаблона; в фазах проверки и завершения блока ответ не используется
+	if session := item.state; session.Phase == engine.PhaseAnswering {
+		rules := s.engine.Config(session).AnswerRules(session.CurrentBlock, session.QuestionCount)
+		if err := answercheck.Check(rules, answer); err != nil {
+			return nil, newError(CodeInvalidArgument, err.Error())
+		}
+	}
+
+	prompt, events, err := s.engine.Advance(ctx, item.state, answer)
+	if err != nil {
+		return nil, s.engineError(item.state, err)
+	}
+	s.afterStep(item, events)
+	return &SubmitAnswerResponse{
+		Phase:    toPhase(item.state.Phase),
+		Question: toQuestion(prompt),
+		Events:   toEvents(events),
+	}, nil
+}
+
+// GetNextQuestion возвращает вопрос, ожидающий ответа
+func (s *Service) GetNextQuestion(ctx context.Context, req *GetNextQuestionRequest) (*GetNextQuestionResponse, error) {
+	item, err := s.lookup(req.InterviewID)
+	if err != nil {
+		return nil, err
+	}
+	item.mu.Lock()
+	defer item.mu.Unlock()
+
+	item.lastActivity = time.Now()
+	return &GetNextQuestionResponse{
+		Phase:    toPhase(item.state.Phase),
+		Question: toQuestion(s.engine.Current(item.state)),
+	}, nil
+}
+
+// GetProfile возвращает профиль завершенного интервью. Интервью, которых уже нет в памяти,
+// ищутся среди сохраненных профилей, но только начатые через API: профили участников
+// Telegram по этому API недоступны.
+func (s *Service) GetProfile(ctx context.Context, req *GetProfileRequest) (*GetProfileResponse, error) {
+	if req.InterviewID == "" {
+		return nil, newError(CodeInvalidArgument, "interview_id is required")
+	}
+	if s.extractor == nil {
+		return nil, newError(CodeUnavailable, "profile extraction is disabled")
+	}
+
+	s.mutex.Lock()
+	item, ok := s.interviews[req.InterviewID]
+	s.mutex.Unlock()
+	if !ok {
+		if result, err := storage.LoadResult(req.InterviewID); err != nil || result.Channel != storage.ChannelAPI {
+			return nil, newError(CodeNotFound, "interview not found")
+		}
+		profileJSON, err := s.extractor.GetLastProfileJSON(req.InterviewID)
+		if err != nil {
+			return nil, newError(CodeNotFound, "interview not found")
+		}
+		return &GetProfileResponse{Status: ProfileReady, ProfileJSON: profileJSON}, nil
+	}
+
+	item.mu.Lock()
+	defer item.mu.Unlock()
+	if item.state.Phase != engine.PhaseCompleted {
+		return nil, newError(CodeFailedPrecondition, "interview is not completed yet")
+	}
+	response := item.profile
+	return &response, nil
+}
+
+// afterStep обновляет время активности и запускает составление профиля после завершения интервью
+func (s *Service) afterStep(item *interview, events []engine.Event) {
+	item.lastActivity = time.Now()
+	for _, event := range events {
+		if event.Kind != engine.EventInterviewCompleted {
+			continue
+		}
+		if s.extractor == nil {
+			item.profile = GetProfileResponse{Status: ProfileFailed, Error: "profile extraction is disabled"}
+			continue
+		}
+		item.profile = GetProfileResponse{Status: ProfilePending}
+		go s.extractProfile(item)
+	}
+}
+
+// extractProfile составляет и сохраняет профиль завершенного интервью
+func (s *Service) extractProfile(item *interview) {
+	item.mu.Lock()
+	state := item.state
+	item.mu.Unlock()
+
+	settings := s.engine.Config(state).ModelFor(config.UseCaseExtraction)
+	started := time.Now()
+	profileResult, err := s.extractor.ExtractProfileWithOptions(state.Result, extractor.ExtractOptions{
+		Model:       settings.Model,
+		Temperature: settings.Temperature,
+		MaxTokens:   settings.MaxTokens,
+		UserID:      state.UserID,
+		Logger:      s.engine.Logger(state),
+	})
+	if err == nil && !profileResult.Success {
+		err = errors.New(profileResult.Error)
+	}
+	if err == nil {
+		_, err = s.extractor.SaveProfile(state.InterviewID, profileResult)
+	}
+
+	item.mu.Lock()
+	defer item.mu.Unlock()
+	if err != nil {
+		s.metrics.ProfileFailed(err)
+		s.engine.Logger(state).Error("Ошибка составления профиля (API)", "error", err)
+		item.profile = GetProfileResponse{Status: ProfileFailed, Error: err.Error()}
+		return
+	}
+	s.metrics.ProfileGenerated(profileResult.Model, profileResult.Usage.PromptTokens, profileResult.Usage.CompletionTokens,
+		api.EstimateCost(profileResult.Model, profileResult.Usage), time.Since(started))
+	item.profile = GetProfileResponse{Status: ProfileReady, ProfileJSON: profileResult.ProfileJSON}
+}
+
+func (s *Service) lookup(interviewID string) (*interview, error) {
+	if interviewID == "" {
+		return nil, newError(CodeInvalidArgument, "interview_id is required")
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	item, ok := s.interviews[interviewID]
+	if !ok {
+		return nil, newError(CodeNotFound, "interview not found")
+	}
+	return item, nil
+}
+
+func (s *Service) templates() *config.Templates {
+	return s.engine.Templates()
+}
+
+// startCleanup периодически удаляет неактивные интервью
+func (s *Service) startCleanup() {
+	ticker := time.NewTicker(time.Hour)
+	go func() {
+		for range ticker.C {
+			s.cleanup(time.Now().Add(-interviewTTL))
+		}
+	}()
+}
+
+func (s *Service) cleanup(cutoff time.Time) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for id, item := range s.interviews {
+		if !item.mu.TryLock() {
+			continue
+		}
+		if item.lastActivity.Before(cutoff) {
+			delete(s.interviews, id)
+		}
+		item.mu.Unlock()
+	}
+}
+
+// engineError переводит ошибку движка в ошибку API
+func (s *Service) engineError(state *engine.Session, err error) error {
+	var typeErr *engine.AnswerTypeError
+	switch {
+	case errors.Is(err, engine.ErrCompleted):
+		return newError(CodeFailedPrecondition, "interview is already completed")
+	case errors.Is(err, engine.ErrBusy):
+		return newError(CodeResourceExhausted, "analysis service is overloaded, retry later")
+	case errors.Is(err, engine.ErrInvalidChoice):
+		return newError(CodeInvalidArgument, "answer must be one of the question options")
+	case errors.As(err, &typeErr):
+		return newError(CodeInvalidArgument, "answer must be a "+typeErr.AnswerType)
+	default:
+		s.engine.Logger(state).Error("Ошибка шага интервью (API)", "error", err)
+		return newError(CodeUnavailable, "step failed, retry the request: "+err.Error())
+	}
+}
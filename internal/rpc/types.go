@@ -0,0 +1,172 @@
+package rpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"interview-bot-complete/internal/engine"
+	"strconv"
+)
+
+// Сообщения InterviewService (proto/interview/v1/interview.proto): имена полей JSON - как в protojson
+// (Connect), тег protobuf - номер поля в proto (gRPC)
+
+// Int64 - int64 из protojson: принимается и строкой, и числом
+type Int64 int64
+
+func (i *Int64) UnmarshalJSON(data []byte) error {
+	var raw json.Number
+	if err := json.Unmarshal(data, &raw); err != nil {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return fmt.Errorf("invalid int64 value %s", data)
+		}
+		raw = json.Number(s)
+	}
+	value, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid int64 value %s", data)
+	}
+	*i = Int64(value)
+	return nil
+}
+
+func (i Int64) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strconv.FormatInt(int64(i), 10))
+}
+
+// Phase - enum Phase: в JSON передается именем значения, в protobuf - номером
+type Phase string
+
+// Значения enum Phase
+const (
+	PhaseUnspecified  Phase = "PHASE_UNSPECIFIED"
+	PhaseAnswering    Phase = "PHASE_ANSWERING"
+	PhaseBlockPending Phase = "PHASE_BLOCK_PENDING"
+	PhaseCompleted    Phase = "PHASE_COMPLETED"
+)
+
+// phaseValues - значения Phase по номерам из proto
+var phaseValues = []Phase{PhaseUnspecified, PhaseAnswering, PhaseBlockPending, PhaseCompleted}
+
+func (p Phase) enumNumber() int32 {
+	for number, value := range phaseValues {
+		if value == p {
+			return int32(number)
+		}
+	}
+	return 0
+}
+
+func (p *Phase) setEnumNumber(number int32) {
+	*p = PhaseUnspecified
+	if number >= 0 && int(number) < len(phaseValues) {
+		*p = phaseValues[number]
+	}
+}
+
+type Question struct {
+	Kind       string `json:"kind" protobuf:"1"`
+	Text       string `json:"text" protobuf:"2"`
+	Block      int    `json:"block" protobuf:"3"`
+	BlockTitle string `json:"blockTitle" protobuf:"4"`
+	Number     int    `json:"number" protobuf:"5"`
+	Variant    string `json:"variant,omitempty" protobuf:"6"`
+	// Options - варианты ответа закрытого вопроса; ответ - текст или номер варианта
+	Options    []string `json:"options,omitempty" protobuf:"7"`
+	AllowOther bool     `json:"allowOther,omitempty" protobuf:"8"`
+	// AnswerType - ожидаемый тип ответа открытого вопроса: number, date или list
+	AnswerType string `json:"answerType,omitempty" protobuf:"9"`
+}
+
+type Event struct {
+	Kind       string `json:"kind" protobuf:"1"`
+	Block      int    `json:"block,omitempty" protobuf:"2"`
+	BlockTitle string `json:"blockTitle,omitempty" protobuf:"3"`
+}
+
+type StartInterviewRequest struct {
+	UserID     Int64  `json:"userId" protobuf:"1"`
+	TemplateID string `json:"templateId" protobuf:"2"`
+	Language   string `json:"language,omitempty" protobuf:"3"`
+	Persona    string `json:"persona,omitempty" protobuf:"4"`
+}
+
+type StartInterviewResponse struct {
+	InterviewID string    `json:"interviewId" protobuf:"1"`
+	Phase       Phase     `json:"phase" protobuf:"2"`
+	Question    *Question `json:"question,omitempty" protobuf:"3"`
+	Events      []Event   `json:"events,omitempty" protobuf:"4"`
+}
+
+type SubmitAnswerRequest struct {
+	InterviewID string `json:"interviewId" protobuf:"1"`
+	Answer      string `json:"answer" protobuf:"2"`
+}
+
+type SubmitAnswerResponse struct {
+	Phase    Phase     `json:"phase" protobuf:"1"`
+	Question *Question `json:"question,omitempty" protobuf:"2"`
+	Events   []Event   `json:"events,omitempty" protobuf:"3"`
+}
+
+type GetNextQuestionRequest struct {
+	InterviewID string `json:"interviewId" protobuf:"1"`
+}
+
+type GetNextQuestionResponse struct {
+	Phase    Phase     `json:"phase" protobuf:"1"`
+	Question *Question `json:"question,omitempty" protobuf:"2"`
+}
+
+type GetProfileRequest struct {
+	InterviewID string `json:"interviewId" protobuf:"1"`
+}
+
+// Статусы профиля в GetProfileResponse
+const (
+	ProfilePending = "pending"
+	ProfileReady   = "ready"
+	ProfileFailed  = "failed"
+)
+
+type GetProfileResponse struct {
+	Status      string `json:"status" protobuf:"1"`
+	ProfileJSON string `json:"profileJson,omitempty" protobuf:"2"`
+	Error       string `json:"error,omitempty" protobuf:"3"`
+}
+
+func toQuestion(prompt *engine.Prompt) *Question {
+	if prompt == nil {
+		return nil
+	}
+	return &Question{
+		Kind:       string(prompt.Kind),
+		Text:       prompt.Text,
+		Block:      prompt.Block,
+		BlockTitle: prompt.BlockTitle,
+		Number:     prompt.Number,
+		Variant:    prompt.Variant,
+		Options:    prompt.Options,
+		AllowOther: prompt.AllowOther,
+		AnswerType: prompt.AnswerType,
+	}
+}
+
+func toEvents(events []engine.Event) []Event {
+	converted := make([]Event, 0, len(events))
+	for _, event := range events {
+		converted = append(converted, Event{Kind: string(event.Kind), Block: event.Block, BlockTitle: event.BlockTitle})
+	}
+	return converted
+}
+
+func toPhase(phase engine.Phase) Phase {
+	switch phase {
+	case engine.PhaseBlockPending:
+		return PhaseBlockPending
+	case engine.PhaseCompleted:
+		return PhaseCompleted
+	default:
+		return PhaseAnswering
+	}
+}
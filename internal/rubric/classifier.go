@@ -0,0 +1,201 @@
+package rubric
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"interview-bot-complete/internal/api"
+	"interview-bot-complete/internal/prompts"
+	"math"
+	"strings"
+)
+
+// Вердикты соответствия кандидата роли
+const (
+	VerdictStrongFit  = "strong_fit"
+	VerdictFit        = "fit"
+	VerdictPartialFit = "partial_fit"
+	VerdictNoFit      = "no_fit"
+)
+
+// Шкала оценки компетенции моделью
+const (
+	minScore = 1
+	maxScore = 5
+)
+
+// ErrIncompleteScores - модель оценила не все компетенции рубрики
+var ErrIncompleteScores = errors.New("модель оценила не все компетенции рубрики")
+
+// Completer - клиент модели, выполняющий запрос с JSON ответом
+type Completer interface {
+	ExtractProfileWithOptions(prompt string, opts api.CompletionOptions) (*api.Completion, error)
+}
+
+// Classifier оценивает кандидата по рубрике роли
+type Classifier struct {
+	client  Completer
+	rubrics *Rubrics
+}
+
+// Input - данные интервью для оценки кандидата
+type Input struct {
+	ProfileJSON string
+	Answers     string
+	Language    string
+}
+
+// Report - отчет о соответствии кандидата роли для рекрутера.
+// Итоговый балл и вердикт считаются по весам рубрики, а не моделью.
+type Report struct {
+	InterviewID string             `json:"interview_id"`
+	Tenant      string             `json:"tenant,omitempty"`
+	RubricID    string             `json:"rubric_id"`
+	Role        string             `json:"role"`
+	Score       float64            `json:"score"` // 0-100
+	Verdict     string             `json:"verdict"`
+	Summary     string             `json:"summary"`
+	Concerns    []string           `json:"concerns,omitempty"`
+	Scores      []CompetencyScore  `json:"competencies"`
+	Model       string             `json:"model,omitempty"`
+	Usage       api.Usage          `json:"usage"`
+	CreatedAt   string             `json:"created_at"`
+	Thresholds  map[string]float64 `json:"thresholds"`
+}
+
+// CompetencyScore - оценка компетенции (1-5) с обоснованием из ответов
+type CompetencyScore struct {
+	ID       string  `json:"id"`
+	Name     string  `json:"name"`
+	Weight   float64 `json:"weight"`
+	Score    int     `json:"score"`
+	Evidence string  `json:"evidence"`
+}
+
+// classifyResponse - ответ модели на промпт оценки по рубрике
+type classifyResponse struct {
+	Competencies []struct {
+		ID       string `json:"id"`
+		Score    int    `json:"score"`
+		Evidence string `json:"evidence"`
+	} `json:"competencies"`
+	Summary  string   `json:"summary"`
+	Concerns []string `json:"concerns"`
+}
+
+// NewClassifier создает оценку кандидатов по загруженным рубрикам
+func NewClassifier(client Completer, rubrics *Rubrics) *Classifier {
+	return &Classifier{client: client, rubrics: rubrics}
+}
+
+// Rubrics возвращает рубрики ролей
+func (c *Classifier) Rubrics() *Rubrics {
+	return c.rubrics
+}
+
+// Classify оценивает кандидата по рубрике rubricID (пустой - рубрика по умолчанию) одним запросом к модели
+func (c *Classifier) Classify(rubricID string, input Input, opts api.CompletionOptions) (*Report, *api.Completion, error) {
+	rubric, ok := c.rubrics.Get(rubricID)
+	if !ok {
+		return nil, nil, fmt.Errorf("неизвестная рубрика %q", rubricID)
+	}
+
+	prompt := prompts.GenerateRubricScorePrompt(rubric.Role, describeCompetencies(rubric), input.ProfileJSON, input.Answers, input.Language)
+	completion, err := c.client.ExtractProfileWithOptions(prompt, opts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("ошибка оценки по рубрике: %w", err)
+	}
+
+	var response classifyResponse
+	if err := json.Unmarshal([]byte(completion.Content), &response); err != nil {
+		return nil, nil, fmt.Errorf("ошибка парсинга оценки по рубрике: %w", err)
+	}
+
+	scored := make(map[string]CompetencyScore, len(response.Competencies))
+	for _, item := range response.Competencies {
+		competency, ok := rubric.Find(strings.ToLower(strings.TrimSpace(item.ID)))
+		if !ok {
+			continue
+		}
+		scored[competency.ID] = CompetencyScore{
+			ID:       competency.ID,
+			Name:     competency.Name,
+			Weight:   competency.Weight,
+			Score:    min(max(item.Score, minScore), maxScore),
+			Evidence: item.Evidence,
+		}
+	}
+
+	report := &Report{
+		RubricID: rubric.ID,
+		Role:     rubric.Role,
+		Summary:  response.Summary,
+		Concerns: response.Concerns,
+		Model:    completion.Model,
+		Usage:    completion.Usage,
+		Thresholds: map[string]float64{
+			VerdictStrongFit:  rubric.Thresholds.StrongFit,
+			VerdictFit:        rubric.Thresholds.Fit,
+			VerdictPartialFit: rubric.Thresholds.PartialFit,
+		},
+	}
+	var missing []string
+	for _, competency := range rubric.Competencies {
+		score, ok := scored[competency.ID]
+		if !ok {
+			missing = append(missing, competency.ID)
+			continue
+		}
+		report.Scores = append(report.Scores, score)
+	}
+	if len(missing) > 0 {
+		return nil, nil, fmt.Errorf("%w: %s (рубрика %s)", ErrIncompleteScores, strings.Join(missing, ", "), rubric.ID)
+	}
+
+	report.Score = weightedScore(report.Scores)
+	report.Verdict = rubric.Verdict(report.Score)
+	return report, completion, nil
+}
+
+// Verdict возвращает вердикт для итогового балла по порогам рубрики
+func (r *Rubric) Verdict(score float64) string {
+	switch {
+	case score >= r.Thresholds.StrongFit:
+		return VerdictStrongFit
+	case score >= r.Thresholds.Fit:
+		return VerdictFit
+	case score >= r.Thresholds.PartialFit:
+		return VerdictPartialFit
+	default:
+		return VerdictNoFit
+	}
+}
+
+// weightedScore переводит оценки 1-5 в шкалу 0-100 и усредняет их по весам компетенций
+func weightedScore(scores []CompetencyScore) float64 {
+	var total, weights float64
+	for _, score := range scores {
+		total += score.Weight * float64(score.Score-minScore) / float64(maxScore-minScore) * 100
+		weights += score.Weight
+	}
+	if weights == 0 {
+		return 0
+	}
+	return math.Round(total/weights*10) / 10
+}
+
+// describeCompetencies перечисляет компетенции рубрики для промпта: [id] название - описание; признаки
+func describeCompetencies(rubric *Rubric) string {
+	var text strings.Builder
+	for _, competency := range rubric.Competencies {
+		text.WriteString(fmt.Sprintf("[%s] %s", competency.ID, competency.Name))
+		if competency.Description != "" {
+			text.WriteString(" - " + competency.Description)
+		}
+		if len(competency.Indicators) > 0 {
+			text.WriteString("; " + strings.Join(competency.Indicators, ", "))
+		}
+		text.WriteString("\n")
+	}
+	return strings.TrimRight(text.String(), "\n")
+}
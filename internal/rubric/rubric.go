@@ -0,0 +1,174 @@
+package rubric
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// idPattern - допустимые ID рубрик и компетенций
+var idPattern = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// Пороги вердикта по умолчанию (итоговый балл 0-100)
+const (
+	defaultStrongFit  = 80
+	defaultFit        = 65
+	defaultPartialFit = 45
+)
+
+// Rubric - рубрика роли: компетенции с весами, по которым кандидат оценивается для рекрутера.
+// ID рубрики - имя файла без расширения
+type Rubric struct {
+	ID           string       `yaml:"-"`
+	Role         string       `yaml:"role"`
+	Description  string       `yaml:"description,omitempty"`
+	Competencies []Competency `yaml:"competencies"`
+	Thresholds   Thresholds   `yaml:"thresholds,omitempty"`
+}
+
+// Competency - компетенция рубрики; Weight - относительный вес в итоговом балле
+type Competency struct {
+	ID          string  `yaml:"id"`
+	Name        string  `yaml:"name"`
+	Description string  `yaml:"description,omitempty"`
+	Weight      float64 `yaml:"weight"`
+	// Indicators - признаки в ответах, подтверждающие компетенцию
+	Indicators []string `yaml:"indicators,omitempty"`
+}
+
+// Thresholds - минимальные итоговые баллы вердиктов; незаданные берутся по умолчанию
+type Thresholds struct {
+	StrongFit  float64 `yaml:"strong_fit,omitempty"`
+	Fit        float64 `yaml:"fit,omitempty"`
+	PartialFit float64 `yaml:"partial_fit,omitempty"`
+}
+
+// Find возвращает компетенцию рубрики по ID
+func (r *Rubric) Find(id string) (*Competency, bool) {
+	for i := range r.Competencies {
+		if r.Competencies[i].ID == id {
+			return &r.Competencies[i], true
+		}
+	}
+	return nil, false
+}
+
+// Rubrics - загруженные рубрики ролей
+type Rubrics struct {
+	rubrics   map[string]*Rubric
+	defaultID string
+}
+
+// LoadRubric загружает и проверяет рубрику из YAML файла
+func LoadRubric(filename string) (*Rubric, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения файла %s: %w", filename, err)
+	}
+
+	var rubric Rubric
+	if err := yaml.Unmarshal(data, &rubric); err != nil {
+		return nil, fmt.Errorf("ошибка парсинга YAML: %w", err)
+	}
+	rubric.ID = strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+
+	if err := validateRubric(&rubric); err != nil {
+		return nil, fmt.Errorf("рубрика %s: %w", rubric.ID, err)
+	}
+	return &rubric, nil
+}
+
+// validateRubric проверяет ID, веса и пороги рубрики и заполняет пороги по умолчанию
+func validateRubric(rubric *Rubric) error {
+	if !idPattern.MatchString(rubric.ID) {
+		return fmt.Errorf("недопустимый ID рубрики %q: разрешены строчные латинские буквы, цифры и _", rubric.ID)
+	}
+	if rubric.Role == "" {
+		return fmt.Errorf("рубрика должна иметь role")
+	}
+	if len(rubric.Competencies) == 0 {
+		return fmt.Errorf("competencies не должны быть пустыми")
+	}
+
+	seen := make(map[string]bool, len(rubric.Competencies))
+	for i, competency := range rubric.Competencies {
+		if !idPattern.MatchString(competency.ID) {
+			return fmt.Errorf("компетенция %d: недопустимый ID %q", i+1, competency.ID)
+		}
+		if seen[competency.ID] {
+			return fmt.Errorf("компетенция %q объявлена повторно", competency.ID)
+		}
+		seen[competency.ID] = true
+		if competency.Name == "" {
+			return fmt.Errorf("компетенция %q должна иметь name", competency.ID)
+		}
+		if competency.Weight <= 0 {
+			return fmt.Errorf("компетенция %q: weight должен быть больше 0", competency.ID)
+		}
+	}
+
+	thresholds := &rubric.Thresholds
+	if thresholds.StrongFit == 0 {
+		thresholds.StrongFit = defaultStrongFit
+	}
+	if thresholds.Fit == 0 {
+		thresholds.Fit = defaultFit
+	}
+	if thresholds.PartialFit == 0 {
+		thresholds.PartialFit = defaultPartialFit
+	}
+	if !(thresholds.PartialFit < thresholds.Fit && thresholds.Fit < thresholds.StrongFit && thresholds.StrongFit <= 100) {
+		return fmt.Errorf("пороги должны возрастать: partial_fit < fit < strong_fit <= 100")
+	}
+	return nil
+}
+
+// LoadRubrics загружает все рубрики *.yaml из директории. defaultID - рубрика,
+// используемая, когда шаблон интервью не задает свою (пустой - только рубрики шаблонов)
+func LoadRubrics(dir, defaultID string) (*Rubrics, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка поиска рубрик в %s: %w", dir, err)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("в %s нет рубрик ролей", dir)
+	}
+
+	rubrics := &Rubrics{rubrics: make(map[string]*Rubric, len(files)), defaultID: defaultID}
+	for _, file := range files {
+		rubric, err := LoadRubric(file)
+		if err != nil {
+			return nil, err
+		}
+		rubrics.rubrics[rubric.ID] = rubric
+	}
+
+	if _, ok := rubrics.rubrics[defaultID]; defaultID != "" && !ok {
+		return nil, fmt.Errorf("рубрика по умолчанию %q не найдена в %s", defaultID, dir)
+	}
+	return rubrics, nil
+}
+
+// Get возвращает рубрику по ID; пустой ID означает рубрику по умолчанию
+func (r *Rubrics) Get(id string) (*Rubric, bool) {
+	if id == "" {
+		id = r.defaultID
+	}
+	rubric, ok := r.rubrics[id]
+	return rubric, ok
+}
+
+// IDs возвращает отсортированный список ID рубрик
+func (r *Rubrics) IDs() []string {
+	ids := make([]string, 0, len(r.rubrics))
+	for id := range r.rubrics {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,72 @@
+package schema
+
+import (
+	"sort"
+	"strings"
+)
+
+// JSONSchemaDraft - версия спецификации JSON Schema публикуемой схемы профиля
+const JSONSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// jsonSchemaTypes - типы JSON Schema для типов полей схемы профиля
+var jsonSchemaTypes = map[string]string{
+	"string": "string",
+	"int":    "integer",
+	"float":  "number",
+	"bool":   "boolean",
+	"array":  "array",
+	"object": "object",
+}
+
+// JSONSchema строит документ JSON Schema профиля по полям схемы. Поля с точечной нотацией
+// (location.city) становятся свойствами вложенного объекта. Любое поле может быть null - модель
+// ставит null, если сведений нет; служебные поля профиля (_metadata, _provenance) допускаются.
+func JSONSchema(fields map[string]SchemaField) map[string]interface{} {
+	properties := make(map[string]interface{})
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	// Родительское поле (location) идет раньше вложенных (location.city) и не затирает их
+	sort.Strings(names)
+
+	for _, name := range names {
+		field := fields[name]
+		root, child, nested := strings.Cut(name, ".")
+		if !nested {
+			properties[name] = fieldSchema(field.Type)
+			continue
+		}
+
+		parent, ok := properties[root].(map[string]interface{})
+		if !ok || parent["properties"] == nil {
+			parent = map[string]interface{}{
+				"type":       nullable("object"),
+				"properties": make(map[string]interface{}),
+			}
+			properties[root] = parent
+		}
+		parent["properties"].(map[string]interface{})[child] = fieldSchema(field.Type)
+	}
+
+	return map[string]interface{}{
+		"$schema":              JSONSchemaDraft,
+		"title":                "Interview profile",
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": true,
+	}
+}
+
+// fieldSchema возвращает схему поля; неизвестный тип не ограничивается
+func fieldSchema(fieldType string) map[string]interface{} {
+	jsonType, ok := jsonSchemaTypes[fieldType]
+	if !ok {
+		return map[string]interface{}{}
+	}
+	return map[string]interface{}{"type": nullable(jsonType)}
+}
+
+func nullable(jsonType string) []interface{} {
+	return []interface{}{jsonType, "null"}
+}
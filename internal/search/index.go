@@ -0,0 +1,411 @@
+package search
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"interview-bot-complete/internal/storage"
+	"log/slog"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Источники фрагментов: ответ интервью или поле профиля
+const (
+	SourceAnswer  = "answer"
+	SourceProfile = "profile"
+)
+
+// maxSnippets - сколько фрагментов показывается для одного интервью
+const maxSnippets = 3
+
+// ErrEmptyQuery - в запросе нет слов для поиска
+var ErrEmptyQuery = errors.New("в запросе нет слов для поиска")
+
+// ProfileSource возвращает JSON профиля по ID интервью
+type ProfileSource func(interviewID string) (string, error)
+
+// Options - параметры поиска
+type Options struct {
+	// Limit - сколько интервью вернуть; 0 - все найденные
+	Limit int
+	// Tenant - искать только в интервью арендатора (пусто - во всех интервью)
+	Tenant string
+}
+
+// Span - совпадение во фрагменте: позиции в символах (рунах), End не включается
+type Span struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// Snippet - фрагмент ответа или поля профиля с совпадениями
+type Snippet struct {
+	Source string `json:"source"`
+	// Label - вопрос для ответа или имя поля профиля
+	Label      string `json:"label"`
+	Text       string `json:"text"`
+	Highlights []Span `json:"highlights"`
+}
+
+// Hit - найденное интервью
+type Hit struct {
+	InterviewID string    `json:"interview_id"`
+	Score       float64   `json:"score"`
+	Timestamp   string    `json:"timestamp,omitempty"`
+	Tenant      string    `json:"tenant,omitempty"`
+	Snippets    []Snippet `json:"snippets"`
+}
+
+// Results - результат поиска: Total интервью найдено, Hits - первые Limit из них
+type Results struct {
+	Query string `json:"query"`
+	Total int    `json:"total"`
+	Hits  []Hit  `json:"hits"`
+}
+
+// BuildReport - итог построения индекса
+type BuildReport struct {
+	Interviews int
+	Profiles   int
+	Skipped    int // результаты, которые не удалось прочитать
+	Took       time.Duration
+}
+
+// field - проиндексированный текст интервью: ответ или поле профиля
+type field struct {
+	source, label, text string
+}
+
+// document - проиндексированное интервью
+type document struct {
+	interviewID string
+	timestamp   string
+	tenant      string
+	fields      []field
+	// terms - сколько раз слово встречается в интервью
+	terms map[string]int
+}
+
+// Index - полнотекстовый индекс архива: ответы интервью и последние ревизии профилей.
+// Индекс хранится в памяти и перестраивается из хранилища (Build, Start); новые профили
+// добавляются сразу (Add).
+type Index struct {
+	profiles ProfileSource
+	logger   *slog.Logger
+
+	mutex    sync.RWMutex
+	docs     map[string]*document
+	postings map[string]map[string]int // слово -> интервью -> число вхождений
+	builtAt  time.Time
+}
+
+// New создает пустой индекс; profiles - источник профилей (nil - индексируются только ответы)
+func New(profiles ProfileSource) *Index {
+	return &Index{
+		profiles: profiles,
+		logger:   slog.Default(),
+		docs:     make(map[string]*document),
+		postings: make(map[string]map[string]int),
+	}
+}
+
+// Build заново индексирует все сохраненные интервью и заменяет ими индекс
+func (ix *Index) Build() (*BuildReport, error) {
+	started := time.Now()
+	ids, err := storage.ListResults()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &BuildReport{}
+	docs := make(map[string]*document, len(ids))
+	postings := make(map[string]map[string]int)
+	for _, id := range ids {
+		result, err := storage.LoadResult(id)
+		if err != nil {
+			report.Skipped++
+			continue
+		}
+		doc := ix.newDocument(result)
+		docs[id] = doc
+		addPostings(postings, doc)
+		report.Interviews++
+		if hasProfile(doc) {
+			report.Profiles++
+		}
+	}
+
+	ix.mutex.Lock()
+	ix.docs = docs
+	ix.postings = postings
+	ix.builtAt = time.Now()
+	ix.mutex.Unlock()
+
+	report.Took = time.Since(started)
+	return report, nil
+}
+
+// Start строит индекс и перестраивает его каждые interval, чтобы в поиск попадали профили,
+// сохраненные другими процессами (backfill), и не попадали ответы, удаленные политикой хранения
+func (ix *Index) Start(interval time.Duration) {
+	go func() {
+		for {
+			report, err := ix.Build()
+			if err != nil {
+				ix.logger.Error("Ошибка построения поискового индекса", "error", err)
+			} else {
+				ix.logger.Info("Поисковый индекс построен", "interviews", report.Interviews,
+					"profiles", report.Profiles, "skipped", report.Skipped, "took", report.Took.Round(time.Millisecond))
+			}
+			if interval <= 0 {
+				return
+			}
+			time.Sleep(interval)
+		}
+	}()
+}
+
+// Add индексирует (или переиндексирует) интервью с его последним профилем
+func (ix *Index) Add(interviewID string) error {
+	result, err := storage.LoadResult(interviewID)
+	if err != nil {
+		return fmt.Errorf("ошибка загрузки интервью %s: %w", interviewID, err)
+	}
+	doc := ix.newDocument(result)
+
+	ix.mutex.Lock()
+	defer ix.mutex.Unlock()
+	if old, ok := ix.docs[interviewID]; ok {
+		removePostings(ix.postings, old)
+	}
+	ix.docs[interviewID] = doc
+	addPostings(ix.postings, doc)
+	return nil
+}
+
+// Count возвращает число проиндексированных интервью
+func (ix *Index) Count() int {
+	ix.mutex.RLock()
+	defer ix.mutex.RUnlock()
+	return len(ix.docs)
+}
+
+// BuiltAt возвращает время последнего построения индекса (нулевое - индекс еще не построен)
+func (ix *Index) BuiltAt() time.Time {
+	ix.mutex.RLock()
+	defer ix.mutex.RUnlock()
+	return ix.builtAt
+}
+
+// Search ищет интервью, в ответах или профиле которых встречаются все слова запроса.
+// Интервью упорядочены по релевантности (TF-IDF), у каждого - фрагменты с совпадениями.
+func (ix *Index) Search(query string, opts Options) (*Results, error) {
+	terms := parseQuery(query)
+	if len(terms) == 0 {
+		return nil, ErrEmptyQuery
+	}
+
+	ix.mutex.RLock()
+	defer ix.mutex.RUnlock()
+
+	var scores map[string]float64
+	for _, term := range terms {
+		termScores := ix.termScores(term)
+		if scores == nil {
+			scores = termScores
+			continue
+		}
+		// Интервью должно содержать все слова запроса
+		for id, score := range scores {
+			if termScore, ok := termScores[id]; ok {
+				scores[id] = score + termScore
+			} else {
+				delete(scores, id)
+			}
+		}
+	}
+
+	hits := make([]Hit, 0, len(scores))
+	for id, score := range scores {
+		doc := ix.docs[id]
+		if opts.Tenant != "" && doc.tenant != opts.Tenant {
+			continue
+		}
+		hits = append(hits, Hit{InterviewID: id, Score: math.Round(score*1000) / 1000, Timestamp: doc.timestamp, Tenant: doc.tenant})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].Timestamp > hits[j].Timestamp
+	})
+
+	results := &Results{Query: query, Total: len(hits)}
+	if opts.Limit > 0 && len(hits) > opts.Limit {
+		hits = hits[:opts.Limit]
+	}
+	for i := range hits {
+		hits[i].Snippets = snippets(ix.docs[hits[i].InterviewID], terms)
+	}
+	results.Hits = hits
+	return results, nil
+}
+
+// termScores возвращает вклад слова запроса в релевантность каждого интервью, где оно встречается
+func (ix *Index) termScores(term queryTerm) map[string]float64 {
+	scores := make(map[string]float64)
+	add := func(word string) {
+		docs := ix.postings[word]
+		idf := math.Log(1 + float64(len(ix.docs))/float64(len(docs)))
+		for id, count := range docs {
+			scores[id] += float64(count) * idf
+		}
+	}
+	if !term.prefix {
+		if _, ok := ix.postings[term.text]; ok {
+			add(term.text)
+		}
+		return scores
+	}
+	for word := range ix.postings {
+		if term.matches(word) {
+			add(word)
+		}
+	}
+	return scores
+}
+
+// snippets возвращает фрагменты интервью с совпадениями: сначала ответы, затем поля профиля
+func snippets(doc *document, terms []queryTerm) []Snippet {
+	var result []Snippet
+	for _, f := range doc.fields {
+		text, spans, ok := makeSnippet(f.text, terms)
+		if !ok {
+			continue
+		}
+		result = append(result, Snippet{Source: f.source, Label: f.label, Text: text, Highlights: spans})
+		if len(result) == maxSnippets {
+			break
+		}
+	}
+	return result
+}
+
+// newDocument собирает тексты интервью: ответы (без пропущенных вопросов) и поля последнего профиля
+func (ix *Index) newDocument(result *storage.InterviewResult) *document {
+	doc := &document{
+		interviewID: result.InterviewID,
+		timestamp:   result.Timestamp,
+		tenant:      result.Tenant,
+		terms:       make(map[string]int),
+	}
+	for _, block := range result.Blocks {
+		for _, qa := range block.QuestionsAndAnswers {
+			if qa.Skipped || strings.TrimSpace(qa.Answer) == "" {
+				continue
+			}
+			doc.fields = append(doc.fields, field{source: SourceAnswer, label: qa.Question, text: qa.Answer})
+		}
+	}
+	if ix.profiles != nil {
+		if profileJSON, err := ix.profiles(result.InterviewID); err == nil {
+			doc.fields = append(doc.fields, profileFields(profileJSON)...)
+		}
+	}
+
+	for _, f := range doc.fields {
+		for _, t := range tokenize(f.text) {
+			doc.terms[t.term]++
+		}
+	}
+	return doc
+}
+
+// profileFields возвращает заполненные поля профиля текстом; служебные поля (_metadata) пропускаются
+func profileFields(profileJSON string) []field {
+	var profile map[string]interface{}
+	if err := json.Unmarshal([]byte(profileJSON), &profile); err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(profile))
+	for name := range profile {
+		if !strings.HasPrefix(name, "_") {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var fields []field
+	for _, name := range names {
+		if text := valueText(profile[name]); text != "" {
+			fields = append(fields, field{source: SourceProfile, label: name, text: text})
+		}
+	}
+	return fields
+}
+
+// valueText возвращает значение поля профиля строкой: элементы массива через «; »
+func valueText(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(v)
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			if text := valueText(item); text != "" {
+				items = append(items, text)
+			}
+		}
+		return strings.Join(items, "; ")
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		parts := make([]string, 0, len(keys))
+		for _, key := range keys {
+			if text := valueText(v[key]); text != "" {
+				parts = append(parts, key+": "+text)
+			}
+		}
+		return strings.Join(parts, "; ")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func hasProfile(doc *document) bool {
+	for _, f := range doc.fields {
+		if f.source == SourceProfile {
+			return true
+		}
+	}
+	return false
+}
+
+func addPostings(postings map[string]map[string]int, doc *document) {
+	for term, count := range doc.terms {
+		docs, ok := postings[term]
+		if !ok {
+			docs = make(map[string]int)
+			postings[term] = docs
+		}
+		docs[doc.interviewID] = count
+	}
+}
+
+func removePostings(postings map[string]map[string]int, doc *document) {
+	for term := range doc.terms {
+		delete(postings[term], doc.interviewID)
+		if len(postings[term]) == 0 {
+			delete(postings, term)
+		}
+	}
+}
@@ -0,0 +1,216 @@
+package search
+
+import (
+	"errors"
+	"fmt"
+	"interview-bot-complete/internal/storage"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testResult - интервью с ответами answers на вопросы «Вопрос N»
+func testResult(id, timestamp string, answers ...string) *storage.InterviewResult {
+	block := storage.BlockResult{BlockID: 1}
+	for i, answer := range answers {
+		block.QuestionsAndAnswers = append(block.QuestionsAndAnswers,
+			storage.QA{Question: fmt.Sprintf("Вопрос %d", i+1), Answer: answer})
+	}
+	return &storage.InterviewResult{InterviewID: id, Timestamp: timestamp, Blocks: []storage.BlockResult{block}}
+}
+
+// testIndex индексирует интервью без хранилища, как Add
+func testIndex(profiles ProfileSource, results ...*storage.InterviewResult) *Index {
+	ix := New(profiles)
+	for _, result := range results {
+		doc := ix.newDocument(result)
+		ix.docs[result.InterviewID] = doc
+		addPostings(ix.postings, doc)
+	}
+	return ix
+}
+
+// hitIDs возвращает ID найденных интервью по порядку
+func hitIDs(results *Results) []string {
+	ids := make([]string, 0, len(results.Hits))
+	for _, hit := range results.Hits {
+		ids = append(ids, hit.InterviewID)
+	}
+	return ids
+}
+
+// TestTokenize проверяет разбиение на слова, нормализацию и позиции в рунах
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []token
+	}{
+		{name: "пустой текст", text: "", want: nil},
+		{
+			name: "регистр и ё",
+			text: "Ёлка ЗЕЛЁНАЯ",
+			want: []token{{term: "елка", start: 0, end: 4}, {term: "зеленая", start: 5, end: 12}},
+		},
+		{
+			name: "пунктуация и короткие слова",
+			text: "Go, и SQL: 5 лет (C++)",
+			want: []token{{term: "go", start: 0, end: 2}, {term: "sql", start: 6, end: 9}, {term: "лет", start: 13, end: 16}},
+		},
+		{
+			name: "цифры внутри слова",
+			text: "k8s — 2024",
+			want: []token{{term: "k8s", start: 0, end: 3}, {term: "2024", start: 6, end: 10}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tokenize(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("слова %+v, ожидались %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestParseQuery проверяет слова запроса: нормализацию, звездочку и повторы
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []queryTerm
+	}{
+		{query: "", want: nil},
+		{query: "  и а * ", want: nil},
+		{query: "Ёж", want: []queryTerm{{text: "еж"}}},
+		{query: "разработ* go", want: []queryTerm{{text: "разработ", prefix: true}, {text: "go"}}},
+		{query: "go GO go*", want: []queryTerm{{text: "go"}, {text: "go", prefix: true}}},
+		{query: "back-end", want: []queryTerm{{text: "back"}, {text: "end"}}},
+	}
+	for _, tt := range tests {
+		if got := parseQuery(tt.query); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("запрос %q: слова %+v, ожидались %+v", tt.query, got, tt.want)
+		}
+	}
+
+	prefix := queryTerm{text: "разработ", prefix: true}
+	if !prefix.matches("разработчик") || !prefix.matches("разработ") || prefix.matches("разрабатывал") {
+		t.Error("слово со звездочкой должно совпадать только с продолжениями")
+	}
+	if exact := (queryTerm{text: "go"}); exact.matches("golang") {
+		t.Error("слово без звездочки не должно совпадать с продолжениями")
+	}
+}
+
+// TestMakeSnippet проверяет обрезку фрагмента по границам слов и позиции совпадений
+func TestMakeSnippet(t *testing.T) {
+	terms := parseQuery("python")
+
+	text, spans, ok := makeSnippet("Пишу на Python и Go", terms)
+	if !ok || text != "Пишу на Python и Go" || !reflect.DeepEqual(spans, []Span{{Start: 8, End: 14}}) {
+		t.Errorf("короткий текст: %q %+v %v", text, spans, ok)
+	}
+
+	long := strings.Repeat("слово ", 30) + "Python " + strings.Repeat("еще ", 40)
+	text, spans, ok = makeSnippet(long, terms)
+	if !ok || !strings.HasPrefix(text, ellipsis+"слово") || !strings.HasSuffix(text, "еще"+ellipsis) {
+		t.Fatalf("длинный текст: фрагмент %q должен быть обрезан по словам с многоточиями", text)
+	}
+	if len(spans) != 1 || string([]rune(text)[spans[0].Start:spans[0].End]) != "Python" {
+		t.Errorf("длинный текст: совпадения %+v не указывают на слово запроса во фрагменте %q", spans, text)
+	}
+
+	if _, _, ok := makeSnippet("Пишу на Go", terms); ok {
+		t.Error("в тексте без слов запроса фрагмента быть не должно")
+	}
+}
+
+// TestSearchRanking проверяет, что найдены интервью со всеми словами запроса, а выше -
+// интервью с более частыми и более редкими в архиве словами
+func TestSearchRanking(t *testing.T) {
+	ix := testIndex(nil,
+		testResult("once", "2024-01-01", "Пишу на Go", "Делал бэкенд"),
+		testResult("twice", "2024-01-02", "Go и еще раз Go", "Делал бэкенд"),
+		testResult("no-backend", "2024-01-03", "Go, Go, Go"),
+		testResult("empty", "2024-01-04"),
+	)
+	results, err := ix.Search("go бэкенд", Options{})
+	if err != nil {
+		t.Fatalf("ошибка поиска: %v", err)
+	}
+	if want := []string{"twice", "once"}; !reflect.DeepEqual(hitIDs(results), want) {
+		t.Errorf("частота: порядок %v, ожидался %v", hitIDs(results), want)
+	}
+
+	// При равной релевантности выше более новое интервью
+	results, err = ix.Search("делал", Options{Limit: 1})
+	if err != nil {
+		t.Fatalf("ошибка поиска: %v", err)
+	}
+	if results.Total != 2 || !reflect.DeepEqual(hitIDs(results), []string{"twice"}) {
+		t.Errorf("лимит: всего %d, найдены %v; ожидалось 2 и [twice]", results.Total, hitIDs(results))
+	}
+
+	// PostgreSQL встречается в одном интервью из четырех, Postman - в трех
+	ix = testIndex(nil,
+		testResult("rare", "2024-01-01", "PostgreSQL"),
+		testResult("common1", "2024-01-02", "Postman"),
+		testResult("common2", "2024-01-03", "Postman"),
+		testResult("common3", "2024-01-04", "Postman"),
+	)
+	results, err = ix.Search("post*", Options{})
+	if err != nil {
+		t.Fatalf("ошибка поиска: %v", err)
+	}
+	if got := hitIDs(results); len(got) != 4 || got[0] != "rare" {
+		t.Errorf("редкость: порядок %v, первым ожидалось rare", got)
+	}
+
+	if _, err := ix.Search(" * ", Options{}); !errors.Is(err, ErrEmptyQuery) {
+		t.Errorf("пустой запрос: ошибка %v, ожидалась ErrEmptyQuery", err)
+	}
+}
+
+// TestSearchPrefixAndProfile проверяет поиск по началу слова, поля профиля и фильтр арендатора
+func TestSearchPrefixAndProfile(t *testing.T) {
+	profiles := func(interviewID string) (string, error) {
+		if interviewID != "profiled" {
+			return "", errors.New("профиля нет")
+		}
+		return `{"skills": ["Разработка на Go", "PostgreSQL"], "_metadata": {"model": "разработчик"}}`, nil
+	}
+	tenant := testResult("tenant", "2024-01-02", "Я разработчица")
+	tenant.Tenant = "acme"
+	ix := testIndex(profiles,
+		testResult("profiled", "2024-01-01", "Расскажу о себе"),
+		tenant,
+		testResult("other", "2024-01-03", "Работал тестировщиком"),
+	)
+
+	results, err := ix.Search("разработ*", Options{})
+	if err != nil {
+		t.Fatalf("ошибка поиска: %v", err)
+	}
+	if got := hitIDs(results); len(got) != 2 {
+		t.Fatalf("найдены %v, ожидались profiled и tenant", got)
+	}
+	for _, hit := range results.Hits {
+		if len(hit.Snippets) != 1 {
+			t.Errorf("%s: фрагменты %+v, ожидался один", hit.InterviewID, hit.Snippets)
+			continue
+		}
+		snippet := hit.Snippets[0]
+		if hit.InterviewID == "profiled" && (snippet.Source != SourceProfile || snippet.Label != "skills") {
+			t.Errorf("профиль: фрагмент %+v, ожидалось поле skills (служебные поля не индексируются)", snippet)
+		}
+		if hit.InterviewID == "tenant" && (snippet.Source != SourceAnswer || snippet.Label != "Вопрос 1") {
+			t.Errorf("ответ: фрагмент %+v, ожидался ответ на «Вопрос 1»", snippet)
+		}
+	}
+
+	results, err = ix.Search("разработ*", Options{Tenant: "acme"})
+	if err != nil {
+		t.Fatalf("ошибка поиска: %v", err)
+	}
+	if want := []string{"tenant"}; !reflect.DeepEqual(hitIDs(results), want) {
+		t.Errorf("арендатор: найдены %v, ожидались %v", hitIDs(results), want)
+	}
+}
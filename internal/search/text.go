@@ -0,0 +1,130 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	// minTokenLength - более короткие слова (предлоги, союзы) не индексируются
+	minTokenLength = 2
+	// snippetRadius - сколько символов контекста показывать вокруг первого совпадения
+	snippetRadius = 80
+	// ellipsis отмечает обрезанный текст фрагмента
+	ellipsis = "…"
+)
+
+// token - слово текста и его позиция в рунах
+type token struct {
+	term       string
+	start, end int
+}
+
+// tokenize разбивает текст на нормализованные слова с позициями
+func tokenize(text string) []token {
+	var tokens []token
+	runes := []rune(text)
+	start := -1
+	for i := 0; i <= len(runes); i++ {
+		if i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i])) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 && i-start >= minTokenLength {
+			tokens = append(tokens, token{term: normalizeTerm(string(runes[start:i])), start: start, end: i})
+		}
+		start = -1
+	}
+	return tokens
+}
+
+// normalizeTerm приводит слово к виду в индексе: нижний регистр, ё как е
+func normalizeTerm(word string) string {
+	return strings.ReplaceAll(strings.ToLower(word), "ё", "е")
+}
+
+// queryTerm - слово запроса; prefix - слово задано со звездочкой и совпадает с любым продолжением
+type queryTerm struct {
+	text   string
+	prefix bool
+}
+
+// matches сообщает, совпадает ли слово текста со словом запроса
+func (q queryTerm) matches(term string) bool {
+	if q.prefix {
+		return strings.HasPrefix(term, q.text)
+	}
+	return term == q.text
+}
+
+// parseQuery разбирает запрос: все слова должны встретиться в интервью, «слово*» ищет по началу слова
+func parseQuery(query string) []queryTerm {
+	var terms []queryTerm
+	seen := make(map[queryTerm]bool)
+	for _, word := range strings.Fields(query) {
+		prefix := strings.HasSuffix(word, "*")
+		for _, t := range tokenize(strings.TrimRight(word, "*")) {
+			term := queryTerm{text: t.term, prefix: prefix}
+			if !seen[term] {
+				seen[term] = true
+				terms = append(terms, term)
+			}
+		}
+	}
+	return terms
+}
+
+// makeSnippet вырезает из текста фрагмент вокруг первого совпадения и отмечает в нем все совпадения.
+// false - в тексте нет слов запроса.
+func makeSnippet(text string, terms []queryTerm) (string, []Span, bool) {
+	var matched []token
+	for _, t := range tokenize(text) {
+		for _, term := range terms {
+			if term.matches(t.term) {
+				matched = append(matched, t)
+				break
+			}
+		}
+	}
+	if len(matched) == 0 {
+		return "", nil, false
+	}
+
+	runes := []rune(text)
+	from := max(matched[0].start-snippetRadius, 0)
+	to := min(matched[0].end+snippetRadius, len(runes))
+	// Фрагмент не начинается и не заканчивается на середине слова и пробелами
+	for from > 0 && !unicode.IsSpace(runes[from-1]) {
+		from--
+	}
+	for to < len(runes) && !unicode.IsSpace(runes[to]) {
+		to++
+	}
+	for from < matched[0].start && unicode.IsSpace(runes[from]) {
+		from++
+	}
+	for to > matched[0].end && unicode.IsSpace(runes[to-1]) {
+		to--
+	}
+
+	var snippet strings.Builder
+	offset := -from
+	if strings.TrimSpace(string(runes[:from])) != "" {
+		snippet.WriteString(ellipsis)
+		offset += len([]rune(ellipsis))
+	}
+	snippet.WriteString(string(runes[from:to]))
+	if strings.TrimSpace(string(runes[to:])) != "" {
+		snippet.WriteString(ellipsis)
+	}
+
+	var spans []Span
+	for _, t := range matched {
+		if t.start >= from && t.end <= to {
+			spans = append(spans, Span{Start: t.start + offset, End: t.end + offset})
+		}
+	}
+	return snippet.String(), spans, true
+}
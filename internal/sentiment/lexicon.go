@@ -0,0 +1,64 @@
+package sentiment
+
+// Эмоции, которые распознает словарь
+const (
+	EmotionJoy      = "joy"
+	EmotionInterest = "interest"
+	EmotionPride    = "pride"
+	EmotionAnxiety  = "anxiety"
+	EmotionSadness  = "sadness"
+	EmotionAnger    = "anger"
+	EmotionFatigue  = "fatigue"
+)
+
+// emotion - эмоция словаря: знак тональности и начала слов (русские и английские)
+type emotion struct {
+	name     string
+	positive bool
+	stems    []string
+}
+
+// lexicon - словарь эмоциональной лексики. Слово относится к эмоции, если начинается с одной из основ
+var lexicon = []emotion{
+	{EmotionJoy, true, []string{
+		"нрав", "люблю", "любим", "обожа", "радост", "радуе", "радую", "счаст", "удовольств", "восторг",
+		"вдохнов", "кайф", "отличн", "прекрасн", "замечательн", "классн", "здорово",
+		"love", "enjoy", "happy", "happi", "glad", "delight", "inspir", "great", "wonderful", "awesome",
+	}},
+	{EmotionInterest, true, []string{
+		"интерес", "увлека", "увлеч", "любопыт", "захватыва", "мечта",
+		"interest", "curious", "fascinat", "passion", "excit", "dream",
+	}},
+	{EmotionPride, true, []string{
+		"горж", "горд", "достиж", "получилось", "справил", "успех", "успешн",
+		"proud", "achiev", "accomplish", "success",
+	}},
+	{EmotionAnxiety, false, []string{
+		"боюсь", "страх", "страш", "тревож", "тревог", "волну", "беспоко", "стресс", "пережива", "неуверен", "паник",
+		"afraid", "fear", "anxi", "worr", "stress", "nervous", "panic", "unsure",
+	}},
+	{EmotionSadness, false, []string{
+		"грус", "печал", "тоск", "жаль", "сожале", "разочаров", "одиноч", "потерял", "тяжело",
+		"sad", "regret", "disappoint", "lonel", "upset", "unhappy",
+	}},
+	{EmotionAnger, false, []string{
+		"злю", "злит", "злост", "бесит", "раздража", "ненавиж", "ненавист", "обид", "возмуща", "несправедлив",
+		"angry", "anger", "annoy", "hate", "frustrat", "irritat", "unfair",
+	}},
+	{EmotionFatigue, false, []string{
+		"устал", "устаю", "выгор", "утомл", "измотан", "надоел",
+		"tired", "exhaust", "burnout", "burned", "burnt", "bored",
+	}},
+}
+
+// negations меняют знак следующего эмоционального слова («не нравится»)
+var negations = map[string]bool{
+	"не": true, "нет": true, "ни": true, "никогда": true, "без": true,
+	"not": true, "no": true, "never": true, "don't": true, "dont": true, "didn't": true, "isn't": true, "without": true,
+}
+
+// intensifiers усиливают следующее эмоциональное слово («очень нравится»)
+var intensifiers = map[string]bool{
+	"очень": true, "сильно": true, "безумно": true, "невероятно": true, "крайне": true, "ужасно": true, "совсем": true, "особенно": true,
+	"very": true, "really": true, "extremely": true, "so": true, "incredibly": true, "truly": true, "absolutely": true,
+}
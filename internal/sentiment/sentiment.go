@@ -0,0 +1,215 @@
+package sentiment
+
+import (
+	"interview-bot-complete/internal/storage"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Метки тональности
+const (
+	LabelPositive = "positive"
+	LabelNegative = "negative"
+	LabelNeutral  = "neutral"
+	LabelMixed    = "mixed"
+)
+
+const (
+	// intensifierWeight - вес эмоционального слова после усилителя
+	intensifierWeight = 1.5
+	// exclamationWeight - вес восклицательного знака (не больше maxExclamations на ответ)
+	exclamationWeight = 0.5
+	maxExclamations   = 3
+	// saturationWeight - вес эмоциональной лексики, при котором интенсивность достигает 1
+	saturationWeight = 4.0
+	// mixedThreshold - при |score| ниже порога и обоих знаках тональность считается смешанной
+	mixedThreshold = 0.34
+)
+
+// counts - взвешенные попадания в словарь
+type counts struct {
+	positive, negative float64
+	exclamations       int
+	emotions           map[string]float64
+}
+
+func newCounts() counts {
+	return counts{emotions: make(map[string]float64)}
+}
+
+func (c *counts) add(other counts) {
+	c.positive += other.positive
+	c.negative += other.negative
+	c.exclamations += other.exclamations
+	for name, weight := range other.emotions {
+		c.emotions[name] += weight
+	}
+}
+
+// Analyze размечает ответы блока и блок в целом тональностью и эмоциями по словарю,
+// без обращения к модели; nil, если ответов нет
+func Analyze(dialogue []storage.QA) *storage.BlockSentiment {
+	result := &storage.BlockSentiment{}
+	total := newCounts()
+	for i, qa := range dialogue {
+		if strings.TrimSpace(qa.Answer) == "" {
+			continue
+		}
+		answer := count(qa.Answer)
+		total.add(answer)
+		result.Answers = append(result.Answers, storage.AnswerTone{Question: i + 1, Tone: answer.tone()})
+	}
+	if len(result.Answers) == 0 {
+		return nil
+	}
+
+	result.Tone = total.tone()
+	// Интенсивность блока - средняя по ответам, иначе длинный блок всегда «очень эмоционален»
+	var intensity float64
+	for _, answer := range result.Answers {
+		intensity += answer.Intensity
+	}
+	result.Intensity = round(intensity / float64(len(result.Answers)))
+	return result
+}
+
+// Aggregate объединяет тональности блоков: оценка взвешивается по интенсивности,
+// эмоции упорядочены по числу блоков, где они встретились
+func Aggregate(blocks []*storage.BlockSentiment) *storage.Tone {
+	var score, weight, intensity float64
+	var n int
+	positive, negative := false, false
+	emotionBlocks := make(map[string]float64)
+	for _, block := range blocks {
+		if block == nil {
+			continue
+		}
+		n++
+		w := block.Intensity + 0.1
+		score += block.Score * w
+		weight += w
+		intensity += block.Intensity
+		positive = positive || block.Label == LabelPositive || block.Label == LabelMixed
+		negative = negative || block.Label == LabelNegative || block.Label == LabelMixed
+		for _, name := range block.Emotions {
+			emotionBlocks[name]++
+		}
+	}
+	if n == 0 {
+		return nil
+	}
+
+	tone := &storage.Tone{
+		Score:     round(score / weight),
+		Intensity: round(intensity / float64(n)),
+		Emotions:  ranked(emotionBlocks),
+	}
+	tone.Label = label(tone.Score, positive, negative)
+	return tone
+}
+
+// count считает эмоциональную лексику текста с учетом отрицаний и усилителей
+func count(text string) counts {
+	c := newCounts()
+	c.exclamations = min(strings.Count(text, "!"), maxExclamations)
+
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	})
+	for i, word := range words {
+		e, ok := match(word)
+		if !ok {
+			continue
+		}
+		weight := 1.0
+		negated := false
+		for j := i - 1; j >= 0 && j >= i-2; j-- {
+			if negations[words[j]] {
+				negated = true
+			}
+			if intensifiers[words[j]] {
+				weight = intensifierWeight
+			}
+		}
+
+		switch {
+		case negated && e.positive:
+			// «не нравится» - негатив без конкретной эмоции
+			c.negative += weight
+		case negated:
+			// «не боюсь» - эмоция отрицается, тональность не меняется
+		case e.positive:
+			c.positive += weight
+			c.emotions[e.name] += weight
+		default:
+			c.negative += weight
+			c.emotions[e.name] += weight
+		}
+	}
+	return c
+}
+
+// match находит эмоцию словаря по началу слова
+func match(word string) (emotion, bool) {
+	for _, e := range lexicon {
+		for _, stem := range e.stems {
+			if strings.HasPrefix(word, stem) {
+				return e, true
+			}
+		}
+	}
+	return emotion{}, false
+}
+
+// tone переводит попадания в метку, оценку и интенсивность
+func (c counts) tone() storage.Tone {
+	hits := c.positive + c.negative
+	var score float64
+	if hits > 0 {
+		score = (c.positive - c.negative) / hits
+	}
+	weight := hits + float64(c.exclamations)*exclamationWeight
+	return storage.Tone{
+		Label:     label(score, c.positive > 0, c.negative > 0),
+		Score:     round(score),
+		Intensity: round(math.Min(1, weight/saturationWeight)),
+		Emotions:  ranked(c.emotions),
+	}
+}
+
+// label выбирает метку тональности по оценке и наличию позитива и негатива
+func label(score float64, positive, negative bool) string {
+	switch {
+	case !positive && !negative:
+		return LabelNeutral
+	case positive && negative && math.Abs(score) < mixedThreshold:
+		return LabelMixed
+	case score > 0:
+		return LabelPositive
+	case score < 0:
+		return LabelNegative
+	default:
+		return LabelNeutral
+	}
+}
+
+// ranked возвращает эмоции по убыванию веса
+func ranked(weights map[string]float64) []string {
+	names := make([]string, 0, len(weights))
+	for name := range weights {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if weights[names[i]] != weights[names[j]] {
+			return weights[names[i]] > weights[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,92 @@
+package server
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"interview-bot-complete/internal/embeddings"
+)
+
+// defaultSimilarLimit - число похожих профилей по умолчанию
+const defaultSimilarLimit = 5
+
+// maxSimilarLimit - максимальное число похожих профилей в ответе
+const maxSimilarLimit = 50
+
+// APIError - тело ответа с ошибкой REST API
+type APIError struct {
+	Error string `json:"error"`
+}
+
+// SimilarProfilesResponse - ответ эндпоинта /api/profiles/similar
+type SimilarProfilesResponse struct {
+	InterviewID string             `json:"interview_id"`
+	Matches     []embeddings.Match `json:"matches"`
+}
+
+// HandleAPI регистрирует эндпоинт REST API. Запрос должен содержать SERVER_API_TOKEN в заголовке
+// Authorization: Bearer <token> или параметре ?token=<token> (для браузера). API раскрывает данные
+// участников, поэтому без заданного токена эндпоинты отвечают 503.
+func (s *Server) HandleAPI(pattern string, handler http.HandlerFunc) {
+	s.mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		if s.config.APIToken == "" {
+			writeJSON(w, http.StatusServiceUnavailable, APIError{Error: "SERVER_API_TOKEN is not set"})
+			return
+		}
+		if !s.authorized(r) {
+			writeJSON(w, http.StatusUnauthorized, APIError{Error: "unauthorized"})
+			return
+		}
+		handler(w, r)
+	})
+}
+
+// EnableSimilarProfiles регистрирует GET /api/profiles/similar?interview_id=<id>&k=<n>.
+// Ищутся только уже проиндексированные профили: запрос не вызывает платных вычислений эмбеддингов.
+func (s *Server) EnableSimilarProfiles(service *embeddings.Service) {
+	s.HandleAPI("/api/profiles/similar", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSON(w, http.StatusMethodNotAllowed, APIError{Error: "method not allowed"})
+			return
+		}
+
+		interviewID := r.URL.Query().Get("interview_id")
+		if interviewID == "" {
+			writeJSON(w, http.StatusBadRequest, APIError{Error: "interview_id is required"})
+			return
+		}
+
+		k := defaultSimilarLimit
+		if raw := r.URL.Query().Get("k"); raw != "" {
+			parsed, err := strconv.Atoi(raw)
+			if err != nil || parsed <= 0 || parsed > maxSimilarLimit {
+				writeJSON(w, http.StatusBadRequest, APIError{Error: "k must be between 1 and 50"})
+				return
+			}
+			k = parsed
+		}
+
+		matches, err := service.FindSimilar(r.Context(), interviewID, k)
+		if errors.Is(err, embeddings.ErrNotIndexed) {
+			writeJSON(w, http.StatusNotFound, APIError{Error: err.Error()})
+			return
+		}
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, APIError{Error: err.Error()})
+			return
+		}
+
+		writeJSON(w, http.StatusOK, SimilarProfilesResponse{InterviewID: interviewID, Matches: matches})
+	})
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if token == "" {
+		token = r.URL.Query().Get("token")
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(s.config.APIToken)) == 1
+}
@@ -0,0 +1,56 @@
+package server
+
+import (
+	_ "embed"
+	"net/http"
+	"time"
+
+	"interview-bot-complete/internal/metrics"
+	"interview-bot-complete/internal/storage"
+)
+
+//go:embed dashboard.html
+var dashboardHTML []byte
+
+// DashboardSources - источники данных дашборда
+type DashboardSources struct {
+	Metrics      *metrics.Registry
+	LiveSessions func() map[string]int
+}
+
+// DashboardReport - ответ эндпоинта /api/dashboard
+type DashboardReport struct {
+	GeneratedAt  time.Time        `json:"generated_at"`
+	LiveSessions map[string]int   `json:"live_sessions"`
+	Process      metrics.Snapshot `json:"process"`
+	Storage      *storage.Stats   `json:"storage,omitempty"`
+	StorageError string           `json:"storage_error,omitempty"`
+}
+
+// EnableDashboard регистрирует /dashboard (HTML) и /api/dashboard (JSON). Страница открывается
+// как /dashboard?token=<token> и передает параметры своего адреса в запросы к /api/dashboard.
+func (s *Server) EnableDashboard(sources DashboardSources) {
+	s.HandleAPI("/dashboard", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		w.Write(dashboardHTML)
+	})
+
+	s.HandleAPI("/api/dashboard", func(w http.ResponseWriter, r *http.Request) {
+		report := DashboardReport{
+			GeneratedAt:  time.Now(),
+			LiveSessions: map[string]int{},
+			Process:      sources.Metrics.Snapshot(),
+		}
+		if sources.LiveSessions != nil {
+			report.LiveSessions = sources.LiveSessions()
+		}
+		if stats, err := storage.CollectStats(); err != nil {
+			report.StorageError = err.Error()
+		} else {
+			report.Storage = stats
+		}
+
+		writeJSON(w, http.StatusOK, report)
+	})
+}
@@ -0,0 +1,44 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"interview-bot-complete/internal/storage"
+)
+
+// defaultDropoffDays - за сколько дней строится отчет, если days не указан
+const defaultDropoffDays = 30
+
+// EnableDropoffReport регистрирует GET /api/analytics/dropoff?days=<n>: на каких вопросах
+// пользователи бросают интервью и средняя длина ответов (days=0 - за все время).
+func (s *Server) EnableDropoffReport() {
+	s.HandleAPI("/api/analytics/dropoff", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSON(w, http.StatusMethodNotAllowed, APIError{Error: "method not allowed"})
+			return
+		}
+
+		days := defaultDropoffDays
+		if raw := r.URL.Query().Get("days"); raw != "" {
+			parsed, err := strconv.Atoi(raw)
+			if err != nil || parsed < 0 {
+				writeJSON(w, http.StatusBadRequest, APIError{Error: "days must be a non-negative integer"})
+				return
+			}
+			days = parsed
+		}
+		var since time.Time
+		if days > 0 {
+			since = time.Now().AddDate(0, 0, -days)
+		}
+
+		report, err := storage.CollectDropoffs(since)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, APIError{Error: err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, report)
+	})
+}
@@ -0,0 +1,176 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"interview-bot-complete/internal/config"
+)
+
+// CheckFunc проверяет доступность зависимости; nil означает, что зависимость готова
+type CheckFunc func(ctx context.Context) error
+
+// checkTimeout - максимальное время выполнения одной проверки
+const checkTimeout = 5 * time.Second
+
+// Server - HTTP сервер служебных эндпоинтов
+type Server struct {
+	config config.ServerConfig
+	mux    *http.ServeMux
+	checks map[string]CheckFunc
+	mutex  sync.RWMutex
+	http   *http.Server
+}
+
+// CheckResult - результат одной проверки готовности
+type CheckResult struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	Took   string `json:"took"`
+}
+
+// ReadinessReport - ответ эндпоинта /readyz
+type ReadinessReport struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+// New создает сервер с эндпоинтами /healthz и /readyz
+func New(cfg config.ServerConfig) *Server {
+	s := &Server{
+		config: cfg,
+		mux:    http.NewServeMux(),
+		checks: make(map[string]CheckFunc),
+	}
+
+	s.mux.HandleFunc("/healthz", s.handleHealthz)
+	s.mux.HandleFunc("/readyz", s.handleReadyz)
+
+	return s
+}
+
+// Handle регистрирует обработчик, который сам проверяет запросы (например, вебхук Telegram -
+// по секретному заголовку), без токена SERVER_API_TOKEN
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+// AddReadinessCheck регистрирует проверку зависимости для /readyz
+func (s *Server) AddReadinessCheck(name string, check CheckFunc) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.checks[name] = check
+}
+
+// Start запускает HTTP сервер (блокирующий вызов)
+func (s *Server) Start() error {
+	// HTTP/2 без TLS (h2c) нужен клиентам gRPC InterviewService
+	protocols := new(http.Protocols)
+	protocols.SetHTTP1(true)
+	protocols.SetUnencryptedHTTP2(true)
+	s.http = &http.Server{
+		Addr:         fmt.Sprintf(":%d", s.config.Port),
+		Handler:      s.mux,
+		ReadTimeout:  s.config.ReadTimeout,
+		WriteTimeout: s.config.WriteTimeout,
+		Protocols:    protocols,
+	}
+
+	slog.Info("HTTP сервер слушает порт", "port", s.config.Port)
+	if s.config.APIToken == "" {
+		slog.Warn("SERVER_API_TOKEN не задан: эндпоинты API отвечают 503")
+	}
+	err := s.http.ListenAndServe()
+	if err == http.ErrServerClosed {
+		return nil
+	}
+	return err
+}
+
+// Shutdown корректно останавливает сервер
+func (s *Server) Shutdown(ctx context.Context) error {
+	if s.http == nil {
+		return nil
+	}
+	return s.http.Shutdown(ctx)
+}
+
+// handleHealthz - liveness: процесс жив и обслуживает запросы
+func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("ok"))
+}
+
+// handleReadyz - readiness: все зависимости доступны
+func (s *Server) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	report := s.RunChecks(r.Context())
+
+	status := http.StatusOK
+	if report.Status != "ok" {
+		status = http.StatusServiceUnavailable
+	}
+
+	writeJSON(w, status, report)
+}
+
+// RunChecks параллельно выполняет все проверки готовности
+func (s *Server) RunChecks(ctx context.Context) ReadinessReport {
+	s.mutex.RLock()
+	names := make([]string, 0, len(s.checks))
+	for name := range s.checks {
+		names = append(names, name)
+	}
+	s.mutex.RUnlock()
+	sort.Strings(names)
+
+	results := make([]CheckResult, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		s.mutex.RLock()
+		check := s.checks[name]
+		s.mutex.RUnlock()
+
+		wg.Add(1)
+		go func(i int, check CheckFunc) {
+			defer wg.Done()
+			results[i] = runCheck(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	report := ReadinessReport{Status: "ok", Checks: make(map[string]CheckResult, len(names))}
+	for i, name := range names {
+		report.Checks[name] = results[i]
+		if results[i].Status != "ok" {
+			report.Status = "fail"
+		}
+	}
+	return report
+}
+
+func runCheck(ctx context.Context, check CheckFunc) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	started := time.Now()
+	err := check(ctx)
+	result := CheckResult{Status: "ok", Took: time.Since(started).Round(time.Millisecond).String()}
+	if err != nil {
+		result.Status = "fail"
+		result.Error = err.Error()
+	}
+	return result
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
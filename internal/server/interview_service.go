@@ -0,0 +1,9 @@
+package server
+
+import "net/http"
+
+// EnableInterviewService регистрирует InterviewService (API интервью) по пути path. API проводит
+// интервью от имени любого user_id и отдает профили.
+func (s *Server) EnableInterviewService(path string, handler http.HandlerFunc) {
+	s.HandleAPI(path, handler)
+}
@@ -0,0 +1,40 @@
+package server
+
+import (
+	"net/http"
+
+	"interview-bot-complete/internal/storage"
+)
+
+// InvitationsResponse - ответ эндпоинта /api/invitations
+type InvitationsResponse struct {
+	Batch       string                     `json:"batch,omitempty"`
+	Counts      map[string]int             `json:"counts"`
+	Invitations []storage.IssuedInvitation `json:"invitations"`
+}
+
+// EnableInvitations регистрирует GET /api/invitations?batch=<id> со статусами массовых приглашений.
+func (s *Server) EnableInvitations() {
+	s.HandleAPI("/api/invitations", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSON(w, http.StatusMethodNotAllowed, APIError{Error: "method not allowed"})
+			return
+		}
+
+		batch := r.URL.Query().Get("batch")
+		invitations, err := storage.ListIssuedInvitations(batch)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, APIError{Error: err.Error()})
+			return
+		}
+
+		counts := make(map[string]int, len(storage.InvitationStatuses))
+		for _, status := range storage.InvitationStatuses {
+			counts[status] = 0
+		}
+		for _, invitation := range invitations {
+			counts[invitation.Status]++
+		}
+		writeJSON(w, http.StatusOK, InvitationsResponse{Batch: batch, Counts: counts, Invitations: invitations})
+	})
+}
@@ -0,0 +1,72 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"interview-bot-complete/internal/storage"
+)
+
+const (
+	// defaultLinkCodeTTL - срок действия кода привязки, если ttl не указан
+	defaultLinkCodeTTL = 72 * time.Hour
+	// maxLinkCodeTTL - наибольший срок действия кода привязки
+	maxLinkCodeTTL = 30 * 24 * time.Hour
+	// maxLinkCodeRequestBytes - предел размера тела запроса кода привязки
+	maxLinkCodeRequestBytes = 4 << 10
+)
+
+// LinkCodeRequest - тело запроса POST /api/link-codes
+type LinkCodeRequest struct {
+	ExternalID string `json:"external_id"`
+	// TTL - срок действия кода (Go duration, например "48h"); пусто - 72h
+	TTL string `json:"ttl,omitempty"`
+}
+
+// LinkCodeResponse - выданный код и команда, которую пользователь отправляет боту
+type LinkCodeResponse struct {
+	storage.LinkCode
+	Command string `json:"command"`
+}
+
+// EnableLinkCodes регистрирует POST /api/link-codes: HR-система получает одноразовый код,
+// которым пользователь командой /link привязывает свой Telegram к сотруднику или кандидату.
+func (s *Server) EnableLinkCodes() {
+	s.HandleAPI("/api/link-codes", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeJSON(w, http.StatusMethodNotAllowed, APIError{Error: "method not allowed"})
+			return
+		}
+
+		var request LinkCodeRequest
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxLinkCodeRequestBytes+1))
+		if err != nil || len(body) > maxLinkCodeRequestBytes || json.Unmarshal(body, &request) != nil {
+			writeJSON(w, http.StatusBadRequest, APIError{Error: "request body must be a JSON object"})
+			return
+		}
+		request.ExternalID = strings.TrimSpace(request.ExternalID)
+		if request.ExternalID == "" {
+			writeJSON(w, http.StatusBadRequest, APIError{Error: "external_id is required"})
+			return
+		}
+
+		ttl := defaultLinkCodeTTL
+		if request.TTL != "" {
+			ttl, err = time.ParseDuration(request.TTL)
+			if err != nil || ttl <= 0 || ttl > maxLinkCodeTTL {
+				writeJSON(w, http.StatusBadRequest, APIError{Error: "ttl must be a duration between 1s and 720h"})
+				return
+			}
+		}
+
+		code, err := storage.IssueLinkCode(request.ExternalID, ttl)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, APIError{Error: err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusCreated, LinkCodeResponse{LinkCode: *code, Command: "/link " + code.Code})
+	})
+}
@@ -0,0 +1,35 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// ProfileSchemaPath - путь публикации JSON Schema профиля
+const ProfileSchemaPath = "/schema/profile.json"
+
+// ProfileSchemaFunc возвращает JSON Schema профиля арендатора (пустой - общая схема)
+type ProfileSchemaFunc func(tenantID string) map[string]interface{}
+
+// EnableProfileSchema публикует JSON Schema профиля: GET /schema/profile.json[?tenant=<id>].
+// Схема не содержит данных участников и доступна без токена API, чтобы ее могли забирать
+// генераторы клиентов и валидаторы потребителей профилей.
+func (s *Server) EnableProfileSchema(schemaFor ProfileSchemaFunc) {
+	s.mux.HandleFunc(ProfileSchemaPath, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			writeJSON(w, http.StatusMethodNotAllowed, APIError{Error: "method not allowed"})
+			return
+		}
+
+		document := schemaFor(r.URL.Query().Get("tenant"))
+		document["$id"] = r.URL.RequestURI()
+		data, err := json.MarshalIndent(document, "", "  ")
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, APIError{Error: err.Error()})
+			return
+		}
+		w.Header().Set("Content-Type", "application/schema+json")
+		w.WriteHeader(http.StatusOK)
+		w.Write(data)
+	})
+}
@@ -0,0 +1,53 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"interview-bot-complete/internal/search"
+)
+
+// defaultSearchLimit - число интервью в ответе поиска по умолчанию
+const defaultSearchLimit = 20
+
+// maxSearchLimit - максимальное число интервью в ответе поиска
+const maxSearchLimit = 100
+
+// EnableSearch регистрирует GET /api/search?q=<запрос>&limit=<n>&tenant=<id>: интервью, в ответах
+// или профиле которых встречаются все слова запроса, с фрагментами совпадений.
+func (s *Server) EnableSearch(index *search.Index) {
+	s.HandleAPI("/api/search", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSON(w, http.StatusMethodNotAllowed, APIError{Error: "method not allowed"})
+			return
+		}
+
+		query := r.URL.Query().Get("q")
+		if query == "" {
+			writeJSON(w, http.StatusBadRequest, APIError{Error: "q is required"})
+			return
+		}
+
+		limit := defaultSearchLimit
+		if raw := r.URL.Query().Get("limit"); raw != "" {
+			parsed, err := strconv.Atoi(raw)
+			if err != nil || parsed <= 0 || parsed > maxSearchLimit {
+				writeJSON(w, http.StatusBadRequest, APIError{Error: "limit must be between 1 and 100"})
+				return
+			}
+			limit = parsed
+		}
+
+		results, err := index.Search(query, search.Options{Limit: limit, Tenant: r.URL.Query().Get("tenant")})
+		if errors.Is(err, search.ErrEmptyQuery) {
+			writeJSON(w, http.StatusBadRequest, APIError{Error: "q has no searchable words"})
+			return
+		}
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, APIError{Error: err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, results)
+	})
+}
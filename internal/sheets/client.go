@@ -0,0 +1,227 @@
+package sheets
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"interview-bot-complete/internal/httpclient"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	sheetsAPIURL    = "https://sheets.googleapis.com/v4/spreadsheets"
+	defaultTokenURL = "https://oauth2.googleapis.com/token"
+	sheetsScope     = "https://www.googleapis.com/auth/spreadsheets"
+	// proxyEnv - прокси запросов к Google; перекрывает HTTPS_PROXY
+	proxyEnv = "SHEETS_PROXY"
+	// tokenLifetime - срок действия запрашиваемого токена (максимум для сервисного аккаунта - час)
+	tokenLifetime = time.Hour
+)
+
+// credentials - ключ сервисного аккаунта Google (JSON, скачанный из консоли)
+type credentials struct {
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// client обращается к Google Sheets API от имени сервисного аккаунта
+type client struct {
+	email    string
+	key      *rsa.PrivateKey
+	tokenURL string
+	http     *http.Client
+
+	mutex   sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// newClient читает ключ сервисного аккаунта из файла
+func newClient(credentialsFile string) (*client, error) {
+	data, err := os.ReadFile(credentialsFile)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения ключа сервисного аккаунта: %w", err)
+	}
+	var creds credentials
+	if err := json.Unmarshal(data, &creds); err != nil {
+		return nil, fmt.Errorf("ошибка парсинга ключа сервисного аккаунта: %w", err)
+	}
+	if creds.ClientEmail == "" || creds.PrivateKey == "" {
+		return nil, fmt.Errorf("в ключе сервисного аккаунта нет client_email или private_key")
+	}
+
+	block, _ := pem.Decode([]byte(creds.PrivateKey))
+	if block == nil {
+		return nil, fmt.Errorf("private_key сервисного аккаунта не в формате PEM")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения private_key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private_key сервисного аккаунта должен быть RSA ключом")
+	}
+
+	tokenURL := creds.TokenURI
+	if tokenURL == "" {
+		tokenURL = defaultTokenURL
+	}
+	transport, err := httpclient.NewTransport(httpclient.OptionsFromEnv(proxyEnv))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка настройки HTTP клиента Google Sheets (SHEETS_PROXY, HTTP_CA_CERT_FILE): %w", err)
+	}
+	return &client{
+		email:    creds.ClientEmail,
+		key:      key,
+		tokenURL: tokenURL,
+		http:     &http.Client{Timeout: 30 * time.Second, Transport: transport},
+	}, nil
+}
+
+// accessToken возвращает OAuth токен, обменивая подписанный JWT на новый за минуту до истечения старого
+func (c *client) accessToken(ctx context.Context) (string, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.token != "" && time.Until(c.expires) > time.Minute {
+		return c.token, nil
+	}
+
+	assertion, err := c.signJWT(time.Now())
+	if err != nil {
+		return "", err
+	}
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", c.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("ошибка создания запроса токена: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := c.do(req, &token); err != nil {
+		return "", fmt.Errorf("ошибка получения токена Google: %w", err)
+	}
+	if token.AccessToken == "" {
+		return "", fmt.Errorf("Google не вернул access_token")
+	}
+	c.token = token.AccessToken
+	c.expires = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	return c.token, nil
+}
+
+// signJWT подписывает RS256 JWT для обмена на токен с доступом к таблицам
+func (c *client) signJWT(now time.Time) (string, error) {
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	claims, _ := json.Marshal(map[string]interface{}{
+		"iss":   c.email,
+		"scope": sheetsScope,
+		"aud":   c.tokenURL,
+		"iat":   now.Unix(),
+		"exp":   now.Add(tokenLifetime).Unix(),
+	})
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+
+	hash := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, c.key, crypto.SHA256, hash[:])
+	if err != nil {
+		return "", fmt.Errorf("ошибка подписи JWT: %w", err)
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// values читает значения диапазона таблицы
+func (c *client) values(ctx context.Context, spreadsheetID, valueRange string) ([][]string, error) {
+	var response struct {
+		Values [][]string `json:"values"`
+	}
+	err := c.call(ctx, "GET", fmt.Sprintf("%s/%s/values/%s", sheetsAPIURL, url.PathEscape(spreadsheetID), url.PathEscape(valueRange)), nil, &response)
+	return response.Values, err
+}
+
+// update записывает значения в диапазон таблицы
+func (c *client) update(ctx context.Context, spreadsheetID, valueRange string, rows [][]string) error {
+	endpoint := fmt.Sprintf("%s/%s/values/%s?valueInputOption=RAW", sheetsAPIURL, url.PathEscape(spreadsheetID), url.PathEscape(valueRange))
+	return c.call(ctx, "PUT", endpoint, map[string]interface{}{"values": rows}, nil)
+}
+
+// appendRows дописывает строки после последней заполненной строки листа
+func (c *client) appendRows(ctx context.Context, spreadsheetID, valueRange string, rows [][]string) error {
+	endpoint := fmt.Sprintf("%s/%s/values/%s:append?valueInputOption=RAW&insertDataOption=INSERT_ROWS",
+		sheetsAPIURL, url.PathEscape(spreadsheetID), url.PathEscape(valueRange))
+	return c.call(ctx, "POST", endpoint, map[string]interface{}{"values": rows}, nil)
+}
+
+// call выполняет авторизованный запрос к Sheets API
+func (c *client) call(ctx context.Context, method, endpoint string, payload, result interface{}) error {
+	token, err := c.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("ошибка сериализации запроса: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return fmt.Errorf("ошибка создания запроса: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if err := c.do(req, result); err != nil {
+		return fmt.Errorf("ошибка Google Sheets API: %w", err)
+	}
+	return nil
+}
+
+// do отправляет запрос и разбирает JSON ответа в result (nil - ответ не нужен)
+func (c *client) do(req *http.Request, result interface{}) error {
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("ошибка чтения ответа: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("статус %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	if result == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, result); err != nil {
+		return fmt.Errorf("ошибка разбора ответа: %w", err)
+	}
+	return nil
+}
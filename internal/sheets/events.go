@@ -0,0 +1,32 @@
+package sheets
+
+import (
+	"context"
+	"interview-bot-complete/internal/events"
+	"interview-bot-complete/internal/logging"
+	"interview-bot-complete/internal/storage"
+	"log/slog"
+	"time"
+)
+
+// exportTimeout - максимальное время выгрузки одного профиля в таблицу
+const exportTimeout = time.Minute
+
+// HandleEvent в фоне дописывает в таблицу профиль, составленный по завершенному интервью
+func (e *Exporter) HandleEvent(event events.Event) {
+	if event.Kind != events.ProfileExtracted {
+		return
+	}
+	go func() {
+		result, err := storage.LoadResult(event.InterviewID)
+		if err != nil {
+			slog.Warn("Не удалось загрузить результат для выгрузки в таблицу", logging.KeyInterviewID, event.InterviewID, "error", err)
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+		defer cancel()
+		if err := e.Export(ctx, result, event.ProfileJSON); err != nil {
+			slog.Warn("Не удалось выгрузить профиль в таблицу", logging.KeyInterviewID, event.InterviewID, "error", err)
+		}
+	}()
+}
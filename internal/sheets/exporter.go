@@ -0,0 +1,211 @@
+package sheets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"interview-bot-complete/internal/config"
+	"interview-bot-complete/internal/storage"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// serviceColumns - колонки интервью перед полями профиля
+var serviceColumns = []string{"interview_id", "completed_at", "template_id", "candidate", "language"}
+
+// ProfileSource возвращает JSON профиля по ID интервью
+type ProfileSource func(interviewID string) (string, error)
+
+// Row - профиль для выгрузки: результат интервью и JSON профиля
+type Row struct {
+	Result      *storage.InterviewResult
+	ProfileJSON string
+}
+
+// Exporter дописывает ключевые поля профилей строками в таблицу Google Sheets
+type Exporter struct {
+	client        *client
+	spreadsheetID string
+	sheet         string
+	columns       []string
+	source        ProfileSource
+
+	// mutex упорядочивает выгрузки: заголовок пишется один раз, строки не дублируются
+	mutex         sync.Mutex
+	headerChecked bool
+}
+
+// BackfillReport - итог выгрузки сохраненных профилей
+type BackfillReport struct {
+	Exported int
+	Skipped  int // уже были в таблице
+	Missing  int // интервью без профиля или с нечитаемым результатом
+}
+
+// New создает выгрузку в таблицу по ключу сервисного аккаунта
+func New(cfg config.SheetsConfig, source ProfileSource) (*Exporter, error) {
+	if cfg.SpreadsheetID == "" {
+		return nil, fmt.Errorf("не задан ID таблицы (GOOGLE_SHEETS_SPREADSHEET_ID)")
+	}
+	if len(cfg.Columns) == 0 {
+		return nil, fmt.Errorf("не заданы колонки профиля (GOOGLE_SHEETS_COLUMNS)")
+	}
+	c, err := newClient(cfg.CredentialsFile)
+	if err != nil {
+		return nil, err
+	}
+	return &Exporter{
+		client:        c,
+		spreadsheetID: cfg.SpreadsheetID,
+		sheet:         cfg.Sheet,
+		columns:       cfg.Columns,
+		source:        source,
+	}, nil
+}
+
+// Export дописывает профиль завершенного интервью; повторная выгрузка того же интервью пропускается
+func (e *Exporter) Export(ctx context.Context, result *storage.InterviewResult, profileJSON string) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	exported, err := storage.SheetExportedInterviews(e.spreadsheetID)
+	if err != nil {
+		return err
+	}
+	if exported[result.InterviewID] {
+		return nil
+	}
+	return e.appendRows(ctx, []Row{{Result: result, ProfileJSON: profileJSON}})
+}
+
+// Backfill выгружает все сохраненные профили, которых еще нет в таблице
+func (e *Exporter) Backfill(ctx context.Context) (*BackfillReport, error) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	ids, err := storage.ListResults()
+	if err != nil {
+		return nil, err
+	}
+	exported, err := storage.SheetExportedInterviews(e.spreadsheetID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &BackfillReport{}
+	var rows []Row
+	for _, id := range ids {
+		if exported[id] {
+			report.Skipped++
+			continue
+		}
+		result, err := storage.LoadResult(id)
+		if err != nil {
+			report.Missing++
+			continue
+		}
+		profileJSON, err := e.source(id)
+		if err != nil {
+			report.Missing++
+			continue
+		}
+		rows = append(rows, Row{Result: result, ProfileJSON: profileJSON})
+	}
+	if len(rows) == 0 {
+		return report, nil
+	}
+
+	// Строки в порядке прохождения интервью
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].Result.Timestamp < rows[j].Result.Timestamp })
+	if err := e.appendRows(ctx, rows); err != nil {
+		return nil, err
+	}
+	report.Exported = len(rows)
+	return report, nil
+}
+
+// appendRows дописывает строки и отмечает интервью выгруженными. Вызывается под mutex.
+func (e *Exporter) appendRows(ctx context.Context, rows []Row) error {
+	if err := e.ensureHeader(ctx); err != nil {
+		return err
+	}
+
+	values := make([][]string, 0, len(rows))
+	ids := make([]string, 0, len(rows))
+	for _, row := range rows {
+		values = append(values, e.row(row))
+		ids = append(ids, row.Result.InterviewID)
+	}
+	if err := e.client.appendRows(ctx, e.spreadsheetID, e.sheetRange("A1"), values); err != nil {
+		return err
+	}
+	return storage.RecordSheetExports(e.spreadsheetID, ids)
+}
+
+// ensureHeader записывает строку заголовков в пустой лист
+func (e *Exporter) ensureHeader(ctx context.Context) error {
+	if e.headerChecked {
+		return nil
+	}
+	existing, err := e.client.values(ctx, e.spreadsheetID, e.sheetRange("1:1"))
+	if err != nil {
+		return err
+	}
+	if len(existing) == 0 {
+		header := append(append([]string(nil), serviceColumns...), e.columns...)
+		if err := e.client.update(ctx, e.spreadsheetID, e.sheetRange("A1"), [][]string{header}); err != nil {
+			return err
+		}
+	}
+	e.headerChecked = true
+	return nil
+}
+
+// sheetRange возвращает диапазон листа в A1-нотации; имя листа всегда в кавычках
+func (e *Exporter) sheetRange(cells string) string {
+	return "'" + strings.ReplaceAll(e.sheet, "'", "''") + "'!" + cells
+}
+
+// row формирует строку таблицы: служебные колонки и выбранные поля профиля
+func (e *Exporter) row(row Row) []string {
+	result := row.Result
+	candidate := ""
+	if result.Invitation != nil {
+		candidate = result.Invitation.Candidate
+	}
+	values := []string{result.InterviewID, result.CompletedAt, result.TemplateID, candidate, result.Language}
+
+	var profile map[string]interface{}
+	json.Unmarshal([]byte(row.ProfileJSON), &profile)
+	for _, column := range e.columns {
+		values = append(values, cellValue(profile[column]))
+	}
+	return values
+}
+
+// cellValue переводит значение поля профиля в текст ячейки: списки - через запятую
+func cellValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			if text := cellValue(item); text != "" {
+				items = append(items, text)
+			}
+		}
+		return strings.Join(items, ", ")
+	default:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+}
@@ -0,0 +1,16 @@
+package storage
+
+import "path/filepath"
+
+// atsExportsDir - директория профилей в форматах ATS внутри директории профилей
+const atsExportsDir = "ats"
+
+// ATSExportPath возвращает путь профиля в формате ATS:
+// <output>/ats/<формат>/[<арендатор>/]profile_<id><расширение>
+func ATSExportPath(format, interviewID, tenantID, extension string) string {
+	dir := filepath.Join(paths.OutputDir, atsExportsDir, format)
+	if len(paths.TenantDirs) > 0 {
+		dir = filepath.Join(dir, tenantDir(tenantID))
+	}
+	return filepath.Join(dir, "profile_"+interviewID+extension)
+}
@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+)
+
+// batchJobsDir - директория пакетных повторных извлечений внутри директории результатов
+const batchJobsDir = "batches"
+
+// BatchJob - повторное извлечение профилей пакетом OpenAI Batch API. Файл задания позволяет
+// дождаться пакета и принять его результаты после перезапуска (backfill -batch-resume).
+type BatchJob struct {
+	BatchID       string   `json:"batch_id"`
+	Model         string   `json:"model,omitempty"`
+	PromptVersion string   `json:"prompt_version,omitempty"`
+	SubmittedAt   string   `json:"submitted_at"`
+	InterviewIDs  []string `json:"interview_ids"`
+	// Ingested - интервью, профили которых из пакета уже сохранены новой ревизией
+	Ingested []string `json:"ingested,omitempty"`
+	// Failed - интервью, запросы которых завершились ошибкой, с текстом ошибки
+	Failed map[string]string `json:"failed,omitempty"`
+}
+
+// BatchJobPath возвращает путь файла задания: <results>/batches/batch_<id>.json
+func BatchJobPath(batchID string) string {
+	return filepath.Join(paths.ResultsDir, batchJobsDir, "batch_"+batchID+".json")
+}
+
+// SaveBatchJob атомарно сохраняет задание пакетного извлечения
+func SaveBatchJob(job *BatchJob) error {
+	data, err := json.MarshalIndent(job, "", "  ")
+	if err != nil {
+		return fmt.Errorf("ошибка сериализации пакетного задания: %w", err)
+	}
+	return WriteFileAtomic(BatchJobPath(job.BatchID), data)
+}
+
+// LoadBatchJob загружает задание пакетного извлечения
+func LoadBatchJob(batchID string) (*BatchJob, error) {
+	data, err := ReadFileVerified(BatchJobPath(batchID))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения пакетного задания %s: %w", batchID, err)
+	}
+	var job BatchJob
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, fmt.Errorf("ошибка разбора пакетного задания %s: %w", batchID, err)
+	}
+	return &job, nil
+}
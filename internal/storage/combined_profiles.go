@@ -0,0 +1,28 @@
+package storage
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// combinedProfilesDir - директория сводных профилей пользователей внутри директории профилей
+const combinedProfilesDir = "combined"
+
+// CombinedProfilePath возвращает путь сводного профиля пользователя по всем его интервью:
+// <output>/combined/[<арендатор>/]combined_<user_id>.json
+func CombinedProfilePath(userID int64, tenantID string) string {
+	dir := filepath.Join(paths.OutputDir, combinedProfilesDir)
+	if len(paths.TenantDirs) > 0 {
+		dir = filepath.Join(dir, tenantDir(tenantID))
+	}
+	return filepath.Join(dir, fmt.Sprintf("combined_%d.json", userID))
+}
+
+// SaveCombinedProfile атомарно сохраняет сводный профиль отдельно от профилей интервью
+func SaveCombinedProfile(userID int64, tenantID string, data []byte) (string, error) {
+	path := CombinedProfilePath(userID, tenantID)
+	if err := WriteFileAtomic(path, data); err != nil {
+		return "", err
+	}
+	return path, nil
+}
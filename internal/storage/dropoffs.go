@@ -0,0 +1,266 @@
+package storage
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const dropoffsLogFile = "dropoffs.jsonl"
+
+// Причины, по которым пользователь не закончил интервью
+const (
+	DropoffStopped   = "stopped"   // /stop
+	DropoffRestarted = "restarted" // /restart или новое интервью вместо незавершенного
+	DropoffInactive  = "inactive"  // долго не отвечал
+)
+
+// dropoffsMutex защищает журнал брошенных интервью от одновременной записи
+var dropoffsMutex sync.Mutex
+
+// Dropoff - запись журнала: пользователь бросил интервью на вопросе Question блока BlockID
+type Dropoff struct {
+	InterviewID string `json:"interview_id"`
+	TemplateID  string `json:"template_id,omitempty"`
+	Tenant      string `json:"tenant,omitempty"`
+	Reason      string `json:"reason"`
+	At          string `json:"at"`
+	BlockID     int    `json:"block_id"`
+	BlockName   string `json:"block_name,omitempty"`
+	// Question - номер вопроса в блоке (с 1), оставшегося без ответа; на единицу больше
+	// заданных вопросов, если пользователь ушел между вопросами (например, на проверке блока)
+	Question     int    `json:"question"`
+	QuestionText string `json:"question_text,omitempty"`
+	// Answers - вопросы, на которые пользователь успел ответить
+	Answers []DropoffAnswer `json:"answers,omitempty"`
+}
+
+// DropoffAnswer - ответ брошенного интервью: номер вопроса в блоке и длина ответа в символах
+type DropoffAnswer struct {
+	BlockID  int  `json:"block_id"`
+	Question int  `json:"question"`
+	Length   int  `json:"length"`
+	Skipped  bool `json:"skipped,omitempty"`
+}
+
+// QuestionDropoff - воронка вопроса: сколько пользователей до него дошли и сколько на нем ушли
+type QuestionDropoff struct {
+	TemplateID      string  `json:"template_id"`
+	BlockID         int     `json:"block_id"`
+	BlockName       string  `json:"block_name,omitempty"`
+	Question        int     `json:"question"`
+	Text            string  `json:"text,omitempty"` // формулировка из шаблона; пусто - вопросы на этом месте генерирует модель
+	Reached         int     `json:"reached"`
+	Abandoned       int     `json:"abandoned"`
+	DropoffRate     float64 `json:"dropoff_rate"`
+	Answers         int     `json:"answers"`
+	AvgAnswerLength float64 `json:"avg_answer_length"`
+}
+
+// DropoffReport - отчет об уходе пользователей по вопросам за период
+type DropoffReport struct {
+	Since     string            `json:"since,omitempty"`
+	Completed int               `json:"completed"`
+	Abandoned int               `json:"abandoned"`
+	ByReason  map[string]int    `json:"by_reason"`
+	Questions []QuestionDropoff `json:"questions"`
+	// Corrupted - поврежденные файлы результатов, пропущенные при подсчете
+	Corrupted int `json:"corrupted,omitempty"`
+}
+
+// RecordDropoff дописывает брошенное интервью в журнал dropoffs.jsonl директории результатов
+func RecordDropoff(dropoff *Dropoff) error {
+	dropoffsMutex.Lock()
+	defer dropoffsMutex.Unlock()
+
+	resultsDir := paths.ResultsDir
+	if err := os.MkdirAll(resultsDir, 0755); err != nil {
+		return fmt.Errorf("ошибка создания директории %s: %w", resultsDir, err)
+	}
+
+	line, err := json.Marshal(dropoff)
+	if err != nil {
+		return fmt.Errorf("ошибка сериализации брошенного интервью: %w", err)
+	}
+
+	path := filepath.Join(resultsDir, dropoffsLogFile)
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("ошибка открытия файла %s: %w", path, err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("ошибка записи файла %s: %w", path, err)
+	}
+	return nil
+}
+
+// CollectDropoffs строит отчет по завершенным интервью и журналу брошенных начиная с since
+// (нулевое время - за все время). Вопрос определяется шаблоном, блоком и номером в блоке;
+// доля ухода - брошенные на вопросе среди дошедших до него.
+func CollectDropoffs(since time.Time) (*DropoffReport, error) {
+	report := &DropoffReport{ByReason: make(map[string]int)}
+	if !since.IsZero() {
+		report.Since = since.Format(time.RFC3339)
+	}
+	funnel := make(dropoffFunnel)
+
+	ids, err := ListResults()
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		result, err := LoadResult(id)
+		if errors.Is(err, ErrCorrupted) {
+			report.Corrupted++
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("ошибка чтения результата %s: %w", id, err)
+		}
+		// Прерванные интервью учитываются по журналу брошенных
+		if result.Partial || !notBefore(result.CompletedAt, result.Timestamp, since) {
+			continue
+		}
+		report.Completed++
+		for _, block := range result.Blocks {
+			for i, qa := range block.QuestionsAndAnswers {
+				question := funnel.question(result.TemplateID, block.BlockID, i+1)
+				question.BlockName = block.BlockName
+				if question.Text == "" && (qa.Source == QuestionSourceStatic || qa.Source == "") {
+					question.Text = qa.Question
+				}
+				question.reach(qa.Skipped, len([]rune(qa.Answer)))
+			}
+		}
+	}
+
+	dropoffs, err := loadDropoffs()
+	if err != nil {
+		return nil, err
+	}
+	for _, dropoff := range dropoffs {
+		if !notBefore(dropoff.At, "", since) {
+			continue
+		}
+		report.Abandoned++
+		report.ByReason[dropoff.Reason]++
+		for _, answer := range dropoff.Answers {
+			funnel.question(dropoff.TemplateID, answer.BlockID, answer.Question).reach(answer.Skipped, answer.Length)
+		}
+		question := funnel.question(dropoff.TemplateID, dropoff.BlockID, dropoff.Question)
+		if question.BlockName == "" {
+			question.BlockName = dropoff.BlockName
+		}
+		if question.Text == "" {
+			question.Text = dropoff.QuestionText
+		}
+		question.Reached++
+		question.Abandoned++
+	}
+
+	report.Questions = make([]QuestionDropoff, 0, len(funnel))
+	for _, question := range funnel {
+		question.DropoffRate = float64(question.Abandoned) / float64(question.Reached)
+		if question.Answers > 0 {
+			question.AvgAnswerLength /= float64(question.Answers)
+		}
+		report.Questions = append(report.Questions, *question)
+	}
+	sort.Slice(report.Questions, func(i, j int) bool {
+		a, b := report.Questions[i], report.Questions[j]
+		if a.TemplateID != b.TemplateID {
+			return a.TemplateID < b.TemplateID
+		}
+		if a.BlockID != b.BlockID {
+			return a.BlockID < b.BlockID
+		}
+		return a.Question < b.Question
+	})
+	return report, nil
+}
+
+// questionKey - вопрос шаблона: блок и номер в блоке
+type questionKey struct {
+	templateID string
+	blockID    int
+	question   int
+}
+
+type dropoffFunnel map[questionKey]*QuestionDropoff
+
+func (f dropoffFunnel) question(templateID string, blockID, number int) *QuestionDropoff {
+	if templateID == "" {
+		templateID = "default"
+	}
+	key := questionKey{templateID: templateID, blockID: blockID, question: number}
+	question, ok := f[key]
+	if !ok {
+		question = &QuestionDropoff{TemplateID: templateID, BlockID: blockID, Question: number}
+		f[key] = question
+	}
+	return question
+}
+
+// reach учитывает ответ на вопрос; длина пропущенного ответа в среднюю не входит.
+// Пока CollectDropoffs собирает отчет, AvgAnswerLength хранит сумму длин.
+func (q *QuestionDropoff) reach(skipped bool, length int) {
+	q.Reached++
+	if skipped {
+		return
+	}
+	q.Answers++
+	q.AvgAnswerLength += float64(length)
+}
+
+// notBefore сообщает, что момент at (или fallback, если at пуст) не раньше since
+func notBefore(at, fallback string, since time.Time) bool {
+	if since.IsZero() {
+		return true
+	}
+	if at == "" {
+		at = fallback
+	}
+	t, err := time.Parse(time.RFC3339, at)
+	return err == nil && !t.Before(since)
+}
+
+// loadDropoffs читает журнал брошенных интервью
+func loadDropoffs() ([]Dropoff, error) {
+	dropoffsMutex.Lock()
+	defer dropoffsMutex.Unlock()
+
+	path := filepath.Join(paths.ResultsDir, dropoffsLogFile)
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка открытия файла %s: %w", path, err)
+	}
+	defer file.Close()
+
+	var dropoffs []Dropoff
+	scanner := bufio.NewScanner(file)
+	// Записи с ответами длинного интервью бывают длиннее буфера по умолчанию
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var dropoff Dropoff
+		// Оборванную последнюю строку пропускаем
+		if json.Unmarshal(scanner.Bytes(), &dropoff) != nil {
+			continue
+		}
+		dropoffs = append(dropoffs, dropoff)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения файла %s: %w", path, err)
+	}
+	return dropoffs, nil
+}
@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const entitlementsLogFile = "entitlements.jsonl"
+
+// FeatureExtendedAnalysis - платный расширенный анализ профиля (отчет, архетип, PDF)
+const FeatureExtendedAnalysis = "extended_analysis"
+
+// entitlementsMutex защищает журнал покупок от одновременной записи
+var entitlementsMutex sync.Mutex
+
+// Entitlement - запись журнала покупок: пользователь получил доступ к функции
+type Entitlement struct {
+	UserID           int64  `json:"user_id"`
+	Feature          string `json:"feature"`
+	Currency         string `json:"currency"`
+	Amount           int    `json:"amount"`
+	TelegramChargeID string `json:"telegram_charge_id"`
+	ProviderChargeID string `json:"provider_charge_id,omitempty"`
+	PurchasedAt      string `json:"purchased_at"`
+}
+
+// RecordEntitlement дописывает покупку в журнал entitlements.jsonl директории результатов
+func RecordEntitlement(entitlement Entitlement) error {
+	entitlementsMutex.Lock()
+	defer entitlementsMutex.Unlock()
+
+	resultsDir := paths.ResultsDir
+	if err := os.MkdirAll(resultsDir, 0755); err != nil {
+		return fmt.Errorf("ошибка создания директории %s: %w", resultsDir, err)
+	}
+
+	line, err := json.Marshal(entitlement)
+	if err != nil {
+		return fmt.Errorf("ошибка сериализации покупки: %w", err)
+	}
+
+	path := filepath.Join(resultsDir, entitlementsLogFile)
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("ошибка открытия файла %s: %w", path, err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("ошибка записи файла %s: %w", path, err)
+	}
+	// Покупка не должна потеряться при сбое сразу после оплаты
+	return file.Sync()
+}
+
+// HasEntitlement проверяет по журналу, оплачена ли функция пользователем
+func HasEntitlement(userID int64, feature string) (bool, error) {
+	entitlementsMutex.Lock()
+	defer entitlementsMutex.Unlock()
+
+	path := filepath.Join(paths.ResultsDir, entitlementsLogFile)
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("ошибка открытия файла %s: %w", path, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entitlement Entitlement
+		// Оборванную последнюю строку пропускаем
+		if json.Unmarshal(scanner.Bytes(), &entitlement) != nil {
+			continue
+		}
+		if entitlement.UserID == userID && entitlement.Feature == feature {
+			return true, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return false, fmt.Errorf("ошибка чтения файла %s: %w", path, err)
+	}
+	return false, nil
+}
@@ -0,0 +1,25 @@
+package storage
+
+import "path/filepath"
+
+// fitReportsDir - директория отчетов для рекрутеров внутри директории профилей
+const fitReportsDir = "fit_reports"
+
+// FitReportPath возвращает путь отчета о соответствии кандидата роли:
+// <output>/fit_reports/[<арендатор>/]fit_<id>.json
+func FitReportPath(interviewID, tenantID string) string {
+	dir := filepath.Join(paths.OutputDir, fitReportsDir)
+	if len(paths.TenantDirs) > 0 {
+		dir = filepath.Join(dir, tenantDir(tenantID))
+	}
+	return filepath.Join(dir, "fit_"+interviewID+".json")
+}
+
+// SaveFitReport атомарно сохраняет отчет для рекрутера отдельно от профиля кандидата
+func SaveFitReport(interviewID, tenantID string, data []byte) (string, error) {
+	path := FitReportPath(interviewID, tenantID)
+	if err := WriteFileAtomic(path, data); err != nil {
+		return "", err
+	}
+	return path, nil
+}
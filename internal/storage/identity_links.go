@@ -0,0 +1,240 @@
+package storage
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const identityLinksLogFile = "identity_links.jsonl"
+
+// Виды записей журнала привязок
+const (
+	linkEventIssued = "issued" // выдан одноразовый код
+	linkEventLinked = "linked" // пользователь привязан по коду
+)
+
+// Ошибки использования кода привязки
+var (
+	ErrLinkCodeNotFound = errors.New("код привязки не найден")
+	ErrLinkCodeUsed     = errors.New("код привязки уже использован")
+	ErrLinkCodeExpired  = errors.New("срок действия кода привязки истек")
+)
+
+// linkCodeBytes - длина кода привязки: 128 случайных бит, которые нельзя подобрать перебором
+const linkCodeBytes = 16
+
+// linkCodeEncoding - base32 без дополнения: 26 символов A-Z2-7, код нечувствителен к регистру
+var linkCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// identityLinksMutex защищает журнал привязок от одновременной записи и двойного использования кода
+var identityLinksMutex sync.Mutex
+
+// identityLinks - индекс журнала привязок в памяти. Журнал дописывается и другими репликами,
+// поэтому перед чтением индекс дочитывает строки, добавленные после offset.
+// Поля используются под identityLinksMutex.
+var identityLinks identityLinkIndex
+
+type identityLinkIndex struct {
+	path   string
+	offset int64
+	// issued - выданные коды, used - использованные, external - привязки пользователей
+	issued   map[string]identityLinkEvent
+	used     map[string]bool
+	external map[int64]string
+}
+
+// LinkCode - одноразовый код, которым пользователь Telegram привязывается к сотруднику
+// или кандидату во внешней HR-системе (/link <code>)
+type LinkCode struct {
+	Code       string `json:"code"`
+	ExternalID string `json:"external_id"`
+	IssuedAt   string `json:"issued_at"`
+	ExpiresAt  string `json:"expires_at"`
+}
+
+// identityLinkEvent - запись журнала: выдача кода или привязка по нему
+type identityLinkEvent struct {
+	Kind       string `json:"kind"`
+	Code       string `json:"code"`
+	ExternalID string `json:"external_id"`
+	UserID     int64  `json:"user_id,omitempty"`
+	At         string `json:"at"`
+	ExpiresAt  string `json:"expires_at,omitempty"`
+}
+
+// IssueLinkCode выдает одноразовый код привязки к externalID, действующий ttl
+func IssueLinkCode(externalID string, ttl time.Duration) (*LinkCode, error) {
+	identityLinksMutex.Lock()
+	defer identityLinksMutex.Unlock()
+
+	random := make([]byte, linkCodeBytes)
+	if _, err := rand.Read(random); err != nil {
+		return nil, fmt.Errorf("ошибка генерации кода привязки: %w", err)
+	}
+	now := time.Now()
+	code := &LinkCode{
+		Code:       linkCodeEncoding.EncodeToString(random),
+		ExternalID: externalID,
+		IssuedAt:   now.Format(time.RFC3339),
+		ExpiresAt:  now.Add(ttl).Format(time.RFC3339),
+	}
+	err := appendIdentityLinkEvent(identityLinkEvent{
+		Kind:       linkEventIssued,
+		Code:       code.Code,
+		ExternalID: externalID,
+		At:         code.IssuedAt,
+		ExpiresAt:  code.ExpiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return code, nil
+}
+
+// RedeemLinkCode привязывает пользователя к идентификатору кода и возвращает этот идентификатор.
+// Код действует один раз; повторная привязка пользователя заменяет прежнюю.
+func RedeemLinkCode(code string, userID int64) (string, error) {
+	identityLinksMutex.Lock()
+	defer identityLinksMutex.Unlock()
+
+	index, err := loadIdentityLinks()
+	if err != nil {
+		return "", err
+	}
+
+	code = strings.ToUpper(strings.TrimSpace(code))
+	issued, ok := index.issued[code]
+	if !ok {
+		return "", ErrLinkCodeNotFound
+	}
+	if index.used[code] {
+		return "", ErrLinkCodeUsed
+	}
+	if expiresAt, err := time.Parse(time.RFC3339, issued.ExpiresAt); err == nil && time.Now().After(expiresAt) {
+		return "", ErrLinkCodeExpired
+	}
+
+	err = appendIdentityLinkEvent(identityLinkEvent{
+		Kind:       linkEventLinked,
+		Code:       code,
+		ExternalID: issued.ExternalID,
+		UserID:     userID,
+		At:         time.Now().Format(time.RFC3339),
+	})
+	if err != nil {
+		return "", err
+	}
+	return issued.ExternalID, nil
+}
+
+// ExternalIDOf возвращает идентификатор пользователя во внешней HR-системе; пусто - пользователь не привязан
+func ExternalIDOf(userID int64) (string, error) {
+	identityLinksMutex.Lock()
+	defer identityLinksMutex.Unlock()
+
+	index, err := loadIdentityLinks()
+	if err != nil {
+		return "", err
+	}
+	return index.external[userID], nil
+}
+
+// appendIdentityLinkEvent дописывает запись в журнал identity_links.jsonl директории результатов.
+// Вызывается под identityLinksMutex.
+func appendIdentityLinkEvent(event identityLinkEvent) error {
+	resultsDir := paths.ResultsDir
+	if err := os.MkdirAll(resultsDir, 0755); err != nil {
+		return fmt.Errorf("ошибка создания директории %s: %w", resultsDir, err)
+	}
+
+	line, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("ошибка сериализации записи привязки: %w", err)
+	}
+
+	path := filepath.Join(resultsDir, identityLinksLogFile)
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("ошибка открытия файла %s: %w", path, err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("ошибка записи файла %s: %w", path, err)
+	}
+	return nil
+}
+
+// loadIdentityLinks дочитывает в индекс записи, добавленные в журнал после прошлого чтения.
+// Если журнал стал короче (удален или заменен), индекс строится заново. Вызывается под identityLinksMutex.
+func loadIdentityLinks() (*identityLinkIndex, error) {
+	index := &identityLinks
+	path := filepath.Join(paths.ResultsDir, identityLinksLogFile)
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		info, err = nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения файла %s: %w", path, err)
+	}
+	if index.issued == nil || index.path != path || info == nil || info.Size() < index.offset {
+		*index = identityLinkIndex{
+			path:     path,
+			issued:   make(map[string]identityLinkEvent),
+			used:     make(map[string]bool),
+			external: make(map[int64]string),
+		}
+	}
+	if info == nil || info.Size() == index.offset {
+		return index, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка открытия файла %s: %w", path, err)
+	}
+	defer file.Close()
+	if _, err := file.Seek(index.offset, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("ошибка чтения файла %s: %w", path, err)
+	}
+
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			// Оборванную последнюю строку дочитаем, когда запись завершится
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("ошибка чтения файла %s: %w", path, err)
+		}
+		index.offset += int64(len(line))
+		var event identityLinkEvent
+		if json.Unmarshal(line, &event) != nil {
+			continue
+		}
+		index.add(event)
+	}
+	return index, nil
+}
+
+// add учитывает запись журнала в индексе
+func (index *identityLinkIndex) add(event identityLinkEvent) {
+	switch event.Kind {
+	case linkEventIssued:
+		index.issued[event.Code] = event
+	case linkEventLinked:
+		index.used[event.Code] = true
+		index.external[event.UserID] = event.ExternalID
+	}
+}
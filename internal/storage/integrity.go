@@ -0,0 +1,212 @@
+package storage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// ChecksumSuffix - расширение файла с SHA-256 рядом с данными (формат sha256sum)
+	ChecksumSuffix = ".sha256"
+	// corruptSuffix - метка файлов, убранных в карантин при восстановлении
+	corruptSuffix = ".corrupt-"
+	tempPrefix    = ".tmp-"
+)
+
+// ErrCorrupted - файл поврежден: некорректный JSON или несовпадение контрольной суммы
+var ErrCorrupted = errors.New("файл поврежден")
+
+// IntegrityError описывает поврежденный файл и способ восстановления
+type IntegrityError struct {
+	Path   string
+	Reason string
+}
+
+func (e *IntegrityError) Error() string {
+	return fmt.Sprintf("файл %s поврежден: %s (проверка и восстановление: go run ./cmd/verify -repair)", e.Path, e.Reason)
+}
+
+func (e *IntegrityError) Unwrap() error { return ErrCorrupted }
+
+// WriteFileAtomic записывает файл через временный файл и rename, затем сохраняет
+// контрольную сумму. Сбой посреди записи оставляет прежнюю версию файла.
+// На время замены файл контрольной суммы содержит суммы и прежней, и новой версии,
+// поэтому сбой между rename и записью суммы не делает файл «поврежденным».
+func WriteFileAtomic(path string, data []byte) error {
+	sums := []string{checksum(data)}
+	if previous, err := os.ReadFile(path); err == nil {
+		sums = append(sums, checksum(previous))
+	}
+	if err := writeChecksums(path, sums); err != nil {
+		return err
+	}
+	if err := writeAtomic(path, data, false); err != nil {
+		return err
+	}
+	return writeChecksum(path, data)
+}
+
+// CreateFileAtomic как WriteFileAtomic, но не перезаписывает существующий файл
+// (возвращает ошибку os.ErrExist)
+func CreateFileAtomic(path string, data []byte) error {
+	if err := writeAtomic(path, data, true); err != nil {
+		return err
+	}
+	return writeChecksum(path, data)
+}
+
+// ReadFileVerified читает JSON файл и проверяет его целостность. Файлы без
+// контрольной суммы (сохраненные до ее появления) проверяются только на корректность JSON.
+func ReadFileVerified(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := verify(path, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// VerifyFile проверяет целостность файла
+func VerifyFile(path string) error {
+	_, err := ReadFileVerified(path)
+	return err
+}
+
+// HasChecksum сообщает, есть ли у файла сохраненная контрольная сумма
+func HasChecksum(path string) bool {
+	_, err := os.Stat(path + ChecksumSuffix)
+	return err == nil
+}
+
+// Reseal пересчитывает контрольную сумму файла с корректным JSON
+// (для файлов, сохраненных до появления контрольных сумм или исправленных вручную)
+func Reseal(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if !json.Valid(data) {
+		return &IntegrityError{Path: path, Reason: "некорректный JSON"}
+	}
+	return writeChecksum(path, data)
+}
+
+// Quarantine переименовывает поврежденный файл, чтобы он не мешал поиску по ID,
+// и возвращает новый путь
+func Quarantine(path string) (string, error) {
+	target := path + corruptSuffix + time.Now().Format("20060102-150405")
+	if err := os.Rename(path, target); err != nil {
+		return "", fmt.Errorf("ошибка перемещения %s в карантин: %w", path, err)
+	}
+	os.Remove(path + ChecksumSuffix)
+	return target, nil
+}
+
+// isAuxiliaryFile сообщает, является ли файл служебным (контрольная сумма, временный, карантин)
+func isAuxiliaryFile(path string) bool {
+	name := filepath.Base(path)
+	return strings.HasSuffix(name, ChecksumSuffix) ||
+		strings.HasPrefix(name, tempPrefix) ||
+		strings.Contains(name, corruptSuffix)
+}
+
+func verify(path string, data []byte) error {
+	if !json.Valid(data) {
+		return &IntegrityError{Path: path, Reason: "некорректный JSON"}
+	}
+	return verifyChecksum(path, data)
+}
+
+// verifyChecksum сверяет данные с сохраненной контрольной суммой (файлы без суммы не проверяются)
+func verifyChecksum(path string, data []byte) error {
+	stored, err := os.ReadFile(path + ChecksumSuffix)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("ошибка чтения контрольной суммы %s: %w", path, err)
+	}
+
+	// Несколько строк - файл заменялся (WriteFileAtomic): подходит сумма любой версии
+	actual := checksum(data)
+	for _, line := range strings.Split(strings.TrimSpace(string(stored)), "\n") {
+		expected, _, _ := strings.Cut(strings.TrimSpace(line), " ")
+		if strings.EqualFold(expected, actual) {
+			return nil
+		}
+	}
+	return &IntegrityError{Path: path, Reason: "контрольная сумма не совпадает"}
+}
+
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// writeChecksum сохраняет сумму в формате sha256sum, чтобы ее можно было проверить и без бота
+func writeChecksum(path string, data []byte) error {
+	return writeChecksums(path, []string{checksum(data)})
+}
+
+// writeChecksums сохраняет допустимые суммы файла, по строке на сумму
+func writeChecksums(path string, sums []string) error {
+	var lines strings.Builder
+	for _, sum := range sums {
+		lines.WriteString(fmt.Sprintf("%s  %s\n", sum, filepath.Base(path)))
+	}
+	return writeAtomic(path+ChecksumSuffix, []byte(lines.String()), false)
+}
+
+// writeAtomic пишет данные во временный файл в той же директории и переносит его на место
+func writeAtomic(path string, data []byte, exclusive bool) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("ошибка создания директории %s: %w", dir, err)
+	}
+
+	tmp, err := os.CreateTemp(dir, tempPrefix+"*")
+	if err != nil {
+		return fmt.Errorf("ошибка создания временного файла в %s: %w", dir, err)
+	}
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName)
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("ошибка записи файла %s: %w", path, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("ошибка записи файла %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("ошибка записи файла %s: %w", path, err)
+	}
+	if err := os.Chmod(tmpName, 0644); err != nil {
+		return fmt.Errorf("ошибка записи файла %s: %w", path, err)
+	}
+
+	if exclusive {
+		// Link в отличие от Rename не заменяет существующий файл
+		if err := os.Link(tmpName, path); err != nil {
+			if errors.Is(err, os.ErrExist) {
+				return fmt.Errorf("файл %s уже существует: %w", path, os.ErrExist)
+			}
+			return fmt.Errorf("ошибка сохранения файла %s: %w", path, err)
+		}
+		return nil
+	}
+	if err := os.Rename(tmpName, path); err != nil {
+		return fmt.Errorf("ошибка сохранения файла %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,239 @@
+package storage
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const invitationEventsLogFile = "invitation_events.jsonl"
+
+// Статусы выданного приглашения в порядке продвижения кандидата
+const (
+	InvitationIssued       = "issued"        // ссылка создана
+	InvitationSent         = "sent"          // ссылка отправлена кандидату ботом
+	InvitationStarted      = "started"       // кандидат начал интервью
+	InvitationCompleted    = "completed"     // интервью завершено
+	InvitationProfileReady = "profile_ready" // профиль извлечен
+)
+
+// InvitationStatuses - статусы приглашения по порядку
+var InvitationStatuses = []string{InvitationIssued, InvitationSent, InvitationStarted, InvitationCompleted, InvitationProfileReady}
+
+// invitationEventsMutex защищает журнал статусов приглашений от одновременной записи
+var invitationEventsMutex sync.Mutex
+
+// IssuedInvitation - приглашение из массовой рассылки и его текущий статус
+type IssuedInvitation struct {
+	Token          string `json:"token"`
+	Batch          string `json:"batch"`
+	Candidate      string `json:"candidate"`
+	TemplateID     string `json:"template_id"`
+	Link           string `json:"link"`
+	TelegramID     int64  `json:"telegram_id,omitempty"`
+	Status         string `json:"status"`
+	IssuedAt       string `json:"issued_at"`
+	SentAt         string `json:"sent_at,omitempty"`
+	StartedAt      string `json:"started_at,omitempty"`
+	CompletedAt    string `json:"completed_at,omitempty"`
+	ProfileReadyAt string `json:"profile_ready_at,omitempty"`
+	InterviewID    string `json:"interview_id,omitempty"`
+	UserID         int64  `json:"user_id,omitempty"`
+	// SendError - почему бот не смог отправить ссылку (ее нужно переслать вручную)
+	SendError string `json:"send_error,omitempty"`
+}
+
+// invitationEvent - запись журнала: выдача приглашения или смена его статуса
+type invitationEvent struct {
+	Token       string            `json:"token"`
+	Status      string            `json:"status"`
+	At          string            `json:"at"`
+	Issued      *IssuedInvitation `json:"issued,omitempty"`
+	InterviewID string            `json:"interview_id,omitempty"`
+	UserID      int64             `json:"user_id,omitempty"`
+	Error       string            `json:"error,omitempty"`
+}
+
+// RecordIssuedInvitations записывает в журнал invitation_events.jsonl выданные приглашения
+func RecordIssuedInvitations(invitations []IssuedInvitation) error {
+	events := make([]invitationEvent, 0, len(invitations))
+	for i := range invitations {
+		invitation := invitations[i]
+		invitation.Status = InvitationIssued
+		events = append(events, invitationEvent{
+			Token:  invitation.Token,
+			Status: InvitationIssued,
+			At:     invitation.IssuedAt,
+			Issued: &invitation,
+		})
+	}
+	return appendInvitationEvents(events)
+}
+
+// RecordInvitationSent отмечает отправку приглашения; непустой sendErr - отправить не удалось
+func RecordInvitationSent(token string, sendErr error) error {
+	event := invitationEvent{Token: token, Status: InvitationSent, At: time.Now().Format(time.RFC3339)}
+	if sendErr != nil {
+		event.Status = ""
+		event.Error = sendErr.Error()
+	}
+	return appendInvitationEvents([]invitationEvent{event})
+}
+
+// RecordInvitationStatus отмечает продвижение кандидата по приглашению. Приглашения,
+// выданные не массовой рассылкой, не отслеживаются и пропускаются без ошибки.
+func RecordInvitationStatus(token, status, interviewID string, userID int64) error {
+	invitationEventsMutex.Lock()
+	invitations, err := loadIssuedInvitations()
+	invitationEventsMutex.Unlock()
+	if err != nil {
+		return err
+	}
+	if _, ok := invitations[token]; !ok {
+		return nil
+	}
+
+	return appendInvitationEvents([]invitationEvent{{
+		Token:       token,
+		Status:      status,
+		At:          time.Now().Format(time.RFC3339),
+		InterviewID: interviewID,
+		UserID:      userID,
+	}})
+}
+
+// ListIssuedInvitations возвращает приглашения рассылки batch (пустой - всех рассылок) в порядке выдачи
+func ListIssuedInvitations(batch string) ([]IssuedInvitation, error) {
+	invitationEventsMutex.Lock()
+	invitations, err := loadIssuedInvitations()
+	invitationEventsMutex.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]IssuedInvitation, 0, len(invitations))
+	for _, invitation := range invitations {
+		if batch == "" || invitation.Batch == batch {
+			list = append(list, *invitation)
+		}
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].IssuedAt != list[j].IssuedAt {
+			return list[i].IssuedAt < list[j].IssuedAt
+		}
+		return list[i].Token < list[j].Token
+	})
+	return list, nil
+}
+
+// appendInvitationEvents дописывает события в журнал директории результатов
+func appendInvitationEvents(events []invitationEvent) error {
+	invitationEventsMutex.Lock()
+	defer invitationEventsMutex.Unlock()
+
+	resultsDir := paths.ResultsDir
+	if err := os.MkdirAll(resultsDir, 0755); err != nil {
+		return fmt.Errorf("ошибка создания директории %s: %w", resultsDir, err)
+	}
+
+	var data []byte
+	for _, event := range events {
+		line, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("ошибка сериализации записи приглашения: %w", err)
+		}
+		data = append(append(data, line...), '\n')
+	}
+
+	path := filepath.Join(resultsDir, invitationEventsLogFile)
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("ошибка открытия файла %s: %w", path, err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(data); err != nil {
+		return fmt.Errorf("ошибка записи файла %s: %w", path, err)
+	}
+	return nil
+}
+
+// loadIssuedInvitations восстанавливает текущие статусы приглашений по журналу.
+// Вызывается под invitationEventsMutex.
+func loadIssuedInvitations() (map[string]*IssuedInvitation, error) {
+	invitations := make(map[string]*IssuedInvitation)
+	path := filepath.Join(paths.ResultsDir, invitationEventsLogFile)
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return invitations, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка открытия файла %s: %w", path, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var event invitationEvent
+		// Оборванную последнюю строку пропускаем
+		if json.Unmarshal(scanner.Bytes(), &event) != nil {
+			continue
+		}
+		if event.Issued != nil {
+			issued := *event.Issued
+			invitations[event.Token] = &issued
+			continue
+		}
+		if invitation, ok := invitations[event.Token]; ok {
+			applyInvitationEvent(invitation, event)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения файла %s: %w", path, err)
+	}
+	return invitations, nil
+}
+
+// applyInvitationEvent продвигает статус приглашения; статус не откатывается назад,
+// например, если ссылку отправили уже после того, как кандидат по ней перешел
+func applyInvitationEvent(invitation *IssuedInvitation, event invitationEvent) {
+	if event.Error != "" {
+		invitation.SendError = event.Error
+		return
+	}
+
+	switch event.Status {
+	case InvitationSent:
+		invitation.SentAt = event.At
+		invitation.SendError = ""
+	case InvitationStarted:
+		invitation.StartedAt = event.At
+		invitation.InterviewID = event.InterviewID
+		invitation.UserID = event.UserID
+	case InvitationCompleted:
+		invitation.CompletedAt = event.At
+	case InvitationProfileReady:
+		invitation.ProfileReadyAt = event.At
+	default:
+		return
+	}
+	if invitationStatusRank(event.Status) > invitationStatusRank(invitation.Status) {
+		invitation.Status = event.Status
+	}
+}
+
+// invitationStatusRank - позиция статуса в InvitationStatuses
+func invitationStatusRank(status string) int {
+	for i, s := range InvitationStatuses {
+		if s == status {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const invitationsLogFile = "invitations.jsonl"
+
+// invitationsMutex защищает журнал приглашений от одновременной записи
+var invitationsMutex sync.Mutex
+
+// InvitationUse - запись журнала: какое приглашение породило какое интервью
+type InvitationUse struct {
+	Invitation
+	InterviewID string `json:"interview_id"`
+	TemplateID  string `json:"template_id"`
+	UserID      int64  `json:"user_id"`
+	StartedAt   string `json:"started_at"`
+}
+
+// RecordInvitationUse дописывает использование приглашения в журнал invitations.jsonl директории результатов
+func RecordInvitationUse(use InvitationUse) error {
+	invitationsMutex.Lock()
+	defer invitationsMutex.Unlock()
+
+	resultsDir := paths.ResultsDir
+	if err := os.MkdirAll(resultsDir, 0755); err != nil {
+		return fmt.Errorf("ошибка создания директории %s: %w", resultsDir, err)
+	}
+
+	line, err := json.Marshal(use)
+	if err != nil {
+		return fmt.Errorf("ошибка сериализации записи приглашения: %w", err)
+	}
+
+	path := filepath.Join(resultsDir, invitationsLogFile)
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("ошибка открытия файла %s: %w", path, err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("ошибка записи файла %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const memoryConsentsLogFile = "memory_consents.jsonl"
+
+// memoryConsentsMutex защищает журнал согласий на память интервьюера от одновременной записи
+var memoryConsentsMutex sync.Mutex
+
+// MemoryConsent - запись журнала: пользователь включил или выключил учет прошлых интервью
+type MemoryConsent struct {
+	UserID    int64  `json:"user_id"`
+	Enabled   bool   `json:"enabled"`
+	DecidedAt string `json:"decided_at"`
+}
+
+// RecordMemoryConsent дописывает решение пользователя в журнал memory_consents.jsonl директории результатов
+func RecordMemoryConsent(userID int64, enabled bool) error {
+	memoryConsentsMutex.Lock()
+	defer memoryConsentsMutex.Unlock()
+
+	resultsDir := paths.ResultsDir
+	if err := os.MkdirAll(resultsDir, 0755); err != nil {
+		return fmt.Errorf("ошибка создания директории %s: %w", resultsDir, err)
+	}
+
+	line, err := json.Marshal(MemoryConsent{UserID: userID, Enabled: enabled, DecidedAt: time.Now().Format(time.RFC3339)})
+	if err != nil {
+		return fmt.Errorf("ошибка сериализации согласия: %w", err)
+	}
+
+	path := filepath.Join(resultsDir, memoryConsentsLogFile)
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("ошибка открытия файла %s: %w", path, err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("ошибка записи файла %s: %w", path, err)
+	}
+	return nil
+}
+
+// MemoryConsentOf возвращает последнее решение пользователя о памяти интервьюера; nil - пользователь не решал
+func MemoryConsentOf(userID int64) (*MemoryConsent, error) {
+	memoryConsentsMutex.Lock()
+	defer memoryConsentsMutex.Unlock()
+
+	path := filepath.Join(paths.ResultsDir, memoryConsentsLogFile)
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка открытия файла %s: %w", path, err)
+	}
+	defer file.Close()
+
+	var latest *MemoryConsent
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var consent MemoryConsent
+		// Оборванную последнюю строку пропускаем
+		if json.Unmarshal(scanner.Bytes(), &consent) != nil || consent.UserID != userID {
+			continue
+		}
+		latest = &consent
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения файла %s: %w", path, err)
+	}
+	return latest, nil
+}
@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// outboxDir - директория недоставленных профилей внутри директории результатов
+const outboxDir = "outbox"
+
+// ProfileDelivery - запись outbox: профиль сохранен, но еще не отправлен пользователю.
+// Запись удаляется после отправки файла, поэтому сбой бота между сохранением
+// и отправкой приводит к повторной доставке, а не к потере профиля.
+type ProfileDelivery struct {
+	InterviewID string `json:"interview_id"`
+	Bot         string `json:"bot,omitempty"` // арендатор бота, который доставляет профиль; пусто - основной бот
+	ChatID      int64  `json:"chat_id"`
+	ThreadID    int    `json:"thread_id,omitempty"`
+	UserID      int64  `json:"user_id"`
+	FileName    string `json:"file_name"`
+	CreatedAt   string `json:"created_at"`
+	Attempts    int    `json:"attempts,omitempty"`
+	LastError   string `json:"last_error,omitempty"`
+}
+
+// deliveryPath возвращает путь записи outbox: <results>/outbox/<id>.json
+func deliveryPath(interviewID string) string {
+	return filepath.Join(paths.ResultsDir, outboxDir, interviewID+".json")
+}
+
+// SaveDelivery атомарно записывает (или обновляет) запись outbox интервью
+func SaveDelivery(delivery ProfileDelivery) error {
+	data, err := json.MarshalIndent(delivery, "", "  ")
+	if err != nil {
+		return fmt.Errorf("ошибка сериализации доставки: %w", err)
+	}
+	return WriteFileAtomic(deliveryPath(delivery.InterviewID), data)
+}
+
+// CompleteDelivery удаляет запись outbox после отправки профиля; отсутствие записи не ошибка
+func CompleteDelivery(interviewID string) error {
+	path := deliveryPath(interviewID)
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("ошибка удаления файла %s: %w", path, err)
+	}
+	if err := os.Remove(path + ChecksumSuffix); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("ошибка удаления контрольной суммы %s: %w", path, err)
+	}
+	return nil
+}
+
+// PendingDeliveries возвращает записи outbox от старых к новым.
+// Поврежденные записи пропускаются и возвращаются вместе с ошибкой.
+func PendingDeliveries() ([]ProfileDelivery, error) {
+	dir := filepath.Join(paths.ResultsDir, outboxDir)
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения директории %s: %w", dir, err)
+	}
+
+	var deliveries []ProfileDelivery
+	var errs []error
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		if entry.IsDir() || isAuxiliaryFile(path) || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		data, err := ReadFileVerified(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		var delivery ProfileDelivery
+		if err := json.Unmarshal(data, &delivery); err != nil {
+			errs = append(errs, fmt.Errorf("ошибка разбора %s: %w", path, err))
+			continue
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	sort.Slice(deliveries, func(i, j int) bool { return deliveries[i].CreatedAt < deliveries[j].CreatedAt })
+	return deliveries, errors.Join(errs...)
+}
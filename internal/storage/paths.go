@@ -0,0 +1,268 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Плейсхолдеры шаблонов имен файлов
+const (
+	placeholderID       = "{{id}}"
+	placeholderDate     = "{{date}}"
+	placeholderTemplate = "{{template}}"
+	placeholderTenant   = "{{tenant}}"
+)
+
+// placeholderPattern находит плейсхолдеры в шаблоне имени файла
+var placeholderPattern = regexp.MustCompile(`\{\{[^}]*\}\}`)
+
+// interviewIDPattern - допустимый ID интервью: без разделителей пути и символов шаблонов glob
+var interviewIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// Paths задает директории и шаблоны имен файлов результатов и профилей.
+// Шаблоны задаются относительно директории и могут содержать {{id}}, {{date}}, {{template}}
+// и {{tenant}}, например {{date}}/{{template}}/profile_{{id}}.json.
+type Paths struct {
+	ResultsDir  string
+	OutputDir   string
+	ResultFile  string
+	ProfileFile string
+	// TenantDirs - директории арендаторов по их ID. Если арендаторы заданы, а шаблон
+	// не содержит {{tenant}}, файлы каждого арендатора хранятся в своей поддиректории.
+	TenantDirs map[string]string
+
+	// legacyResultFile и legacyProfileFile - шаблоны до выноса файлов в директории арендаторов:
+	// по ним находятся результаты и профили, сохраненные раньше без арендаторов
+	legacyResultFile  string
+	legacyProfileFile string
+}
+
+// DefaultPaths возвращает прежнее расположение файлов: results/interview_<id>.json и output/profile_<id>.json
+func DefaultPaths() Paths {
+	return Paths{
+		ResultsDir:  "results",
+		OutputDir:   "output",
+		ResultFile:  "interview_{{id}}.json",
+		ProfileFile: "profile_{{id}}.json",
+	}
+}
+
+// paths - текущие пути хранилища, задаются через Configure при старте
+var paths = DefaultPaths()
+
+// Configure задает пути хранилища; пустые поля берутся из DefaultPaths
+func Configure(p Paths) error {
+	defaults := DefaultPaths()
+	if p.ResultsDir == "" {
+		p.ResultsDir = defaults.ResultsDir
+	}
+	if p.OutputDir == "" {
+		p.OutputDir = defaults.OutputDir
+	}
+	if p.ResultFile == "" {
+		p.ResultFile = defaults.ResultFile
+	}
+	if p.ProfileFile == "" {
+		p.ProfileFile = defaults.ProfileFile
+	}
+
+	if len(p.TenantDirs) > 0 {
+		if tenanted := withTenantDir(p.ResultFile); tenanted != p.ResultFile {
+			p.legacyResultFile, p.ResultFile = p.ResultFile, tenanted
+		}
+		if tenanted := withTenantDir(p.ProfileFile); tenanted != p.ProfileFile {
+			p.legacyProfileFile, p.ProfileFile = p.ProfileFile, tenanted
+		}
+	}
+
+	for _, tmpl := range []string{p.ResultFile, p.ProfileFile} {
+		if err := validateFileTemplate(tmpl); err != nil {
+			return err
+		}
+	}
+
+	paths = p
+	return nil
+}
+
+// ResultsDir возвращает директорию результатов интервью
+func ResultsDir() string {
+	return paths.ResultsDir
+}
+
+// OutputDir возвращает директорию профилей и производных данных
+func OutputDir() string {
+	return paths.OutputDir
+}
+
+// withTenantDir выносит файлы в директорию арендатора, если шаблон не задает ее сам
+func withTenantDir(tmpl string) string {
+	if strings.Contains(tmpl, placeholderTenant) {
+		return tmpl
+	}
+	return placeholderTenant + "/" + tmpl
+}
+
+// tenantDir возвращает директорию арендатора (default - интервью без арендатора)
+func tenantDir(tenantID string) string {
+	if dir, ok := paths.TenantDirs[tenantID]; ok {
+		return dir
+	}
+	if tenantID == "" {
+		return "default"
+	}
+	return tenantID
+}
+
+// validateFileTemplate проверяет, что шаблон содержит {{id}}, только известные плейсхолдеры
+// и не выходит за пределы своей директории
+func validateFileTemplate(tmpl string) error {
+	if !strings.Contains(tmpl, placeholderID) {
+		return fmt.Errorf("шаблон имени файла %q должен содержать %s", tmpl, placeholderID)
+	}
+	for _, placeholder := range placeholderPattern.FindAllString(tmpl, -1) {
+		switch placeholder {
+		case placeholderID, placeholderDate, placeholderTemplate, placeholderTenant:
+		default:
+			return fmt.Errorf("неизвестный плейсхолдер %s в шаблоне %q", placeholder, tmpl)
+		}
+	}
+	if filepath.IsAbs(tmpl) || strings.HasPrefix(filepath.Clean(tmpl), "..") {
+		return fmt.Errorf("шаблон имени файла %q должен быть относительным путем внутри директории", tmpl)
+	}
+	return nil
+}
+
+// renderFileTemplate подставляет значения в шаблон имени файла
+func renderFileTemplate(tmpl, id, templateID, tenantID string, date time.Time) string {
+	if templateID == "" {
+		templateID = "default"
+	}
+	return strings.NewReplacer(
+		placeholderID, id,
+		placeholderDate, date.Format("2006-01-02"),
+		placeholderTemplate, templateID,
+		placeholderTenant, tenantDir(tenantID),
+	).Replace(tmpl)
+}
+
+// ValidInterviewID сообщает, можно ли искать файлы интервью по этому ID
+func ValidInterviewID(id string) bool {
+	return interviewIDPattern.MatchString(id)
+}
+
+// findFile ищет файл по шаблону, когда дата, шаблон и арендатор интервью неизвестны.
+// ID проверяется: иначе «*» или «../» в нем нашли бы файлы чужих интервью.
+func findFile(dir, tmpl, id string) (string, error) {
+	if !ValidInterviewID(id) {
+		return "", fmt.Errorf("недопустимый ID интервью %q: %w", id, os.ErrNotExist)
+	}
+	pattern := strings.NewReplacer(
+		placeholderID, id,
+		placeholderDate, "*",
+		placeholderTemplate, "*",
+		placeholderTenant, "*",
+	).Replace(tmpl)
+
+	found, err := filepath.Glob(filepath.Join(dir, pattern))
+	if err != nil {
+		return "", err
+	}
+	var matches []string
+	for _, match := range found {
+		if !isAuxiliaryFile(match) {
+			matches = append(matches, match)
+		}
+	}
+	if len(matches) == 0 {
+		return "", os.ErrNotExist
+	}
+	// При нескольких совпадениях берем последнее по дате
+	sort.Strings(matches)
+	return matches[len(matches)-1], nil
+}
+
+// findFileWithLegacy ищет файл по текущему шаблону, а если его нет - по шаблону
+// до включения арендаторов (legacy пустой - такого шаблона нет)
+func findFileWithLegacy(dir, tmpl, legacy, id string) (string, error) {
+	path, err := findFile(dir, tmpl, id)
+	if legacy == "" || !errors.Is(err, os.ErrNotExist) {
+		return path, err
+	}
+	return findFile(dir, legacy, id)
+}
+
+// interviewDate возвращает дату начала интервью из RFC3339 метки (текущую дату, если метки нет)
+func interviewDate(timestamp string) time.Time {
+	if date, err := time.Parse(time.RFC3339, timestamp); err == nil {
+		return date
+	}
+	return time.Now()
+}
+
+// ResultPath возвращает путь к файлу результата интервью
+func ResultPath(result *InterviewResult) string {
+	name := renderFileTemplate(paths.ResultFile, result.InterviewID, result.TemplateID, result.Tenant, interviewDate(result.Timestamp))
+	return filepath.Join(paths.ResultsDir, name)
+}
+
+// FindResult находит файл результата интервью по ID
+func FindResult(interviewID string) (string, error) {
+	return findFileWithLegacy(paths.ResultsDir, paths.ResultFile, paths.legacyResultFile, interviewID)
+}
+
+// ProfilePath возвращает путь к ревизии профиля. Ревизия 1 - основной файл,
+// следующие получают суффикс _v<N> перед расширением.
+func ProfilePath(interviewID, templateID, tenantID, timestamp string, revision int) string {
+	name := renderFileTemplate(revisionTemplate(paths.ProfileFile, revision), interviewID, templateID, tenantID, interviewDate(timestamp))
+	return filepath.Join(paths.OutputDir, name)
+}
+
+// FindProfile находит файл ревизии профиля по ID интервью
+func FindProfile(interviewID string, revision int) (string, error) {
+	legacy := ""
+	if paths.legacyProfileFile != "" {
+		legacy = revisionTemplate(paths.legacyProfileFile, revision)
+	}
+	return findFileWithLegacy(paths.OutputDir, revisionTemplate(paths.ProfileFile, revision), legacy, interviewID)
+}
+
+// revisionTemplate добавляет к шаблону профиля суффикс ревизии
+func revisionTemplate(tmpl string, revision int) string {
+	if revision <= 1 {
+		return tmpl
+	}
+	ext := filepath.Ext(tmpl)
+	return fmt.Sprintf("%s_v%d%s", strings.TrimSuffix(tmpl, ext), revision, ext)
+}
+
+// resultIDPattern строит регулярное выражение, извлекающее ID интервью из пути результата
+func resultIDPattern(tmpl string) *regexp.Regexp {
+	var pattern strings.Builder
+	pattern.WriteString("^")
+	rest := filepath.ToSlash(tmpl)
+	captured := false
+	for {
+		loc := placeholderPattern.FindStringIndex(rest)
+		if loc == nil {
+			pattern.WriteString(regexp.QuoteMeta(rest))
+			break
+		}
+		pattern.WriteString(regexp.QuoteMeta(rest[:loc[0]]))
+		if rest[loc[0]:loc[1]] == placeholderID && !captured {
+			pattern.WriteString("([^/]+)")
+			captured = true
+		} else {
+			pattern.WriteString("[^/]+")
+		}
+		rest = rest[loc[1]:]
+	}
+	pattern.WriteString("$")
+	return regexp.MustCompile(pattern.String())
+}
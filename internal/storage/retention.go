@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const retentionLogFile = "retention.jsonl"
+
+// Действия политики хранения над ответами интервью
+const (
+	RetentionDeleted  = "deleted"  // файл ответов удален
+	RetentionArchived = "archived" // файл ответов перенесен в архив
+)
+
+// retentionMutex защищает журнал политики хранения от одновременной записи
+var retentionMutex sync.Mutex
+
+// RetentionRecord - запись журнала: ответы какого интервью удалены или перенесены в архив
+type RetentionRecord struct {
+	InterviewID   string `json:"interview_id"`
+	Tenant        string `json:"tenant,omitempty"`
+	Action        string `json:"action"`
+	Archive       string `json:"archive,omitempty"`
+	InterviewDate string `json:"interview_date"`
+	HasProfile    bool   `json:"has_profile"`
+	PurgedAt      string `json:"purged_at"`
+}
+
+// RecordRetention дописывает удаленные и архивированные интервью в журнал retention.jsonl директории результатов
+func RecordRetention(records []RetentionRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+	retentionMutex.Lock()
+	defer retentionMutex.Unlock()
+
+	resultsDir := paths.ResultsDir
+	if err := os.MkdirAll(resultsDir, 0755); err != nil {
+		return fmt.Errorf("ошибка создания директории %s: %w", resultsDir, err)
+	}
+
+	var data []byte
+	for _, record := range records {
+		line, err := json.Marshal(record)
+		if err != nil {
+			return fmt.Errorf("ошибка сериализации записи журнала хранения: %w", err)
+		}
+		data = append(append(data, line...), '\n')
+	}
+
+	path := filepath.Join(resultsDir, retentionLogFile)
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("ошибка открытия файла %s: %w", path, err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(data); err != nil {
+		return fmt.Errorf("ошибка записи файла %s: %w", path, err)
+	}
+	return nil
+}
+
+// RemoveResult удаляет файл результата интервью вместе с контрольной суммой
+func RemoveResult(interviewID string) error {
+	path, err := FindResult(interviewID)
+	if err != nil {
+		return fmt.Errorf("результат интервью %s не найден: %w", interviewID, err)
+	}
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("ошибка удаления файла %s: %w", path, err)
+	}
+	if err := os.Remove(path + ChecksumSuffix); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("ошибка удаления контрольной суммы %s: %w", path, err)
+	}
+	return nil
+}
+
+// ReadArchive читает архив ответов и сверяет его контрольную сумму
+func ReadArchive(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyChecksum(path, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// TenantDir возвращает директорию арендатора в хранилище (default - интервью без арендатора)
+func TenantDir(tenantID string) string {
+	return tenantDir(tenantID)
+}
@@ -2,24 +2,17 @@ package storage
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"regexp"
 )
 
-const resultsDir = "results"
-
-// SaveResult сохраняет результат интервью в JSON файл
+// SaveResult атомарно сохраняет результат интервью в JSON файл с контрольной суммой
 func SaveResult(result *InterviewResult) error {
-	// Создаем директорию если её нет
-	err := os.MkdirAll(resultsDir, 0755)
-	if err != nil {
-		return fmt.Errorf("ошибка создания директории %s: %w", resultsDir, err)
-	}
-
-	// Формируем имя файла
-	filename := fmt.Sprintf("interview_%s.json", result.InterviewID)
-	filepath := filepath.Join(resultsDir, filename)
+	path := ResultPath(result)
 
 	// Сериализуем результат в JSON с отступами
 	jsonData, err := json.MarshalIndent(result, "", "  ")
@@ -27,24 +20,24 @@ func SaveResult(result *InterviewResult) error {
 		return fmt.Errorf("ошибка сериализации результата: %w", err)
 	}
 
-	// Записываем в файл
-	err = os.WriteFile(filepath, jsonData, 0644)
-	if err != nil {
-		return fmt.Errorf("ошибка записи файла %s: %w", filepath, err)
-	}
-
-	return nil
+	return WriteFileAtomic(path, jsonData)
 }
 
 // LoadResult загружает результат интервью из JSON файла
 func LoadResult(interviewID string) (*InterviewResult, error) {
-	filename := fmt.Sprintf("interview_%s.json", interviewID)
-	filepath := filepath.Join(resultsDir, filename)
+	path, err := FindResult(interviewID)
+	if err != nil {
+		return nil, fmt.Errorf("результат интервью %s не найден в %s: %w", interviewID, paths.ResultsDir, err)
+	}
 
-	// Читаем файл
-	data, err := os.ReadFile(filepath)
+	// Читаем файл с проверкой целостности
+	data, err := ReadFileVerified(path)
 	if err != nil {
-		return nil, fmt.Errorf("ошибка чтения файла %s: %w", filepath, err)
+		var integrityErr *IntegrityError
+		if errors.As(err, &integrityErr) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("ошибка чтения файла %s: %w", path, err)
 	}
 
 	// Десериализуем JSON
@@ -59,28 +52,70 @@ func LoadResult(interviewID string) (*InterviewResult, error) {
 
 // ListResults возвращает список всех сохраненных интервью
 func ListResults() ([]string, error) {
+	resultsDir := paths.ResultsDir
+
 	// Проверяем существование директории
 	if _, err := os.Stat(resultsDir); os.IsNotExist(err) {
 		return []string{}, nil
 	}
 
-	// Читаем содержимое директории
-	entries, err := os.ReadDir(resultsDir)
-	if err != nil {
-		return nil, fmt.Errorf("ошибка чтения директории %s: %w", resultsDir, err)
+	// Обходим директорию: шаблон имени может раскладывать результаты по поддиректориям
+	idPatterns := []*regexp.Regexp{resultIDPattern(paths.ResultFile)}
+	if paths.legacyResultFile != "" {
+		// Результаты, сохраненные до включения арендаторов, лежат вне их директорий
+		idPatterns = append(idPatterns, resultIDPattern(paths.legacyResultFile))
 	}
-
 	var results []string
-	for _, entry := range entries {
-		if !entry.IsDir() && filepath.Ext(entry.Name()) == ".json" {
-			// Извлекаем ID интервью из имени файла
-			name := entry.Name()
-			if len(name) > 10 && name[:10] == "interview_" {
-				interviewID := name[10 : len(name)-5] // убираем "interview_" и ".json"
-				results = append(results, interviewID)
+	seen := make(map[string]bool)
+	err := filepath.WalkDir(resultsDir, func(path string, entry fs.DirEntry, err error) error {
+		// Записи outbox называются по ID интервью и не должны приниматься за результаты
+		if err == nil && entry.IsDir() && path == filepath.Join(resultsDir, outboxDir) {
+			return filepath.SkipDir
+		}
+		if err != nil || entry.IsDir() || isAuxiliaryFile(path) {
+			return err
+		}
+		rel, err := filepath.Rel(resultsDir, path)
+		if err != nil {
+			return err
+		}
+		// Извлекаем ID интервью из пути файла
+		for _, idPattern := range idPatterns {
+			// Пересохраненное интервью может остаться и в прежнем месте
+			if match := idPattern.FindStringSubmatch(filepath.ToSlash(rel)); match != nil {
+				if !seen[match[1]] {
+					seen[match[1]] = true
+					results = append(results, match[1])
+				}
+				break
 			}
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения директории %s: %w", resultsDir, err)
 	}
 
 	return results, nil
 }
+
+// CheckWritable проверяет, что в директорию можно записать файл
+func CheckWritable(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("ошибка создания директории %s: %w", dir, err)
+	}
+
+	file, err := os.CreateTemp(dir, ".write_check_*")
+	if err != nil {
+		return fmt.Errorf("директория %s недоступна для записи: %w", dir, err)
+	}
+	name := file.Name()
+	file.Close()
+
+	return os.Remove(name)
+}
+
+// CheckResultsWritable проверяет, что директория результатов доступна для записи
+func CheckResultsWritable() error {
+	return CheckWritable(paths.ResultsDir)
+}
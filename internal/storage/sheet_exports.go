@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const sheetExportsLogFile = "sheet_exports.jsonl"
+
+// sheetExportsMutex защищает журнал выгрузок в таблицы от одновременной записи
+var sheetExportsMutex sync.Mutex
+
+// SheetExport - запись журнала: профиль интервью выгружен строкой в таблицу
+type SheetExport struct {
+	InterviewID   string `json:"interview_id"`
+	SpreadsheetID string `json:"spreadsheet_id"`
+	ExportedAt    string `json:"exported_at"`
+}
+
+// RecordSheetExports дописывает выгруженные интервью в журнал sheet_exports.jsonl директории результатов
+func RecordSheetExports(spreadsheetID string, interviewIDs []string) error {
+	sheetExportsMutex.Lock()
+	defer sheetExportsMutex.Unlock()
+
+	resultsDir := paths.ResultsDir
+	if err := os.MkdirAll(resultsDir, 0755); err != nil {
+		return fmt.Errorf("ошибка создания директории %s: %w", resultsDir, err)
+	}
+
+	exportedAt := time.Now().Format(time.RFC3339)
+	var data []byte
+	for _, id := range interviewIDs {
+		line, err := json.Marshal(SheetExport{InterviewID: id, SpreadsheetID: spreadsheetID, ExportedAt: exportedAt})
+		if err != nil {
+			return fmt.Errorf("ошибка сериализации выгрузки: %w", err)
+		}
+		data = append(append(data, line...), '\n')
+	}
+
+	path := filepath.Join(resultsDir, sheetExportsLogFile)
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("ошибка открытия файла %s: %w", path, err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(data); err != nil {
+		return fmt.Errorf("ошибка записи файла %s: %w", path, err)
+	}
+	return nil
+}
+
+// SheetExportedInterviews возвращает ID интервью, уже выгруженных в таблицу spreadsheetID
+func SheetExportedInterviews(spreadsheetID string) (map[string]bool, error) {
+	sheetExportsMutex.Lock()
+	defer sheetExportsMutex.Unlock()
+
+	exported := make(map[string]bool)
+	path := filepath.Join(paths.ResultsDir, sheetExportsLogFile)
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return exported, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка открытия файла %s: %w", path, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var export SheetExport
+		// Оборванную последнюю строку пропускаем
+		if json.Unmarshal(scanner.Bytes(), &export) != nil {
+			continue
+		}
+		if export.SpreadsheetID == spreadsheetID {
+			exported[export.InterviewID] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения файла %s: %w", path, err)
+	}
+	return exported, nil
+}
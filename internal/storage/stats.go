@@ -0,0 +1,141 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Stats - сводка по сохраненным интервью и профилям за все время
+type Stats struct {
+	CompletedInterviews int            `json:"completed_interviews"`
+	Profiles            int            `json:"profiles"`
+	AvgDurationSeconds  float64        `json:"avg_duration_seconds"`
+	ByTemplate          map[string]int `json:"by_template"`
+	// PartialInterviews - прерванные интервью, сохраненные по просьбе пользователя
+	PartialInterviews int `json:"partial_interviews"`
+	// Corrupted - поврежденные файлы результатов, пропущенные при подсчете
+	Corrupted int `json:"corrupted"`
+	// Variants - качество ответов по вариантам формулировок вопросов (ключ - VariantKey)
+	Variants map[string]VariantStats `json:"variants,omitempty"`
+	// ByTenant - завершенные интервью по арендаторам (только при заданных арендаторах)
+	ByTenant map[string]int `json:"by_tenant,omitempty"`
+}
+
+// CollectStats читает сохраненные результаты и считает завершенные интервью,
+// созданные профили и среднюю длительность интервью
+func CollectStats() (*Stats, error) {
+	ids, err := ListResults()
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &Stats{ByTemplate: make(map[string]int)}
+	var totalSeconds, timed int
+	variants := make(map[string]*variantTotals)
+	for _, id := range ids {
+		result, err := LoadResult(id)
+		if errors.Is(err, ErrCorrupted) {
+			stats.Corrupted++
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("ошибка чтения результата %s: %w", id, err)
+		}
+
+		if result.Partial {
+			stats.PartialInterviews++
+			continue
+		}
+		stats.CompletedInterviews++
+		templateID := result.TemplateID
+		if templateID == "" {
+			templateID = "default"
+		}
+		stats.ByTemplate[templateID]++
+		if result.Tenant != "" {
+			if stats.ByTenant == nil {
+				stats.ByTenant = make(map[string]int)
+			}
+			stats.ByTenant[result.Tenant]++
+		}
+
+		for _, block := range result.Blocks {
+			for _, qa := range block.QuestionsAndAnswers {
+				key := VariantKey(result.TemplateID, qa.Variant)
+				if key == "" {
+					continue
+				}
+				if variants[key] == nil {
+					variants[key] = &variantTotals{}
+				}
+				variants[key].add(qa)
+			}
+		}
+
+		if result.DurationSeconds > 0 {
+			totalSeconds += result.DurationSeconds
+			timed++
+		}
+		if _, err := FindProfile(id, 1); err == nil {
+			stats.Profiles++
+		}
+	}
+
+	if timed > 0 {
+		stats.AvgDurationSeconds = float64(totalSeconds) / float64(timed)
+	}
+	if len(variants) > 0 {
+		stats.Variants = make(map[string]VariantStats, len(variants))
+		for key, totals := range variants {
+			stats.Variants[key] = totals.stats()
+		}
+	}
+	return stats, nil
+}
+
+// VariantStats - качество ответов на вариант формулировки вопроса в завершенных интервью
+type VariantStats struct {
+	Answers           int     `json:"answers"`
+	AvgAnswerLength   float64 `json:"avg_answer_length"`
+	ClarificationRate float64 `json:"clarification_rate"`
+	EditRate          float64 `json:"edit_rate"`
+}
+
+// VariantKey - ключ варианта вопроса в статистике: <шаблон>/<вариант> (пустой для вопросов без вариантов)
+func VariantKey(templateID, variant string) string {
+	if variant == "" {
+		return ""
+	}
+	if templateID == "" {
+		templateID = "default"
+	}
+	return templateID + "/" + variant
+}
+
+// variantTotals - накопители для VariantStats
+type variantTotals struct {
+	answers, chars, clarifications, edits int
+}
+
+// add учитывает ответ на вариант вопроса
+func (t *variantTotals) add(qa QA) {
+	t.answers++
+	t.chars += len([]rune(qa.Answer))
+	if qa.Clarification != nil {
+		t.clarifications++
+	}
+	if qa.Edited {
+		t.edits++
+	}
+}
+
+// stats переводит накопленные значения в средние и доли
+func (t *variantTotals) stats() VariantStats {
+	n := float64(t.answers)
+	return VariantStats{
+		Answers:           t.answers,
+		AvgAnswerLength:   float64(t.chars) / n,
+		ClarificationRate: float64(t.clarifications) / n,
+		EditRate:          float64(t.edits) / n,
+	}
+}
@@ -2,20 +2,161 @@ package storage
 
 // InterviewResult представляет результат всего интервью
 type InterviewResult struct {
-	InterviewID string        `json:"interview_id"`
-	Timestamp   string        `json:"timestamp"`
-	Blocks      []BlockResult `json:"blocks"`
+	InterviewID   string        `json:"interview_id"`
+	Timestamp     string        `json:"timestamp"`
+	Blocks        []BlockResult `json:"blocks"`
+	SkippedBlocks []int         `json:"skipped_blocks,omitempty"`
+	TemplateID    string        `json:"template_id,omitempty"`
+	// UserID - пользователь Telegram, прошедший интервью (0 - результаты до появления поля и CLI)
+	UserID int64 `json:"user_id,omitempty"`
+	// Language - язык, на котором задавались вопросы шаблона (ID блоков от языка не зависят)
+	Language string `json:"language,omitempty"`
+	// Persona - персона интервьюера, задававшего вопросы (пусто - базовая роль)
+	Persona string `json:"persona,omitempty"`
+	// TemplateRevision - ревизия шаблона (контрольная сумма его файлов), по которой проведено интервью
+	TemplateRevision string `json:"template_revision,omitempty"`
+	// Tenant - арендатор, в боте которого проведено интервью (пусто - без арендатора)
+	Tenant string `json:"tenant,omitempty"`
+	// MemoryFrom - прошлое интервью пользователя, профиль которого учитывался в вопросах (память интервьюера)
+	MemoryFrom string      `json:"memory_from,omitempty"`
+	Invitation *Invitation `json:"invitation,omitempty"`
+	Consent    *Consent    `json:"consent,omitempty"`
+	// Channel - через что проведено интервью (ChannelAPI); пусто - Telegram или CLI
+	Channel string `json:"channel,omitempty"`
+	// CompletedAt и DurationSeconds заполняются по завершении интервью
+	CompletedAt     string `json:"completed_at,omitempty"`
+	DurationSeconds int    `json:"duration_seconds,omitempty"`
+	// Partial - интервью прервано (/stop или неактивность), сохранены только пройденные блоки
+	Partial bool `json:"partial,omitempty"`
+	// TotalBlocks - число блоков шаблона, нужно для доли пройденного в прерванном интервью
+	TotalBlocks int `json:"total_blocks,omitempty"`
+	// DeclinedBlocks - необязательные блоки, от которых пользователь отказался (они есть и в SkippedBlocks)
+	DeclinedBlocks []int `json:"declined_blocks,omitempty"`
+	// ResumeFacts - факты из резюме, загруженного перед интервью (поле профиля - значение);
+	// ими заполняются поля профиля, которые не раскрыты в ответах
+	ResumeFacts map[string]interface{} `json:"resume_facts,omitempty"`
+}
+
+// BlocksCompletion возвращает долю пройденных блоков (1 для завершенного интервью).
+// Пропущенные по условию и отклоненные пользователем блоки не учитываются.
+func (r *InterviewResult) BlocksCompletion() float64 {
+	planned := r.TotalBlocks - len(r.SkippedBlocks)
+	if !r.Partial || planned <= 0 {
+		return 1
+	}
+	return min(float64(len(r.Blocks))/float64(planned), 1)
+}
+
+// Invitation описывает приглашение, по которому было начато интервью
+type Invitation struct {
+	Token     string `json:"token"`
+	Source    string `json:"source"`
+	Candidate string `json:"candidate,omitempty"`
+}
+
+// Consent - согласие на обработку данных: версия текста согласия и время принятия
+type Consent struct {
+	Version    string `json:"version"`
+	AcceptedAt string `json:"accepted_at"`
 }
 
 // BlockResult представляет результат одного блока
 type BlockResult struct {
-	BlockID             int    `json:"block_id"`
-	BlockName           string `json:"block_name"`
-	QuestionsAndAnswers []QA   `json:"questions_and_answers"`
+	BlockID             int           `json:"block_id"`
+	BlockName           string        `json:"block_name"`
+	QuestionsAndAnswers []QA          `json:"questions_and_answers"`
+	Summary             *BlockSummary `json:"summary,omitempty"`
+	StartedAt           string        `json:"started_at,omitempty"`
+	FinishedAt          string        `json:"finished_at,omitempty"`
+	DurationSeconds     int           `json:"duration_seconds,omitempty"`
+	TimeLimitExceeded   bool          `json:"time_limit_exceeded,omitempty"`
+	// Sentiment - тональность и эмоции ответов блока (nil для результатов до появления разметки)
+	Sentiment *BlockSentiment `json:"sentiment,omitempty"`
+}
+
+// Tone - тональность текста: метка, оценка и интенсивность эмоций
+type Tone struct {
+	Label     string   `json:"label"`     // positive, negative, neutral или mixed
+	Score     float64  `json:"score"`     // от -1 (негатив) до 1 (позитив)
+	Intensity float64  `json:"intensity"` // от 0 (нейтрально) до 1 (очень эмоционально)
+	Emotions  []string `json:"emotions,omitempty"`
+}
+
+// AnswerTone - тональность ответа блока по номеру вопроса (с 1)
+type AnswerTone struct {
+	Question int `json:"question"`
+	Tone
+}
+
+// BlockSentiment - тональность блока в целом и по каждому ответу
+type BlockSentiment struct {
+	Tone
+	Answers []AnswerTone `json:"answers,omitempty"`
+}
+
+// BlockSummary - саммари блока: свободный текст и разделы по summary_structure
+type BlockSummary struct {
+	Text   string              `json:"text"`
+	Fields map[string][]string `json:"fields,omitempty"`
+	Model  string              `json:"model,omitempty"`
 }
 
 // QA представляет один вопрос и ответ
 type QA struct {
-	Question string `json:"question"`
-	Answer   string `json:"answer"`
+	Question       string `json:"question"`
+	Answer         string `json:"answer"`
+	Edited         bool   `json:"edited,omitempty"`
+	OriginalAnswer string `json:"original_answer,omitempty"`
+	AskedAt        string `json:"asked_at,omitempty"`
+	AnsweredAt     string `json:"answered_at,omitempty"`
+	// GeneratedBy - модель, сгенерировавшая вопрос (пусто для вопросов из конфигурации)
+	GeneratedBy string `json:"generated_by,omitempty"`
+	// Variant - ID заданного варианта формулировки (b2.q1.v2) для вопросов с A/B вариантами
+	Variant string `json:"variant,omitempty"`
+	// Options - варианты ответа закрытого вопроса; ответ - один из них (или свой текст при allow_other)
+	Options []string `json:"options,omitempty"`
+	// Clarification - уточняющий вопрос к слишком краткому ответу; его ответ дописывается в Answer
+	Clarification *Clarification `json:"clarification,omitempty"`
+	// DepthScore - информативность ответа от 0 до 1 (длина, конкретика, рефлексия)
+	DepthScore float64 `json:"depth_score,omitempty"`
+	// DepthRating - оценка глубины ответа моделью от 1 до 5 (при check_answer_quality)
+	DepthRating int `json:"depth_rating,omitempty"`
+	// Skipped - пользователь пропустил вопрос (/skip), Answer содержит отметку о пропуске
+	Skipped bool `json:"skipped,omitempty"`
+	// Moderation - категории модерации, по которым отмечен ответ (self-harm, violence...)
+	Moderation []string `json:"moderation,omitempty"`
+	// AnswerType - ожидаемый тип ответа (number, date, list) из answer_type вопроса
+	AnswerType string `json:"answer_type,omitempty"`
+	// Normalized - ответ, приведенный к AnswerType: 25, 2021-03-15, «Go; Python»
+	Normalized string `json:"normalized,omitempty"`
+	// Source - откуда взят вопрос (QuestionSource*); пусто в результатах до появления разметки
+	Source string `json:"question_source,omitempty"`
+	// QuestionMessageID и AnswerMessageID - ID сообщений фронтенда с вопросом и ответом (0 - неизвестен)
+	QuestionMessageID int `json:"question_message_id,omitempty"`
+	AnswerMessageID   int `json:"answer_message_id,omitempty"`
+}
+
+// ChannelAPI - интервью проведено через InterviewService (InterviewResult.Channel)
+const ChannelAPI = "api"
+
+// Источники вопросов (QA.Source, Clarification.Source)
+const (
+	QuestionSourceStatic        = "static"        // вопрос из шаблона интервью
+	QuestionSourceFollowup      = "followup"      // уточняющий вопрос модели или заготовленный взамен отклоненного
+	QuestionSourceClarification = "clarification" // уточнение слишком краткого ответа
+	QuestionSourceSupervisor    = "supervisor"    // вопрос, заданный супервизором
+	QuestionSourceResume        = "resume"        // вопрос шаблона, заданный как подтверждение факта из резюме
+)
+
+// Clarification - уточнение ответа (не больше одного на вопрос)
+type Clarification struct {
+	Question   string `json:"question"`
+	Reason     string `json:"reason"` // too_short или uninformative
+	Answer     string `json:"answer,omitempty"`
+	AskedAt    string `json:"asked_at,omitempty"`
+	AnsweredAt string `json:"answered_at,omitempty"`
+
+	Source            string `json:"question_source,omitempty"`
+	QuestionMessageID int    `json:"question_message_id,omitempty"`
+	AnswerMessageID   int    `json:"answer_message_id,omitempty"`
 }
@@ -0,0 +1,118 @@
+package storage
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const userInterviewsLogFile = "user_interviews.jsonl"
+
+// userInterviewsMutex защищает журнал интервью пользователей от одновременной записи
+var userInterviewsMutex sync.Mutex
+
+// UserInterview - запись журнала: интервью пользователя Telegram на момент сохранения результата
+type UserInterview struct {
+	InterviewID string `json:"interview_id"`
+	UserID      int64  `json:"user_id"`
+	TemplateID  string `json:"template_id,omitempty"`
+	Tenant      string `json:"tenant,omitempty"`
+	Timestamp   string `json:"timestamp"`
+	CompletedAt string `json:"completed_at,omitempty"`
+	Partial     bool   `json:"partial,omitempty"`
+	// Completion - доля пройденных блоков (1 для завершенного интервью)
+	Completion float64 `json:"completion"`
+	SavedAt    string  `json:"saved_at"`
+	// HasProfile заполняется при чтении (UserInterviews) и в журнал не пишется
+	HasProfile bool `json:"-"`
+}
+
+// RecordUserInterview дописывает сохраненный результат в журнал user_interviews.jsonl директории результатов.
+// Результат может сохраняться повторно - действует последняя запись; результат без пользователя не записывается.
+func RecordUserInterview(result *InterviewResult) error {
+	if result.UserID == 0 {
+		return nil
+	}
+	userInterviewsMutex.Lock()
+	defer userInterviewsMutex.Unlock()
+
+	line, err := json.Marshal(UserInterview{
+		InterviewID: result.InterviewID,
+		UserID:      result.UserID,
+		TemplateID:  result.TemplateID,
+		Tenant:      result.Tenant,
+		Timestamp:   result.Timestamp,
+		CompletedAt: result.CompletedAt,
+		Partial:     result.Partial,
+		Completion:  result.BlocksCompletion(),
+		SavedAt:     time.Now().Format(time.RFC3339),
+	})
+	if err != nil {
+		return fmt.Errorf("ошибка сериализации записи журнала интервью: %w", err)
+	}
+
+	path := filepath.Join(paths.ResultsDir, userInterviewsLogFile)
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("ошибка открытия файла %s: %w", path, err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("ошибка записи файла %s: %w", path, err)
+	}
+	return nil
+}
+
+// UserInterviews возвращает интервью пользователя, начиная с последнего. Интервью, ответы
+// и профиль которых удалены (политика хранения), не возвращаются.
+func UserInterviews(userID int64) ([]UserInterview, error) {
+	userInterviewsMutex.Lock()
+	defer userInterviewsMutex.Unlock()
+
+	path := filepath.Join(paths.ResultsDir, userInterviewsLogFile)
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка открытия файла %s: %w", path, err)
+	}
+	defer file.Close()
+
+	latest := make(map[string]UserInterview)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record UserInterview
+		// Оборванную последнюю строку пропускаем
+		if json.Unmarshal(scanner.Bytes(), &record) != nil {
+			continue
+		}
+		if record.UserID == userID {
+			latest[record.InterviewID] = record
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения файла %s: %w", path, err)
+	}
+
+	interviews := make([]UserInterview, 0, len(latest))
+	for _, record := range latest {
+		_, profileErr := FindProfile(record.InterviewID, 1)
+		record.HasProfile = profileErr == nil
+		if _, err := FindResult(record.InterviewID); err != nil && !record.HasProfile {
+			continue
+		}
+		interviews = append(interviews, record)
+	}
+	sort.Slice(interviews, func(i, j int) bool {
+		return interviews[i].Timestamp > interviews[j].Timestamp
+	})
+	return interviews, nil
+}
@@ -0,0 +1,88 @@
+package telegram
+
+import (
+	"fmt"
+	"interview-bot-complete/internal/storage"
+	"strings"
+)
+
+// Данные inline-кнопок прерванного интервью: partial:<extract|discard>:<id интервью>
+const (
+	partialCallbackPrefix = "partial:"
+	partialExtract        = "extract"
+	partialDiscard        = "discard"
+)
+
+// abandonInterview прерывает интервью по причине reason (storage.Dropoff*). Если пройден хотя бы
+// один блок, ответы откладываются в сессии и пользователю предлагается составить профиль по ним;
+// иначе они удаляются.
+func (h *Handler) abandonInterview(session *UserSession, reason, notice string) {
+	h.recordDropoff(session, reason)
+	result := h.engine.Abandon(&session.Session)
+	h.releaseInterviewSlot(session)
+	h.resetSession(session)
+
+	if result == nil || h.extractor == nil {
+		h.reply(session, notice)
+		return
+	}
+	session.AbandonedResult = result
+
+	keyboard := &InlineKeyboardMarkup{
+		InlineKeyboard: [][]InlineKeyboardButton{{
+			{Text: "🧠 Составить профиль", CallbackData: partialCallbackPrefix + partialExtract + ":" + result.InterviewID},
+			{Text: "🗑 Удалить ответы", CallbackData: partialCallbackPrefix + partialDiscard + ":" + result.InterviewID},
+		}},
+	}
+	text := fmt.Sprintf("%s\n\nВы успели пройти блоков: %d из %d. Можно составить профиль по уже данным ответам - "+
+		"он будет помечен как неполный. Иначе ответы будут удалены.",
+		notice, len(result.Blocks), result.TotalBlocks-len(result.SkippedBlocks))
+	if err := h.bot.SendReplyWithKeyboard(h.destination(session), text, keyboard); err != nil {
+		h.logger(session).Warn("Не удалось предложить частичный профиль", "error", err)
+	}
+}
+
+// handlePartialCallback обрабатывает решение пользователя по прерванному интервью
+func (h *Handler) handlePartialCallback(query *CallbackQuery, session *UserSession) {
+	parts := strings.SplitN(strings.TrimPrefix(query.Data, partialCallbackPrefix), ":", 2)
+	result := session.AbandonedResult
+	if len(parts) != 2 || result == nil || result.InterviewID != parts[1] {
+		h.bot.AnswerCallbackQuery(query.ID, "Эта кнопка больше не активна")
+		h.bot.RemoveInlineKeyboard(query.Message.Chat.ID, query.Message.MessageID)
+		return
+	}
+
+	if parts[0] != partialExtract {
+		session.AbandonedResult = nil
+		h.bot.RemoveInlineKeyboard(query.Message.Chat.ID, query.Message.MessageID)
+		h.bot.AnswerCallbackQuery(query.ID, "")
+		h.reply(session, "🗑 Ответы прерванного интервью удалены. Используйте /start для нового интервью.")
+		return
+	}
+
+	if session.isActive() || session.State == StateAwaitingConsent {
+		h.bot.AnswerCallbackQuery(query.ID, "Сначала завершите или остановите текущее интервью")
+		return
+	}
+
+	h.bot.RemoveInlineKeyboard(query.Message.Chat.ID, query.Message.MessageID)
+	h.bot.AnswerCallbackQuery(query.ID, "")
+	if err := storage.SaveResult(result); err != nil {
+		h.metrics.RecordError("storage", err.Error())
+		h.reply(session, "Ошибка сохранения результата интервью.")
+		return
+	}
+	if err := storage.RecordUserInterview(result); err != nil {
+		h.logger(session).Warn("Не удалось записать интервью в историю пользователя", "error", err)
+	}
+
+	// Прерванное интервью становится текущим: /getprofile, /ask и /summary работают с ним
+	session.AbandonedResult = nil
+	session.InterviewID = result.InterviewID
+	session.TemplateID = result.TemplateID
+	session.TemplateRevision = result.TemplateRevision
+	session.Result = result
+
+	h.reply(session, "🧠 Составляю профиль по пройденным блокам...")
+	h.startExtraction(session)
+}
@@ -0,0 +1,253 @@
+package telegram
+
+import (
+	"fmt"
+	"interview-bot-complete/internal/extractor"
+	"interview-bot-complete/internal/jsonrepair"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Ограничения отчета /profilediff, чтобы он поместился в одно сообщение
+const (
+	maxDiffChanges    = 40
+	maxDiffValueRunes = 120
+)
+
+// isAdmin проверяет, входит ли пользователь в список администраторов
+func (h *Handler) isAdmin(userID int64) bool {
+	return h.admins[userID]
+}
+
+// requireAdmin сообщает об отказе в доступе, если пользователь не администратор
+func (h *Handler) requireAdmin(session *UserSession) bool {
+	if h.isAdmin(session.UserID) {
+		return true
+	}
+	h.reply(session, "⛔ Команда доступна только администраторам.")
+	return false
+}
+
+// handleReextractCommand обрабатывает команду /reextract <interview_id> [model] [prompt_version]
+func (h *Handler) handleReextractCommand(args []string, session *UserSession) {
+	if !h.requireAdmin(session) {
+		return
+	}
+
+	if len(args) == 0 {
+		h.reply(session, "Использование: /reextract <interview_id> [model] [prompt_version]")
+		return
+	}
+
+	if h.extractor == nil {
+		h.reply(session, "❌ Сервис анализа профилей недоступен.")
+		return
+	}
+
+	opts := extractor.ExtractOptions{}
+	if len(args) > 1 && args[1] != "-" {
+		opts.Model = args[1]
+	}
+	if len(args) > 2 {
+		opts.PromptVersion = args[2]
+	}
+
+	interviewID := args[0]
+	h.replyf(session, "🔁 Повторное извлечение профиля `%s`...", interviewID)
+
+	h.goSafe(session, "reextract", func() {
+		if err := h.waitLLMBudget(session.UserID); err != nil {
+			h.reply(session, "❌ Сервис анализа перегружен, попробуйте позже.")
+			return
+		}
+
+		reextraction, err := h.extractor.ReextractProfile(interviewID, opts)
+		if err != nil {
+			h.reply(session, "❌ Ошибка повторного извлечения: "+err.Error())
+			return
+		}
+
+		h.indexProfile(interviewID, reextraction.Result.ProfileJSON)
+		h.indexForSearch(interviewID)
+		h.reply(session, formatReextractionReport(interviewID, reextraction))
+		if reextraction.Revision > 1 {
+			h.reply(session, fmt.Sprintf("Изменения полей: /profilediff %s", interviewID))
+		}
+		h.sendJSONProfile(session, reextraction.FileName, fmt.Sprintf("%s_v%d", interviewID, reextraction.Revision))
+	})
+}
+
+// formatReextractionReport формирует отчет о повторном извлечении с разницей в токенах и стоимости
+func formatReextractionReport(interviewID string, r *extractor.Reextraction) string {
+	var report strings.Builder
+
+	report.WriteString("✅ *Профиль извлечен повторно*\n\n")
+	report.WriteString(fmt.Sprintf("🆔 Интервью: `%s`\n", interviewID))
+	report.WriteString(fmt.Sprintf("📄 Ревизия: v%d\n", r.Revision))
+	report.WriteString(fmt.Sprintf("🤖 Модель: %s\n", r.Result.Model))
+	report.WriteString(fmt.Sprintf("🔢 Токены: %d, стоимость ≈ $%.4f\n", r.Result.Usage.TotalTokens, r.Cost))
+
+	if r.PreviousKnown {
+		report.WriteString(fmt.Sprintf("📊 Разница с предыдущей ревизией (%s): %+d токенов, %+.4f$",
+			r.PreviousModel, r.TokenDelta(), r.CostDelta()))
+	} else {
+		report.WriteString("📊 Разница с предыдущей ревизией: нет данных о токенах")
+	}
+
+	return report.String()
+}
+
+// handleProfileDiffCommand обрабатывает команду /profilediff <interview_id> [from] [to]:
+// без номеров сравниваются две последние ревизии
+func (h *Handler) handleProfileDiffCommand(args []string, session *UserSession) {
+	if !h.requireAdmin(session) {
+		return
+	}
+
+	if len(args) == 0 || len(args) > 3 {
+		h.reply(session, "Использование: /profilediff <interview_id> [from] [to]")
+		return
+	}
+
+	if h.extractor == nil {
+		h.reply(session, "❌ Сервис анализа профилей недоступен.")
+		return
+	}
+
+	var revisions [2]int
+	for i, arg := range args[1:] {
+		revision, err := strconv.Atoi(strings.TrimPrefix(strings.ToLower(arg), "v"))
+		if err != nil || revision <= 0 {
+			h.reply(session, "❌ Номер ревизии должен быть положительным числом, например 1 или v2.")
+			return
+		}
+		revisions[i] = revision
+	}
+
+	diff, err := h.extractor.DiffProfileRevisions(args[0], revisions[0], revisions[1])
+	if err != nil {
+		h.reply(session, "❌ "+err.Error())
+		return
+	}
+	h.reply(session, formatProfileDiff(diff))
+}
+
+// formatProfileDiff формирует отчет об изменениях; значения выводятся моноширинным блоком,
+// чтобы Markdown в ответах модели не ломал разметку
+func formatProfileDiff(diff *extractor.ProfileDiff) string {
+	var report strings.Builder
+
+	report.WriteString(fmt.Sprintf("🔀 *Профиль* `%s`: v%d → v%d\n", diff.InterviewID, diff.From, diff.To))
+	if diff.FromModel != "" || diff.ToModel != "" {
+		report.WriteString(fmt.Sprintf("🤖 Модель: %s → %s\n", valueOrDash(diff.FromModel), valueOrDash(diff.ToModel)))
+	}
+	if len(diff.Changes) == 0 {
+		report.WriteString("\nПоля профиля не изменились.")
+		return report.String()
+	}
+
+	var added, removed, changed int
+	for _, change := range diff.Changes {
+		switch change.Kind {
+		case extractor.FieldAdded:
+			added++
+		case extractor.FieldRemoved:
+			removed++
+		default:
+			changed++
+		}
+	}
+	report.WriteString(fmt.Sprintf("📊 Изменено: %d, добавлено: %d, удалено: %d\n\n```\n", changed, added, removed))
+
+	for i, change := range diff.Changes {
+		if i == maxDiffChanges {
+			report.WriteString(fmt.Sprintf("… и еще %d\n", len(diff.Changes)-maxDiffChanges))
+			break
+		}
+		switch change.Kind {
+		case extractor.FieldAdded:
+			report.WriteString(fmt.Sprintf("+ %s: %s\n", change.Path, diffValue(change.After)))
+		case extractor.FieldRemoved:
+			report.WriteString(fmt.Sprintf("- %s: %s\n", change.Path, diffValue(change.Before)))
+		default:
+			report.WriteString(fmt.Sprintf("~ %s: %s → %s\n", change.Path, diffValue(change.Before), diffValue(change.After)))
+		}
+	}
+	report.WriteString("```")
+	return report.String()
+}
+
+// diffValue укорачивает значение и убирает обратные кавычки, закрывающие блок кода
+func diffValue(value string) string {
+	value = strings.ReplaceAll(strings.ReplaceAll(value, "`", "'"), "\n", " ")
+	runes := []rune(value)
+	if len(runes) > maxDiffValueRunes {
+		return string(runes[:maxDiffValueRunes]) + "…"
+	}
+	return value
+}
+
+func valueOrDash(value string) string {
+	if value == "" {
+		return "—"
+	}
+	return value
+}
+
+// handleStatsCommand обрабатывает команду /stats: квоты, бюджет токенов и метрики с момента запуска
+func (h *Handler) handleStatsCommand(session *UserSession) {
+	if !h.isAdmin(session.UserID) {
+		if t := h.adminTenant(session.UserID); t != nil {
+			h.replyTenantStats(session, t)
+			return
+		}
+		h.requireAdmin(session)
+		return
+	}
+
+	var stats strings.Builder
+	stats.WriteString("📊 *Квоты и бюджет*\n\n")
+	stats.WriteString(fmt.Sprintf("🟢 Идет интервью: %d из %s\n", h.interviewSlots.Active(), limitOrUnlimited(h.interviewSlots.Limit())))
+	stats.WriteString(fmt.Sprintf("🔢 Токены OpenAI за сутки: %d из %s (сброс через %s)\n",
+		h.tokenBudget.Used(), limitOrUnlimited(h.tokenBudget.Limit()), formatWait(h.tokenBudget.ResetIn())))
+	stats.WriteString(fmt.Sprintf("👤 Интервью на пользователя в сутки: %s\n", limitOrUnlimited(h.quotas.InterviewsPerUserPerDay)))
+
+	snapshot := h.metrics.Snapshot()
+	stats.WriteString(fmt.Sprintf("⏸ Отложено запусков: бюджет %d, квота пользователя %d, лимит одновременных %d, обслуживание %d\n",
+		snapshot.DeferredStarts[deferredTokenBudget], snapshot.DeferredStarts[deferredUserQuota], snapshot.DeferredStarts[deferredActiveLimit],
+		snapshot.DeferredStarts[deferredMaintenance]))
+
+	if !snapshot.StartedAt.IsZero() {
+		stats.WriteString(fmt.Sprintf("\n📈 *С момента запуска* (%s)\n\n", formatWait(time.Since(snapshot.StartedAt))))
+		stats.WriteString(fmt.Sprintf("🎯 Интервью: начато %d, завершено %d\n", snapshot.InterviewsStarted, snapshot.InterviewsCompleted))
+		stats.WriteString(fmt.Sprintf("🧠 Профили: создано %d, ошибок %d\n", snapshot.ProfilesGenerated, snapshot.ProfilesFailed))
+		stats.WriteString(fmt.Sprintf("💰 Токены профилей: %d, стоимость ≈ $%.4f\n",
+			snapshot.PromptTokens+snapshot.CompletionTokens, snapshot.CostUSD))
+		stats.WriteString(fmt.Sprintf("🩹 JSON ответов модели: валидных %d, исправлено локально %d, моделью %d, не разобрано %d\n",
+			snapshot.JSONRepairs[jsonrepair.MethodStrict], snapshot.JSONRepairs[jsonrepair.MethodTolerant]+snapshot.JSONRepairs[jsonrepair.MethodBalanced],
+			snapshot.JSONRepairs[jsonrepair.MethodLLM], snapshot.JSONRepairs[jsonrepair.MethodFailed]))
+		updates := snapshot.Updates
+		shed := 0
+		for _, count := range updates.Shed {
+			shed += count
+		}
+		stats.WriteString(fmt.Sprintf("📨 Обновления: принято %d, ждут обработки %d (пик %d), ожидание ~%.1f с, отброшено %d\n",
+			updates.Queued, updates.QueueDepth, updates.PeakQueueDepth, updates.AvgWaitSeconds, shed))
+		for _, t := range h.tenants.All() {
+			counts := snapshot.Tenants[t.ID]
+			stats.WriteString(fmt.Sprintf("🏢 %s: начато %d, завершено %d, профилей %d\n",
+				t.Name, counts.InterviewsStarted, counts.InterviewsCompleted, counts.ProfilesGenerated))
+		}
+	}
+
+	h.reply(session, stats.String())
+}
+
+// limitOrUnlimited форматирует лимит; 0 - без ограничения
+func limitOrUnlimited(limit int) string {
+	if limit <= 0 {
+		return "∞"
+	}
+	return strconv.Itoa(limit)
+}
@@ -0,0 +1,96 @@
+package telegram
+
+import (
+	"fmt"
+	"interview-bot-complete/internal/storage"
+	"strings"
+	"time"
+)
+
+// askHistoryLimit - сколько последних вопросов о профиле передается модели для связности
+const askHistoryLimit = 5
+
+// handleAskCommand обрабатывает /ask [вопрос]: без аргументов включает режим вопросов о профиле
+func (h *Handler) handleAskCommand(args []string, session *UserSession) {
+	if !session.isCompleted() {
+		h.reply(session, "❌ Вопросы о профиле доступны после завершения интервью. Используйте /start для начала.")
+		return
+	}
+	if h.extractor == nil {
+		h.reply(session, "❌ Сервис анализа профилей недоступен.")
+		return
+	}
+	if _, err := h.extractor.GetLastProfileJSON(session.InterviewID); err != nil {
+		h.reply(session, "⏳ Профиль еще не готов. Дождитесь завершения анализа и попробуйте снова.")
+		return
+	}
+
+	if len(args) > 0 {
+		h.askProfileQuestion(strings.Join(args, " "), session)
+		return
+	}
+
+	session.State = StateAskingProfile
+	h.reply(session, `💬 *Режим вопросов о профиле*
+
+Спросите что угодно о своем профиле, например:
+• Какие профессии мне подходят?
+• Какие у меня сильные стороны?
+• Над чем мне стоит поработать?
+
+Используйте /stop, чтобы выйти из режима.`)
+}
+
+// askProfileQuestion отвечает на вопрос о профиле с учетом квоты пользователя
+func (h *Handler) askProfileQuestion(question string, session *UserSession) {
+	if err := h.validateUserInput(session, question, -1); err != nil {
+		h.reply(session, "❌ "+err.Error())
+		return
+	}
+
+	profileJSON, err := h.extractor.GetLastProfileJSON(session.InterviewID)
+	if err != nil {
+		h.reply(session, "❌ Профиль не найден. Возможно, файл был удален.")
+		return
+	}
+
+	allowed, remaining := h.askQuota.Use(session.UserID)
+	if !allowed {
+		h.replyf(session, "⏳ Лимит вопросов о профиле исчерпан. Новые вопросы будут доступны через %s.",
+			formatWait(h.askQuota.ResetIn(session.UserID)))
+		return
+	}
+
+	if err := h.waitLLMBudget(session.UserID); err != nil {
+		h.askQuota.Refund(session.UserID)
+		h.reply(session, "⏳ Сервис перегружен, попробуйте спросить чуть позже.")
+		return
+	}
+
+	answer, err := h.interviewer.AnswerProfileQuestion(profileJSON, session.AskHistory, question, h.configFor(session))
+	if err != nil {
+		h.askQuota.Refund(session.UserID)
+		h.replyError(session, err)
+		return
+	}
+
+	session.AskHistory = append(session.AskHistory, storage.QA{Question: question, Answer: answer})
+	if len(session.AskHistory) > askHistoryLimit {
+		session.AskHistory = session.AskHistory[len(session.AskHistory)-askHistoryLimit:]
+	}
+	session.LastActivity = time.Now()
+
+	if remaining >= 0 {
+		answer += fmt.Sprintf("\n\n_Осталось вопросов сегодня: %d_", remaining)
+	}
+	h.reply(session, answer)
+}
+
+// formatWait форматирует время ожидания в часах и минутах
+func formatWait(d time.Duration) string {
+	d = d.Round(time.Minute)
+	if d < time.Hour {
+		return fmt.Sprintf("%d мин", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%d ч %d мин", int(d.Hours()), int(d.Minutes())%60)
+}
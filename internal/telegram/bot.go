@@ -2,11 +2,15 @@ package telegram
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"mime/multipart"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 )
 
@@ -15,88 +19,137 @@ func New(token string) *Bot {
 	return &Bot{
 		token:   token,
 		baseURL: fmt.Sprintf("https://api.telegram.org/bot%s", token),
+		queue:   newSendQueue(),
+		polling: defaultPolling(),
+
+		transport: http.DefaultTransport,
+		dispatch:  defaultDispatch(),
 	}
 }
 
-// GetUpdates получает обновления от Telegram
-func (b *Bot) GetUpdates(offset int) ([]Update, error) {
-	url := fmt.Sprintf("%s/getUpdates?offset=%d&timeout=30", b.baseURL, offset)
+// SetTransport задает транспорт запросов к Bot API (прокси, корпоративные корневые сертификаты);
+// вызывается до запуска бота
+func (b *Bot) SetTransport(transport http.RoundTripper) {
+	b.transport = transport
+}
 
-	resp, err := http.Get(url)
-	if err != nil {
-		return nil, fmt.Errorf("ошибка запроса getUpdates: %w", err)
-	}
-	defer resp.Body.Close()
+// httpClient возвращает клиент Bot API с транспортом бота (timeout 0 - без ограничения)
+func (b *Bot) httpClient(timeout time.Duration) *http.Client {
+	return &http.Client{Timeout: timeout, Transport: b.transport}
+}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("ошибка чтения ответа: %w", err)
-	}
+// SendMessage отправляет сообщение пользователю
+func (b *Bot) SendMessage(chatID int64, text string) error {
+	return b.SendReply(Destination{ChatID: chatID}, text)
+}
 
-	var response GetUpdatesResponse
-	err = json.Unmarshal(body, &response)
-	if err != nil {
-		return nil, fmt.Errorf("ошибка парсинга JSON: %w", err)
-	}
+// SendReply отправляет сообщение в указанную тему чата, при необходимости ответом на сообщение
+func (b *Bot) SendReply(dest Destination, text string) error {
+	return b.SendReplyWithKeyboard(dest, text, nil)
+}
 
-	if !response.OK {
-		return nil, fmt.Errorf("Telegram API вернул ошибку")
+// SendReplyWithKeyboard отправляет сообщение с inline-кнопками (keyboard может быть nil)
+func (b *Bot) SendReplyWithKeyboard(dest Destination, text string, keyboard *InlineKeyboardMarkup) error {
+	if keyboard == nil {
+		return b.SendReplyWithMarkup(dest, text, nil)
 	}
+	return b.SendReplyWithMarkup(dest, text, keyboard)
+}
 
-	return response.Result, nil
+// SendReplyWithMarkup отправляет сообщение с клавиатурой ответа или убирает ее (ReplyKeyboardRemove)
+func (b *Bot) SendReplyWithMarkup(dest Destination, text string, markup interface{}) error {
+	_, err := b.SendMessageWithID(dest, text, markup)
+	return err
 }
 
-// SendMessage отправляет сообщение пользователю
-func (b *Bot) SendMessage(chatID int64, text string) error {
-	request := SendMessageRequest{
-		ChatID:    chatID,
-		Text:      text,
-		ParseMode: "Markdown",
+// SendMessageWithID отправляет сообщение в Markdown и возвращает его message_id;
+// markup - клавиатура сообщения (nil - без клавиатуры)
+func (b *Bot) SendMessageWithID(dest Destination, text string, markup interface{}) (int, error) {
+	request := newSendMessageRequest(dest, text, markup)
+	request.ParseMode = "Markdown"
+	return b.sendMessage(request)
+}
+
+// SendRichText отправляет сообщение, размеченное сущностями (спойлеры, сворачиваемые цитаты),
+// и возвращает его message_id; текст не экранируется
+func (b *Bot) SendRichText(dest Destination, rich *RichText, markup interface{}) (int, error) {
+	request := newSendMessageRequest(dest, rich.String(), markup)
+	request.Entities = rich.Entities()
+	return b.sendMessage(request)
+}
+
+func newSendMessageRequest(dest Destination, text string, markup interface{}) SendMessageRequest {
+	return SendMessageRequest{
+		ChatID:                   dest.ChatID,
+		MessageThreadID:          dest.MessageThreadID,
+		Text:                     text,
+		ReplyToMessageID:         dest.ReplyToMessageID,
+		AllowSendingWithoutReply: dest.ReplyToMessageID != 0,
+		ReplyMarkup:              markup,
 	}
+}
 
+// sendMessage выполняет sendMessage через очередь отправки чата и возвращает message_id
+func (b *Bot) sendMessage(request SendMessageRequest) (int, error) {
 	jsonData, err := json.Marshal(request)
 	if err != nil {
-		return fmt.Errorf("ошибка сериализации запроса: %w", err)
+		return 0, fmt.Errorf("ошибка сериализации запроса: %w", err)
 	}
 
 	url := fmt.Sprintf("%s/sendMessage", b.baseURL)
-	resp, err := http.Post(url, "application/json", bytes.NewBuffer(jsonData))
-	if err != nil {
-		return fmt.Errorf("ошибка отправки сообщения: %w", err)
-	}
-	defer resp.Body.Close()
+	var messageID int
+	err = b.queue.do(request.ChatID, func() error {
+		resp, err := b.httpClient(0).Post(url, "application/json", bytes.NewReader(jsonData))
+		if err != nil {
+			return fmt.Errorf("ошибка отправки сообщения: %w", err)
+		}
+		defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("ошибка чтения ответа: %w", err)
-	}
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("ошибка чтения ответа: %w", err)
+		}
+		messageID = sentMessageID(body)
+		return parseSendResponse(resp.StatusCode, body)
+	})
+	return messageID, err
+}
 
-	var response SendMessageResponse
-	err = json.Unmarshal(body, &response)
-	if err != nil {
-		return fmt.Errorf("ошибка парсинга ответа: %w", err)
-	}
+// SendDocument отправляет файл в чат с подписью
+func (b *Bot) SendDocument(chatID int64, fileData []byte, fileName string, caption string) error {
+	return b.SendDocumentTo(Destination{ChatID: chatID}, fileData, fileName, caption)
+}
 
-	if !response.OK {
-		return fmt.Errorf("Telegram API вернул ошибку при отправке сообщения")
-	}
+// SendDocumentTo отправляет файл в указанную тему чата
+func (b *Bot) SendDocumentTo(dest Destination, fileData []byte, fileName string, caption string) error {
+	return b.sendFile(dest, "sendDocument", "document", fileData, fileName, caption)
+}
 
-	return nil
+// SendPhotoTo отправляет изображение (PNG или JPEG) в указанную тему чата с подписью
+func (b *Bot) SendPhotoTo(dest Destination, imageData []byte, fileName string, caption string) error {
+	return b.sendFile(dest, "sendPhoto", "photo", imageData, fileName, caption)
 }
 
-// SendDocument отправляет файл в чат
-func (b *Bot) SendDocument(chatID int64, filePath string, fileData []byte, fileName string) error {
-	url := fmt.Sprintf("%s/sendDocument", b.baseURL)
+// sendFile отправляет файл методом Bot API method (multipart, файл в поле field)
+func (b *Bot) sendFile(dest Destination, method, field string, fileData []byte, fileName string, caption string) error {
+	url := fmt.Sprintf("%s/%s", b.baseURL, method)
 
 	// Создаем multipart form
 	var buf bytes.Buffer
 	writer := multipart.NewWriter(&buf)
 
-	// Добавляем chat_id
-	writer.WriteField("chat_id", fmt.Sprintf("%d", chatID))
+	// Добавляем chat_id и тему форума
+	writer.WriteField("chat_id", fmt.Sprintf("%d", dest.ChatID))
+	if dest.MessageThreadID != 0 {
+		writer.WriteField("message_thread_id", fmt.Sprintf("%d", dest.MessageThreadID))
+	}
+	if dest.ReplyToMessageID != 0 {
+		writer.WriteField("reply_to_message_id", fmt.Sprintf("%d", dest.ReplyToMessageID))
+		writer.WriteField("allow_sending_without_reply", "true")
+	}
 
 	// Добавляем файл
-	part, err := writer.CreateFormFile("document", fileName)
+	part, err := writer.CreateFormFile(field, fileName)
 	if err != nil {
 		return fmt.Errorf("ошибка создания form file: %w", err)
 	}
@@ -107,41 +160,140 @@ func (b *Bot) SendDocument(chatID int64, filePath string, fileData []byte, fileN
 	}
 
 	// Добавляем caption
-	writer.WriteField("caption", fmt.Sprintf("📄 Ваш профиль: %s", fileName))
+	if caption != "" {
+		writer.WriteField("caption", caption)
+	}
 
 	err = writer.Close()
 	if err != nil {
 		return fmt.Errorf("ошибка закрытия writer: %w", err)
 	}
 
-	// Отправляем запрос
-	req, err := http.NewRequest("POST", url, &buf)
-	if err != nil {
-		return fmt.Errorf("ошибка создания запроса: %w", err)
+	payload := buf.Bytes()
+	contentType := writer.FormDataContentType()
+	client := b.httpClient(30 * time.Second)
+	return b.queue.do(dest.ChatID, func() error {
+		// Отправляем запрос
+		req, err := http.NewRequest("POST", url, bytes.NewReader(payload))
+		if err != nil {
+			return fmt.Errorf("ошибка создания запроса: %w", err)
+		}
+		req.Header.Set("Content-Type", contentType)
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("ошибка отправки файла: %w", err)
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("ошибка чтения ответа: %w", err)
+		}
+		return parseSendResponse(resp.StatusCode, body)
+	})
+}
+
+// SetMyCommands устанавливает меню команд бота для области scope (nil - по умолчанию)
+// и языка languageCode (пустая строка - для всех языков)
+func (b *Bot) SetMyCommands(commands []BotCommand, scope *BotCommandScope, languageCode string) error {
+	request := SetMyCommandsRequest{
+		Commands:     commands,
+		Scope:        scope,
+		LanguageCode: languageCode,
 	}
 
-	req.Header.Set("Content-Type", writer.FormDataContentType())
+	jsonData, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("ошибка сериализации запроса: %w", err)
+	}
 
-	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Do(req)
+	url := fmt.Sprintf("%s/setMyCommands", b.baseURL)
+	resp, err := b.httpClient(0).Post(url, "application/json", bytes.NewBuffer(jsonData))
 	if err != nil {
-		return fmt.Errorf("ошибка отправки документа: %w", err)
+		return fmt.Errorf("ошибка запроса setMyCommands: %w", err)
 	}
 	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	var response struct {
+		OK          bool   `json:"ok"`
+		Description string `json:"description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return fmt.Errorf("ошибка парсинга ответа: %w", err)
+	}
+
+	if !response.OK {
+		return fmt.Errorf("Telegram API вернул ошибку при установке команд: %s", response.Description)
+	}
+
+	return nil
+}
+
+// AnswerCallbackQuery подтверждает нажатие inline-кнопки, text показывается всплывающим уведомлением
+func (b *Bot) AnswerCallbackQuery(callbackQueryID string, text string) error {
+	return b.callMethod("answerCallbackQuery", AnswerCallbackQueryRequest{
+		CallbackQueryID: callbackQueryID,
+		Text:            text,
+	})
+}
+
+// RemoveInlineKeyboard убирает inline-кнопки у отправленного сообщения
+func (b *Bot) RemoveInlineKeyboard(chatID int64, messageID int) error {
+	return b.callMethod("editMessageReplyMarkup", EditMessageReplyMarkupRequest{
+		ChatID:    chatID,
+		MessageID: messageID,
+	})
+}
+
+// EditMessageText заменяет текст отправленного сообщения (Markdown)
+func (b *Bot) EditMessageText(chatID int64, messageID int, text string) error {
+	return b.callMethod("editMessageText", EditMessageTextRequest{
+		ChatID:    chatID,
+		MessageID: messageID,
+		Text:      text,
+		ParseMode: "Markdown",
+	})
+}
+
+// SendInvoice отправляет счет на оплату (Telegram Payments или Stars при пустом ProviderToken)
+func (b *Bot) SendInvoice(request SendInvoiceRequest) error {
+	return b.callMethod("sendInvoice", request)
+}
+
+// AnswerPreCheckoutQuery подтверждает или отклоняет оплату; ответ нужен в течение 10 секунд
+func (b *Bot) AnswerPreCheckoutQuery(queryID string, ok bool, errorMessage string) error {
+	return b.callMethod("answerPreCheckoutQuery", AnswerPreCheckoutQueryRequest{
+		PreCheckoutQueryID: queryID,
+		OK:                 ok,
+		ErrorMessage:       errorMessage,
+	})
+}
+
+// callMethod выполняет метод Bot API с JSON телом и проверяет поле ok ответа
+func (b *Bot) callMethod(method string, request interface{}) error {
+	jsonData, err := json.Marshal(request)
 	if err != nil {
-		return fmt.Errorf("ошибка чтения ответа: %w", err)
+		return fmt.Errorf("ошибка сериализации запроса: %w", err)
 	}
 
-	var response SendMessageResponse
-	err = json.Unmarshal(body, &response)
+	url := fmt.Sprintf("%s/%s", b.baseURL, method)
+	resp, err := b.httpClient(0).Post(url, "application/json", bytes.NewBuffer(jsonData))
 	if err != nil {
+		return fmt.Errorf("ошибка запроса %s: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	var response struct {
+		OK          bool   `json:"ok"`
+		Description string `json:"description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
 		return fmt.Errorf("ошибка парсинга ответа: %w", err)
 	}
 
 	if !response.OK {
-		return fmt.Errorf("Telegram API вернул ошибку при отправке документа: %s", string(body))
+		return fmt.Errorf("Telegram API вернул ошибку (%s): %s", method, response.Description)
 	}
 
 	return nil
@@ -153,25 +305,100 @@ func (b *Bot) SendFormattedMessage(chatID int64, format string, args ...interfac
 	return b.SendMessage(chatID, text)
 }
 
-// StartPolling запускает polling для получения обновлений
-func (b *Bot) StartPolling(handler func(Update)) error {
-	offset := 0
+// GetMe проверяет токен бота и доступность Telegram API
+func (b *Bot) GetMe(ctx context.Context) (*User, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/getMe", b.baseURL), nil)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка создания запроса: %w", err)
+	}
 
-	for {
-		updates, err := b.GetUpdates(offset)
-		if err != nil {
-			fmt.Printf("Ошибка получения обновлений: %v\n", err)
-			time.Sleep(5 * time.Second)
-			continue
+	resp, err := b.httpClient(0).Do(req)
+	if err != nil {
+		// URL запроса содержит токен бота - не выносим его в текст ошибки
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
 		}
+		return nil, fmt.Errorf("ошибка запроса getMe: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var response struct {
+		OK          bool   `json:"ok"`
+		Result      *User  `json:"result"`
+		Description string `json:"description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("ошибка парсинга ответа: %w", err)
+	}
 
-		for _, update := range updates {
-			offset = update.UpdateID + 1
-			go handler(update)
+	if !response.OK || response.Result == nil {
+		return nil, fmt.Errorf("Telegram API вернул ошибку: %s", response.Description)
+	}
+
+	return response.Result, nil
+}
+
+// DownloadFile скачивает файл, отправленный боту, не больше maxSize байт
+func (b *Bot) DownloadFile(ctx context.Context, fileID string, maxSize int64) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/getFile?file_id=%s", b.baseURL, url.QueryEscape(fileID)), nil)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка создания запроса: %w", err)
+	}
+	resp, err := b.httpClient(0).Do(req)
+	if err != nil {
+		// URL запроса содержит токен бота - не выносим его в текст ошибки
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
 		}
+		return nil, fmt.Errorf("ошибка запроса getFile: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var response struct {
+		OK     bool `json:"ok"`
+		Result *struct {
+			FilePath string `json:"file_path"`
+			FileSize int64  `json:"file_size"`
+		} `json:"result"`
+		Description string `json:"description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("ошибка парсинга ответа: %w", err)
+	}
+	if !response.OK || response.Result == nil || response.Result.FilePath == "" {
+		return nil, fmt.Errorf("Telegram API вернул ошибку: %s", response.Description)
+	}
+	if response.Result.FileSize > maxSize {
+		return nil, fmt.Errorf("файл больше %d КБ", maxSize/1024)
+	}
 
-		if len(updates) == 0 {
-			time.Sleep(1 * time.Second)
+	// Файлы скачиваются с https://api.telegram.org/file/bot<token>/<file_path>
+	fileURL := strings.TrimSuffix(b.baseURL, "/bot"+b.token) + "/file/bot" + b.token + "/" + response.Result.FilePath
+	req, err = http.NewRequestWithContext(ctx, "GET", fileURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка создания запроса: %w", err)
+	}
+	fileResp, err := b.httpClient(0).Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
 		}
+		return nil, fmt.Errorf("ошибка скачивания файла: %w", err)
+	}
+	defer fileResp.Body.Close()
+	if fileResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ошибка скачивания файла: HTTP %d", fileResp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(fileResp.Body, maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения файла: %w", err)
+	}
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("файл больше %d КБ", maxSize/1024)
 	}
+	return data, nil
 }
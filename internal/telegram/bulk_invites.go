@@ -0,0 +1,292 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"interview-bot-complete/internal/invite"
+	"interview-bot-complete/internal/storage"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// maxBulkInviteFileSize - предельный размер загружаемого CSV
+	maxBulkInviteFileSize = 1 << 20
+	// maxBulkInvites - сколько приглашений можно выпустить за одну рассылку
+	maxBulkInvites = 1000
+	// maxBatchIDLength - предельная длина ID рассылки
+	maxBatchIDLength = 32
+)
+
+// batchIDPattern - допустимые символы ID рассылки
+var batchIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// bulkInviteUsage - подсказка по команде /bulkinvite
+const bulkInviteUsage = `Использование: отправьте CSV файл с подписью ` + "`/bulkinvite <template_id> [рассылка]`" + `
+или ту же команду и строки CSV со следующей строки сообщения.
+
+Колонки CSV: ` + "`candidate`" + ` - идентификатор кандидата (email, ФИО, ID в ATS), ` + "`telegram_id`" + ` - необязательный ID пользователя Telegram.
+Если ID указан, бот сам отправит ссылку кандидату (он должен был хотя бы раз написать боту). Все ссылки возвращаются CSV файлом для рассылки по почте.`
+
+// bulkCandidate - строка CSV с кандидатом
+type bulkCandidate struct {
+	Candidate  string
+	TelegramID int64
+}
+
+// handleBulkInviteCommand выпускает приглашения по списку кандидатов: /bulkinvite <template_id> [рассылка].
+// Кандидаты берутся из CSV файла document или из строк сообщения после команды.
+func (h *Handler) handleBulkInviteCommand(text string, document *Document, session *UserSession) {
+	if !h.requireAdmin(session) {
+		return
+	}
+
+	header, rows, _ := strings.Cut(text, "\n")
+	_, args := parseCommand(header)
+	if len(args) == 0 || (document == nil && strings.TrimSpace(rows) == "") {
+		h.replyf(session, "%s\nШаблоны: %s", bulkInviteUsage, strings.Join(h.templates.IDs(), ", "))
+		return
+	}
+	if !h.invites.SigningEnabled() {
+		h.reply(session, "❌ Для массовых приглашений нужен секрет подписи приглашений (INVITE_HMAC_SECRET).")
+		return
+	}
+
+	templateID := args[0]
+	if _, ok := h.templates.Get(templateID); !ok {
+		h.replyf(session, "❌ Шаблон %q не найден. Доступные: %s", templateID, strings.Join(h.templates.IDs(), ", "))
+		return
+	}
+	batch := time.Now().Format("20060102_150405")
+	if len(args) > 1 {
+		batch = args[1]
+	}
+	if len(batch) > maxBatchIDLength || !batchIDPattern.MatchString(batch) {
+		h.replyf(session, "❌ ID рассылки: до %d латинских букв, цифр, _ и -.", maxBatchIDLength)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	data := []byte(rows)
+	if document != nil {
+		var err error
+		if data, err = h.bot.DownloadFile(ctx, document.FileID, maxBulkInviteFileSize); err != nil {
+			h.reply(session, "❌ Не удалось получить файл: "+err.Error())
+			return
+		}
+	}
+	candidates, err := parseBulkCandidates(data)
+	if err != nil {
+		h.reply(session, "❌ Ошибка в CSV: "+err.Error())
+		return
+	}
+
+	me, err := h.bot.GetMe(ctx)
+	if err != nil {
+		h.reply(session, "❌ Не удалось получить имя бота: "+err.Error())
+		return
+	}
+
+	issuedAt := time.Now().Format(time.RFC3339)
+	invitations := make([]storage.IssuedInvitation, 0, len(candidates))
+	for _, candidate := range candidates {
+		token := uuid.New().String()[:8]
+		payload, err := h.invites.Sign(templateID, token)
+		if err != nil {
+			h.reply(session, "❌ "+err.Error())
+			return
+		}
+		invitations = append(invitations, storage.IssuedInvitation{
+			Token:      token,
+			Batch:      batch,
+			Candidate:  candidate.Candidate,
+			TemplateID: templateID,
+			Link:       invite.Link(me.Username, payload),
+			TelegramID: candidate.TelegramID,
+			Status:     storage.InvitationIssued,
+			IssuedAt:   issuedAt,
+		})
+	}
+	if err := storage.RecordIssuedInvitations(invitations); err != nil {
+		h.logger(session).Error("Ошибка сохранения приглашений", "batch", batch, "error", err)
+		h.reply(session, "❌ Не удалось сохранить приглашения: "+err.Error())
+		return
+	}
+
+	sent, failed := h.sendBulkInvitations(session, invitations)
+	h.logger(session).Info("Выпущены приглашения", "batch", batch, "template_id", templateID, "count", len(invitations), "sent", sent, "failed", failed)
+
+	summary := fmt.Sprintf("✉️ Рассылка `%s`: выпущено приглашений %d (шаблон `%s`), отправлено ботом %d", batch, len(invitations), templateID, sent)
+	if failed > 0 {
+		summary += fmt.Sprintf(", не удалось отправить %d", failed)
+	}
+	h.reply(session, summary+".\nСтатусы: `/invitations "+batch+"`")
+	h.sendInvitationsCSV(session, batch, invitations)
+}
+
+// sendBulkInvitations отправляет ссылки кандидатам с указанным telegram_id
+func (h *Handler) sendBulkInvitations(session *UserSession, invitations []storage.IssuedInvitation) (sent, failed int) {
+	for i := range invitations {
+		invitation := &invitations[i]
+		if invitation.TelegramID == 0 {
+			continue
+		}
+		err := h.bot.SendMessage(invitation.TelegramID, "👋 Вас приглашают пройти интервью: [начать интервью]("+invitation.Link+")")
+		if err != nil {
+			failed++
+			invitation.SendError = err.Error()
+		} else {
+			sent++
+			invitation.Status = storage.InvitationSent
+		}
+		if err := storage.RecordInvitationSent(invitation.Token, err); err != nil {
+			h.logger(session).Error("Ошибка записи статуса приглашения", "token", invitation.Token, "error", err)
+		}
+	}
+	return sent, failed
+}
+
+// handleInvitationsCommand показывает статусы массовых приглашений: /invitations [рассылка]
+func (h *Handler) handleInvitationsCommand(args []string, session *UserSession) {
+	if !h.requireAdmin(session) {
+		return
+	}
+
+	batch := ""
+	if len(args) > 0 {
+		batch = args[0]
+	}
+	invitations, err := storage.ListIssuedInvitations(batch)
+	if err != nil {
+		h.reply(session, "❌ "+err.Error())
+		return
+	}
+	if len(invitations) == 0 {
+		h.reply(session, "Массовых приглашений пока нет. Выпустить: /bulkinvite")
+		return
+	}
+
+	// Сводка по рассылкам в порядке выдачи
+	var batches []string
+	counts := make(map[string]map[string]int)
+	for _, invitation := range invitations {
+		if counts[invitation.Batch] == nil {
+			batches = append(batches, invitation.Batch)
+			counts[invitation.Batch] = make(map[string]int)
+		}
+		counts[invitation.Batch][invitation.Status]++
+	}
+
+	var text strings.Builder
+	text.WriteString("✉️ *Массовые приглашения*\n")
+	for _, id := range batches {
+		text.WriteString(fmt.Sprintf("\n`%s`:", id))
+		for _, status := range storage.InvitationStatuses {
+			text.WriteString(fmt.Sprintf(" %s %d", invitationStatusTitle(status), counts[id][status]))
+		}
+		text.WriteString("\n")
+	}
+	text.WriteString("\n🆕 выпущено, 📤 отправлено, ▶️ начато, ✅ завершено, 🧠 профиль готов\n")
+	if batch == "" {
+		text.WriteString("\nСписок кандидатов рассылки: /invitations <рассылка>")
+		h.reply(session, text.String())
+		return
+	}
+	h.reply(session, text.String())
+	h.sendInvitationsCSV(session, batch, invitations)
+}
+
+// sendInvitationsCSV отправляет приглашения рассылки CSV файлом: ссылки для почты и статусы
+func (h *Handler) sendInvitationsCSV(session *UserSession, batch string, invitations []storage.IssuedInvitation) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	writer.Write([]string{"candidate", "token", "link", "telegram_id", "status", "send_error", "interview_id", "started_at", "completed_at", "profile_ready_at"})
+	for _, invitation := range invitations {
+		telegramID := ""
+		if invitation.TelegramID != 0 {
+			telegramID = strconv.FormatInt(invitation.TelegramID, 10)
+		}
+		writer.Write([]string{invitation.Candidate, invitation.Token, invitation.Link, telegramID, invitation.Status,
+			invitation.SendError, invitation.InterviewID, invitation.StartedAt, invitation.CompletedAt, invitation.ProfileReadyAt})
+	}
+	writer.Flush()
+
+	if err := h.bot.SendDocumentTo(h.destination(session), buf.Bytes(), fmt.Sprintf("invitations_%s.csv", batch), "✉️ Приглашения "+batch); err != nil {
+		h.reply(session, "❌ Ошибка отправки файла: "+err.Error())
+	}
+}
+
+// trackInvitation отмечает продвижение кандидата по массовому приглашению, с которого начато интервью
+func (h *Handler) trackInvitation(session *UserSession, status string) {
+	if session.Result == nil || session.Result.Invitation == nil {
+		return
+	}
+	if err := storage.RecordInvitationStatus(session.Result.Invitation.Token, status, session.InterviewID, session.UserID); err != nil {
+		h.logger(session).Error("Ошибка записи статуса приглашения", "status", status, "error", err)
+	}
+}
+
+// parseBulkCandidates читает CSV: candidate[,telegram_id]; строка заголовка необязательна
+func parseBulkCandidates(data []byte) ([]bulkCandidate, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var candidates []bulkCandidate
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		candidate := strings.TrimSpace(record[0])
+		if candidate == "" || (line == 1 && strings.EqualFold(candidate, "candidate")) {
+			continue
+		}
+
+		entry := bulkCandidate{Candidate: candidate}
+		if len(record) > 1 && strings.TrimSpace(record[1]) != "" {
+			entry.TelegramID, err = strconv.ParseInt(strings.TrimSpace(record[1]), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("строка %d: telegram_id должен быть числом", line)
+			}
+		}
+		candidates = append(candidates, entry)
+	}
+
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("в файле нет кандидатов")
+	}
+	if len(candidates) > maxBulkInvites {
+		return nil, fmt.Errorf("кандидатов больше %d, разделите список на несколько рассылок", maxBulkInvites)
+	}
+	return candidates, nil
+}
+
+// invitationStatusTitle - короткое обозначение статуса приглашения
+func invitationStatusTitle(status string) string {
+	switch status {
+	case storage.InvitationIssued:
+		return "🆕"
+	case storage.InvitationSent:
+		return "📤"
+	case storage.InvitationStarted:
+		return "▶️"
+	case storage.InvitationCompleted:
+		return "✅"
+	case storage.InvitationProfileReady:
+		return "🧠"
+	default:
+		return status
+	}
+}
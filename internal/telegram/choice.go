@@ -0,0 +1,113 @@
+package telegram
+
+import (
+	"fmt"
+	"interview-bot-complete/internal/config"
+	"interview-bot-complete/internal/engine"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Данные inline-кнопок закрытого вопроса: choice:<id интервью>:<блок>:<номер вопроса>:<номер варианта>
+const choiceCallbackPrefix = "choice:"
+
+const (
+	// choiceButtonsPerRow - кнопок вариантов ответа в одном ряду
+	choiceButtonsPerRow = 2
+	// choiceWideOption - вариант длиннее стольких символов занимает ряд целиком
+	choiceWideOption = 20
+)
+
+// sendChoicePrompt отправляет закрытый вопрос с вариантами ответа кнопками под сообщением
+// или клавиатурой вместо поля ввода (keyboard: reply)
+func (h *Handler) sendChoicePrompt(session *UserSession, prompt *engine.Prompt, text string) (int, error) {
+	if prompt.AllowOther {
+		text += "\n\n_Выберите вариант или напишите свой ответ._"
+	} else {
+		text += "\n\n_Выберите один из вариантов._"
+	}
+
+	if prompt.Keyboard == config.KeyboardReply {
+		keyboard := &ReplyKeyboardMarkup{ResizeKeyboard: true, OneTimeKeyboard: true, Selective: session.IsGroup}
+		for _, row := range chunkOptions(prompt.Options) {
+			var buttons []KeyboardButton
+			for _, option := range row {
+				buttons = append(buttons, KeyboardButton{Text: option})
+			}
+			keyboard.Keyboard = append(keyboard.Keyboard, buttons)
+		}
+		session.ReplyKeyboard = true
+		return h.bot.SendMessageWithID(h.destination(session), text, keyboard)
+	}
+
+	keyboard := &InlineKeyboardMarkup{}
+	questionRef := fmt.Sprintf("%s%s:%d:%d", choiceCallbackPrefix, session.InterviewID, prompt.Block, prompt.Number)
+	index := 0
+	for _, row := range chunkOptions(prompt.Options) {
+		var buttons []InlineKeyboardButton
+		for _, option := range row {
+			index++
+			buttons = append(buttons, InlineKeyboardButton{Text: option, CallbackData: fmt.Sprintf("%s:%d", questionRef, index)})
+		}
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, buttons)
+	}
+	return h.bot.SendMessageWithID(h.destination(session), text, keyboard)
+}
+
+// sendOpenPrompt отправляет открытый вопрос; клавиатура вариантов предыдущего вопроса убирается
+func (h *Handler) sendOpenPrompt(session *UserSession, text string) (int, error) {
+	if !session.ReplyKeyboard {
+		return h.bot.SendMessageWithID(h.destination(session), text, nil)
+	}
+	session.ReplyKeyboard = false
+	return h.bot.SendMessageWithID(h.destination(session), text, &ReplyKeyboardRemove{RemoveKeyboard: true, Selective: session.IsGroup})
+}
+
+// chunkOptions раскладывает варианты по рядам; длинные варианты занимают ряд целиком
+func chunkOptions(options []string) [][]string {
+	var rows [][]string
+	var row []string
+	for _, option := range options {
+		if len([]rune(option)) > choiceWideOption {
+			if len(row) > 0 {
+				rows, row = append(rows, row), nil
+			}
+			rows = append(rows, []string{option})
+			continue
+		}
+		row = append(row, option)
+		if len(row) == choiceButtonsPerRow {
+			rows, row = append(rows, row), nil
+		}
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// handleChoiceCallback записывает вариант, выбранный inline-кнопкой, как ответ на текущий вопрос
+func (h *Handler) handleChoiceCallback(query *CallbackQuery, session *UserSession) {
+	parts := strings.Split(strings.TrimPrefix(query.Data, choiceCallbackPrefix), ":")
+	prompt := h.engine.Current(&session.Session)
+	if len(parts) != 4 || session.State != StateWaitingAnswer || prompt == nil || prompt.Kind == engine.PromptClarification ||
+		parts[0] != session.InterviewID || parts[1] != strconv.Itoa(prompt.Block) || parts[2] != strconv.Itoa(prompt.Number) {
+		h.bot.AnswerCallbackQuery(query.ID, "Эта кнопка больше не активна")
+		h.bot.RemoveInlineKeyboard(query.Message.Chat.ID, query.Message.MessageID)
+		return
+	}
+	index, err := strconv.Atoi(parts[3])
+	if err != nil || index < 1 || index > len(prompt.Options) {
+		h.bot.AnswerCallbackQuery(query.ID, "Эта кнопка больше не активна")
+		return
+	}
+
+	option := prompt.Options[index-1]
+	h.bot.RemoveInlineKeyboard(query.Message.Chat.ID, query.Message.MessageID)
+	h.bot.AnswerCallbackQuery(query.ID, "")
+	h.reply(session, "✅ "+markdownEscaper.Replace(option))
+
+	session.LastActivity = time.Now()
+	h.processUserAnswer(option, session)
+}
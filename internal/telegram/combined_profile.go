@@ -0,0 +1,79 @@
+package telegram
+
+import (
+	"fmt"
+	"interview-bot-complete/internal/apperr"
+	"interview-bot-complete/internal/storage"
+)
+
+// handleCombinedProfileCommand обрабатывает /getprofile combined: сводный профиль по всем интервью
+// пользователя в этом боте. Профиль объединяется заново, сохраняется и отправляется файлом.
+func (h *Handler) handleCombinedProfileCommand(session *UserSession) {
+	if h.extractor == nil {
+		h.reply(session, "❌ Сводный профиль в этом боте недоступен.")
+		return
+	}
+	path, count, err := h.mergeUserProfiles(session)
+	if err != nil {
+		h.logger(session).Warn("Не удалось объединить профили", "error", err)
+		h.reply(session, "❌ Не удалось составить сводный профиль, попробуйте позже.")
+		return
+	}
+	if count == 0 {
+		h.reply(session, "У вас пока нет готовых профилей. Используйте /start для начала интервью.")
+		return
+	}
+
+	data, err := storage.ReadFileVerified(path)
+	if err != nil {
+		h.replyError(session, apperr.Wrap(apperr.ErrStorage, err))
+		return
+	}
+	name := fmt.Sprintf("profile_combined_%d.json", session.UserID)
+	caption := fmt.Sprintf("📄 Сводный профиль по интервью: %d\nИстория изменений полей - в _history", count)
+	if err := h.bot.SendDocumentTo(h.destination(session), data, name, caption); err != nil {
+		h.replyError(session, err)
+	}
+}
+
+// mergeUserProfiles объединяет профили интервью пользователя и сохраняет сводный профиль.
+// Возвращает путь файла и число интервью с профилем; при 0 файл не создается.
+func (h *Handler) mergeUserProfiles(session *UserSession) (string, int, error) {
+	interviews, err := h.userHistory(session.UserID)
+	if err != nil {
+		return "", 0, err
+	}
+	count := 0
+	for _, interview := range interviews {
+		if interview.HasProfile {
+			count++
+		}
+	}
+	if count == 0 {
+		return "", 0, nil
+	}
+
+	data, err := h.extractor.MergeProfiles(session.UserID, interviews)
+	if err != nil {
+		return "", 0, err
+	}
+	tenantID := ""
+	if h.tenant != nil {
+		tenantID = h.tenant.ID
+	}
+	path, err := storage.SaveCombinedProfile(session.UserID, tenantID, data)
+	if err != nil {
+		return "", 0, err
+	}
+	return path, count, nil
+}
+
+// updateCombinedProfile обновляет сводный профиль пользователя после нового профиля интервью;
+// ошибка не мешает выдаче профиля интервью
+func (h *Handler) updateCombinedProfile(session *UserSession) {
+	if _, count, err := h.mergeUserProfiles(session); err != nil {
+		h.logger(session).Warn("Не удалось обновить сводный профиль", "error", err)
+	} else {
+		h.logger(session).Info("Сводный профиль обновлен", "interviews", count)
+	}
+}
@@ -0,0 +1,281 @@
+package telegram
+
+import (
+	"fmt"
+	"strings"
+)
+
+// menuLanguages - языки, для которых регистрируются описания команд (первый - язык по умолчанию)
+var menuLanguages = []string{"ru", "en"}
+
+// menuCommand описывает команду меню и состояния сессии, в которых она полезна
+type menuCommand struct {
+	Command      string
+	Descriptions map[string]string
+	States       []SessionState // пусто - команда показывается всегда
+	AdminOnly    bool
+	Premium      bool // показывается только при включенных платежах
+	Memory       bool // показывается только при включенной памяти интервьюера
+}
+
+// menuCommands - команды бота в порядке отображения в меню
+var menuCommands = []menuCommand{
+	{
+		Command:      "start",
+		Descriptions: map[string]string{"ru": "Начать новое интервью", "en": "Start a new interview"},
+		States:       []SessionState{StateIdle, StateAwaitingConsent, StateCompleted},
+	},
+	{
+		Command:      "status",
+		Descriptions: map[string]string{"ru": "Прогресс текущего интервью", "en": "Current interview progress"},
+		States:       []SessionState{StateInterview, StateWaitingAnswer, StateEditingAnswer, StateReviewingBlock, StateCompleted},
+	},
+	{
+		Command:      "edit",
+		Descriptions: map[string]string{"ru": "Исправить ответ в текущем блоке", "en": "Correct an answer in the current block"},
+		States:       []SessionState{StateWaitingAnswer, StateEditingAnswer, StateReviewingBlock},
+	},
+	{
+		Command:      "pause",
+		Descriptions: map[string]string{"ru": "Пауза без напоминаний", "en": "Pause without reminders"},
+		States:       []SessionState{StateWaitingAnswer},
+	},
+	{
+		Command:      "skip",
+		Descriptions: map[string]string{"ru": "Пропустить текущий вопрос", "en": "Skip the current question"},
+		States:       []SessionState{StateWaitingAnswer},
+	},
+	{
+		Command:      "persona",
+		Descriptions: map[string]string{"ru": "Выбрать стиль интервьюера", "en": "Choose the interviewer style"},
+		States:       []SessionState{StateIdle, StateAwaitingConsent, StateInterview, StateWaitingAnswer, StateCompleted},
+	},
+	{
+		Command:      "transcript",
+		Descriptions: map[string]string{"ru": "Стенограмма интервью файлом", "en": "Interview transcript as a file"},
+		States:       []SessionState{StateInterview, StateWaitingAnswer, StateEditingAnswer, StateReviewingBlock, StateCompleted},
+	},
+	{
+		Command:      "getprofile",
+		Descriptions: map[string]string{"ru": "JSON файл профиля", "en": "Profile JSON file"},
+		States:       []SessionState{StateCompleted, StateAskingProfile},
+	},
+	{
+		Command:      "getsummary",
+		Descriptions: map[string]string{"ru": "Краткое резюме профиля", "en": "Short profile summary"},
+		States:       []SessionState{StateCompleted, StateAskingProfile},
+	},
+	{
+		Command:      "summary",
+		Descriptions: map[string]string{"ru": "Резюме: bullets, narrative или table", "en": "Summary: bullets, narrative or table"},
+		States:       []SessionState{StateCompleted, StateAskingProfile},
+	},
+	{
+		Command:      "history",
+		Descriptions: map[string]string{"ru": "Прошлые интервью и профили", "en": "Past interviews and profiles"},
+		States:       []SessionState{StateIdle, StateCompleted, StateAskingProfile},
+	},
+	{
+		Command:      "memory",
+		Descriptions: map[string]string{"ru": "Учитывать прошлые интервью", "en": "Remember past interviews"},
+		States:       []SessionState{StateIdle, StateCompleted, StateAskingProfile},
+		Memory:       true,
+	},
+	{
+		Command:      "cv",
+		Descriptions: map[string]string{"ru": "Резюме для предзаполнения профиля", "en": "Resume to pre-fill the profile"},
+		States:       []SessionState{StateIdle, StateAwaitingConsent, StateCompleted},
+	},
+	{
+		Command:      "ask",
+		Descriptions: map[string]string{"ru": "Вопросы о своем профиле", "en": "Ask about your profile"},
+		States:       []SessionState{StateCompleted, StateAskingProfile},
+	},
+	{
+		Command:      "premium",
+		Descriptions: map[string]string{"ru": "Расширенный анализ (платно)", "en": "Extended analysis (paid)"},
+		States:       []SessionState{StateIdle, StateCompleted, StateAskingProfile},
+		Premium:      true,
+	},
+	{
+		Command:      "restart",
+		Descriptions: map[string]string{"ru": "Перезапустить интервью", "en": "Restart the interview"},
+		States:       []SessionState{StateInterview, StateWaitingAnswer, StateEditingAnswer, StateReviewingBlock, StateCompleted},
+	},
+	{
+		Command:      "stop",
+		Descriptions: map[string]string{"ru": "Остановить интервью", "en": "Stop the interview"},
+		States:       []SessionState{StateAwaitingConsent, StateInterview, StateWaitingAnswer, StateEditingAnswer, StateReviewingBlock, StateAskingProfile},
+	},
+	{
+		Command:      "reextract",
+		Descriptions: map[string]string{"ru": "Повторно извлечь профиль", "en": "Re-extract a profile"},
+		AdminOnly:    true,
+	},
+	{
+		Command:      "profilediff",
+		Descriptions: map[string]string{"ru": "Изменения между ревизиями профиля", "en": "Changes between profile revisions"},
+		AdminOnly:    true,
+	},
+	{
+		Command:      "stats",
+		Descriptions: map[string]string{"ru": "Квоты, бюджет токенов и статистика", "en": "Quotas, token budget and stats"},
+		AdminOnly:    true,
+	},
+	{
+		Command:      "dumpsession",
+		Descriptions: map[string]string{"ru": "Снимок сессии пользователя файлом", "en": "Dump a user session to a file"},
+		AdminOnly:    true,
+	},
+	{
+		Command:      "loadsession",
+		Descriptions: map[string]string{"ru": "Восстановить сессию из снимка", "en": "Restore a session from a dump"},
+		AdminOnly:    true,
+	},
+	{
+		Command:      "takeover",
+		Descriptions: map[string]string{"ru": "Подключиться к интервью пользователя", "en": "Join a user's interview"},
+		AdminOnly:    true,
+	},
+	{
+		Command:      "release",
+		Descriptions: map[string]string{"ru": "Отключиться от интервью", "en": "Leave the joined interview"},
+		AdminOnly:    true,
+	},
+	{
+		Command:      "invite",
+		Descriptions: map[string]string{"ru": "Ссылка-приглашение на интервью", "en": "Interview invite link"},
+		AdminOnly:    true,
+	},
+	{
+		Command:      "bulkinvite",
+		Descriptions: map[string]string{"ru": "Приглашения по списку кандидатов (CSV)", "en": "Invite candidates from a CSV list"},
+		AdminOnly:    true,
+	},
+	{
+		Command:      "invitations",
+		Descriptions: map[string]string{"ru": "Статусы массовых приглашений", "en": "Bulk invitation statuses"},
+		AdminOnly:    true,
+	},
+	{
+		Command:      "similar",
+		Descriptions: map[string]string{"ru": "Похожие профили", "en": "Similar profiles"},
+		AdminOnly:    true,
+	},
+	{
+		Command:      "search",
+		Descriptions: map[string]string{"ru": "Поиск по архиву интервью", "en": "Search the interview archive"},
+		AdminOnly:    true,
+	},
+	{
+		Command:      "exportsheet",
+		Descriptions: map[string]string{"ru": "Выгрузить профили в Google Sheets", "en": "Export profiles to Google Sheets"},
+		AdminOnly:    true,
+	},
+	{
+		Command:      "researchexport",
+		Descriptions: map[string]string{"ru": "Обезличенная выгрузка для исследований", "en": "Pseudonymized research export"},
+		AdminOnly:    true,
+	},
+	{
+		Command:      "dropoff",
+		Descriptions: map[string]string{"ru": "На каких вопросах бросают интервью", "en": "Where users abandon interviews"},
+		AdminOnly:    true,
+	},
+	{
+		Command:      "maintenance",
+		Descriptions: map[string]string{"ru": "Режим обслуживания для деплоя", "en": "Maintenance mode for deploys"},
+		AdminOnly:    true,
+	},
+	{
+		Command:      "reloadconfig",
+		Descriptions: map[string]string{"ru": "Перечитать шаблоны интервью и схему профиля", "en": "Reload interview templates and profile schema"},
+		AdminOnly:    true,
+	},
+	{
+		Command:      "help",
+		Descriptions: map[string]string{"ru": "Справка по командам", "en": "Command help"},
+	},
+}
+
+// menuLanguage приводит language_code пользователя Telegram к одному из языков меню
+func menuLanguage(languageCode string) string {
+	code := strings.ToLower(languageCode)
+	for _, lang := range menuLanguages {
+		if strings.HasPrefix(code, lang) {
+			return lang
+		}
+	}
+	return menuLanguages[0]
+}
+
+// availableIn сообщает, показывается ли команда в указанном состоянии сессии
+func (c menuCommand) availableIn(state SessionState) bool {
+	if len(c.States) == 0 {
+		return true
+	}
+	// Пока профиль составляется и после этого меню то же, что у завершенного интервью
+	switch state {
+	case StateExtracting, StateProfileReady, StateExtractionFailed:
+		state = StateCompleted
+	}
+	for _, s := range c.States {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
+
+// buildCommandMenu собирает меню команд для состояния сессии.
+// Пустое состояние означает общее меню без фильтрации по состоянию.
+func buildCommandMenu(state SessionState, admin, premium, memory bool, lang string) []BotCommand {
+	var commands []BotCommand
+	for _, c := range menuCommands {
+		if (c.AdminOnly && !admin) || (c.Premium && !premium) || (c.Memory && !memory) {
+			continue
+		}
+		if state != "" && !c.availableIn(state) {
+			continue
+		}
+		commands = append(commands, BotCommand{Command: c.Command, Description: c.Descriptions[lang]})
+	}
+	return commands
+}
+
+// RegisterCommands регистрирует общее меню команд бота с описаниями на всех поддерживаемых языках
+func (h *Handler) RegisterCommands() error {
+	for i, lang := range menuLanguages {
+		languageCode := lang
+		if i == 0 {
+			languageCode = "" // язык по умолчанию для всех остальных пользователей
+		}
+		if err := h.bot.SetMyCommands(buildCommandMenu(StateIdle, false, h.premium.Enabled, h.memory, lang), nil, languageCode); err != nil {
+			return fmt.Errorf("меню команд (%s): %w", lang, err)
+		}
+	}
+	return nil
+}
+
+// syncCommandMenu обновляет меню команд пользователя под текущее состояние сессии.
+// В личном чате меню задается для чата, в группе - для конкретного участника.
+func (h *Handler) syncCommandMenu(session *UserSession) {
+	lang := menuLanguage(session.LanguageCode)
+	admin := h.isAdmin(session.UserID)
+
+	menuKey := fmt.Sprintf("%s/%s/%t", session.State, lang, admin)
+	if session.commandMenu == menuKey {
+		return
+	}
+
+	scope := &BotCommandScope{Type: "chat", ChatID: session.ChatID}
+	if session.IsGroup {
+		scope = &BotCommandScope{Type: "chat_member", ChatID: session.ChatID, UserID: session.UserID}
+	}
+
+	if err := h.bot.SetMyCommands(buildCommandMenu(session.State, admin, h.premium.Enabled, h.memory, lang), scope, ""); err != nil {
+		h.logger(session).Warn("Ошибка обновления меню команд", "error", err)
+		return
+	}
+	session.commandMenu = menuKey
+}
@@ -0,0 +1,144 @@
+package telegram
+
+import (
+	"fmt"
+	"interview-bot-complete/internal/invite"
+	"interview-bot-complete/internal/storage"
+	"strings"
+	"time"
+)
+
+// Данные inline-кнопок согласия: consent:<accept|decline>:<версия>
+const (
+	consentCallbackPrefix = "consent:"
+	consentAccept         = "accept"
+	consentDecline        = "decline"
+)
+
+// consentText - описание того, какие данные сохраняются и как они анализируются
+const consentText = `📝 *Согласие на обработку данных*
+
+Перед началом интервью, пожалуйста, ознакомьтесь с тем, как мы работаем с вашими данными.
+
+*Что сохраняется:*
+• Ваши ответы на вопросы интервью и время ответов
+• ID Telegram-чата и пользователя, язык интерфейса
+• Профиль, составленный по вашим ответам
+
+*Как данные анализируются:*
+• Ответы передаются языковой модели OpenAI для уточняющих вопросов, саммари блоков и составления профиля
+• Текст загруженного резюме передается языковой модели для предзаполнения профиля; сам файл не сохраняется
+• Профиль хранится на сервере и доступен вам по /getprofile
+
+Версия соглашения: %s
+
+Нажмите «Согласен», чтобы начать интервью. Без согласия интервью невозможно.`
+
+// hasConsent сообщает, дал ли пользователь согласие на текущую версию соглашения
+func (h *Handler) hasConsent(session *UserSession) bool {
+	return !h.consentRequired || (session.Consent != nil && session.Consent.Version == h.consentVersion)
+}
+
+// requestConsent - первая фаза: показывает соглашение с кнопками и откладывает старт интервью
+func (h *Handler) requestConsent(session *UserSession, invitation *invite.Invitation) {
+	session.State = StateAwaitingConsent
+	session.PendingInvitation = invitation
+	session.LastActivity = time.Now()
+
+	keyboard := &InlineKeyboardMarkup{
+		InlineKeyboard: [][]InlineKeyboardButton{{
+			{Text: "✅ Согласен", CallbackData: consentCallbackPrefix + consentAccept + ":" + h.consentVersion},
+			{Text: "❌ Отказаться", CallbackData: consentCallbackPrefix + consentDecline + ":" + h.consentVersion},
+		}},
+	}
+	if err := h.bot.SendReplyWithKeyboard(h.destination(session), fmt.Sprintf(consentText, h.consentVersion), keyboard); err != nil {
+		h.logger(session).Warn("Не удалось отправить соглашение", "error", err)
+	}
+}
+
+// handleCallbackQuery обрабатывает нажатия inline-кнопок
+func (h *Handler) handleCallbackQuery(query *CallbackQuery) {
+	if query.From == nil || query.Message == nil || query.Message.Chat == nil {
+		h.bot.AnswerCallbackQuery(query.ID, "")
+		return
+	}
+
+	isConsent := strings.HasPrefix(query.Data, consentCallbackPrefix)
+	isPartial := strings.HasPrefix(query.Data, partialCallbackPrefix)
+	isReview := strings.HasPrefix(query.Data, reviewCallbackPrefix)
+	isChoice := strings.HasPrefix(query.Data, choiceCallbackPrefix)
+	isNudge := strings.HasPrefix(query.Data, nudgeCallbackPrefix)
+	isHistory := strings.HasPrefix(query.Data, historyCallbackPrefix)
+	isResume := strings.HasPrefix(query.Data, resumeCallbackPrefix)
+	if !isConsent && !isPartial && !isReview && !isChoice && !isNudge && !isHistory && !isResume {
+		h.bot.AnswerCallbackQuery(query.ID, "")
+		return
+	}
+
+	session, unlock := h.lockSession(query.Message.Chat.ID, query.From.ID)
+	defer unlock()
+	session.LanguageCode = query.From.LanguageCode
+	defer h.persistSession(session)
+	defer h.syncCommandMenu(session)
+
+	if isPartial {
+		h.handlePartialCallback(query, session)
+		return
+	}
+	if isReview {
+		h.handleReviewCallback(query, session)
+		return
+	}
+	if isChoice {
+		h.handleChoiceCallback(query, session)
+		return
+	}
+	if isNudge {
+		h.handleNudgeCallback(query, session)
+		return
+	}
+	if isHistory {
+		h.handleHistoryCallback(query, session)
+		return
+	}
+	if isResume {
+		h.handleResumeCallback(query, session)
+		return
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(query.Data, consentCallbackPrefix), ":", 2)
+	if session.State != StateAwaitingConsent || session.ThreadID != query.Message.MessageThreadID {
+		h.bot.AnswerCallbackQuery(query.ID, "Эта кнопка больше не активна")
+		return
+	}
+	if len(parts) != 2 || parts[1] != h.consentVersion {
+		// Соглашение обновилось, пока сообщение ждало ответа
+		h.bot.AnswerCallbackQuery(query.ID, "Соглашение обновлено")
+		h.bot.RemoveInlineKeyboard(query.Message.Chat.ID, query.Message.MessageID)
+		h.requestConsent(session, session.PendingInvitation)
+		return
+	}
+
+	h.bot.RemoveInlineKeyboard(query.Message.Chat.ID, query.Message.MessageID)
+	h.handleConsentDecision(session, parts[0] == consentAccept)
+	h.bot.AnswerCallbackQuery(query.ID, "")
+}
+
+// handleConsentDecision - вторая фаза: фиксирует согласие и начинает интервью либо отменяет старт
+func (h *Handler) handleConsentDecision(session *UserSession, accepted bool) {
+	invitation := session.PendingInvitation
+	session.PendingInvitation = nil
+
+	if !accepted {
+		session.State = StateIdle
+		session.Consent = nil
+		h.reply(session, "Понимаем. Без согласия на обработку данных интервью провести нельзя. Если передумаете, используйте /start.")
+		return
+	}
+
+	session.Consent = &storage.Consent{
+		Version:    h.consentVersion,
+		AcceptedAt: time.Now().Format(time.RFC3339),
+	}
+	h.initializeInterview(session, invitation)
+}
@@ -0,0 +1,184 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"interview-bot-complete/internal/cv"
+	"interview-bot-complete/internal/interviewer"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// maxResumeFileSize - предельный размер файла резюме
+	maxResumeFileSize = 5 << 20
+	// resumeDownloadTimeout - время на скачивание файла резюме
+	resumeDownloadTimeout = 30 * time.Second
+)
+
+// resumeMimeTypes - MIME-типы принимаемых резюме
+var resumeMimeTypes = map[string]bool{
+	"application/pdf": true,
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document": true,
+}
+
+// isResumeDocument сообщает, похож ли файл на резюме в PDF или DOCX
+func isResumeDocument(document *Document) bool {
+	if resumeMimeTypes[document.MimeType] {
+		return true
+	}
+	switch strings.ToLower(path.Ext(document.FileName)) {
+	case ".pdf", ".docx":
+		return true
+	}
+	return false
+}
+
+// handleResumeUpload принимает резюме до начала интервью: факты из него (образование, должности,
+// навыки) предзаполняют профиль, а интервьюер только просит их подтвердить. Без согласия на обработку
+// данных файл не читается до /start.
+func (h *Handler) handleResumeUpload(document *Document, session *UserSession) {
+	if h.extractor == nil {
+		h.reply(session, "❌ Загрузка резюме в этом боте недоступна.")
+		return
+	}
+	if session.isActive() {
+		h.reply(session, "📄 Резюме учитывается только с начала интервью. Пришлите его после завершения текущего интервью, перед следующим /start.")
+		return
+	}
+	if document.FileSize > maxResumeFileSize {
+		h.replyf(session, "❌ Файл слишком большой: резюме должно быть не больше %d МБ.", maxResumeFileSize>>20)
+		return
+	}
+
+	if !h.hasConsent(session) {
+		session.PendingResumeFile = document.FileID
+		session.PendingResume = ""
+		h.reply(session, "📄 Резюме получено. Мы прочитаем его только после вашего согласия на обработку данных - отправьте /start.")
+		return
+	}
+
+	h.reply(session, "📄 Читаю резюме...")
+	facts, err := h.readResume(session, document.FileID)
+	if err != nil {
+		h.logger(session).Warn("Не удалось прочитать резюме", "error", err)
+		h.reply(session, resumeErrorText(err))
+		return
+	}
+	if facts == "" {
+		h.reply(session, "🤷 В резюме не нашлось фактов для профиля - интервью пройдет полностью.")
+		return
+	}
+	session.PendingResume = facts
+	session.PendingResumeFile = ""
+	h.logger(session).Info("Резюме загружено", "file_size", document.FileSize)
+	h.reply(session, "📄 *Резюме прочитано.* Вот что я узнал:\n\n"+resumeFactsText(facts)+
+		"\n\nВ интервью я только попрошу подтвердить эти факты.\n\nНачать интервью: /start\nУдалить резюме: /cv clear")
+}
+
+// handleCVCommand показывает загруженное резюме (/cv) или удаляет его (/cv clear)
+func (h *Handler) handleCVCommand(args []string, session *UserSession) {
+	if len(args) > 0 {
+		if strings.ToLower(args[0]) != "clear" {
+			h.reply(session, "❌ Используйте /cv или /cv clear.")
+			return
+		}
+		session.PendingResume = ""
+		session.PendingResumeFile = ""
+		h.reply(session, "✅ Резюме удалено: интервью пройдет полностью.")
+		return
+	}
+
+	switch {
+	case session.PendingResume != "":
+		h.reply(session, "📄 *Резюме для следующего интервью:*\n\n"+resumeFactsText(session.PendingResume)+
+			"\n\nНачать интервью: /start\nУдалить резюме: /cv clear")
+	case session.PendingResumeFile != "":
+		h.reply(session, "📄 Резюме получено и будет прочитано после согласия на обработку данных: /start\nУдалить резюме: /cv clear")
+	default:
+		h.reply(session, "📄 Пришлите резюме файлом (PDF или DOCX) до начала интервью: известные из него факты "+
+			"об образовании, работе и навыках интервьюер только попросит подтвердить.")
+	}
+}
+
+// attachResume подключает к новому интервью резюме, загруженное до его начала, и возвращает строку
+// для приветствия. Файл, ожидавший согласия, читается здесь. Резюме используется в одном интервью.
+func (h *Handler) attachResume(session *UserSession) string {
+	if h.extractor == nil {
+		return ""
+	}
+	if session.PendingResumeFile != "" && h.hasConsent(session) {
+		fileID := session.PendingResumeFile
+		session.PendingResumeFile = ""
+		facts, err := h.readResume(session, fileID)
+		if err != nil {
+			h.logger(session).Warn("Не удалось прочитать резюме", "error", err)
+			return "📄 Резюме прочитать не удалось - интервью пройдет полностью."
+		}
+		session.PendingResume = facts
+	}
+	if session.PendingResume == "" {
+		return ""
+	}
+
+	facts := session.PendingResume
+	session.PendingResume = ""
+	if err := h.engine.SetResumeFacts(&session.Session, facts); err != nil {
+		h.logger(session).Warn("Не удалось подключить резюме к интервью", "error", err)
+		return ""
+	}
+	return "📄 Интервьюер учтет ваше резюме: известные из него факты достаточно будет подтвердить."
+}
+
+// readResume скачивает резюме и извлекает из него факты профиля (JSON); пусто - фактов нет
+func (h *Handler) readResume(session *UserSession, fileID string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), resumeDownloadTimeout)
+	defer cancel()
+	data, err := h.bot.DownloadFile(ctx, fileID, maxResumeFileSize)
+	if err != nil {
+		return "", fmt.Errorf("ошибка скачивания резюме: %w", err)
+	}
+	text, err := cv.ExtractText(data)
+	if err != nil {
+		return "", err
+	}
+	lang := h.configFor(session).MatchLanguage(session.LanguageCode)
+	return h.extractor.ExtractResumeFacts(text, session.Tenant, lang)
+}
+
+// resumeErrorText возвращает сообщение пользователю об ошибке чтения резюме
+func resumeErrorText(err error) string {
+	switch {
+	case errors.Is(err, cv.ErrUnsupportedFormat):
+		return "❌ Поддерживаются резюме в PDF и DOCX."
+	case errors.Is(err, cv.ErrNoText):
+		return "❌ В файле не найден текст. Если это скан, сохраните резюме в PDF из текстового редактора или в DOCX."
+	default:
+		return "❌ Не удалось прочитать резюме, попробуйте позже."
+	}
+}
+
+// resumeFactsText перечисляет факты из резюме по строке на поле
+func resumeFactsText(factsJSON string) string {
+	var facts map[string]interface{}
+	if err := json.Unmarshal([]byte(factsJSON), &facts); err != nil {
+		return ""
+	}
+	keys := make([]string, 0, len(facts))
+	for key := range facts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var lines []string
+	for _, key := range keys {
+		if value := interviewer.FieldValue(facts[key]); value != "" {
+			lines = append(lines, fmt.Sprintf("• *%s:* %s", fieldLabel(key), markdownEscaper.Replace(value)))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
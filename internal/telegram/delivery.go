@@ -0,0 +1,35 @@
+package telegram
+
+// markDelivery запоминает, дошел ли до пользователя вопрос (или ответы блока на проверку):
+// недоставленный вопрос отправляется повторно при следующем сообщении пользователя
+func (h *Handler) markDelivery(session *UserSession, err error) {
+	if err == nil {
+		session.PendingDelivery = false
+		return
+	}
+	session.PendingDelivery = true
+	h.logger(session).Warn("Вопрос не доставлен, он будет отправлен повторно", "error", err)
+}
+
+// redeliverPending повторно отправляет недоставленный вопрос. Возвращает true, если сообщение
+// пользователя не нужно обрабатывать как ответ: он еще не видел вопроса, на который отвечает.
+func (h *Handler) redeliverPending(session *UserSession) bool {
+	if !session.PendingDelivery {
+		return false
+	}
+
+	switch session.State {
+	case StateReviewingBlock:
+		h.reply(session, "📨 Похоже, предыдущее сообщение не дошло. Отправляю ответы блока на проверку еще раз:")
+		h.sendBlockReview(session)
+		return true
+	case StateWaitingAnswer:
+		if prompt := h.engine.Current(&session.Session); prompt != nil {
+			h.reply(session, "📨 Похоже, предыдущий вопрос не дошел. Повторяю его - ответьте, пожалуйста, на него:")
+			h.sendPrompt(session, prompt)
+			return true
+		}
+	}
+	session.PendingDelivery = false
+	return false
+}
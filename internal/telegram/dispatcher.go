@@ -0,0 +1,271 @@
+package telegram
+
+import (
+	"context"
+	"interview-bot-complete/internal/metrics"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultUpdateWorkers - обработчиков обновлений по умолчанию
+	defaultUpdateWorkers = 32
+	// defaultUpdateQueue - обновлений в обработке и очереди по умолчанию
+	defaultUpdateQueue = 1000
+	// defaultShedAfter - сколько ждать места в очереди, прежде чем отбросить обновление
+	defaultShedAfter = 5 * time.Second
+	// shedNoticeInterval - не чаще этого сообщать чату, что его обновление отброшено
+	shedNoticeInterval = time.Minute
+)
+
+// Виды обновлений для учета отброшенных
+const (
+	updateKindMessage       = "message"
+	updateKindEditedMessage = "edited_message"
+	updateKindCallback      = "callback_query"
+	updateKindPreCheckout   = "pre_checkout_query"
+)
+
+// shedNoticeText - ответ на отброшенное сообщение
+const shedNoticeText = "⏳ Бот сейчас перегружен, ваше сообщение не обработано. Отправьте его еще раз через минуту."
+
+// DispatchOptions - параметры обработки полученных обновлений
+type DispatchOptions struct {
+	// Workers - обновлений, обрабатываемых одновременно
+	Workers int
+	// QueueSize - предел обновлений в обработке и очереди; при нем прием обновлений приостанавливается
+	QueueSize int
+	// ShedAfter - сколько ждать места в очереди; затем обновление отбрасывается с ответом пользователю
+	ShedAfter time.Duration
+}
+
+func defaultDispatch() DispatchOptions {
+	return DispatchOptions{
+		Workers:   defaultUpdateWorkers,
+		QueueSize: defaultUpdateQueue,
+		ShedAfter: defaultShedAfter,
+	}
+}
+
+// SetDispatch задает пул обработки обновлений; недопустимые значения заменяются значениями по умолчанию
+func (b *Bot) SetDispatch(opts DispatchOptions) {
+	defaults := defaultDispatch()
+	if opts.Workers <= 0 {
+		opts.Workers = defaults.Workers
+	}
+	if opts.QueueSize < opts.Workers {
+		opts.QueueSize = max(defaults.QueueSize, opts.Workers)
+	}
+	if opts.ShedAfter <= 0 {
+		opts.ShedAfter = defaults.ShedAfter
+	}
+	b.dispatch = opts
+}
+
+// SetMetrics подключает учет очереди обновлений
+func (b *Bot) SetMetrics(registry *metrics.Registry) {
+	b.metrics = registry
+}
+
+// dispatcher обрабатывает обновления пулом из Workers обработчиков. Обновления одного чата
+// обрабатываются строго по очереди, разные чаты - параллельно и поочередно, по одному обновлению.
+// Пока в обработке и очереди QueueSize обновлений, новые ждут места не дольше ShedAfter.
+type dispatcher struct {
+	bot     *Bot
+	handler func(Update)
+	opts    DispatchOptions
+	// slots - места в очереди: занимаются при постановке, освобождаются после обработки
+	slots chan struct{}
+	// ready - чаты с необработанными обновлениями, каждый не более одного раза
+	ready chan int64
+	// pending - поставленные в очередь и еще не обработанные обновления
+	pending sync.WaitGroup
+	workers sync.WaitGroup
+
+	mutex sync.Mutex
+	chats map[int64][]queuedUpdate
+	// shedNotices - когда чату последний раз сообщили об отброшенном обновлении; записи старше
+	// shedNoticeInterval удаляются. Используется только в shed из цикла приема обновлений.
+	shedNotices map[int64]time.Time
+}
+
+// queuedUpdate - обновление в очереди чата
+type queuedUpdate struct {
+	update   Update
+	queuedAt time.Time
+}
+
+func newDispatcher(bot *Bot, handler func(Update)) *dispatcher {
+	opts := bot.dispatch
+	d := &dispatcher{
+		bot:         bot,
+		handler:     handler,
+		opts:        opts,
+		slots:       make(chan struct{}, opts.QueueSize),
+		ready:       make(chan int64, opts.QueueSize),
+		chats:       make(map[int64][]queuedUpdate),
+		shedNotices: make(map[int64]time.Time),
+	}
+	for i := 0; i < opts.Workers; i++ {
+		d.workers.Add(1)
+		go d.work()
+	}
+	return d
+}
+
+// dispatch ставит обновление в очередь его чата. Без места в очереди ждет ShedAfter (тем временем
+// новые обновления не запрашиваются), затем отбрасывает обновление; платежи ждут без ограничения.
+// false - ctx отменен.
+func (d *dispatcher) dispatch(ctx context.Context, update Update) bool {
+	select {
+	case d.slots <- struct{}{}:
+	default:
+		if !d.waitSlot(ctx, update) {
+			return ctx.Err() == nil
+		}
+	}
+
+	chatID := updateChatID(update)
+	d.bot.metrics.UpdateQueued()
+	d.pending.Add(1)
+	d.mutex.Lock()
+	queue := d.chats[chatID]
+	d.chats[chatID] = append(queue, queuedUpdate{update: update, queuedAt: time.Now()})
+	if len(queue) == 0 {
+		// Чат не ждет обработчика и не обрабатывается: место в ready есть, слотов не больше его емкости
+		d.ready <- chatID
+	}
+	d.mutex.Unlock()
+	return true
+}
+
+// waitSlot ждет места в очереди; false - обновление отброшено или ctx отменен
+func (d *dispatcher) waitSlot(ctx context.Context, update Update) bool {
+	var timeout <-chan time.Time
+	if !isPaymentUpdate(update) {
+		timer := time.NewTimer(d.opts.ShedAfter)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	slog.Warn("Очередь обновлений заполнена, прием приостановлен", "queue_size", d.opts.QueueSize, "update_id", update.UpdateID)
+
+	select {
+	case d.slots <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		return false
+	case <-timeout:
+		d.shed(update)
+		return false
+	}
+}
+
+// work обрабатывает чаты из ready: одно обновление чата, затем чат снова встает в ready, если
+// у него остались обновления, - так длинная очередь одного чата не задерживает остальные
+func (d *dispatcher) work() {
+	defer d.workers.Done()
+	for chatID := range d.ready {
+		d.mutex.Lock()
+		item := d.chats[chatID][0]
+		d.mutex.Unlock()
+
+		d.bot.metrics.UpdateDequeued(time.Since(item.queuedAt))
+		d.handler(item.update)
+		<-d.slots
+		d.pending.Done()
+
+		d.mutex.Lock()
+		queue := d.chats[chatID][1:]
+		if len(queue) == 0 {
+			delete(d.chats, chatID)
+		} else {
+			d.chats[chatID] = queue
+			d.ready <- chatID
+		}
+		d.mutex.Unlock()
+	}
+}
+
+// stop дожидается обработки обновлений, уже поставленных в очередь
+func (d *dispatcher) stop() {
+	d.pending.Wait()
+	close(d.ready)
+	d.workers.Wait()
+}
+
+// shed отбрасывает обновление: нажатие кнопки получает ответ сразу, сообщение - ответ в чат
+// не чаще shedNoticeInterval
+func (d *dispatcher) shed(update Update) {
+	kind := updateKind(update)
+	d.bot.metrics.UpdateShed(kind)
+	slog.Warn("Обновление отброшено: очередь переполнена", "update_id", update.UpdateID, "kind", kind)
+
+	if query := update.CallbackQuery; query != nil {
+		if err := d.bot.AnswerCallbackQuery(query.ID, "⏳ Бот перегружен, нажмите еще раз через минуту."); err != nil {
+			slog.Warn("Не удалось ответить на отброшенное нажатие кнопки", "error", err)
+		}
+		return
+	}
+	message := update.Message
+	if message == nil || message.Chat == nil {
+		return
+	}
+	now := time.Now()
+	d.pruneShedNotices(now)
+	if _, noticed := d.shedNotices[message.Chat.ID]; noticed {
+		return
+	}
+	d.shedNotices[message.Chat.ID] = now
+	if err := d.bot.SendReply(Destination{ChatID: message.Chat.ID, MessageThreadID: message.MessageThreadID}, shedNoticeText); err != nil {
+		slog.Warn("Не удалось сообщить об отброшенном сообщении", "error", err)
+	}
+}
+
+// pruneShedNotices забывает уведомления старше shedNoticeInterval: таким чатам можно сообщить снова,
+// а в карте остаются только чаты, уведомленные за последний интервал
+func (d *dispatcher) pruneShedNotices(now time.Time) {
+	for chatID, noticedAt := range d.shedNotices {
+		if now.Sub(noticedAt) >= shedNoticeInterval {
+			delete(d.shedNotices, chatID)
+		}
+	}
+}
+
+// updateChatID возвращает чат обновления, а без чата - пользователя
+func updateChatID(update Update) int64 {
+	switch {
+	case update.Message != nil && update.Message.Chat != nil:
+		return update.Message.Chat.ID
+	case update.EditedMessage != nil && update.EditedMessage.Chat != nil:
+		return update.EditedMessage.Chat.ID
+	case update.CallbackQuery != nil:
+		if message := update.CallbackQuery.Message; message != nil && message.Chat != nil {
+			return message.Chat.ID
+		}
+		if update.CallbackQuery.From != nil {
+			return update.CallbackQuery.From.ID
+		}
+	case update.PreCheckoutQuery != nil && update.PreCheckoutQuery.From != nil:
+		return update.PreCheckoutQuery.From.ID
+	}
+	return 0
+}
+
+// updateKind возвращает вид обновления
+func updateKind(update Update) string {
+	switch {
+	case update.EditedMessage != nil:
+		return updateKindEditedMessage
+	case update.CallbackQuery != nil:
+		return updateKindCallback
+	case update.PreCheckoutQuery != nil:
+		return updateKindPreCheckout
+	}
+	return updateKindMessage
+}
+
+// isPaymentUpdate сообщает, что обновление относится к оплате и не может быть отброшено
+func isPaymentUpdate(update Update) bool {
+	return update.PreCheckoutQuery != nil || (update.Message != nil && update.Message.SuccessfulPayment != nil)
+}
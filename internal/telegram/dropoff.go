@@ -0,0 +1,140 @@
+package telegram
+
+import (
+	"fmt"
+	"interview-bot-complete/internal/storage"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultDropoffDays - за сколько дней /dropoff строит отчет без аргумента
+	defaultDropoffDays = 30
+	// dropoffReportTop - столько вопросов с наибольшим уходом попадает в отчет
+	dropoffReportTop = 10
+	// dropoffQuestionPreview - столько символов формулировки вопроса показывается в отчете
+	dropoffQuestionPreview = 80
+)
+
+// dropoffReasons - подписи причин ухода в отчете
+var dropoffReasons = map[string]string{
+	storage.DropoffStopped:   "/stop",
+	storage.DropoffRestarted: "перезапуск",
+	storage.DropoffInactive:  "неактивность",
+}
+
+// recordDropoff записывает, на каком вопросе пользователь бросил идущее интервью; вызывается до сброса сессии
+func (h *Handler) recordDropoff(session *UserSession, reason string) {
+	if !session.isActive() {
+		return
+	}
+	dropoff := h.engine.Dropoff(&session.Session, reason)
+	if dropoff == nil {
+		return
+	}
+	if err := storage.RecordDropoff(dropoff); err != nil {
+		h.logger(session).Warn("Не удалось записать брошенное интервью", "error", err)
+	}
+}
+
+// handleDropoffCommand обрабатывает команду /dropoff [дней]: на каких вопросах пользователи бросают интервью
+func (h *Handler) handleDropoffCommand(args []string, session *UserSession) {
+	if !h.requireAdmin(session) {
+		return
+	}
+
+	days := defaultDropoffDays
+	if len(args) > 0 {
+		parsed, err := strconv.Atoi(args[0])
+		if err != nil || parsed < 0 {
+			h.reply(session, "❌ Используйте /dropoff [дней], например /dropoff 7. /dropoff 0 - за все время.")
+			return
+		}
+		days = parsed
+	}
+	var since time.Time
+	if days > 0 {
+		since = time.Now().AddDate(0, 0, -days)
+	}
+
+	report, err := storage.CollectDropoffs(since)
+	if err != nil {
+		h.logger(session).Error("Ошибка построения отчета об уходе", "error", err)
+		h.reply(session, "❌ Не удалось построить отчет, попробуйте позже.")
+		return
+	}
+	period := "за все время"
+	if days > 0 {
+		period = fmt.Sprintf("за %d дн.", days)
+	}
+	h.reply(session, formatDropoffReport(report, period))
+}
+
+// StartDropoffReports раз в interval отправляет администраторам отчет об уходе за прошедший период.
+// Запускается только для основного бота: отчет общий для всех арендаторов.
+func (h *Handler) StartDropoffReports(interval time.Duration) {
+	h.goTicker("dropoff_report", interval, func() {
+		report, err := storage.CollectDropoffs(time.Now().Add(-interval))
+		if err != nil {
+			h.baseLogger.Warn("Ошибка построения отчета об уходе", "error", err)
+			return
+		}
+		h.notifyAdmins(formatDropoffReport(report, "за "+formatWait(interval)))
+	})
+}
+
+// formatDropoffReport показывает итоги и вопросы, на которых уходят чаще всего
+func formatDropoffReport(report *storage.DropoffReport, period string) string {
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("📉 *Уход по вопросам* (%s)\n\n", period))
+	text.WriteString(fmt.Sprintf("✅ Завершено: %d\n🚪 Брошено: %d", report.Completed, report.Abandoned))
+	if report.Abandoned > 0 {
+		reasons := make([]string, 0, len(report.ByReason))
+		for reason, count := range report.ByReason {
+			label, ok := dropoffReasons[reason]
+			if !ok {
+				label = reason
+			}
+			reasons = append(reasons, fmt.Sprintf("%s %d", label, count))
+		}
+		sort.Strings(reasons)
+		text.WriteString(" (" + strings.Join(reasons, ", ") + ")")
+	}
+	text.WriteString("\n")
+
+	var questions []storage.QuestionDropoff
+	for _, question := range report.Questions {
+		if question.Abandoned > 0 {
+			questions = append(questions, question)
+		}
+	}
+	if len(questions) == 0 {
+		text.WriteString("\nЗа этот период интервью не бросали.")
+		return text.String()
+	}
+	sort.SliceStable(questions, func(i, j int) bool {
+		if questions[i].Abandoned != questions[j].Abandoned {
+			return questions[i].Abandoned > questions[j].Abandoned
+		}
+		return questions[i].DropoffRate > questions[j].DropoffRate
+	})
+	if len(questions) > dropoffReportTop {
+		questions = questions[:dropoffReportTop]
+	}
+
+	text.WriteString("\n*Чаще всего бросают:*\n")
+	for i, question := range questions {
+		text.WriteString(fmt.Sprintf("%d. %s, блок %d «%s», вопрос %d — %.0f%% (%d из %d), средний ответ %.0f симв.\n",
+			i+1, markdownEscaper.Replace(question.TemplateID), question.BlockID, markdownEscaper.Replace(question.BlockName),
+			question.Question, question.DropoffRate*100, question.Abandoned, question.Reached, question.AvgAnswerLength))
+		if runes := []rune(question.Text); len(runes) > dropoffQuestionPreview {
+			question.Text = string(runes[:dropoffQuestionPreview]) + "…"
+		}
+		if question.Text != "" {
+			text.WriteString("   " + markdownEscaper.Replace(question.Text) + "\n")
+		}
+	}
+	return text.String()
+}
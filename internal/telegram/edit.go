@@ -0,0 +1,117 @@
+package telegram
+
+import (
+	"errors"
+	"fmt"
+	"interview-bot-complete/internal/engine"
+	"strconv"
+	"strings"
+)
+
+// answeredCount возвращает число вопросов текущего блока, на которые уже дан ответ
+func answeredCount(session *UserSession) int {
+	count := 0
+	for _, qa := range session.CurrentDialogue {
+		if qa.Answer != "" {
+			count++
+		}
+	}
+	return count
+}
+
+// handleEditCommand обрабатывает команду /edit N - исправление ответа в текущем блоке
+func (h *Handler) handleEditCommand(args []string, session *UserSession) {
+	if session.State == StateEditingAnswer && len(args) == 0 {
+		h.reply(session, "↩️ Исправление отменено.")
+		h.returnFromEdit(session)
+		return
+	}
+
+	if session.State != StateWaitingAnswer && session.State != StateReviewingBlock {
+		h.reply(session, "❌ Исправлять ответы можно только во время интервью, до завершения блока.")
+		return
+	}
+
+	answered := answeredCount(session)
+	if answered == 0 {
+		h.reply(session, "В текущем блоке пока нет ответов для исправления.")
+		return
+	}
+
+	if len(args) == 0 {
+		var list strings.Builder
+		list.WriteString("✏️ *Ответы текущего блока:*\n\n")
+		for i := 0; i < answered; i++ {
+			list.WriteString(fmt.Sprintf("%d. %s\n", i+1, session.CurrentDialogue[i].Question))
+		}
+		list.WriteString("\nИспользуйте /edit N, чтобы исправить ответ на вопрос N.")
+		h.reply(session, list.String())
+		return
+	}
+
+	number, err := strconv.Atoi(args[0])
+	if err != nil || number < 1 || number > answered {
+		h.replyf(session, "❌ Укажите номер вопроса от 1 до %d.", answered)
+		return
+	}
+
+	h.beginAnswerEdit(session, number-1)
+}
+
+// beginAnswerEdit переводит сессию в режим исправления ответа на вопрос index (с нуля)
+func (h *Handler) beginAnswerEdit(session *UserSession, index int) {
+	session.EditIndex = index
+	session.State = StateEditingAnswer
+
+	qa := session.CurrentDialogue[index]
+	var options string
+	if len(qa.Options) > 0 {
+		options = "\n\n*Варианты:* " + strings.Join(qa.Options, ", ")
+	}
+	h.replyf(session, "✏️ *Вопрос %d:* %s%s\n\n*Текущий ответ:* %s\n\nОтправьте новый ответ или /edit для отмены.",
+		index+1, qa.Question, options, qa.Answer)
+}
+
+// applyAnswerEdit заменяет ранее данный ответ и возвращает пользователя к текущему вопросу
+// или к проверке ответов блока
+func (h *Handler) applyAnswerEdit(text string, session *UserSession) {
+	if err := h.validateUserInput(session, text, session.EditIndex); err != nil {
+		h.reply(session, "❌ "+err.Error())
+		return
+	}
+
+	if err := h.engine.EditAnswer(&session.Session, session.EditIndex, text); err != nil {
+		if errors.Is(err, engine.ErrInvalidChoice) {
+			h.reply(session, "❌ Отправьте один из вариантов ответа или /edit для отмены.")
+			return
+		}
+		var typeErr *engine.AnswerTypeError
+		if errors.As(err, &typeErr) {
+			h.reply(session, "❌ "+typeErr.Error()+" Или /edit для отмены.")
+			return
+		}
+		h.returnFromEdit(session)
+		return
+	}
+
+	h.replyf(session, "✅ Ответ на вопрос %d обновлен.", session.EditIndex+1)
+	h.returnFromEdit(session)
+}
+
+// returnFromEdit возвращает пользователя к проверке ответов блока или к текущему вопросу
+func (h *Handler) returnFromEdit(session *UserSession) {
+	if session.Phase == engine.PhaseBlockReview {
+		session.State = StateReviewingBlock
+		h.sendBlockReview(session)
+		return
+	}
+	session.State = StateWaitingAnswer
+	h.resendCurrentQuestion(session)
+}
+
+// resendCurrentQuestion повторно отправляет вопрос, ожидающий ответа
+func (h *Handler) resendCurrentQuestion(session *UserSession) {
+	if prompt := h.engine.Current(&session.Session); prompt != nil {
+		h.sendPrompt(session, prompt)
+	}
+}
@@ -0,0 +1,64 @@
+package telegram
+
+import (
+	"interview-bot-complete/internal/engine"
+	"strings"
+)
+
+// rememberAnswerMessage запоминает сообщение, которым пользователь отвечает на текущий вопрос.
+// Ответ на уточняющий вопрос дописывается к исходному, поэтому его правка не отслеживается.
+func (h *Handler) rememberAnswerMessage(session *UserSession) {
+	session.LastAnswer = nil
+	index := len(session.CurrentDialogue) - 1
+	if (session.Phase != engine.PhaseAnswering && session.Phase != "") || index < 0 || session.CurrentDialogue[index].Answer != "" {
+		return
+	}
+	session.LastAnswer = &AnswerRef{
+		MessageID:   session.LastMessageID,
+		InterviewID: session.InterviewID,
+		Block:       session.CurrentBlock,
+		Index:       index,
+	}
+}
+
+// handleEditedMessage обновляет ответ, если пользователь исправил сообщение с последним ответом
+// до создания саммари блока. Об удалении сообщений Telegram ботам не сообщает.
+func (h *Handler) handleEditedMessage(message *Message) {
+	if message.From == nil || message.Chat == nil || !h.rateLimiter.Allow(message.From.ID) {
+		return
+	}
+	text := strings.TrimSpace(message.Text)
+	if text == "" || strings.HasPrefix(text, "/") {
+		return
+	}
+
+	session, unlock := h.lockSession(message.Chat.ID, message.From.ID)
+	defer unlock()
+	if message.MessageThreadID != session.ThreadID ||
+		(session.State != StateWaitingAnswer && session.State != StateReviewingBlock) {
+		return
+	}
+	defer h.persistSession(session)
+
+	ref := session.LastAnswer
+	if ref == nil || ref.MessageID != message.MessageID || ref.InterviewID != session.InterviewID ||
+		ref.Block != session.CurrentBlock || ref.Index != answeredCount(session)-1 {
+		h.reply(session, "✏️ Исправленное сообщение не учтено: правка обновляет только последний ответ до завершения блока. Используйте /edit, чтобы исправить другой ответ.")
+		return
+	}
+
+	if err := h.validateUserInput(session, text, ref.Index); err != nil {
+		h.reply(session, "❌ "+err.Error())
+		return
+	}
+	if err := h.engine.EditAnswer(&session.Session, ref.Index, text); err != nil {
+		h.reply(session, "❌ "+err.Error())
+		return
+	}
+
+	h.logger(session).Info("Ответ обновлен по исправленному сообщению", "question", ref.Index+1)
+	h.replyf(session, "✅ Ответ на вопрос %d обновлен по исправленному сообщению.", ref.Index+1)
+	if session.State == StateReviewingBlock {
+		h.sendBlockReview(session)
+	}
+}
@@ -0,0 +1,19 @@
+package telegram
+
+import (
+	"interview-bot-complete/internal/apperr"
+	"interview-bot-complete/internal/logging"
+)
+
+// errorReply возвращает безопасное сообщение об ошибке на языке интервью с кодом для поддержки
+// и пишет ошибку в лог с тем же кодом. Текст ошибки пользователю не показывается.
+func (h *Handler) errorReply(session *UserSession, err error) string {
+	code := apperr.Code(err)
+	h.logger(session).Error("Ошибка при обработке запроса пользователя", logging.KeyErrorCode, code, "error", err)
+	return "❌ " + apperr.UserMessage(err, h.configFor(session).Language(), code)
+}
+
+// replyError сообщает пользователю об ошибке (см. errorReply)
+func (h *Handler) replyError(session *UserSession, err error) {
+	h.reply(session, h.errorReply(session, err))
+}
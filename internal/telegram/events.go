@@ -0,0 +1,23 @@
+package telegram
+
+import (
+	"interview-bot-complete/internal/events"
+)
+
+// SetEvents подключает шину событий интервью: метрики, вебхуки арендаторов, журнал и выгрузки
+// получают события через подписки на нее
+func (h *Handler) SetEvents(bus *events.Bus) {
+	h.events = bus
+}
+
+// publishEvent дополняет событие данными интервью сессии и публикует его
+func (h *Handler) publishEvent(session *UserSession, event events.Event) {
+	event.InterviewID = session.InterviewID
+	event.TemplateID = session.TemplateID
+	event.UserID = session.UserID
+	if session.Result != nil {
+		event.Tenant = session.Result.Tenant
+		event.Partial = session.Result.Partial
+	}
+	h.events.Publish(event)
+}
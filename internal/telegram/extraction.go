@@ -0,0 +1,141 @@
+package telegram
+
+import (
+	"fmt"
+	"interview-bot-complete/internal/apperr"
+	"interview-bot-complete/internal/logging"
+	"time"
+)
+
+const (
+	// maxExtractionAttempts - сколько раз профиль извлекается автоматически, прежде чем ждать /getprofile
+	maxExtractionAttempts = 4
+	// extractionRetryDelay - пауза перед первым повтором, дальше удваивается
+	extractionRetryDelay = time.Minute
+	// extractionStaleAfter - через сколько извлечение без результата считается прерванным
+	// (процесс бота перезапустился, пока профиль составлялся)
+	extractionStaleAfter = 15 * time.Minute
+)
+
+// startExtraction переводит сессию в StateExtracting, сохраняет переход и запускает извлечение профиля
+func (h *Handler) startExtraction(session *UserSession) {
+	session.State = StateExtracting
+	session.ExtractionAttempts++
+	session.ExtractionStartedAt = time.Now()
+	session.ExtractionRetryAt = time.Time{}
+	h.persistSession(session)
+
+	interviewID := session.InterviewID
+	done := h.trackExtraction()
+	h.goSafe(session, "profile_extraction", func() {
+		defer done()
+		h.processProfileExtraction(session, interviewID)
+	})
+}
+
+// extractionCurrent сообщает, что сессия все еще ждет профиль интервью interviewID
+// (пользователь не начал новое интервью, пока профиль составлялся)
+func extractionCurrent(session *UserSession, interviewID string) bool {
+	return session.InterviewID == interviewID && session.State == StateExtracting
+}
+
+// finishExtraction отмечает, что профиль сохранен. Вызывается из фоновой задачи извлечения
+// и захватывает блокировку сессии, как и failExtraction и retryExtraction.
+func (h *Handler) finishExtraction(session *UserSession, interviewID string) {
+	unlock := h.relockSession(session)
+	defer unlock()
+	if !extractionCurrent(session, interviewID) {
+		return
+	}
+	session.State = StateProfileReady
+	session.ExtractionError = ""
+	h.persistSession(session)
+}
+
+// failExtraction отмечает неудачное извлечение и планирует повтор с растущей паузой.
+// Пользователь получает сообщение категории ошибки с кодом для поддержки, администраторы - текст ошибки.
+// После maxExtractionAttempts попыток повтор запускается по /getprofile.
+func (h *Handler) failExtraction(session *UserSession, interviewID string, err error) {
+	unlock := h.relockSession(session)
+	defer unlock()
+	if !extractionCurrent(session, interviewID) {
+		return
+	}
+	session.State = StateExtractionFailed
+	session.ExtractionError = err.Error()
+	code := apperr.Code(err)
+	h.logger(session).Warn("Ошибка извлечения профиля", "attempt", session.ExtractionAttempts, logging.KeyErrorCode, code, "error", err)
+	message := "❌ " + apperr.UserMessage(err, h.configFor(session).Language(), code)
+
+	if session.ExtractionAttempts >= maxExtractionAttempts {
+		h.persistSession(session)
+		h.reply(session, message+"\n\nИспользуйте /getprofile, чтобы попробовать еще раз.")
+		h.notifyAdmins(fmt.Sprintf("⚠️ Профиль интервью `%s` не создан после %d попыток (%s): %s",
+			interviewID, session.ExtractionAttempts, code, err.Error()))
+		return
+	}
+
+	delay := extractionRetryDelay << (session.ExtractionAttempts - 1)
+	session.ExtractionRetryAt = time.Now().Add(delay)
+	h.persistSession(session)
+	h.reply(session, fmt.Sprintf("%s\n\n🔄 Повторю попытку через %s.", message, formatWait(delay)))
+	time.AfterFunc(delay, func() { h.retryExtraction(session, interviewID) })
+}
+
+// retryExtraction повторяет извлечение, если профиль интервью все еще не создан
+func (h *Handler) retryExtraction(session *UserSession, interviewID string) {
+	unlock := h.relockSession(session)
+	defer unlock()
+	if session.InterviewID != interviewID || session.State != StateExtractionFailed {
+		return
+	}
+	h.startExtraction(session)
+}
+
+// resumeExtraction возобновляет извлечение, прерванное перезапуском бота или ожидающее повтора:
+// таймер повтора живет только в процессе, а состояние сессии - во внешнем хранилище
+func (h *Handler) resumeExtraction(session *UserSession) {
+	now := time.Now()
+	switch session.State {
+	case StateExtracting:
+		if now.Sub(session.ExtractionStartedAt) < extractionStaleAfter {
+			return
+		}
+	case StateExtractionFailed:
+		if session.ExtractionAttempts >= maxExtractionAttempts || now.Before(session.ExtractionRetryAt) {
+			return
+		}
+	default:
+		return
+	}
+	if h.extractor == nil {
+		return
+	}
+	h.logger(session).Info("Возобновление извлечения профиля", "state", session.State, "attempts", session.ExtractionAttempts)
+	h.startExtraction(session)
+}
+
+// replyProfilePending отвечает, если профиль еще составляется или не удался; возвращает true,
+// если профиля пока нет. После исчерпания автоматических попыток запускает новую.
+func (h *Handler) replyProfilePending(session *UserSession) bool {
+	switch session.State {
+	case StateExtracting:
+		h.reply(session, "⏳ Профиль еще составляется. Он придет в этот чат, как только будет готов.")
+		return true
+	case StateExtractionFailed:
+		if session.ExtractionAttempts < maxExtractionAttempts {
+			h.replyf(session, "⚠️ Профиль пока не удалось составить. Повторная попытка в %s.",
+				session.ExtractionRetryAt.Format("15:04"))
+			return true
+		}
+		if h.extractor == nil {
+			h.reply(session, "❌ Сервис анализа профилей недоступен.")
+			return true
+		}
+		session.ExtractionAttempts = 0
+		h.reply(session, "🔄 Повторяю анализ профиля...")
+		h.startExtraction(session)
+		return true
+	}
+	return false
+}
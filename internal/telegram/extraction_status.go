@@ -0,0 +1,89 @@
+package telegram
+
+import (
+	"fmt"
+	"interview-bot-complete/internal/extractor"
+	"strings"
+	"sync"
+)
+
+// extractionStageTexts - подписи этапов извлечения профиля в сообщении о ходе анализа
+var extractionStageTexts = map[string]string{
+	extractor.StagePreparing:  "📋 Готовлю ответы к анализу",
+	extractor.StageExtracting: "🔍 Извлекаю поля профиля",
+	extractor.StageValidating: "🧪 Проверяю профиль",
+	extractor.StageAnalyzing:  "🔬 Провожу расширенный анализ",
+	extractor.StageMatching:   "🧩 Подбираю архетип",
+	extractor.StageFinalizing: "💾 Сохраняю профиль",
+}
+
+const (
+	// extractionProgressCells - длина полосы прогресса
+	extractionProgressCells = 10
+	// Итоги сообщения о ходе анализа; подробности ошибки отправляются отдельным сообщением
+	extractionDoneStatus   = "🧠 *Анализ профиля*\n\n✅ Профиль готов"
+	extractionFailedStatus = "🧠 *Анализ профиля*\n\n⚠️ Анализ прерван"
+)
+
+// extractionStatus - сообщение о ходе составления профиля: вместо нового сообщения на каждом
+// этапе правится одно. Ошибки Telegram только пишутся в лог - извлечение от них не зависит.
+type extractionStatus struct {
+	h         *Handler
+	session   *UserSession
+	messageID int // 0 - сообщение не отправлено, правки пропускаются
+
+	mu   sync.Mutex
+	text string
+}
+
+// startExtractionStatus отправляет сообщение о начале анализа профиля
+func (h *Handler) startExtractionStatus(session *UserSession) *extractionStatus {
+	status := &extractionStatus{h: h, session: session, text: extractionStatusText("⏳ Начинаю анализ", 0)}
+	messageID, err := h.bot.SendMessageWithID(h.destination(session), status.text, nil)
+	if err != nil {
+		h.logger(session).Warn("Не удалось отправить сообщение о ходе анализа", "error", err)
+		return status
+	}
+	status.messageID = messageID
+	return status
+}
+
+// update показывает начавшийся этап извлечения; передается в extractor.ExtractOptions.OnStage
+func (s *extractionStatus) update(stage extractor.Stage) {
+	label, ok := extractionStageTexts[stage.Name]
+	if !ok {
+		label = "⏳ Анализирую"
+	}
+	s.edit(extractionStatusText(label, stage.Percent))
+}
+
+// finish заменяет сообщение итогом анализа
+func (s *extractionStatus) finish(text string) {
+	s.edit(text)
+}
+
+func (s *extractionStatus) edit(text string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// Telegram отклоняет правку без изменений текста
+	if s.messageID == 0 || text == s.text {
+		return
+	}
+	if err := s.h.bot.EditMessageText(s.session.ChatID, s.messageID, text); err != nil {
+		s.h.logger(s.session).Warn("Не удалось обновить сообщение о ходе анализа", "error", err)
+		return
+	}
+	s.text = text
+}
+
+// extractionStatusText возвращает текст сообщения о ходе анализа с полосой прогресса
+func extractionStatusText(label string, percent int) string {
+	if percent < 0 {
+		percent = 0
+	} else if percent > 100 {
+		percent = 100
+	}
+	filled := percent * extractionProgressCells / 100
+	bar := strings.Repeat("▰", filled) + strings.Repeat("▱", extractionProgressCells-filled)
+	return fmt.Sprintf("🧠 *Анализ профиля*\n\n%s… %d%%\n%s", label, percent, bar)
+}
@@ -0,0 +1,124 @@
+package telegram
+
+import "fmt"
+
+// destination возвращает адресата ответов сессии: чат, тему и (в группах) сообщение пользователя
+func (h *Handler) destination(session *UserSession) Destination {
+	dest := Destination{
+		ChatID:          session.ChatID,
+		MessageThreadID: session.ThreadID,
+	}
+	if session.IsGroup {
+		dest.ReplyToMessageID = session.LastMessageID
+	}
+	return dest
+}
+
+// reply отправляет сообщение участнику сессии
+func (h *Handler) reply(session *UserSession, text string) error {
+	return h.bot.SendReply(h.destination(session), text)
+}
+
+// replyRich отправляет участнику сессии сообщение, размеченное сущностями
+func (h *Handler) replyRich(session *UserSession, rich *RichText) error {
+	_, err := h.bot.SendRichText(h.destination(session), rich, nil)
+	return err
+}
+
+// replyf отправляет форматированное сообщение участнику сессии
+func (h *Handler) replyf(session *UserSession, format string, args ...interface{}) error {
+	return h.reply(session, fmt.Sprintf(format, args...))
+}
+
+// isActive сообщает, идет ли в сессии интервью
+func (s *UserSession) isActive() bool {
+	return s.State == StateInterview || s.State == StateWaitingAnswer || s.State == StateEditingAnswer ||
+		s.State == StateReviewingBlock
+}
+
+// isCompleted сообщает, что интервью завершено (в том числе пока составляется профиль
+// и в режиме вопросов о профиле)
+func (s *UserSession) isCompleted() bool {
+	switch s.State {
+	case StateCompleted, StateExtracting, StateProfileReady, StateExtractionFailed, StateAskingProfile:
+		return true
+	}
+	return false
+}
+
+// bindSessionToMessage запоминает тему и сообщение, на которые нужно отвечать.
+// Во время интервью сессия остается привязанной к теме, в которой оно началось.
+func (h *Handler) bindSessionToMessage(session *UserSession, message *Message) {
+	session.IsGroup = message.Chat.IsGroup()
+	session.LanguageCode = message.From.LanguageCode
+
+	if !session.isActive() {
+		session.ThreadID = message.MessageThreadID
+	}
+	if message.MessageThreadID == session.ThreadID {
+		session.LastMessageID = message.MessageID
+	}
+}
+
+// acceptGroupAnswer решает, считать ли сообщение в группе ответом на вопрос интервью.
+// Сообщения посторонних участников в теме с идущим интервью вежливо отклоняются.
+func (h *Handler) acceptGroupAnswer(session *UserSession, message *Message) bool {
+	if (session.State == StateWaitingAnswer || session.State == StateEditingAnswer || session.State == StateAskingProfile) && message.MessageThreadID == session.ThreadID {
+		return true
+	}
+
+	h.sessionsMutex.RLock()
+	owner := h.threadOwners[threadKey{ChatID: message.Chat.ID, ThreadID: message.MessageThreadID}]
+	h.sessionsMutex.RUnlock()
+
+	if owner != 0 && owner != session.UserID {
+		h.bot.SendReply(Destination{
+			ChatID:           message.Chat.ID,
+			MessageThreadID:  message.MessageThreadID,
+			ReplyToMessageID: message.MessageID,
+		}, "🙏 Сейчас здесь идет интервью с другим участником. Пожалуйста, не отвечайте за него — используйте /start в другой теме, чтобы пройти свое.")
+	}
+
+	// Прочие сообщения в группе не относятся к интервью и игнорируются
+	return false
+}
+
+// threadOwnedByOther сообщает, закреплена ли тема сессии за другим участником
+func (h *Handler) threadOwnedByOther(session *UserSession) bool {
+	h.sessionsMutex.RLock()
+	defer h.sessionsMutex.RUnlock()
+
+	owner, exists := h.threadOwners[threadKey{ChatID: session.ChatID, ThreadID: session.ThreadID}]
+	return exists && owner != session.UserID
+}
+
+// claimThread закрепляет тему группы за участником сессии; false если тема занята другим
+func (h *Handler) claimThread(session *UserSession) bool {
+	h.sessionsMutex.Lock()
+	defer h.sessionsMutex.Unlock()
+
+	key := threadKey{ChatID: session.ChatID, ThreadID: session.ThreadID}
+	if owner, exists := h.threadOwners[key]; exists && owner != session.UserID {
+		return false
+	}
+	h.threadOwners[key] = session.UserID
+	return true
+}
+
+// releaseThread освобождает тему группы, закрепленную за сессией
+func (h *Handler) releaseThread(session *UserSession) {
+	h.sessionsMutex.Lock()
+	defer h.sessionsMutex.Unlock()
+	h.releaseThreadLocked(session)
+}
+
+func (h *Handler) releaseThreadLocked(session *UserSession) {
+	if !session.IsGroup {
+		return
+	}
+
+	key := threadKey{ChatID: session.ChatID, ThreadID: session.ThreadID}
+	if h.threadOwners[key] == session.UserID {
+		delete(h.threadOwners, key)
+	}
+}
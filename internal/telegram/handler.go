@@ -1,233 +1,554 @@
 package telegram
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"interview-bot-complete/internal/answercheck"
+	"interview-bot-complete/internal/api"
+	"interview-bot-complete/internal/apperr"
 	"interview-bot-complete/internal/config"
+	"interview-bot-complete/internal/embeddings"
+	"interview-bot-complete/internal/engine"
+	"interview-bot-complete/internal/events"
 	"interview-bot-complete/internal/extractor"
 	"interview-bot-complete/internal/interviewer"
+	"interview-bot-complete/internal/invite"
+	"interview-bot-complete/internal/logging"
+	"interview-bot-complete/internal/metrics"
+	"interview-bot-complete/internal/moderation"
+	"interview-bot-complete/internal/ratelimit"
+	"interview-bot-complete/internal/reload"
+	"interview-bot-complete/internal/report"
+	"interview-bot-complete/internal/reporting"
+	"interview-bot-complete/internal/search"
+	"interview-bot-complete/internal/sheets"
 	"interview-bot-complete/internal/storage"
-	"os"
+	"interview-bot-complete/internal/tenant"
+	"log/slog"
 	"strings"
 	"sync"
 	"time"
+)
+
+// llmBudgetWaitTimeout - максимальное время ожидания бюджета обращений к OpenAI
+const llmBudgetWaitTimeout = 2 * time.Minute
 
-	"github.com/google/uuid"
+const (
+	// sessionLockWait - сколько команда администратора ждет сессию другого пользователя,
+	// занятую обработкой сообщения
+	sessionLockWait = 15 * time.Second
+	// sessionLockPoll - период повторных попыток захватить блокировку сессии
+	sessionLockPoll = 20 * time.Millisecond
 )
 
-type RateLimiter struct {
-	requests map[int64][]time.Time
-	mutex    sync.RWMutex
-	limit    int
-	window   time.Duration
+type Handler struct {
+	bot             *Bot
+	templates       *config.Templates
+	invites         *invite.Validator
+	requireInvite   bool
+	interviewer     *interviewer.Service
+	engine          *engine.Engine
+	extractor       *extractor.Service
+	embeddings      *embeddings.Service
+	search          *search.Index
+	reloader        *reload.Reloader
+	sheets          *sheets.Exporter
+	events          *events.Bus // события интервью для интеграций; nil - без подписчиков
+	moderation      *moderation.Service
+	moderationCfg   config.ModerationConfig
+	researchSalt    string // соль псевдонимов обезличенной выгрузки; пусто - /researchexport недоступна
+	memory          bool   // память интервьюера: прошлый профиль в вопросах для согласившихся пользователей
+	sessions        map[sessionKey]*UserSession
+	threadOwners    map[threadKey]int64
+	sessionsMutex   sync.RWMutex
+	sessionStore    SessionStore
+	outboxMutex     sync.Mutex // одна выгрузка outbox профилей за раз
+	extendedMutex   sync.Mutex
+	extendedJobs    map[string]bool // интервью, для которых готовится расширенный анализ (под extendedMutex)
+	limits          config.RateLimitConfig
+	askPerDay       int
+	rateLimiter     ratelimit.RateLimiter
+	llmLimiter      ratelimit.RateLimiter
+	askQuota        ratelimit.UserQuota
+	linkAttempts    ratelimit.UserQuota // неудачные попытки /link: защита кодов привязки от перебора
+	quotas          config.QuotaConfig
+	interviewQuota  ratelimit.UserQuota
+	interviewSlots  ratelimit.ActiveSlots
+	tokenBudget     ratelimit.TokenBudget
+	profilePreview  bool
+	blockReview     bool
+	nudgeAfter      time.Duration // через сколько напомнить о вопросе без ответа; 0 - не напоминать
+	admins          map[int64]bool
+	adminChatID     int64
+	consentRequired bool
+	consentVersion  string
+	premium         config.PremiumConfig
+	recruiterChatID int64
+	reportFont      *report.Font
+	reporters       []reporting.Reporter
+	metrics         *metrics.Registry
+	tenants         *tenant.Registry
+	tenant          *tenant.Tenant // арендатор отдельного бота; nil - основной бот
+	baseLogger      *slog.Logger
+
+	// Трансляция интервью наблюдателям: общий чат и очередь отправки (создается при первом сообщении)
+	observerChatID int64
+	observerQueue  chan observerMessage
+	observerOnce   sync.Once
+
+	maintenance *maintenanceMode // режим обслуживания и идущие извлечения, общие для всех ботов
 }
 
-func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
-	return &RateLimiter{
-		requests: make(map[int64][]time.Time),
-		limit:    limit,
-		window:   window,
+func NewHandler(bot *Bot, templates *config.Templates, invites *invite.Validator, appCfg *config.AppConfig, interviewerService *interviewer.Service, extractorService *extractor.Service) *Handler {
+	limits := appCfg.RateLimit
+	h := &Handler{
+		bot:             bot,
+		templates:       templates,
+		invites:         invites,
+		requireInvite:   appCfg.Invites.Required,
+		profilePreview:  appCfg.Telegram.ProfilePreview,
+		blockReview:     appCfg.Telegram.BlockReview,
+		nudgeAfter:      appCfg.Telegram.NudgeAfter,
+		adminChatID:     appCfg.Telegram.AdminChatID,
+		consentRequired: appCfg.Consent.Required,
+		consentVersion:  appCfg.Consent.Version,
+		premium:         appCfg.Premium,
+		moderationCfg:   appCfg.Moderation,
+		researchSalt:    appCfg.Research.PseudonymSalt,
+		memory:          appCfg.Telegram.InterviewerMemory,
+		recruiterChatID: appCfg.Outcome.RecruiterChatID,
+		observerChatID:  appCfg.Telegram.ObserverChatID,
+		interviewer:     interviewerService,
+		extractor:       extractorService,
+		sessions:        make(map[sessionKey]*UserSession),
+		threadOwners:    make(map[threadKey]int64),
+		limits:          limits,
+		askPerDay:       appCfg.ProfileQA.QuestionsPerDay,
+		quotas:          appCfg.Quotas,
+		maintenance:     &maintenanceMode{},
 	}
-}
-
-func (rl *RateLimiter) IsAllowed(userID int64) bool {
-	rl.mutex.Lock()
-	defer rl.mutex.Unlock()
-
-	now := time.Now()
-
-	if requests, exists := rl.requests[userID]; exists {
-		var valid []time.Time
-		for _, t := range requests {
-			if now.Sub(t) < rl.window {
-				valid = append(valid, t)
-			}
-		}
-		rl.requests[userID] = valid
+	h.maintenance.enabled.Store(appCfg.Telegram.Maintenance)
+	messageLimiter := ratelimit.New(messageLimiterConfig(limits))
+	llmLimiter := ratelimit.New(llmLimiterConfig(limits))
+	messageLimiter.StartCleanup(limits.CleanupInterval)
+	llmLimiter.StartCleanup(limits.CleanupInterval)
+	h.rateLimiter = messageLimiter
+	h.llmLimiter = llmLimiter
+	h.engine = engine.New(templates, interviewerService)
+	h.engine.SetLLMLimiter(llmLimiter)
+	h.baseLogger = slog.Default()
+	h.askQuota = ratelimit.NewQuota(h.askPerDay, 24*time.Hour)
+	h.interviewQuota = ratelimit.NewQuota(h.quotas.InterviewsPerUserPerDay, 24*time.Hour)
+	h.linkAttempts = ratelimit.NewQuota(maxLinkAttempts, linkLockout)
+	h.interviewSlots = ratelimit.NewSlots(h.quotas.MaxActiveInterviews, activeInterviewTTL)
+	h.tokenBudget = ratelimit.NewDailyBudget(h.quotas.DailyTokenBudget)
+	// Токены интервьюера и анализа профилей расходуют общий дневной бюджет
+	interviewerService.SetUsageRecorder(api.UsageRecorderFunc(h.recordUsage))
+	if extractorService != nil {
+		extractorService.SetUsageRecorder(api.UsageRecorderFunc(h.recordUsage))
 	}
-
-	if len(rl.requests[userID]) >= rl.limit {
-		return false
+	// Вопросы интервьюера, саммари и извлечение профилей делят лимиты аккаунта OpenAI
+	// через один планировщик; вопросы идут первыми
+	scheduler := api.NewScheduler(openAISchedulerConfig(limits))
+	interviewerService.SetScheduler(scheduler)
+	if extractorService != nil {
+		extractorService.SetScheduler(scheduler)
+	}
+	h.admins = make(map[int64]bool)
+	for _, adminID := range appCfg.Telegram.AdminIDs {
+		h.admins[adminID] = true
 	}
+	h.startSessionCleanup()
+	h.startBlockTimeWatcher()
+	h.startNudgeWatcher()
+	return h
+}
 
-	rl.requests[userID] = append(rl.requests[userID], now)
-	return true
+// openAISchedulerConfig - лимиты аккаунта OpenAI для планировщика запросов
+func openAISchedulerConfig(limits config.RateLimitConfig) api.SchedulerConfig {
+	return api.SchedulerConfig{
+		RequestsPerMinute: limits.OpenAIRequestsPerMinute,
+		TokensPerMinute:   limits.OpenAITokensPerMinute,
+		MaxWait:           limits.OpenAIMaxWait,
+	}
 }
 
-type Handler struct {
-	bot           *Bot
-	config        *config.Config
-	interviewer   *interviewer.Service
-	extractor     *extractor.Service
-	sessions      map[int64]*UserSession
-	sessionsMutex sync.RWMutex
-	rateLimiter   *RateLimiter
+// messageLimiterConfig - лимит входящих сообщений пользователя
+func messageLimiterConfig(limits config.RateLimitConfig) ratelimit.Config {
+	return ratelimit.Config{
+		PerUserPerMinute: limits.MessagesPerMinute,
+		PerUserBurst:     limits.MessagesBurst,
+		IdleTTL:          limits.IdleTTL,
+	}
 }
 
-func NewHandler(bot *Bot, cfg *config.Config, interviewerService *interviewer.Service, extractorService *extractor.Service) *Handler {
-	h := &Handler{
-		bot:         bot,
-		config:      cfg,
-		interviewer: interviewerService,
-		extractor:   extractorService,
-		sessions:    make(map[int64]*UserSession),
-		rateLimiter: NewRateLimiter(10, time.Minute),
+// llmLimiterConfig - пользовательский и глобальный бюджет обращений к OpenAI
+func llmLimiterConfig(limits config.RateLimitConfig) ratelimit.Config {
+	return ratelimit.Config{
+		PerUserPerMinute: limits.LLMCallsPerMinute,
+		PerUserBurst:     limits.LLMCallsBurst,
+		GlobalPerMinute:  limits.GlobalLLMPerMinute,
+		GlobalBurst:      limits.GlobalLLMBurst,
+		IdleTTL:          limits.IdleTTL,
 	}
-	h.startSessionCleanup()
-	return h
 }
 
 func (h *Handler) startSessionCleanup() {
-	ticker := time.NewTicker(1 * time.Hour)
-	go func() {
-		for range ticker.C {
-			h.cleanupInactiveSessions()
-		}
-	}()
+	h.goTicker("session_cleanup", 1*time.Hour, h.cleanupInactiveSessions)
 }
 
 func (h *Handler) cleanupInactiveSessions() {
-	h.sessionsMutex.Lock()
-	defer h.sessionsMutex.Unlock()
-
 	cutoff := time.Now().Add(-24 * time.Hour)
-	for uid, sess := range h.sessions {
-		if sess.LastActivity.Before(cutoff) {
-			delete(h.sessions, uid)
-		}
+	for _, sess := range h.sessionList() {
+		h.cleanupSession(sess, cutoff)
 	}
 }
 
+// cleanupSession завершает сессию, неактивную с cutoff. Сессия, занятая обработкой
+// сообщения, активна - ее проверит следующая очистка.
+func (h *Handler) cleanupSession(session *UserSession, cutoff time.Time) {
+	unlock, ok := h.tryLockSession(session)
+	if !ok {
+		return
+	}
+	defer unlock()
+	if !session.LastActivity.Before(cutoff) {
+		return
+	}
+
+	// Интервью с пройденными блоками не удаляем молча - сначала предлагаем частичный профиль
+	if session.isActive() && session.Result != nil && len(session.Result.Blocks) > 0 {
+		h.abandonInterview(session, storage.DropoffInactive, "⌛ Интервью прервано из-за долгого отсутствия ответов.")
+		h.persistSession(session)
+		return
+	}
+
+	h.sessionsMutex.Lock()
+	key := sessionKey{ChatID: session.ChatID, UserID: session.UserID}
+	if h.sessions[key] == session {
+		delete(h.sessions, key)
+	}
+	h.releaseThreadLocked(session)
+	h.sessionsMutex.Unlock()
+
+	h.recordDropoff(session, storage.DropoffInactive)
+}
+
 func (h *Handler) HandleUpdate(update Update) {
-	if update.Message == nil || update.Message.From == nil {
+	if update.CallbackQuery != nil {
+		h.handleCallbackQuery(update.CallbackQuery)
+		return
+	}
+	if update.PreCheckoutQuery != nil {
+		h.handlePreCheckoutQuery(update.PreCheckoutQuery)
+		return
+	}
+	if update.EditedMessage != nil {
+		h.handleEditedMessage(update.EditedMessage)
+		return
+	}
+
+	message := update.Message
+	if message == nil || message.From == nil || message.Chat == nil {
+		return
+	}
+	userID := message.From.ID
+	text := strings.TrimSpace(message.Text)
+
+	// Сообщение об оплате обрабатывается всегда, без лимита сообщений
+	if message.SuccessfulPayment != nil {
+		session, unlock := h.lockSession(message.Chat.ID, userID)
+		defer unlock()
+		h.bindSessionToMessage(session, message)
+		defer h.persistSession(session)
+		h.handleSuccessfulPayment(session, message.SuccessfulPayment)
 		return
 	}
-	userID := update.Message.From.ID
-	chatID := update.Message.Chat.ID
-	text := strings.TrimSpace(update.Message.Text)
 
-	if !h.rateLimiter.IsAllowed(userID) {
-		h.bot.SendMessage(chatID, "⏳ Слишком много сообщений. Пожалуйста, подождите минуту.")
+	if !h.rateLimiter.Allow(userID) {
+		h.bot.SendReply(Destination{
+			ChatID:           message.Chat.ID,
+			MessageThreadID:  message.MessageThreadID,
+			ReplyToMessageID: message.MessageID,
+		}, "⏳ Слишком много сообщений. Пожалуйста, подождите минуту.")
 		return
 	}
 
-	session := h.getOrCreateSession(userID)
+	session, unlock := h.lockSession(message.Chat.ID, userID)
+	defer unlock()
+	h.bindSessionToMessage(session, message)
+	defer h.persistSession(session)
+	defer h.syncCommandMenu(session)
+	h.resumeExtraction(session)
 
-	if strings.HasPrefix(text, "/") {
-		h.handleCommand(chatID, text, session)
+	// CSV для массовых приглашений приходит файлом с командой в подписи
+	if command, _ := parseCommand(message.Caption); message.Document != nil && command == "/bulkinvite" {
+		h.handleBulkInviteCommand(message.Caption, message.Document, session)
+		return
+	}
+	// Снимок сессии для /loadsession тоже приходит файлом
+	if command, _ := parseCommand(message.Caption); message.Document != nil && command == "/loadsession" {
+		h.handleLoadSessionCommand(message.Document, session)
+		return
+	}
+	// Резюме (PDF или DOCX) перед интервью присылают в личном чате файлом без команды
+	if message.Document != nil && message.Caption == "" && !message.Chat.IsGroup() && isResumeDocument(message.Document) {
+		h.handleResumeUpload(message.Document, session)
 		return
 	}
-	h.handleUserInput(chatID, text, session)
-}
 
-func (h *Handler) completeInterview(chatID int64, session *UserSession) {
-	if err := storage.SaveResult(session.Result); err != nil {
-		h.bot.SendMessage(chatID, "Ошибка сохранения результата интервью.")
+	if strings.HasPrefix(text, "/") {
+		h.handleCommand(text, session)
 		return
 	}
-	session.State = StateCompleted
 
-	h.bot.SendMessage(chatID, "🎉 Интервью завершено! Начинаю анализ вашего профиля...")
-	if h.extractor != nil {
-		go h.processProfileExtraction(chatID, session)
+	// В группах принимаем ответы только от участника, с которым идет интервью в этой теме
+	if message.Chat.IsGroup() && !h.acceptGroupAnswer(session, message) {
+		return
+	}
+	// Администратор, подключенный к интервью через /takeover, пишет вопросы для пользователя
+	if session.Supervising != 0 && !message.Chat.IsGroup() && h.isAdmin(userID) {
+		h.injectSupervisorQuestion(text, session)
+		return
 	}
+	h.handleUserInput(text, session)
+}
 
-	completionText := fmt.Sprintf(`✅ *Интервью успешно завершено!*
-📊 Собрано данных:
-• %d блоков пройдено
-• %d ответов получено
-• 🆔 ID: `+"`%s`"+`
+// completeInterview сообщает о завершении интервью (результат уже сохранен движком) и запускает анализ профиля
+func (h *Handler) completeInterview(session *UserSession) {
+	h.releaseThread(session)
+	h.releaseInterviewSlot(session)
+	h.trackInvitation(session, storage.InvitationCompleted)
+	h.endSupervision(session, "✅ Интервью завершено, наблюдение окончено.")
+	h.observe(session, "🏁", "интервью завершено")
+	h.publishEvent(session, events.Event{
+		Kind:     events.InterviewCompleted,
+		Duration: time.Duration(session.Result.DurationSeconds) * time.Second,
+	})
 
-🧠 Анализ профиля в процессе...
-Результат будет готов через 1-2 минуты.
+	h.reply(session, "🎉 Интервью завершено! Начинаю анализ вашего профиля...")
+	if h.extractor != nil {
+		h.startExtraction(session)
+	} else {
+		session.State = StateCompleted
+	}
 
-Используйте /start для нового интервью.`,
-		len(session.Result.Blocks),
-		h.getTotalAnswersCount(session.Result),
-		session.InterviewID,
-	)
-	h.bot.SendMessage(chatID, completionText)
+	completionText := h.configFor(session).CompletionMessage(session.InterviewID,
+		len(session.Result.Blocks), h.getTotalAnswersCount(session.Result))
+	h.reply(session, completionText)
 }
 
-func (h *Handler) processProfileExtraction(chatID int64, session *UserSession) {
-	profileResult, err := h.extractor.ExtractProfile(session.Result)
+// processProfileExtraction составляет и сохраняет профиль интервью interviewID.
+// Сессия остается в StateExtracting, пока файл профиля не сохранен.
+func (h *Handler) processProfileExtraction(session *UserSession, interviewID string) {
+	if err := h.waitLLMBudget(session.UserID); err != nil {
+		h.failExtraction(session, interviewID, apperr.Wrap(apperr.ErrRateLimited, err))
+		return
+	}
+
+	status := h.startExtractionStatus(session)
+	opts := h.extractOptions(session)
+	opts.Extended = h.hasPremium(session.UserID)
+	opts.OnStage = status.update
+	started := time.Now()
+	profileResult, err := h.extractor.ExtractProfileWithOptions(session.Result, opts)
 	if err != nil {
-		h.bot.SendMessage(chatID, "❌ Ошибка при анализе профиля: "+err.Error())
+		status.finish(extractionFailedStatus)
+		h.publishEvent(session, events.Event{Kind: events.ExtractionFailed, Err: err})
+		h.failExtraction(session, interviewID, err)
 		return
 	}
 	if !profileResult.Success {
-		h.bot.SendMessage(chatID, "❌ Не удалось проанализировать профиль: "+profileResult.Error)
+		err := errors.New(profileResult.Error)
+		status.finish(extractionFailedStatus)
+		h.publishEvent(session, events.Event{Kind: events.ExtractionFailed, Err: err})
+		h.failExtraction(session, interviewID, err)
 		return
 	}
 
-	fileName, err := h.extractor.SaveProfile(session.InterviewID, profileResult)
+	fileName, err := h.extractor.SaveProfile(interviewID, profileResult)
 	if err != nil {
-		h.bot.SendMessage(chatID, "⚠️ Профиль создан, но не удалось сохранить файл: "+err.Error())
+		status.finish(extractionFailedStatus)
+		h.publishEvent(session, events.Event{Kind: events.ExtractionFailed, Err: err})
+		h.failExtraction(session, interviewID, apperr.Wrap(apperr.ErrStorage, err))
 		return
 	}
+	status.finish(extractionDoneStatus)
+	h.enqueueDelivery(session, interviewID, fileName)
+	h.finishExtraction(session, interviewID)
+	h.indexProfile(session.InterviewID, profileResult.ProfileJSON)
+	h.indexForSearch(session.InterviewID)
+	h.updateCombinedProfile(session)
+	h.trackInvitation(session, storage.InvitationProfileReady)
+	h.publishEvent(session, events.Event{
+		Kind:             events.ProfileExtracted,
+		Duration:         time.Since(started),
+		ProfileJSON:      profileResult.ProfileJSON,
+		Model:            profileResult.Model,
+		PromptTokens:     profileResult.Usage.PromptTokens,
+		CompletionTokens: profileResult.Usage.CompletionTokens,
+		CostUSD:          api.EstimateCost(profileResult.Model, profileResult.Usage),
+	})
+	h.classifyOutcome(session, profileResult.ProfileJSON)
 
-	// Отправляем краткое резюме
-	summary, err := h.extractor.GetProfileSummary(profileResult.ProfileJSON)
-	if err != nil {
-		summary = "Профиль создан, но не удалось сгенерировать резюме."
+	// Отправляем краткое резюме: разделы профиля свернуты и раскрываются по нажатию
+	texts := summaryLocaleFor(h.configFor(session).Language())
+	resultMessage := NewRichText().Bold(texts.Completed).Text("\n\n").Bold(texts.Title).Text("\n\n")
+	if err := writeProfileSummary(resultMessage, profileResult.ProfileJSON); err != nil {
+		resultMessage.Text(texts.NoSummary + "\n")
 	}
+	resultMessage.Text("\n" + texts.Saved + "\n\n").Italic(texts.Disclaimer)
+	h.replyRich(session, resultMessage)
 
-	resultMessage := fmt.Sprintf(`🎯 *Анализ профиля завершен!*
-
-%s
-
-💾 Профиль сохранен в файл
-
-🔍 Профиль содержит детальную информацию:
-• Личные данные и контакты
-• Образование и карьера
-• Навыки и компетенции
-• Интересы и хобби
-• Цели и планы
-
-_Этот анализ создан искусственным интеллектом на основе ваших ответов._`,
-		summary,
-	)
-	h.bot.SendMessage(chatID, resultMessage)
+	// Отправляем JSON файл; недоставленный профиль остается в outbox и будет отправлен повторно
+	if h.sendJSONProfile(session, fileName, interviewID) == nil {
+		h.completeDelivery(session, interviewID)
+	}
+	h.sendProfileChart(session, profileResult.ProfileJSON)
 
-	// Отправляем JSON файл
-	h.sendJSONFile(chatID, fileName, session.InterviewID)
+	if analysis := extractor.ParseExtendedAnalysis(profileResult.ProfileJSON); analysis != nil {
+		h.sendExtendedReport(session, analysis)
+	} else if h.premium.Enabled {
+		h.reply(session, "💎 Хотите больше? /premium - развернутый отчет, разбор архетипа личности и PDF.")
+	}
 }
 
 // handleCommand обрабатывает команды бота
-func (h *Handler) handleCommand(chatID int64, command string, session *UserSession) {
+func (h *Handler) handleCommand(text string, session *UserSession) {
+	command, args := parseCommand(text)
+
 	switch command {
 	case "/start":
-		h.handleStartCommand(chatID, session)
+		h.handleStartCommand(args, session)
 	case "/help":
-		h.handleHelpCommand(chatID)
+		h.handleHelpCommand(session)
 	case "/status":
-		h.handleStatusCommand(chatID, session)
+		h.handleStatusCommand(session)
 	case "/restart":
-		h.handleRestartCommand(chatID, session)
+		h.handleRestartCommand(session)
 	case "/stop":
-		h.handleStopCommand(chatID, session)
+		h.handleStopCommand(session)
+	case "/pause":
+		h.handlePauseCommand(session)
+	case "/skip":
+		h.handleSkipCommand(session)
 	case "/getprofile":
-		h.handleGetProfileCommand(chatID, session)
+		h.handleGetProfileCommand(args, session)
 	case "/getsummary":
-		h.handleGetSummaryCommand(chatID, session)
+		h.handleGetSummaryCommand(session)
+	case "/history":
+		h.handleHistoryCommand(session)
+	case "/summary":
+		h.handleSummaryCommand(args, session)
+	case "/edit":
+		h.handleEditCommand(args, session)
+	case "/ask":
+		h.handleAskCommand(args, session)
+	case "/transcript":
+		h.handleTranscriptCommand(session)
+	case "/reextract":
+		h.handleReextractCommand(args, session)
+	case "/profilediff":
+		h.handleProfileDiffCommand(args, session)
+	case "/stats":
+		h.handleStatsCommand(session)
+	case "/dumpsession":
+		h.handleDumpSessionCommand(args, session)
+	case "/loadsession":
+		h.handleLoadSessionCommand(nil, session)
+	case "/takeover":
+		h.handleTakeoverCommand(args, session)
+	case "/release":
+		h.handleReleaseCommand(session)
+	case "/invite":
+		h.handleInviteCommand(args, session)
+	case "/bulkinvite":
+		h.handleBulkInviteCommand(text, nil, session)
+	case "/invitations":
+		h.handleInvitationsCommand(args, session)
+	case "/similar":
+		h.handleSimilarCommand(args, session)
+	case "/search":
+		h.handleSearchCommand(args, session)
+	case "/reloadconfig":
+		h.handleReloadConfigCommand(session)
+	case "/exportsheet":
+		h.handleExportSheetCommand(session)
+	case "/researchexport":
+		h.handleResearchExportCommand(session)
+	case "/premium":
+		h.handlePremiumCommand(session)
+	case "/persona":
+		h.handlePersonaCommand(args, session)
+	case "/memory":
+		h.handleMemoryCommand(args, session)
+	case "/cv":
+		h.handleCVCommand(args, session)
+	case "/link":
+		h.handleLinkCommand(args, session)
+	case "/dropoff":
+		h.handleDropoffCommand(args, session)
+	case "/maintenance":
+		h.handleMaintenanceCommand(args, session)
 	default:
-		h.bot.SendMessage(chatID, "Неизвестная команда. Используйте /help для получения списка команд.")
+		h.reply(session, "Неизвестная команда. Используйте /help для получения списка команд.")
 	}
 }
 
-// handleStartCommand обрабатывает команду /start
-func (h *Handler) handleStartCommand(chatID int64, session *UserSession) {
-	if session.State == StateInterview || session.State == StateWaitingAnswer {
-		h.bot.SendMessage(chatID, "У вас уже идет интервью. Используйте /status для проверки прогресса или /restart для начала нового интервью.")
+// parseCommand разделяет текст на команду (без суффикса @botname) и аргументы
+func parseCommand(text string) (string, []string) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return "", nil
+	}
+
+	command := fields[0]
+	if at := strings.Index(command, "@"); at > 0 {
+		command = command[:at]
+	}
+	return strings.ToLower(command), fields[1:]
+}
+
+// handleStartCommand обрабатывает команду /start [payload]
+func (h *Handler) handleStartCommand(args []string, session *UserSession) {
+	if session.isActive() {
+		h.offerResume(session, args)
+		return
+	}
+
+	// В группе одна тема - одно интервью
+	if session.IsGroup && h.threadOwnedByOther(session) {
+		h.reply(session, "🙏 В этой теме уже идет интервью с другим участником. Дождитесь его завершения или начните интервью в другой теме.")
+		return
+	}
+
+	// В режиме обслуживания новые интервью не начинаются, до согласия дело не доходит
+	if h.InMaintenance() {
+		h.deferInterview(session, deferredMaintenance)
+		h.reply(session, maintenanceMessage)
+		return
+	}
+
+	// Deep link t.me/<bot>?start=<payload> приходит как /start <payload>
+	args = h.resolveTenantLink(args, session)
+	invitation, ok := h.resolveInvitation(args, session)
+	if !ok {
+		return
+	}
+
+	// Без согласия на обработку данных интервью не начинается
+	if !h.hasConsent(session) {
+		h.requestConsent(session, invitation)
 		return
 	}
 
 	// Инициализируем новое интервью
-	h.initializeInterview(chatID, session)
+	h.initializeInterview(session, invitation)
 }
 
 // handleHelpCommand обрабатывает команду /help
-func (h *Handler) handleHelpCommand(chatID int64) {
+func (h *Handler) handleHelpCommand(session *UserSession) {
 	helpText := `🤖 *Бот-интервьюер с анализом профиля*
 
 *Команды:*
@@ -235,8 +556,21 @@ func (h *Handler) handleHelpCommand(chatID int64) {
 /status - Проверить прогресс текущего интервью
 /restart - Перезапустить интервью
 /stop - Остановить текущее интервью
+/pause - Поставить интервью на паузу (без напоминаний)
+/skip - Пропустить текущий вопрос
 /getprofile - Получить JSON файл профиля (после завершения)
+/getprofile combined - Сводный профиль по всем интервью
 /getsummary - Получить краткое резюме профиля (после завершения)
+/summary <формат> - Резюме в формате bullets, narrative или table
+/history - Прошлые интервью с профилями и резюме
+/edit N - Исправить ответ на вопрос N текущего блока
+/persona - Выбрать стиль интервьюера
+/memory - Учитывать прошлые интервью в вопросах (если доступно)
+/cv - Резюме (PDF или DOCX) для предзаполнения профиля
+/link <код> - Привязать Telegram к учетной записи HR-системы
+/transcript - Получить стенограмму интервью файлом
+/ask - Задать вопросы о своем профиле (после завершения)
+/premium - Расширенный анализ: отчет, архетип и PDF (если доступен)
 /help - Показать это сообщение
 
 *Как это работает:*
@@ -256,345 +590,541 @@ func (h *Handler) handleHelpCommand(chatID int64) {
 • После завершения интервью профиль отправляется как JSON файл
 • Используйте /getprofile для повторного получения файла
 • Используйте /getsummary для краткого резюме
+• /summary narrative или /summary table - резюме в другом формате
 
 *Совет:* Чем подробнее ваши ответы, тем точнее будет профиль!`
 
-	maxQuestions := h.config.GetQuestionsPerBlock() + h.config.GetMaxFollowupQuestions()
-	h.bot.SendFormattedMessage(chatID, helpText, h.config.GetTotalBlocks(), maxQuestions)
+	cfg := h.configFor(session)
+	maxQuestions := cfg.GetQuestionsPerBlock() + cfg.GetMaxFollowupQuestions()
+	h.replyf(session, helpText, cfg.GetTotalBlocks(), maxQuestions)
 }
 
 // handleStatusCommand показывает статус интервью
-func (h *Handler) handleStatusCommand(chatID int64, session *UserSession) {
+func (h *Handler) handleStatusCommand(session *UserSession) {
 	switch session.State {
 	case StateIdle:
-		h.bot.SendMessage(chatID, "Интервью не начато. Используйте /start для начала.")
-	case StateInterview, StateWaitingAnswer:
-		progress := fmt.Sprintf("📊 *Прогресс интервью*\n\n"+
+		h.reply(session, "Интервью не начато. Используйте /start для начала.")
+	case StateAwaitingConsent:
+		h.reply(session, "Интервью начнется после согласия на обработку данных. Нажмите кнопку под соглашением или используйте /start, чтобы показать его снова.")
+	case StateInterview, StateWaitingAnswer, StateEditingAnswer, StateReviewingBlock:
+		progress := h.engine.Progress(&session.Session)
+		status := fmt.Sprintf("📊 *Прогресс интервью*\n\n"+
+			"%s\n\n"+
 			"🆔 ID: `%s`\n"+
 			"📋 Блок: %d/%d (%s)\n"+
-			"❓ Вопросов в блоке: %d\n"+
+			"❓ Вопрос: %d из %d\n"+
+			"⏱ Осталось: %s\n"+
 			"⏰ Состояние: %s",
+			progressBar(progress.Block, progress.TotalBlocks),
 			session.InterviewID,
-			session.CurrentBlock, h.config.GetTotalBlocks(),
-			h.getCurrentBlockTitle(session.CurrentBlock),
-			session.QuestionCount,
+			progress.Block, progress.TotalBlocks,
+			h.getCurrentBlockTitle(session),
+			progress.Question, progress.BlockQuestions,
+			formatRemaining(progress),
 			h.getStateDescription(session.State))
-		h.bot.SendMessage(chatID, progress)
-	case StateCompleted:
-		h.bot.SendFormattedMessage(chatID, "✅ Интервью завершено!\n🆔 ID: `%s`\n\n_Используйте /getprofile для получения JSON файла профиля_", session.InterviewID)
+		h.reply(session, status)
+	case StateCompleted, StateProfileReady, StateAskingProfile:
+		h.replyf(session, "✅ Интервью завершено!\n🆔 ID: `%s`\n\n_Используйте /getprofile для получения JSON файла профиля_", session.InterviewID)
+	case StateExtracting:
+		h.replyf(session, "✅ Интервью завершено!\n🆔 ID: `%s`\n\n⏳ Профиль составляется.", session.InterviewID)
+	case StateExtractionFailed:
+		h.replyf(session, "✅ Интервью завершено!\n🆔 ID: `%s`\n\n⚠️ Профиль пока не удалось составить. Используйте /getprofile.", session.InterviewID)
 	}
 }
 
 // handleRestartCommand перезапускает интервью
-func (h *Handler) handleRestartCommand(chatID int64, session *UserSession) {
+func (h *Handler) handleRestartCommand(session *UserSession) {
+	h.recordDropoff(session, storage.DropoffRestarted)
+	h.releaseInterviewSlot(session)
 	h.resetSession(session)
-	h.bot.SendMessage(chatID, "🔄 Интервью сброшено. Используйте /start для начала нового интервью.")
+	h.reply(session, "🔄 Интервью сброшено. Используйте /start для начала нового интервью.")
 }
 
 // handleStopCommand останавливает интервью
-func (h *Handler) handleStopCommand(chatID int64, session *UserSession) {
+func (h *Handler) handleStopCommand(session *UserSession) {
 	if session.State == StateIdle {
-		h.bot.SendMessage(chatID, "Интервью не запущено.")
+		h.reply(session, "Интервью не запущено.")
 		return
 	}
 
-	h.resetSession(session)
-	h.bot.SendMessage(chatID, "🛑 Интервью остановлено.")
+	if session.State == StateAskingProfile {
+		session.State = StateProfileReady
+		h.reply(session, "👌 Вы вышли из режима вопросов о профиле. Используйте /ask, чтобы вернуться.")
+		return
+	}
+
+	h.abandonInterview(session, storage.DropoffStopped, "🛑 Интервью остановлено.")
 }
 
-// handleGetProfileCommand получает профиль по команде
-func (h *Handler) handleGetProfileCommand(chatID int64, session *UserSession) {
-	if session.State != StateCompleted || session.InterviewID == "" {
-		h.bot.SendMessage(chatID, "❌ Профиль доступен только после завершения интервью. Используйте /start для начала нового интервью.")
+// handleGetProfileCommand получает профиль по команде; /getprofile combined - сводный профиль по всем интервью
+func (h *Handler) handleGetProfileCommand(args []string, session *UserSession) {
+	if len(args) > 0 && strings.ToLower(args[0]) == "combined" {
+		h.handleCombinedProfileCommand(session)
+		return
+	}
+	if !session.isCompleted() || session.InterviewID == "" {
+		h.reply(session, "❌ Профиль доступен только после завершения интервью. Используйте /start для начала нового интервью.")
+		return
+	}
+	if h.replyProfilePending(session) {
 		return
 	}
 
-	// Путь к файлу профиля
-	fileName := fmt.Sprintf("output/profile_%s.json", session.InterviewID)
+	h.sendProfileOf(session, session.InterviewID)
+}
 
-	// Проверяем существование файла
-	if _, err := os.Stat(fileName); os.IsNotExist(err) {
-		h.bot.SendMessage(chatID, "❌ Файл профиля не найден. Возможно, он еще не был создан или был удален.")
+// sendProfileOf отправляет JSON файл профиля интервью
+func (h *Handler) sendProfileOf(session *UserSession, interviewID string) {
+	// Ищем файл профиля по шаблону имени
+	fileName, err := storage.FindProfile(interviewID, 1)
+	if err != nil {
+		h.reply(session, "❌ Файл профиля не найден. Возможно, он еще не был создан или был удален.")
 		return
 	}
 
-	h.bot.SendMessage(chatID, "📤 Отправляю ваш JSON профиль...")
-	h.sendJSONFile(chatID, fileName, session.InterviewID)
+	h.reply(session, "📤 Отправляю ваш JSON профиль...")
+	h.sendJSONProfile(session, fileName, interviewID)
 }
 
 // handleGetSummaryCommand получает краткое резюме по команде
-func (h *Handler) handleGetSummaryCommand(chatID int64, session *UserSession) {
-	if session.State != StateCompleted || session.InterviewID == "" {
-		h.bot.SendMessage(chatID, "❌ Резюме доступно только после завершения интервью. Используйте /start для начала нового интервью.")
+func (h *Handler) handleGetSummaryCommand(session *UserSession) {
+	if !session.isCompleted() || session.InterviewID == "" {
+		h.reply(session, "❌ Резюме доступно только после завершения интервью. Используйте /start для начала нового интервью.")
 		return
 	}
-
-	// Загружаем профиль из файла
-	fileName := fmt.Sprintf("output/profile_%s.json", session.InterviewID)
-	profileData, err := os.ReadFile(fileName)
-	if err != nil {
-		h.bot.SendMessage(chatID, "❌ Профиль не найден. Возможно, он еще не был создан или файл был удален.")
+	if h.replyProfilePending(session) {
 		return
 	}
+	h.replyProfileSummary(session, session.InterviewID)
+}
 
-	// Получаем краткое резюме
+// replyProfileSummary отправляет краткое резюме профиля интервью
+func (h *Handler) replyProfileSummary(session *UserSession, interviewID string) {
 	if h.extractor != nil {
-		summary, err := h.extractor.GetProfileSummary(string(profileData))
+		// Берем профиль из кэша экстрактора (с откатом на сохраненный файл)
+		profileJSON, err := h.extractor.GetLastProfileJSON(interviewID)
 		if err != nil {
-			h.bot.SendMessage(chatID, "❌ Ошибка создания резюме: "+err.Error())
+			h.reply(session, "❌ Профиль не найден. Возможно, он еще не был создан или файл был удален.")
 			return
 		}
 
-		resultMessage := fmt.Sprintf(`🎯 *Краткое резюме профиля:*
+		texts := summaryLocaleFor(h.configFor(session).Language())
+		resultMessage := NewRichText().Bold(texts.Title).Text("\n\n")
+		if err := writeProfileSummary(resultMessage, profileJSON); err != nil {
+			h.replyError(session, apperr.Wrap(apperr.ErrStorage, err))
+			return
+		}
 
-%s
+		// /getprofile отправляет профиль текущего интервью, прошлые доступны из /history
+		fileHint := texts.FileHint
+		if interviewID != session.InterviewID {
+			fileHint = texts.HistoryHint
+		}
+		resultMessage.Text("\n" + texts.SavedFull + "\n\n").Italic(fileHint)
+		h.replyRich(session, resultMessage)
+	} else {
+		h.reply(session, "❌ Сервис анализа профилей недоступен.")
+	}
+}
 
-💾 Полный профиль сохранен в JSON файле
+// validateUserInput проверяет сообщение по правилам validation шаблона. question - номер вопроса
+// текущего блока (с нуля), на который дан ответ; -1 - сообщение не является ответом интервью
+// (вопрос о профиле), для него не действует минимальная длина.
+func (h *Handler) validateUserInput(session *UserSession, text string, question int) error {
+	if question < 0 {
+		rules := h.configFor(session).AnswerRules(0, 0)
+		rules.MinLength = 0
+		return answercheck.Check(rules, text)
+	}
+	return answercheck.Check(h.configFor(session).AnswerRules(session.CurrentBlock, question), text)
+}
 
-_Используйте /getprofile для получения файла_`, summary)
+// handleUserInput обрабатывает ответы пользователя
+func (h *Handler) handleUserInput(text string, session *UserSession) {
+	if h.redeliverPending(session) {
+		return
+	}
 
-		h.bot.SendMessage(chatID, resultMessage)
-	} else {
-		h.bot.SendMessage(chatID, "❌ Сервис анализа профилей недоступен.")
+	if session.State == StateEditingAnswer {
+		h.applyAnswerEdit(text, session)
+		return
 	}
-}
 
-// Улучшенная валидация пользовательского ввода
-func (h *Handler) validateUserInput(text string) error {
-	if len(text) > 4000 {
-		return fmt.Errorf("сообщение слишком длинное (максимум 4000 символов)")
+	if session.State == StateAskingProfile {
+		h.askProfileQuestion(text, session)
+		return
 	}
 
-	// Проверка на спам/повторяющиеся символы
-	if len(text) > 10 && strings.Count(text, text[:1]) > len(text)*8/10 {
-		return fmt.Errorf("сообщение содержит слишком много повторяющихся символов")
+	if session.State == StateAwaitingConsent {
+		h.reply(session, "Пожалуйста, нажмите «Согласен» или «Отказаться» под сообщением о согласии.")
+		return
 	}
 
-	return nil
-}
+	if session.State == StateReviewingBlock {
+		h.reply(session, "Пожалуйста, проверьте ответы блока и нажмите «Все верно» или «Исправить N» под списком.")
+		return
+	}
 
-// handleUserInput обрабатывает ответы пользователя
-func (h *Handler) handleUserInput(chatID int64, text string, session *UserSession) {
 	if session.State != StateWaitingAnswer {
-		h.bot.SendMessage(chatID, "Сейчас не время для ответов. Используйте /start для начала интервью или /help для помощи.")
+		h.reply(session, "Сейчас не время для ответов. Используйте /start для начала интервью или /help для помощи.")
 		return
 	}
 
-	// Валидация ввода
-	if err := h.validateUserInput(text); err != nil {
-		h.bot.SendMessage(chatID, "❌ "+err.Error())
+	// Валидация ввода; ответ на вопрос о необязательном блоке - не ответ интервью
+	question := session.QuestionCount
+	if session.Phase == engine.PhaseBlockOptIn {
+		question = -1
+	}
+	if err := h.validateUserInput(session, text, question); err != nil {
+		h.reply(session, "❌ "+err.Error())
 		return
 	}
 
 	// Обновляем активность сессии
 	session.LastActivity = time.Now()
 
-	h.processUserAnswer(chatID, text, session)
+	h.moderateAnswer(session, text)
+	h.rememberAnswerMessage(session)
+	h.engine.RecordAnswerMessage(&session.Session, session.LastMessageID)
+	h.processUserAnswer(text, session)
 }
 
-// initializeInterview инициализирует новое интервью
-func (h *Handler) initializeInterview(chatID int64, session *UserSession) {
+// initializeInterview инициализирует новое интервью по шаблону из приглашения (или по умолчанию)
+func (h *Handler) initializeInterview(session *UserSession, invitation *invite.Invitation) {
+	if !h.admitInterview(session) {
+		return
+	}
+
 	// Сбрасываем сессию
 	h.resetSession(session)
+	if session.IsGroup {
+		h.claimThread(session)
+	}
+	t := h.tenantFor(session)
+	templateID := ""
+	if invitation != nil {
+		templateID = invitation.TemplateID
+	} else if t != nil {
+		templateID = t.Template
+	}
 
 	// Создаем новое интервью
-	session.InterviewID = uuid.New().String()
+	session.Session = *h.engine.NewInterview(session.UserID, templateID)
+	h.engine.SetLanguage(&session.Session, session.LanguageCode)
+	h.engine.SetPersona(&session.Session, h.configFor(session).MatchPersona(session.PreferredPersona))
+	session.Result.Consent = session.Consent
+	if t != nil {
+		session.Result.Tenant = t.ID
+	}
+	h.publishEvent(session, events.Event{Kind: events.InterviewStarted})
+	h.observe(session, "▶️", "начато интервью")
+	session.ReviewBlocks = h.blockReview
 	session.State = StateInterview
-	session.CurrentBlock = 1
-	session.QuestionCount = 0
 	session.LastActivity = time.Now()
-	session.Result = &storage.InterviewResult{
-		InterviewID: session.InterviewID,
-		Timestamp:   time.Now().Format(time.RFC3339),
-		Blocks:      make([]storage.BlockResult, 0, h.config.GetTotalBlocks()),
-	}
+	cfg := h.configFor(session)
+	h.recordInvitation(session, invitation)
 
 	// Отправляем приветствие
-	welcomeText := fmt.Sprintf(`🎯 *Добро пожаловать в интервью!*
-
-🆔 *ID интервью:* `+"`%s`"+`
-📋 *Всего блоков:* %d
-❓ *Вопросов в блоке:* до %d
-⏱ *Время:* ~%d минут
-
-*Правила:*
-• Отвечайте честно и подробно
-• Можете отвечать в несколько сообщений
-• Используйте /status для проверки прогресса
-• Используйте /stop для остановки
+	welcomeText := cfg.WelcomeMessage(session.InterviewID)
 
-Готовы начать? Сейчас начнется первый блок! 🚀`,
-		session.InterviewID,
-		h.config.GetTotalBlocks(),
-		h.config.GetQuestionsPerBlock()+h.config.GetMaxFollowupQuestions(),
-		h.config.GetTotalBlocks()*3)
-
-	h.bot.SendMessage(chatID, welcomeText)
+	if name := h.personaName(session); name != "" {
+		welcomeText += fmt.Sprintf("\n\n🎙 *Интервьюер:* %s (сменить: /persona)", name)
+	}
+	if note := h.attachMemory(session); note != "" {
+		welcomeText += "\n\n" + note
+	}
+	if note := h.attachResume(session); note != "" {
+		welcomeText += "\n\n" + note
+	}
+	if t != nil && t.Welcome != "" {
+		welcomeText = strings.TrimSpace(t.Welcome) + "\n\n" + welcomeText
+	}
+	h.reply(session, welcomeText)
 
 	// Начинаем первый блок
-	h.startNextBlock(chatID, session)
+	prompt, events, err := h.engine.Start(context.Background(), &session.Session)
+	h.deliverStep(session, prompt, events, err)
 }
 
-// processUserAnswer обрабатывает ответ пользователя
-func (h *Handler) processUserAnswer(chatID int64, answer string, session *UserSession) {
-	// Добавляем ответ в текущий диалог (последний вопрос)
-	if len(session.CurrentDialogue) > 0 {
-		lastIndex := len(session.CurrentDialogue) - 1
-		session.CurrentDialogue[lastIndex].Answer = answer
-	}
-
-	session.QuestionCount++
-	maxQuestions := h.config.GetQuestionsPerBlock() + h.config.GetMaxFollowupQuestions()
-
-	// Проверяем, нужен ли следующий вопрос в блоке
-	if session.QuestionCount < maxQuestions {
-		// Генерируем следующий вопрос
-		h.generateNextQuestion(chatID, session)
-	} else {
-		// Завершаем блок
-		h.finishCurrentBlock(chatID, session)
+// processUserAnswer передает ответ пользователя движку интервью
+func (h *Handler) processUserAnswer(answer string, session *UserSession) {
+	if session.Phase == engine.PhaseBlockPending {
+		h.reply(session, "📝 Обрабатываю блок...")
 	}
+	h.mirrorToSupervisor(session, "👤 "+markdownEscaper.Replace(answer))
+	h.observe(session, "👤", answer)
+	prompt, events, err := h.engine.Advance(context.Background(), &session.Session, answer)
+	h.deliverStep(session, prompt, events, err)
 }
 
-// generateNextQuestion генерирует следующий вопрос
-func (h *Handler) generateNextQuestion(chatID int64, session *UserSession) {
-	block := h.config.Blocks[session.CurrentBlock-1]
-
-	if session.QuestionCount >= len(block.Questions) {
-		h.finishCurrentBlock(chatID, session)
-		return
+// deliverStep отправляет пользователю события шага интервью и следующий вопрос
+func (h *Handler) deliverStep(session *UserSession, prompt *engine.Prompt, steps []engine.Event, err error) {
+	cfg := h.configFor(session)
+	for _, event := range steps {
+		switch event.Kind {
+		case engine.EventBlockStarted:
+			h.replyf(session, "📋 *Блок %d/%d: %s*\n\nСейчас мы поговорим о %s",
+				event.Block, cfg.GetTotalBlocks(), event.BlockTitle, strings.ToLower(event.BlockTitle))
+		case engine.EventBlockDeclined:
+			h.observe(session, "⏭", fmt.Sprintf("блок %d «%s» отклонен пользователем", event.Block, event.BlockTitle))
+			h.replyf(session, "⏭ Тема «%s» пропущена.", event.BlockTitle)
+		case engine.EventBlockReview:
+			session.State = StateReviewingBlock
+			h.sendBlockReview(session)
+		case engine.EventBlockFinished:
+			h.publishEvent(session, events.Event{Kind: events.BlockCompleted, Block: event.Block, BlockTitle: event.BlockTitle})
+			h.observe(session, "✅", fmt.Sprintf("блок %d «%s» завершен", event.Block, event.BlockTitle))
+			h.replyf(session, "✅ Блок %d завершен! Переходим к следующему...", event.Block)
+		case engine.EventInterviewCompleted:
+			h.completeInterview(session)
+		}
 	}
 
-	question := block.Questions[session.QuestionCount]
-
-	// Добавляем вопрос в диалог
-	session.CurrentDialogue = append(session.CurrentDialogue, storage.QA{
-		Question: question,
-		Answer:   "", // Будет заполнен при получении ответа
-	})
+	var typeErr *engine.AnswerTypeError
+	switch {
+	case errors.Is(err, engine.ErrBusy):
+		h.reply(session, "⏳ Сервис перегружен, попробуйте ответить еще раз чуть позже.")
+	case errors.Is(err, engine.ErrInvalidChoice):
+		h.reply(session, "❌ Выберите, пожалуйста, один из вариантов ответа.")
+	case errors.As(err, &typeErr):
+		h.reply(session, "❌ "+typeErr.Error())
+	case errors.Is(err, engine.ErrSummary):
+		h.logger(session).Error("Ошибка завершения блока", "error", err)
+		h.reply(session, "Ошибка при создании саммари блока. Отправьте любое сообщение, чтобы повторить.")
+	case errors.Is(err, engine.ErrSave):
+		h.metrics.RecordError("storage", err.Error())
+		h.reply(session, "Ошибка сохранения результата интервью. Отправьте любое сообщение, чтобы повторить.")
+	case err != nil:
+		h.replyError(session, err)
+	}
 
-	session.State = StateWaitingAnswer
-	h.bot.SendFormattedMessage(chatID, "❓ *Вопрос %d:*\n\n%s", session.QuestionCount+1, question)
+	if prompt != nil {
+		session.State = StateWaitingAnswer
+		h.sendPrompt(session, prompt)
+	}
 }
 
-// startNextBlock начинает следующий блок
-func (h *Handler) startNextBlock(chatID int64, session *UserSession) {
-	if session.CurrentBlock > h.config.GetTotalBlocks() {
-		h.completeInterview(chatID, session)
-		return
+// sendPrompt отправляет вопрос или уточнение
+func (h *Handler) sendPrompt(session *UserSession, prompt *engine.Prompt) {
+	session.QuestionSentAt = time.Now()
+	session.Nudges = 0
+	session.Paused = false
+	h.mirrorToSupervisor(session, "🤖 "+markdownEscaper.Replace(prompt.Text))
+	h.observe(session, "🤖", prompt.Text)
+	var messageID int
+	var err error
+	switch prompt.Kind {
+	case engine.PromptClarification:
+		messageID, err = h.sendOpenPrompt(session, "🔎 "+prompt.Text)
+	case engine.PromptOptIn:
+		messageID, err = h.sendChoicePrompt(session, prompt, "🔀 *Необязательная тема*\n\n"+prompt.Text)
+	default:
+		text := fmt.Sprintf("%s\n\n❓ *Вопрос %d:*\n\n%s",
+			formatProgress(h.engine.Progress(&session.Session)), prompt.Number, prompt.Text)
+		if len(prompt.Options) > 0 {
+			messageID, err = h.sendChoicePrompt(session, prompt, text)
+		} else {
+			messageID, err = h.sendOpenPrompt(session, text)
+		}
 	}
+	h.markDelivery(session, err)
+	h.engine.RecordQuestionMessage(&session.Session, messageID)
+}
 
-	block := h.config.Blocks[session.CurrentBlock-1]
-	session.QuestionCount = 0
-	session.CurrentDialogue = []storage.QA{}
-
-	// Отправляем информацию о блоке
-	blockInfo := fmt.Sprintf("📋 *Блок %d/%d: %s*\n\nСейчас мы поговорим о %s",
-		session.CurrentBlock, h.config.GetTotalBlocks(), block.Title, strings.ToLower(block.Title))
-
-	h.bot.SendMessage(chatID, blockInfo)
+// Вспомогательные методы
 
-	// Генерируем первый вопрос блока
-	h.generateNextQuestion(chatID, session)
+// waitLLMBudget ожидает свободный токен в пользовательском и глобальном бюджете OpenAI
+func (h *Handler) waitLLMBudget(userID int64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), llmBudgetWaitTimeout)
+	defer cancel()
+	return h.llmLimiter.Wait(ctx, userID)
 }
 
-// finishCurrentBlock завершает текущий блок
-func (h *Handler) finishCurrentBlock(chatID int64, session *UserSession) {
-	h.bot.SendMessage(chatID, "📝 Обрабатываю блок...")
+// SetLogger задает логгер бота и движка интервью
+func (h *Handler) SetLogger(logger *slog.Logger) {
+	h.baseLogger = logger
+	h.engine.SetLogger(logger)
+}
 
-	block := h.config.Blocks[session.CurrentBlock-1]
+// logger возвращает логгер с атрибутами сессии: интервью, пользователь, чат и текущий блок
+func (h *Handler) logger(session *UserSession) *slog.Logger {
+	return logging.Interview(h.baseLogger, session.InterviewID, session.UserID, session.CurrentBlock).
+		With(logging.KeyChatID, session.ChatID)
+}
 
-	// Создаем результат блока
-	blockResult := &storage.BlockResult{
-		BlockID:             block.ID,
-		BlockName:           block.Name,
-		QuestionsAndAnswers: session.CurrentDialogue,
-	}
+// Engine возвращает движок интервью бота, чтобы другие фронтенды использовали общий бюджет OpenAI и метрики
+func (h *Handler) Engine() *engine.Engine {
+	return h.engine
+}
 
-	// Создаем саммари
-	summary, err := h.interviewer.CreateSummary(session.CurrentDialogue, h.config)
-	if err != nil {
-		h.bot.SendMessage(chatID, "Ошибка при создании саммари блока.")
-		return
+// lockSession возвращает сессию пользователя в чате, захватив ее блокировку: обновления
+// сессии, ее фоновые задачи и команды администраторов (/takeover) не выполняются одновременно.
+// С внешним хранилищем сессия блокируется и в нем и перечитывается (lockStored).
+// Блокировки снимает возвращенная функция.
+func (h *Handler) lockSession(chatID, userID int64) (*UserSession, func()) {
+	key := sessionKey{ChatID: chatID, UserID: userID}
+	for {
+		session := h.getOrCreateSession(chatID, userID)
+		session.mu.Lock()
+
+		// Пока ждали блокировку, сессию могла удалить очистка неактивных сессий
+		h.sessionsMutex.RLock()
+		current := h.sessions[key] == session
+		h.sessionsMutex.RUnlock()
+		if current {
+			return session, h.lockStored(session)
+		}
+		session.mu.Unlock()
 	}
+}
 
-	// Добавляем результат и саммари
-	session.Result.Blocks = append(session.Result.Blocks, *blockResult)
-	session.CumulativeSummaries = append(session.CumulativeSummaries, summary)
-
-	// Информируем о завершении блока
-	h.bot.SendFormattedMessage(chatID, "✅ Блок %d завершен! Переходим к следующему...", session.CurrentBlock)
+// relockSession захватывает блокировку сессии, полученной раньше (фоновой задачей), как lockSession
+func (h *Handler) relockSession(session *UserSession) func() {
+	session.mu.Lock()
+	return h.lockStored(session)
+}
 
-	// Переходим к следующему блоку
-	session.CurrentBlock++
-	h.startNextBlock(chatID, session)
+// lockStored добавляет к захваченной session.mu блокировку сессии во внешнем хранилище.
+// Если другая реплика держит сессию дольше sessionStoreLockWait, обработка продолжается без нее.
+// Возвращенная функция снимает обе блокировки.
+func (h *Handler) lockStored(session *UserSession) func() {
+	unlockStored, ok := h.lockStoredSession(session, sessionStoreLockWait)
+	if !ok {
+		h.logger(session).Warn("Сессия занята другой репликой, обработка продолжается без блокировки хранилища")
+	}
+	return func() {
+		unlockStored()
+		session.mu.Unlock()
+	}
 }
 
-// sendJSONFile отправляет JSON файл в чат
-func (h *Handler) sendJSONFile(chatID int64, fileName string, interviewID string) {
-	// Читаем содержимое файла
-	fileData, err := os.ReadFile(fileName)
-	if err != nil {
-		h.bot.SendMessage(chatID, "❌ Ошибка чтения файла: "+err.Error())
-		return
+// tryLockSession захватывает блокировку сессии для фоновой проверки, не дожидаясь ее:
+// сессию, занятую обработкой сообщения здесь или в другой реплике, проверит следующий тик
+func (h *Handler) tryLockSession(session *UserSession) (func(), bool) {
+	if !session.mu.TryLock() {
+		return nil, false
 	}
+	unlockStored, ok := h.lockStoredSession(session, 0)
+	if !ok {
+		session.mu.Unlock()
+		return nil, false
+	}
+	return func() {
+		unlockStored()
+		session.mu.Unlock()
+	}, true
+}
 
-	// Отправляем как документ через SendDocument API
-	err = h.bot.SendDocument(chatID, fileName, fileData, fmt.Sprintf("profile_%s.json", interviewID))
-	if err != nil {
-		h.bot.SendMessage(chatID, "❌ Ошибка отправки файла: "+err.Error())
-		return
+// lockWithin захватывает блокировку сессии другого пользователя, ожидая не дольше sessionLockWait.
+// false - сессия все это время занята: ожидание без предела могло бы зациклиться на
+// администраторах, обращающихся к сессиям друг друга.
+func (h *Handler) lockWithin(session *UserSession) (func(), bool) {
+	deadline := time.Now().Add(sessionLockWait)
+	for !session.mu.TryLock() {
+		if time.Now().After(deadline) {
+			return nil, false
+		}
+		time.Sleep(sessionLockPoll)
+	}
+	unlockStored, ok := h.lockStoredSession(session, time.Until(deadline))
+	if !ok {
+		session.mu.Unlock()
+		return nil, false
 	}
+	return func() {
+		unlockStored()
+		session.mu.Unlock()
+	}, true
+}
+
+// sessionList возвращает сессии в памяти; блокировки самих сессий не захватываются
+func (h *Handler) sessionList() []*UserSession {
+	h.sessionsMutex.RLock()
+	defer h.sessionsMutex.RUnlock()
 
-	h.bot.SendMessage(chatID, "✅ JSON профиль отправлен как файл!")
+	sessions := make([]*UserSession, 0, len(h.sessions))
+	for _, session := range h.sessions {
+		sessions = append(sessions, session)
+	}
+	return sessions
 }
 
-// Вспомогательные методы
-func (h *Handler) getOrCreateSession(userID int64) *UserSession {
+func (h *Handler) getOrCreateSession(chatID, userID int64) *UserSession {
 	h.sessionsMutex.Lock()
 	defer h.sessionsMutex.Unlock()
 
-	if session, exists := h.sessions[userID]; exists {
+	key := sessionKey{ChatID: chatID, UserID: userID}
+	if session, exists := h.sessions[key]; exists {
 		return session
 	}
 
 	session := &UserSession{
-		UserID:       userID,
+		Session:      engine.Session{UserID: userID},
+		ChatID:       chatID,
 		State:        StateIdle,
 		LastActivity: time.Now(),
+		mu:           new(sync.Mutex),
 	}
-	h.sessions[userID] = session
+	h.sessions[key] = session
 	return session
 }
 
 func (h *Handler) resetSession(session *UserSession) {
+	h.releaseThread(session)
+	h.endSupervision(session, "🛑 Интервью прервано, наблюдение окончено.")
+	if session.isActive() {
+		h.observe(session, "🛑", "интервью прервано")
+	}
 	session.State = StateIdle
-	session.CurrentBlock = 0
-	session.QuestionCount = 0
-	session.CurrentDialogue = []storage.QA{}
-	session.CumulativeSummaries = []string{}
-	session.Result = nil
-	session.InterviewID = ""
+	session.Session = engine.Session{UserID: session.UserID}
+	session.AskHistory = nil
+	session.PendingInvitation = nil
+	session.PendingStart = nil
+	session.LastAnswer = nil
+	session.PendingDelivery = false
+	session.ExtractionAttempts = 0
+	session.ExtractionStartedAt = time.Time{}
+	session.ExtractionRetryAt = time.Time{}
+	session.ExtractionError = ""
+	session.QuestionSentAt = time.Time{}
+	session.Nudges = 0
+	session.Paused = false
 	session.LastActivity = time.Now()
 }
 
-func (h *Handler) getCurrentBlockTitle(blockNum int) string {
-	if blockNum <= 0 || blockNum > len(h.config.Blocks) {
+func (h *Handler) getCurrentBlockTitle(session *UserSession) string {
+	blockNum := session.CurrentBlock
+	cfg := h.configFor(session)
+	if blockNum <= 0 || blockNum > len(cfg.Blocks) {
 		return "Неизвестный блок"
 	}
-	return h.config.Blocks[blockNum-1].Title
+	return cfg.Blocks[blockNum-1].Title
 }
 
 func (h *Handler) getStateDescription(state SessionState) string {
 	switch state {
 	case StateIdle:
 		return "Ожидание"
+	case StateAwaitingConsent:
+		return "Ожидание согласия"
 	case StateInterview:
 		return "Интервью"
 	case StateWaitingAnswer:
 		return "Ожидание ответа"
+	case StateEditingAnswer:
+		return "Исправление ответа"
+	case StateReviewingBlock:
+		return "Проверка ответов блока"
 	case StateCompleted:
 		return "Завершено"
+	case StateExtracting:
+		return "Составление профиля"
+	case StateProfileReady:
+		return "Профиль готов"
+	case StateExtractionFailed:
+		return "Ошибка составления профиля"
+	case StateAskingProfile:
+		return "Вопросы о профиле"
 	default:
 		return "Неизвестно"
 	}
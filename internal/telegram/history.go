@@ -0,0 +1,127 @@
+package telegram
+
+import (
+	"fmt"
+	"interview-bot-complete/internal/config"
+	"interview-bot-complete/internal/storage"
+	"strings"
+	"time"
+)
+
+// historyLimit - сколько последних интервью показывает /history
+const historyLimit = 10
+
+// Данные inline-кнопок истории: history:<profile|summary>:<id интервью>
+const (
+	historyCallbackPrefix = "history:"
+	historyProfile        = "profile"
+	historySummary        = "summary"
+)
+
+// userHistory возвращает интервью пользователя в этом боте: бот арендатора показывает только свои интервью
+func (h *Handler) userHistory(userID int64) ([]storage.UserInterview, error) {
+	interviews, err := storage.UserInterviews(userID)
+	if err != nil || h.tenant == nil {
+		return interviews, err
+	}
+	var own []storage.UserInterview
+	for _, interview := range interviews {
+		if interview.Tenant == h.tenant.ID {
+			own = append(own, interview)
+		}
+	}
+	return own, nil
+}
+
+// handleHistoryCommand обрабатывает /history: список прошлых интервью с кнопками профиля и резюме
+func (h *Handler) handleHistoryCommand(session *UserSession) {
+	interviews, err := h.userHistory(session.UserID)
+	if err != nil {
+		h.logger(session).Warn("Ошибка чтения истории интервью", "error", err)
+		h.reply(session, "❌ Не удалось загрузить историю интервью, попробуйте позже.")
+		return
+	}
+	if len(interviews) == 0 {
+		h.reply(session, "У вас пока нет сохраненных интервью. Используйте /start для начала интервью.")
+		return
+	}
+	if len(interviews) > historyLimit {
+		interviews = interviews[:historyLimit]
+	}
+
+	var text strings.Builder
+	text.WriteString("📚 *Ваши интервью:*\n\n")
+	keyboard := &InlineKeyboardMarkup{}
+	for n, interview := range interviews {
+		text.WriteString(fmt.Sprintf("%d. %s - %s\n", n+1, historyDate(interview.Timestamp), historyStatus(interview)))
+		templateID := interview.TemplateID
+		if templateID == "" {
+			templateID = config.DefaultTemplateID
+		}
+		text.WriteString(fmt.Sprintf("   шаблон `%s`\n", templateID))
+		if !interview.HasProfile {
+			continue
+		}
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, []InlineKeyboardButton{
+			{Text: fmt.Sprintf("%d · 📄 Профиль", n+1), CallbackData: historyCallbackPrefix + historyProfile + ":" + interview.InterviewID},
+			{Text: fmt.Sprintf("%d · 🎯 Резюме", n+1), CallbackData: historyCallbackPrefix + historySummary + ":" + interview.InterviewID},
+		})
+	}
+	if len(keyboard.InlineKeyboard) == 0 {
+		h.reply(session, text.String()+"\nПрофили по этим интервью еще не составлены.")
+		return
+	}
+	text.WriteString("\nВыберите интервью, чтобы получить профиль или резюме.")
+	if err := h.bot.SendReplyWithKeyboard(h.destination(session), text.String(), keyboard); err != nil {
+		h.logger(session).Warn("Не удалось отправить историю интервью", "error", err)
+	}
+}
+
+// historyDate возвращает дату интервью (ДД.ММ.ГГГГ) из метки RFC3339
+func historyDate(timestamp string) string {
+	date, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return "дата неизвестна"
+	}
+	return date.Format("02.01.2006")
+}
+
+// historyStatus описывает завершенность интервью
+func historyStatus(interview storage.UserInterview) string {
+	if interview.Partial {
+		return fmt.Sprintf("⏸ прервано, пройдено %.0f%%", interview.Completion*100)
+	}
+	return "✅ завершено"
+}
+
+// handleHistoryCallback отправляет профиль или резюме интервью из /history. Интервью проверяется
+// по истории нажавшего пользователя, чтобы кнопка из чужого сообщения не открыла чужой профиль.
+func (h *Handler) handleHistoryCallback(query *CallbackQuery, session *UserSession) {
+	parts := strings.SplitN(strings.TrimPrefix(query.Data, historyCallbackPrefix), ":", 2)
+	if len(parts) != 2 || !h.ownsInterview(query.From.ID, parts[1]) {
+		h.bot.AnswerCallbackQuery(query.ID, "Интервью не найдено")
+		return
+	}
+	h.bot.AnswerCallbackQuery(query.ID, "")
+
+	if parts[0] == historyProfile {
+		h.sendProfileOf(session, parts[1])
+		return
+	}
+	h.replyProfileSummary(session, parts[1])
+}
+
+// ownsInterview сообщает, есть ли интервью в истории пользователя
+func (h *Handler) ownsInterview(userID int64, interviewID string) bool {
+	interviews, err := h.userHistory(userID)
+	if err != nil {
+		h.baseLogger.Warn("Ошибка чтения истории интервью", "user_id", userID, "error", err)
+		return false
+	}
+	for _, interview := range interviews {
+		if interview.InterviewID == interviewID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package telegram
+
+import (
+	"context"
+	"interview-bot-complete/internal/config"
+	"interview-bot-complete/internal/invite"
+	"interview-bot-complete/internal/storage"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// configFor возвращает шаблон интервью сессии (по умолчанию - основной)
+func (h *Handler) configFor(session *UserSession) *config.Config {
+	return h.engine.Config(&session.Session)
+}
+
+// resolveInvitation проверяет приглашение из параметра /start.
+// Возвращает nil без ошибки, если интервью начинается без приглашения и это разрешено.
+func (h *Handler) resolveInvitation(args []string, session *UserSession) (*invite.Invitation, bool) {
+	if len(args) == 0 {
+		if h.requireInvite {
+			h.reply(session, "🔒 Интервью доступно только по персональной ссылке-приглашению.")
+			return nil, false
+		}
+		return nil, true
+	}
+
+	invitation, err := h.invites.Resolve(args[0], time.Now())
+	if err != nil {
+		h.logger(session).Info("Отклонено приглашение", "error", err)
+		h.reply(session, "❌ Ссылка-приглашение недействительна или устарела. Обратитесь к тому, кто ее прислал.")
+		return nil, false
+	}
+
+	if !h.bindInvitationTenant(session, invitation) {
+		h.reply(session, "❌ Ссылка-приглашение недействительна или устарела. Обратитесь к тому, кто ее прислал.")
+		return nil, false
+	}
+
+	if _, ok := h.templates.Get(invitation.TemplateID); !ok {
+		h.logger(session).Warn("Приглашение ссылается на неизвестный шаблон", "token", invitation.Token, "template_id", invitation.TemplateID)
+		h.reply(session, "❌ Шаблон интервью из приглашения не найден. Обратитесь к тому, кто прислал ссылку.")
+		return nil, false
+	}
+
+	return invitation, true
+}
+
+// recordInvitation сохраняет в результате и журнале, по какому приглашению начато интервью
+func (h *Handler) recordInvitation(session *UserSession, invitation *invite.Invitation) {
+	if invitation == nil {
+		return
+	}
+
+	record := storage.Invitation{
+		Token:     invitation.Token,
+		Source:    invitation.Source,
+		Candidate: invitation.Candidate,
+	}
+	session.Result.Invitation = &record
+
+	err := storage.RecordInvitationUse(storage.InvitationUse{
+		Invitation:  record,
+		InterviewID: session.InterviewID,
+		TemplateID:  session.TemplateID,
+		UserID:      session.UserID,
+		StartedAt:   session.Result.Timestamp,
+	})
+	if err != nil {
+		h.logger(session).Error("Ошибка записи журнала приглашений", "error", err)
+	}
+	h.trackInvitation(session, storage.InvitationStarted)
+}
+
+// handleInviteCommand выпускает подписанную ссылку-приглашение: /invite <template_id> [invite_id]
+func (h *Handler) handleInviteCommand(args []string, session *UserSession) {
+	if !h.requireAdmin(session) {
+		return
+	}
+
+	if len(args) == 0 {
+		h.replyf(session, "Использование: /invite <template_id> [invite_id]\nШаблоны: %s", strings.Join(h.templates.IDs(), ", "))
+		return
+	}
+
+	templateID := args[0]
+	if _, ok := h.templates.Get(templateID); !ok {
+		h.replyf(session, "❌ Шаблон %q не найден. Доступные: %s", templateID, strings.Join(h.templates.IDs(), ", "))
+		return
+	}
+
+	inviteID := uuid.New().String()[:8]
+	if len(args) > 1 {
+		inviteID = args[1]
+	}
+
+	payload, err := h.invites.Sign(templateID, inviteID)
+	if err != nil {
+		h.reply(session, "❌ "+err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	me, err := h.bot.GetMe(ctx)
+	if err != nil {
+		h.reply(session, "❌ Не удалось получить имя бота: "+err.Error())
+		return
+	}
+
+	h.replyf(session, "✉️ Приглашение `%s` (шаблон `%s`):\n`%s`", inviteID, templateID, invite.Link(me.Username, payload))
+}
@@ -0,0 +1,63 @@
+package telegram
+
+import (
+	"errors"
+	"interview-bot-complete/internal/storage"
+	"time"
+)
+
+const (
+	// maxLinkAttempts - столько неверных кодов /link пользователь может ввести до блокировки
+	maxLinkAttempts = 5
+	// linkLockout - окно, за которое считаются неверные коды, и срок блокировки
+	linkLockout = time.Hour
+)
+
+// handleLinkCommand обрабатывает команду /link <код>: привязывает пользователя к сотруднику или
+// кандидату во внешней HR-системе по одноразовому коду из API (POST /api/link-codes).
+// Профили привязанного пользователя выгружаются с его внешним идентификатором.
+func (h *Handler) handleLinkCommand(args []string, session *UserSession) {
+	if len(args) == 0 {
+		externalID, err := storage.ExternalIDOf(session.UserID)
+		if err != nil {
+			h.logger(session).Warn("Ошибка чтения привязки к HR-системе", "error", err)
+			h.reply(session, "❌ Не удалось загрузить привязку, попробуйте позже.")
+			return
+		}
+		if externalID == "" {
+			h.reply(session, "🔗 Ваш Telegram не привязан к учетной записи HR-системы.\n\nЕсли вам прислали код привязки, отправьте: /link <код>")
+			return
+		}
+		h.replyf(session, "🔗 Ваш Telegram привязан к учетной записи *%s*. Профили интервью выгружаются с этим идентификатором.\n\n"+
+			"Чтобы сменить привязку, отправьте новый код: /link <код>", markdownEscaper.Replace(externalID))
+		return
+	}
+
+	// Попытка расходует квоту заранее: одновременные /link не должны обойти блокировку.
+	// Успешная привязка и сбои хранилища квоту возвращают - считаются только неверные коды.
+	if allowed, _ := h.linkAttempts.Use(session.UserID); !allowed {
+		h.replyf(session, "⛔ Слишком много неверных кодов. Попробуйте снова через %s или запросите новый код привязки.",
+			formatWait(h.linkAttempts.ResetIn(session.UserID)))
+		return
+	}
+	externalID, err := storage.RedeemLinkCode(args[0], session.UserID)
+	if err == nil || !(errors.Is(err, storage.ErrLinkCodeNotFound) || errors.Is(err, storage.ErrLinkCodeUsed) ||
+		errors.Is(err, storage.ErrLinkCodeExpired)) {
+		h.linkAttempts.Refund(session.UserID)
+	}
+	switch {
+	case errors.Is(err, storage.ErrLinkCodeNotFound):
+		h.reply(session, "❌ Код не найден. Проверьте, что он введен без ошибок, или запросите новый.")
+	case errors.Is(err, storage.ErrLinkCodeUsed):
+		h.reply(session, "❌ Этот код уже использован. Запросите новый код привязки.")
+	case errors.Is(err, storage.ErrLinkCodeExpired):
+		h.reply(session, "⌛ Срок действия кода истек. Запросите новый код привязки.")
+	case err != nil:
+		h.logger(session).Error("Не удалось привязать пользователя к HR-системе", "error", err)
+		h.reply(session, "❌ Не удалось выполнить привязку, попробуйте позже.")
+	default:
+		h.logger(session).Info("Пользователь привязан к HR-системе", "external_id", externalID)
+		h.replyf(session, "✅ Готово! Ваш Telegram привязан к учетной записи *%s*. Профили интервью будут выгружаться с этим идентификатором.",
+			markdownEscaper.Replace(externalID))
+	}
+}
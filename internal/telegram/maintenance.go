@@ -0,0 +1,84 @@
+package telegram
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// drainPollInterval - как часто при остановке проверять, завершились ли извлечения профилей
+const drainPollInterval = 500 * time.Millisecond
+
+// maintenanceMessage - ответ на попытку начать интервью в режиме обслуживания
+const maintenanceMessage = "🛠 Бот ненадолго на обслуживании, поэтому новые интервью сейчас не начинаются. " +
+	"Пожалуйста, попробуйте /start через несколько минут — спасибо за терпение!"
+
+// maintenanceMode - режим обслуживания для безопасного деплоя: новые интервью не начинаются,
+// идущие доигрываются. Общий для основного бота и ботов арендаторов.
+type maintenanceMode struct {
+	enabled     atomic.Bool
+	extractions atomic.Int64 // идущие извлечения профилей
+}
+
+// SetMaintenance включает или выключает режим обслуживания
+func (h *Handler) SetMaintenance(enabled bool) {
+	h.maintenance.enabled.Store(enabled)
+}
+
+// InMaintenance сообщает, что включен режим обслуживания
+func (h *Handler) InMaintenance() bool {
+	return h.maintenance.enabled.Load()
+}
+
+// trackExtraction учитывает идущее извлечение профиля до вызова возвращенной функции
+func (h *Handler) trackExtraction() func() {
+	h.maintenance.extractions.Add(1)
+	return func() { h.maintenance.extractions.Add(-1) }
+}
+
+// DrainExtractions ждет завершения идущих извлечений профилей всех ботов или отмены ctx
+// и возвращает число незавершенных. Их сессии остаются в StateExtracting, и извлечение
+// возобновляется после перезапуска.
+func (h *Handler) DrainExtractions(ctx context.Context) int {
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+	for {
+		pending := int(h.maintenance.extractions.Load())
+		if pending == 0 {
+			return 0
+		}
+		select {
+		case <-ctx.Done():
+			return pending
+		case <-ticker.C:
+		}
+	}
+}
+
+// handleMaintenanceCommand обрабатывает команду /maintenance [on|off]
+func (h *Handler) handleMaintenanceCommand(args []string, session *UserSession) {
+	if !h.requireAdmin(session) {
+		return
+	}
+
+	if len(args) > 0 {
+		switch strings.ToLower(args[0]) {
+		case "on":
+			h.SetMaintenance(true)
+		case "off":
+			h.SetMaintenance(false)
+		default:
+			h.reply(session, "❌ Используйте /maintenance on или /maintenance off.")
+			return
+		}
+		h.logger(session).Info("Режим обслуживания изменен", "enabled", h.InMaintenance())
+	}
+
+	status := "выключен: интервью начинаются как обычно"
+	if h.InMaintenance() {
+		status = "включен: новые интервью не начинаются, идущие продолжаются"
+	}
+	h.replyf(session, "🛠 *Режим обслуживания* %s.\n\n🟢 Идет интервью: %d\n🧠 Извлекается профилей: %d\n\nВключить: /maintenance on\nВыключить: /maintenance off",
+		status, h.interviewSlots.Active(), h.maintenance.extractions.Load())
+}
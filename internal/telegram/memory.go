@@ -0,0 +1,106 @@
+package telegram
+
+import (
+	"interview-bot-complete/internal/storage"
+	"strings"
+)
+
+// handleMemoryCommand показывает (/memory) или меняет (/memory on|off) согласие на учет
+// прошлых интервью: интервьюер видит профиль последнего интервью и может на него ссылаться
+func (h *Handler) handleMemoryCommand(args []string, session *UserSession) {
+	if !h.memory {
+		h.reply(session, "Память интервьюера в этом боте отключена.")
+		return
+	}
+
+	if len(args) == 0 {
+		consent, err := storage.MemoryConsentOf(session.UserID)
+		if err != nil {
+			h.logger(session).Warn("Ошибка чтения согласия на память интервьюера", "error", err)
+			h.reply(session, "❌ Не удалось загрузить настройку, попробуйте позже.")
+			return
+		}
+		status := "выключена"
+		if consent != nil && consent.Enabled {
+			status = "включена"
+		}
+		h.replyf(session, "🧠 *Память интервьюера:* %s\n\n"+
+			"Если память включена, в новом интервью интервьюер учитывает профиль вашего прошлого интервью "+
+			"и может спросить, что изменилось с тех пор.\n\nВключить: /memory on\nВыключить: /memory off", status)
+		return
+	}
+
+	var enabled bool
+	switch strings.ToLower(args[0]) {
+	case "on":
+		enabled = true
+	case "off":
+		enabled = false
+	default:
+		h.reply(session, "❌ Используйте /memory on или /memory off.")
+		return
+	}
+	if err := storage.RecordMemoryConsent(session.UserID, enabled); err != nil {
+		h.logger(session).Error("Не удалось сохранить согласие на память интервьюера", "error", err)
+		h.reply(session, "❌ Не удалось сохранить настройку, попробуйте позже.")
+		return
+	}
+	h.logger(session).Info("Согласие на память интервьюера изменено", "enabled", enabled)
+	if enabled {
+		h.reply(session, "✅ Память включена: следующее интервью учтет ваше прошлое интервью.")
+	} else {
+		h.reply(session, "✅ Память выключена: прошлые интервью не будут учитываться в вопросах.")
+	}
+}
+
+// attachMemory подключает к новому интервью профиль прошлого интервью пользователя, если он
+// согласился на память интервьюера, и возвращает строку для приветствия. Пользователю с прошлым
+// профилем, который еще не решал, предлагается включить память.
+func (h *Handler) attachMemory(session *UserSession) string {
+	if !h.memory || h.extractor == nil {
+		return ""
+	}
+	consent, err := storage.MemoryConsentOf(session.UserID)
+	if err != nil {
+		h.logger(session).Warn("Ошибка чтения согласия на память интервьюера", "error", err)
+		return ""
+	}
+	if consent != nil && !consent.Enabled {
+		return ""
+	}
+
+	previous := h.previousProfileInterview(session)
+	if previous == "" {
+		return ""
+	}
+	if consent == nil {
+		return "🧠 Вы уже проходили интервью. Чтобы интервьюер учел прошлые ответы, включите память: /memory on"
+	}
+
+	profileJSON, err := h.extractor.GetLastProfileJSON(previous)
+	if err == nil {
+		err = h.engine.SetMemory(&session.Session, previous, profileJSON)
+	}
+	if err != nil {
+		h.logger(session).Warn("Прошлый профиль не подключен к интервью", "previous_interview_id", previous, "error", err)
+		return ""
+	}
+	h.logger(session).Info("К интервью подключен прошлый профиль", "previous_interview_id", previous)
+	return "🧠 Интервьюер учтет ваше прошлое интервью (выключить: /memory off)"
+}
+
+// previousProfileInterview возвращает последнее прошлое интервью пользователя с профилем (пусто - такого нет)
+func (h *Handler) previousProfileInterview(session *UserSession) string {
+	interviews, err := h.userHistory(session.UserID)
+	if err != nil {
+		h.logger(session).Warn("Ошибка чтения истории интервью", "error", err)
+		return ""
+	}
+	// История отсортирована от новых к старым
+	for _, interview := range interviews {
+		if interview.HasProfile && interview.InterviewID != session.InterviewID {
+			return interview.InterviewID
+		}
+	}
+	return ""
+}
@@ -0,0 +1,30 @@
+package telegram
+
+import (
+	"interview-bot-complete/internal/metrics"
+	"time"
+)
+
+// SetMetrics подключает реестр метрик для дашборда
+func (h *Handler) SetMetrics(registry *metrics.Registry) {
+	h.metrics = registry
+	h.engine.SetMetrics(registry)
+}
+
+// LiveSessions возвращает число сессий по состояниям, активных за последний час.
+// Сессии, занятые сейчас обработкой сообщения, считаются в состоянии "processing".
+func (h *Handler) LiveSessions() map[string]int {
+	cutoff := time.Now().Add(-time.Hour)
+	counts := make(map[string]int)
+	for _, session := range h.sessionList() {
+		if !session.mu.TryLock() {
+			counts["processing"]++
+			continue
+		}
+		if session.LastActivity.After(cutoff) {
+			counts[string(session.State)]++
+		}
+		session.mu.Unlock()
+	}
+	return counts
+}
@@ -0,0 +1,38 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"interview-bot-complete/internal/moderation"
+	"strings"
+)
+
+// SetModeration подключает проверку ответов через OpenAI Moderation API
+func (h *Handler) SetModeration(service *moderation.Service) {
+	h.moderation = service
+}
+
+// moderateAnswer проверяет ответ до записи. Отмеченный ответ помечается в диалоге (политика модерации
+// решает, попадет ли он в анализ профиля), пользователь получает сообщение поддержки, администраторы -
+// уведомление без текста ответа. Ошибка проверки не мешает интервью.
+func (h *Handler) moderateAnswer(session *UserSession, answer string) {
+	if h.moderation == nil {
+		return
+	}
+	result, err := h.moderation.Check(context.Background(), answer)
+	if err != nil {
+		h.logger(session).Warn("Не удалось проверить ответ модерацией", "error", err)
+		h.metrics.RecordError("moderation", err.Error())
+		return
+	}
+	if !result.Flagged() {
+		return
+	}
+
+	h.engine.FlagAnswer(&session.Session, result.Categories)
+	h.reply(session, h.moderationCfg.CrisisMessage)
+	if h.moderationCfg.NotifyAdmins {
+		h.notifyAdmins(fmt.Sprintf("⚠️ Ответ пользователя %d в интервью `%s` отмечен модерацией: %s",
+			session.UserID, session.InterviewID, strings.Join(result.Categories, ", ")))
+	}
+}
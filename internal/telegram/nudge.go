@@ -0,0 +1,127 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"interview-bot-complete/internal/engine"
+	"strings"
+	"time"
+)
+
+// nudgeCheckInterval - период проверки вопросов, оставшихся без ответа
+const nudgeCheckInterval = time.Minute
+
+// Данные inline-кнопок второго напоминания: nudge:<pause|skip>:<id интервью>
+const (
+	nudgeCallbackPrefix = "nudge:"
+	nudgePause          = "pause"
+	nudgeSkip           = "skip"
+)
+
+// startNudgeWatcher периодически напоминает о вопросах без ответа; nudgeAfter = 0 отключает напоминания
+func (h *Handler) startNudgeWatcher() {
+	if h.nudgeAfter <= 0 {
+		return
+	}
+	h.goTicker("nudge", nudgeCheckInterval, func() { h.checkUnansweredQuestions(time.Now()) })
+}
+
+// checkUnansweredQuestions отправляет первое напоминание через nudgeAfter после вопроса
+// и второе, с предложением паузы или пропуска, еще через nudgeAfter
+func (h *Handler) checkUnansweredQuestions(now time.Time) {
+	for _, session := range h.sessionList() {
+		h.nudgeUnanswered(session, now)
+	}
+}
+
+// nudgeUnanswered напоминает о вопросе сессии, если пора. Сессию, занятую обработкой
+// сообщения, проверим на следующем тике.
+func (h *Handler) nudgeUnanswered(session *UserSession, now time.Time) {
+	unlock, ok := h.tryLockSession(session)
+	if !ok {
+		return
+	}
+	defer unlock()
+	if session.State != StateWaitingAnswer || session.Paused || session.PendingDelivery ||
+		session.QuestionSentAt.IsZero() || session.Nudges >= 2 {
+		return
+	}
+	idleSince := session.QuestionSentAt
+	if session.LastActivity.After(idleSince) {
+		idleSince = session.LastActivity
+	}
+	if now.Sub(idleSince) <= time.Duration(session.Nudges+1)*h.nudgeAfter {
+		return
+	}
+	session.Nudges++
+	h.sendNudge(session)
+	h.persistSession(session)
+}
+
+// sendNudge напоминает о текущем вопросе: первый раз повторяет его, второй - предлагает паузу или пропуск
+func (h *Handler) sendNudge(session *UserSession) {
+	prompt := h.engine.Current(&session.Session)
+	if prompt == nil {
+		return
+	}
+	if session.Nudges == 1 {
+		h.reply(session, "👋 Напоминаю о вопросе - ответьте, когда будет удобно:\n\n"+prompt.Text)
+		return
+	}
+
+	keyboard := &InlineKeyboardMarkup{
+		InlineKeyboard: [][]InlineKeyboardButton{{
+			{Text: "⏸ Пауза", CallbackData: nudgeCallbackPrefix + nudgePause + ":" + session.InterviewID},
+			{Text: "⏭ Пропустить вопрос", CallbackData: nudgeCallbackPrefix + nudgeSkip + ":" + session.InterviewID},
+		}},
+	}
+	text := "Похоже, сейчас неудобно отвечать. Можно поставить интервью на паузу (/pause) " +
+		"или пропустить этот вопрос (/skip)."
+	if err := h.bot.SendReplyWithKeyboard(h.destination(session), text, keyboard); err != nil {
+		h.logger(session).Warn("Не удалось отправить напоминание о вопросе", "error", err)
+	}
+}
+
+// handleNudgeCallback обрабатывает кнопки паузы и пропуска из напоминания
+func (h *Handler) handleNudgeCallback(query *CallbackQuery, session *UserSession) {
+	parts := strings.SplitN(strings.TrimPrefix(query.Data, nudgeCallbackPrefix), ":", 2)
+	h.bot.RemoveInlineKeyboard(query.Message.Chat.ID, query.Message.MessageID)
+	if len(parts) != 2 || session.State != StateWaitingAnswer || parts[1] != session.InterviewID {
+		h.bot.AnswerCallbackQuery(query.ID, "Эта кнопка больше не активна")
+		return
+	}
+	h.bot.AnswerCallbackQuery(query.ID, "")
+
+	if parts[0] == nudgePause {
+		h.handlePauseCommand(session)
+		return
+	}
+	h.handleSkipCommand(session)
+}
+
+// handlePauseCommand ставит интервью на паузу: напоминания не отправляются до следующего ответа
+func (h *Handler) handlePauseCommand(session *UserSession) {
+	if session.State != StateWaitingAnswer {
+		h.reply(session, "Сейчас нет вопроса, ожидающего ответа.")
+		return
+	}
+	session.Paused = true
+	h.reply(session, "⏸ Интервью на паузе, напоминаний больше не будет. Когда вернетесь, просто ответьте на последний вопрос.")
+}
+
+// handleSkipCommand пропускает текущий вопрос и задает следующий
+func (h *Handler) handleSkipCommand(session *UserSession) {
+	if session.State != StateWaitingAnswer {
+		h.reply(session, "Сейчас нет вопроса, который можно пропустить.")
+		return
+	}
+	prompt, events, err := h.engine.Skip(context.Background(), &session.Session)
+	if errors.Is(err, engine.ErrNothingToSkip) {
+		h.reply(session, "Сейчас нет вопроса, который можно пропустить.")
+		return
+	}
+	session.LastActivity = time.Now()
+	session.LastAnswer = nil
+	h.reply(session, "⏭ Вопрос пропущен.")
+	h.deliverStep(session, prompt, events, err)
+}
@@ -0,0 +1,63 @@
+package telegram
+
+import (
+	"fmt"
+	"interview-bot-complete/internal/research"
+	"interview-bot-complete/internal/transcript"
+)
+
+// observerQueueSize - столько сообщений трансляции может ждать отправки; при переполнении
+// новые сообщения отбрасываются, чтобы трансляция не задерживала ответы участникам
+const observerQueueSize = 256
+
+// observerMessage - сообщение трансляции для чата наблюдателей
+type observerMessage struct {
+	chatID int64
+	text   string
+}
+
+// observerChat возвращает чат наблюдателей для интервью: интервью без арендатора транслируются
+// в общий чат, интервью арендатора - только если он включил observer (0 - не транслировать)
+func (h *Handler) observerChat(session *UserSession) int64 {
+	t := h.tenantOfResult(session.Result)
+	if t == nil {
+		return h.observerChatID
+	}
+	if !t.Observer {
+		return 0
+	}
+	if t.ObserverChatID != 0 {
+		return t.ObserverChatID
+	}
+	return h.observerChatID
+}
+
+// observe транслирует событие интервью в чат наблюдателей. Участник обозначается псевдонимом
+// интервью (тем же, что в /researchexport), а контакты и номера в тексте скрываются.
+func (h *Handler) observe(session *UserSession, icon, text string) {
+	chatID := h.observerChat(session)
+	if chatID == 0 || session.InterviewID == "" {
+		return
+	}
+	participant := research.NewPseudonymizer(h.researchSalt).Pseudonym(research.KindInterview, session.InterviewID)
+	message := fmt.Sprintf("%s `%s`: %s", icon, participant, markdownEscaper.Replace(transcript.Mask(text)))
+
+	h.observerOnce.Do(func() {
+		h.observerQueue = make(chan observerMessage, observerQueueSize)
+		go h.runObserver()
+	})
+	select {
+	case h.observerQueue <- observerMessage{chatID: chatID, text: message}:
+	default:
+		h.logger(session).Warn("Очередь трансляции наблюдателям переполнена, сообщение пропущено")
+	}
+}
+
+// runObserver отправляет сообщения трансляции по порядку
+func (h *Handler) runObserver() {
+	for message := range h.observerQueue {
+		if err := h.bot.SendMessage(message.chatID, message.text); err != nil {
+			h.baseLogger.Warn("Не удалось отправить трансляцию в чат наблюдателей", "chat_id", message.chatID, "error", err)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package telegram
+
+import (
+	"errors"
+	"fmt"
+	"interview-bot-complete/internal/logging"
+	"interview-bot-complete/internal/storage"
+	"os"
+	"time"
+)
+
+const (
+	// outboxDrainInterval - как часто бот повторно отправляет недоставленные профили
+	outboxDrainInterval = 5 * time.Minute
+	// outboxStaleAfter - моложе этого запись outbox, скорее всего, еще доставляется
+	// обычным путем после извлечения профиля
+	outboxStaleAfter = 10 * time.Minute
+	// maxOutboxAttempts - после стольких неудачных отправок запись снимается, а администраторы уведомляются
+	maxOutboxAttempts = 12
+)
+
+// StartOutbox отправляет профили, не доставленные до перезапуска бота, и затем периодически
+// повторяет отправку. Вызывается после настройки арендаторов обработчика.
+func (h *Handler) StartOutbox() {
+	go func() {
+		h.runSafe("outbox", func() { h.drainOutbox(0) })
+		h.goTicker("outbox", outboxDrainInterval, func() { h.drainOutbox(outboxStaleAfter) })
+	}()
+}
+
+// outboxBot - бот, который доставляет профили этого обработчика: арендатор бота или основной
+func (h *Handler) outboxBot() string {
+	if h.tenant != nil {
+		return h.tenant.ID
+	}
+	return ""
+}
+
+// enqueueDelivery записывает сохраненный профиль в outbox до его отправки пользователю
+func (h *Handler) enqueueDelivery(session *UserSession, interviewID, fileName string) {
+	err := storage.SaveDelivery(storage.ProfileDelivery{
+		InterviewID: interviewID,
+		Bot:         h.outboxBot(),
+		ChatID:      session.ChatID,
+		ThreadID:    session.ThreadID,
+		UserID:      session.UserID,
+		FileName:    fileName,
+		CreatedAt:   time.Now().Format(time.RFC3339),
+	})
+	if err != nil {
+		h.logger(session).Warn("Не удалось записать профиль в outbox", "error", err)
+	}
+}
+
+// completeDelivery снимает профиль с outbox после отправки файла пользователю
+func (h *Handler) completeDelivery(session *UserSession, interviewID string) {
+	if err := storage.CompleteDelivery(interviewID); err != nil {
+		h.logger(session).Warn("Не удалось удалить профиль из outbox", "error", err)
+	}
+}
+
+// drainOutbox отправляет профили из outbox, записанные не позже minAge назад
+func (h *Handler) drainOutbox(minAge time.Duration) {
+	h.outboxMutex.Lock()
+	defer h.outboxMutex.Unlock()
+
+	deliveries, err := storage.PendingDeliveries()
+	if err != nil {
+		h.baseLogger.Warn("Ошибка чтения outbox профилей", "error", err)
+	}
+
+	bot := h.outboxBot()
+	for _, delivery := range deliveries {
+		if delivery.Bot != bot {
+			continue
+		}
+		createdAt, _ := time.Parse(time.RFC3339, delivery.CreatedAt)
+		if time.Since(createdAt) < minAge {
+			continue
+		}
+		h.deliverFromOutbox(delivery)
+	}
+}
+
+// deliverFromOutbox отправляет профиль из outbox; неудача увеличивает счетчик попыток
+func (h *Handler) deliverFromOutbox(delivery storage.ProfileDelivery) {
+	logger := logging.Interview(h.baseLogger, delivery.InterviewID, delivery.UserID, 0).With(logging.KeyChatID, delivery.ChatID)
+
+	fileData, err := storage.ReadFileVerified(delivery.FileName)
+	if errors.Is(err, os.ErrNotExist) {
+		// Профиль удален (политика хранения или вручную) - отправлять нечего
+		logger.Warn("Профиль из outbox не найден, доставка отменена", "file", delivery.FileName)
+		storage.CompleteDelivery(delivery.InterviewID)
+		return
+	}
+	if err == nil {
+		dest := Destination{ChatID: delivery.ChatID, MessageThreadID: delivery.ThreadID}
+		err = h.sendProfileDocument(dest, fileData, delivery.FileName, delivery.InterviewID)
+	}
+	if err == nil {
+		if err := storage.CompleteDelivery(delivery.InterviewID); err != nil {
+			logger.Warn("Не удалось удалить профиль из outbox", "error", err)
+		}
+		logger.Info("Недоставленный профиль отправлен", "attempts", delivery.Attempts+1)
+		return
+	}
+
+	delivery.Attempts++
+	delivery.LastError = err.Error()
+	if delivery.Attempts >= maxOutboxAttempts {
+		logger.Error("Профиль не доставлен, попытки исчерпаны", "attempts", delivery.Attempts, "error", err)
+		storage.CompleteDelivery(delivery.InterviewID)
+		h.notifyAdmins(fmt.Sprintf("⚠️ Профиль интервью `%s` не доставлен в чат %d после %d попыток: %s",
+			delivery.InterviewID, delivery.ChatID, delivery.Attempts, err.Error()))
+		return
+	}
+	logger.Warn("Повторная отправка профиля не удалась", "attempts", delivery.Attempts, "error", err)
+	if err := storage.SaveDelivery(delivery); err != nil {
+		logger.Warn("Не удалось обновить запись outbox", "error", err)
+	}
+}
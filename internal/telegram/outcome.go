@@ -0,0 +1,109 @@
+package telegram
+
+import (
+	"encoding/json"
+	"fmt"
+	"interview-bot-complete/internal/rubric"
+	"interview-bot-complete/internal/storage"
+	"strings"
+)
+
+// verdictLabels - вердикты соответствия роли в отчете рекрутеру
+var verdictLabels = map[string]string{
+	rubric.VerdictStrongFit:  "🟢 Сильное соответствие",
+	rubric.VerdictFit:        "🟢 Соответствует",
+	rubric.VerdictPartialFit: "🟡 Частичное соответствие",
+	rubric.VerdictNoFit:      "🔴 Не соответствует",
+}
+
+// recruiterChat возвращает чат рекрутеров для интервью: чат арендатора или общий RECRUITER_CHAT_ID
+func (h *Handler) recruiterChat(session *UserSession) int64 {
+	if t := h.tenantOfResult(session.Result); t != nil && t.RecruiterChatID != 0 {
+		return t.RecruiterChatID
+	}
+	return h.recruiterChatID
+}
+
+// classifyOutcome в фоне оценивает кандидата по рубрике роли и отправляет отчет в чат рекрутеров.
+// Кандидат отчет не видит; ошибка оценки не влияет на выдачу профиля.
+func (h *Handler) classifyOutcome(session *UserSession, profileJSON string) {
+	chatID := h.recruiterChat(session)
+	opts := h.extractOptions(session)
+	if chatID == 0 || h.extractor == nil || !h.extractor.OutcomeEnabled(opts.Rubric) {
+		return
+	}
+
+	result := session.Result
+	logger := h.logger(session)
+	h.goSafe(session, "outcome", func() {
+		if err := h.waitLLMBudget(session.UserID); err != nil {
+			logger.Warn("Оценка по рубрике отложена: сервис анализа перегружен", "error", err)
+			return
+		}
+		report, err := h.extractor.ClassifyOutcome(result, profileJSON, opts)
+		if err != nil {
+			logger.Warn("Ошибка оценки кандидата по рубрике", "rubric", opts.Rubric, "error", err)
+			h.metrics.RecordError("outcome", err.Error())
+			return
+		}
+
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			logger.Warn("Ошибка сериализации отчета для рекрутера", "error", err)
+			return
+		}
+		path, err := storage.SaveFitReport(report.InterviewID, report.Tenant, data)
+		if err != nil {
+			logger.Warn("Не удалось сохранить отчет для рекрутера", "error", err)
+		}
+
+		if err := h.bot.SendMessage(chatID, fitReportText(report, candidateName(profileJSON))); err != nil {
+			logger.Warn("Не удалось отправить отчет в чат рекрутеров", "chat_id", chatID, "error", err)
+			return
+		}
+		logger.Info("Отчет о соответствии роли отправлен рекрутеру", "rubric", report.RubricID,
+			"score", report.Score, "verdict", report.Verdict, "path", path)
+	})
+}
+
+// fitReportText форматирует отчет о соответствии роли для чата рекрутеров
+func fitReportText(report *rubric.Report, name string) string {
+	var text strings.Builder
+	text.WriteString("🧑‍💼 *Оценка кандидата: " + report.Role + "*\n")
+	if name != "" {
+		text.WriteString("Кандидат: " + name + "\n")
+	}
+	text.WriteString(fmt.Sprintf("Интервью: `%s`\n\n", report.InterviewID))
+	text.WriteString(fmt.Sprintf("*%s* - %.0f/100\n", verdictLabels[report.Verdict], report.Score))
+	if report.Summary != "" {
+		text.WriteString("\n" + report.Summary + "\n")
+	}
+
+	text.WriteString("\n*Компетенции:*\n")
+	for _, score := range report.Scores {
+		text.WriteString(fmt.Sprintf("• %s (вес %g): %d/5", score.Name, score.Weight, score.Score))
+		if score.Evidence != "" {
+			text.WriteString(" - " + score.Evidence)
+		}
+		text.WriteString("\n")
+	}
+
+	if len(report.Concerns) > 0 {
+		text.WriteString("\n*Проверить на следующем этапе:*\n")
+		for _, concern := range report.Concerns {
+			text.WriteString("• " + concern + "\n")
+		}
+	}
+	return text.String()
+}
+
+// candidateName возвращает имя кандидата из профиля (пусто, если имя не указано)
+func candidateName(profileJSON string) string {
+	var profile struct {
+		Name string `json:"name"`
+	}
+	if json.Unmarshal([]byte(profileJSON), &profile) != nil {
+		return ""
+	}
+	return profile.Name
+}
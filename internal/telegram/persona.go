@@ -0,0 +1,71 @@
+package telegram
+
+import (
+	"fmt"
+	"interview-bot-complete/internal/engine"
+	"strings"
+)
+
+// handlePersonaCommand показывает персоны интервьюера шаблона (/persona)
+// или выбирает персону (/persona <id>) для текущего и следующих интервью
+func (h *Handler) handlePersonaCommand(args []string, session *UserSession) {
+	cfg := h.configFor(session)
+	if len(cfg.Personas) == 0 {
+		h.reply(session, "В этом интервью нет вариантов интервьюера.")
+		return
+	}
+
+	active := session.InterviewID != "" && session.Phase != engine.PhaseCompleted
+	current := cfg.MatchPersona(session.PreferredPersona)
+	if active {
+		current = session.Persona
+	}
+
+	if len(args) == 0 {
+		var text strings.Builder
+		text.WriteString("🎙 *Интервьюер*\n\n")
+		for _, persona := range cfg.Personas {
+			marker := "•"
+			if persona.ID == current {
+				marker = "✅"
+			}
+			text.WriteString(fmt.Sprintf("%s *%s* - `%s`\n", marker, persona.Name, persona.ID))
+			if persona.Description != "" {
+				text.WriteString(persona.Description + "\n")
+			}
+		}
+		text.WriteString("\nВыбрать: /persona <id>")
+		h.reply(session, text.String())
+		return
+	}
+
+	id := strings.ToLower(args[0])
+	persona, ok := cfg.Persona(id)
+	if !ok {
+		ids := make([]string, len(cfg.Personas))
+		for i, candidate := range cfg.Personas {
+			ids[i] = candidate.ID
+		}
+		h.replyf(session, "❌ Нет интервьюера `%s`. Доступны: %s", id, strings.Join(ids, ", "))
+		return
+	}
+
+	session.PreferredPersona = persona.ID
+	if !active {
+		h.replyf(session, "✅ Интервью проведет *%s*. Используйте /start для начала.", persona.Name)
+		return
+	}
+	if err := h.engine.SetPersona(&session.Session, persona.ID); err != nil {
+		h.reply(session, "❌ "+err.Error())
+		return
+	}
+	h.replyf(session, "✅ Следующие вопросы задаст *%s*.", persona.Name)
+}
+
+// personaName возвращает название персоны интервью (пусто для базовой роли)
+func (h *Handler) personaName(session *UserSession) string {
+	if persona, ok := h.configFor(session).Persona(session.Persona); ok {
+		return persona.Name
+	}
+	return ""
+}
@@ -0,0 +1,146 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	// defaultPollTimeout - сколько Telegram держит getUpdates открытым, если обновлений нет
+	defaultPollTimeout = 30 * time.Second
+	// maxPollLimit - максимум обновлений за один getUpdates по Bot API
+	maxPollLimit = 100
+	// pollClientMargin - запас таймаута HTTP клиента сверх long polling на сеть
+	pollClientMargin = 10 * time.Second
+	// pollRetryDelay - пауза после ошибки getUpdates
+	pollRetryDelay = 5 * time.Second
+	// shortPollDelay - пауза между пустыми ответами без long polling
+	shortPollDelay = time.Second
+)
+
+// DefaultAllowedUpdates - типы обновлений, которые обрабатывает бот (поля Update)
+var DefaultAllowedUpdates = []string{"message", "edited_message", "callback_query", "pre_checkout_query"}
+
+// PollingOptions - параметры получения обновлений через getUpdates
+type PollingOptions struct {
+	// Timeout - длительность long polling; 0 - короткий polling
+	Timeout time.Duration
+	// Limit - максимум обновлений за запрос (1-100)
+	Limit int
+	// AllowedUpdates - запрашиваемые типы обновлений; пусто - DefaultAllowedUpdates
+	AllowedUpdates []string
+}
+
+func defaultPolling() PollingOptions {
+	return PollingOptions{
+		Timeout:        defaultPollTimeout,
+		Limit:          maxPollLimit,
+		AllowedUpdates: DefaultAllowedUpdates,
+	}
+}
+
+// SetPolling задает параметры getUpdates; недопустимые значения заменяются значениями по умолчанию
+func (b *Bot) SetPolling(opts PollingOptions) {
+	if opts.Timeout < 0 {
+		opts.Timeout = defaultPollTimeout
+	}
+	if opts.Limit <= 0 || opts.Limit > maxPollLimit {
+		opts.Limit = maxPollLimit
+	}
+	if len(opts.AllowedUpdates) == 0 {
+		opts.AllowedUpdates = DefaultAllowedUpdates
+	}
+	b.polling = opts
+}
+
+// GetUpdates получает обновления от Telegram; запрос прерывается отменой ctx
+func (b *Bot) GetUpdates(ctx context.Context, offset int) ([]Update, error) {
+	allowedUpdates, err := json.Marshal(b.polling.AllowedUpdates)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка сериализации allowed_updates: %w", err)
+	}
+	params := url.Values{}
+	params.Set("offset", strconv.Itoa(offset))
+	params.Set("timeout", strconv.Itoa(int(b.polling.Timeout/time.Second)))
+	params.Set("limit", strconv.Itoa(b.polling.Limit))
+	params.Set("allowed_updates", string(allowedUpdates))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", b.baseURL+"/getUpdates?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка создания запроса: %w", err)
+	}
+	client := b.httpClient(b.polling.Timeout + pollClientMargin)
+	resp, err := client.Do(req)
+	if err != nil {
+		// URL запроса содержит токен бота - не выносим его в текст ошибки
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return nil, fmt.Errorf("ошибка запроса getUpdates: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var response GetUpdatesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("ошибка парсинга JSON: %w", err)
+	}
+	if !response.OK {
+		return nil, fmt.Errorf("Telegram API вернул ошибку: %s", response.Description)
+	}
+
+	return response.Result, nil
+}
+
+// StartPolling получает обновления и передает их handler пулом обработчиков (SetDispatch), пока
+// не отменен ctx. Пока очередь заполнена, новые обновления не запрашиваются. Перед возвратом
+// дожидается обработки принятых обновлений.
+func (b *Bot) StartPolling(ctx context.Context, handler func(Update)) error {
+	offset := 0
+	dispatcher := newDispatcher(b, handler)
+	defer dispatcher.stop()
+
+	for {
+		updates, err := b.GetUpdates(ctx, offset)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			slog.Warn("Ошибка получения обновлений", "error", err)
+			if !sleepContext(ctx, pollRetryDelay) {
+				return nil
+			}
+			continue
+		}
+
+		for _, update := range updates {
+			if !dispatcher.dispatch(ctx, update) {
+				return nil
+			}
+			offset = update.UpdateID + 1
+		}
+
+		if len(updates) == 0 && b.polling.Timeout == 0 && !sleepContext(ctx, shortPollDelay) {
+			return nil
+		}
+	}
+}
+
+// sleepContext ждет d; false - ctx отменен раньше
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
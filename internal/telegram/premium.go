@@ -0,0 +1,364 @@
+package telegram
+
+import (
+	"fmt"
+	"interview-bot-complete/internal/config"
+	"interview-bot-complete/internal/extractor"
+	"interview-bot-complete/internal/language"
+	"interview-bot-complete/internal/logging"
+	"interview-bot-complete/internal/report"
+	"interview-bot-complete/internal/storage"
+	"strings"
+	"time"
+)
+
+// premiumPayloadPrefix - префикс payload счета: premium:<функция>:<user_id>
+const premiumPayloadPrefix = "premium:"
+
+// archetypeLabels - подписи разбора архетипа и отчета расширенного анализа
+type archetypeLabels struct {
+	// Сообщение с разбором архетипа
+	YourArchetype, Strengths, BlindSpots, Recommendations string
+	// Отчет; ReportSubtitle - формат с ID интервью и датой
+	ReportTitle, ReportSubtitle, Archetype, Growth, CareerPaths, Disclaimer string
+}
+
+// premiumLabels - подписи разбора архетипа и отчета по языку интервью
+var premiumLabels = map[string]archetypeLabels{
+	language.Russian: {
+		YourArchetype:   "Ваш архетип",
+		Strengths:       "Сильные стороны",
+		BlindSpots:      "Слепые зоны",
+		Recommendations: "Рекомендации",
+		Archetype:       "Архетип",
+		Growth:          "Рекомендации по развитию",
+		ReportTitle:     "Расширенный анализ профиля",
+		ReportSubtitle:  "Интервью %s · %s",
+		CareerPaths:     "Подходящие направления",
+		Disclaimer:      "Этот анализ создан искусственным интеллектом на основе ваших ответов и не является психологическим заключением.",
+	},
+	language.English: {
+		YourArchetype:   "Your archetype",
+		Strengths:       "Strengths",
+		BlindSpots:      "Blind spots",
+		Recommendations: "Recommendations",
+		Archetype:       "Archetype",
+		Growth:          "Growth recommendations",
+		ReportTitle:     "Extended profile analysis",
+		ReportSubtitle:  "Interview %s · %s",
+		CareerPaths:     "Suitable career paths",
+		Disclaimer:      "This analysis was generated by artificial intelligence from your answers and is not a psychological assessment.",
+	},
+}
+
+// labelsFor возвращает подписи архетипа и отчета на языке шаблона интервью
+func labelsFor(lang string) archetypeLabels {
+	labels, ok := premiumLabels[lang]
+	if !ok {
+		labels = premiumLabels[language.Default]
+	}
+	return labels
+}
+
+// SetReportFont подключает шрифт для PDF отчета расширенного анализа
+func (h *Handler) SetReportFont(font *report.Font) {
+	h.reportFont = font
+}
+
+// extractOptions возвращает параметры извлечения с переопределениями модели из шаблона интервью
+func (h *Handler) extractOptions(session *UserSession) extractor.ExtractOptions {
+	cfg := h.configFor(session)
+	settings := cfg.ModelFor(config.UseCaseExtraction)
+	return extractor.ExtractOptions{
+		Model:            settings.Model,
+		Temperature:      settings.Temperature,
+		MaxTokens:        settings.MaxTokens,
+		UserID:           session.UserID,
+		ArchetypeCatalog: cfg.InterviewConfig.ArchetypeCatalog,
+		Rubric:           cfg.InterviewConfig.Rubric,
+		Matching:         cfg.ModelFor(config.UseCaseMatching),
+		Logger:           h.logger(session),
+	}
+}
+
+// hasPremium проверяет, оплачен ли пользователем расширенный анализ
+func (h *Handler) hasPremium(userID int64) bool {
+	if !h.premium.Enabled {
+		return false
+	}
+	entitled, err := storage.HasEntitlement(userID, storage.FeatureExtendedAnalysis)
+	if err != nil {
+		h.baseLogger.Error("Ошибка проверки оплаты", logging.KeyUserID, userID, "error", err)
+		return false
+	}
+	return entitled
+}
+
+// premiumPayload формирует payload счета для пользователя
+func premiumPayload(userID int64) string {
+	return fmt.Sprintf("%s%s:%d", premiumPayloadPrefix, storage.FeatureExtendedAnalysis, userID)
+}
+
+// handlePremiumCommand обрабатывает /premium: выставляет счет или, если анализ
+// уже оплачен, готовит его для завершенного интервью
+func (h *Handler) handlePremiumCommand(session *UserSession) {
+	if !h.premium.Enabled || h.extractor == nil {
+		h.reply(session, "❌ Расширенный анализ сейчас недоступен.")
+		return
+	}
+
+	if h.hasPremium(session.UserID) {
+		if !session.isCompleted() {
+			h.reply(session, "💎 Расширенный анализ у вас уже оплачен. Он будет подготовлен автоматически после завершения интервью (/start).")
+			return
+		}
+		h.startExtendedAnalysis(session, "💎 Готовлю расширенный анализ...")
+		return
+	}
+
+	h.reply(session, `💎 *Расширенный анализ*
+
+Базовый профиль бесплатен. Расширенный анализ добавляет:
+• развернутый отчет по мотивации, стилю работы, ценностям и карьере
+• разбор вашего архетипа личности: сильные стороны, слепые зоны, рекомендации
+• подходящие направления и роли
+• отчет в PDF
+
+Доступ покупается один раз и действует для всех ваших интервью.`)
+
+	err := h.bot.SendInvoice(SendInvoiceRequest{
+		ChatID:          session.ChatID,
+		MessageThreadID: session.ThreadID,
+		Title:           "Расширенный анализ профиля",
+		Description:     "Развернутый отчет, архетип личности и PDF по результатам интервью",
+		Payload:         premiumPayload(session.UserID),
+		ProviderToken:   h.premium.ProviderToken,
+		Currency:        h.premium.Currency,
+		Prices:          []LabeledPrice{{Label: "Расширенный анализ", Amount: h.premium.Price}},
+	})
+	if err != nil {
+		h.logger(session).Error("Ошибка выставления счета", "error", err)
+		h.reply(session, "❌ Не удалось выставить счет, попробуйте позже.")
+	}
+}
+
+// handlePreCheckoutQuery проверяет счет перед списанием: payload, валюту и сумму
+func (h *Handler) handlePreCheckoutQuery(query *PreCheckoutQuery) {
+	ok, reason := h.validatePayment(query.From, query.InvoicePayload, query.Currency, query.TotalAmount)
+	if !ok {
+		h.baseLogger.Warn("Отклонена оплата", "query_id", query.ID, "reason", reason)
+	}
+	if err := h.bot.AnswerPreCheckoutQuery(query.ID, ok, reason); err != nil {
+		h.baseLogger.Error("Ошибка ответа на pre_checkout_query", "query_id", query.ID, "error", err)
+	}
+}
+
+// validatePayment сверяет оплату с текущими условиями; reason показывается пользователю
+func (h *Handler) validatePayment(from *User, payload, currency string, amount int) (bool, string) {
+	if !h.premium.Enabled {
+		return false, "Расширенный анализ сейчас недоступен."
+	}
+	if from == nil || payload != premiumPayload(from.ID) {
+		return false, "Счет выставлен другому пользователю. Запросите новый через /premium."
+	}
+	if currency != h.premium.Currency || amount != h.premium.Price {
+		return false, "Цена изменилась. Запросите новый счет через /premium."
+	}
+	return true, ""
+}
+
+// handleSuccessfulPayment сохраняет доступ к расширенному анализу и готовит его,
+// если интервью уже завершено
+func (h *Handler) handleSuccessfulPayment(session *UserSession, payment *SuccessfulPayment) {
+	// Payload сверен в pre_checkout_query; расхождение только логируем - деньги уже списаны
+	if payment.InvoicePayload != premiumPayload(session.UserID) {
+		h.logger(session).Warn("Оплата с неожиданным payload", "charge_id", payment.TelegramPaymentChargeID, "payload", payment.InvoicePayload)
+	}
+
+	err := storage.RecordEntitlement(storage.Entitlement{
+		UserID:           session.UserID,
+		Feature:          storage.FeatureExtendedAnalysis,
+		Currency:         payment.Currency,
+		Amount:           payment.TotalAmount,
+		TelegramChargeID: payment.TelegramPaymentChargeID,
+		ProviderChargeID: payment.ProviderPaymentChargeID,
+		PurchasedAt:      time.Now().Format(time.RFC3339),
+	})
+	if err != nil {
+		// Деньги списаны - администраторы должны выдать доступ вручную
+		h.logger(session).Error("Ошибка сохранения оплаты", "charge_id", payment.TelegramPaymentChargeID, "error", err)
+		h.metrics.RecordError("payments", err.Error())
+		h.notifyAdmins(fmt.Sprintf("💳 Оплата %s от пользователя %d (%d %s) не сохранена: %v",
+			payment.TelegramPaymentChargeID, session.UserID, payment.TotalAmount, payment.Currency, err))
+		h.reply(session, "⚠️ Оплата получена, но доступ не удалось сохранить. Администраторы уже уведомлены и исправят это.")
+		return
+	}
+
+	if !session.isCompleted() {
+		h.reply(session, "✅ Спасибо за оплату! Расширенный анализ будет подготовлен после завершения интервью.")
+		return
+	}
+	h.startExtendedAnalysis(session, "✅ Спасибо за оплату! Готовлю расширенный анализ...")
+}
+
+// startExtendedAnalysis готовит расширенный анализ завершенного интервью в фоне, сообщив message.
+// Готовый анализ отправляется повторно без нового платного запроса к модели, а пока анализ
+// интервью готовится, второй не запускается.
+func (h *Handler) startExtendedAnalysis(session *UserSession, message string) {
+	interviewID := session.InterviewID
+	h.extendedMutex.Lock()
+	running := h.extendedJobs[interviewID]
+	if !running {
+		if h.extendedJobs == nil {
+			h.extendedJobs = make(map[string]bool)
+		}
+		h.extendedJobs[interviewID] = true
+	}
+	h.extendedMutex.Unlock()
+	if running {
+		h.reply(session, "⏳ Расширенный анализ уже готовится - пришлю его, как только он будет готов.")
+		return
+	}
+	finish := func() {
+		h.extendedMutex.Lock()
+		delete(h.extendedJobs, interviewID)
+		h.extendedMutex.Unlock()
+	}
+
+	if extended := h.extractor.LatestExtendedProfile(interviewID); extended != nil {
+		finish()
+		h.sendExtendedReport(session, extended.Analysis)
+		h.sendJSONProfile(session, extended.FileName, fmt.Sprintf("%s_v%d", interviewID, extended.Revision))
+		return
+	}
+
+	h.reply(session, message)
+	h.goSafe(session, "extended_analysis", func() {
+		defer finish()
+		h.deliverExtendedAnalysis(session)
+	})
+}
+
+// deliverExtendedAnalysis добавляет расширенный анализ к профилю завершенного интервью и отправляет отчет
+func (h *Handler) deliverExtendedAnalysis(session *UserSession) {
+	if err := h.waitLLMBudget(session.UserID); err != nil {
+		h.reply(session, "❌ Сервис анализа перегружен, попробуйте /premium чуть позже.")
+		return
+	}
+
+	extended, err := h.extractor.ExtendProfile(session.InterviewID, h.extractOptions(session))
+	if err != nil {
+		h.reply(session, h.errorReply(session, err)+"\nПопробуйте /premium позже - повторная оплата не потребуется.")
+		return
+	}
+
+	h.indexForSearch(session.InterviewID)
+	h.sendExtendedReport(session, extended.Analysis)
+	h.sendJSONProfile(session, extended.FileName, fmt.Sprintf("%s_v%d", session.InterviewID, extended.Revision))
+}
+
+// sendExtendedReport отправляет разбор архетипа сообщением и полный отчет файлом
+func (h *Handler) sendExtendedReport(session *UserSession, analysis *extractor.ExtendedAnalysis) {
+	if analysis.Archetype.Name != "" {
+		h.sendArchetype(session, analysis.Archetype)
+	}
+
+	doc := extendedReportDocument(session.InterviewID, analysis, labelsFor(h.configFor(session).Language()))
+	if h.reportFont != nil {
+		data, err := report.RenderPDF(h.reportFont, doc)
+		if err == nil {
+			err = h.bot.SendDocumentTo(h.destination(session), data, fmt.Sprintf("report_%s.pdf", session.InterviewID), "📑 Расширенный отчет")
+		}
+		if err == nil {
+			return
+		}
+		h.logger(session).Warn("Ошибка отправки PDF отчета", "error", err)
+	}
+
+	// Без шрифта для PDF отчет отправляется текстовым файлом
+	if err := h.bot.SendDocumentTo(h.destination(session), []byte(extendedReportText(doc)),
+		fmt.Sprintf("report_%s.txt", session.InterviewID), "📑 Расширенный отчет"); err != nil {
+		h.replyError(session, err)
+	}
+}
+
+// sendArchetype отправляет разбор архетипа: название из каталога, описание и списки
+func (h *Handler) sendArchetype(session *UserSession, archetype extractor.Archetype) {
+	labels := labelsFor(h.configFor(session).Language())
+	var message strings.Builder
+	message.WriteString("💎 *" + labels.YourArchetype + ": " + archetype.Name + "*\n")
+	if archetype.CatalogName != "" {
+		message.WriteString("_" + archetype.CatalogName + "_\n")
+	}
+	message.WriteString("\n" + archetype.Description + "\n")
+	writeList := func(title string, items []string) {
+		if len(items) == 0 {
+			return
+		}
+		message.WriteString("\n*" + title + ":*\n")
+		for _, item := range items {
+			message.WriteString("• " + item + "\n")
+		}
+	}
+	writeList(labels.Strengths, archetype.Strengths)
+	writeList(labels.BlindSpots, archetype.BlindSpots)
+	writeList(labels.Recommendations, archetype.Growth)
+	h.reply(session, message.String())
+}
+
+// extendedReportDocument собирает документ отчета из расширенного анализа с подписями labels
+func extendedReportDocument(interviewID string, analysis *extractor.ExtendedAnalysis, labels archetypeLabels) report.Document {
+	doc := report.Document{
+		Title:    labels.ReportTitle,
+		Subtitle: fmt.Sprintf(labels.ReportSubtitle, interviewID, time.Now().Format("02.01.2006")),
+	}
+
+	archetype := analysis.Archetype
+	if archetype.Name != "" {
+		doc.Sections = append(doc.Sections, report.Section{
+			Heading:    labels.Archetype + ": " + archetype.Name,
+			Paragraphs: []string{archetype.Description},
+		})
+	}
+	for _, list := range []struct {
+		heading string
+		items   []string
+	}{
+		{labels.Strengths, archetype.Strengths},
+		{labels.BlindSpots, archetype.BlindSpots},
+		{labels.Growth, archetype.Growth},
+	} {
+		if len(list.items) > 0 {
+			doc.Sections = append(doc.Sections, report.Section{Heading: list.heading, Bullets: list.items})
+		}
+	}
+	for _, section := range analysis.Report {
+		doc.Sections = append(doc.Sections, report.Section{Heading: section.Title, Paragraphs: []string{section.Text}})
+	}
+	if len(analysis.CareerPaths) > 0 {
+		doc.Sections = append(doc.Sections, report.Section{Heading: labels.CareerPaths, Bullets: analysis.CareerPaths})
+	}
+	doc.Sections = append(doc.Sections, report.Section{
+		Paragraphs: []string{labels.Disclaimer},
+	})
+	return doc
+}
+
+// extendedReportText - текстовая версия отчета
+func extendedReportText(doc report.Document) string {
+	var text strings.Builder
+	text.WriteString(doc.Title + "\n" + doc.Subtitle + "\n")
+	for _, section := range doc.Sections {
+		text.WriteString("\n")
+		if section.Heading != "" {
+			text.WriteString(strings.ToUpper(section.Heading) + "\n")
+		}
+		for _, paragraph := range section.Paragraphs {
+			text.WriteString(paragraph + "\n")
+		}
+		for _, bullet := range section.Bullets {
+			text.WriteString("• " + bullet + "\n")
+		}
+	}
+	return text.String()
+}
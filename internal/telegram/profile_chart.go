@@ -0,0 +1,121 @@
+package telegram
+
+import (
+	"encoding/json"
+	"fmt"
+	"interview-bot-complete/internal/chart"
+	"interview-bot-complete/internal/extractor"
+	"strings"
+)
+
+const (
+	// profileChartSize - сторона изображения диаграммы профиля в пикселях
+	profileChartSize = 600
+	// profileChartFullScale - число заполненных пунктов раздела, при котором ось заполнена целиком
+	profileChartFullScale = 10
+)
+
+// profileDimension - ось диаграммы профиля: раздел схемы и его поля; название раздела - в summaryLocales
+type profileDimension struct {
+	ID     string
+	Fields []string
+}
+
+// profileDimensions - разделы профиля (config/profile_schema.yaml) для радарной диаграммы
+var profileDimensions = []profileDimension{
+	{"skills", []string{"hard_skills", "soft_skills", "programming_languages", "tools_and_technologies", "certifications"}},
+	{"career", []string{"current_position", "work_experience_years", "previous_companies", "career_goals"}},
+	{"education", []string{"university", "education_level", "field_of_study", "graduation_year"}},
+	{"interests", []string{"hobbies", "interests", "favorite_books", "favorite_movies", "sports"}},
+	{"personality", []string{"personality_traits", "values", "motivations", "work_style"}},
+	{"goals", []string{"short_term_goals", "long_term_goals", "dream_projects"}},
+	{"experience", []string{"languages_spoken", "travel_experience", "volunteer_experience", "achievements"}},
+}
+
+// profileChartAxis - ось диаграммы с числом заполненных пунктов раздела
+type profileChartAxis struct {
+	Label string
+	Items int
+}
+
+// profileChartAxes считает заполненные пункты разделов профиля: элементы массивов
+// и непустые значения полей. Разделы, полей которых нет в профиле (другая схема), пропускаются.
+func profileChartAxes(profileJSON string, locale summaryLocale) []profileChartAxis {
+	var profile map[string]interface{}
+	if err := json.Unmarshal([]byte(profileJSON), &profile); err != nil {
+		return nil
+	}
+
+	var axes []profileChartAxis
+	for _, dimension := range profileDimensions {
+		present := false
+		items := 0
+		for _, field := range dimension.Fields {
+			value, ok := profile[field]
+			if !ok {
+				continue
+			}
+			present = true
+			items += filledItems(value)
+		}
+		if present {
+			axes = append(axes, profileChartAxis{Label: locale.Dimensions[dimension.ID], Items: items})
+		}
+	}
+	return axes
+}
+
+// filledItems возвращает число заполненных пунктов значения поля профиля
+func filledItems(value interface{}) int {
+	switch v := value.(type) {
+	case nil:
+		return 0
+	case []interface{}:
+		count := 0
+		for _, item := range v {
+			count += filledItems(item)
+		}
+		return count
+	case string:
+		if strings.TrimSpace(v) == "" {
+			return 0
+		}
+	case float64:
+		if v == 0 {
+			return 0
+		}
+	case bool:
+		if !v {
+			return 0
+		}
+	}
+	return 1
+}
+
+// sendProfileChart отправляет радарную диаграмму заполненности разделов профиля;
+// при нехватке разделов диаграмма не отправляется
+func (h *Handler) sendProfileChart(session *UserSession, profileJSON string) {
+	locale := summaryLocaleFor(extractor.ProfileLanguage(profileJSON))
+	axes := profileChartAxes(profileJSON, locale)
+	if len(axes) < chart.MinAxes {
+		return
+	}
+
+	chartAxes := make([]chart.Axis, len(axes))
+	legend := make([]string, len(axes))
+	for i, axis := range axes {
+		chartAxes[i] = chart.Axis{Label: axis.Label, Value: float64(axis.Items) / profileChartFullScale}
+		legend[i] = fmt.Sprintf("%d — %s: %d", i+1, axis.Label, axis.Items)
+	}
+
+	image, err := chart.Radar(chartAxes, profileChartSize)
+	if err != nil {
+		h.logger(session).Warn("Не удалось построить диаграмму профиля", "error", err)
+		return
+	}
+	caption := locale.ChartCaption + "\n" + strings.Join(legend, "\n")
+	fileName := fmt.Sprintf("profile_%s.png", session.InterviewID)
+	if err := h.bot.SendPhotoTo(h.destination(session), image, fileName, caption); err != nil {
+		h.logger(session).Warn("Ошибка отправки диаграммы профиля", "error", err)
+	}
+}
@@ -0,0 +1,77 @@
+package telegram
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"interview-bot-complete/internal/apperr"
+	"interview-bot-complete/internal/storage"
+	"os"
+	"time"
+)
+
+// profilePreviewLimit - максимальная длина превью профиля в сообщении
+const profilePreviewLimit = 1500
+
+// sendJSONProfile отправляет профиль файлом .json с подписью (ID интервью и дата),
+// при включенном TELEGRAM_PROFILE_PREVIEW - с превью начала профиля в сообщении.
+// Возвращает ошибку, если файл не дошел до пользователя.
+func (h *Handler) sendJSONProfile(session *UserSession, fileName string, documentID string) error {
+	fileData, err := storage.ReadFileVerified(fileName)
+	if err != nil {
+		h.replyError(session, apperr.Wrap(apperr.ErrStorage, err))
+		return err
+	}
+
+	if h.profilePreview {
+		if preview := profilePreview(fileData); preview != "" {
+			h.reply(session, "👀 *Превью профиля:*\n```\n"+preview+"\n```")
+		}
+	}
+
+	if err := h.sendProfileDocument(h.destination(session), fileData, fileName, documentID); err != nil {
+		h.replyError(session, err)
+		return err
+	}
+
+	h.reply(session, "✅ JSON профиль отправлен как файл!")
+	return nil
+}
+
+// sendProfileDocument отправляет данные профиля файлом profile_<documentID>.json
+func (h *Handler) sendProfileDocument(dest Destination, fileData []byte, fileName, documentID string) error {
+	documentName := fmt.Sprintf("profile_%s.json", documentID)
+	caption := fmt.Sprintf("📄 Профиль интервью %s\n📅 %s", documentID, profileDate(fileData, fileName))
+	return h.bot.SendDocumentTo(dest, fileData, documentName, caption)
+}
+
+// profileDate возвращает дату создания профиля из _metadata или время изменения файла
+func profileDate(fileData []byte, fileName string) string {
+	var profile struct {
+		Metadata struct {
+			CreationDate string `json:"creation_date"`
+		} `json:"_metadata"`
+	}
+	if json.Unmarshal(fileData, &profile) == nil && profile.Metadata.CreationDate != "" {
+		return profile.Metadata.CreationDate
+	}
+
+	if info, err := os.Stat(fileName); err == nil {
+		return info.ModTime().Format("2006-01-02 15:04:05")
+	}
+	return time.Now().Format("2006-01-02 15:04:05")
+}
+
+// profilePreview форматирует JSON с отступами и обрезает его до profilePreviewLimit
+func profilePreview(fileData []byte) string {
+	var pretty bytes.Buffer
+	if err := json.Indent(&pretty, fileData, "", "  "); err != nil {
+		return ""
+	}
+
+	preview := []rune(pretty.String())
+	if len(preview) <= profilePreviewLimit {
+		return string(preview)
+	}
+	return string(preview[:profilePreviewLimit]) + "\n…"
+}
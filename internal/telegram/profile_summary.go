@@ -0,0 +1,303 @@
+package telegram
+
+import (
+	"encoding/json"
+	"fmt"
+	"interview-bot-complete/internal/extractor"
+	"interview-bot-complete/internal/language"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// summaryMaxItems - столько элементов массива показывается в поле резюме
+	summaryMaxItems = 8
+	// summaryMaxValueLength - предел длины значения поля резюме в символах
+	summaryMaxValueLength = 300
+	// summaryFooterReserve - место в сообщении для текста после резюме
+	summaryFooterReserve = 300
+)
+
+// summaryFacts - ключевые поля профиля, которые показываются в резюме открыто
+var summaryFacts = []struct {
+	Icon  string
+	Field string
+}{
+	{"👤", "name"},
+	{"🎂", "age"},
+	{"📍", "current_city"},
+	{"🎓", "university"},
+	{"💼", "current_position"},
+}
+
+// summaryLocale - подписи резюме профиля на одном языке
+type summaryLocale struct {
+	// Facts - подписи ключевых фактов, Fields - подписи полей в разделах
+	Facts, Fields map[string]string
+	// Dimensions - названия разделов профиля по profileDimension.ID
+	Dimensions map[string]string
+	Yes, No    string
+	// More - хвост сокращенного списка: %d - сколько элементов не показано
+	More string
+	// ChartCaption - заголовок подписи диаграммы профиля
+	ChartCaption string
+	// Тексты сообщения с резюме
+	Completed, Title, NoSummary, Saved, SavedFull, Disclaimer, FileHint, HistoryHint string
+}
+
+// summaryLocales - подписи и тексты резюме по языку
+var summaryLocales = map[string]summaryLocale{
+	language.Russian: {
+		Facts: map[string]string{
+			"name":             "Имя",
+			"age":              "Возраст",
+			"current_city":     "Город",
+			"university":       "Университет",
+			"current_position": "Позиция",
+		},
+		Fields: profileFieldLabels,
+		Dimensions: map[string]string{
+			"skills":      "Навыки",
+			"career":      "Карьера",
+			"education":   "Образование",
+			"interests":   "Интересы",
+			"personality": "Личность",
+			"goals":       "Цели",
+			"experience":  "Опыт",
+		},
+		Yes:          "да",
+		No:           "нет",
+		More:         "и еще %d",
+		ChartCaption: "📊 Профиль по разделам (число пунктов)",
+		Completed:    "🎯 Анализ профиля завершен!",
+		Title:        "📊 Краткое резюме профиля:",
+		NoSummary:    "Профиль создан, но не удалось сгенерировать резюме.",
+		Saved:        "💾 Профиль сохранен в файл",
+		SavedFull:    "💾 Полный профиль сохранен в JSON файле",
+		Disclaimer:   "Этот анализ создан искусственным интеллектом на основе ваших ответов.",
+		FileHint:     "Используйте /getprofile для получения файла",
+		HistoryHint:  "Файл профиля - кнопка «Профиль» в /history",
+	},
+	language.English: {
+		Facts: map[string]string{
+			"name":             "Name",
+			"age":              "Age",
+			"current_city":     "City",
+			"university":       "University",
+			"current_position": "Position",
+		},
+		Fields: englishFieldLabels,
+		Dimensions: map[string]string{
+			"skills":      "Skills",
+			"career":      "Career",
+			"education":   "Education",
+			"interests":   "Interests",
+			"personality": "Personality",
+			"goals":       "Goals",
+			"experience":  "Experience",
+		},
+		Yes:          "yes",
+		No:           "no",
+		More:         "and %d more",
+		ChartCaption: "📊 Profile by section (number of items)",
+		Completed:    "🎯 Profile analysis complete!",
+		Title:        "📊 Profile summary:",
+		NoSummary:    "The profile was created, but the summary could not be generated.",
+		Saved:        "💾 The profile is saved to a file",
+		SavedFull:    "💾 The full profile is saved to a JSON file",
+		Disclaimer:   "This analysis was generated by artificial intelligence from your answers.",
+		FileHint:     "Use /getprofile to get the file",
+		HistoryHint:  "The profile file is under the «Profile» button in /history",
+	},
+}
+
+// summaryLocaleFor возвращает подписи резюме на языке lang (по умолчанию - русские)
+func summaryLocaleFor(lang string) summaryLocale {
+	locale, ok := summaryLocales[lang]
+	if !ok {
+		locale = summaryLocales[language.Default]
+	}
+	return locale
+}
+
+// fieldLabel возвращает подпись поля на языке подписей; поле без подписи показывается по имени
+func (l summaryLocale) fieldLabel(field string) string {
+	if label, ok := l.Fields[field]; ok {
+		return label
+	}
+	return strings.ReplaceAll(field, "_", " ")
+}
+
+// profileFieldLabels - подписи полей профиля в разделах резюме; поле без подписи показывается по имени
+var profileFieldLabels = map[string]string{
+	"hard_skills":            "Профессиональные навыки",
+	"soft_skills":            "Гибкие навыки",
+	"programming_languages":  "Языки программирования",
+	"tools_and_technologies": "Инструменты и технологии",
+	"certifications":         "Сертификаты",
+	"current_position":       "Позиция",
+	"work_experience_years":  "Опыт работы, лет",
+	"previous_companies":     "Прошлые компании",
+	"career_goals":           "Карьерные цели",
+	"university":             "Университет",
+	"education_level":        "Уровень образования",
+	"field_of_study":         "Специальность",
+	"graduation_year":        "Год выпуска",
+	"hobbies":                "Хобби",
+	"interests":              "Интересы",
+	"favorite_books":         "Любимые книги",
+	"favorite_movies":        "Любимые фильмы",
+	"sports":                 "Спорт",
+	"personality_traits":     "Черты характера",
+	"values":                 "Ценности",
+	"motivations":            "Мотивация",
+	"work_style":             "Стиль работы",
+	"short_term_goals":       "Ближайшие цели",
+	"long_term_goals":        "Долгосрочные цели",
+	"dream_projects":         "Проекты мечты",
+	"languages_spoken":       "Языки",
+	"travel_experience":      "Путешествия",
+	"volunteer_experience":   "Волонтерство",
+	"achievements":           "Достижения",
+}
+
+// englishFieldLabels - подписи полей профиля для интервью на английском
+var englishFieldLabels = map[string]string{
+	"hard_skills":            "Hard skills",
+	"soft_skills":            "Soft skills",
+	"programming_languages":  "Programming languages",
+	"tools_and_technologies": "Tools and technologies",
+	"certifications":         "Certifications",
+	"current_position":       "Position",
+	"work_experience_years":  "Work experience, years",
+	"previous_companies":     "Previous companies",
+	"career_goals":           "Career goals",
+	"university":             "University",
+	"education_level":        "Education level",
+	"field_of_study":         "Field of study",
+	"graduation_year":        "Graduation year",
+	"hobbies":                "Hobbies",
+	"interests":              "Interests",
+	"favorite_books":         "Favorite books",
+	"favorite_movies":        "Favorite movies",
+	"sports":                 "Sports",
+	"personality_traits":     "Personality traits",
+	"values":                 "Values",
+	"motivations":            "Motivations",
+	"work_style":             "Work style",
+	"short_term_goals":       "Short-term goals",
+	"long_term_goals":        "Long-term goals",
+	"dream_projects":         "Dream projects",
+	"languages_spoken":       "Languages",
+	"travel_experience":      "Travel",
+	"volunteer_experience":   "Volunteering",
+	"achievements":           "Achievements",
+}
+
+// writeProfileSummary добавляет резюме профиля: ключевые факты открыто, а разделы профиля
+// (profileDimensions) - свернутыми цитатами, чтобы длинные ценности, карьера и личность
+// не занимали весь экран. Разделы, которые не помещаются в сообщение, пропускаются.
+// Подписи - на языке профиля, чтобы они не расходились с его содержанием.
+func writeProfileSummary(rich *RichText, profileJSON string) error {
+	var profile map[string]interface{}
+	if err := json.Unmarshal([]byte(profileJSON), &profile); err != nil {
+		return err
+	}
+	locale := summaryLocaleFor(extractor.ProfileLanguage(profileJSON))
+
+	for _, fact := range summaryFacts {
+		if value := locale.summaryValue(profile[fact.Field]); value != "" {
+			rich.Text(fact.Icon + " ").Bold(locale.Facts[fact.Field] + ":").Text(" " + value + "\n")
+		}
+	}
+
+	for _, dimension := range profileDimensions {
+		section := locale.summarySection(dimension, profile)
+		if section == nil {
+			continue
+		}
+		if rich.Len()+section.Len()+summaryFooterReserve > maxMessageLength {
+			break
+		}
+		rich.Append(section)
+	}
+	return nil
+}
+
+// summarySection размечает раздел профиля свернутой цитатой; nil - в разделе нет заполненных полей
+func (l summaryLocale) summarySection(dimension profileDimension, profile map[string]interface{}) *RichText {
+	var fields []string
+	for _, field := range dimension.Fields {
+		if l.summaryValue(profile[field]) != "" {
+			fields = append(fields, field)
+		}
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+
+	section := NewRichText().Text("\n").Bold(l.Dimensions[dimension.ID]).Text("\n")
+	section.Wrap(EntityExpandableBlockquote, func() {
+		for i, field := range fields {
+			if i > 0 {
+				section.Text("\n")
+			}
+			section.Bold(l.fieldLabel(field) + ":").Text(" " + l.summaryValue(profile[field]))
+		}
+	})
+	return section.Text("\n")
+}
+
+func fieldLabel(field string) string {
+	return summaryLocaleFor(language.Default).fieldLabel(field)
+}
+
+// summaryValue возвращает значение поля профиля строкой для резюме; пусто - поле не заполнено
+func (l summaryLocale) summaryValue(value interface{}) string {
+	var text string
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		text = strings.TrimSpace(v)
+	case float64:
+		text = strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		text = l.No
+		if v {
+			text = l.Yes
+		}
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			if s := l.summaryValue(item); s != "" {
+				items = append(items, s)
+			}
+		}
+		if len(items) > summaryMaxItems {
+			items = append(items[:summaryMaxItems], fmt.Sprintf(l.More, len(items)-summaryMaxItems))
+		}
+		text = strings.Join(items, ", ")
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		parts := make([]string, 0, len(keys))
+		for _, key := range keys {
+			if s := l.summaryValue(v[key]); s != "" {
+				parts = append(parts, key+": "+s)
+			}
+		}
+		text = strings.Join(parts, "; ")
+	default:
+		text = fmt.Sprint(v)
+	}
+
+	if runes := []rune(text); len(runes) > summaryMaxValueLength {
+		text = string(runes[:summaryMaxValueLength]) + "…"
+	}
+	return text
+}
@@ -0,0 +1,49 @@
+package telegram
+
+import (
+	"fmt"
+	"interview-bot-complete/internal/engine"
+	"strings"
+	"time"
+)
+
+// progressBar рисует полосу прогресса по блокам: пройденные и текущий блоки закрашены
+func progressBar(done, total int) string {
+	if total <= 0 {
+		return ""
+	}
+	if done > total {
+		done = total
+	}
+	if done < 0 {
+		done = 0
+	}
+	return strings.Repeat("▰", done) + strings.Repeat("▱", total-done)
+}
+
+// formatProgress возвращает строку прогресса: полоса, блок, вопрос и оценка оставшегося времени
+func formatProgress(progress engine.Progress) string {
+	text := fmt.Sprintf("%s блок %d/%d, вопрос %d/%d",
+		progressBar(progress.Block, progress.TotalBlocks), progress.Block, progress.TotalBlocks,
+		progress.Question, progress.BlockQuestions)
+	if progress.Remaining > 0 {
+		text += ", осталось " + formatEstimate(progress.Remaining)
+	}
+	return text
+}
+
+// formatRemaining возвращает оценку оставшегося времени для /status
+func formatRemaining(progress engine.Progress) string {
+	if progress.Remaining <= 0 {
+		return "оценка появится после первого ответа"
+	}
+	return formatEstimate(progress.Remaining)
+}
+
+// formatEstimate форматирует оценку времени с точностью до минуты
+func formatEstimate(d time.Duration) string {
+	if d < time.Minute {
+		return "меньше минуты"
+	}
+	return "~" + formatWait(d)
+}
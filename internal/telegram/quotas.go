@@ -0,0 +1,75 @@
+package telegram
+
+import (
+	"fmt"
+	"interview-bot-complete/internal/api"
+	"time"
+)
+
+// activeInterviewTTL - через сколько слот интервью освобождается сам (совпадает со сроком неактивной сессии)
+const activeInterviewTTL = 24 * time.Hour
+
+// Причины, по которым запуск интервью откладывается
+const (
+	deferredTokenBudget = "token_budget"
+	deferredUserQuota   = "user_quota"
+	deferredActiveLimit = "active_limit"
+	deferredMaintenance = "maintenance"
+)
+
+// admitInterview проверяет режим обслуживания, дневной бюджет токенов, дневную квоту пользователя
+// и число идущих интервью. При отказе вежливо сообщает, когда можно вернуться, и возвращает false.
+func (h *Handler) admitInterview(session *UserSession) bool {
+	if h.InMaintenance() {
+		h.deferInterview(session, deferredMaintenance)
+		h.reply(session, maintenanceMessage)
+		return false
+	}
+
+	if h.tokenBudget.Exhausted() {
+		h.deferInterview(session, deferredTokenBudget)
+		h.replyf(session, "🙏 На сегодня бот провел все интервью, которые позволяет бюджет анализа. "+
+			"Пожалуйста, возвращайтесь через %s — будем рады продолжить!", formatWait(h.tokenBudget.ResetIn()))
+		return false
+	}
+
+	allowed, _ := h.interviewQuota.Use(session.UserID)
+	if !allowed {
+		h.deferInterview(session, deferredUserQuota)
+		h.replyf(session, "🙏 За сутки можно начать не больше %d интервью. Новое будет доступно через %s.",
+			h.quotas.InterviewsPerUserPerDay, formatWait(h.interviewQuota.ResetIn(session.UserID)))
+		return false
+	}
+
+	if !h.interviewSlots.Acquire(interviewSlotID(session)) {
+		h.interviewQuota.Refund(session.UserID)
+		h.deferInterview(session, deferredActiveLimit)
+		h.reply(session, "⏳ Сейчас одновременно идет слишком много интервью. Пожалуйста, попробуйте /start через несколько минут.")
+		return false
+	}
+	return true
+}
+
+// deferInterview учитывает отложенный запуск; согласие, если его ждали, остается в силе
+func (h *Handler) deferInterview(session *UserSession, reason string) {
+	h.metrics.InterviewDeferred(reason)
+	h.logger(session).Info("Запуск интервью отложен", "reason", reason)
+	if session.State == StateAwaitingConsent {
+		session.State = StateIdle
+	}
+}
+
+// releaseInterviewSlot освобождает слот завершенного или прерванного интервью
+func (h *Handler) releaseInterviewSlot(session *UserSession) {
+	h.interviewSlots.Release(interviewSlotID(session))
+}
+
+// interviewSlotID - слот занимает сессия: в чате у пользователя идет не больше одного интервью
+func interviewSlotID(session *UserSession) string {
+	return fmt.Sprintf("%d:%d", session.ChatID, session.UserID)
+}
+
+// recordUsage учитывает токены всех запросов к OpenAI в дневном бюджете
+func (h *Handler) recordUsage(model string, usage api.Usage) {
+	h.tokenBudget.RecordUsage(model, usage)
+}
@@ -0,0 +1,149 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"interview-bot-complete/internal/logging"
+	"interview-bot-complete/internal/reporting"
+	"runtime/debug"
+	"time"
+)
+
+// panicStackPreview - сколько символов стека отправляется администратору в Telegram
+const panicStackPreview = 1500
+
+// reportTimeout - максимальное время отправки события во внешний репортер
+const reportTimeout = 10 * time.Second
+
+// AddErrorReporter подключает внешний репортер ошибок (например, Sentry)
+func (h *Handler) AddErrorReporter(reporter reporting.Reporter) {
+	h.reporters = append(h.reporters, reporter)
+}
+
+// Recover оборачивает обработчик обновлений: паника внутри обработки одного обновления
+// логируется и сообщается администраторам, не останавливая бота
+func (h *Handler) Recover(next func(Update)) func(Update) {
+	return func(update Update) {
+		defer func() {
+			if value := recover(); value != nil {
+				event := reporting.Event{
+					Message:  fmt.Sprintf("panic: %v", value),
+					Stack:    string(debug.Stack()),
+					UpdateID: update.UpdateID,
+					Tags:     map[string]string{"source": "update"},
+				}
+				message := update.Message
+				if query := update.CallbackQuery; query != nil {
+					message = query.Message
+					if query.From != nil {
+						event.UserID = query.From.ID
+					}
+				}
+				if message != nil {
+					if message.From != nil && event.UserID == 0 {
+						event.UserID = message.From.ID
+					}
+					if message.Chat != nil {
+						event.ChatID = message.Chat.ID
+						h.bot.SendReply(Destination{ChatID: message.Chat.ID, MessageThreadID: message.MessageThreadID},
+							"⚠️ Произошла внутренняя ошибка. Попробуйте еще раз или используйте /status.")
+					}
+				}
+				h.reportPanic(event)
+			}
+		}()
+		next(update)
+	}
+}
+
+// goSafe запускает фоновую задачу сессии с перехватом паники
+func (h *Handler) goSafe(session *UserSession, task string, fn func()) {
+	go func() {
+		defer func() {
+			if value := recover(); value != nil {
+				h.reportPanic(reporting.Event{
+					Message: fmt.Sprintf("panic: %v", value),
+					Stack:   string(debug.Stack()),
+					UserID:  session.UserID,
+					ChatID:  session.ChatID,
+					Tags:    map[string]string{"source": task},
+				})
+				h.reply(session, "⚠️ Произошла внутренняя ошибка. Администраторы уже уведомлены.")
+			}
+		}()
+		defer func() {
+			session.mu.Lock()
+			defer session.mu.Unlock()
+			h.persistSession(session)
+		}()
+		fn()
+	}()
+}
+
+// goTicker вызывает fn каждые interval в фоне; паника в fn сообщается как в goSafe
+// и не останавливает следующие вызовы
+func (h *Handler) goTicker(task string, interval time.Duration, fn func()) {
+	ticker := time.NewTicker(interval)
+	go func() {
+		for range ticker.C {
+			h.runSafe(task, fn)
+		}
+	}()
+}
+
+// runSafe вызывает fn фоновой задачи task, перехватывая панику
+func (h *Handler) runSafe(task string, fn func()) {
+	defer func() {
+		if value := recover(); value != nil {
+			h.reportPanic(reporting.Event{
+				Message: fmt.Sprintf("panic: %v", value),
+				Stack:   string(debug.Stack()),
+				Tags:    map[string]string{"source": task},
+			})
+		}
+	}()
+	fn()
+}
+
+// reportPanic логирует панику со стеком и рассылает ее администраторам и репортерам
+func (h *Handler) reportPanic(event reporting.Event) {
+	h.baseLogger.Error("ПАНИКА", logging.KeyUserID, event.UserID, logging.KeyChatID, event.ChatID, "update_id", event.UpdateID,
+		"source", event.Tags["source"], "panic", event.Message, "stack", event.Stack)
+
+	h.metrics.RecordError("panic: "+event.Tags["source"], event.Message)
+
+	h.notifyAdmins(fmt.Sprintf("🚨 *Паника в боте*\n\nПользователь: `%d`\nЧат: `%d`\nИсточник: %s\n\n```\n%s\n\n%s\n```",
+		event.UserID, event.ChatID, event.Tags["source"], event.Message, truncateRunes(event.Stack, panicStackPreview)))
+
+	for _, reporter := range h.reporters {
+		ctx, cancel := context.WithTimeout(context.Background(), reportTimeout)
+		if err := reporter.Report(ctx, event); err != nil {
+			h.baseLogger.Warn("Не удалось отправить отчет об ошибке", "error", err)
+		}
+		cancel()
+	}
+}
+
+// notifyAdmins отправляет сообщение в чат администраторов, а если он не задан - каждому администратору
+func (h *Handler) notifyAdmins(text string) {
+	if h.adminChatID != 0 {
+		if err := h.bot.SendMessage(h.adminChatID, text); err != nil {
+			h.baseLogger.Warn("Не удалось уведомить чат администраторов", logging.KeyChatID, h.adminChatID, "error", err)
+		}
+		return
+	}
+	for adminID := range h.admins {
+		if err := h.bot.SendMessage(adminID, text); err != nil {
+			h.baseLogger.Warn("Не удалось уведомить администратора", logging.KeyUserID, adminID, "error", err)
+		}
+	}
+}
+
+// truncateRunes обрезает строку до limit символов
+func truncateRunes(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit]) + "\n…"
+}
@@ -0,0 +1,58 @@
+package telegram
+
+import (
+	"interview-bot-complete/internal/reload"
+	"strconv"
+	"strings"
+)
+
+// SetReloader подключает перезагрузку конфигурации; без нее /reloadconfig недоступна
+func (h *Handler) SetReloader(reloader *reload.Reloader) {
+	h.reloader = reloader
+}
+
+// handleReloadConfigCommand обрабатывает команду /reloadconfig: перечитывает шаблоны интервью
+// и схему профиля. Начатые интервью продолжаются по прежним шаблонам.
+func (h *Handler) handleReloadConfigCommand(session *UserSession) {
+	if !h.requireAdmin(session) {
+		return
+	}
+
+	if h.reloader == nil {
+		h.reply(session, "❌ Перезагрузка конфигурации недоступна.")
+		return
+	}
+
+	report, err := h.reloader.Reload()
+	if err != nil {
+		h.logger(session).Warn("Конфигурация не перезагружена по команде", "error", err)
+		// Текст ошибки содержит пути и YAML, поэтому отправляется блоком кода без разметки Markdown
+		message := NewRichText().
+			Bold("❌ Конфигурация не перезагружена").
+			Text(", действует прежняя.\n\n").
+			Styled(EntityCode, err.Error())
+		h.replyRich(session, message)
+		return
+	}
+
+	h.logger(session).Info("Конфигурация перезагружена по команде")
+	message := NewRichText().Bold("✅ Конфигурация перезагружена").Text("\n\n")
+	message.Text("Шаблоны: " + strings.Join(report.Templates, ", ") + "\n")
+	message.Text("Изменены: " + listOrDash(report.Changed) + "\n")
+	if len(report.Removed) > 0 {
+		message.Text("Удалены: " + strings.Join(report.Removed, ", ") + "\n")
+	}
+	if report.SchemaFields > 0 {
+		message.Text("Полей в схеме профиля: " + strconv.Itoa(report.SchemaFields) + "\n")
+	}
+	message.Text("\nНовые интервью начнутся по новой конфигурации, начатые продолжатся по прежней.")
+	h.replyRich(session, message)
+}
+
+// listOrDash возвращает элементы через запятую или «—» для пустого списка
+func listOrDash(items []string) string {
+	if len(items) == 0 {
+		return "—"
+	}
+	return strings.Join(items, ", ")
+}
@@ -0,0 +1,49 @@
+package telegram
+
+import (
+	"fmt"
+	"interview-bot-complete/internal/research"
+	"time"
+)
+
+// handleResearchExportCommand обрабатывает команду /researchexport: отправляет администратору
+// обезличенный набор данных (zip с JSONL интервью и профилей)
+func (h *Handler) handleResearchExportCommand(session *UserSession) {
+	if !h.requireAdmin(session) {
+		return
+	}
+
+	if h.researchSalt == "" {
+		h.reply(session, "❌ Обезличенная выгрузка не настроена. Задайте `RESEARCH_PSEUDONYM_SALT` - секрет стабильных псевдонимов.")
+		return
+	}
+
+	opts := research.Options{Salt: h.researchSalt}
+	if h.tenant != nil {
+		// Бот арендатора выгружает только свои интервью
+		opts.Tenant = h.tenant.ID
+	}
+	if h.extractor != nil {
+		opts.Profiles = h.extractor.GetLastProfileJSON
+	}
+
+	h.reply(session, "⏳ Собираю обезличенную выгрузку...")
+	h.goSafe(session, "research_export", func() {
+		data, report, err := research.Export(opts)
+		if err != nil {
+			h.logger(session).Error("Ошибка обезличенной выгрузки", "error", err)
+			h.reply(session, "❌ Не удалось собрать выгрузку.")
+			return
+		}
+		h.logger(session).Info("Обезличенная выгрузка собрана", "interviews", report.Interviews, "profiles", report.Profiles, "skipped", report.Skipped)
+
+		fileName := fmt.Sprintf("research_export_%s.zip", time.Now().Format("2006-01-02"))
+		caption := fmt.Sprintf("🔬 Обезличенная выгрузка: интервью %d, профилей %d", report.Interviews, report.Profiles)
+		if report.Skipped > 0 {
+			caption += fmt.Sprintf(", не прочитано %d", report.Skipped)
+		}
+		if err := h.bot.SendDocumentTo(h.destination(session), data, fileName, caption); err != nil {
+			h.reply(session, "❌ Ошибка отправки выгрузки: "+err.Error())
+		}
+	})
+}
@@ -0,0 +1,76 @@
+package telegram
+
+import (
+	"fmt"
+	"interview-bot-complete/internal/storage"
+	"strings"
+)
+
+// Данные inline-кнопок незавершенного интервью при /start: resume:<continue|fresh>:<id интервью>
+const (
+	resumeCallbackPrefix = "resume:"
+	resumeContinue       = "continue"
+	resumeFresh          = "fresh"
+)
+
+// offerResume предлагает продолжить незавершенное интервью или начать новое. Аргументы /start
+// (приглашение, арендатор) откладываются до решения пользователя.
+func (h *Handler) offerResume(session *UserSession, args []string) {
+	session.PendingStart = args
+
+	cfg := h.configFor(session)
+	keyboard := &InlineKeyboardMarkup{
+		InlineKeyboard: [][]InlineKeyboardButton{{
+			{Text: fmt.Sprintf("▶️ Продолжить (блок %d/%d)", session.CurrentBlock, cfg.GetTotalBlocks()),
+				CallbackData: resumeCallbackPrefix + resumeContinue + ":" + session.InterviewID},
+			{Text: "🆕 Начать заново", CallbackData: resumeCallbackPrefix + resumeFresh + ":" + session.InterviewID},
+		}},
+	}
+	text := fmt.Sprintf("У вас есть незавершенное интервью: блок %d/%d «%s».\n\n"+
+		"Продолжить его или начать заново? При новом старте ответы незавершенного интервью будут удалены.",
+		session.CurrentBlock, cfg.GetTotalBlocks(), h.getCurrentBlockTitle(session))
+	if err := h.bot.SendReplyWithKeyboard(h.destination(session), text, keyboard); err != nil {
+		h.logger(session).Warn("Не удалось предложить продолжение интервью", "error", err)
+	}
+}
+
+// handleResumeCallback продолжает незавершенное интервью или сбрасывает его и начинает новое
+func (h *Handler) handleResumeCallback(query *CallbackQuery, session *UserSession) {
+	parts := strings.SplitN(strings.TrimPrefix(query.Data, resumeCallbackPrefix), ":", 2)
+	h.bot.RemoveInlineKeyboard(query.Message.Chat.ID, query.Message.MessageID)
+	if len(parts) != 2 || !session.isActive() || parts[1] != session.InterviewID ||
+		session.ThreadID != query.Message.MessageThreadID {
+		h.bot.AnswerCallbackQuery(query.ID, "Эта кнопка больше не активна")
+		return
+	}
+	h.bot.AnswerCallbackQuery(query.ID, "")
+
+	args := session.PendingStart
+	session.PendingStart = nil
+	if parts[0] == resumeFresh {
+		h.recordDropoff(session, storage.DropoffRestarted)
+		h.releaseInterviewSlot(session)
+		h.resetSession(session)
+		h.reply(session, "🔄 Незавершенное интервью сброшено.")
+		h.handleStartCommand(args, session)
+		return
+	}
+	h.resumeInterview(session)
+}
+
+// resumeInterview повторяет то, чего ждет незавершенное интервью: вопрос или проверку ответов блока
+func (h *Handler) resumeInterview(session *UserSession) {
+	switch session.State {
+	case StateWaitingAnswer:
+		h.reply(session, "▶️ Продолжаем интервью с того места, где остановились.")
+		h.resendCurrentQuestion(session)
+	case StateReviewingBlock:
+		h.reply(session, "▶️ Продолжаем интервью: проверьте ответы блока.")
+		h.sendBlockReview(session)
+	case StateEditingAnswer:
+		h.reply(session, "▶️ Продолжаем интервью, исправление ответа отменено.")
+		h.returnFromEdit(session)
+	default:
+		h.reply(session, "▶️ Интервью продолжается, следующий вопрос скоро придет.")
+	}
+}
@@ -0,0 +1,96 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Данные inline-кнопок проверки блока: review:ok:<id интервью>:<блок> и review:fix:<id интервью>:<блок>:<N>
+const (
+	reviewCallbackPrefix = "review:"
+	reviewConfirm        = "ok"
+	reviewFix            = "fix"
+)
+
+const (
+	// reviewAnswerLimit - сколько символов ответа показывать в списке на проверку
+	reviewAnswerLimit = 300
+	// reviewButtonsPerRow - кнопок «Исправить N» в одном ряду
+	reviewButtonsPerRow = 4
+)
+
+// markdownEscaper экранирует разметку Markdown в ответах пользователя
+var markdownEscaper = strings.NewReplacer("_", "\\_", "*", "\\*", "`", "\\`", "[", "\\[")
+
+// sendBlockReview показывает ответы завершенного блока с кнопками «Все верно» и «Исправить N»
+func (h *Handler) sendBlockReview(session *UserSession) {
+	cfg := h.configFor(session)
+	blockRef := session.InterviewID + ":" + strconv.Itoa(session.CurrentBlock)
+
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("📝 *Проверьте ответы блока %d/%d: %s*\n\n",
+		session.CurrentBlock, cfg.GetTotalBlocks(), h.getCurrentBlockTitle(session)))
+
+	var fixButtons []InlineKeyboardButton
+	for i, qa := range session.CurrentDialogue {
+		if qa.Answer == "" {
+			continue
+		}
+		answer := markdownEscaper.Replace(truncateRunes(qa.Answer, reviewAnswerLimit))
+		text.WriteString(fmt.Sprintf("*%d. %s*\n%s\n\n", i+1, qa.Question, answer))
+		fixButtons = append(fixButtons, InlineKeyboardButton{
+			Text:         fmt.Sprintf("✏️ Исправить %d", i+1),
+			CallbackData: fmt.Sprintf("%s%s:%s:%d", reviewCallbackPrefix, reviewFix, blockRef, i+1),
+		})
+	}
+	text.WriteString("Если ответ записан неточно (например, при распознавании голоса), исправьте его до создания саммари блока.")
+
+	keyboard := &InlineKeyboardMarkup{InlineKeyboard: [][]InlineKeyboardButton{{
+		{Text: "✅ Все верно", CallbackData: reviewCallbackPrefix + reviewConfirm + ":" + blockRef},
+	}}}
+	for len(fixButtons) > 0 {
+		n := min(reviewButtonsPerRow, len(fixButtons))
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, fixButtons[:n])
+		fixButtons = fixButtons[n:]
+	}
+
+	h.markDelivery(session, h.bot.SendReplyWithKeyboard(h.destination(session), text.String(), keyboard))
+}
+
+// handleReviewCallback обрабатывает подтверждение или исправление ответов блока
+func (h *Handler) handleReviewCallback(query *CallbackQuery, session *UserSession) {
+	parts := strings.Split(strings.TrimPrefix(query.Data, reviewCallbackPrefix), ":")
+	if len(parts) < 3 || session.State != StateReviewingBlock ||
+		parts[1] != session.InterviewID || parts[2] != strconv.Itoa(session.CurrentBlock) {
+		h.bot.AnswerCallbackQuery(query.ID, "Эта кнопка больше не активна")
+		h.bot.RemoveInlineKeyboard(query.Message.Chat.ID, query.Message.MessageID)
+		return
+	}
+
+	switch {
+	case parts[0] == reviewConfirm:
+		h.bot.RemoveInlineKeyboard(query.Message.Chat.ID, query.Message.MessageID)
+		h.bot.AnswerCallbackQuery(query.ID, "")
+		session.State = StateInterview
+		h.reply(session, "📝 Обрабатываю блок...")
+		prompt, events, err := h.engine.ConfirmBlock(context.Background(), &session.Session)
+		if err != nil {
+			// Ответы уже подтверждены: завершение блока повторит любое следующее сообщение
+			session.State = StateWaitingAnswer
+		}
+		h.deliverStep(session, prompt, events, err)
+	case parts[0] == reviewFix && len(parts) == 4:
+		number, err := strconv.Atoi(parts[3])
+		if err != nil || number < 1 || number > len(session.CurrentDialogue) || session.CurrentDialogue[number-1].Answer == "" {
+			h.bot.AnswerCallbackQuery(query.ID, "Эта кнопка больше не активна")
+			return
+		}
+		h.bot.RemoveInlineKeyboard(query.Message.Chat.ID, query.Message.MessageID)
+		h.bot.AnswerCallbackQuery(query.ID, "")
+		h.beginAnswerEdit(session, number-1)
+	default:
+		h.bot.AnswerCallbackQuery(query.ID, "")
+	}
+}
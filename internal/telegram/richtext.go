@@ -0,0 +1,98 @@
+package telegram
+
+import (
+	"strings"
+	"unicode/utf16"
+)
+
+// Типы сущностей сообщения Telegram
+const (
+	EntityBold                 = "bold"
+	EntityItalic               = "italic"
+	EntityCode                 = "code"
+	EntityExpandableBlockquote = "expandable_blockquote" // цитата свернута, пока ее не раскроют
+)
+
+// maxMessageLength - предел длины текста сообщения Telegram в UTF-16
+const maxMessageLength = 4096
+
+// RichText собирает текст сообщения с сущностями оформления. В отличие от Markdown текст
+// не нужно экранировать: оформление задается смещениями, а не символами разметки.
+type RichText struct {
+	text     strings.Builder
+	length   int // длина текста в UTF-16 - в этих единицах Telegram считает смещения сущностей
+	entities []MessageEntity
+}
+
+// NewRichText создает пустой размеченный текст
+func NewRichText() *RichText {
+	return &RichText{}
+}
+
+// Text добавляет текст без оформления
+func (r *RichText) Text(s string) *RichText {
+	r.text.WriteString(s)
+	r.length += utf16Len(s)
+	return r
+}
+
+// Styled добавляет текст с оформлением entityType
+func (r *RichText) Styled(entityType, s string) *RichText {
+	return r.Wrap(entityType, func() { r.Text(s) })
+}
+
+// Bold добавляет полужирный текст
+func (r *RichText) Bold(s string) *RichText {
+	return r.Styled(EntityBold, s)
+}
+
+// Italic добавляет курсив
+func (r *RichText) Italic(s string) *RichText {
+	return r.Styled(EntityItalic, s)
+}
+
+// Wrap оформляет сущностью entityType все, что добавит write (вложенное оформление сохраняется).
+// Пустой фрагмент сущностью не отмечается.
+func (r *RichText) Wrap(entityType string, write func()) *RichText {
+	offset := len(r.entities)
+	start := r.length
+	write()
+	if r.length == start {
+		return r
+	}
+	// Внешняя сущность идет раньше вложенных: Telegram ждет сущности по возрастанию смещения
+	r.entities = append(r.entities, MessageEntity{})
+	copy(r.entities[offset+1:], r.entities[offset:])
+	r.entities[offset] = MessageEntity{Type: entityType, Offset: start, Length: r.length - start}
+	return r
+}
+
+// Append добавляет другой размеченный текст с его оформлением
+func (r *RichText) Append(other *RichText) *RichText {
+	for _, entity := range other.entities {
+		entity.Offset += r.length
+		r.entities = append(r.entities, entity)
+	}
+	r.text.WriteString(other.String())
+	r.length += other.length
+	return r
+}
+
+// Len возвращает длину текста в UTF-16
+func (r *RichText) Len() int {
+	return r.length
+}
+
+// String возвращает текст сообщения
+func (r *RichText) String() string {
+	return r.text.String()
+}
+
+// Entities возвращает сущности оформления текста
+func (r *RichText) Entities() []MessageEntity {
+	return r.entities
+}
+
+func utf16Len(s string) int {
+	return len(utf16.Encode([]rune(s)))
+}
@@ -0,0 +1,119 @@
+package telegram
+
+import (
+	"errors"
+	"fmt"
+	"interview-bot-complete/internal/logging"
+	"interview-bot-complete/internal/search"
+	"strings"
+)
+
+const (
+	// searchResultsLimit - сколько найденных интервью показывает /search
+	searchResultsLimit = 5
+	// searchLabelLength - предел длины вопроса над фрагментом ответа в символах
+	searchLabelLength = 80
+	// searchFooterReserve - место в сообщении для строки о непоказанных интервью
+	searchFooterReserve = 200
+)
+
+// SetSearch подключает поиск по архиву интервью; без него /search недоступна
+func (h *Handler) SetSearch(index *search.Index) {
+	h.search = index
+}
+
+// indexForSearch в фоне добавляет в поисковый индекс интервью с новым профилем
+func (h *Handler) indexForSearch(interviewID string) {
+	if h.search == nil {
+		return
+	}
+	go func() {
+		if err := h.search.Add(interviewID); err != nil {
+			h.baseLogger.Warn("Не удалось добавить интервью в поисковый индекс", logging.KeyInterviewID, interviewID, "error", err)
+		}
+	}()
+}
+
+// handleSearchCommand обрабатывает команду /search <запрос>: интервью, в ответах или профиле
+// которых встречаются все слова запроса, с фрагментами совпадений
+func (h *Handler) handleSearchCommand(args []string, session *UserSession) {
+	if !h.requireAdmin(session) {
+		return
+	}
+
+	if len(args) == 0 {
+		h.reply(session, "Использование: /search <запрос>\nНайдутся интервью со всеми словами запроса; `слово*` ищет по началу слова (выгора* - выгорание, выгорания).")
+		return
+	}
+
+	if h.search == nil {
+		h.reply(session, "❌ Поиск по архиву недоступен.")
+		return
+	}
+
+	opts := search.Options{Limit: searchResultsLimit}
+	if h.tenant != nil {
+		// Бот арендатора ищет только в своих интервью
+		opts.Tenant = h.tenant.ID
+	}
+	query := strings.Join(args, " ")
+	results, err := h.search.Search(query, opts)
+	if errors.Is(err, search.ErrEmptyQuery) {
+		h.reply(session, "❌ В запросе нет слов для поиска: слова короче двух букв не учитываются.")
+		return
+	}
+	if err != nil {
+		h.reply(session, "❌ Ошибка поиска: "+err.Error())
+		return
+	}
+
+	h.replyRich(session, formatSearchResults(results))
+}
+
+// formatSearchResults формирует список найденных интервью с фрагментами, совпадения выделены
+func formatSearchResults(results *search.Results) *RichText {
+	message := NewRichText()
+	if results.Total == 0 {
+		return message.Text(fmt.Sprintf("🔎 По запросу «%s» ничего не найдено.", results.Query))
+	}
+
+	message.Bold(fmt.Sprintf("🔎 Найдено интервью: %d", results.Total)).Text(fmt.Sprintf(" по запросу «%s»\n", results.Query))
+	shown := 0
+	for i, hit := range results.Hits {
+		entry := NewRichText().Text(fmt.Sprintf("\n%d. ", i+1)).Styled(EntityCode, hit.InterviewID).
+			Text(" · " + historyDate(hit.Timestamp) + "\n")
+		for _, snippet := range hit.Snippets {
+			writeSearchSnippet(entry, snippet)
+		}
+		if message.Len()+entry.Len()+searchFooterReserve > maxMessageLength {
+			break
+		}
+		message.Append(entry)
+		shown++
+	}
+	if shown < results.Total {
+		message.Text(fmt.Sprintf("\nПоказаны первые %d. Уточните запрос или используйте /api/search.", shown))
+	}
+	return message
+}
+
+// writeSearchSnippet добавляет фрагмент: откуда он (вопрос или поле профиля) и текст с выделенными совпадениями
+func writeSearchSnippet(rich *RichText, snippet search.Snippet) {
+	label := snippet.Label
+	if snippet.Source == search.SourceProfile {
+		rich.Text("🧾 ").Italic(fieldLabel(label) + ":").Text(" ")
+	} else {
+		if runes := []rune(label); len(runes) > searchLabelLength {
+			label = string(runes[:searchLabelLength]) + "…"
+		}
+		rich.Text("💬 ").Italic(label).Text("\n")
+	}
+
+	text := []rune(snippet.Text)
+	position := 0
+	for _, span := range snippet.Highlights {
+		rich.Text(string(text[position:span.Start])).Bold(string(text[span.Start:span.End]))
+		position = span.End
+	}
+	rich.Text(string(text[position:]) + "\n")
+}
@@ -0,0 +1,158 @@
+package telegram
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// sendAttempts - число попыток отправки сообщения или файла
+	sendAttempts = 4
+	// sendBackoff - пауза перед второй попыткой, дальше удваивается
+	sendBackoff = time.Second
+	// maxRetryAfter - наибольшее ожидание по retry_after; при большем сообщение не ждет лимита
+	maxRetryAfter = time.Minute
+)
+
+// SendError - ошибка Telegram API при отправке
+type SendError struct {
+	Code        int
+	Description string
+	// RetryAfter - через сколько можно повторить запрос (ответ 429 Too Many Requests)
+	RetryAfter time.Duration
+}
+
+func (e *SendError) Error() string {
+	return fmt.Sprintf("Telegram API вернул ошибку %d: %s", e.Code, e.Description)
+}
+
+// temporary сообщает, имеет ли смысл повторить запрос: лимит частоты или сбой на стороне Telegram
+func (e *SendError) temporary() bool {
+	return e.Code == http.StatusTooManyRequests || e.Code >= http.StatusInternalServerError
+}
+
+// sendResponse - ответ методов отправки Bot API
+type sendResponse struct {
+	OK          bool   `json:"ok"`
+	ErrorCode   int    `json:"error_code"`
+	Description string `json:"description"`
+	Parameters  *struct {
+		RetryAfter int `json:"retry_after"`
+	} `json:"parameters,omitempty"`
+}
+
+// sentMessageID возвращает message_id отправленного сообщения из ответа sendMessage (0 - нет)
+func sentMessageID(body []byte) int {
+	var response struct {
+		Result struct {
+			MessageID int `json:"message_id"`
+		} `json:"result"`
+	}
+	if json.Unmarshal(body, &response) != nil {
+		return 0
+	}
+	return response.Result.MessageID
+}
+
+// parseSendResponse проверяет ответ метода отправки; status - HTTP статус ответа
+func parseSendResponse(status int, body []byte) error {
+	var response sendResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		if status >= http.StatusInternalServerError {
+			return &SendError{Code: status, Description: http.StatusText(status)}
+		}
+		return fmt.Errorf("ошибка парсинга ответа: %w", err)
+	}
+	if response.OK {
+		return nil
+	}
+
+	sendErr := &SendError{Code: response.ErrorCode, Description: response.Description}
+	if sendErr.Code == 0 {
+		sendErr.Code = status
+	}
+	if response.Parameters != nil {
+		sendErr.RetryAfter = time.Duration(response.Parameters.RetryAfter) * time.Second
+	}
+	return sendErr
+}
+
+// sendQueue упорядочивает отправку по чатам: сообщения одного чата уходят по очереди,
+// и пока одно ждет повтора, следующие за ним не обгоняют его
+type sendQueue struct {
+	mu    sync.Mutex
+	chats map[int64]*chatQueue
+}
+
+// chatQueue - очередь отправки одного чата
+type chatQueue struct {
+	mu      sync.Mutex
+	waiting int // отправки, ждущие очереди или выполняемые; пустая очередь удаляется
+}
+
+func newSendQueue() *sendQueue {
+	return &sendQueue{chats: make(map[int64]*chatQueue)}
+}
+
+// do выполняет send в очереди чата, повторяя временные ошибки с растущей паузой
+// (для 429 - с паузой retry_after из ответа Telegram)
+func (q *sendQueue) do(chatID int64, send func() error) error {
+	q.mu.Lock()
+	chat, ok := q.chats[chatID]
+	if !ok {
+		chat = &chatQueue{}
+		q.chats[chatID] = chat
+	}
+	chat.waiting++
+	q.mu.Unlock()
+
+	defer func() {
+		q.mu.Lock()
+		chat.waiting--
+		if chat.waiting == 0 {
+			delete(q.chats, chatID)
+		}
+		q.mu.Unlock()
+	}()
+
+	chat.mu.Lock()
+	defer chat.mu.Unlock()
+	return retrySend(chatID, send)
+}
+
+// retrySend повторяет отправку, пока ошибка временная и не исчерпаны попытки
+func retrySend(chatID int64, send func() error) error {
+	backoff := sendBackoff
+	var err error
+	for attempt := 1; attempt <= sendAttempts; attempt++ {
+		if err = send(); err == nil {
+			return nil
+		}
+
+		wait := backoff
+		var sendErr *SendError
+		if errors.As(err, &sendErr) {
+			if !sendErr.temporary() {
+				return err
+			}
+			if sendErr.RetryAfter > maxRetryAfter {
+				return err
+			}
+			if sendErr.RetryAfter > 0 {
+				wait = sendErr.RetryAfter
+			}
+		}
+		if attempt == sendAttempts {
+			break
+		}
+		slog.Warn("Ошибка отправки в Telegram, повтор", "chat_id", chatID, "attempt", attempt, "wait", wait, "error", err)
+		time.Sleep(wait)
+		backoff *= 2
+	}
+	return fmt.Errorf("сообщение не отправлено после %d попыток: %w", sendAttempts, err)
+}
@@ -0,0 +1,160 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+const (
+	// sessionDumpVersion - версия формата файла /dumpsession
+	sessionDumpVersion = 1
+	// maxSessionDumpFileSize - предельный размер файла, загружаемого /loadsession
+	maxSessionDumpFileSize = 5 << 20
+)
+
+// sessionDump - снимок сессии пользователя для воспроизведения ошибок локально
+type sessionDump struct {
+	Version  int          `json:"version"`
+	DumpedAt time.Time    `json:"dumped_at"`
+	Session  *UserSession `json:"session"`
+}
+
+// handleDumpSessionCommand обрабатывает команду /dumpsession <user_id>: сессия пользователя
+// (состояние, диалог текущего блока, саммари и результат) отправляется JSON файлом
+func (h *Handler) handleDumpSessionCommand(args []string, session *UserSession) {
+	if !h.requireAdmin(session) {
+		return
+	}
+
+	if len(args) != 1 {
+		h.reply(session, "Использование: /dumpsession <user_id>")
+		return
+	}
+	userID, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		h.reply(session, "❌ ID пользователя должен быть числом.")
+		return
+	}
+
+	target, shared := h.findUserSession(userID)
+	if target == nil {
+		h.replyf(session, "❌ Сессия пользователя %d не найдена.", userID)
+		return
+	}
+	// Сессию в памяти одновременно может менять обработка сообщений пользователя
+	if shared && target != session {
+		unlock, ok := h.lockWithin(target)
+		if !ok {
+			h.reply(session, "⏳ Сессия пользователя сейчас обрабатывается, попробуйте через несколько секунд.")
+			return
+		}
+		defer unlock()
+	}
+
+	data, err := json.MarshalIndent(sessionDump{Version: sessionDumpVersion, DumpedAt: time.Now(), Session: target}, "", "  ")
+	if err != nil {
+		h.reply(session, "❌ Ошибка сериализации сессии: "+err.Error())
+		return
+	}
+
+	fileName := fmt.Sprintf("session_%d_%s.json", userID, time.Now().Format("20060102_150405"))
+	caption := fmt.Sprintf("🗂 Сессия %d: %s. Восстановить: отправьте файл с подписью /loadsession", userID, h.getStateDescription(target.State))
+	if err := h.bot.SendDocumentTo(h.destination(session), data, fileName, caption); err != nil {
+		h.logger(session).Error("Ошибка отправки снимка сессии", "target_user_id", userID, "error", err)
+		h.reply(session, "❌ Не удалось отправить файл: "+err.Error())
+		return
+	}
+	h.logger(session).Info("Снимок сессии выгружен", "target_user_id", userID, "state", target.State)
+}
+
+// handleLoadSessionCommand восстанавливает сессию из файла /dumpsession в сессию администратора
+// в текущем чате: интервью продолжается с того же места от его имени
+func (h *Handler) handleLoadSessionCommand(document *Document, session *UserSession) {
+	if !h.requireAdmin(session) {
+		return
+	}
+
+	if document == nil {
+		h.reply(session, "Использование: отправьте файл из /dumpsession с подписью /loadsession")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	data, err := h.bot.DownloadFile(ctx, document.FileID, maxSessionDumpFileSize)
+	if err != nil {
+		h.reply(session, "❌ Не удалось получить файл: "+err.Error())
+		return
+	}
+
+	var dump sessionDump
+	if err := json.Unmarshal(data, &dump); err != nil {
+		h.reply(session, "❌ Ошибка разбора снимка сессии: "+err.Error())
+		return
+	}
+	if dump.Version != sessionDumpVersion || dump.Session == nil {
+		h.replyf(session, "❌ Неподдерживаемый снимок сессии (версия %d, ожидается %d).", dump.Version, sessionDumpVersion)
+		return
+	}
+	restored := dump.Session
+	if restored.Result != nil {
+		if _, ok := h.templates.Get(restored.TemplateID); !ok {
+			h.replyf(session, "❌ Шаблон %q сессии не найден.", restored.TemplateID)
+			return
+		}
+	}
+
+	sourceUserID := restored.UserID
+	h.releaseInterviewSlot(session)
+	h.releaseThread(session)
+
+	// Сессия привязывается к чату администратора; ссылки на сообщения исходного чата недействительны
+	restored.UserID = session.UserID
+	restored.ChatID = session.ChatID
+	restored.ThreadID = session.ThreadID
+	restored.IsGroup = session.IsGroup
+	restored.LastMessageID = session.LastMessageID
+	restored.LastActivity = time.Now()
+	restored.LastAnswer = nil
+	restored.PendingDelivery = false
+	restored.ReplyKeyboard = false
+	restored.SupervisorChatID = 0
+	restored.Supervising = 0
+	restored.StoreVersion = session.StoreVersion
+	session.restore(restored)
+
+	h.logger(session).Info("Сессия восстановлена из снимка", "target_user_id", sourceUserID, "state", session.State)
+	h.replyf(session, "📥 Сессия пользователя %d восстановлена (снимок от %s), состояние: %s.",
+		sourceUserID, dump.DumpedAt.Format("02.01.2006 15:04"), h.getStateDescription(session.State))
+
+	switch session.State {
+	case StateWaitingAnswer:
+		if prompt := h.engine.Current(&session.Session); prompt != nil {
+			h.sendPrompt(session, prompt)
+		}
+	case StateReviewingBlock:
+		h.sendBlockReview(session)
+	}
+}
+
+// findUserSession возвращает последнюю активную сессию пользователя среди чатов (shared - сессия
+// из памяти, доступная другим обработчикам), а если ее нет в памяти - сессию личного чата
+// из внешнего хранилища
+func (h *Handler) findUserSession(userID int64) (session *UserSession, shared bool) {
+	h.sessionsMutex.RLock()
+	var found *UserSession
+	for key, sess := range h.sessions {
+		if key.UserID == userID && (found == nil || sess.LastActivity.After(found.LastActivity)) {
+			found = sess
+		}
+	}
+	h.sessionsMutex.RUnlock()
+
+	if found != nil {
+		return found, true
+	}
+	return h.loadStoredSession(userID, userID), false
+}
@@ -0,0 +1,228 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"interview-bot-complete/internal/config"
+	"interview-bot-complete/internal/logging"
+	"interview-bot-complete/internal/ratelimit"
+	"interview-bot-complete/internal/redis"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// sessionStoreTimeout - таймаут одного обращения к хранилищу сессий
+	sessionStoreTimeout = 2 * time.Second
+	// sessionStoreLockWait - сколько обновление ждет сессию, которую обрабатывает другая реплика
+	sessionStoreLockWait = 30 * time.Second
+	// sessionStoreLockTTL - время жизни блокировки сессии, если реплика не сняла ее (упала)
+	sessionStoreLockTTL = 5 * time.Minute
+	// sessionStoreLockPoll - период повторных попыток захватить блокировку
+	sessionStoreLockPoll = 50 * time.Millisecond
+)
+
+// ErrSessionConflict - сессию сохранила другая реплика после того, как эта ее загрузила
+var ErrSessionConflict = errors.New("сессия изменена другой репликой")
+
+// SessionStore - внешнее хранилище сессий, общее для нескольких реплик бота.
+// Load возвращает nil без ошибки, если сессии нет. Save отклоняет сессию с ErrSessionConflict,
+// если ее StoreVersion устарела. Lock захватывает сессию для обработки обновления одной репликой;
+// снимает блокировку возвращенная функция.
+type SessionStore interface {
+	Load(ctx context.Context, chatID, userID int64) (*UserSession, error)
+	Save(ctx context.Context, session *UserSession) error
+	Lock(ctx context.Context, chatID, userID int64) (func() error, error)
+}
+
+// RedisSessionStore хранит сессии в Redis в JSON с ключами <prefix>:<chat_id>:<user_id>
+type RedisSessionStore struct {
+	client *redis.Client
+	prefix string
+	ttl    time.Duration
+}
+
+// NewRedisSessionStore создает хранилище; ttl - время жизни неактивной сессии
+func NewRedisSessionStore(client *redis.Client, prefix string, ttl time.Duration) *RedisSessionStore {
+	return &RedisSessionStore{client: client, prefix: prefix, ttl: ttl}
+}
+
+func (s *RedisSessionStore) key(chatID, userID int64) string {
+	return fmt.Sprintf("%s:%d:%d", s.prefix, chatID, userID)
+}
+
+// Load читает сессию пользователя в чате
+func (s *RedisSessionStore) Load(ctx context.Context, chatID, userID int64) (*UserSession, error) {
+	data, err := s.client.Get(ctx, s.key(chatID, userID))
+	if errors.Is(err, redis.ErrNil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var session UserSession
+	if err := json.Unmarshal([]byte(data), &session); err != nil {
+		return nil, fmt.Errorf("ошибка разбора сессии: %w", err)
+	}
+	return &session, nil
+}
+
+// sessionSaveScript записывает сессию ARGV[1], если версия сохраненной равна ARGV[2]
+// (или сессии нет). ARGV[3] - время жизни в мс, 0 - без ограничения. Возвращает 1 или 0 при конфликте.
+const sessionSaveScript = `
+local current = redis.call('GET', KEYS[1])
+if current and (cjson.decode(current).store_version or 0) ~= tonumber(ARGV[2]) then
+	return 0
+end
+if tonumber(ARGV[3]) > 0 then
+	redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[3])
+else
+	redis.call('SET', KEYS[1], ARGV[1])
+end
+return 1
+`
+
+// sessionUnlockScript снимает блокировку, только если ее держит этот владелец
+const sessionUnlockScript = `
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`
+
+// Save сохраняет сессию со следующей версией и продлевает ее время жизни.
+// Если другая реплика уже сохранила более новую версию, возвращает ErrSessionConflict.
+func (s *RedisSessionStore) Save(ctx context.Context, session *UserSession) error {
+	version := session.StoreVersion
+	session.StoreVersion++
+	data, err := json.Marshal(session)
+	if err != nil {
+		session.StoreVersion = version
+		return fmt.Errorf("ошибка сериализации сессии: %w", err)
+	}
+
+	reply, err := s.client.Eval(ctx, sessionSaveScript, []string{s.key(session.ChatID, session.UserID)},
+		string(data), strconv.FormatInt(version, 10), strconv.FormatInt(s.ttl.Milliseconds(), 10))
+	if err != nil {
+		session.StoreVersion = version
+		return err
+	}
+	if saved, _ := reply.(int64); saved != 1 {
+		session.StoreVersion = version
+		return ErrSessionConflict
+	}
+	return nil
+}
+
+// Lock захватывает блокировку сессии (SET NX с временем жизни), ожидая ее освобождения другой репликой до отмены ctx
+func (s *RedisSessionStore) Lock(ctx context.Context, chatID, userID int64) (func() error, error) {
+	key := s.key(chatID, userID) + ":lock"
+	owner := uuid.NewString()
+	for {
+		reply, err := s.client.Do(ctx, "SET", key, owner, "NX", "PX", strconv.FormatInt(sessionStoreLockTTL.Milliseconds(), 10))
+		if err != nil {
+			return nil, err
+		}
+		if reply != nil {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(sessionStoreLockPoll):
+		}
+	}
+
+	return func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), sessionStoreTimeout)
+		defer cancel()
+		_, err := s.client.Eval(ctx, sessionUnlockScript, []string{key}, owner)
+		return err
+	}, nil
+}
+
+// UseRedis переносит лимиты, квоты, бюджет токенов и сессии в Redis, чтобы несколько
+// реплик бота работали согласованно
+func (h *Handler) UseRedis(client *redis.Client, cfg config.RedisConfig) {
+	prefix := cfg.KeyPrefix
+	h.rateLimiter = ratelimit.NewRedis(client, prefix+"ratelimit:messages", messageLimiterConfig(h.limits))
+	h.llmLimiter = ratelimit.NewRedis(client, prefix+"ratelimit:llm", llmLimiterConfig(h.limits))
+	h.engine.SetLLMLimiter(h.llmLimiter)
+	h.askQuota = ratelimit.NewRedisQuota(client, prefix+"quota:ask", h.askPerDay, 24*time.Hour)
+	h.interviewQuota = ratelimit.NewRedisQuota(client, prefix+"quota:interviews", h.quotas.InterviewsPerUserPerDay, 24*time.Hour)
+	h.linkAttempts = ratelimit.NewRedisQuota(client, prefix+"quota:link", maxLinkAttempts, linkLockout)
+	h.interviewSlots = ratelimit.NewRedisSlots(client, prefix+"interviews:active", h.quotas.MaxActiveInterviews, activeInterviewTTL)
+	h.tokenBudget = ratelimit.NewRedisBudget(client, prefix+"budget:tokens", h.quotas.DailyTokenBudget)
+	h.sessionStore = NewRedisSessionStore(client, prefix+"session", cfg.SessionTTL)
+}
+
+// lockStoredSession захватывает сессию во внешнем хранилище, ожидая ее не дольше wait, чтобы
+// сессию обрабатывала одна реплика, и обновляет из хранилища локальную сессию: ее могла
+// изменить другая реплика. Вызывается под session.mu. false - сессию держит другая реплика
+// или хранилище недоступно; устаревшую локальную сессию тогда не даст записать проверка версии в Save.
+func (h *Handler) lockStoredSession(session *UserSession, wait time.Duration) (func(), bool) {
+	if h.sessionStore == nil {
+		return func() {}, true
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), max(wait, sessionStoreLockPoll))
+	unlock, err := h.sessionStore.Lock(ctx, session.ChatID, session.UserID)
+	cancel()
+	if err != nil {
+		if !errors.Is(err, context.DeadlineExceeded) {
+			h.logger(session).Warn("Не удалось заблокировать сессию в хранилище", "error", err)
+		}
+		return func() {}, false
+	}
+
+	if stored := h.loadStoredSession(session.ChatID, session.UserID); stored != nil && stored.StoreVersion != session.StoreVersion {
+		session.restore(stored)
+	}
+	return func() {
+		if err := unlock(); err != nil {
+			h.logger(session).Warn("Не удалось снять блокировку сессии в хранилище", "error", err)
+		}
+	}, true
+}
+
+// loadStoredSession читает сессию из внешнего хранилища (nil - сессии нет или хранилище недоступно)
+func (h *Handler) loadStoredSession(chatID, userID int64) *UserSession {
+	if h.sessionStore == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sessionStoreTimeout)
+	defer cancel()
+
+	stored, err := h.sessionStore.Load(ctx, chatID, userID)
+	if err != nil {
+		h.baseLogger.Warn("Не удалось загрузить сессию", logging.KeyChatID, chatID, logging.KeyUserID, userID, "error", err)
+		return nil
+	}
+	return stored
+}
+
+// persistSession сохраняет сессию во внешнее хранилище, если оно подключено
+func (h *Handler) persistSession(session *UserSession) {
+	if h.sessionStore == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sessionStoreTimeout)
+	defer cancel()
+
+	err := h.sessionStore.Save(ctx, session)
+	if errors.Is(err, ErrSessionConflict) {
+		// Локальная сессия устарела - она обновится из хранилища при следующем обновлении пользователя
+		h.logger(session).Warn("Сессия не сохранена: ее уже изменила другая реплика", "state", session.State)
+		return
+	}
+	if err != nil {
+		h.logger(session).Warn("Не удалось сохранить сессию", "error", err)
+	}
+}
@@ -0,0 +1,42 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"interview-bot-complete/internal/sheets"
+	"time"
+)
+
+// sheetBackfillTimeout - максимальное время выгрузки всех сохраненных профилей
+const sheetBackfillTimeout = 10 * time.Minute
+
+// SetSheets подключает выгрузку профилей в Google Sheets; без нее /exportsheet недоступна
+func (h *Handler) SetSheets(exporter *sheets.Exporter) {
+	h.sheets = exporter
+}
+
+// handleExportSheetCommand обрабатывает команду /exportsheet: выгружает в таблицу профили, которых там еще нет
+func (h *Handler) handleExportSheetCommand(session *UserSession) {
+	if !h.requireAdmin(session) {
+		return
+	}
+
+	if h.sheets == nil {
+		h.reply(session, "❌ Выгрузка в Google Sheets не настроена. Задайте `GOOGLE_SHEETS_SPREADSHEET_ID` и ключ сервисного аккаунта.")
+		return
+	}
+
+	h.reply(session, "⏳ Выгружаю профили в таблицу...")
+	h.goSafe(session, "sheets_backfill", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), sheetBackfillTimeout)
+		defer cancel()
+		report, err := h.sheets.Backfill(ctx)
+		if err != nil {
+			h.logger(session).Error("Ошибка выгрузки профилей в таблицу", "error", err)
+			h.reply(session, "❌ Не удалось выгрузить профили в таблицу.")
+			return
+		}
+		h.reply(session, fmt.Sprintf("✅ Выгружено профилей: %d\nУже в таблице: %d\nБез профиля: %d",
+			report.Exported, report.Skipped, report.Missing))
+	})
+}
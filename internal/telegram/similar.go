@@ -0,0 +1,94 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"interview-bot-complete/internal/embeddings"
+	"interview-bot-complete/internal/logging"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultSimilarCount - сколько похожих профилей показывает /similar по умолчанию
+const defaultSimilarCount = 5
+
+// embeddingTimeout - максимальное время вычисления эмбеддинга профиля
+const embeddingTimeout = time.Minute
+
+// SetEmbeddings подключает поиск похожих профилей; без него /similar недоступна
+func (h *Handler) SetEmbeddings(service *embeddings.Service) {
+	h.embeddings = service
+}
+
+// indexProfile в фоне сохраняет эмбеддинг нового профиля
+func (h *Handler) indexProfile(interviewID, profileJSON string) {
+	if h.embeddings == nil {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), embeddingTimeout)
+		defer cancel()
+		if err := h.embeddings.IndexProfile(ctx, interviewID, profileJSON); err != nil {
+			h.baseLogger.Warn("Не удалось проиндексировать профиль", logging.KeyInterviewID, interviewID, "error", err)
+		}
+	}()
+}
+
+// handleSimilarCommand обрабатывает команду /similar <interview_id> [k]
+func (h *Handler) handleSimilarCommand(args []string, session *UserSession) {
+	if !h.requireAdmin(session) {
+		return
+	}
+
+	if len(args) == 0 {
+		h.reply(session, "Использование: /similar <interview_id> [k]")
+		return
+	}
+
+	if h.embeddings == nil {
+		h.reply(session, "❌ Поиск похожих профилей недоступен.")
+		return
+	}
+
+	k := defaultSimilarCount
+	if len(args) > 1 {
+		parsed, err := strconv.Atoi(args[1])
+		if err != nil || parsed <= 0 {
+			h.reply(session, "❌ k должно быть положительным числом.")
+			return
+		}
+		k = parsed
+	}
+
+	interviewID := args[0]
+	ctx, cancel := context.WithTimeout(context.Background(), embeddingTimeout)
+	defer cancel()
+
+	// Администратор может запросить профиль, проиндексированный до включения эмбеддингов
+	if err := h.embeddings.EnsureIndexed(ctx, interviewID); err != nil {
+		h.reply(session, "❌ Ошибка поиска похожих профилей: "+err.Error())
+		return
+	}
+	matches, err := h.embeddings.FindSimilar(ctx, interviewID, k)
+	if err != nil {
+		h.reply(session, "❌ Ошибка поиска похожих профилей: "+err.Error())
+		return
+	}
+
+	h.reply(session, formatSimilarProfiles(interviewID, matches))
+}
+
+// formatSimilarProfiles формирует список похожих профилей со степенью сходства
+func formatSimilarProfiles(interviewID string, matches []embeddings.Match) string {
+	if len(matches) == 0 {
+		return fmt.Sprintf("🔍 Для `%s` пока нет других проиндексированных профилей.", interviewID)
+	}
+
+	var report strings.Builder
+	report.WriteString(fmt.Sprintf("🔍 *Профили, похожие на* `%s`:\n\n", interviewID))
+	for i, match := range matches {
+		report.WriteString(fmt.Sprintf("%d. `%s` — сходство %.2f\n", i+1, match.InterviewID, match.Score))
+	}
+	return report.String()
+}
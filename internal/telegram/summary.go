@@ -0,0 +1,57 @@
+package telegram
+
+import (
+	"interview-bot-complete/internal/prompts"
+	"strings"
+)
+
+// summaryStylesHint - подсказка о стилях резюме
+const summaryStylesHint = "_Другой формат: /summary bullets - списком, /summary narrative - связным текстом, /summary table - таблицей_"
+
+// handleSummaryCommand обрабатывает /summary [стиль]: без стиля - краткое резюме, со стилем -
+// резюме профиля, переписанное моделью в выбранном формате
+func (h *Handler) handleSummaryCommand(args []string, session *UserSession) {
+	if len(args) == 0 {
+		h.handleGetSummaryCommand(session)
+		if session.isCompleted() && h.extractor != nil {
+			h.reply(session, summaryStylesHint)
+		}
+		return
+	}
+
+	style := strings.ToLower(args[0])
+	if !prompts.IsSummaryStyle(style) {
+		h.reply(session, "❌ Неизвестный формат резюме. Доступны: "+strings.Join(prompts.SummaryStyles, ", ")+".\nНапример: /summary narrative")
+		return
+	}
+	if !session.isCompleted() || session.InterviewID == "" {
+		h.reply(session, "❌ Резюме доступно только после завершения интервью. Используйте /start для начала нового интервью.")
+		return
+	}
+	if h.extractor == nil {
+		h.reply(session, "❌ Сервис анализа профилей недоступен.")
+		return
+	}
+
+	h.goSafe(session, "styled_summary", func() { h.sendStyledSummary(session, style) })
+}
+
+// sendStyledSummary готовит (или берет сохраненное) резюме в стиле style и отправляет его
+func (h *Handler) sendStyledSummary(session *UserSession, style string) {
+	if err := h.waitLLMBudget(session.UserID); err != nil {
+		h.reply(session, "❌ Сервис анализа перегружен, попробуйте позже.")
+		return
+	}
+
+	summary, err := h.extractor.GetStyledSummary(session.InterviewID, style, h.extractOptions(session))
+	if err != nil {
+		h.replyError(session, err)
+		return
+	}
+
+	// Таблицу показываем моноширинным блоком, чтобы колонки не разъезжались
+	if style == prompts.SummaryStyleTable {
+		summary = "```\n" + summary + "\n```"
+	}
+	h.reply(session, "🎯 *Резюме профиля:*\n\n"+summary)
+}
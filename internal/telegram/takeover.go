@@ -0,0 +1,184 @@
+package telegram
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// errSessionBusy - сессия пользователя занята обработкой сообщения дольше sessionLockWait
+var errSessionBusy = errors.New("сессия занята обработкой сообщения")
+
+// sessionBusyMessage - ответ администратору, если интервью пользователя занято обработкой сообщения
+const sessionBusyMessage = "⏳ Интервью пользователя сейчас обрабатывается, попробуйте через несколько секунд."
+
+// handleTakeoverCommand обрабатывает команду /takeover <user_id>: администратор подключается
+// к активному интервью пользователя, видит диалог и задает свои вопросы
+func (h *Handler) handleTakeoverCommand(args []string, session *UserSession) {
+	if !h.requireAdmin(session) {
+		return
+	}
+	if len(args) != 1 {
+		h.reply(session, "Использование: `/takeover <user_id>` - подключиться к интервью пользователя. Отключиться: /release")
+		return
+	}
+	userID, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		h.reply(session, "❌ Некорректный ID пользователя.")
+		return
+	}
+	if userID == session.UserID {
+		h.reply(session, "❌ Нельзя подключиться к собственному интервью.")
+		return
+	}
+
+	target, unlock, err := h.lockActiveSessionOf(userID)
+	if err != nil {
+		h.reply(session, sessionBusyMessage)
+		return
+	}
+	if target == nil {
+		h.replyf(session, "❌ У пользователя %d нет активного интервью.", userID)
+		return
+	}
+	defer unlock()
+	if target.SupervisorChatID != 0 && target.SupervisorChatID != session.ChatID {
+		h.reply(session, "❌ К этому интервью уже подключен другой администратор.")
+		return
+	}
+	if session.Supervising != 0 && session.Supervising != userID {
+		if err := h.detachSupervisor(session); err != nil {
+			h.reply(session, sessionBusyMessage)
+			return
+		}
+	}
+
+	target.SupervisorChatID = session.ChatID
+	session.Supervising = userID
+	h.persistSession(target)
+	h.logger(target).Info("Администратор подключился к интервью", "admin_id", session.UserID)
+
+	h.replyf(session, "🕹 Вы подключены к интервью пользователя %d (%s).\n\n%s\n\n"+
+		"Ответы пользователя и вопросы бота будут приходить сюда. Отправьте текст - он будет задан следующим вопросом. "+
+		"Отключиться: /release",
+		userID, h.getStateDescription(target.State), h.supervisorDialogue(target))
+}
+
+// handleReleaseCommand отключает администратора от интервью, к которому он подключен через /takeover
+func (h *Handler) handleReleaseCommand(session *UserSession) {
+	if !h.requireAdmin(session) {
+		return
+	}
+	if session.Supervising == 0 {
+		h.reply(session, "Вы не подключены ни к одному интервью.")
+		return
+	}
+	userID := session.Supervising
+	if err := h.detachSupervisor(session); err != nil {
+		h.reply(session, sessionBusyMessage)
+		return
+	}
+	h.replyf(session, "👋 Вы отключились от интервью пользователя %d.", userID)
+}
+
+// detachSupervisor отключает администратора от наблюдаемого интервью
+func (h *Handler) detachSupervisor(session *UserSession) error {
+	target, unlock, err := h.lockActiveSessionOf(session.Supervising)
+	if err != nil {
+		return err
+	}
+	if target != nil {
+		if target.SupervisorChatID == session.ChatID {
+			target.SupervisorChatID = 0
+			h.persistSession(target)
+			h.logger(target).Info("Администратор отключился от интервью", "admin_id", session.UserID)
+		}
+		unlock()
+	}
+	session.Supervising = 0
+	return nil
+}
+
+// injectSupervisorQuestion ставит текст администратора в очередь вопросов наблюдаемого интервью
+func (h *Handler) injectSupervisorQuestion(text string, session *UserSession) {
+	target, unlock, err := h.lockActiveSessionOf(session.Supervising)
+	if err != nil {
+		h.reply(session, sessionBusyMessage)
+		return
+	}
+	if target != nil {
+		defer unlock()
+	}
+	if target == nil || target.SupervisorChatID != session.ChatID {
+		session.Supervising = 0
+		h.reply(session, "Интервью уже не активно, вы отключены от него.")
+		return
+	}
+	if err := h.engine.InjectQuestion(&target.Session, text); err != nil {
+		h.reply(session, "❌ "+err.Error())
+		return
+	}
+	h.persistSession(target)
+	h.reply(session, "📝 Вопрос будет задан следующим - после ответа на текущий вопрос.")
+}
+
+// lockActiveSessionOf возвращает активное интервью пользователя из памяти с захваченной
+// блокировкой сессии, которую снимает unlock (nil - интервью не идет). errSessionBusy -
+// сессия пользователя дольше sessionLockWait занята обработкой сообщения.
+func (h *Handler) lockActiveSessionOf(userID int64) (found *UserSession, unlock func(), err error) {
+	for _, sess := range h.sessionList() {
+		if sess.UserID != userID {
+			continue
+		}
+		unlockSess, ok := h.lockWithin(sess)
+		if !ok {
+			if found != nil {
+				unlock()
+			}
+			return nil, nil, errSessionBusy
+		}
+		if !sess.isActive() || (found != nil && !sess.LastActivity.After(found.LastActivity)) {
+			unlockSess()
+			continue
+		}
+		if found != nil {
+			unlock()
+		}
+		found, unlock = sess, unlockSess
+	}
+	return found, unlock, nil
+}
+
+// supervisorDialogue - вопросы и ответы текущего блока для подключившегося администратора
+func (h *Handler) supervisorDialogue(session *UserSession) string {
+	var text strings.Builder
+	fmt.Fprintf(&text, "📋 *Блок %d: %s*", session.CurrentBlock, h.getCurrentBlockTitle(session))
+	for _, qa := range session.CurrentDialogue {
+		text.WriteString("\n\n🤖 " + markdownEscaper.Replace(qa.Question))
+		if qa.Answer != "" {
+			text.WriteString("\n👤 " + markdownEscaper.Replace(qa.Answer))
+		}
+	}
+	return text.String()
+}
+
+// mirrorToSupervisor пересылает сообщение интервью подключенному администратору
+func (h *Handler) mirrorToSupervisor(session *UserSession, text string) {
+	if session.SupervisorChatID == 0 {
+		return
+	}
+	message := fmt.Sprintf("👁 *%d:* %s", session.UserID, text)
+	if err := h.bot.SendReply(Destination{ChatID: session.SupervisorChatID}, message); err != nil {
+		h.logger(session).Warn("Не удалось переслать сообщение администратору", "error", err)
+	}
+}
+
+// endSupervision сообщает подключенному администратору, что интервью закончилось, и отключает его
+func (h *Handler) endSupervision(session *UserSession, notice string) {
+	if session.SupervisorChatID == 0 {
+		return
+	}
+	h.mirrorToSupervisor(session, notice)
+	session.SupervisorChatID = 0
+}
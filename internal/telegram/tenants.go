@@ -0,0 +1,115 @@
+package telegram
+
+import (
+	"fmt"
+	"interview-bot-complete/internal/invite"
+	"interview-bot-complete/internal/storage"
+	"interview-bot-complete/internal/tenant"
+	"strings"
+)
+
+// SetTenants подключает арендаторов. bound - арендатор отдельного бота этого обработчика:
+// все его интервью принадлежат арендатору; nil - основной бот, арендатор выбирается ссылкой.
+func (h *Handler) SetTenants(registry *tenant.Registry, bound *tenant.Tenant) {
+	h.tenants = registry
+	h.tenant = bound
+}
+
+// ShareServices подключает к обработчику бота арендатора сервисы основного обработчика:
+// поиск похожих профилей и по архиву, выгрузку, метрики, отчеты об ошибках, а также общие
+// лимит обращений к OpenAI, бюджет токенов и лимит одновременных интервью
+func (h *Handler) ShareServices(primary *Handler) {
+	h.embeddings = primary.embeddings
+	h.search = primary.search
+	h.reloader = primary.reloader
+	h.sheets = primary.sheets
+	h.events = primary.events
+	h.moderation = primary.moderation
+	h.reportFont = primary.reportFont
+	h.reporters = primary.reporters
+	h.SetMetrics(primary.metrics)
+	h.llmLimiter = primary.llmLimiter
+	h.engine.SetLLMLimiter(primary.llmLimiter)
+	h.tokenBudget = primary.tokenBudget
+	h.interviewSlots = primary.interviewSlots
+	h.maintenance = primary.maintenance
+}
+
+// tenantFor возвращает арендатора сессии: арендатора бота или выбранного ссылкой (nil - без арендатора)
+func (h *Handler) tenantFor(session *UserSession) *tenant.Tenant {
+	if h.tenant != nil {
+		return h.tenant
+	}
+	t, _ := h.tenants.Get(session.Tenant)
+	return t
+}
+
+// resolveTenantLink выбирает арендатора по ссылке t.me/<bot>?start=<tenant_id> основного бота
+// и возвращает оставшиеся аргументы /start. ID арендатора проверяется раньше токенов приглашений.
+func (h *Handler) resolveTenantLink(args []string, session *UserSession) []string {
+	if h.tenant != nil || len(args) == 0 {
+		return args
+	}
+	t, ok := h.tenants.Get(args[0])
+	if !ok {
+		return args
+	}
+	session.Tenant = t.ID
+	return args[1:]
+}
+
+// bindInvitationTenant закрепляет за сессией арендатора из приглашения; приглашение
+// другого арендатора в боте арендатора отклоняется
+func (h *Handler) bindInvitationTenant(session *UserSession, invitation *invite.Invitation) bool {
+	if invitation.Tenant == "" {
+		return true
+	}
+	if _, ok := h.tenants.Get(invitation.Tenant); !ok || (h.tenant != nil && h.tenant.ID != invitation.Tenant) {
+		h.logger(session).Warn("Приглашение другого или неизвестного арендатора", "token", invitation.Token, "tenant", invitation.Tenant)
+		return false
+	}
+	session.Tenant = invitation.Tenant
+	return true
+}
+
+// tenantOfResult возвращает арендатора, которому принадлежит интервью
+func (h *Handler) tenantOfResult(result *storage.InterviewResult) *tenant.Tenant {
+	if result == nil {
+		return nil
+	}
+	t, _ := h.tenants.Get(result.Tenant)
+	return t
+}
+
+// adminTenant возвращает арендатора, администратором которого является пользователь
+// (в боте арендатора - только этого арендатора)
+func (h *Handler) adminTenant(userID int64) *tenant.Tenant {
+	if h.tenant != nil {
+		if h.tenant.IsAdmin(userID) {
+			return h.tenant
+		}
+		return nil
+	}
+	for _, t := range h.tenants.All() {
+		if t.IsAdmin(userID) {
+			return t
+		}
+	}
+	return nil
+}
+
+// replyTenantStats отвечает администратору арендатора статистикой только его арендатора
+func (h *Handler) replyTenantStats(session *UserSession, t *tenant.Tenant) {
+	counts := h.metrics.Snapshot().Tenants[t.ID]
+
+	var stats strings.Builder
+	stats.WriteString(fmt.Sprintf("📊 *%s*\n\n", t.Name))
+	stats.WriteString(fmt.Sprintf("🎯 Интервью с момента запуска: начато %d, завершено %d\n", counts.InterviewsStarted, counts.InterviewsCompleted))
+	stats.WriteString(fmt.Sprintf("🧠 Профили: создано %d, ошибок %d\n", counts.ProfilesGenerated, counts.ProfilesFailed))
+	if stored, err := storage.CollectStats(); err != nil {
+		h.logger(session).Warn("Не удалось собрать статистику хранилища", "error", err)
+	} else {
+		stats.WriteString(fmt.Sprintf("🗂 Завершенных интервью за все время: %d\n", stored.ByTenant[t.ID]))
+	}
+	h.reply(session, stats.String())
+}
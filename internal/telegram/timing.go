@@ -0,0 +1,49 @@
+package telegram
+
+import (
+	"time"
+)
+
+// blockTimeCheckInterval - период проверки мягких лимитов времени на блок
+const blockTimeCheckInterval = 30 * time.Second
+
+// startBlockTimeWatcher периодически напоминает участникам, превысившим лимит времени на блок
+func (h *Handler) startBlockTimeWatcher() {
+	h.goTicker("block_time", blockTimeCheckInterval, func() { h.checkBlockTimeLimits(time.Now()) })
+}
+
+// checkBlockTimeLimits отправляет одно мягкое напоминание на блок, если лимит времени истек
+func (h *Handler) checkBlockTimeLimits(now time.Time) {
+	for _, session := range h.sessionList() {
+		h.nudgeBlockTime(session, now)
+	}
+}
+
+// nudgeBlockTime напоминает участнику о лимите времени на блок. Сессию, занятую
+// обработкой сообщения, проверим на следующем тике.
+func (h *Handler) nudgeBlockTime(session *UserSession, now time.Time) {
+	unlock, ok := h.tryLockSession(session)
+	if !ok {
+		return
+	}
+	defer unlock()
+	if !h.blockTimeExceeded(session, now) {
+		return
+	}
+	session.BlockNudged = true
+	h.reply(session, "⏰ Вы на этом блоке уже довольно давно. Не торопим — но если удобно, ответьте коротко, и мы двинемся дальше.")
+	h.persistSession(session)
+}
+
+// blockTimeExceeded сообщает, что участник дольше лимита отвечает на текущий блок и еще не получал напоминания
+func (h *Handler) blockTimeExceeded(session *UserSession, now time.Time) bool {
+	if session.State != StateWaitingAnswer || session.BlockNudged || session.BlockStartedAt.IsZero() {
+		return false
+	}
+	cfg := h.configFor(session)
+	if session.CurrentBlock <= 0 || session.CurrentBlock > len(cfg.Blocks) {
+		return false
+	}
+	limit := cfg.BlockTimeLimit(cfg.Blocks[session.CurrentBlock-1])
+	return limit > 0 && now.Sub(session.BlockStartedAt) > limit
+}
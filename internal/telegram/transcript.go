@@ -0,0 +1,57 @@
+package telegram
+
+import (
+	"fmt"
+	"interview-bot-complete/internal/storage"
+	"interview-bot-complete/internal/transcript"
+	"strings"
+	"time"
+)
+
+// handleTranscriptCommand отправляет стенограмму интервью Markdown-файлом
+func (h *Handler) handleTranscriptCommand(session *UserSession) {
+	if session.Result == nil {
+		h.reply(session, "❌ Нет данных интервью. Используйте /start для начала.")
+		return
+	}
+
+	result := h.transcriptSnapshot(session)
+	if len(result.Blocks) == 0 {
+		h.reply(session, "❌ Вы еще не ответили ни на один вопрос.")
+		return
+	}
+
+	data := transcript.RenderMarkdown(result, h.configFor(session), time.Now())
+	fileName := fmt.Sprintf("transcript_%s.md", session.InterviewID)
+	caption := fmt.Sprintf("📝 Стенограмма интервью %s", session.InterviewID)
+
+	if err := h.bot.SendDocumentTo(h.destination(session), data, fileName, caption); err != nil {
+		h.replyError(session, err)
+	}
+}
+
+// transcriptSnapshot возвращает копию результата, дополненную ответами текущего блока
+func (h *Handler) transcriptSnapshot(session *UserSession) *storage.InterviewResult {
+	snapshot := *session.Result
+	snapshot.Blocks = append([]storage.BlockResult(nil), session.Result.Blocks...)
+
+	cfg := h.configFor(session)
+	if !session.isCompleted() && session.CurrentBlock > 0 && session.CurrentBlock <= len(cfg.Blocks) {
+		var answered []storage.QA
+		for _, qa := range session.CurrentDialogue {
+			if strings.TrimSpace(qa.Answer) != "" {
+				answered = append(answered, qa)
+			}
+		}
+		if len(answered) > 0 {
+			block := cfg.Blocks[session.CurrentBlock-1]
+			snapshot.Blocks = append(snapshot.Blocks, storage.BlockResult{
+				BlockID:             block.ID,
+				BlockName:           block.Name,
+				QuestionsAndAnswers: answered,
+			})
+		}
+	}
+
+	return &snapshot
+}
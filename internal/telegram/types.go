@@ -1,7 +1,12 @@
 package telegram
 
 import (
+	"interview-bot-complete/internal/engine"
+	"interview-bot-complete/internal/invite"
+	"interview-bot-complete/internal/metrics"
 	"interview-bot-complete/internal/storage"
+	"net/http"
+	"sync"
 	"time"
 )
 
@@ -9,28 +14,80 @@ import (
 type Bot struct {
 	token   string
 	baseURL string
+	queue   *sendQueue
+	polling PollingOptions
+
+	// transport - выход в Bot API: прокси и корневые сертификаты (SetTransport)
+	transport http.RoundTripper
+	// dispatch - пул обработки обновлений (SetDispatch), metrics - учет его очереди
+	dispatch DispatchOptions
+	metrics  *metrics.Registry
 }
 
 // Update представляет обновление от Telegram
 type Update struct {
-	UpdateID int      `json:"update_id"`
-	Message  *Message `json:"message,omitempty"`
+	UpdateID         int               `json:"update_id"`
+	Message          *Message          `json:"message,omitempty"`
+	EditedMessage    *Message          `json:"edited_message,omitempty"`
+	CallbackQuery    *CallbackQuery    `json:"callback_query,omitempty"`
+	PreCheckoutQuery *PreCheckoutQuery `json:"pre_checkout_query,omitempty"`
+}
+
+// PreCheckoutQuery - запрос подтверждения перед списанием оплаты
+type PreCheckoutQuery struct {
+	ID             string `json:"id"`
+	From           *User  `json:"from"`
+	Currency       string `json:"currency"`
+	TotalAmount    int    `json:"total_amount"`
+	InvoicePayload string `json:"invoice_payload"`
+}
+
+// SuccessfulPayment - сведения о завершенной оплате счета
+type SuccessfulPayment struct {
+	Currency                string `json:"currency"`
+	TotalAmount             int    `json:"total_amount"`
+	InvoicePayload          string `json:"invoice_payload"`
+	TelegramPaymentChargeID string `json:"telegram_payment_charge_id"`
+	ProviderPaymentChargeID string `json:"provider_payment_charge_id"`
+}
+
+// CallbackQuery представляет нажатие inline-кнопки
+type CallbackQuery struct {
+	ID      string   `json:"id"`
+	From    *User    `json:"from"`
+	Message *Message `json:"message,omitempty"`
+	Data    string   `json:"data,omitempty"`
 }
 
 // Message представляет сообщение в Telegram
 type Message struct {
-	MessageID int    `json:"message_id"`
-	From      *User  `json:"from,omitempty"`
-	Chat      *Chat  `json:"chat"`
-	Text      string `json:"text,omitempty"`
+	MessageID       int    `json:"message_id"`
+	MessageThreadID int    `json:"message_thread_id,omitempty"`
+	From            *User  `json:"from,omitempty"`
+	Chat            *Chat  `json:"chat"`
+	Text            string `json:"text,omitempty"`
+	// Document и Caption - файл и подпись к нему (загрузка CSV для /bulkinvite)
+	Document *Document `json:"document,omitempty"`
+	Caption  string    `json:"caption,omitempty"`
+
+	SuccessfulPayment *SuccessfulPayment `json:"successful_payment,omitempty"`
+}
+
+// Document - файл, отправленный пользователем
+type Document struct {
+	FileID   string `json:"file_id"`
+	FileName string `json:"file_name,omitempty"`
+	MimeType string `json:"mime_type,omitempty"`
+	FileSize int    `json:"file_size,omitempty"`
 }
 
 // User представляет пользователя Telegram
 type User struct {
-	ID        int64  `json:"id"`
-	FirstName string `json:"first_name"`
-	LastName  string `json:"last_name,omitempty"`
-	Username  string `json:"username,omitempty"`
+	ID           int64  `json:"id"`
+	FirstName    string `json:"first_name"`
+	LastName     string `json:"last_name,omitempty"`
+	Username     string `json:"username,omitempty"`
+	LanguageCode string `json:"language_code,omitempty"`
 }
 
 // Chat представляет чат в Telegram
@@ -42,17 +99,142 @@ type Chat struct {
 	Type      string `json:"type"`
 }
 
+// IsGroup сообщает, является ли чат группой или супергруппой
+func (c *Chat) IsGroup() bool {
+	return c.Type == "group" || c.Type == "supergroup"
+}
+
+// Destination описывает адресата ответа: чат, тему форума и сообщение, на которое отвечаем
+type Destination struct {
+	ChatID           int64
+	MessageThreadID  int
+	ReplyToMessageID int
+}
+
 // SendMessageRequest представляет запрос на отправку сообщения
 type SendMessageRequest struct {
+	ChatID                   int64  `json:"chat_id"`
+	MessageThreadID          int    `json:"message_thread_id,omitempty"`
+	Text                     string `json:"text"`
+	ParseMode                string `json:"parse_mode,omitempty"`
+	ReplyToMessageID         int    `json:"reply_to_message_id,omitempty"`
+	AllowSendingWithoutReply bool   `json:"allow_sending_without_reply,omitempty"`
+
+	// ReplyMarkup - *InlineKeyboardMarkup, *ReplyKeyboardMarkup или *ReplyKeyboardRemove
+	ReplyMarkup interface{} `json:"reply_markup,omitempty"`
+	// Entities - разметка текста сущностями; задается вместо ParseMode
+	Entities []MessageEntity `json:"entities,omitempty"`
+}
+
+// MessageEntity - фрагмент текста с оформлением; Offset и Length считаются в UTF-16
+type MessageEntity struct {
+	Type   string `json:"type"`
+	Offset int    `json:"offset"`
+	Length int    `json:"length"`
+}
+
+// ReplyKeyboardMarkup - клавиатура с вариантами ответа вместо поля ввода
+type ReplyKeyboardMarkup struct {
+	Keyboard        [][]KeyboardButton `json:"keyboard"`
+	ResizeKeyboard  bool               `json:"resize_keyboard,omitempty"`
+	OneTimeKeyboard bool               `json:"one_time_keyboard,omitempty"`
+	// Selective - в группе клавиатура показывается только автору сообщения, на которое отвечает бот
+	Selective bool `json:"selective,omitempty"`
+}
+
+// KeyboardButton - кнопка клавиатуры ответа; нажатие отправляет ее текст сообщением
+type KeyboardButton struct {
+	Text string `json:"text"`
+}
+
+// ReplyKeyboardRemove убирает клавиатуру ответа
+type ReplyKeyboardRemove struct {
+	RemoveKeyboard bool `json:"remove_keyboard"`
+	Selective      bool `json:"selective,omitempty"`
+}
+
+// InlineKeyboardMarkup - inline-клавиатура под сообщением
+type InlineKeyboardMarkup struct {
+	InlineKeyboard [][]InlineKeyboardButton `json:"inline_keyboard"`
+}
+
+// InlineKeyboardButton - кнопка inline-клавиатуры
+type InlineKeyboardButton struct {
+	Text         string `json:"text"`
+	CallbackData string `json:"callback_data"`
+}
+
+// AnswerCallbackQueryRequest представляет запрос answerCallbackQuery
+type AnswerCallbackQueryRequest struct {
+	CallbackQueryID string `json:"callback_query_id"`
+	Text            string `json:"text,omitempty"`
+}
+
+// LabeledPrice - позиция счета; Amount в минимальных единицах валюты (для XTR - в звездах)
+type LabeledPrice struct {
+	Label  string `json:"label"`
+	Amount int    `json:"amount"`
+}
+
+// SendInvoiceRequest представляет запрос sendInvoice
+type SendInvoiceRequest struct {
+	ChatID          int64          `json:"chat_id"`
+	MessageThreadID int            `json:"message_thread_id,omitempty"`
+	Title           string         `json:"title"`
+	Description     string         `json:"description"`
+	Payload         string         `json:"payload"`
+	ProviderToken   string         `json:"provider_token,omitempty"`
+	Currency        string         `json:"currency"`
+	Prices          []LabeledPrice `json:"prices"`
+}
+
+// AnswerPreCheckoutQueryRequest представляет запрос answerPreCheckoutQuery
+type AnswerPreCheckoutQueryRequest struct {
+	PreCheckoutQueryID string `json:"pre_checkout_query_id"`
+	OK                 bool   `json:"ok"`
+	ErrorMessage       string `json:"error_message,omitempty"`
+}
+
+// EditMessageReplyMarkupRequest представляет запрос editMessageReplyMarkup
+type EditMessageReplyMarkupRequest struct {
+	ChatID      int64                 `json:"chat_id"`
+	MessageID   int                   `json:"message_id"`
+	ReplyMarkup *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+}
+
+// EditMessageTextRequest представляет запрос editMessageText
+type EditMessageTextRequest struct {
 	ChatID    int64  `json:"chat_id"`
+	MessageID int    `json:"message_id"`
 	Text      string `json:"text"`
 	ParseMode string `json:"parse_mode,omitempty"`
 }
 
+// BotCommand описывает команду в меню бота
+type BotCommand struct {
+	Command     string `json:"command"`
+	Description string `json:"description"`
+}
+
+// BotCommandScope задает область действия меню команд
+type BotCommandScope struct {
+	Type   string `json:"type"`
+	ChatID int64  `json:"chat_id,omitempty"`
+	UserID int64  `json:"user_id,omitempty"`
+}
+
+// SetMyCommandsRequest представляет запрос setMyCommands
+type SetMyCommandsRequest struct {
+	Commands     []BotCommand     `json:"commands"`
+	Scope        *BotCommandScope `json:"scope,omitempty"`
+	LanguageCode string           `json:"language_code,omitempty"`
+}
+
 // GetUpdatesResponse представляет ответ от getUpdates
 type GetUpdatesResponse struct {
-	OK     bool     `json:"ok"`
-	Result []Update `json:"result"`
+	OK          bool     `json:"ok"`
+	Result      []Update `json:"result"`
+	Description string   `json:"description,omitempty"`
 }
 
 // SendMessageResponse представляет ответ от sendMessage
@@ -61,25 +243,98 @@ type SendMessageResponse struct {
 	Result *Message `json:"result,omitempty"`
 }
 
-// Обновить UserSession
+// sessionKey идентифицирует сессию пользователя в конкретном чате
+type sessionKey struct {
+	ChatID int64
+	UserID int64
+}
+
+// threadKey идентифицирует тему форума (0 - основной чат)
+type threadKey struct {
+	ChatID   int64
+	ThreadID int
+}
+
+// UserSession - сессия пользователя в чате. Ход интервью (блоки, диалог, результат) хранится
+// во встроенной engine.Session, поля которой сериализуются на верхнем уровне как и раньше.
 type UserSession struct {
-	UserID              int64                    `json:"user_id"`
-	InterviewID         string                   `json:"interview_id"`
-	CurrentBlock        int                      `json:"current_block"`
-	QuestionCount       int                      `json:"question_count"`
-	State               SessionState             `json:"state"`
-	CurrentDialogue     []storage.QA             `json:"current_dialogue"`
-	CumulativeSummaries []string                 `json:"cumulative_summaries"`
-	Result              *storage.InterviewResult `json:"result"`
-	LastActivity        time.Time                `json:"last_activity"`
+	engine.Session
+	ChatID            int64                    `json:"chat_id"`
+	ThreadID          int                      `json:"thread_id,omitempty"`
+	IsGroup           bool                     `json:"is_group,omitempty"`
+	LastMessageID     int                      `json:"last_message_id,omitempty"`
+	State             SessionState             `json:"state"`
+	LastActivity      time.Time                `json:"last_activity"`
+	EditIndex         int                      `json:"edit_index,omitempty"`
+	LanguageCode      string                   `json:"language_code,omitempty"`
+	AskHistory        []storage.QA             `json:"ask_history,omitempty"`
+	Consent           *storage.Consent         `json:"consent,omitempty"`
+	PendingInvitation *invite.Invitation       `json:"pending_invitation,omitempty"` // приглашение, ожидающее согласия на обработку данных
+	PendingStart      []string                 `json:"pending_start,omitempty"`      // аргументы /start, ожидающие выбора: продолжить интервью или начать заново
+	AbandonedResult   *storage.InterviewResult `json:"abandoned_result,omitempty"`   // прерванное интервью, ожидающее решения о частичном профиле
+	LastAnswer        *AnswerRef               `json:"last_answer,omitempty"`        // сообщение с последним ответом - его правка обновляет ответ
+	PendingDelivery   bool                     `json:"pending_delivery,omitempty"`   // вопрос не доставлен и будет отправлен повторно
+	PreferredPersona  string                   `json:"preferred_persona,omitempty"`  // персона интервьюера, выбранная через /persona
+	ReplyKeyboard     bool                     `json:"reply_keyboard,omitempty"`     // под полем ввода показаны варианты ответа
+	Tenant            string                   `json:"tenant,omitempty"`             // арендатор, выбранный ссылкой /start <tenant_id> или приглашением
+	// Попытки составления профиля: число, начало текущей, время повтора и последняя ошибка
+	ExtractionAttempts  int       `json:"extraction_attempts,omitempty"`
+	ExtractionStartedAt time.Time `json:"extraction_started_at,omitempty"`
+	ExtractionRetryAt   time.Time `json:"extraction_retry_at,omitempty"`
+	ExtractionError     string    `json:"extraction_error,omitempty"`
+	// Напоминания о вопросе без ответа: когда задан вопрос, сколько напоминаний отправлено
+	// и поставлено ли интервью на паузу (/pause) до следующего ответа
+	QuestionSentAt time.Time `json:"question_sent_at,omitempty"`
+	Nudges         int       `json:"nudges,omitempty"`
+	Paused         bool      `json:"paused,omitempty"`
+	// Резюме, загруженное до начала интервью: факты профиля (JSON) для следующего /start
+	// или файл, ожидающий согласия на обработку данных
+	PendingResume     string `json:"pending_resume,omitempty"`
+	PendingResumeFile string `json:"pending_resume_file,omitempty"`
+	// SupervisorChatID - чат администратора, подключенного к интервью (/takeover); Supervising - пользователь,
+	// к интервью которого подключен администратор
+	SupervisorChatID int64  `json:"supervisor_chat_id,omitempty"`
+	Supervising      int64  `json:"supervising,omitempty"`
+	commandMenu      string // последнее установленное меню команд
+
+	// StoreVersion - версия сессии во внешнем хранилище: сохранение поверх версии,
+	// записанной другой репликой, отклоняется
+	StoreVersion int64 `json:"store_version,omitempty"`
+	// mu - блокировка сессии (lockSession): обновления пользователя, фоновые задачи сессии
+	// и команды администраторов, обращающиеся к ней, выполняются по очереди
+	mu *sync.Mutex
+}
+
+// restore заменяет состояние сессии загруженным (из внешнего хранилища или снимка /loadsession),
+// сохраняя ее блокировку и меню команд. Вызывается под s.mu.
+func (s *UserSession) restore(loaded *UserSession) {
+	mu, commandMenu := s.mu, s.commandMenu
+	*s = *loaded
+	s.mu, s.commandMenu = mu, commandMenu
+}
+
+// AnswerRef связывает сообщение пользователя с ответом на вопрос index (с нуля) блока интервью
+type AnswerRef struct {
+	MessageID   int    `json:"message_id"`
+	InterviewID string `json:"interview_id"`
+	Block       int    `json:"block"`
+	Index       int    `json:"index"`
 }
 
 // SessionState представляет состояние сессии
 type SessionState string
 
 const (
-	StateIdle          SessionState = "idle"
-	StateInterview     SessionState = "interview"
-	StateWaitingAnswer SessionState = "waiting_answer"
-	StateCompleted     SessionState = "completed"
+	StateIdle            SessionState = "idle"
+	StateAwaitingConsent SessionState = "awaiting_consent"
+	StateInterview       SessionState = "interview"
+	StateWaitingAnswer   SessionState = "waiting_answer"
+	StateEditingAnswer   SessionState = "editing_answer"
+	StateReviewingBlock  SessionState = "reviewing_block"
+	StateAskingProfile   SessionState = "asking_profile"
+	StateCompleted       SessionState = "completed" // интервью завершено без составления профиля
+	// Составление профиля после интервью: переходы сохраняются, чтобы пережить перезапуск бота
+	StateExtracting       SessionState = "extracting"
+	StateProfileReady     SessionState = "profile_ready"
+	StateExtractionFailed SessionState = "extraction_failed"
 )
@@ -0,0 +1,96 @@
+package telegram
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+)
+
+const (
+	// webhookSecretHeader - заголовок, в котором Telegram передает secret_token из setWebhook
+	webhookSecretHeader = "X-Telegram-Bot-Api-Secret-Token"
+	// maxWebhookBody - предельный размер тела запроса с обновлением
+	maxWebhookBody = 1 << 20
+)
+
+// SetWebhookRequest - параметры setWebhook
+type SetWebhookRequest struct {
+	URL            string   `json:"url"`
+	SecretToken    string   `json:"secret_token"`
+	MaxConnections int      `json:"max_connections,omitempty"`
+	AllowedUpdates []string `json:"allowed_updates,omitempty"`
+}
+
+// SetWebhook регистрирует адрес, на который Telegram отправляет обновления вместо getUpdates.
+// secret возвращается в заголовке X-Telegram-Bot-Api-Secret-Token каждого запроса. Обновления
+// приходят по одному соединению - по порядку, как из getUpdates; их типы задает SetPolling.
+func (b *Bot) SetWebhook(webhookURL, secret string) error {
+	return b.callMethod("setWebhook", SetWebhookRequest{
+		URL:            webhookURL,
+		SecretToken:    secret,
+		MaxConnections: 1,
+		AllowedUpdates: b.polling.AllowedUpdates,
+	})
+}
+
+// ServeWebhook принимает обновления, которые Telegram отправляет на webhookURL, и передает их handler
+// пулом обработчиков (SetDispatch), как StartPolling. HTTP обработчик регистрируется через mount
+// по пути из webhookURL; запросы без secret в заголовке X-Telegram-Bot-Api-Secret-Token отклоняются.
+// В отличие от getUpdates, так обновления получают несколько реплик бота за балансировщиком.
+// Обновления принимаются, пока не отменен ctx; перед возвратом дожидается обработки принятых.
+func (b *Bot) ServeWebhook(ctx context.Context, webhookURL, secret string, handler func(Update), mount func(string, http.Handler)) error {
+	parsed, err := url.Parse(webhookURL)
+	if err != nil || parsed.Scheme != "https" || parsed.Path == "" {
+		return fmt.Errorf("некорректный адрес вебхука %q: нужен https URL с путем", webhookURL)
+	}
+	if secret == "" {
+		return fmt.Errorf("не задан секрет вебхука")
+	}
+
+	dispatcher := newDispatcher(b, handler)
+	// intake ставит обновления в очередь по одному, как цикл getUpdates; stopped - очередь закрыта
+	var intake sync.Mutex
+	stopped := false
+	defer func() {
+		intake.Lock()
+		stopped = true
+		intake.Unlock()
+		dispatcher.stop()
+	}()
+
+	mount(parsed.Path, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get(webhookSecretHeader)), []byte(secret)) != 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var update Update
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxWebhookBody)).Decode(&update); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		intake.Lock()
+		accepted := !stopped && dispatcher.dispatch(ctx, update)
+		intake.Unlock()
+		if !accepted {
+			// Бот останавливается - Telegram повторит обновление позже
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	if err := b.SetWebhook(webhookURL, secret); err != nil {
+		return err
+	}
+	<-ctx.Done()
+	return nil
+}
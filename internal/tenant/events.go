@@ -0,0 +1,51 @@
+package tenant
+
+import (
+	"context"
+	"encoding/json"
+	"interview-bot-complete/internal/events"
+	"interview-bot-complete/internal/logging"
+	"log/slog"
+	"time"
+)
+
+// notifyTimeout - ограничение на отправку одного события на все вебхуки арендатора
+const notifyTimeout = 30 * time.Second
+
+// WebhookSubscriber в фоне отправляет события интервью на вебхуки арендаторов:
+// завершение интервью и готовый профиль. onError (может быть nil) вызывается при ошибке доставки.
+func (r *Registry) WebhookSubscriber(onError func(tenantID string, err error)) events.Subscriber {
+	return events.SubscriberFunc(func(event events.Event) {
+		var webhookEvent Event
+		switch event.Kind {
+		case events.InterviewCompleted:
+			webhookEvent.Event = EventInterviewCompleted
+		case events.ProfileExtracted:
+			webhookEvent.Event = EventProfileReady
+			webhookEvent.Profile = json.RawMessage(event.ProfileJSON)
+		default:
+			return
+		}
+		t, ok := r.Get(event.Tenant)
+		if !ok || len(t.Webhooks) == 0 {
+			return
+		}
+		webhookEvent.InterviewID = event.InterviewID
+		webhookEvent.TemplateID = event.TemplateID
+		webhookEvent.UserID = event.UserID
+		webhookEvent.Time = event.Time
+		webhookEvent.Partial = event.Partial
+
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+			defer cancel()
+			if err := t.Notify(ctx, webhookEvent); err != nil {
+				slog.Warn("Ошибка отправки вебхука арендатора", logging.KeyInterviewID, event.InterviewID,
+					"tenant", t.ID, "event", webhookEvent.Event, "error", err)
+				if onError != nil {
+					onError(t.ID, err)
+				}
+			}
+		}()
+	})
+}
@@ -0,0 +1,156 @@
+package tenant
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+)
+
+// idPattern - ID арендатора: латиница в нижнем регистре, цифры и _ (без '-', как токен приглашения)
+var idPattern = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// prefixPattern - префикс хранилища: одна директория без выхода за пределы results/output
+var prefixPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// Tenant - арендатор бота: свой шаблон интервью, приветствие, схема профиля,
+// директория в хранилище и получатели вебхуков
+type Tenant struct {
+	ID   string `yaml:"id"`
+	Name string `yaml:"name"`
+	// BotTokenEnv - переменная окружения с токеном отдельного бота арендатора;
+	// пусто - арендатор выбирается ссылкой t.me/<bot>?start=<id> основного бота
+	BotTokenEnv string `yaml:"bot_token_env,omitempty"`
+	// Template - шаблон интервью без приглашения; пусто - шаблон по умолчанию
+	Template string `yaml:"template,omitempty"`
+	// Welcome - текст приветствия арендатора перед правилами интервью
+	Welcome string `yaml:"welcome,omitempty"`
+	// Schema - схема профиля; пусто - общая config/profile_schema.yaml
+	Schema string `yaml:"schema,omitempty"`
+	// StoragePrefix - директория результатов и профилей; пусто - ID арендатора
+	StoragePrefix string `yaml:"storage_prefix,omitempty"`
+	// Webhooks - URL, получающие события о завершенных интервью и готовых профилях
+	Webhooks []string `yaml:"webhooks,omitempty"`
+	// Admins - администраторы арендатора: видят статистику только своего арендатора
+	Admins []int64 `yaml:"admins,omitempty"`
+	// RecruiterChatID - чат рекрутеров арендатора для отчетов о соответствии роли;
+	// 0 - общий RECRUITER_CHAT_ID
+	RecruiterChatID int64 `yaml:"recruiter_chat_id,omitempty"`
+	// Observer включает обезличенную трансляцию интервью арендатора в чат наблюдателей:
+	// ObserverChatID или общий TELEGRAM_OBSERVER_CHAT_ID. Бот арендатора должен состоять в этом чате.
+	Observer       bool  `yaml:"observer,omitempty"`
+	ObserverChatID int64 `yaml:"observer_chat_id,omitempty"`
+	// RetentionDays и RetentionMode переопределяют RETENTION_DAYS и RETENTION_MODE;
+	// retention_days: 0 - ответы арендатора хранятся бессрочно
+	RetentionDays *int   `yaml:"retention_days,omitempty"`
+	RetentionMode string `yaml:"retention_mode,omitempty"`
+}
+
+// BotToken возвращает токен отдельного бота арендатора (пусто - бота нет)
+func (t *Tenant) BotToken() string {
+	if t.BotTokenEnv == "" {
+		return ""
+	}
+	return os.Getenv(t.BotTokenEnv)
+}
+
+// IsAdmin проверяет, входит ли пользователь в администраторы арендатора
+func (t *Tenant) IsAdmin(userID int64) bool {
+	for _, admin := range t.Admins {
+		if admin == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// Registry - арендаторы из файла TENANTS_FILE. Методы безопасны для nil (арендаторы не заданы).
+type Registry struct {
+	tenants []*Tenant
+	byID    map[string]*Tenant
+}
+
+// NewRegistry проверяет арендаторов и заполняет префиксы хранилища по умолчанию
+func NewRegistry(tenants []Tenant) (*Registry, error) {
+	r := &Registry{byID: make(map[string]*Tenant)}
+	prefixes := make(map[string]string)
+	for i := range tenants {
+		t := tenants[i]
+		if !idPattern.MatchString(t.ID) {
+			return nil, fmt.Errorf("tenants[%d]: id %q должен состоять из латинских букв в нижнем регистре, цифр и _", i, t.ID)
+		}
+		if _, exists := r.byID[t.ID]; exists {
+			return nil, fmt.Errorf("tenants: повторяющийся id %q", t.ID)
+		}
+		if t.Name == "" {
+			t.Name = t.ID
+		}
+		if t.StoragePrefix == "" {
+			t.StoragePrefix = t.ID
+		}
+		if !prefixPattern.MatchString(t.StoragePrefix) {
+			return nil, fmt.Errorf("tenants.%s: storage_prefix %q должен быть именем одной директории", t.ID, t.StoragePrefix)
+		}
+		if other, exists := prefixes[t.StoragePrefix]; exists {
+			return nil, fmt.Errorf("tenants.%s: storage_prefix %q уже занят арендатором %s", t.ID, t.StoragePrefix, other)
+		}
+		prefixes[t.StoragePrefix] = t.ID
+		if t.BotTokenEnv != "" && t.BotToken() == "" {
+			return nil, fmt.Errorf("tenants.%s: переменная %s с токеном бота не задана", t.ID, t.BotTokenEnv)
+		}
+		r.tenants = append(r.tenants, &t)
+		r.byID[t.ID] = &t
+	}
+	return r, nil
+}
+
+// Load читает арендаторов из YAML файла. Отсутствующий файл не считается ошибкой.
+func Load(filename string) (*Registry, error) {
+	var file struct {
+		Tenants []Tenant `yaml:"tenants"`
+	}
+
+	data, err := os.ReadFile(filename)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("ошибка чтения файла %s: %w", filename, err)
+	}
+	if err == nil {
+		if err := yaml.Unmarshal(data, &file); err != nil {
+			return nil, fmt.Errorf("ошибка парсинга %s: %w", filename, err)
+		}
+	}
+
+	return NewRegistry(file.Tenants)
+}
+
+// Enabled сообщает, задан ли хотя бы один арендатор
+func (r *Registry) Enabled() bool {
+	return r != nil && len(r.tenants) > 0
+}
+
+// Get возвращает арендатора по ID
+func (r *Registry) Get(id string) (*Tenant, bool) {
+	if r == nil || id == "" {
+		return nil, false
+	}
+	t, ok := r.byID[id]
+	return t, ok
+}
+
+// All возвращает арендаторов в порядке объявления
+func (r *Registry) All() []*Tenant {
+	if r == nil {
+		return nil
+	}
+	return r.tenants
+}
+
+// StoragePrefixes возвращает директории хранилища по ID арендаторов
+func (r *Registry) StoragePrefixes() map[string]string {
+	prefixes := make(map[string]string)
+	for _, t := range r.All() {
+		prefixes[t.ID] = t.StoragePrefix
+	}
+	return prefixes
+}
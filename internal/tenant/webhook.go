@@ -0,0 +1,105 @@
+package tenant
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"interview-bot-complete/internal/httpclient"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// События вебхуков арендатора
+const (
+	EventInterviewCompleted = "interview.completed"
+	EventProfileReady       = "profile.ready"
+)
+
+// webhookTimeout - ограничение на доставку события одному получателю
+const webhookTimeout = 10 * time.Second
+
+// webhookProxyEnv - прокси запросов к вебхукам; перекрывает HTTPS_PROXY
+const webhookProxyEnv = "WEBHOOK_PROXY"
+
+// webhook - общий HTTP клиент вебхуков, создается при первой отправке
+var webhook struct {
+	once   sync.Once
+	client *http.Client
+	err    error
+}
+
+// webhookClient возвращает клиент вебхуков с прокси из WEBHOOK_PROXY (иначе HTTPS_PROXY
+// с учетом NO_PROXY) и корневыми сертификатами из HTTP_CA_CERT_FILE
+func webhookClient() (*http.Client, error) {
+	webhook.once.Do(func() {
+		transport, err := httpclient.NewTransport(httpclient.OptionsFromEnv(webhookProxyEnv))
+		if err != nil {
+			webhook.err = fmt.Errorf("ошибка настройки HTTP клиента вебхуков (WEBHOOK_PROXY, HTTP_CA_CERT_FILE): %w", err)
+			return
+		}
+		webhook.client = &http.Client{Timeout: webhookTimeout, Transport: transport}
+	})
+	return webhook.client, webhook.err
+}
+
+// Event - событие, отправляемое на вебхуки арендатора
+type Event struct {
+	Event       string          `json:"event"`
+	Tenant      string          `json:"tenant"`
+	InterviewID string          `json:"interview_id"`
+	TemplateID  string          `json:"template_id,omitempty"`
+	UserID      int64           `json:"user_id"`
+	Time        time.Time       `json:"time"`
+	Partial     bool            `json:"partial,omitempty"`
+	Profile     json.RawMessage `json:"profile,omitempty"`
+}
+
+// Notify отправляет событие POST запросом на все вебхуки арендатора.
+// Ошибки получателей собираются вместе; недоступный получатель не мешает остальным.
+func (t *Tenant) Notify(ctx context.Context, event Event) error {
+	if len(t.Webhooks) == 0 {
+		return nil
+	}
+	event.Tenant = t.ID
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("ошибка сериализации события: %w", err)
+	}
+
+	var errs []error
+	for _, url := range t.Webhooks {
+		if err := postWebhook(ctx, url, event.Event, body); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", url, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func postWebhook(ctx context.Context, url, event string, body []byte) error {
+	client, err := webhookClient()
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("ошибка создания запроса: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Interview-Event", event)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("статус %d: %s", resp.StatusCode, respBody)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package transcript
+
+import (
+	"fmt"
+	"interview-bot-complete/internal/config"
+	"interview-bot-complete/internal/storage"
+	"strings"
+	"time"
+)
+
+// RenderMarkdown формирует читаемую стенограмму интервью в формате Markdown
+func RenderMarkdown(result *storage.InterviewResult, cfg *config.Config, generatedAt time.Time) []byte {
+	var md strings.Builder
+
+	md.WriteString("# Стенограмма интервью\n\n")
+	md.WriteString(fmt.Sprintf("- **ID интервью:** `%s`\n", result.InterviewID))
+	if started := formatTimestamp(result.Timestamp); started != "" {
+		md.WriteString(fmt.Sprintf("- **Начало:** %s\n", started))
+	}
+	md.WriteString(fmt.Sprintf("- **Стенограмма сформирована:** %s\n", generatedAt.Format("02.01.2006 15:04")))
+	md.WriteString(fmt.Sprintf("- **Блоков пройдено:** %d из %d\n", len(result.Blocks), cfg.GetTotalBlocks()))
+
+	for _, block := range result.Blocks {
+		md.WriteString(fmt.Sprintf("\n## Блок %d. %s\n", block.BlockID, blockTitle(cfg, block)))
+
+		for i, qa := range block.QuestionsAndAnswers {
+			md.WriteString(fmt.Sprintf("\n**Вопрос %d.**%s %s\n\n", i+1, questionNote(qa), qa.Question))
+			if strings.TrimSpace(qa.Answer) == "" {
+				md.WriteString("> _нет ответа_\n")
+				continue
+			}
+			for _, line := range strings.Split(strings.TrimSpace(qa.Answer), "\n") {
+				md.WriteString("> " + line + "\n")
+			}
+		}
+	}
+
+	declined := make(map[int]bool, len(result.DeclinedBlocks))
+	for _, id := range result.DeclinedBlocks {
+		declined[id] = true
+	}
+	var skippedTitles, declinedTitles []string
+	for _, id := range result.SkippedBlocks {
+		title := blockTitle(cfg, storage.BlockResult{BlockID: id})
+		if declined[id] {
+			declinedTitles = append(declinedTitles, title)
+		} else {
+			skippedTitles = append(skippedTitles, title)
+		}
+	}
+	if len(skippedTitles) > 0 || len(declinedTitles) > 0 {
+		md.WriteString("\n---\n")
+	}
+	if len(skippedTitles) > 0 {
+		md.WriteString("\n_Пропущенные по условиям блоки:_ " + strings.Join(skippedTitles, ", ") + "\n")
+	}
+	if len(declinedTitles) > 0 {
+		md.WriteString("\n_Необязательные блоки, от которых отказался пользователь:_ " + strings.Join(declinedTitles, ", ") + "\n")
+	}
+
+	return []byte(md.String())
+}
+
+// questionSources - пометки вопросов не из шаблона
+var questionSources = map[string]string{
+	storage.QuestionSourceFollowup:   "уточняющий",
+	storage.QuestionSourceSupervisor: "от супервизора",
+}
+
+// questionNote возвращает пометку вопроса: источник и время, когда он был задан
+func questionNote(qa storage.QA) string {
+	var notes []string
+	if source, ok := questionSources[qa.Source]; ok {
+		notes = append(notes, source)
+	}
+	if asked, err := time.Parse(time.RFC3339, qa.AskedAt); err == nil {
+		notes = append(notes, asked.Format("15:04"))
+	}
+	if len(notes) == 0 {
+		return ""
+	}
+	return " _(" + strings.Join(notes, ", ") + ")_"
+}
+
+// blockTitle возвращает заголовок блока из конфигурации, либо его техническое имя
+func blockTitle(cfg *config.Config, block storage.BlockResult) string {
+	for _, b := range cfg.Blocks {
+		if b.ID == block.BlockID {
+			return b.Title
+		}
+	}
+	if block.BlockName != "" {
+		return block.BlockName
+	}
+	return fmt.Sprintf("Блок %d", block.BlockID)
+}
+
+// formatTimestamp переводит RFC3339 в удобочитаемый вид
+func formatTimestamp(value string) string {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return value
+	}
+	return t.Format("02.01.2006 15:04")
+}
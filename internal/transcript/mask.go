@@ -0,0 +1,41 @@
+package transcript
+
+import (
+	"regexp"
+	"unicode"
+)
+
+// Шаблоны контактов и номеров, которые скрываются в живой трансляции интервью
+var (
+	emailPattern    = regexp.MustCompile(`[\p{L}\p{N}._%+-]+@[\p{L}\p{N}.-]+\.\p{L}{2,}`)
+	linkPattern     = regexp.MustCompile(`(?i)\b(?:https?://|www\.|t\.me/)\S+`)
+	usernamePattern = regexp.MustCompile(`@[A-Za-z0-9_]{4,}`)
+	// phonePattern - цифры с разделителями; телефоном считается последовательность
+	// от minPhoneDigits цифр, чтобы не скрывать перечисления годов
+	phonePattern  = regexp.MustCompile(`\+?\d[\d\s()-]{4,}\d`)
+	numberPattern = regexp.MustCompile(`\b\d{6,}\b`)
+)
+
+// minPhoneDigits - столько цифр в номере телефона без кода страны
+const minPhoneDigits = 10
+
+// Mask скрывает в тексте почту, ссылки, имена пользователей Telegram, телефоны
+// и длинные номера (паспорт, карта, счет). Почта и ссылки заменяются раньше, чем @имя и цифры внутри них.
+func Mask(text string) string {
+	text = emailPattern.ReplaceAllString(text, "[почта]")
+	text = linkPattern.ReplaceAllString(text, "[ссылка]")
+	text = usernamePattern.ReplaceAllString(text, "[username]")
+	text = phonePattern.ReplaceAllStringFunc(text, func(match string) string {
+		digits := 0
+		for _, r := range match {
+			if unicode.IsDigit(r) {
+				digits++
+			}
+		}
+		if digits < minPhoneDigits {
+			return match
+		}
+		return "[номер]"
+	})
+	return numberPattern.ReplaceAllString(text, "[номер]")
+}
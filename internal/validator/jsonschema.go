@@ -0,0 +1,138 @@
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// maxViolations - столько несоответствий схеме попадает в ошибку
+const maxViolations = 20
+
+// SchemaError - профиль не соответствует JSON Schema
+type SchemaError struct {
+	Violations []string
+}
+
+func (e *SchemaError) Error() string {
+	return fmt.Sprintf("profile does not match JSON Schema: %s", strings.Join(e.Violations, "; "))
+}
+
+// ValidateJSONSchema проверяет профиль по документу JSON Schema (schema.JSONSchema).
+// Поддерживаются ключевые слова type, properties, required, items и additionalProperties.
+// Несоответствия возвращаются как *SchemaError.
+func ValidateJSONSchema(jsonStr string, document map[string]interface{}) error {
+	var profile interface{}
+	if err := json.Unmarshal([]byte(jsonStr), &profile); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	var violations []string
+	validateNode(profile, document, "", &violations)
+	if len(violations) == 0 {
+		return nil
+	}
+	if len(violations) > maxViolations {
+		violations = append(violations[:maxViolations], fmt.Sprintf("and %d more", len(violations)-maxViolations))
+	}
+	return &SchemaError{Violations: violations}
+}
+
+func validateNode(value interface{}, node map[string]interface{}, path string, violations *[]string) {
+	if types := schemaTypes(node["type"]); len(types) > 0 && !matchesType(value, types) {
+		*violations = append(*violations, fmt.Sprintf("%s: expected %s, got %s", pathName(path), strings.Join(types, " or "), jsonType(value)))
+		return
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		properties, _ := node["properties"].(map[string]interface{})
+		if required, ok := node["required"].([]interface{}); ok {
+			for _, name := range required {
+				if key, ok := name.(string); ok {
+					if _, exists := v[key]; !exists {
+						*violations = append(*violations, fmt.Sprintf("%s: missing required property", pathName(path+"/"+key)))
+					}
+				}
+			}
+		}
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if property, ok := properties[key].(map[string]interface{}); ok {
+				validateNode(v[key], property, path+"/"+key, violations)
+			} else if additional, ok := node["additionalProperties"].(bool); ok && !additional {
+				*violations = append(*violations, fmt.Sprintf("%s: property is not allowed", pathName(path+"/"+key)))
+			}
+		}
+	case []interface{}:
+		if items, ok := node["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				validateNode(item, items, fmt.Sprintf("%s/%d", path, i), violations)
+			}
+		}
+	}
+}
+
+// schemaTypes возвращает допустимые типы из ключевого слова type (строка или список)
+func schemaTypes(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		types := make([]string, 0, len(v))
+		for _, t := range v {
+			if s, ok := t.(string); ok {
+				types = append(types, s)
+			}
+		}
+		return types
+	case []string:
+		return v
+	}
+	return nil
+}
+
+func matchesType(value interface{}, types []string) bool {
+	actual := jsonType(value)
+	for _, t := range types {
+		if t == actual || (t == "number" && actual == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonType возвращает тип значения в терминах JSON Schema
+func jsonType(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if v == math.Trunc(v) && !math.IsInf(v, 0) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func pathName(path string) string {
+	if path == "" {
+		return "/"
+	}
+	return path
+}
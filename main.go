@@ -1,99 +1,633 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"interview-bot-complete/internal/api"
 	"interview-bot-complete/internal/config"
+	"interview-bot-complete/internal/embeddings"
+	"interview-bot-complete/internal/events"
 	"interview-bot-complete/internal/extractor"
+	"interview-bot-complete/internal/formatters"
+	"interview-bot-complete/internal/httpclient"
 	"interview-bot-complete/internal/interviewer"
+	"interview-bot-complete/internal/invite"
+	"interview-bot-complete/internal/logging"
+	"interview-bot-complete/internal/matcher"
+	"interview-bot-complete/internal/metrics"
+	"interview-bot-complete/internal/moderation"
+	"interview-bot-complete/internal/prompts"
+	"interview-bot-complete/internal/redis"
+	"interview-bot-complete/internal/reload"
+	"interview-bot-complete/internal/report"
+	"interview-bot-complete/internal/reporting"
+	"interview-bot-complete/internal/retention"
+	"interview-bot-complete/internal/rpc"
+	"interview-bot-complete/internal/rubric"
+	"interview-bot-complete/internal/search"
+	"interview-bot-complete/internal/server"
+	"interview-bot-complete/internal/sheets"
+	"interview-bot-complete/internal/storage"
 	"interview-bot-complete/internal/telegram"
-	"log"
+	"interview-bot-complete/internal/tenant"
+	"log/slog"
+	"net/url"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 func main() {
-	fmt.Println("🚀 Запуск Interview Bot с оптимизированным Profile Extractor...")
-
 	// Загружаем переменные окружения
-	err := godotenv.Load()
+	envErr := godotenv.Load()
+
+	// Загружаем настройки приложения из переменных окружения
+	appCfg := config.LoadAppConfig()
+
+	// Логгер настраивается до создания сервисов: они запоминают slog.Default()
+	logger, err := logging.New(os.Stderr, appCfg.Logging.Level, appCfg.Logging.Format)
 	if err != nil {
-		log.Printf("Предупреждение: .env файл не найден, используем переменные системы")
+		fmt.Fprintf(os.Stderr, "Ошибка настройки логов: %v\n", err)
+		os.Exit(1)
 	}
+	slog.SetDefault(logger)
+
+	logger.Info("Запуск Interview Bot")
+	if envErr != nil {
+		logger.Warn(".env файл не найден, используем переменные системы")
+	}
+
+	// Режим симуляции позволяет прогнать весь сценарий без ключа OpenAI
+	mockMode := strings.EqualFold(os.Getenv("LLM_PROVIDER"), interviewer.ProviderMock)
 
 	// Проверяем наличие API ключей
 	openaiKey := os.Getenv("OPENAI_API_KEY")
-	if openaiKey == "" {
-		log.Fatal("OPENAI_API_KEY не установлен")
+	if openaiKey == "" && !mockMode {
+		fatal(logger, "OPENAI_API_KEY не установлен", nil)
 	}
 
 	telegramToken := os.Getenv("TELEGRAM_BOT_TOKEN")
 	if telegramToken == "" {
-		log.Fatal("TELEGRAM_BOT_TOKEN не установлен")
+		fatal(logger, "TELEGRAM_BOT_TOKEN не установлен", nil)
 	}
 
-	// Выводим используемую модель
+	// Используемая модель
 	model := os.Getenv("OPENAI_MODEL")
 	if model == "" {
 		model = "gpt-4.1-mini" // значение по умолчанию
 	}
-	fmt.Printf("🤖 Используемая модель: %s\n", model)
+	if mockMode {
+		logger.Info("Режим симуляции: LLM_PROVIDER=mock, запросы к OpenAI не выполняются")
+	}
+
+	// OpenAI, совместимый шлюз (OPENAI_BASE_URL) или Azure OpenAI (OPENAI_API_TYPE=azure)
+	endpoint := api.EndpointFromEnv()
+	switch endpoint.APIType {
+	case api.APITypeOpenAI:
+	case api.APITypeAzure:
+		if os.Getenv("OPENAI_BASE_URL") == "" {
+			fatal(logger, "Для OPENAI_API_TYPE=azure нужен OPENAI_BASE_URL вида https://<resource>.openai.azure.com", nil)
+		}
+	default:
+		fatal(logger, "Неизвестный OPENAI_API_TYPE", fmt.Errorf("%q: допустимы openai и azure", endpoint.APIType))
+	}
+
+	// Арендаторы: свой шаблон, приветствие, схема, директория хранилища и вебхуки
+	tenants, err := tenant.Load(appCfg.Tenants.File)
+	if err != nil {
+		fatal(logger, "Ошибка загрузки арендаторов", err)
+	}
 
-	// Загружаем конфигурацию интервью
-	cfg, err := config.Load("config/interview.yaml")
+	// Директории и шаблоны имен файлов результатов и профилей
+	if err := storage.Configure(storage.Paths{
+		ResultsDir:  appCfg.Storage.ResultsDir,
+		OutputDir:   appCfg.Storage.OutputDir,
+		ResultFile:  appCfg.Storage.ResultFileTemplate,
+		ProfileFile: appCfg.Storage.ProfileFileTemplate,
+		TenantDirs:  tenants.StoragePrefixes(),
+	}); err != nil {
+		fatal(logger, "Ошибка настройки хранилища", err)
+	}
+
+	// Ночное удаление или архивация ответов старше срока хранения (профили остаются)
+	defaultPolicy := retention.Policy{Days: appCfg.Retention.Days, Mode: appCfg.Retention.Mode}
+	if err := defaultPolicy.Validate(); err != nil {
+		fatal(logger, "Ошибка настройки политики хранения", err)
+	}
+	retentionJob := retention.New(defaultPolicy, appCfg.Retention.ArchiveDir)
+	for _, t := range tenants.All() {
+		if t.RetentionDays == nil && t.RetentionMode == "" {
+			continue
+		}
+		policy := defaultPolicy
+		if t.RetentionDays != nil {
+			policy.Days = *t.RetentionDays
+		}
+		if t.RetentionMode != "" {
+			policy.Mode = t.RetentionMode
+		}
+		if err := policy.Validate(); err != nil {
+			fatal(logger, "Ошибка политики хранения арендатора "+t.ID, err)
+		}
+		retentionJob.SetTenantPolicy(t.ID, policy)
+	}
+	if retentionJob.Enabled() {
+		runAt, err := retention.ParseClock(appCfg.Retention.RunAt)
+		if err != nil {
+			fatal(logger, "Ошибка настройки политики хранения", err)
+		}
+		retentionJob.Start(runAt)
+		logger.Info("Политика хранения ответов включена", "days", appCfg.Retention.Days, "mode", appCfg.Retention.Mode, "run_at", appCfg.Retention.RunAt)
+	}
+
+	// Загружаем конфигурацию интервью и дополнительные шаблоны
+	templates, err := config.LoadTemplates(appCfg.Interview.ConfigFile, appCfg.Interview.TemplatesDir)
 	if err != nil {
-		log.Fatalf("Ошибка загрузки конфигурации интервью: %v", err)
+		fatal(logger, "Ошибка загрузки конфигурации интервью", err)
+	}
+	cfg := templates.Default()
+	if err := checkTenantTemplates(tenants)(templates); err != nil {
+		fatal(logger, "Неизвестный шаблон интервью арендатора", err)
 	}
 
-	// Инициализируем сервисы
-	fmt.Println("🔧 Инициализация сервисов...")
+	// Приглашения по deep link /start <payload>
+	invites, err := invite.Load(appCfg.Invites.File, appCfg.Invites.HMACSecret)
+	if err != nil {
+		fatal(logger, "Ошибка загрузки приглашений", err)
+	}
+	if appCfg.Invites.Required && !invites.Enabled() {
+		fatal(logger, "INVITES_REQUIRED=true, но не заданы ни список приглашений, ни INVITE_HMAC_SECRET", nil)
+	}
+
+	// Прокси и корпоративные корневые сертификаты для OpenAI и Telegram
+	if _, err := api.HTTPTransport(); err != nil {
+		fatal(logger, "Ошибка настройки HTTP клиента OpenAI (OPENAI_PROXY, HTTP_CA_CERT_FILE)", err)
+	}
+	telegramTransport, err := httpclient.NewTransport(httpclient.OptionsFromEnv("TELEGRAM_PROXY"))
+	if err != nil {
+		fatal(logger, "Ошибка настройки HTTP клиента Telegram (TELEGRAM_PROXY, HTTP_CA_CERT_FILE)", err)
+	}
 
 	// Интервьюер для Telegram бота
 	interviewerService := interviewer.New(openaiKey)
-	fmt.Println("✅ Интервьюер инициализирован")
 
 	// Profile Extractor для анализа (оптимизированный)
 	extractorService, err := extractor.New(openaiKey)
 	if err != nil {
-		log.Printf("⚠️ Ошибка инициализации Profile Extractor: %v", err)
-		log.Println("Бот будет работать без анализа профилей")
+		logger.Warn("Profile Extractor не инициализирован, бот будет работать без анализа профилей", "error", err)
 		extractorService = nil
-	} else {
-		fmt.Println("✅ Profile Extractor инициализирован (оптимизированный)")
+	}
+	if extractorService != nil {
+		for _, t := range tenants.All() {
+			if t.Schema == "" {
+				continue
+			}
+			if err := extractorService.LoadTenantSchema(t.ID, t.Schema); err != nil {
+				fatal(logger, "Ошибка загрузки схемы профиля арендатора "+t.ID, err)
+			}
+		}
+		if err := formatters.Validate(appCfg.Storage.ExportFormats); err != nil {
+			fatal(logger, "Ошибка настройки выгрузки профилей", err)
+		}
+		extractorService.SetExportFormats(appCfg.Storage.ExportFormats)
+		extractorService.SetContextTokens(appCfg.OpenAI.ContextTokens)
+
+		// Шаблоны промптов извлечения с диска: правка файла применяется без пересборки и перезапуска
+		if err := prompts.LoadTemplates(appCfg.Interview.PromptsDir); err != nil {
+			fatal(logger, "Ошибка загрузки шаблонов промптов", err)
+		}
+		if appCfg.Interview.PromptsReloadInterval > 0 {
+			prompts.WatchTemplates(appCfg.Interview.PromptsReloadInterval, logger)
+		}
+		logger.Info("Версии промпта извлечения", "available", strings.Join(prompts.PromptVersions(), ","),
+			"from_files", strings.Join(prompts.TemplateVersions(), ","))
 	}
 
 	// Telegram бот
 	bot := telegram.New(telegramToken)
-	handler := telegram.NewHandler(bot, cfg, interviewerService, extractorService)
-	fmt.Println("✅ Telegram бот инициализирован с поддержкой отправки файлов")
+	bot.SetTransport(telegramTransport)
+	polling := telegram.PollingOptions{
+		Timeout:        appCfg.Telegram.PollTimeout,
+		Limit:          appCfg.Telegram.PollLimit,
+		AllowedUpdates: appCfg.Telegram.AllowedUpdates,
+	}
+	bot.SetPolling(polling)
+	dispatch := telegram.DispatchOptions{
+		Workers:   appCfg.Telegram.UpdateWorkers,
+		QueueSize: appCfg.Telegram.UpdateQueueSize,
+		ShedAfter: appCfg.Telegram.UpdateShedAfter,
+	}
+	bot.SetDispatch(dispatch)
+	handler := telegram.NewHandler(bot, templates, invites, appCfg, interviewerService, extractorService)
+	handler.SetTenants(tenants, nil)
+
+	// Перезагрузка шаблонов интервью и схемы профиля без перезапуска (/reloadconfig и по изменению файлов)
+	reloader := reload.New(templates, extractorService)
+	reloader.SetLogger(logger)
+	reloader.AddCheck(checkTenantTemplates(tenants))
+	handler.SetReloader(reloader)
 
-	// Выводим информацию о конфигурации
-	fmt.Println("\n📋 Конфигурация:")
-	fmt.Printf("• Блоков в интервью: %d\n", cfg.GetTotalBlocks())
-	fmt.Printf("• Вопросов на блок: до %d\n", cfg.GetQuestionsPerBlock()+cfg.GetMaxFollowupQuestions())
-	fmt.Printf("• Модель OpenAI: %s\n", model)
+	// Общие лимиты и сессии в Redis для нескольких реплик
+	var redisClient *redis.Client
+	if appCfg.Redis.URL != "" {
+		redisClient, err = redis.NewClient(appCfg.Redis.URL)
+		if err != nil {
+			fatal(logger, "Ошибка настройки Redis", err)
+		}
+		pingCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		err = redisClient.Ping(pingCtx)
+		cancel()
+		if err != nil {
+			fatal(logger, "Redis недоступен", err)
+		}
+		handler.UseRedis(redisClient, appCfg.Redis)
+		logger.Info("Лимиты и сессии хранятся в Redis")
+	}
 
+	// Эмбеддинги профилей для поиска похожих
+	var embeddingService *embeddings.Service
 	if extractorService != nil {
-		fmt.Println("• Анализ профилей: включен 🧠 (оптимизированный)")
-		fmt.Println("• Формат профиля: Viget JSON")
-		fmt.Println("• Отправка: JSON файлы 📄")
+		embeddingService, err = embeddings.New(openaiKey, extractorService.GetLastProfileJSON)
+		if err != nil {
+			logger.Warn("Поиск похожих профилей отключен", "error", err)
+			embeddingService = nil
+		} else {
+			handler.SetEmbeddings(embeddingService)
+			logger.Info("Поиск похожих профилей инициализирован", "indexed", embeddingService.Count())
+		}
+	}
+
+	// Полнотекстовый поиск по ответам и профилям архива
+	var searchIndex *search.Index
+	if appCfg.Search.Enabled {
+		var profiles search.ProfileSource
+		if extractorService != nil {
+			profiles = extractorService.GetLastProfileJSON
+		}
+		searchIndex = search.New(profiles)
+		searchIndex.Start(appCfg.Search.RefreshInterval)
+		handler.SetSearch(searchIndex)
+	}
+
+	// Шина событий интервью: журнал, выгрузки, метрики и вебхуки подписываются на нее
+	eventBus := events.NewBus()
+	eventBus.SetLogger(logger)
+	eventBus.Subscribe("log", events.LogSubscriber(logger))
+	handler.SetEvents(eventBus)
+
+	// Выгрузка профилей в Google Sheets
+	if appCfg.Sheets.SpreadsheetID != "" && extractorService != nil {
+		exporter, err := sheets.New(appCfg.Sheets, extractorService.GetLastProfileJSON)
+		if err != nil {
+			logger.Warn("Выгрузка профилей в Google Sheets отключена", "error", err)
+		} else {
+			handler.SetSheets(exporter)
+			eventBus.Subscribe("sheets", exporter)
+			logger.Info("Выгрузка профилей в Google Sheets инициализирована", "sheet", appCfg.Sheets.Sheet)
+		}
+	}
+
+	// Метрики для дашборда
+	metricsRegistry := metrics.New()
+	handler.SetMetrics(metricsRegistry)
+	bot.SetMetrics(metricsRegistry)
+	if extractorService != nil {
+		extractorService.SetMetrics(metricsRegistry)
+	}
+	eventBus.Subscribe("metrics", metricsRegistry)
+	eventBus.Subscribe("tenant_webhooks", tenants.WebhookSubscriber(func(tenantID string, err error) {
+		metricsRegistry.RecordError("tenant_webhook", fmt.Sprintf("%s: %v", tenantID, err))
+	}))
+
+	// Модерация ответов: кризисное сообщение пользователю и уведомление администраторов
+	if appCfg.Moderation.Enabled {
+		policy := appCfg.Moderation.ExtractionPolicy
+		if policy != config.ModerationExclude && policy != config.ModerationKeep {
+			fatal(logger, "Ошибка конфигурации модерации", fmt.Errorf("MODERATION_EXTRACTION_POLICY=%q (допустимы: %s, %s)",
+				policy, config.ModerationExclude, config.ModerationKeep))
+		}
+		handler.SetModeration(moderation.New(openaiKey, appCfg.Moderation))
+		if extractorService != nil {
+			extractorService.SetModerationPolicy(policy)
+		}
+		logger.Info("Модерация ответов включена", "model", appCfg.Moderation.Model,
+			"categories", strings.Join(appCfg.Moderation.Categories, ","), "extraction_policy", policy)
+	}
+
+	// API интервью (InterviewService) на общем с ботом движке: бюджет OpenAI и метрики общие
+	var interviewService *rpc.Service
+	if appCfg.Server.InterviewAPI {
+		interviewService = rpc.NewService(handler.Engine())
+		interviewService.SetMetrics(metricsRegistry)
+		if extractorService != nil {
+			interviewService.SetExtractor(extractorService)
+		}
+	}
+
+	// Отчеты о паниках в Sentry (администраторы в Telegram уведомляются всегда)
+	if appCfg.Reporting.SentryDSN != "" {
+		sentry, err := reporting.NewSentry(appCfg.Reporting.SentryDSN, appCfg.Reporting.Environment)
+		if err != nil {
+			logger.Warn("Отчеты в Sentry отключены", "error", err)
+		} else {
+			handler.AddErrorReporter(sentry)
+			logger.Info("Отчеты об ошибках отправляются в Sentry")
+		}
+	}
+
+	// Платный расширенный анализ (/premium)
+	if appCfg.Premium.Enabled {
+		if appCfg.Premium.PDFFont != "" {
+			font, err := report.LoadFont(appCfg.Premium.PDFFont)
+			if err != nil {
+				logger.Warn("PDF отчеты отключены", "error", err)
+			} else {
+				handler.SetReportFont(font)
+			}
+		}
+		if extractorService != nil {
+			catalogs, err := matcher.LoadCatalogs(appCfg.Premium.ArchetypesDir, appCfg.Premium.ArchetypeCatalog)
+			if err != nil {
+				logger.Warn("Подбор архетипов отключен", "error", err)
+			} else {
+				if err := checkArchetypeCatalogs(catalogs)(templates); err != nil {
+					fatal(logger, "Неизвестный каталог архетипов в шаблоне", err)
+				}
+				reloader.AddCheck(checkArchetypeCatalogs(catalogs))
+				extractorService.SetArchetypes(catalogs)
+				logger.Info("Каталоги архетипов загружены", "catalogs", catalogs.IDs(), "default", appCfg.Premium.ArchetypeCatalog)
+			}
+		}
+		logger.Info("Расширенный анализ доступен", "price", appCfg.Premium.Price, "currency", appCfg.Premium.Currency)
+	}
+
+	// Оценка кандидатов по рубрикам ролей для рекрутеров
+	if extractorService != nil && recruiterChatConfigured(appCfg.Outcome, tenants) {
+		rubrics, err := rubric.LoadRubrics(appCfg.Outcome.RubricsDir, appCfg.Outcome.Rubric)
+		if err != nil {
+			logger.Warn("Оценка кандидатов по рубрикам отключена", "error", err)
+		} else {
+			if err := checkRubrics(rubrics)(templates); err != nil {
+				fatal(logger, "Неизвестная рубрика роли в шаблоне", err)
+			}
+			reloader.AddCheck(checkRubrics(rubrics))
+			extractorService.SetRubrics(rubrics)
+			logger.Info("Рубрики ролей загружены", "rubrics", rubrics.IDs(), "default", appCfg.Outcome.Rubric)
+		}
+	}
+
+	if appCfg.Interview.WatchConfig {
+		if err := reloader.Watch(); err != nil {
+			logger.Warn("Конфигурация перезагружается только по команде /reloadconfig", "error", err)
+		}
+	}
+
+	// Меню команд с описаниями на русском и английском
+	if err := handler.RegisterCommands(); err != nil {
+		logger.Warn("Не удалось зарегистрировать меню команд", "error", err)
+	}
+
+	// Профили, не доставленные до перезапуска, отправляются повторно
+	handler.StartOutbox()
+	if appCfg.Telegram.DropoffInterval > 0 {
+		handler.StartDropoffReports(appCfg.Telegram.DropoffInterval)
+	}
+
+	// HTTP сервер с эндпоинтами /healthz и /readyz для Kubernetes (и вебхуками ботов)
+	healthServer := server.New(appCfg.Server)
+
+	// Отдельные боты арендаторов: свои сессии, общие с основным ботом сервисы, лимиты и метрики
+	tenantBots := make(map[string]*telegram.Bot)
+	// Polling всех ботов останавливается по SIGINT/SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	handlers := []*telegram.Handler{handler}
+	for _, t := range tenants.All() {
+		token := t.BotToken()
+		if token == "" {
+			continue
+		}
+		tenantBot := telegram.New(token)
+		tenantBot.SetTransport(telegramTransport)
+		tenantBot.SetPolling(polling)
+		tenantBot.SetDispatch(dispatch)
+		tenantBot.SetMetrics(metricsRegistry)
+		tenantHandler := telegram.NewHandler(tenantBot, templates, invites, appCfg, interviewerService, extractorService)
+		if redisClient != nil {
+			// Chat ID пользователя одинаков во всех ботах - сессии арендатора хранятся под своим префиксом
+			redisCfg := appCfg.Redis
+			redisCfg.KeyPrefix += "tenant:" + t.ID + ":"
+			tenantHandler.UseRedis(redisClient, redisCfg)
+		}
+		tenantHandler.ShareServices(handler)
+		tenantHandler.SetTenants(tenants, t)
+		if err := tenantHandler.RegisterCommands(); err != nil {
+			logger.Warn("Не удалось зарегистрировать меню команд бота арендатора", "tenant", t.ID, "error", err)
+		}
+		tenantBots[t.ID] = tenantBot
+		handlers = append(handlers, tenantHandler)
+		tenantHandler.StartOutbox()
+
+		tenantID := t.ID
+		go func() {
+			var err error
+			if appCfg.Telegram.WebhookURL != "" {
+				// Вебхук бота арендатора - на своем пути под адресом вебхука основного бота
+				err = tenantBot.ServeWebhook(ctx, appCfg.Telegram.WebhookURL+"/"+url.PathEscape(tenantID),
+					appCfg.Telegram.WebhookSecret, tenantHandler.Recover(tenantHandler.HandleUpdate), healthServer.Handle)
+			} else {
+				err = tenantBot.StartPolling(ctx, tenantHandler.Recover(tenantHandler.HandleUpdate))
+			}
+			if err != nil {
+				logger.Error("Ошибка бота арендатора", "tenant", tenantID, "error", err)
+			}
+		}()
+	}
+
+	healthServer.AddReadinessCheck("telegram", func(ctx context.Context) error {
+		_, err := bot.GetMe(ctx)
+		return err
+	})
+	healthServer.AddReadinessCheck("openai", api.NewOpenAIClient(openaiKey).CheckAPIKey)
+	healthServer.AddReadinessCheck("config", func(ctx context.Context) error {
+		if cfg := templates.Default(); cfg == nil || len(cfg.Blocks) == 0 {
+			return fmt.Errorf("конфигурация интервью не загружена")
+		}
+		return nil
+	})
+	healthServer.AddReadinessCheck("storage", func(ctx context.Context) error {
+		if err := storage.CheckResultsWritable(); err != nil {
+			return err
+		}
+		return storage.CheckWritable(storage.OutputDir())
+	})
+	for tenantID, tenantBot := range tenantBots {
+		tenantBot := tenantBot
+		healthServer.AddReadinessCheck("telegram_"+tenantID, func(ctx context.Context) error {
+			_, err := tenantBot.GetMe(ctx)
+			return err
+		})
+	}
+	if redisClient != nil {
+		healthServer.AddReadinessCheck("redis", redisClient.Ping)
+	}
+	if embeddingService != nil {
+		healthServer.EnableSimilarProfiles(embeddingService)
+	}
+	if searchIndex != nil {
+		healthServer.EnableSearch(searchIndex)
+	}
+	if interviewService != nil {
+		healthServer.EnableInterviewService(rpc.ServicePath, interviewService.Handler())
+		logger.Info("InterviewService доступен", "port", appCfg.Server.Port, "path", rpc.ServicePath)
+	}
+	if extractorService != nil {
+		healthServer.EnableProfileSchema(extractorService.ProfileJSONSchema)
+	}
+	healthServer.EnableInvitations()
+	healthServer.EnableDropoffReport()
+	healthServer.EnableLinkCodes()
+	healthServer.EnableDashboard(server.DashboardSources{
+		Metrics: metricsRegistry,
+		LiveSessions: func() map[string]int {
+			counts := make(map[string]int)
+			for _, h := range handlers {
+				for state, count := range h.LiveSessions() {
+					counts[state] += count
+				}
+			}
+			return counts
+		},
+	})
+	logger.Info("Дашборд доступен", "port", appCfg.Server.Port, "path", "/dashboard")
+	go func() {
+		if err := healthServer.Start(); err != nil {
+			logger.Error("Ошибка HTTP сервера", "error", err)
+		}
+	}()
+
+	// Итоговая конфигурация одной записью
+	fallbacks := api.FallbackModels()
+	logger.Info("Конфигурация",
+		"templates", strings.Join(templates.IDs(), ","),
+		"results", filepath.Join(storage.ResultsDir(), appCfg.Storage.ResultFileTemplate),
+		"profiles", filepath.Join(storage.OutputDir(), appCfg.Storage.ProfileFileTemplate),
+		"blocks", cfg.GetTotalBlocks(),
+		"questions_per_block", cfg.GetQuestionsPerBlock()+cfg.GetMaxFollowupQuestions(),
+		"model", model,
+		"fallback_models", strings.Join(fallbacks, ","),
+		"openai_api_type", endpoint.APIType,
+		"openai_base_url", endpoint.BaseURL,
+		"messages_per_minute", appCfg.RateLimit.MessagesPerMinute,
+		"llm_per_user_per_minute", appCfg.RateLimit.LLMCallsPerMinute,
+		"llm_global_per_minute", appCfg.RateLimit.GlobalLLMPerMinute,
+		"profile_extraction", extractorService != nil,
+		"tenants", len(tenants.All()),
+		"tenant_bots", len(tenantBots),
+		"webhook", appCfg.Telegram.WebhookURL != "",
+		"poll_timeout", appCfg.Telegram.PollTimeout,
+		"maintenance", appCfg.Telegram.Maintenance,
+		"http_port", appCfg.Server.Port,
+	)
+	logger.Info("Telegram бот запущен, ожидание сообщений")
+
+	// Обновления принимает вебхук (несколько реплик за балансировщиком) или long polling
+	if appCfg.Telegram.WebhookURL != "" {
+		err = bot.ServeWebhook(ctx, appCfg.Telegram.WebhookURL, appCfg.Telegram.WebhookSecret,
+			handler.Recover(handler.HandleUpdate), healthServer.Handle)
 	} else {
-		fmt.Println("• Анализ профилей: отключен ⚠️")
+		err = bot.StartPolling(ctx, handler.Recover(handler.HandleUpdate))
+	}
+	if err != nil {
+		fatal(logger, "Ошибка запуска бота", err)
 	}
 
-	fmt.Println("\n🎯 Особенности:")
-	fmt.Println("• Один запрос к API для анализа профиля")
-	fmt.Println("• Минимальные метаданные")
-	fmt.Println("• Пополняемый формат профиля")
-	fmt.Println("• Отправка как JSON файл")
+	logger.Info("Бот остановлен")
+	// Новые интервью больше не начинаются; идущие извлечения профилей дописываются до выхода
+	handler.SetMaintenance(true)
+	drainCtx, cancelDrain := context.WithTimeout(context.Background(), appCfg.Telegram.DrainTimeout)
+	if pending := handler.DrainExtractions(drainCtx); pending > 0 {
+		logger.Warn("Извлечения профилей не завершились до остановки, продолжатся после перезапуска", "pending", pending)
+	}
+	cancelDrain()
 
-	fmt.Println("\n🤖 Telegram бот запущен!")
-	fmt.Println("⏳ Ожидание сообщений...")
-	fmt.Println("📱 Найдите бота в Telegram и отправьте /start")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), appCfg.Server.ShutdownTimeout)
+	defer cancel()
+	if err := healthServer.Shutdown(shutdownCtx); err != nil {
+		logger.Warn("Ошибка остановки HTTP сервера", "error", err)
+	}
+}
 
-	// Запускаем polling
-	err = bot.StartPolling(handler.HandleUpdate)
+// fatal пишет ошибку в лог и завершает процесс
+func fatal(logger *slog.Logger, msg string, err error) {
 	if err != nil {
-		log.Fatalf("Ошибка запуска бота: %v", err)
+		logger.Error(msg, "error", err)
+	} else {
+		logger.Error(msg)
+	}
+	os.Exit(1)
+}
+
+// recruiterChatConfigured сообщает, задан ли чат рекрутеров - общий или хотя бы одного арендатора
+func recruiterChatConfigured(outcome config.OutcomeConfig, tenants *tenant.Registry) bool {
+	if outcome.RecruiterChatID != 0 {
+		return true
+	}
+	for _, t := range tenants.All() {
+		if t.RecruiterChatID != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// checkTenantTemplates проверяет, что шаблоны интервью арендаторов есть среди шаблонов
+func checkTenantTemplates(tenants *tenant.Registry) reload.Check {
+	return func(templates *config.Templates) error {
+		for _, t := range tenants.All() {
+			if t.Template == "" {
+				continue
+			}
+			if _, ok := templates.Get(t.Template); !ok {
+				return fmt.Errorf("арендатор %s: шаблон %q не найден", t.ID, t.Template)
+			}
+		}
+		return nil
+	}
+}
+
+// checkArchetypeCatalogs проверяет, что каталоги архетипов из шаблонов загружены
+func checkArchetypeCatalogs(catalogs *matcher.Catalogs) reload.Check {
+	return func(templates *config.Templates) error {
+		for _, id := range templates.IDs() {
+			cfg, _ := templates.Get(id)
+			if catalogID := cfg.InterviewConfig.ArchetypeCatalog; catalogID != "" {
+				if _, ok := catalogs.Get(catalogID); !ok {
+					return fmt.Errorf("шаблон %s: archetype_catalog %q не найден", id, catalogID)
+				}
+			}
+		}
+		return nil
+	}
+}
+
+// checkRubrics проверяет, что рубрики ролей из шаблонов загружены
+func checkRubrics(rubrics *rubric.Rubrics) reload.Check {
+	return func(templates *config.Templates) error {
+		for _, id := range templates.IDs() {
+			cfg, _ := templates.Get(id)
+			if rubricID := cfg.InterviewConfig.Rubric; rubricID != "" {
+				if _, ok := rubrics.Get(rubricID); !ok {
+					return fmt.Errorf("шаблон %s: rubric %q не найдена", id, rubricID)
+				}
+			}
+		}
+		return nil
 	}
 }